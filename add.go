@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const (
+	metersPerMile = 1609.34
+	metersPerKm   = 1000.0
+)
+
+// runAdd implements `runner add`, which records a manual activity such as a
+// treadmill run or a run without a watch
+func runAdd(args []string) error {
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	unit := "km"
+	if cfg.Display.DistanceUnit == "mi" {
+		unit = "mi"
+	}
+
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	name := fs.String("name", service.DefaultManualActivityName, "activity name")
+	date := fs.String("date", time.Now().Format("2006-01-02 15:04"), `start time, "YYYY-MM-DD" or "YYYY-MM-DD HH:MM"`)
+	distance := fs.Float64("distance", 0, "distance in "+unit+" (required)")
+	duration := fs.String("duration", "", `moving time, "MM:SS" or "H:MM:SS" (required)`)
+	hr := fs.Float64("hr", 0, "average heart rate in bpm (optional)")
+	rpe := fs.Int("rpe", 0, "perceived effort from 1 to 10 (optional)")
+	feel := fs.Int("feel", 0, "how the run felt, 1 (awful) to 5 (great), with -rpe (optional)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: runner add -distance N -duration MM:SS [-date YYYY-MM-DD] [-hr BPM] [-rpe 1-10 [-feel 1-5]] [-name NAME]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	start, err := parseStartTime(*date)
+	if err != nil {
+		return err
+	}
+	seconds, err := parseClockDuration(*duration)
+	if err != nil {
+		return err
+	}
+
+	meters := *distance * metersPerKm
+	if unit == "mi" {
+		meters = *distance * metersPerMile
+	}
+
+	activity := service.ManualActivity{
+		Name:      *name,
+		StartTime: start,
+		Distance:  meters,
+		Duration:  seconds,
+	}
+	if *hr > 0 {
+		activity.AvgHR = hr
+	}
+	if *rpe != 0 {
+		activity.RPE = rpe
+	}
+	if *feel != 0 {
+		activity.Feel = feel
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	if err := querySvc.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		return fmt.Errorf("applying load model: %w", err)
+	}
+	if _, err := querySvc.AddManualActivity(activity); err != nil {
+		return fmt.Errorf("adding activity: %w", err)
+	}
+
+	fmt.Printf("Added %q: %.2f %s in %s on %s\n", activity.Name, *distance, unit, *duration, start.Format("Mon Jan 2, 2006 15:04"))
+	return nil
+}
+
+// parseStartTime parses a local date with an optional time of day
+func parseStartTime(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD or YYYY-MM-DD HH:MM)", s)
+}
+
+// parseClockDuration parses "MM:SS" or "H:MM:SS" into seconds
+func parseClockDuration(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("duration is required (MM:SS or H:MM:SS)")
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q (want MM:SS or H:MM:SS)", s)
+	}
+
+	total := 0
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, fmt.Errorf("invalid duration %q (want MM:SS or H:MM:SS)", s)
+		}
+		total = total*60 + n
+	}
+	return total, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"runner/internal/auth"
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+// runAuth implements `runner auth`, which connects to Strava again, e.g. to
+// grant a permission left unticked the first time
+func runAuth(args []string) error {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: runner auth")
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	return authenticate(context.Background(), db, cfg)
+}
+
+// scopeWarning returns a prompt to reauthorize if the stored token wasn't
+// granted every scope the app needs, or "" if it was or isn't known
+func scopeWarning(db *store.Store) string {
+	stored, err := db.GetAuth()
+	if err != nil {
+		return ""
+	}
+	return auth.ScopeWarning(auth.MissingScopes(auth.ParseScopes(stored.Scopes)))
+}
+
+// formatScopes lists granted scopes for display
+func formatScopes(scopes []string) string {
+	if len(scopes) == 0 {
+		return "unknown"
+	}
+	return strings.Join(scopes, ", ")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+const restoreUsage = `Usage: runner restore [number | path]
+
+With no argument, lists the backups in the data directory, newest first.
+Give a number from that list, or the path to a backup file, to replace the
+database with it. The current database is kept as a pre-restore backup.`
+
+// runBackup implements `runner backup`, which snapshots the database now
+func runBackup(args []string) error {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: runner backup")
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	b, err := db.Backup(store.BackupManual)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backed up to %s (%s)\n", b.Path, formatBytes(b.Bytes))
+
+	removed, err := store.PruneBackups(cfg.Storage.BackupKeep)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		fmt.Printf("Removed %d old backups (keeping %d)\n", removed, cfg.Storage.BackupKeep)
+	}
+	return nil
+}
+
+// runRestore implements `runner restore`, which lists backups or rolls the
+// database back to one of them
+func runRestore(args []string) error {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, restoreUsage)
+		return nil
+	}
+
+	backups, err := store.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		if len(backups) == 0 {
+			fmt.Println("No backups found. Run `runner backup` to create one.")
+			return nil
+		}
+		fmt.Printf("%3s  %-17s %-12s %10s\n", "#", "Created", "Reason", "Size")
+		for i, b := range backups {
+			fmt.Printf("%3d  %-17s %-12s %10s\n", i+1, b.CreatedAt.Local().Format("2006-01-02 15:04"), b.Reason, formatBytes(b.Bytes))
+		}
+		fmt.Println("\nRun `runner restore <number>` to restore one.")
+		return nil
+	}
+
+	path := args[0]
+	if n, err := strconv.Atoi(args[0]); err == nil {
+		if n < 1 || n > len(backups) {
+			return fmt.Errorf("no backup numbered %d; run `runner restore` to list them", n)
+		}
+		path = backups[n-1].Path
+	}
+
+	if err := store.RestoreBackup(path); err != nil {
+		return fmt.Errorf("restoring backup: %w", err)
+	}
+	fmt.Printf("Restored database from %s\n", path)
+	fmt.Println("The previous database was saved as a pre-restore backup.")
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const dbUsage = `Usage: runner db <command>
+
+Commands:
+  stats            show database size and rows per table
+  usage            show how much space each kind of data takes
+  vacuum           rebuild the database file to reclaim free space
+  verify [-repair] check integrity and find rows left by deleted activities
+  archive -before YYYY-MM-DD [-name NAME]
+                   move older runs into a season archive; browse them with --all-time
+  archives         list season archives`
+
+// runDB implements the `runner db` maintenance commands
+func runDB(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, dbUsage)
+		return nil
+	}
+
+	db, err := openStore()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "stats":
+		return runDBStats(db)
+	case "usage":
+		return runDBUsage(db)
+	case "vacuum":
+		if db.ReadOnly() {
+			return errors.New("vacuum rewrites the database; run it without --read-only")
+		}
+		return runDBVacuum(db)
+	case "verify":
+		return runDBVerify(db, args[1:])
+	case "archive":
+		if db.ReadOnly() {
+			return errors.New("archive moves runs out of the database; run it without --read-only or --all-time")
+		}
+		return runDBArchive(db, args[1:])
+	case "archives":
+		return runDBArchives()
+	default:
+		fmt.Fprintln(os.Stderr, dbUsage)
+		return nil
+	}
+}
+
+func runDBStats(db *store.Store) error {
+	stats, err := db.Stats()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Database size: %s (%s free)\n\n", formatBytes(stats.FileBytes), formatBytes(stats.FreeBytes))
+	fmt.Printf("%-20s %12s %10s\n", "Table", "Rows", "Size")
+	for _, t := range stats.Tables {
+		fmt.Printf("%-20s %12d %10s\n", t.Name, t.Rows, formatBytes(t.Bytes))
+	}
+	return nil
+}
+
+func runDBUsage(db *store.Store) error {
+	usage, err := db.UsageByClass()
+	if err != nil {
+		return err
+	}
+	stats, err := db.Stats()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-12s %10s %12s  %s\n", "Data", "Size", "Rows", "Holds")
+	for _, u := range usage {
+		fmt.Printf("%-12s %10s %12d  %s\n", u.Class, formatBytes(u.Bytes), u.Rows, u.Description)
+	}
+	fmt.Printf("%-12s %10s %12s  %s\n", "free", formatBytes(stats.FreeBytes), "", "space `runner db vacuum` would reclaim")
+	fmt.Println("\nRun `runner streams purge -older-than YEARS` to drop old streams, or `runner db archive` to move old seasons out.")
+	return nil
+}
+
+func runDBVacuum(db *store.Store) error {
+	before, err := db.Stats()
+	if err != nil {
+		return err
+	}
+	if err := db.Vacuum(); err != nil {
+		return fmt.Errorf("vacuuming database: %w", err)
+	}
+	after, err := db.Stats()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Database size: %s -> %s\n", formatBytes(before.FileBytes), formatBytes(after.FileBytes))
+	return nil
+}
+
+func runDBVerify(db *store.Store, args []string) error {
+	fs := flag.NewFlagSet("db verify", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "delete rows that reference missing activities")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	problems, err := db.IntegrityCheck()
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Println("Integrity check: ok")
+	} else {
+		fmt.Printf("Integrity check: %d problems\n", len(problems))
+		for _, p := range problems {
+			fmt.Printf("  %s\n", p)
+		}
+		fmt.Println("  Restore from a backup or re-sync into a fresh database; these can't be repaired in place.")
+	}
+
+	violations, err := db.ForeignKeyViolations()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Foreign key violations: %d\n", len(violations))
+
+	orphans, err := db.FindOrphans()
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		fmt.Println("Orphaned rows: none")
+		return nil
+	}
+
+	fmt.Println("Orphaned rows (referencing deleted activities):")
+	for _, o := range orphans {
+		fmt.Printf("  %-20s %d\n", o.Table, o.Rows)
+	}
+
+	if !*repair {
+		fmt.Println("Run `runner db verify -repair` to delete them.")
+		return nil
+	}
+	if db.ReadOnly() {
+		return errors.New("-repair deletes rows; run it without --read-only")
+	}
+
+	deleted, err := db.DeleteOrphans()
+	if err != nil {
+		return fmt.Errorf("deleting orphaned rows: %w", err)
+	}
+	fmt.Printf("Deleted %d orphaned rows. Run `runner db vacuum` to reclaim the space.\n", deleted)
+	return nil
+}
+
+func runDBArchive(db *store.Store, args []string) error {
+	fs := flag.NewFlagSet("db archive", flag.ContinueOnError)
+	beforeFlag := fs.String("before", "", "archive runs that started before this day (YYYY-MM-DD)")
+	name := fs.String("name", "", "archive name (default: the years it covers, e.g. 2016-2020)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *beforeFlag == "" {
+		return errors.New("-before is required, e.g. runner db archive -before 2021-01-01")
+	}
+	before, err := time.Parse("2006-01-02", *beforeFlag)
+	if err != nil {
+		return fmt.Errorf("invalid -before %q, use YYYY-MM-DD", *beforeFlag)
+	}
+
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if *name == "" {
+		first, err := db.FirstActivityDate()
+		if err != nil {
+			return err
+		}
+		if first == nil || !first.Before(before) {
+			fmt.Printf("No runs before %s to archive.\n", before.Format("Jan 02, 2006"))
+			return nil
+		}
+		*name = fmt.Sprintf("%d-%d", first.Year(), before.AddDate(0, 0, -1).Year())
+	}
+	path, err := store.ArchivePath(*name)
+	if err != nil {
+		return err
+	}
+
+	b, err := db.Backup(store.BackupPreArchive)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backed up to %s (%s)\n", b.Path, formatBytes(b.Bytes))
+
+	qs := service.NewQueryService(db, cfg.Athlete)
+	if err := qs.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	moved, err := qs.ArchiveSeason(before, path)
+	if err != nil {
+		return fmt.Errorf("archiving runs: %w", err)
+	}
+	if moved == 0 {
+		fmt.Printf("No runs before %s to archive.\n", before.Format("Jan 02, 2006"))
+		return nil
+	}
+	fmt.Printf("Moved %d runs to %s\n", moved, path)
+	fmt.Println("Records set by those runs moved with them; run `runner --all-time` to browse everything.")
+	return runDBVacuum(db)
+}
+
+func runDBArchives() error {
+	archives, err := store.ListArchives()
+	if err != nil {
+		return err
+	}
+	if len(archives) == 0 {
+		fmt.Println("No season archives. Run `runner db archive -before YYYY-MM-DD` to create one.")
+		return nil
+	}
+	for _, path := range archives {
+		size := "?"
+		if info, err := os.Stat(path); err == nil {
+			size = formatBytes(info.Size())
+		}
+		fmt.Printf("%-20s %10s  %s\n", filepath.Base(path), size, path)
+	}
+	return nil
+}
+
+// formatBytes formats a byte count as B, KB, MB, or GB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		value /= unit
+		if value < unit || suffix == "GB" {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
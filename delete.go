@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+// runDelete implements `runner delete`, which removes an activity for good
+func runDelete(args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "delete without asking to run again")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: runner delete [-yes] ACTIVITY_ID")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return nil
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid activity ID %q", fs.Arg(0))
+	}
+
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	activity, err := db.GetActivity(id)
+	if errors.Is(err, store.ErrActivityNotFound) {
+		return fmt.Errorf("no activity %d", id)
+	}
+	if err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("%q on %s", activity.Name, activity.StartDateLocal.Format("Mon Jan 2, 2006 15:04"))
+	if !*yes {
+		fmt.Printf("This deletes %s with its streams, metrics, notes and records, and sync won't bring it back.\n", summary)
+		fmt.Printf("Run `runner delete -yes %d` to go ahead.\n", id)
+		return nil
+	}
+
+	// Weeks, trends and records are rebuilt the way a sync would
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	if err := querySvc.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		return fmt.Errorf("applying load model: %w", err)
+	}
+	syncSvc := service.NewSyncService(nil, db, cfg.Athlete, cfg.Analysis)
+	syncSvc.SetStorageConfig(cfg.Storage)
+
+	if _, err := syncSvc.DeleteActivity(context.Background(), id); err != nil {
+		return fmt.Errorf("deleting activity: %w", err)
+	}
+	fmt.Printf("Deleted %s\n", summary)
+	return nil
+}
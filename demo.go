@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"runner/internal/config"
+	"runner/internal/demo"
+	"runner/internal/logging"
+	"runner/internal/service"
+	"runner/internal/store"
+	"runner/internal/tui"
+)
+
+// runDemo implements `runner --demo`, which opens the TUI on a generated
+// training history in a temporary database. It needs no Strava credentials
+// and never touches the real database.
+func runDemo() error {
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "runner-demo-")
+	if err != nil {
+		return fmt.Errorf("creating demo directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return err
+	}
+	logFile, err := logging.Setup(dir, level)
+	if err != nil {
+		return fmt.Errorf("setting up logging: %w", err)
+	}
+	defer logFile.Close()
+
+	db, err := store.OpenPath(filepath.Join(dir, "demo.db"))
+	if err != nil {
+		return fmt.Errorf("opening demo database: %w", err)
+	}
+	defer db.Close()
+
+	fmt.Println("Generating demo data...")
+	if _, err := demo.Load(context.Background(), db, time.Now()); err != nil {
+		return err
+	}
+
+	// Settings show and edit the demo athlete, not the configured one
+	cfg.Athlete = demo.Athlete()
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	querySvc.SetRiegelExponent(cfg.Analysis.RiegelExponent)
+	querySvc.SetRestDayWarningDays(cfg.Analysis.RestDayWarningDays)
+	querySvc.SetRampThreshold(cfg.Analysis.RampThresholdPct)
+	querySvc.SetWeekNumbers(cfg.Display.WeekNumbers)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	if err := querySvc.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		return fmt.Errorf("applying load model: %w", err)
+	}
+
+	if err := tui.ApplyTheme(cfg.Display); err != nil {
+		return fmt.Errorf("applying theme: %w", err)
+	}
+
+	app := tui.NewApp(db, nil, nil, querySvc, *cfg, logging.Path(dir))
+	p := tea.NewProgram(app, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("running TUI: %w", err)
+	}
+	return nil
+}
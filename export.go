@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/export"
+	"runner/internal/service"
+)
+
+// runExport implements `runner export`, which writes runs as CSV for import
+// into Intervals.icu or TrainingPeaks, or as an iCalendar file
+func runExport(args []string) error {
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "intervals", "target format: intervals, trainingpeaks or ical")
+	sinceFlag := fs.String("since", "", "only export runs on or after this date, YYYY-MM-DD")
+	out := fs.String("o", "", "file to write (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: runner export [-format intervals|trainingpeaks|ical] [-since YYYY-MM-DD] [-o FILE]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if *sinceFlag != "" {
+		since, err = time.ParseInLocation("2006-01-02", *sinceFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date %q (want YYYY-MM-DD)", *sinceFlag)
+		}
+	}
+
+	db, err := openStore()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	activities, err := querySvc.GetActivitiesForExport(since)
+	if err != nil {
+		return fmt.Errorf("loading activities: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("creating export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == export.FormatICal {
+		err = export.WriteICal(w, activities, cfg.Display, time.Now())
+	} else {
+		err = export.WriteCSV(w, format, activities)
+	}
+	if err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	if *out != "" {
+		fmt.Printf("Exported %d runs to %s\n", len(activities), *out)
+	}
+	return nil
+}
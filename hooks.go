@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/hooks"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+// hookedWeekKey is the sync_state key holding the start of the last week
+// sent to weekly_summary hooks, so each week is sent once
+const hookedWeekKey = "hooked_weekly_summary"
+
+// syncPayload is the data of a sync_complete event
+type syncPayload struct {
+	ActivitiesStored    int      `json:"activities_stored"`
+	StreamsFetched      int      `json:"streams_fetched"`
+	MetricsComputed     int      `json:"metrics_computed"`
+	RecordsUpdated      int      `json:"records_updated"`
+	RacesFound          int      `json:"races_found"`
+	PredictionsComputed int      `json:"predictions_computed"`
+	Failures            []string `json:"failures,omitempty"`
+	Error               string   `json:"error,omitempty"`
+}
+
+// prPayload is the data of a new_pr event
+type prPayload struct {
+	Category        string    `json:"category"`
+	Label           string    `json:"label"`
+	ActivityID      int64     `json:"activity_id"`
+	ActivityName    string    `json:"activity_name"`
+	DistanceMeters  float64   `json:"distance_meters"`
+	DurationSeconds int       `json:"duration_seconds"`
+	PacePerMile     *float64  `json:"pace_per_mile,omitempty"` // seconds
+	AchievedAt      time.Time `json:"achieved_at"`
+
+	// Improvement is how much the previous record was beaten by, in
+	// seconds, meters or seconds per mile depending on the category; zero
+	// for a first record
+	Improvement float64 `json:"improvement"`
+}
+
+// weekPayload is the data of a weekly_summary event
+type weekPayload struct {
+	WeekStart      string  `json:"week_start"` // YYYY-MM-DD
+	Runs           int     `json:"runs"`
+	DistanceMeters float64 `json:"distance_meters"`
+	MovingSeconds  int     `json:"moving_seconds"`
+	ElevationGain  float64 `json:"elevation_gain"` // meters
+	AvgHR          float64 `json:"avg_hr,omitempty"`
+	TRIMP          float64 `json:"trimp"`
+}
+
+// syncHooks returns the SyncService.SetOnSyncDone callback that fires the
+// configured hooks, so syncs from the command line, the -every daemon and
+// the TUI all send the same events
+func syncHooks(db *store.Store, querySvc *service.QueryService, cfg []config.Hook) func(context.Context, *service.SyncResult, error) {
+	return func(ctx context.Context, result *service.SyncResult, syncErr error) {
+		runHooks(ctx, db, querySvc, cfg, result, syncErr)
+	}
+}
+
+// runHooks fires the hooks configured for one sync's events. Records and the
+// weekly summary only follow a sync that succeeded, and a cancelled sync
+// fires nothing.
+func runHooks(ctx context.Context, db *store.Store, querySvc *service.QueryService, cfg []config.Hook, result *service.SyncResult, syncErr error) {
+	if len(cfg) == 0 || ctx.Err() != nil {
+		return
+	}
+
+	hooks.Fire(ctx, cfg, config.HookSyncComplete, newSyncPayload(result, syncErr))
+	if syncErr != nil {
+		return
+	}
+
+	for _, r := range result.NewRecords {
+		hooks.Fire(ctx, cfg, config.HookNewPR, prPayload{
+			Category:        r.Record.Category,
+			Label:           r.Label(),
+			ActivityID:      r.Record.ActivityID,
+			ActivityName:    r.ActivityName,
+			DistanceMeters:  r.Record.DistanceMeters,
+			DurationSeconds: r.Record.DurationSeconds,
+			PacePerMile:     r.Record.PacePerMile,
+			AchievedAt:      r.Record.AchievedAt,
+			Improvement:     r.Margin(),
+		})
+	}
+
+	if hooks.Wants(cfg, config.HookWeeklySummary) {
+		hookWeeklySummary(ctx, db, querySvc, cfg)
+	}
+}
+
+// newSyncPayload summarizes a sync, or its error, for sync_complete hooks
+func newSyncPayload(result *service.SyncResult, syncErr error) syncPayload {
+	if syncErr != nil {
+		return syncPayload{Error: syncErr.Error()}
+	}
+	p := syncPayload{
+		ActivitiesStored:    result.ActivitiesStored,
+		StreamsFetched:      result.StreamsFetched,
+		MetricsComputed:     result.MetricsComputed,
+		RecordsUpdated:      result.PRsComputed,
+		RacesFound:          result.RacesFound,
+		PredictionsComputed: result.PredictionsComputed,
+	}
+	for _, f := range result.Failures {
+		p.Failures = append(p.Failures, phaseLabel(f.Phase)+": "+f.Err.Error())
+	}
+	return p
+}
+
+// hookWeeklySummary sends the last completed week to weekly_summary hooks,
+// unless it was sent after an earlier sync
+func hookWeeklySummary(ctx context.Context, db *store.Store, querySvc *service.QueryService, cfg []config.Hook) {
+	week, err := querySvc.GetLastWeekSummary()
+	if err != nil {
+		slog.Warn("building weekly summary for hooks", "error", err)
+		return
+	}
+	start := week.WeekStart.Format("2006-01-02")
+	sent, err := db.GetSyncState(hookedWeekKey)
+	if err != nil {
+		slog.Warn("reading last hooked week", "error", err)
+		return
+	}
+	if sent == start {
+		return
+	}
+
+	p := weekPayload{
+		WeekStart:      start,
+		Runs:           week.RunCount,
+		DistanceMeters: week.Distance,
+		MovingSeconds:  week.MovingTime,
+		ElevationGain:  week.ElevationGain,
+		TRIMP:          week.TRIMP,
+	}
+	if week.HRCount > 0 {
+		p.AvgHR = week.HRSum / float64(week.HRCount)
+	}
+	hooks.Fire(ctx, cfg, config.HookWeeklySummary, p)
+	if err := db.SetSyncState(hookedWeekKey, start); err != nil {
+		slog.Warn("saving last hooked week", "error", err)
+	}
+}
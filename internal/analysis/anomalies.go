@@ -0,0 +1,149 @@
+package analysis
+
+import "runner/internal/store"
+
+// Anomaly flags stored on ActivityMetrics.AnomalyFlags
+const (
+	AnomalyLowHRCoverage = "low_hr_coverage" // many samples without HR
+	AnomalyPaceSpike     = "pace_spike"      // stretches faster than any human can run
+	AnomalyHRFlatline    = "hr_flatline"     // HR stuck at one value near max
+	AnomalyDistanceJump  = "distance_jump"   // GPS teleport between samples
+)
+
+const (
+	// minHRCoverage is the data quality score below which HR metrics are suspect
+	minHRCoverage = 0.70
+
+	// maxPlausibleSpeed is ~2:13/km; faster samples are GPS errors
+	maxPlausibleSpeed = 7.5 // m/s
+	minSpikeSeconds   = 10
+
+	// flatlineSeconds of identical HR at or above flatlineFraction of max HR
+	// points at a strap reporting cadence or a stuck sensor
+	flatlineSeconds  = 120
+	flatlineFraction = 0.95
+
+	// maxJumpSpeed is the implied speed between two samples that counts as a
+	// jump, ignoring moves shorter than minJumpMeters
+	maxJumpSpeed  = 15.0 // m/s
+	minJumpMeters = 50.0
+)
+
+// DetectAnomalies flags stream data that is likely wrong. hrCoverage is the
+// fraction of samples with HR (the data quality score).
+func DetectAnomalies(streams []store.StreamPoint, hrCoverage float64, zones HRZones) []string {
+	if len(streams) == 0 {
+		return nil
+	}
+
+	var flags []string
+	if hrCoverage < minHRCoverage {
+		flags = append(flags, AnomalyLowHRCoverage)
+	}
+	if hasPaceSpike(streams) {
+		flags = append(flags, AnomalyPaceSpike)
+	}
+	if hasHRFlatline(streams, zones.MaxHR*flatlineFraction) {
+		flags = append(flags, AnomalyHRFlatline)
+	}
+	if hasDistanceJump(streams) {
+		flags = append(flags, AnomalyDistanceJump)
+	}
+	return flags
+}
+
+// AnomalyDescription returns a short human-readable explanation of a flag
+func AnomalyDescription(flag string) string {
+	switch flag {
+	case AnomalyLowHRCoverage:
+		return "Heart rate missing for much of the run"
+	case AnomalyPaceSpike:
+		return "Impossible pace spikes (GPS error)"
+	case AnomalyHRFlatline:
+		return "Heart rate stuck near max (strap error)"
+	case AnomalyDistanceJump:
+		return "Distance jumps between samples (GPS error)"
+	default:
+		return flag
+	}
+}
+
+// SegmentHasGPSErrors reports whether the recorded streams between two time
+// offsets contain a speed or a distance gain faster than anyone can run. A
+// best effort over such a stretch may owe its time to the GPS.
+func SegmentHasGPSErrors(streams []store.StreamPoint, start, end int) bool {
+	prev := -1 // last sample in the segment with distance
+	for i, p := range streams {
+		if p.TimeOffset < start || p.TimeOffset > end {
+			continue
+		}
+		if p.VelocitySmooth != nil && *p.VelocitySmooth > maxPlausibleSpeed {
+			return true
+		}
+		if p.Distance == nil {
+			continue
+		}
+		if prev >= 0 {
+			delta := *p.Distance - *streams[prev].Distance
+			dt := p.TimeOffset - streams[prev].TimeOffset
+			if dt > 0 && delta/float64(dt) > maxPlausibleSpeed {
+				return true
+			}
+		}
+		prev = i
+	}
+	return false
+}
+
+func hasPaceSpike(streams []store.StreamPoint) bool {
+	spikes := 0
+	for _, p := range streams {
+		if p.VelocitySmooth != nil && *p.VelocitySmooth > maxPlausibleSpeed {
+			spikes++
+			if spikes >= minSpikeSeconds {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasHRFlatline(streams []store.StreamPoint, threshold float64) bool {
+	if threshold <= 0 {
+		return false
+	}
+
+	start := -1
+	for i, p := range streams {
+		high := p.Heartrate != nil && float64(*p.Heartrate) >= threshold
+		if high && start >= 0 && *streams[start].Heartrate == *p.Heartrate {
+			if p.TimeOffset-streams[start].TimeOffset >= flatlineSeconds {
+				return true
+			}
+			continue
+		}
+		start = -1
+		if high {
+			start = i
+		}
+	}
+	return false
+}
+
+func hasDistanceJump(streams []store.StreamPoint) bool {
+	for i := 1; i < len(streams); i++ {
+		prev, cur := streams[i-1], streams[i]
+		if prev.Distance == nil || cur.Distance == nil {
+			continue
+		}
+		delta := *cur.Distance - *prev.Distance
+		if delta < minJumpMeters {
+			continue
+		}
+		dt := cur.TimeOffset - prev.TimeOffset
+		if dt <= 0 || delta/float64(dt) > maxJumpSpeed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"runner/internal/store"
+)
+
+// steadyStreams returns n seconds of clean 3 m/s running at 150 bpm
+func steadyStreams(n int) []store.StreamPoint {
+	streams := make([]store.StreamPoint, n)
+	for i := range streams {
+		streams[i] = store.StreamPoint{
+			TimeOffset:     i,
+			VelocitySmooth: floatPtr(3.0),
+			Heartrate:      intPtr(150),
+			Distance:       floatPtr(float64(i) * 3.0),
+		}
+	}
+	return streams
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	zones := NewHRZones(50, 185, 165)
+
+	tests := []struct {
+		name       string
+		streams    func() []store.StreamPoint
+		hrCoverage float64
+		want       []string
+	}{
+		{
+			name:       "no streams",
+			streams:    func() []store.StreamPoint { return nil },
+			hrCoverage: 0,
+			want:       nil,
+		},
+		{
+			name:       "clean run",
+			streams:    func() []store.StreamPoint { return steadyStreams(600) },
+			hrCoverage: 1,
+			want:       nil,
+		},
+		{
+			name:       "low HR coverage",
+			streams:    func() []store.StreamPoint { return steadyStreams(600) },
+			hrCoverage: 0.5,
+			want:       []string{AnomalyLowHRCoverage},
+		},
+		{
+			name: "pace spike",
+			streams: func() []store.StreamPoint {
+				s := steadyStreams(600)
+				for i := 100; i < 120; i++ {
+					s[i].VelocitySmooth = floatPtr(12)
+				}
+				return s
+			},
+			hrCoverage: 1,
+			want:       []string{AnomalyPaceSpike},
+		},
+		{
+			name: "brief fast sample is not a spike",
+			streams: func() []store.StreamPoint {
+				s := steadyStreams(600)
+				s[100].VelocitySmooth = floatPtr(12)
+				return s
+			},
+			hrCoverage: 1,
+			want:       nil,
+		},
+		{
+			name: "HR stuck near max",
+			streams: func() []store.StreamPoint {
+				s := steadyStreams(600)
+				for i := 200; i < 400; i++ {
+					s[i].Heartrate = intPtr(184)
+				}
+				return s
+			},
+			hrCoverage: 1,
+			want:       []string{AnomalyHRFlatline},
+		},
+		{
+			name: "high but varying HR is not a flatline",
+			streams: func() []store.StreamPoint {
+				s := steadyStreams(600)
+				for i := 200; i < 400; i++ {
+					s[i].Heartrate = intPtr(180 + i%3)
+				}
+				return s
+			},
+			hrCoverage: 1,
+			want:       nil,
+		},
+		{
+			name: "distance jump",
+			streams: func() []store.StreamPoint {
+				s := steadyStreams(600)
+				for i := 300; i < len(s); i++ {
+					s[i].Distance = floatPtr(*s[i].Distance + 500)
+				}
+				return s
+			},
+			hrCoverage: 1,
+			want:       []string{AnomalyDistanceJump},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectAnomalies(tt.streams(), tt.hrCoverage, zones)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectAnomalies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSegmentHasGPSErrors(t *testing.T) {
+	s := steadyStreams(600)
+	for i := 300; i < len(s); i++ {
+		s[i].Distance = floatPtr(*s[i].Distance + 100)
+	}
+	s[450].VelocitySmooth = floatPtr(9)
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       bool
+	}{
+		{"clean stretch", 0, 200, false},
+		{"across the jump", 250, 350, true},
+		{"speed spike", 400, 500, true},
+		{"after both", 460, 599, false},
+	}
+	for _, tt := range tests {
+		if got := SegmentHasGPSErrors(s, tt.start, tt.end); got != tt.want {
+			t.Errorf("%s: SegmentHasGPSErrors(%d, %d) = %v, want %v", tt.name, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"math"
+
+	"runner/internal/store"
+)
+
+const (
+	// BenchmarkTolerance is how far a run's distance (route benchmarks) or
+	// moving time (duration benchmarks) may be from the reference run's, as
+	// a fraction
+	BenchmarkTolerance = 0.05
+
+	// BenchmarkStartRadius is how close, in meters, a run must start to the
+	// reference run when both have GPS
+	BenchmarkStartRadius = 250.0
+
+	earthRadiusMeters = 6371000.0
+)
+
+// LatLng is a GPS position in degrees
+type LatLng struct {
+	Lat, Lng float64
+}
+
+// HaversineMeters returns the great-circle distance between two positions
+func HaversineMeters(a, b LatLng) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// StartPoint returns the first GPS position in a run's streams, or nil for
+// runs without GPS
+func StartPoint(streams []store.StreamPoint) *LatLng {
+	for _, p := range streams {
+		if p.Lat != nil && p.Lng != nil {
+			return &LatLng{Lat: *p.Lat, Lng: *p.Lng}
+		}
+	}
+	return nil
+}
+
+// BenchmarkRun is what benchmark matching compares between two runs
+type BenchmarkRun struct {
+	Distance   float64 // meters
+	MovingTime int     // seconds
+	Start      *LatLng // nil without GPS
+}
+
+// MatchesBenchmark reports whether run repeats the reference run of a
+// benchmark. Route benchmarks need about the same distance and duration
+// benchmarks about the same moving time. When both runs have GPS they must
+// also start in the same place.
+func MatchesBenchmark(kind store.BenchmarkKind, ref, run BenchmarkRun) bool {
+	var want, got float64
+	switch kind {
+	case store.BenchmarkRoute:
+		want, got = ref.Distance, run.Distance
+	case store.BenchmarkDuration:
+		want, got = float64(ref.MovingTime), float64(run.MovingTime)
+	default:
+		return false
+	}
+	if want <= 0 || math.Abs(got-want) > want*BenchmarkTolerance {
+		return false
+	}
+
+	if ref.Start != nil && run.Start != nil {
+		return HaversineMeters(*ref.Start, *run.Start) <= BenchmarkStartRadius
+	}
+	return true
+}
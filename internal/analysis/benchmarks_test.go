@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestHaversineMeters(t *testing.T) {
+	// One degree of latitude is about 111.2 km
+	got := HaversineMeters(LatLng{Lat: 40, Lng: -105}, LatLng{Lat: 41, Lng: -105})
+	if math.Abs(got-111195) > 10 {
+		t.Errorf("HaversineMeters() = %.0f, want about 111195", got)
+	}
+	if d := HaversineMeters(LatLng{Lat: 40, Lng: -105}, LatLng{Lat: 40, Lng: -105}); d != 0 {
+		t.Errorf("expected 0 for the same point, got %v", d)
+	}
+}
+
+func TestStartPoint(t *testing.T) {
+	lat, lng := 40.0, -105.0
+	streams := []store.StreamPoint{{TimeOffset: 0}, {TimeOffset: 1, Lat: &lat, Lng: &lng}}
+	if p := StartPoint(streams); p == nil || p.Lat != lat || p.Lng != lng {
+		t.Errorf("StartPoint() = %+v, want the first point with GPS", p)
+	}
+	if p := StartPoint(streams[:1]); p != nil {
+		t.Errorf("StartPoint() = %+v, want nil without GPS", p)
+	}
+}
+
+func TestMatchesBenchmark(t *testing.T) {
+	home := &LatLng{Lat: 40, Lng: -105}
+	nearby := &LatLng{Lat: 40.001, Lng: -105} // about 110 m north
+	across := &LatLng{Lat: 40.01, Lng: -105}  // about 1.1 km north
+	ref := BenchmarkRun{Distance: 8000, MovingTime: 1800, Start: home}
+
+	tests := []struct {
+		name string
+		kind store.BenchmarkKind
+		run  BenchmarkRun
+		want bool
+	}{
+		{"same loop, faster", store.BenchmarkRoute, BenchmarkRun{Distance: 8100, MovingTime: 1700, Start: nearby}, true},
+		{"loop started elsewhere", store.BenchmarkRoute, BenchmarkRun{Distance: 8000, MovingTime: 1800, Start: across}, false},
+		{"longer run from home", store.BenchmarkRoute, BenchmarkRun{Distance: 10000, MovingTime: 2300, Start: home}, false},
+		{"loop without GPS", store.BenchmarkRoute, BenchmarkRun{Distance: 7900, MovingTime: 1850}, true},
+		{"same duration, further", store.BenchmarkDuration, BenchmarkRun{Distance: 8500, MovingTime: 1820, Start: home}, true},
+		{"duration too short", store.BenchmarkDuration, BenchmarkRun{Distance: 6000, MovingTime: 1500, Start: home}, false},
+		{"unknown kind", "", BenchmarkRun{Distance: 8000, MovingTime: 1800, Start: home}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesBenchmark(tt.kind, ref, tt.run); got != tt.want {
+				t.Errorf("MatchesBenchmark() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
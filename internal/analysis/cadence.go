@@ -0,0 +1,42 @@
+package analysis
+
+import "runner/internal/store"
+
+// Strava cadence streams count one foot, so steps per minute is double
+const stepsPerStravaCadence = 2
+
+// Samples slower than minStrideSpeed (walking, standing) are ignored
+const minStrideSpeed = 1.0 // m/s
+
+// StrideLength returns meters covered per step at the given speed (m/s) and
+// Strava cadence (single-leg steps per minute)
+func StrideLength(velocity float64, cadence int) float64 {
+	if velocity <= 0 || cadence <= 0 {
+		return 0
+	}
+	stepsPerSecond := float64(cadence*stepsPerStravaCadence) / 60
+	return velocity / stepsPerSecond
+}
+
+// AverageStrideLength returns the mean stride length (meters per step) over
+// samples with both running speed and cadence
+func AverageStrideLength(streams []store.StreamPoint) float64 {
+	var sum float64
+	var count int
+
+	for _, p := range streams {
+		if p.VelocitySmooth == nil || p.Cadence == nil {
+			continue
+		}
+		if *p.VelocitySmooth < minStrideSpeed || *p.Cadence <= 0 {
+			continue
+		}
+		sum += StrideLength(*p.VelocitySmooth, *p.Cadence)
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestStrideLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		velocity float64
+		cadence  int
+		want     float64
+	}{
+		// 90 single-leg = 180 spm = 3 steps/s
+		{"3 m/s at 180 spm", 3.0, 90, 1.0},
+		{"4 m/s at 160 spm", 4.0, 80, 1.5},
+		{"no cadence", 3.0, 0, 0},
+		{"standing", 0, 90, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StrideLength(tt.velocity, tt.cadence)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("StrideLength(%v, %d) = %v, want %v", tt.velocity, tt.cadence, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAverageStrideLength(t *testing.T) {
+	streams := []store.StreamPoint{
+		{VelocitySmooth: floatPtr(3.0), Cadence: intPtr(90)},
+		{VelocitySmooth: floatPtr(4.0), Cadence: intPtr(80)},
+		{VelocitySmooth: floatPtr(0.5), Cadence: intPtr(60)}, // walking, ignored
+		{VelocitySmooth: floatPtr(3.0)},                      // no cadence, ignored
+	}
+
+	got := AverageStrideLength(streams)
+	if math.Abs(got-1.25) > 1e-9 {
+		t.Errorf("AverageStrideLength() = %v, want 1.25", got)
+	}
+
+	if got := AverageStrideLength(nil); got != 0 {
+		t.Errorf("AverageStrideLength(nil) = %v, want 0", got)
+	}
+}
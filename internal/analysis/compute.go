@@ -12,21 +12,25 @@ func ComputeActivityMetrics(activity store.Activity, streams []store.StreamPoint
 		return metrics
 	}
 
+	// EF, decoupling and drift leave out stops, where HR falls while speed
+	// is zero, and decoupling splits the moving time rather than the clock
+	moving := MovingStreams(streams)
+
 	// Efficiency Factor
-	ef := EfficiencyFactor(streams)
+	ef := EfficiencyFactor(moving)
 	if ef > 0 {
 		metrics.EfficiencyFactor = &ef
 	}
 
 	// Aerobic Decoupling
-	decoupling := AerobicDecoupling(streams)
+	decoupling := AerobicDecoupling(moving)
 	if decoupling != 0 {
 		metrics.AerobicDecoupling = &decoupling
 	}
 
 	// Cardiac Drift
 	avgPace := activity.Distance / float64(activity.MovingTime) // m/s
-	drift := CardiacDrift(streams, avgPace)
+	drift := CardiacDrift(moving, avgPace)
 	if drift != 0 {
 		metrics.CardiacDrift = &drift
 	}
@@ -52,31 +56,44 @@ func ComputeActivityMetrics(activity store.Activity, streams []store.StreamPoint
 	quality := float64(validPoints) / float64(len(streams))
 	metrics.DataQualityScore = &quality
 
+	// Suspect data: HR gaps, GPS spikes and jumps, stuck HR straps
+	metrics.AnomalyFlags = DetectAnomalies(streams, quality, zones)
+
 	// Steady State Percentage
 	steadyPct := SteadyStatePct(streams, avgPace)
 	if steadyPct > 0 {
 		metrics.SteadyStatePct = &steadyPct
 	}
 
-	// Pace at HR Zones (using typical zone midpoints)
-	// Z1: ~60% max HR, Z2: ~70% max HR, Z3: ~80% max HR
-	z1HR := zones.RestingHR + (zones.MaxHR-zones.RestingHR)*0.6
-	z2HR := zones.RestingHR + (zones.MaxHR-zones.RestingHR)*0.7
-	z3HR := zones.RestingHR + (zones.MaxHR-zones.RestingHR)*0.8
+	// Pacing: half-to-half split and per-km consistency
+	split := PacingSplit(streams)
+	if split != 0 {
+		metrics.PacingSplit = &split
+	}
+
+	variability := PaceVariability(streams)
+	if variability > 0 {
+		metrics.PaceVariability = &variability
+	}
 
-	paceZ1 := PaceAtHR(streams, z1HR, 5)
-	if paceZ1 > 0 {
-		metrics.PaceAtZ1 = &paceZ1
+	// Stride length from cadence and speed
+	stride := AverageStrideLength(streams)
+	if stride > 0 {
+		metrics.AvgStrideLength = &stride
 	}
 
-	paceZ2 := PaceAtHR(streams, z2HR, 5)
-	if paceZ2 > 0 {
-		metrics.PaceAtZ2 = &paceZ2
+	// Heart rate recovery after the hardest effort
+	hrr := HeartRateRecovery(streams, zones)
+	if hrr > 0 {
+		metrics.HRR60 = &hrr
 	}
 
-	paceZ3 := PaceAtHR(streams, z3HR, 5)
-	if paceZ3 > 0 {
-		metrics.PaceAtZ3 = &paceZ3
+	// Pace at the HR anchors, by default 60, 70 and 80% of heart rate reserve
+	paces := []**float64{&metrics.PaceAtZ1, &metrics.PaceAtZ2, &metrics.PaceAtZ3}
+	for i, hr := range zones.PaceAnchorHRs() {
+		if pace := PaceAtHR(streams, hr, 5); pace > 0 {
+			*paces[i] = &pace
+		}
 	}
 
 	return metrics
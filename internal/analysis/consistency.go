@@ -0,0 +1,49 @@
+package analysis
+
+// GoalHitFraction is the share of the weekly distance goal that counts as
+// hitting it, so a week just short still rewards showing up
+const GoalHitFraction = 0.8
+
+// GoalConsistency measures how reliably weekly distance meets the goal
+type GoalConsistency struct {
+	// Percent is the share of the last Weeks completed weeks that hit the
+	// goal, 0-100
+	Percent float64
+	Weeks   int
+
+	// Streak counts consecutive weeks that hit the goal, up to the last
+	// completed week. The current week joins it once it hits the goal but
+	// doesn't break it until the week is over.
+	Streak int
+}
+
+// CalculateGoalConsistency works out goal consistency from weekly distances,
+// oldest first, ending with the current week so far. window is how many
+// completed weeks Percent covers; the streak uses all the weeks given.
+func CalculateGoalConsistency(weekly []float64, goal float64, window int) GoalConsistency {
+	if goal <= 0 || len(weekly) < 2 {
+		return GoalConsistency{}
+	}
+	hit := func(d float64) bool { return d >= goal*GoalHitFraction }
+
+	current := len(weekly) - 1
+	completed := weekly[:current]
+
+	var c GoalConsistency
+	hits := 0
+	for i := len(completed) - 1; i >= 0 && i >= len(completed)-window; i-- {
+		c.Weeks++
+		if hit(completed[i]) {
+			hits++
+		}
+	}
+	c.Percent = float64(hits) * 100 / float64(c.Weeks)
+
+	for i := len(completed) - 1; i >= 0 && hit(completed[i]); i-- {
+		c.Streak++
+	}
+	if hit(weekly[current]) {
+		c.Streak++
+	}
+	return c
+}
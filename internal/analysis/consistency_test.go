@@ -0,0 +1,49 @@
+package analysis
+
+import "testing"
+
+func TestCalculateGoalConsistency(t *testing.T) {
+	tests := []struct {
+		name   string
+		weekly []float64
+		goal   float64
+		window int
+		want   GoalConsistency
+	}{
+		{"no goal", []float64{20, 20, 20}, 0, 12, GoalConsistency{}},
+		{"no completed weeks", []float64{20}, 20, 12, GoalConsistency{}},
+		{
+			name:   "current week not yet hit",
+			weekly: []float64{10, 20, 16, 18, 5},
+			goal:   20, window: 12,
+			want: GoalConsistency{Percent: 75, Weeks: 4, Streak: 3},
+		},
+		{
+			name:   "current week hit",
+			weekly: []float64{20, 0, 22, 30},
+			goal:   20, window: 12,
+			want: GoalConsistency{Percent: 200.0 / 3, Weeks: 3, Streak: 2},
+		},
+		{
+			name:   "streak longer than the window",
+			weekly: []float64{20, 20, 20, 20, 20, 0},
+			goal:   20, window: 2,
+			want: GoalConsistency{Percent: 100, Weeks: 2, Streak: 5},
+		},
+		{
+			name:   "last week missed",
+			weekly: []float64{20, 20, 15, 25},
+			goal:   20, window: 12,
+			want: GoalConsistency{Percent: 200.0 / 3, Weeks: 3, Streak: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateGoalConsistency(tt.weekly, tt.goal, tt.window)
+			if got != tt.want {
+				t.Errorf("CalculateGoalConsistency() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package analysis
+
+import "runner/internal/store"
+
+// CurveDurations are the durations, in seconds, of the pace-duration curve
+var CurveDurations = []int{60, 120, 300, 600, 1200, 1800, 3600, 5400}
+
+// Critical speed is fitted to efforts in this duration range. Shorter efforts
+// lean on anaerobic power and longer ones on fatigue, so neither fits the
+// two-parameter model well.
+const (
+	CriticalSpeedMinDuration = 120
+	CriticalSpeedMaxDuration = 1200
+	MinCriticalSpeedPoints   = 3
+)
+
+// minWindowCoverage is the share of a duration a stream window must span to
+// count, so sparse or paused streams don't produce short "efforts"
+const minWindowCoverage = 0.95
+
+// DurationEffort is the farthest distance covered in a fixed time within an
+// activity
+type DurationEffort struct {
+	DurationSeconds int
+	DistanceMeters  float64
+	StartOffset     int // time offset in stream where the effort starts
+}
+
+// Speed returns the average speed of the effort in m/s
+func (e DurationEffort) Speed() float64 {
+	if e.DurationSeconds <= 0 {
+		return 0
+	}
+	return e.DistanceMeters / float64(e.DurationSeconds)
+}
+
+// FindBestDurationEffort finds the farthest distance covered in any window of
+// durationSeconds within the stream data, using a two-pointer scan. Returns
+// nil if the activity is shorter than the duration or has insufficient data.
+func FindBestDurationEffort(streams []store.StreamPoint, durationSeconds int) *DurationEffort {
+	if len(streams) < MinPointsForEffort || durationSeconds <= 0 {
+		return nil
+	}
+
+	var points []distPoint
+	for _, p := range streams {
+		if p.Distance != nil {
+			points = append(points, distPoint{distance: *p.Distance, timeOffset: p.TimeOffset})
+		}
+	}
+	if len(points) < MinPointsForEffort {
+		return nil
+	}
+	if points[len(points)-1].timeOffset-points[0].timeOffset < durationSeconds {
+		return nil
+	}
+
+	minSpan := int(float64(durationSeconds) * minWindowCoverage)
+	var best *DurationEffort
+	right := 0
+	for left := range points {
+		if right < left {
+			right = left
+		}
+		// Extend the window as far as the duration allows
+		for right+1 < len(points) && points[right+1].timeOffset-points[left].timeOffset <= durationSeconds {
+			right++
+		}
+
+		if points[right].timeOffset-points[left].timeOffset < minSpan {
+			continue
+		}
+		dist := points[right].distance - points[left].distance
+		if best == nil || dist > best.DistanceMeters {
+			best = &DurationEffort{
+				DurationSeconds: durationSeconds,
+				DistanceMeters:  dist,
+				StartOffset:     points[left].timeOffset,
+			}
+		}
+	}
+
+	return best
+}
+
+// FindDurationEfforts returns the best effort for each of CurveDurations that
+// the activity is long enough to cover
+func FindDurationEfforts(streams []store.StreamPoint) []DurationEffort {
+	var efforts []DurationEffort
+	for _, d := range CurveDurations {
+		if e := FindBestDurationEffort(streams, d); e != nil {
+			efforts = append(efforts, *e)
+		}
+	}
+	return efforts
+}
+
+// CriticalSpeed fits the two-parameter critical speed model, distance =
+// CS * time + D', to the efforts between CriticalSpeedMinDuration and
+// CriticalSpeedMaxDuration by least squares. CS is in m/s and D' (the
+// distance that can be run above CS before exhaustion) in meters. ok is false
+// when there are too few efforts or the fit is not physical.
+func CriticalSpeed(efforts []DurationEffort) (cs, dPrime float64, ok bool) {
+	var n, sumT, sumD, sumTT, sumTD float64
+	for _, e := range efforts {
+		if e.DurationSeconds < CriticalSpeedMinDuration || e.DurationSeconds > CriticalSpeedMaxDuration {
+			continue
+		}
+		t := float64(e.DurationSeconds)
+		n++
+		sumT += t
+		sumD += e.DistanceMeters
+		sumTT += t * t
+		sumTD += t * e.DistanceMeters
+	}
+	if n < MinCriticalSpeedPoints {
+		return 0, 0, false
+	}
+
+	denom := n*sumTT - sumT*sumT
+	if denom == 0 {
+		return 0, 0, false
+	}
+	cs = (n*sumTD - sumT*sumD) / denom
+	dPrime = (sumD - cs*sumT) / n
+	if cs <= 0 || dPrime < 0 {
+		return 0, 0, false
+	}
+	return cs, dPrime, true
+}
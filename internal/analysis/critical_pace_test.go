@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestFindBestDurationEffort(t *testing.T) {
+	// 10 minutes at 3 m/s with a 2 minute surge at 4 m/s from 4:00 to 6:00
+	var streams []store.StreamPoint
+	var dist float64
+	for i := 0; i <= 600; i++ {
+		if i > 0 {
+			if i > 240 && i <= 360 {
+				dist += 4
+			} else {
+				dist += 3
+			}
+		}
+		d := dist
+		streams = append(streams, store.StreamPoint{TimeOffset: i, Distance: &d})
+	}
+
+	e := FindBestDurationEffort(streams, 120)
+	if e == nil {
+		t.Fatal("expected a 2 minute effort")
+	}
+	if e.DistanceMeters != 480 || e.StartOffset != 240 {
+		t.Errorf("expected 480 m from 240s, got %.0f m from %ds", e.DistanceMeters, e.StartOffset)
+	}
+	if e.Speed() != 4 {
+		t.Errorf("Speed() = %.2f, want 4", e.Speed())
+	}
+
+	if FindBestDurationEffort(streams, 1200) != nil {
+		t.Error("expected no 20 minute effort in a 10 minute run")
+	}
+
+	efforts := FindDurationEfforts(streams)
+	if len(efforts) != 4 { // 1, 2, 5 and 10 minutes
+		t.Errorf("expected 4 curve points, got %d", len(efforts))
+	}
+}
+
+func TestFindBestDurationEffort_Gap(t *testing.T) {
+	// Two 50 second blocks separated by a 5 minute pause: no window covers
+	// most of a 2 minute duration
+	var streams []store.StreamPoint
+	for i := 0; i < 50; i++ {
+		d := float64(i) * 3
+		streams = append(streams, store.StreamPoint{TimeOffset: i, Distance: &d})
+	}
+	for i := 0; i < 50; i++ {
+		d := 150 + float64(i)*3
+		streams = append(streams, store.StreamPoint{TimeOffset: 350 + i, Distance: &d})
+	}
+
+	if e := FindBestDurationEffort(streams, 120); e != nil {
+		t.Errorf("expected no effort across the pause, got %+v", e)
+	}
+}
+
+func TestCriticalSpeed(t *testing.T) {
+	// Efforts generated from CS = 4 m/s and D' = 200 m
+	var efforts []DurationEffort
+	for _, d := range CurveDurations {
+		efforts = append(efforts, DurationEffort{DurationSeconds: d, DistanceMeters: 4*float64(d) + 200})
+	}
+
+	cs, dPrime, ok := CriticalSpeed(efforts)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	if math.Abs(cs-4) > 1e-9 || math.Abs(dPrime-200) > 1e-6 {
+		t.Errorf("CriticalSpeed() = %.3f, %.1f; want 4, 200", cs, dPrime)
+	}
+
+	// Only efforts outside the fitting range
+	if _, _, ok := CriticalSpeed(efforts[:1]); ok {
+		t.Error("expected no fit from a single 1 minute effort")
+	}
+}
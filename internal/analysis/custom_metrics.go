@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"runner/internal/store"
+)
+
+// MetricInput is what a custom metric is computed from: the activity, its
+// streams as the standard metrics saw them, the athlete's HR values and the
+// standard metrics already computed
+type MetricInput struct {
+	Activity store.Activity
+	Streams  []store.StreamPoint
+	Zones    HRZones
+	Metrics  store.ActivityMetrics
+}
+
+// MetricComputer adds a per-activity metric to the ones ComputeActivityMetrics
+// computes. Values are stored under Name, so it must be stable and unique;
+// Label is shown next to the value on the activity detail screen.
+type MetricComputer interface {
+	Name() string
+	Label() string
+	// Compute returns the metric for one activity; ok is false when the
+	// activity lacks the data it needs. Sync analyzes several activities at
+	// once, so it may be called concurrently.
+	Compute(in MetricInput) (value float64, ok bool)
+}
+
+var (
+	metricsMu  sync.Mutex
+	registered []MetricComputer
+)
+
+// RegisterMetric adds a metric computed for every activity with streams. It
+// is meant to be called from an init function, and panics if the name is
+// empty or already taken, like database/sql.Register.
+func RegisterMetric(c MetricComputer) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if c.Name() == "" {
+		panic("analysis: RegisterMetric with an empty name")
+	}
+	for _, r := range registered {
+		if r.Name() == c.Name() {
+			panic(fmt.Sprintf("analysis: RegisterMetric called twice for %q", c.Name()))
+		}
+	}
+	registered = append(registered, c)
+}
+
+// RegisteredMetrics returns the metrics added with RegisterMetric, in the
+// order they were registered
+func RegisteredMetrics() []MetricComputer {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	return append([]MetricComputer(nil), registered...)
+}
+
+// ComputeCustomMetrics runs each computer over one activity, keyed by name.
+// Metrics without a finite value are left out.
+func ComputeCustomMetrics(in MetricInput, computers []MetricComputer) map[string]float64 {
+	values := make(map[string]float64)
+	for _, c := range computers {
+		v, ok := c.Compute(in)
+		if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		values[c.Name()] = v
+	}
+	return values
+}
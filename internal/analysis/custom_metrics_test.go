@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"runner/internal/store"
+)
+
+// constMetric is a metric computed by Go code rather than an expression
+type constMetric struct {
+	name  string
+	value float64
+	ok    bool
+}
+
+func (m constMetric) Name() string                        { return m.name }
+func (m constMetric) Label() string                       { return "Const" }
+func (m constMetric) Compute(MetricInput) (float64, bool) { return m.value, m.ok }
+
+func TestComputeCustomMetrics(t *testing.T) {
+	km, err := NewExprMetric("km", "Kilometers", "distance / 1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	computers := []MetricComputer{
+		km,
+		constMetric{name: "seven", value: 7, ok: true},
+		constMetric{name: "missing", ok: false},
+	}
+
+	got := ComputeCustomMetrics(MetricInput{Activity: store.Activity{Distance: 5000}}, computers)
+	if want := map[string]float64{"km": 5, "seven": 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeCustomMetrics() = %v, want %v", got, want)
+	}
+}
+
+func TestRegisterMetric(t *testing.T) {
+	defer func() { registered = nil }()
+
+	RegisterMetric(constMetric{name: "one"})
+	RegisterMetric(constMetric{name: "two"})
+	if got := RegisteredMetrics(); len(got) != 2 || got[0].Name() != "one" || got[1].Name() != "two" {
+		t.Errorf("RegisteredMetrics() = %v, want one and two in order", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic registering a name twice")
+		}
+	}()
+	RegisterMetric(constMetric{name: "one"})
+}
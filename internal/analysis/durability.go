@@ -0,0 +1,47 @@
+package analysis
+
+import "math"
+
+// DecouplingLimit is the aerobic decoupling (%) past which a run has
+// outlasted the athlete's aerobic endurance. Below it the aerobic base held.
+const DecouplingLimit = 5.0
+
+// LogCurve is y = Slope*ln(x) + Intercept, fitted to a per-run metric such
+// as decoupling against run duration or distance. Metrics that drift over a
+// run change fastest early on, which a straight line misses.
+type LogCurve struct {
+	Slope     float64
+	Intercept float64
+}
+
+// FitLogCurve fits a LogCurve by least squares, skipping points with x <= 0.
+// ok is false with fewer than MinTrendPoints points or when every x is the
+// same.
+func FitLogCurve(xs, ys []float64) (curve LogCurve, ok bool) {
+	var lx, fy []float64
+	for i := range xs {
+		if i < len(ys) && xs[i] > 0 {
+			lx = append(lx, math.Log(xs[i]))
+			fy = append(fy, ys[i])
+		}
+	}
+	trend, ok := FitLinearTrend(lx, fy)
+	if !ok {
+		return LogCurve{}, false
+	}
+	return LogCurve{Slope: trend.Slope, Intercept: trend.Intercept}, true
+}
+
+// At returns the curve's value at x, which must be positive
+func (c LogCurve) At(x float64) float64 {
+	return c.Slope*math.Log(x) + c.Intercept
+}
+
+// Reaches returns the x at which a rising curve reaches y. ok is false when
+// the curve is flat or falling, since it then never climbs to y.
+func (c LogCurve) Reaches(y float64) (x float64, ok bool) {
+	if c.Slope <= 0 {
+		return 0, false
+	}
+	return math.Exp((y - c.Intercept) / c.Slope), true
+}
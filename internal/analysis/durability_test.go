@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitLogCurve(t *testing.T) {
+	// Decoupling of 2% at 30 minutes rising 3 points for each doubling
+	var xs, ys []float64
+	for _, minutes := range []float64{30, 45, 60, 90, 120} {
+		xs = append(xs, minutes)
+		ys = append(ys, 2+3*math.Log2(minutes/30))
+	}
+	xs, ys = append(xs, 0), append(ys, 99) // no duration, skipped
+
+	curve, ok := FitLogCurve(xs, ys)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	if got := curve.At(60); math.Abs(got-5) > 1e-9 {
+		t.Errorf("At(60) = %.3f, want 5", got)
+	}
+	if got, ok := curve.Reaches(DecouplingLimit); !ok || math.Abs(got-60) > 1e-6 {
+		t.Errorf("Reaches(%v) = %.3f, %v; want 60", DecouplingLimit, got, ok)
+	}
+
+	if _, ok := FitLogCurve(xs[:3], ys[:3]); ok {
+		t.Error("expected no fit from 3 points")
+	}
+}
+
+func TestLogCurve_ReachesFalling(t *testing.T) {
+	curve := LogCurve{Slope: -0.5, Intercept: 4}
+	if _, ok := curve.Reaches(DecouplingLimit); ok {
+		t.Error("a falling curve should never reach the limit")
+	}
+}
@@ -0,0 +1,40 @@
+package analysis
+
+// heatCurve is the pace lost to heat at each air temperature (°C), as a
+// fraction of cool-weather pace. It follows the shape of the published
+// heat adjustment curves: nothing in cool weather, then a cost that grows
+// faster the hotter it gets, reaching around 10% in the mid-30s.
+var heatCurve = []struct {
+	tempC    float64
+	slowdown float64
+}{
+	{10, 0},
+	{15, 0.005},
+	{20, 0.015},
+	{25, 0.035},
+	{30, 0.065},
+	{35, 0.105},
+}
+
+// HeatSlowdown returns the fraction by which heat at tempC (°C) slows an
+// effort compared with cool weather. It is 0 at or below 10°C and carries on
+// along the curve's last slope above 35°C.
+func HeatSlowdown(tempC float64) float64 {
+	if tempC <= heatCurve[0].tempC {
+		return 0
+	}
+	for i := 1; i < len(heatCurve); i++ {
+		lo, hi := heatCurve[i-1], heatCurve[i]
+		if tempC <= hi.tempC || i == len(heatCurve)-1 {
+			return lo.slowdown + (tempC-lo.tempC)/(hi.tempC-lo.tempC)*(hi.slowdown-lo.slowdown)
+		}
+	}
+	return 0
+}
+
+// HeatAdjustedEF scales an efficiency factor up to what the same run would
+// have shown in cool weather. Heat slows the pace held at a given HR, so EF
+// drops by the same fraction as pace.
+func HeatAdjustedEF(ef, tempC float64) float64 {
+	return ef * (1 + HeatSlowdown(tempC))
+}
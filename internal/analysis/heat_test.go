@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHeatSlowdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		tempC float64
+		want  float64
+	}{
+		{"cold", -5, 0},
+		{"cool", 10, 0},
+		{"mild", 15, 0.005},
+		{"between points", 22.5, 0.025},
+		{"hot", 30, 0.065},
+		{"beyond the curve", 37, 0.121},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HeatSlowdown(tt.tempC); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("HeatSlowdown(%v) = %.4f, want %.4f", tt.tempC, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeatAdjustedEF(t *testing.T) {
+	if got := HeatAdjustedEF(1.5, 8); got != 1.5 {
+		t.Errorf("HeatAdjustedEF() in cool weather = %v, want 1.5", got)
+	}
+	// A 6.5% slower pace at 30°C lowers EF by the same fraction
+	if got := HeatAdjustedEF(1.5, 30); math.Abs(got-1.5975) > 1e-9 {
+		t.Errorf("HeatAdjustedEF() at 30°C = %v, want 1.5975", got)
+	}
+}
@@ -0,0 +1,110 @@
+package analysis
+
+import "runner/internal/store"
+
+// flatGrade is the grade (%) either side of level still counted as flat.
+// grade_smooth wanders about a percent on roads that run flat.
+const flatGrade = 2.0
+
+// Samples slower than minHillSpeed are stops, and gaps between samples
+// longer than maxHillSampleGap are pauses; neither counts as time on a hill
+const (
+	minHillSpeed     = 0.5 // m/s
+	maxHillSampleGap = 10  // seconds
+)
+
+// HillStats splits a run's moving time and distance by terrain, using the
+// smoothed grade stream
+type HillStats struct {
+	AscentSeconds  int
+	DescentSeconds int
+	FlatSeconds    int
+
+	AscentDistance  float64 // meters
+	DescentDistance float64 // meters
+	FlatDistance    float64 // meters
+
+	// Vertical meters from grade × distance, which follows the route more
+	// closely than the noisy altitude stream
+	Climbed   float64
+	Descended float64
+}
+
+// UphillSpeed returns the average speed (m/s) while ascending, 0 without
+// ascents
+func (h HillStats) UphillSpeed() float64 {
+	if h.AscentSeconds == 0 {
+		return 0
+	}
+	return h.AscentDistance / float64(h.AscentSeconds)
+}
+
+// DownhillSpeed returns the average speed (m/s) while descending, 0 without
+// descents
+func (h HillStats) DownhillSpeed() float64 {
+	if h.DescentSeconds == 0 {
+		return 0
+	}
+	return h.DescentDistance / float64(h.DescentSeconds)
+}
+
+// VerticalSpeed returns meters climbed per hour spent ascending
+func (h HillStats) VerticalSpeed() float64 {
+	if h.AscentSeconds == 0 {
+		return 0
+	}
+	return h.Climbed / float64(h.AscentSeconds) * 3600
+}
+
+// ComputeHillStats sorts each moving interval between samples by the grade
+// at its end: ascending above flatGrade, descending below -flatGrade, flat
+// otherwise. Returns nil when the stream has no grade data.
+func ComputeHillStats(streams []store.StreamPoint) *HillStats {
+	var h HillStats
+	found := false
+
+	for i := 1; i < len(streams); i++ {
+		prev, p := streams[i-1], streams[i]
+		if p.GradeSmooth == nil {
+			continue
+		}
+		dt := p.TimeOffset - prev.TimeOffset
+		if dt <= 0 || dt > maxHillSampleGap {
+			continue
+		}
+
+		var dist float64
+		switch {
+		case p.Distance != nil && prev.Distance != nil:
+			dist = *p.Distance - *prev.Distance
+		case p.VelocitySmooth != nil:
+			dist = *p.VelocitySmooth * float64(dt)
+		default:
+			continue
+		}
+		if dist < minHillSpeed*float64(dt) {
+			continue
+		}
+
+		found = true
+		grade := *p.GradeSmooth
+		switch {
+		case grade > flatGrade:
+			h.AscentSeconds += dt
+			h.AscentDistance += dist
+			h.Climbed += dist * grade / 100
+		case grade < -flatGrade:
+			h.DescentSeconds += dt
+			h.DescentDistance += dist
+			h.Descended -= dist * grade / 100
+		default:
+			h.FlatSeconds += dt
+			h.FlatDistance += dist
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &h
+}
@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestComputeHillStats(t *testing.T) {
+	// 1 Hz samples: 100 s up a 5% grade at 2.5 m/s, 50 s flat at 3 m/s,
+	// 100 s down a 5% grade at 4 m/s, then a 30 s stop
+	var streams []store.StreamPoint
+	dist := 0.0
+	add := func(seconds int, speed, grade float64) {
+		for i := 0; i < seconds; i++ {
+			dist += speed
+			streams = append(streams, store.StreamPoint{
+				TimeOffset:  len(streams),
+				Distance:    floatPtr(dist),
+				GradeSmooth: floatPtr(grade),
+			})
+		}
+	}
+	add(1, 0, 0)
+	add(100, 2.5, 5)
+	add(50, 3, 0.5)
+	add(100, 4, -5)
+	add(30, 0, 0)
+
+	h := ComputeHillStats(streams)
+	if h == nil {
+		t.Fatal("ComputeHillStats() = nil, want stats")
+	}
+	if h.AscentSeconds != 100 || h.FlatSeconds != 50 || h.DescentSeconds != 100 {
+		t.Errorf("seconds up/flat/down = %d/%d/%d, want 100/50/100", h.AscentSeconds, h.FlatSeconds, h.DescentSeconds)
+	}
+	if math.Abs(h.UphillSpeed()-2.5) > 1e-9 || math.Abs(h.DownhillSpeed()-4) > 1e-9 {
+		t.Errorf("uphill/downhill speed = %v/%v, want 2.5/4", h.UphillSpeed(), h.DownhillSpeed())
+	}
+	// 250 m at 5% is 12.5 m up in 100 s
+	if math.Abs(h.Climbed-12.5) > 1e-9 || math.Abs(h.Descended-20) > 1e-9 {
+		t.Errorf("climbed/descended = %v/%v, want 12.5/20", h.Climbed, h.Descended)
+	}
+	if math.Abs(h.VerticalSpeed()-450) > 1e-9 {
+		t.Errorf("VerticalSpeed() = %v, want 450 m/h", h.VerticalSpeed())
+	}
+
+	if h := ComputeHillStats([]store.StreamPoint{{TimeOffset: 0}, {TimeOffset: 1, VelocitySmooth: floatPtr(3)}}); h != nil {
+		t.Errorf("ComputeHillStats() = %+v without grade, want nil", h)
+	}
+}
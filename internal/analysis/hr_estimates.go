@@ -0,0 +1,20 @@
+package analysis
+
+import "math"
+
+// ThresholdHRFraction is the share of max HR used as a first guess at
+// threshold HR, which sits around 88-92% of max in trained runners
+const ThresholdHRFraction = 0.9
+
+// EstimateMaxHR estimates max HR from age with Tanaka's formula,
+// 208 - 0.7 × age, which fits adults better than 220 - age. Individuals
+// commonly differ from it by 10 bpm either way, so a measured max is better.
+func EstimateMaxHR(age float64) float64 {
+	return math.Round(208 - 0.7*age)
+}
+
+// EstimateThresholdHR estimates lactate threshold HR from max HR, for
+// athletes who haven't done a 30-minute time trial to measure it
+func EstimateThresholdHR(maxHR float64) float64 {
+	return math.Round(maxHR * ThresholdHRFraction)
+}
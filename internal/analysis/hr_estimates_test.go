@@ -0,0 +1,25 @@
+package analysis
+
+import "testing"
+
+func TestEstimateMaxHR(t *testing.T) {
+	tests := []struct {
+		age  float64
+		want float64
+	}{
+		{20, 194},
+		{40, 180},
+		{55, 170}, // 169.5 rounds up
+	}
+	for _, tt := range tests {
+		if got := EstimateMaxHR(tt.age); got != tt.want {
+			t.Errorf("EstimateMaxHR(%v) = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateThresholdHR(t *testing.T) {
+	if got := EstimateThresholdHR(185); got != 167 {
+		t.Errorf("EstimateThresholdHR(185) = %v, want 167", got)
+	}
+}
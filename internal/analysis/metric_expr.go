@@ -0,0 +1,331 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ExprMetric is a custom metric written as an arithmetic expression over an
+// activity's summary values, its standard metrics and its streams, e.g.
+// "time_above_hr(170) / 60" or "avg_hr * moving_time / 60 / (distance / 1000)".
+// An activity missing a value the expression uses gets no metric.
+type ExprMetric struct {
+	name  string
+	label string
+	eval  exprNode
+}
+
+// exprNode evaluates part of an expression; ok is false when a value it
+// needs is missing or it divides by zero
+type exprNode func(in *MetricInput) (float64, bool)
+
+// exprVariables are the names an expression can use for a value
+var exprVariables = map[string]func(in *MetricInput) (float64, bool){
+	"distance":       func(in *MetricInput) (float64, bool) { return in.Activity.Distance, true },
+	"moving_time":    func(in *MetricInput) (float64, bool) { return float64(in.Activity.MovingTime), true },
+	"elapsed_time":   func(in *MetricInput) (float64, bool) { return float64(in.Activity.ElapsedTime), true },
+	"elevation_gain": func(in *MetricInput) (float64, bool) { return in.Activity.TotalElevationGain, true },
+	"avg_speed":      func(in *MetricInput) (float64, bool) { return in.Activity.AverageSpeed, true },
+	"avg_hr":         func(in *MetricInput) (float64, bool) { return optional(in.Activity.AverageHeartrate) },
+	"max_hr":         func(in *MetricInput) (float64, bool) { return optional(in.Activity.MaxHeartrate) },
+	"avg_cadence": func(in *MetricInput) (float64, bool) {
+		// Strava counts one foot
+		v, ok := optional(in.Activity.AverageCadence)
+		return v * 2, ok
+	},
+	"ef":           func(in *MetricInput) (float64, bool) { return optional(in.Metrics.EfficiencyFactor) },
+	"decoupling":   func(in *MetricInput) (float64, bool) { return optional(in.Metrics.AerobicDecoupling) },
+	"trimp":        func(in *MetricInput) (float64, bool) { return optional(in.Metrics.TRIMP) },
+	"hrss":         func(in *MetricInput) (float64, bool) { return optional(in.Metrics.HRSS) },
+	"resting_hr":   func(in *MetricInput) (float64, bool) { return in.Zones.RestingHR, in.Zones.RestingHR > 0 },
+	"threshold_hr": func(in *MetricInput) (float64, bool) { return in.Zones.ThresholdHR, in.Zones.ThresholdHR > 0 },
+	"zone_max_hr":  func(in *MetricInput) (float64, bool) { return in.Zones.MaxHR, in.Zones.MaxHR > 0 },
+}
+
+// exprFunctions are the functions an expression can call, by argument count
+var exprFunctions = map[string]struct {
+	args int
+	call func(in *MetricInput, args []float64) (float64, bool)
+}{
+	"min": {2, func(_ *MetricInput, a []float64) (float64, bool) { return math.Min(a[0], a[1]), true }},
+	"max": {2, func(_ *MetricInput, a []float64) (float64, bool) { return math.Max(a[0], a[1]), true }},
+	"abs": {1, func(_ *MetricInput, a []float64) (float64, bool) { return math.Abs(a[0]), true }},
+	"time_above_hr": {1, func(in *MetricInput, a []float64) (float64, bool) {
+		return timeWhereHR(in, func(hr float64) bool { return hr > a[0] })
+	}},
+	"time_below_hr": {1, func(in *MetricInput, a []float64) (float64, bool) {
+		return timeWhereHR(in, func(hr float64) bool { return hr < a[0] })
+	}},
+	"time_between_hr": {2, func(in *MetricInput, a []float64) (float64, bool) {
+		return timeWhereHR(in, func(hr float64) bool { return hr >= a[0] && hr <= a[1] })
+	}},
+}
+
+// NewExprMetric parses expr into a metric stored under name. The label
+// defaults to the name.
+func NewExprMetric(name, label, expr string) (*ExprMetric, error) {
+	p := &exprParser{tokens: tokenize(expr)}
+	eval, err := p.parseExpr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("metric %q: %w", name, err)
+	}
+	if label == "" {
+		label = name
+	}
+	return &ExprMetric{name: name, label: label, eval: eval}, nil
+}
+
+// Name returns the key the metric is stored under
+func (m *ExprMetric) Name() string { return m.name }
+
+// Label returns the metric's display name
+func (m *ExprMetric) Label() string { return m.label }
+
+// Compute evaluates the expression for one activity
+func (m *ExprMetric) Compute(in MetricInput) (float64, bool) {
+	return m.eval(&in)
+}
+
+func optional(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// timeWhereHR sums the seconds of the streams whose heart rate matches.
+// Each point counts for the time since the one before it.
+func timeWhereHR(in *MetricInput, match func(hr float64) bool) (float64, bool) {
+	var secs, withHR int
+	for i := 1; i < len(in.Streams); i++ {
+		p := in.Streams[i]
+		if p.Heartrate == nil || *p.Heartrate <= 0 {
+			continue
+		}
+		withHR++
+		if match(float64(*p.Heartrate)) {
+			secs += p.TimeOffset - in.Streams[i-1].TimeOffset
+		}
+	}
+	return float64(secs), withHR > 0
+}
+
+// tokenize splits an expression into numbers, names and single-character
+// operators. Anything else becomes its own token, for the parser to reject.
+func tokenize(expr string) []string {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens
+}
+
+// exprParser is a recursive-descent parser for
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | name | name "(" [ expr { "," expr } ] ")" | "(" expr ")"
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "+" || p.peek() == "-" {
+		op := p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(left, right, op)
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "*" || p.peek() == "/" {
+		op := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(left, right, op)
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek() != "-" {
+		return p.parsePrimary()
+	}
+	p.next()
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return func(in *MetricInput) (float64, bool) {
+		v, ok := operand(in)
+		return -v, ok
+	}, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	case unicode.IsDigit(rune(tok[0])) || tok[0] == '.':
+		v, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok)
+		}
+		return func(*MetricInput) (float64, bool) { return v, true }, nil
+	case unicode.IsLetter(rune(tok[0])) || tok[0] == '_':
+		if p.peek() == "(" {
+			return p.parseCall(tok)
+		}
+		variable, ok := exprVariables[tok]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q (have %s)", tok, exprNames(exprVariables))
+		}
+		return func(in *MetricInput) (float64, bool) { return variable(in) }, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok)
+}
+
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	fn, ok := exprFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q (have %s)", name, exprNames(exprFunctions))
+	}
+	p.next() // "("
+
+	var args []exprNode
+	if p.peek() != ")" {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek() != "," {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.next() != ")" {
+		return nil, fmt.Errorf("missing ) after the arguments to %s", name)
+	}
+	if len(args) != fn.args {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", name, fn.args, len(args))
+	}
+
+	return func(in *MetricInput) (float64, bool) {
+		values := make([]float64, len(args))
+		for i, arg := range args {
+			v, ok := arg(in)
+			if !ok {
+				return 0, false
+			}
+			values[i] = v
+		}
+		return fn.call(in, values)
+	}, nil
+}
+
+func binary(left, right exprNode, op string) exprNode {
+	return func(in *MetricInput) (float64, bool) {
+		a, ok := left(in)
+		if !ok {
+			return 0, false
+		}
+		b, ok := right(in)
+		if !ok {
+			return 0, false
+		}
+		switch op {
+		case "+":
+			return a + b, true
+		case "-":
+			return a - b, true
+		case "*":
+			return a * b, true
+		}
+		if b == 0 {
+			return 0, false
+		}
+		return a / b, true
+	}
+}
+
+// exprNames lists the variables or functions an expression can use, for
+// error messages
+func exprNames[V any](known map[string]V) string {
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
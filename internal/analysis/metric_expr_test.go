@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestExprMetric(t *testing.T) {
+	avgHR, ef := 150.0, 1.25
+	hrs := []int{140, 160, 175, 180, 150}
+	streams := make([]store.StreamPoint, len(hrs))
+	for i := range hrs {
+		streams[i] = store.StreamPoint{TimeOffset: i * 10, Heartrate: &hrs[i]}
+	}
+	in := MetricInput{
+		Activity: store.Activity{Distance: 10000, MovingTime: 3000, AverageHeartrate: &avgHR},
+		Streams:  streams,
+		Zones:    NewHRZones(50, 190, 170),
+		Metrics:  store.ActivityMetrics{EfficiencyFactor: &ef},
+	}
+
+	tests := []struct {
+		expr   string
+		want   float64
+		wantOK bool
+	}{
+		{"distance / 1000", 10, true},
+		{"avg_hr * moving_time / 60 / (distance / 1000)", 750, true},
+		{"-ef * 2 + 1", -1.5, true},
+		{"2 * (3 + 4) - 10 / 4", 11.5, true},
+		{"time_above_hr(170)", 20, true},
+		{"time_above_hr(threshold_hr) / 60", 20.0 / 60, true},
+		{"time_between_hr(150, 175)", 30, true},
+		{"max(min(3, 5), abs(-4))", 4, true},
+		{"trimp", 0, false},                // not computed
+		{"distance / (ef - ef)", 0, false}, // divides by zero
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			m, err := NewExprMetric("test", "", tt.expr)
+			if err != nil {
+				t.Fatalf("NewExprMetric(%q) error = %v", tt.expr, err)
+			}
+			got, ok := m.Compute(in)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Compute() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewExprMetric_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "unexpected end"},
+		{"distance +", "unexpected end"},
+		{"(distance", "missing )"},
+		{"distance 5", `unexpected "5"`},
+		{"pace * 2", `unknown variable "pace"`},
+		{"sqrt(distance)", `unknown function "sqrt"`},
+		{"max(1)", "max takes 2 arguments, got 1"},
+		{"distance % 2", `unexpected "%"`},
+		{"1.2.3", `invalid number "1.2.3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := NewExprMetric("test", "", tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewExprMetric(%q) error = %v, want one containing %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+
+	m, err := NewExprMetric("km", "", "distance / 1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Label() != "km" {
+		t.Errorf("Label() = %q, want the name when none is given", m.Label())
+	}
+}
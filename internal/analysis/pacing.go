@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"math"
+
+	"runner/internal/store"
+)
+
+// Pacing grades
+const (
+	PacingEven          = "Even"
+	PacingPositiveSplit = "Positive split"
+	PacingNegativeSplit = "Negative split"
+)
+
+// evenSplitTolerance is the half-to-half pace difference (%) still graded even
+const evenSplitTolerance = 2.0
+
+// minPacingDistance is the shortest run worth a pacing analysis
+const minPacingDistance = 1000.0 // meters
+
+// PacingSplit compares second-half pace to first-half pace, splitting the run
+// at half its distance. Returns percentage - positive means the second half
+// was slower (positive split), negative means it was faster (negative split).
+func PacingSplit(streams []store.StreamPoint) float64 {
+	first, last, ok := distanceBounds(streams)
+	if !ok {
+		return 0
+	}
+
+	start, end := streams[first], streams[last]
+	total := *end.Distance - *start.Distance
+	if total < minPacingDistance {
+		return 0
+	}
+
+	// First sample at or past the halfway mark
+	half := *start.Distance + total/2
+	mid := -1
+	for i := first; i <= last; i++ {
+		if streams[i].Distance != nil && *streams[i].Distance >= half {
+			mid = i
+			break
+		}
+	}
+	if mid <= first || mid >= last {
+		return 0
+	}
+
+	mp := streams[mid]
+	d1 := *mp.Distance - *start.Distance
+	d2 := *end.Distance - *mp.Distance
+	t1 := float64(mp.TimeOffset - start.TimeOffset)
+	t2 := float64(end.TimeOffset - mp.TimeOffset)
+	if d1 <= 0 || d2 <= 0 || t1 <= 0 || t2 <= 0 {
+		return 0
+	}
+
+	pace1 := t1 / d1
+	pace2 := t2 / d2
+	return (pace2/pace1 - 1) * 100
+}
+
+// PaceVariability returns the coefficient of variation (%) of per-kilometer
+// split paces. Lower is steadier; needs at least three full kilometers.
+func PaceVariability(streams []store.StreamPoint) float64 {
+	first, last, ok := distanceBounds(streams)
+	if !ok {
+		return 0
+	}
+
+	var paces []float64
+	segStart := first
+	next := *streams[first].Distance + 1000
+	for i := first + 1; i <= last; i++ {
+		p := streams[i]
+		if p.Distance == nil || *p.Distance < next {
+			continue
+		}
+		dist := *p.Distance - *streams[segStart].Distance
+		secs := float64(p.TimeOffset - streams[segStart].TimeOffset)
+		if dist > 0 && secs > 0 {
+			paces = append(paces, secs/dist*1000)
+		}
+		segStart = i
+		next += 1000
+	}
+
+	if len(paces) < 3 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range paces {
+		sum += p
+	}
+	mean := sum / float64(len(paces))
+
+	var sq float64
+	for _, p := range paces {
+		sq += (p - mean) * (p - mean)
+	}
+	stddev := math.Sqrt(sq / float64(len(paces)))
+
+	return stddev / mean * 100
+}
+
+// PacingGrade returns a human-readable grade for a pacing split percentage
+func PacingGrade(splitPct float64) string {
+	switch {
+	case splitPct > evenSplitTolerance:
+		return PacingPositiveSplit
+	case splitPct < -evenSplitTolerance:
+		return PacingNegativeSplit
+	default:
+		return PacingEven
+	}
+}
+
+// distanceBounds returns the first and last samples that carry distance
+func distanceBounds(streams []store.StreamPoint) (first, last int, ok bool) {
+	first, last = -1, -1
+	for i, p := range streams {
+		if p.Distance == nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	return first, last, first >= 0 && last > first
+}
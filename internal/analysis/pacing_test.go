@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+// pacedStreams builds a run of 1 Hz samples with the given speed (m/s) for
+// each half
+func pacedStreams(seconds int, firstSpeed, secondSpeed float64) []store.StreamPoint {
+	streams := make([]store.StreamPoint, seconds)
+	dist := 0.0
+	for i := range streams {
+		speed := firstSpeed
+		if i >= seconds/2 {
+			speed = secondSpeed
+		}
+		streams[i] = store.StreamPoint{
+			TimeOffset:     i,
+			VelocitySmooth: floatPtr(speed),
+			Distance:       floatPtr(dist),
+		}
+		dist += speed
+	}
+	return streams
+}
+
+func TestPacingSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		streams []store.StreamPoint
+		want    float64
+		tol     float64
+	}{
+		{"empty", nil, 0, 0},
+		{"too short", pacedStreams(100, 3, 3), 0, 0},
+		{"even", pacedStreams(1200, 3, 3), 0, 0.5},
+		// 3600 m at 3 m/s then 3000 m at 2.5 m/s: the halfway mark is at
+		// 3300 m, so the halves take 1100 s and 1300 s
+		{"positive split", pacedStreams(2400, 3, 2.5), 18.2, 0.5},
+		{"negative split", pacedStreams(2400, 2.5, 3), -15.4, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PacingSplit(tt.streams)
+			if math.Abs(got-tt.want) > tt.tol {
+				t.Errorf("PacingSplit() = %.2f, want %.2f ± %.1f", got, tt.want, tt.tol)
+			}
+		})
+	}
+}
+
+func TestPaceVariability(t *testing.T) {
+	if got := PaceVariability(pacedStreams(600, 3, 3)); got != 0 {
+		t.Errorf("PaceVariability() under 3 km = %.2f, want 0", got)
+	}
+
+	steady := PaceVariability(pacedStreams(3000, 3, 3))
+	if steady > 0.5 {
+		t.Errorf("PaceVariability() of steady run = %.2f, want ~0", steady)
+	}
+
+	uneven := PaceVariability(pacedStreams(3000, 3.5, 2.5))
+	if uneven <= steady {
+		t.Errorf("PaceVariability() uneven = %.2f, want more than steady %.2f", uneven, steady)
+	}
+}
+
+func TestPacingGrade(t *testing.T) {
+	tests := []struct {
+		split float64
+		want  string
+	}{
+		{0, PacingEven},
+		{1.9, PacingEven},
+		{-2, PacingEven},
+		{5, PacingPositiveSplit},
+		{-5, PacingNegativeSplit},
+	}
+
+	for _, tt := range tests {
+		if got := PacingGrade(tt.split); got != tt.want {
+			t.Errorf("PacingGrade(%.1f) = %q, want %q", tt.split, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package analysis
+
+import "math"
+
+// ClimbCostMeters is the flat distance each meter of elevation gain is
+// worth, the usual rule of thumb for running. It prices the climb net of the
+// help from the descent back down, as on a loop.
+const ClimbCostMeters = 8
+
+// PerformanceReferenceSeconds is the 10K time a performance score of 100
+// stands for, the men's world record of 26:11
+const PerformanceReferenceSeconds = 1571
+
+// Performance is a run normalized to a flat, cool 10K so runs over
+// different routes, weather and distances can be compared on one number
+type Performance struct {
+	// Score is the normalized 10K time as a percentage of the reference
+	// time: higher is faster, and a hard flat 10K scores the same whether it
+	// was run as a 10K or as part of a hot, hilly 10-miler
+	Score float64
+
+	EquivalentSeconds int     // flat, cool 10K time
+	FlatMeters        float64 // distance plus the cost of the climbing
+	HeatSlowdown      float64 // fraction the heat slowed the pace; 0 without a temperature
+}
+
+// NormalizePerformance scores a run of distanceMeters in seconds with
+// elevationGain meters of climbing. The climb is added to the distance at
+// ClimbCostMeters per meter, the time is scaled to cool weather along the
+// heat curve when tempC is known, and the result is carried to 10K with
+// Riegel's formula at exponent. The zero Performance is returned for
+// invalid input.
+func NormalizePerformance(distanceMeters float64, seconds int, elevationGain float64, tempC *float64, exponent float64) Performance {
+	if distanceMeters <= 0 || seconds <= 0 {
+		return Performance{}
+	}
+
+	p := Performance{FlatMeters: distanceMeters + max(elevationGain, 0)*ClimbCostMeters}
+	coolSeconds := float64(seconds)
+	if tempC != nil {
+		p.HeatSlowdown = HeatSlowdown(*tempC)
+		coolSeconds /= 1 + p.HeatSlowdown
+	}
+
+	p.EquivalentSeconds = RiegelPredict(p.FlatMeters, int(math.Round(coolSeconds)), Distance10K, exponent)
+	if p.EquivalentSeconds <= 0 {
+		return Performance{}
+	}
+	p.Score = math.Round(1000*PerformanceReferenceSeconds/float64(p.EquivalentSeconds)) / 10
+	return p
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+	"testing"
+)
+
+func TestNormalizePerformance(t *testing.T) {
+	// A flat, cool 10K needs no normalizing
+	flat := NormalizePerformance(Distance10K, 2400, 0, nil, DefaultRiegelExponent)
+	if flat.EquivalentSeconds != 2400 || flat.FlatMeters != Distance10K || flat.HeatSlowdown != 0 {
+		t.Errorf("flat 10K = %+v, want its own 40:00", flat)
+	}
+	if flat.Score != 65.5 {
+		t.Errorf("flat 10K score = %v, want 65.5", flat.Score)
+	}
+
+	// 100 m of climbing is worth 800 m of flat running
+	hilly := NormalizePerformance(Distance10K, 2400, 100, nil, DefaultRiegelExponent)
+	if hilly.FlatMeters != 10800 || hilly.Score <= flat.Score {
+		t.Errorf("hilly 10K = %+v, want 10800 flat meters scoring above the flat run", hilly)
+	}
+
+	// Heat at 30°C costs 6.5%, so a run that much slower scores the same
+	hot := 30.0
+	heat := NormalizePerformance(Distance10K, 2556, 0, &hot, DefaultRiegelExponent)
+	if heat.HeatSlowdown != 0.065 || heat.EquivalentSeconds != 2400 {
+		t.Errorf("hot 10K = %+v, want a 6.5%% slowdown back to 40:00", heat)
+	}
+	cold := -5.0
+	if got := NormalizePerformance(Distance10K, 2400, 0, &cold, DefaultRiegelExponent); got.EquivalentSeconds != 2400 {
+		t.Errorf("cold 10K = %+v, want no adjustment below 10°C", got)
+	}
+
+	// A 5K carried to 10K with Riegel's formula
+	fiveK := NormalizePerformance(Distance5K, 1200, 0, nil, DefaultRiegelExponent)
+	if want := RiegelPredict(Distance5K, 1200, Distance10K, DefaultRiegelExponent); fiveK.EquivalentSeconds != want {
+		t.Errorf("5K equivalent = %d, want %d", fiveK.EquivalentSeconds, want)
+	}
+
+	if got := NormalizePerformance(0, 2400, 0, nil, DefaultRiegelExponent); got != (Performance{}) {
+		t.Errorf("NormalizePerformance() without distance = %+v, want zero", got)
+	}
+}
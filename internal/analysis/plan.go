@@ -0,0 +1,108 @@
+package analysis
+
+import "math"
+
+const (
+	// CutbackFactor is the share of the build a cutback week keeps
+	CutbackFactor = 0.75
+
+	// TaperFloor is the share of the peak kept in race week. Taper weeks
+	// step down evenly to it.
+	TaperFloor = 0.4
+
+	// planStartFraction is where a build starts, as a share of the peak,
+	// without a base week to ramp from
+	planStartFraction = 0.5
+)
+
+// PlanWeekKind says what a week of a mileage plan is for
+type PlanWeekKind string
+
+// Plan week kinds
+const (
+	PlanBuild   PlanWeekKind = "build"
+	PlanCutback PlanWeekKind = "cutback"
+	PlanTaper   PlanWeekKind = "taper"
+	PlanRace    PlanWeekKind = "race"
+)
+
+// MileagePlanSpec shapes a build toward a race
+type MileagePlanSpec struct {
+	Weeks        int     // from the first week of the plan through race week
+	Peak         float64 // weekly volume at the top of the build
+	TaperWeeks   int     // weeks easing off before the race, race week included
+	CutbackEvery int     // every Nth week of the build is a cutback week; 0 for none
+	RampPct      float64 // most a build week may rise over the full week before
+}
+
+// PlanWeek is one week of a mileage plan
+type PlanWeek struct {
+	Kind   PlanWeekKind
+	Target float64
+}
+
+// PlanMileage lays out weekly targets from week from through race week,
+// building from base, the volume of the week before from. Build weeks grow
+// by an even factor so the last one reaches the peak, but never by more
+// than RampPct, so a low base peaks lower rather than ramping too fast.
+// Cutback weeks fall on the same weeks of the plan whatever from is, keep
+// CutbackFactor of the build and don't count toward its growth. The taper
+// steps down from the top of the build to TaperFloor of it in race week.
+// Without a base the build starts at half the peak.
+func PlanMileage(spec MileagePlanSpec, from int, base float64) []PlanWeek {
+	from = max(from, 0)
+	if from >= spec.Weeks {
+		return nil
+	}
+	taperWeeks := min(max(spec.TaperWeeks, 1), spec.Weeks)
+	taperStart := spec.Weeks - taperWeeks
+	cutback := func(i int) bool {
+		// The last build week is never a cutback, so the taper starts from the peak
+		return spec.CutbackEvery > 0 && (i+1)%spec.CutbackEvery == 0 && i < taperStart-1
+	}
+
+	level := math.Min(base, spec.Peak)
+	if level <= 0 {
+		level = spec.Peak * planStartFraction
+	}
+	steps := 0
+	for i := from; i < taperStart; i++ {
+		if !cutback(i) {
+			steps++
+		}
+	}
+	growth := 1.0
+	if steps > 0 && level < spec.Peak {
+		growth = math.Min(math.Pow(spec.Peak/level, 1/float64(steps)), 1+spec.RampPct/100)
+	}
+
+	// Starting within the taper resumes it from the peak, held to a safe
+	// ramp over the week before
+	top := spec.Peak
+	prev := base
+	weeks := make([]PlanWeek, 0, spec.Weeks-from)
+	for i := from; i < spec.Weeks; i++ {
+		var w PlanWeek
+		switch {
+		case i >= taperStart:
+			k := i - taperStart + 1
+			w.Kind = PlanTaper
+			if i == spec.Weeks-1 {
+				w.Kind = PlanRace
+			}
+			w.Target = top * (1 - (1-TaperFloor)*float64(k)/float64(taperWeeks))
+			if prev > 0 {
+				w.Target = math.Min(w.Target, RampCap(prev, spec.RampPct))
+			}
+		case cutback(i):
+			w = PlanWeek{Kind: PlanCutback, Target: level * CutbackFactor}
+		default:
+			level = math.Min(level*growth, spec.Peak)
+			top = level
+			w = PlanWeek{Kind: PlanBuild, Target: level}
+		}
+		prev = w.Target
+		weeks = append(weeks, w)
+	}
+	return weeks
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlanMileage(t *testing.T) {
+	spec := MileagePlanSpec{Weeks: 12, Peak: 60, TaperWeeks: 3, CutbackEvery: 4, RampPct: 10}
+	weeks := PlanMileage(spec, 0, 40)
+	if len(weeks) != 12 {
+		t.Fatalf("got %d weeks, want 12", len(weeks))
+	}
+
+	wantKinds := []PlanWeekKind{
+		PlanBuild, PlanBuild, PlanBuild, PlanCutback,
+		PlanBuild, PlanBuild, PlanBuild, PlanCutback,
+		PlanBuild, PlanTaper, PlanTaper, PlanRace,
+	}
+	prevBuild := 40.0
+	for i, w := range weeks {
+		if w.Kind != wantKinds[i] {
+			t.Errorf("week %d kind = %s, want %s", i+1, w.Kind, wantKinds[i])
+		}
+		if w.Kind == PlanBuild {
+			if w.Target > RampCap(prevBuild, 10)+1e-9 {
+				t.Errorf("week %d target %.1f ramps more than 10%% over %.1f", i+1, w.Target, prevBuild)
+			}
+			prevBuild = w.Target
+		}
+	}
+
+	// The last build week peaks and the taper steps down to the floor
+	if math.Abs(weeks[8].Target-60) > 1e-9 {
+		t.Errorf("last build week = %.2f, want the 60 peak", weeks[8].Target)
+	}
+	if math.Abs(weeks[3].Target-weeks[2].Target*CutbackFactor) > 1e-9 {
+		t.Errorf("cutback week = %.2f, want %.2f", weeks[3].Target, weeks[2].Target*CutbackFactor)
+	}
+	if math.Abs(weeks[9].Target-48) > 1e-9 || math.Abs(weeks[11].Target-60*TaperFloor) > 1e-9 {
+		t.Errorf("taper = %.1f .. %.1f, want 48 .. %.1f", weeks[9].Target, weeks[11].Target, 60*TaperFloor)
+	}
+
+	// From a low base the ramp cap wins and the build peaks lower
+	low := PlanMileage(spec, 0, 10)
+	if got, want := low[8].Target, 10*math.Pow(1.1, 7); math.Abs(got-want) > 1e-9 {
+		t.Errorf("capped peak = %.2f, want %.2f", got, want)
+	}
+
+	// Replanning mid-build keeps cutbacks on the same weeks
+	rest := PlanMileage(spec, 5, 30)
+	if len(rest) != 7 || rest[2].Kind != PlanCutback || rest[0].Target > RampCap(30, 10)+1e-9 {
+		t.Errorf("unexpected replan from week 6: %+v", rest)
+	}
+
+	// Replanning within the taper holds to a safe ramp over the week before
+	taper := PlanMileage(spec, 10, 20)
+	if len(taper) != 2 || math.Abs(taper[0].Target-22) > 1e-9 || taper[1].Kind != PlanRace {
+		t.Errorf("unexpected replan from the taper: %+v", taper)
+	}
+
+	if PlanMileage(spec, 12, 40) != nil {
+		t.Error("expected no weeks after race week")
+	}
+}
@@ -58,10 +58,20 @@ var PRPriority = map[string]int{
 	"effort_400m":   10,
 }
 
+// RacePriorityBonus lifts PRs set in races above every PR set in training,
+// since a race effort is the truest measure of fitness
+const RacePriorityBonus = 1000
+
 // SelectBestSourcePR chooses the best PR for race predictions
 // Prefers longer race distances over best efforts
 // Requires PR from last 365 days
 func SelectBestSourcePR(prs []store.PersonalRecord) *SourcePR {
+	return SelectBestSourcePRWithRaces(prs, nil)
+}
+
+// SelectBestSourcePRWithRaces is SelectBestSourcePR, but prefers PRs set in
+// the given race activities over any set in training
+func SelectBestSourcePRWithRaces(prs []store.PersonalRecord, races map[int64]bool) *SourcePR {
 	if len(prs) == 0 {
 		return nil
 	}
@@ -83,6 +93,9 @@ func SelectBestSourcePR(prs []store.PersonalRecord) *SourcePR {
 		if !ok {
 			continue
 		}
+		if races[pr.ActivityID] {
+			priority += RacePriorityBonus
+		}
 
 		// Select the highest priority PR
 		if priority > bestPriority {
@@ -92,6 +92,25 @@ func TestSelectBestSourcePR(t *testing.T) {
 	}
 }
 
+func TestSelectBestSourcePRWithRaces(t *testing.T) {
+	recent := time.Now().AddDate(0, -1, 0)
+	prs := []store.PersonalRecord{
+		{Category: "distance_half", ActivityID: 1, AchievedAt: recent, DistanceMeters: DistanceHalfMara, DurationSeconds: 5400},
+		{Category: "distance_5k", ActivityID: 2, AchievedAt: recent, DistanceMeters: Distance5K, DurationSeconds: 1200},
+	}
+
+	// Without races the longer distance wins
+	if got := SelectBestSourcePRWithRaces(prs, nil); got == nil || got.Category != "distance_half" {
+		t.Errorf("expected distance_half without races, got %+v", got)
+	}
+
+	// A 5K race beats a half run in training
+	got := SelectBestSourcePRWithRaces(prs, map[int64]bool{2: true})
+	if got == nil || got.Category != "distance_5k" {
+		t.Errorf("expected the 5K race, got %+v", got)
+	}
+}
+
 func TestCalculateConfidence(t *testing.T) {
 	now := time.Now()
 
@@ -0,0 +1,58 @@
+package analysis
+
+import "sort"
+
+// Race detection thresholds. GPS usually measures a course slightly long, so
+// a race may read a little over its distance but rarely under it.
+const (
+	RaceDistanceUnder  = 0.01 // up to 1% short of the race distance
+	RaceDistanceOver   = 0.04 // up to 4% over it
+	RacePaceMargin     = 0.10 // at least 10% faster than the median pace
+	MinRunsForRaceScan = 10   // runs needed before the median pace means anything
+)
+
+// RaceDistanceFor returns the race category an activity's distance nearly
+// exactly matches, using the tighter race tolerances rather than the 5% used
+// for personal records
+func RaceDistanceFor(distanceMeters float64) (category string, matches bool) {
+	for cat, dist := range RaceDistances {
+		if distanceMeters >= dist*(1-RaceDistanceUnder) && distanceMeters <= dist*(1+RaceDistanceOver) {
+			return cat, true
+		}
+	}
+	return "", false
+}
+
+// IsLikelyRace reports whether a run looks like a race: a near-exact race
+// distance run at least RacePaceMargin faster than the athlete's median pace
+// (seconds per mile)
+func IsLikelyRace(distanceMeters float64, movingTime int, medianPacePerMile float64) bool {
+	if medianPacePerMile <= 0 {
+		return false
+	}
+	if _, matches := RaceDistanceFor(distanceMeters); !matches {
+		return false
+	}
+	pace := CalculatePacePerMile(distanceMeters, movingTime)
+	return pace > 0 && pace <= medianPacePerMile*(1-RacePaceMargin)
+}
+
+// MedianPace returns the median of the paces, ignoring non-positive values.
+// Returns 0 with fewer than MinRunsForRaceScan paces.
+func MedianPace(paces []float64) float64 {
+	var valid []float64
+	for _, p := range paces {
+		if p > 0 {
+			valid = append(valid, p)
+		}
+	}
+	if len(valid) < MinRunsForRaceScan {
+		return 0
+	}
+	sort.Float64s(valid)
+	mid := len(valid) / 2
+	if len(valid)%2 == 0 {
+		return (valid[mid-1] + valid[mid]) / 2
+	}
+	return valid[mid]
+}
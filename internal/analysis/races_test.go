@@ -0,0 +1,42 @@
+package analysis
+
+import "testing"
+
+func TestIsLikelyRace(t *testing.T) {
+	median := 540.0 // 9:00/mi
+
+	tests := []struct {
+		name       string
+		distance   float64
+		movingTime int
+		want       bool
+	}{
+		{"fast 5K", 5050, 1500, true},  // ~7:58/mi
+		{"easy 5K", 5000, 1680, false}, // ~9:01/mi
+		{"fast but off distance", 6500, 1900, false},
+		{"fast 5K measured short", 4900, 1450, false},
+		{"fast 10K", 10150, 2950, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsLikelyRace(tt.distance, tt.movingTime, median); got != tt.want {
+				t.Errorf("IsLikelyRace(%.0f, %d) = %v, want %v", tt.distance, tt.movingTime, got, tt.want)
+			}
+		})
+	}
+
+	if IsLikelyRace(5000, 1200, 0) {
+		t.Error("expected no race without a median pace")
+	}
+}
+
+func TestMedianPace(t *testing.T) {
+	paces := []float64{500, 510, 520, 530, 540, 550, 560, 570, 580, 590, 0}
+	if got := MedianPace(paces); got != 545 {
+		t.Errorf("MedianPace() = %v, want 545", got)
+	}
+	if got := MedianPace(paces[:5]); got != 0 {
+		t.Errorf("expected 0 with too few runs, got %v", got)
+	}
+}
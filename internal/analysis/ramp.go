@@ -0,0 +1,55 @@
+package analysis
+
+const (
+	// RampAcuteDays is the recent window a ramp measures
+	RampAcuteDays = 7
+
+	// RampBaseWeeks is how many weeks before the recent window a ramp
+	// compares against
+	RampBaseWeeks = 4
+)
+
+// WeeklyRamp returns how much the RampAcuteDays before day i rose above the
+// average week of the RampBaseWeeks before them, as a percentage; negative
+// when volume dropped. daily holds one total per consecutive day, so day i
+// itself is not counted. ok is false without a full base period or when the
+// base period was empty.
+func WeeklyRamp(daily []float64, i int) (pct float64, ok bool) {
+	baseDays := RampBaseWeeks * 7
+	if i > len(daily) || i < RampAcuteDays+baseDays {
+		return 0, false
+	}
+
+	var recent, base float64
+	for d := i - RampAcuteDays; d < i; d++ {
+		recent += daily[d]
+	}
+	for d := i - RampAcuteDays - baseDays; d < i-RampAcuteDays; d++ {
+		base += daily[d]
+	}
+	if base <= 0 {
+		return 0, false
+	}
+	weekly := base / RampBaseWeeks
+	return (recent/weekly - 1) * 100, true
+}
+
+// DefaultRampThresholdPct is the classic 10% rule: a week's volume shouldn't
+// rise more than this above the week before
+const DefaultRampThresholdPct = 10
+
+// WeekOverWeekRamp returns how much a week's volume rose above the week
+// before, as a percentage; negative when it dropped. ok is false when the
+// week before had no volume.
+func WeekOverWeekRamp(previous, week float64) (pct float64, ok bool) {
+	if previous <= 0 {
+		return 0, false
+	}
+	return (week/previous - 1) * 100, true
+}
+
+// RampCap returns the most volume a week can hold after a week of previous
+// without ramping more than thresholdPct
+func RampCap(previous, thresholdPct float64) float64 {
+	return previous * (1 + thresholdPct/100)
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeeklyRamp(t *testing.T) {
+	// Four weeks of 30 a week, then a 45 week: a 50% ramp
+	daily := make([]float64, 35)
+	for d := 0; d < 28; d++ {
+		if d%7 < 3 {
+			daily[d] = 10
+		}
+	}
+	for d := 28; d < 35; d++ {
+		if d%7 < 3 {
+			daily[d] = 15
+		}
+	}
+
+	pct, ok := WeeklyRamp(daily, 35)
+	if !ok || math.Abs(pct-50) > 1e-9 {
+		t.Errorf("WeeklyRamp() = %v, %v; want 50, true", pct, ok)
+	}
+
+	// Not enough history before day 34
+	if _, ok := WeeklyRamp(daily, 34); ok {
+		t.Error("expected no ramp without a full base period")
+	}
+	// Past the end of the data
+	if _, ok := WeeklyRamp(daily, 36); ok {
+		t.Error("expected no ramp past the end of the data")
+	}
+	// An empty base period has nothing to compare against
+	if _, ok := WeeklyRamp(make([]float64, 35), 35); ok {
+		t.Error("expected no ramp from an empty base")
+	}
+
+	// A down week ramps negative
+	for d := 28; d < 35; d++ {
+		daily[d] = 0
+	}
+	daily[28] = 15
+	if pct, _ := WeeklyRamp(daily, 35); math.Abs(pct+50) > 1e-9 {
+		t.Errorf("WeeklyRamp() = %v; want -50", pct)
+	}
+}
+
+func TestWeekOverWeekRamp(t *testing.T) {
+	if pct, ok := WeekOverWeekRamp(40, 46); !ok || math.Abs(pct-15) > 1e-9 {
+		t.Errorf("WeekOverWeekRamp(40, 46) = %v, %v; want 15, true", pct, ok)
+	}
+	if pct, ok := WeekOverWeekRamp(40, 30); !ok || math.Abs(pct+25) > 1e-9 {
+		t.Errorf("WeekOverWeekRamp(40, 30) = %v, %v; want -25, true", pct, ok)
+	}
+	if _, ok := WeekOverWeekRamp(0, 30); ok {
+		t.Error("expected no ramp after an empty week")
+	}
+	if got := RampCap(40, DefaultRampThresholdPct); math.Abs(got-44) > 1e-9 {
+		t.Errorf("RampCap(40, 10) = %v, want 44", got)
+	}
+}
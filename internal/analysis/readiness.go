@@ -0,0 +1,89 @@
+package analysis
+
+import "math"
+
+// ReadinessInput is what a day's readiness is scored from. Any field may be
+// nil; resting HR and HRV only count against a personal baseline.
+type ReadinessInput struct {
+	RestingHR         *float64 // bpm this morning
+	BaselineRestingHR *float64 // usual resting HR
+	HRV               *float64 // ms this morning
+	BaselineHRV       *float64 // usual HRV
+	SleepHours        *float64
+	Soreness          *int     // 1 (none) to 5 (very sore)
+	TSB               *float64 // training form
+}
+
+// Readiness is a 0-100 score of how ready the body is to train hard today.
+// Each input scores 75 when it's typical, higher when better and lower when
+// worse; the score is their average.
+type Readiness struct {
+	Score      float64
+	RestingHR  *float64 // score of each input that counted
+	HRV        *float64
+	Sleep      *float64
+	Soreness   *float64
+	Form       *float64
+	Components int
+}
+
+// Readiness score bands
+const (
+	ReadinessHigh = 75 // ready for a hard session
+	ReadinessLow  = 50 // keep it easy
+)
+
+// CalculateReadiness scores today's readiness. ok is false when no wellness
+// input was given: form alone says nothing about how the body feels.
+func CalculateReadiness(in ReadinessInput) (r Readiness, ok bool) {
+	var sum float64
+	add := func(score float64) *float64 {
+		score = math.Max(0, math.Min(100, score))
+		sum += score
+		r.Components++
+		return &score
+	}
+
+	// Each bpm above baseline costs 8 points
+	if in.RestingHR != nil && in.BaselineRestingHR != nil {
+		r.RestingHR = add(75 - 8*(*in.RestingHR-*in.BaselineRestingHR))
+	}
+	// 10% below baseline HRV costs 15 points
+	if in.HRV != nil && in.BaselineHRV != nil && *in.BaselineHRV > 0 {
+		r.HRV = add(75 + 150*((*in.HRV)/(*in.BaselineHRV)-1))
+	}
+	// 8 hours or more is full marks; each hour short costs 15 points, so 7
+	// hours scores about typical
+	if in.SleepHours != nil {
+		r.Sleep = add(100 - 15*(8-*in.SleepHours))
+	}
+	// No soreness scores 100, a little 75, very sore 0
+	if in.Soreness != nil {
+		r.Soreness = add(float64(125 - 25*(*in.Soreness)))
+	}
+	if r.Components == 0 {
+		return Readiness{}, false
+	}
+
+	// Fresh form helps, deep fatigue hurts: 2.5 points per TSB point
+	if in.TSB != nil {
+		r.Form = add(75 + 2.5*(*in.TSB))
+	}
+
+	r.Score = sum / float64(r.Components)
+	return r, true
+}
+
+// ReadinessAdvice describes what a readiness score suggests for today
+func ReadinessAdvice(score float64) string {
+	switch {
+	case score >= ReadinessHigh:
+		return "Ready for a hard session"
+	case score >= ReadinessLow:
+		return "Train as planned"
+	case score >= 35:
+		return "Keep it easy today"
+	default:
+		return "Consider a rest day"
+	}
+}
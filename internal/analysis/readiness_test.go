@@ -0,0 +1,53 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateReadiness(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	i := func(v int) *int { return &v }
+
+	// Form alone doesn't make a readiness score
+	if _, ok := CalculateReadiness(ReadinessInput{TSB: f(5)}); ok {
+		t.Error("expected no score without wellness inputs")
+	}
+
+	// Everything typical scores 75
+	r, ok := CalculateReadiness(ReadinessInput{
+		RestingHR: f(50), BaselineRestingHR: f(50),
+		HRV: f(60), BaselineHRV: f(60),
+		SleepHours: f(8 - 25.0/15),
+		Soreness:   i(2),
+		TSB:        f(0),
+	})
+	if !ok || r.Components != 5 {
+		t.Fatalf("CalculateReadiness() = %+v, %v; want 5 components", r, ok)
+	}
+	if math.Abs(r.Score-75) > 1e-9 {
+		t.Errorf("Score = %.2f, want 75", r.Score)
+	}
+
+	// Raised resting HR, suppressed HRV, short sleep and deep fatigue
+	r, _ = CalculateReadiness(ReadinessInput{
+		RestingHR: f(55), BaselineRestingHR: f(50),
+		HRV: f(48), BaselineHRV: f(60),
+		SleepHours: f(5),
+		Soreness:   i(4),
+		TSB:        f(-25),
+	})
+	// 35, 45, 55, 25, 12.5
+	if want := (35 + 45 + 55 + 25 + 12.5) / 5; math.Abs(r.Score-want) > 1e-9 {
+		t.Errorf("Score = %.2f, want %.2f", r.Score, want)
+	}
+	if ReadinessAdvice(r.Score) != "Consider a rest day" {
+		t.Errorf("unexpected advice %q for %.1f", ReadinessAdvice(r.Score), r.Score)
+	}
+
+	// Scores are clamped, and resting HR needs a baseline to count
+	r, _ = CalculateReadiness(ReadinessInput{RestingHR: f(40), SleepHours: f(10), Soreness: i(1)})
+	if r.Components != 2 || r.RestingHR != nil || r.Score != 100 {
+		t.Errorf("unexpected readiness %+v", r)
+	}
+}
@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"sort"
+
+	"runner/internal/store"
+)
+
+const (
+	// recoveryWindow is how long after an effort HR recovery is measured
+	recoveryWindow = 60 // seconds
+
+	// recoveryTolerance allows for gaps in smart-recorded streams
+	recoveryTolerance = 10 // seconds
+
+	// An effort has ended when speed over the recovery window falls below
+	// effortEndSpeedRatio of the speed over the preceding window
+	effortEndSpeedRatio = 0.75
+)
+
+// HeartRateRecovery returns the largest 60-second HR drop (bpm) after a hard
+// effort ends - an interval, a surge, or the switch to a cooldown. An effort
+// is hard when HR reaches 95% of threshold HR (85% of max HR without one).
+// Returns 0 if the run has no hard effort followed by a slowdown.
+func HeartRateRecovery(streams []store.StreamPoint, zones HRZones) float64 {
+	hard := zones.MaxHR * 0.85
+	if zones.ThresholdHR > 0 {
+		hard = zones.ThresholdHR * 0.95
+	}
+	if hard <= 0 || len(streams) < 2*recoveryWindow {
+		return 0
+	}
+
+	// Prefix sums of speed so window averages are O(1)
+	speedSum := make([]float64, len(streams)+1)
+	speedCount := make([]int, len(streams)+1)
+	for i, p := range streams {
+		speedSum[i+1] = speedSum[i]
+		speedCount[i+1] = speedCount[i]
+		if p.VelocitySmooth != nil {
+			speedSum[i+1] += *p.VelocitySmooth
+			speedCount[i+1]++
+		}
+	}
+	avgSpeed := func(from, to int) float64 { // samples [from, to)
+		n := speedCount[to] - speedCount[from]
+		if n == 0 {
+			return 0
+		}
+		return (speedSum[to] - speedSum[from]) / float64(n)
+	}
+	indexAt := func(t int) int { // first sample at or after time t
+		return sort.Search(len(streams), func(i int) bool { return streams[i].TimeOffset >= t })
+	}
+
+	best := 0.0
+	for i, p := range streams {
+		if p.Heartrate == nil || float64(*p.Heartrate) < hard {
+			continue
+		}
+
+		// Need a full window of effort before and a sample ~60s after
+		if p.TimeOffset-recoveryWindow < streams[0].TimeOffset {
+			continue
+		}
+		before := indexAt(p.TimeOffset - recoveryWindow)
+		after := indexAt(p.TimeOffset + recoveryWindow)
+		if after >= len(streams) || streams[after].TimeOffset-p.TimeOffset > recoveryWindow+recoveryTolerance {
+			continue
+		}
+
+		effort := avgSpeed(before, i+1)
+		recovery := avgSpeed(i+1, after+1)
+		if effort <= 0 || recovery >= effort*effortEndSpeedRatio {
+			continue
+		}
+
+		end := streams[after]
+		if end.Heartrate == nil || *end.Heartrate <= 0 {
+			continue
+		}
+		if drop := float64(*p.Heartrate - *end.Heartrate); drop > best {
+			best = drop
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"testing"
+
+	"runner/internal/store"
+)
+
+// intervalStreams builds a run of 1 Hz samples: easy running, a hard effort
+// ending at effortEnd, then a jog whose HR falls by dropPerSecond
+func intervalStreams(effortEnd int, recoverySpeed float64, dropPerSecond int) []store.StreamPoint {
+	streams := make([]store.StreamPoint, effortEnd+180)
+	for i := range streams {
+		speed, hr := 3.0, 140
+		switch {
+		case i >= effortEnd-120 && i <= effortEnd:
+			speed, hr = 4.5, 175
+		case i > effortEnd:
+			speed = recoverySpeed
+			hr = 175 - dropPerSecond*(i-effortEnd)
+			if hr < 110 {
+				hr = 110
+			}
+		}
+		streams[i] = store.StreamPoint{
+			TimeOffset:     i,
+			VelocitySmooth: floatPtr(speed),
+			Heartrate:      intPtr(hr),
+		}
+	}
+	return streams
+}
+
+func TestHeartRateRecovery(t *testing.T) {
+	zones := NewHRZones(50, 185, 165) // hard effort at >= 156.75 bpm
+
+	tests := []struct {
+		name    string
+		streams []store.StreamPoint
+		want    float64
+	}{
+		{"empty", nil, 0},
+		// HR falls 1 bpm/s for 60s after the effort
+		{"recovery jog after interval", intervalStreams(600, 2.0, 1), 60},
+		{"plateau at 110 bpm", intervalStreams(600, 2.0, 2), 65},
+		// Still running hard, so the effort never ended
+		{"no slowdown", intervalStreams(600, 4.5, 1), 0},
+		{"easy run", steadyStreams(900), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HeartRateRecovery(tt.streams, zones)
+			if got != tt.want {
+				t.Errorf("HeartRateRecovery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
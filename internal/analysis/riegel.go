@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultRiegelExponent is Riegel's fatigue exponent. Higher values predict
+// more slowing as the distance grows; well-trained endurance runners often
+// sit nearer 1.04-1.05.
+const DefaultRiegelExponent = 1.06
+
+// RiegelPredict extrapolates a race result to another distance with Riegel's
+// power law, T2 = T1 * (D2 / D1) ^ exponent. Returns 0 for invalid input.
+func RiegelPredict(sourceMeters float64, sourceSeconds int, targetMeters, exponent float64) int {
+	if sourceMeters <= 0 || sourceSeconds <= 0 || targetMeters <= 0 || exponent <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(sourceSeconds) * math.Pow(targetMeters/sourceMeters, exponent)))
+}
+
+// EnsembleEstimate combines the predictions of several models for one
+// distance. Low and High bound the spread between models, which serves as an
+// uncertainty band.
+type EnsembleEstimate struct {
+	MeanSeconds int
+	LowSeconds  int
+	HighSeconds int
+	Models      int // number of models that produced a prediction
+}
+
+// Spread returns the gap between the fastest and slowest model in seconds
+func (e EnsembleEstimate) Spread() int {
+	return e.HighSeconds - e.LowSeconds
+}
+
+// Ensemble averages the model predictions, ignoring models that produced
+// none (zero). Models is zero when no model produced a prediction.
+func Ensemble(predictions ...int) EnsembleEstimate {
+	var valid []int
+	for _, p := range predictions {
+		if p > 0 {
+			valid = append(valid, p)
+		}
+	}
+	if len(valid) == 0 {
+		return EnsembleEstimate{}
+	}
+	sort.Ints(valid)
+
+	var sum int
+	for _, p := range valid {
+		sum += p
+	}
+	return EnsembleEstimate{
+		MeanSeconds: int(math.Round(float64(sum) / float64(len(valid)))),
+		LowSeconds:  valid[0],
+		HighSeconds: valid[len(valid)-1],
+		Models:      len(valid),
+	}
+}
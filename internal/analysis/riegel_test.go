@@ -0,0 +1,42 @@
+package analysis
+
+import "testing"
+
+func TestRiegelPredict(t *testing.T) {
+	// 20:00 5K to 10K: 1200 * 2^1.06 = 2501s
+	got := RiegelPredict(Distance5K, 1200, Distance10K, DefaultRiegelExponent)
+	if got < 2499 || got > 2503 {
+		t.Errorf("RiegelPredict(5K in 20:00 -> 10K) = %d, want ~2501", got)
+	}
+
+	// An exponent of 1 is a constant pace
+	if got := RiegelPredict(Distance5K, 1200, Distance10K, 1); got != 2400 {
+		t.Errorf("RiegelPredict with exponent 1 = %d, want 2400", got)
+	}
+
+	// Predicting down to a shorter distance is faster
+	if got := RiegelPredict(Distance10K, 2500, Distance5K, DefaultRiegelExponent); got >= 1250 {
+		t.Errorf("expected 5K under 20:50 from a 41:40 10K, got %d", got)
+	}
+
+	if got := RiegelPredict(0, 1200, Distance10K, DefaultRiegelExponent); got != 0 {
+		t.Errorf("expected 0 for invalid input, got %d", got)
+	}
+}
+
+func TestEnsemble(t *testing.T) {
+	e := Ensemble(2400, 0, 2500, 2450)
+	if e.Models != 3 {
+		t.Errorf("Models = %d, want 3", e.Models)
+	}
+	if e.MeanSeconds != 2450 || e.LowSeconds != 2400 || e.HighSeconds != 2500 {
+		t.Errorf("unexpected ensemble %+v", e)
+	}
+	if e.Spread() != 100 {
+		t.Errorf("Spread() = %d, want 100", e.Spread())
+	}
+
+	if e := Ensemble(0, 0); e.Models != 0 || e.MeanSeconds != 0 {
+		t.Errorf("expected an empty ensemble, got %+v", e)
+	}
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"sort"
+
+	"runner/internal/store"
+)
+
+// smoothWindow is how many samples the speed median filter spans; a spike
+// shorter than half the window is removed entirely
+const smoothWindow = 5
+
+// SmoothStreams cleans GPS noise before metrics and records are computed.
+// Speed gets a median filter, and distance gained faster than anyone can run
+// (a teleport) is replaced with the distance the smoothed speed would cover,
+// or the last believable speed if that is implausible too. Distance is then
+// rebuilt so it still only goes up. The input, and so the stored streams,
+// are left unchanged.
+func SmoothStreams(streams []store.StreamPoint) []store.StreamPoint {
+	smoothed := make([]store.StreamPoint, len(streams))
+	copy(smoothed, streams)
+
+	for i := range smoothed {
+		if streams[i].VelocitySmooth == nil {
+			continue
+		}
+		v := medianVelocity(streams, i)
+		smoothed[i].VelocitySmooth = &v
+	}
+
+	var dist, lastSpeed float64
+	prev := -1 // last sample with distance
+	for i := range smoothed {
+		if streams[i].Distance == nil {
+			continue
+		}
+		if prev < 0 {
+			dist = *streams[i].Distance
+		} else {
+			delta := *streams[i].Distance - *streams[prev].Distance
+			dt := float64(streams[i].TimeOffset - streams[prev].TimeOffset)
+			switch {
+			case delta < 0:
+				delta = 0
+			case dt > 0 && delta/dt > maxPlausibleSpeed:
+				speed := lastSpeed
+				if v := smoothed[i].VelocitySmooth; v != nil && *v <= maxPlausibleSpeed {
+					speed = *v
+				}
+				delta = speed * dt
+			case dt > 0:
+				lastSpeed = delta / dt
+			}
+			dist += delta
+		}
+		d := dist
+		smoothed[i].Distance = &d
+		prev = i
+	}
+	return smoothed
+}
+
+// medianVelocity returns the median speed of the samples around i
+func medianVelocity(streams []store.StreamPoint, i int) float64 {
+	var window []float64
+	for j := max(0, i-smoothWindow/2); j <= min(len(streams)-1, i+smoothWindow/2); j++ {
+		if streams[j].VelocitySmooth != nil {
+			window = append(window, *streams[j].VelocitySmooth)
+		}
+	}
+	sort.Float64s(window)
+	return window[len(window)/2]
+}
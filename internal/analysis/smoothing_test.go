@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestSmoothStreams(t *testing.T) {
+	// A steady 3 m/s run where the GPS teleports 200 m at 10s and reports
+	// a two-sample speed spike at 20s
+	var streams []store.StreamPoint
+	dist := 0.0
+	for i := 0; i <= 40; i++ {
+		if i > 0 {
+			dist += 3
+		}
+		if i == 10 {
+			dist += 200
+		}
+		v := 3.0
+		if i == 20 || i == 21 {
+			v = 12
+		}
+		streams = append(streams, store.StreamPoint{TimeOffset: i, VelocitySmooth: floatPtr(v), Distance: floatPtr(dist), Heartrate: intPtr(150)})
+	}
+
+	smoothed := SmoothStreams(streams)
+	if math.Abs(*smoothed[40].Distance-120) > 1e-9 {
+		t.Errorf("distance = %v, want 120 without the teleport", *smoothed[40].Distance)
+	}
+	if *smoothed[20].VelocitySmooth != 3 || *smoothed[21].VelocitySmooth != 3 {
+		t.Errorf("spike velocity = %v, %v, want 3", *smoothed[20].VelocitySmooth, *smoothed[21].VelocitySmooth)
+	}
+	if *smoothed[5].Heartrate != 150 {
+		t.Errorf("heartrate changed: %v", *smoothed[5].Heartrate)
+	}
+	if *streams[40].Distance != 320 || *streams[20].VelocitySmooth != 12 {
+		t.Error("SmoothStreams() modified its input")
+	}
+}
+
+func TestSmoothStreams_Clean(t *testing.T) {
+	streams := []store.StreamPoint{
+		{TimeOffset: 0, VelocitySmooth: floatPtr(3), Distance: floatPtr(0)},
+		{TimeOffset: 1, VelocitySmooth: floatPtr(3.2), Distance: floatPtr(3.2)},
+		{TimeOffset: 2},
+		{TimeOffset: 3, VelocitySmooth: floatPtr(3.1), Distance: floatPtr(9.4)},
+	}
+
+	smoothed := SmoothStreams(streams)
+	for i, p := range smoothed {
+		if (p.Distance == nil) != (streams[i].Distance == nil) {
+			t.Fatalf("point %d distance presence changed", i)
+		}
+		if p.Distance != nil && math.Abs(*p.Distance-*streams[i].Distance) > 1e-9 {
+			t.Errorf("point %d distance = %v, want %v", i, *p.Distance, *streams[i].Distance)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import "runner/internal/store"
+
+// A stop is at least minStopSeconds below stopSpeed, such as waiting at a
+// crossing, or a gap between samples longer than maxRecordingGap, which is
+// the watch auto-pausing
+const (
+	stopSpeed       = 0.5 // m/s
+	minStopSeconds  = 10
+	maxRecordingGap = 10 // seconds
+)
+
+// Stop is a stretch of a run spent standing still or paused, in seconds
+// from the start
+type Stop struct {
+	Start int
+	End   int
+}
+
+// Duration returns the length of the stop in seconds
+func (s Stop) Duration() int {
+	return s.End - s.Start
+}
+
+// FindStops returns the stops in a run, in order
+func FindStops(streams []store.StreamPoint) []Stop {
+	var stops []Stop
+	add := func(s Stop) {
+		// A pause straight after standing still is one stop
+		if n := len(stops); n > 0 && stops[n-1].End >= s.Start {
+			stops[n-1].End = max(stops[n-1].End, s.End)
+			return
+		}
+		stops = append(stops, s)
+	}
+
+	still := -1 // index where the current slow stretch began
+	for i, p := range streams {
+		if i > 0 && p.TimeOffset-streams[i-1].TimeOffset > maxRecordingGap {
+			if still >= 0 && streams[i-1].TimeOffset-streams[still].TimeOffset >= minStopSeconds {
+				add(Stop{Start: streams[still].TimeOffset, End: streams[i-1].TimeOffset})
+			}
+			still = -1
+			add(Stop{Start: streams[i-1].TimeOffset, End: p.TimeOffset})
+		}
+
+		slow := p.VelocitySmooth != nil && *p.VelocitySmooth < stopSpeed
+		switch {
+		case slow && still < 0:
+			still = i
+		case !slow && still >= 0:
+			if p.TimeOffset-streams[still].TimeOffset >= minStopSeconds {
+				add(Stop{Start: streams[still].TimeOffset, End: p.TimeOffset})
+			}
+			still = -1
+		}
+	}
+	if still >= 0 {
+		if last := streams[len(streams)-1].TimeOffset; last-streams[still].TimeOffset >= minStopSeconds {
+			add(Stop{Start: streams[still].TimeOffset, End: last})
+		}
+	}
+	return stops
+}
+
+// StoppedSeconds returns the total time spent in stops
+func StoppedSeconds(stops []Stop) int {
+	var total int
+	for _, s := range stops {
+		total += s.Duration()
+	}
+	return total
+}
+
+// MovingStreams drops the points recorded during stops, so standing still
+// doesn't drag down speed or let HR recover in the middle of EF and
+// decoupling. The input is left unchanged.
+func MovingStreams(streams []store.StreamPoint) []store.StreamPoint {
+	stops := FindStops(streams)
+	if len(stops) == 0 {
+		return streams
+	}
+
+	moving := make([]store.StreamPoint, 0, len(streams))
+	next := 0
+	for _, p := range streams {
+		for next < len(stops) && p.TimeOffset > stops[next].End {
+			next++
+		}
+		// The first sample after a pause is moving again
+		if next < len(stops) && p.TimeOffset >= stops[next].Start && p.TimeOffset < stops[next].End {
+			continue
+		}
+		moving = append(moving, p)
+	}
+	return moving
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"runner/internal/store"
+)
+
+// stopStreams builds 1 Hz samples from (seconds, speed) stretches; a
+// negative speed is an auto-pause gap of that many seconds
+func stopStreams(stretches ...[2]float64) []store.StreamPoint {
+	var streams []store.StreamPoint
+	t := 0
+	for _, s := range stretches {
+		if s[1] < 0 {
+			t += int(s[0])
+			continue
+		}
+		for i := 0; i < int(s[0]); i++ {
+			v := s[1]
+			hr := 150
+			if v < stopSpeed {
+				hr = 110
+			}
+			streams = append(streams, store.StreamPoint{TimeOffset: t, VelocitySmooth: &v, Heartrate: &hr})
+			t++
+		}
+	}
+	return streams
+}
+
+func TestFindStops(t *testing.T) {
+	tests := []struct {
+		name    string
+		streams []store.StreamPoint
+		want    []Stop
+	}{
+		{"no stops", stopStreams([2]float64{60, 3}), nil},
+		{"short slowdown", stopStreams([2]float64{60, 3}, [2]float64{5, 0}, [2]float64{60, 3}), nil},
+		{"standing still", stopStreams([2]float64{60, 3}, [2]float64{30, 0}, [2]float64{60, 3}), []Stop{{60, 90}}},
+		{"auto-pause", stopStreams([2]float64{60, 3}, [2]float64{120, -1}, [2]float64{60, 3}), []Stop{{59, 180}}},
+		{"standing then paused", stopStreams([2]float64{60, 3}, [2]float64{20, 0}, [2]float64{100, -1}, [2]float64{60, 3}), []Stop{{60, 180}}},
+		{"stopped at the end", stopStreams([2]float64{60, 3}, [2]float64{30, 0.2}), []Stop{{60, 89}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindStops(tt.streams); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindStops() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	stops := []Stop{{60, 90}, {200, 260}}
+	if got := StoppedSeconds(stops); got != 90 {
+		t.Errorf("StoppedSeconds() = %d, want 90", got)
+	}
+}
+
+func TestMovingStreams(t *testing.T) {
+	streams := stopStreams([2]float64{60, 3}, [2]float64{30, 0}, [2]float64{60, 3})
+	moving := MovingStreams(streams)
+	if len(moving) != 120 {
+		t.Fatalf("len(MovingStreams()) = %d, want 120", len(moving))
+	}
+	for _, p := range moving {
+		if *p.VelocitySmooth < stopSpeed {
+			t.Fatalf("MovingStreams() kept a stopped point at %d s", p.TimeOffset)
+		}
+	}
+
+	// A stop in the middle leaves EF as if the runner never stopped
+	steady := stopStreams([2]float64{120, 3})
+	got := EfficiencyFactor(MovingStreams(streams))
+	if want := EfficiencyFactor(steady); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EF with a stop = %v, want %v", got, want)
+	}
+}
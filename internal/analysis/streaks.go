@@ -0,0 +1,66 @@
+package analysis
+
+import "time"
+
+// Streaks summarizes runs on consecutive days
+type Streaks struct {
+	// Current counts consecutive days with a run up to today. A day without
+	// a run yet today doesn't break it until the day is over.
+	Current int
+
+	// Longest is the longest streak ever
+	Longest int
+
+	// DaysSinceRest counts days since the last day without a run before
+	// today; 1 when yesterday was a rest day
+	DaysSinceRest int
+}
+
+// CalculateStreaks works out run streaks from the calendar days that have a
+// run, given as midnight UTC in any order. today is the current calendar
+// day, also as midnight UTC.
+func CalculateStreaks(runDays []time.Time, today time.Time) Streaks {
+	if len(runDays) == 0 {
+		return Streaks{}
+	}
+
+	ran := make(map[int64]bool, len(runDays))
+	for _, d := range runDays {
+		ran[dayNumber(d)] = true
+	}
+	t := dayNumber(today)
+
+	var s Streaks
+	day := t
+	if !ran[day] {
+		day--
+	}
+	for ran[day] {
+		s.Current++
+		day--
+	}
+
+	rest := t - 1
+	for ran[rest] {
+		rest--
+	}
+	s.DaysSinceRest = int(t - rest)
+
+	for d := range ran {
+		// Count each streak once, from its first day
+		if ran[d-1] {
+			continue
+		}
+		n := int64(1)
+		for ran[d+n] {
+			n++
+		}
+		s.Longest = max(s.Longest, int(n))
+	}
+	return s
+}
+
+// dayNumber counts days since the Unix epoch for a midnight UTC time
+func dayNumber(t time.Time) int64 {
+	return t.Unix() / (24 * 60 * 60)
+}
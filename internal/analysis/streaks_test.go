@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateStreaks(t *testing.T) {
+	today := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	days := func(offsets ...int) []time.Time {
+		var out []time.Time
+		for _, o := range offsets {
+			out = append(out, today.AddDate(0, 0, -o))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		runDays []time.Time
+		want    Streaks
+	}{
+		{"no runs", nil, Streaks{}},
+		{
+			name:    "ran today after a rest day",
+			runDays: days(0, 2, 3, 4),
+			want:    Streaks{Current: 1, Longest: 3, DaysSinceRest: 1},
+		},
+		{
+			name:    "not run yet today",
+			runDays: days(1, 2, 3, 5),
+			want:    Streaks{Current: 3, Longest: 3, DaysSinceRest: 4},
+		},
+		{
+			name:    "streak broken yesterday",
+			runDays: days(2, 3, 4, 5, 6, 10),
+			want:    Streaks{Current: 0, Longest: 5, DaysSinceRest: 1},
+		},
+		{
+			name:    "every day for two weeks",
+			runDays: days(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13),
+			want:    Streaks{Current: 14, Longest: 14, DaysSinceRest: 14},
+		},
+		{
+			name:    "longest streak in the past",
+			runDays: days(1, 2, 5, 6, 7, 8),
+			want:    Streaks{Current: 2, Longest: 4, DaysSinceRest: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateStreaks(tt.runDays, today)
+			if got != tt.want {
+				t.Errorf("CalculateStreaks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,24 @@ type HRZones struct {
 	RestingHR   float64
 	MaxHR       float64
 	ThresholdHR float64
+
+	// Banister is the TRIMP weighting coefficient; zero uses BanisterMale
+	Banister float64
+
+	// PaceAnchors are the heart rates pace is measured at, at most three;
+	// empty uses the heart rate reserve defaults (see PaceAnchorHRs)
+	PaceAnchors []float64
+}
+
+// PaceAnchorHRs returns the heart rates ComputeActivityMetrics stores pace at,
+// in the order of PaceAtZ1, PaceAtZ2 and PaceAtZ3: the configured anchors, or
+// 60, 70 and 80% of heart rate reserve
+func (z HRZones) PaceAnchorHRs() []float64 {
+	if len(z.PaceAnchors) > 0 {
+		return z.PaceAnchors[:min(len(z.PaceAnchors), 3)]
+	}
+	reserve := z.MaxHR - z.RestingHR
+	return []float64{z.RestingHR + reserve*0.6, z.RestingHR + reserve*0.7, z.RestingHR + reserve*0.8}
 }
 
 // NewHRZones creates an HRZones with the given values
@@ -33,27 +51,29 @@ func DefaultZones() HRZones {
 	}
 }
 
-// TRIMP calculates Training Impulse (Banister model)
-// TRIMP = duration (min) * ΔHR ratio * e^(b * ΔHR ratio)
-// where b = 1.92 for men, 1.67 for women (using male default)
-func TRIMP(activity store.Activity, streams []store.StreamPoint, zones HRZones) float64 {
-	duration := float64(activity.MovingTime) / 60.0 // Convert to minutes
+// Banister's TRIMP coefficients, which weight time near max HR more for men
+const (
+	BanisterMale   = 1.92
+	BanisterFemale = 1.67
+)
 
-	avgHR := averageHR(streams)
-	if avgHR == 0 && activity.AverageHeartrate != nil {
-		avgHR = *activity.AverageHeartrate
-	}
-	if avgHR == 0 {
-		return 0
+// banister returns the TRIMP coefficient, defaulting to BanisterMale
+func (z HRZones) banister() float64 {
+	if z.Banister > 0 {
+		return z.Banister
 	}
+	return BanisterMale
+}
 
+// trimpPerMinute is the TRIMP one minute at hr adds
+func (z HRZones) trimpPerMinute(hr float64) float64 {
 	// Heart rate reserve ratio
-	hrReserve := zones.MaxHR - zones.RestingHR
+	hrReserve := z.MaxHR - z.RestingHR
 	if hrReserve <= 0 {
 		return 0
 	}
 
-	hrRatio := (avgHR - zones.RestingHR) / hrReserve
+	hrRatio := (hr - z.RestingHR) / hrReserve
 	if hrRatio < 0 {
 		hrRatio = 0
 	}
@@ -61,22 +81,46 @@ func TRIMP(activity store.Activity, streams []store.StreamPoint, zones HRZones)
 		hrRatio = 1
 	}
 
-	// Gender coefficient (using male default)
-	b := 1.92
+	return hrRatio * math.Exp(z.banister()*hrRatio)
+}
+
+// TRIMP calculates Training Impulse (Banister model)
+// TRIMP = duration (min) * ΔHR ratio * e^(b * ΔHR ratio)
+// where b = 1.92 for men, 1.67 for women (zones.Banister)
+func TRIMP(activity store.Activity, streams []store.StreamPoint, zones HRZones) float64 {
+	duration := float64(activity.MovingTime) / 60.0 // Convert to minutes
+
+	avgHR := averageHR(streams)
+	if avgHR == 0 && activity.AverageHeartrate != nil {
+		avgHR = *activity.AverageHeartrate
+	}
+	if avgHR == 0 {
+		return 0
+	}
 
-	return duration * hrRatio * math.Exp(b*hrRatio)
+	return duration * zones.trimpPerMinute(avgHR)
 }
 
-// HRSS calculates Heart Rate Stress Score
-// Normalized to ~100 for a 1-hour threshold effort
+// HRSS calculates Heart Rate Stress Score: TRIMP scaled so that an hour at
+// threshold HR scores 100, which makes loads comparable between athletes
 func HRSS(activity store.Activity, streams []store.StreamPoint, zones HRZones) float64 {
-	trimp := TRIMP(activity, streams, zones)
+	return TRIMP(activity, streams, zones) * HRSSPerTRIMP(zones)
+}
 
-	// Threshold TRIMP for 1 hour at lactate threshold (~88% max HR)
-	// Approximately 100 TRIMP for 1 hour at threshold
-	thresholdTRIMP := 100.0
+// HRSSPerTRIMP is the HRSS each point of TRIMP is worth with zones, or 0
+// when they have no threshold to scale by
+func HRSSPerTRIMP(zones HRZones) float64 {
+	thresholdTRIMP := 60 * zones.trimpPerMinute(zones.ThresholdHR)
+	if thresholdTRIMP <= 0 {
+		return 0
+	}
+	return 100 / thresholdTRIMP
+}
 
-	return (trimp / thresholdTRIMP) * 100
+// SessionRPE is Foster's session load: the perceived effort of a run (RPE,
+// 1-10) times its duration in minutes
+func SessionRPE(rpe, movingTime int) float64 {
+	return float64(rpe) * float64(movingTime) / 60
 }
 
 // DailyLoad represents training load for a single day
@@ -85,12 +129,51 @@ type DailyLoad struct {
 	TRIMP float64
 }
 
-// FitnessMetrics represents CTL/ATL/TSB for a day
+// FitnessMetrics represents CTL/ATL/TSB for a day, with the monotony and
+// strain of the 7 days ending on it
 type FitnessMetrics struct {
-	Date time.Time
-	CTL  float64 // Chronic Training Load (42-day EMA) - "Fitness"
-	ATL  float64 // Acute Training Load (7-day EMA) - "Fatigue"
-	TSB  float64 // Training Stress Balance (CTL - ATL) - "Form"
+	Date     time.Time
+	CTL      float64 // Chronic Training Load (42-day EMA) - "Fitness"
+	ATL      float64 // Acute Training Load (7-day EMA) - "Fatigue"
+	TSB      float64 // Training Stress Balance (CTL - ATL) - "Form"
+	Monotony float64 // Foster's monotony; 0 until there's a week of history
+	Strain   float64 // Weekly load x monotony
+}
+
+const (
+	// MonotonyDays is the rolling window monotony and strain cover
+	MonotonyDays = 7
+
+	// MonotonyWarning is the monotony above which training is too samey:
+	// Foster linked weeks above 2.0 with illness and overtraining
+	MonotonyWarning = 2.0
+)
+
+// Monotony returns Foster's training monotony and strain for a run of daily
+// loads. Monotony is the mean daily load over its standard deviation, so it
+// rises when hard and easy days blur together; strain is the total load
+// multiplied by monotony. ok is false when every day carries the same load,
+// where monotony is undefined.
+func Monotony(loads []float64) (monotony, strain float64, ok bool) {
+	if len(loads) < 2 {
+		return 0, 0, false
+	}
+	var total float64
+	for _, l := range loads {
+		total += l
+	}
+	mean := total / float64(len(loads))
+
+	var sq float64
+	for _, l := range loads {
+		sq += (l - mean) * (l - mean)
+	}
+	sd := math.Sqrt(sq / float64(len(loads)-1))
+	if sd == 0 {
+		return 0, 0, false
+	}
+	monotony = mean / sd
+	return monotony, total * monotony, true
 }
 
 // CalculateFitnessTrend computes CTL/ATL/TSB from daily loads
@@ -110,6 +193,7 @@ func CalculateFitnessTrend(dailyLoads []DailyLoad) []FitnessMetrics {
 
 	var metrics []FitnessMetrics
 	var ctl, atl float64
+	var window []float64 // the last MonotonyDays of load
 
 	// Fill in missing days with zero load
 	startDate := dailyLoads[0].Date.Truncate(24 * time.Hour)
@@ -131,11 +215,22 @@ func CalculateFitnessTrend(dailyLoads []DailyLoad) []FitnessMetrics {
 		atl = atl + atlDecay*(trimp-atl)
 		tsb := ctl - atl
 
+		window = append(window, trimp)
+		if len(window) > MonotonyDays {
+			window = window[1:]
+		}
+		var monotony, strain float64
+		if len(window) == MonotonyDays {
+			monotony, strain, _ = Monotony(window)
+		}
+
 		metrics = append(metrics, FitnessMetrics{
-			Date: d,
-			CTL:  ctl,
-			ATL:  atl,
-			TSB:  tsb,
+			Date:     d,
+			CTL:      ctl,
+			ATL:      atl,
+			TSB:      tsb,
+			Monotony: monotony,
+			Strain:   strain,
 		})
 	}
 
@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"math"
+	"slices"
 	"testing"
 	"time"
 
@@ -45,6 +46,18 @@ func TestTRIMP(t *testing.T) {
 			expected: 184.3,
 			delta:    1,
 		},
+		{
+			name: "female coefficient",
+			activity: store.Activity{
+				MovingTime:       3600,
+				AverageHeartrate: floatPtr(150),
+			},
+			streams: []store.StreamPoint{},
+			zones:   HRZones{RestingHR: 50, MaxHR: 185, ThresholdHR: 165, Banister: BanisterFemale},
+			// TRIMP = 60 * 0.741 * e^(1.67*0.741)
+			expected: 153.3,
+			delta:    1,
+		},
 		{
 			name: "no HR data available",
 			activity: store.Activity{
@@ -179,10 +192,9 @@ func TestHRSS(t *testing.T) {
 			},
 			streams: []store.StreamPoint{},
 			zones:   defaultZones,
-			// HRSS = (TRIMP / 100) * 100 = TRIMP
-			// At 163 HR, TRIMP is ~250
-			expected: 250,
-			delta:    10,
+			// TRIMP is ~250 at 163 HR and ~262 at the 165 threshold
+			expected: 95,
+			delta:    2,
 		},
 		{
 			name: "easy effort = low HRSS",
@@ -192,8 +204,8 @@ func TestHRSS(t *testing.T) {
 			},
 			streams:  []store.StreamPoint{},
 			zones:    defaultZones,
-			expected: 111, // TRIMP at 130 HR for 1 hour
-			delta:    10,
+			expected: 42, // TRIMP of 111 at 130 HR for 1 hour
+			delta:    2,
 		},
 		{
 			name: "hard effort = high HRSS",
@@ -203,8 +215,19 @@ func TestHRSS(t *testing.T) {
 			},
 			streams:  []store.StreamPoint{},
 			zones:    defaultZones,
-			expected: 329, // TRIMP at 175 HR for 1 hour
-			delta:    10,
+			expected: 125, // TRIMP of 329 at 175 HR for 1 hour
+			delta:    2,
+		},
+		{
+			name: "an hour at threshold is 100 whatever the coefficient",
+			activity: store.Activity{
+				MovingTime:       3600,
+				AverageHeartrate: floatPtr(165),
+			},
+			streams:  []store.StreamPoint{},
+			zones:    HRZones{RestingHR: 50, MaxHR: 185, ThresholdHR: 165, Banister: BanisterFemale},
+			expected: 100,
+			delta:    0.001,
 		},
 		{
 			name: "no HR data",
@@ -410,6 +433,63 @@ func TestGetCurrentFitness(t *testing.T) {
 	}
 }
 
+func TestMonotony(t *testing.T) {
+	tests := []struct {
+		name         string
+		loads        []float64
+		wantMonotony float64
+		wantStrain   float64
+		wantOK       bool
+	}{
+		{"hard and rest days alternate", []float64{100, 0, 100, 0, 100, 0, 100}, 1.069, 427.6, true},
+		{"the same run every day", []float64{60, 60, 60, 60, 60, 50, 60}, 15.497, 6353.6, true},
+		{"identical days", []float64{50, 50, 50, 50, 50, 50, 50}, 0, 0, false},
+		{"too short", []float64{50}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monotony, strain, ok := Monotony(tt.loads)
+			if ok != tt.wantOK {
+				t.Fatalf("Monotony() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(monotony-tt.wantMonotony) > 0.001 {
+				t.Errorf("monotony = %.3f, want %.3f", monotony, tt.wantMonotony)
+			}
+			if math.Abs(strain-tt.wantStrain) > 0.1 {
+				t.Errorf("strain = %.1f, want %.1f", strain, tt.wantStrain)
+			}
+		})
+	}
+}
+
+func TestCalculateFitnessTrend_Monotony(t *testing.T) {
+	baseDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Runs every other day for two weeks; rest days count as zero load
+	var loads []DailyLoad
+	for i := 0; i < 14; i += 2 {
+		loads = append(loads, DailyLoad{Date: baseDate.AddDate(0, 0, i), TRIMP: 100})
+	}
+	metrics := CalculateFitnessTrend(loads)
+	if len(metrics) != 13 {
+		t.Fatalf("expected 13 days, got %d", len(metrics))
+	}
+
+	// The first six days don't make a full week yet
+	for _, m := range metrics[:MonotonyDays-1] {
+		if m.Monotony != 0 || m.Strain != 0 {
+			t.Errorf("%s: monotony %.2f before a full week", m.Date.Format("Jan 02"), m.Monotony)
+		}
+	}
+	// Day 7 ends a week of four runs and three rest days
+	if got := metrics[6].Monotony; math.Abs(got-1.069) > 0.001 {
+		t.Errorf("week 1 monotony = %.3f, want 1.069", got)
+	}
+	if got := metrics[6].Strain; math.Abs(got-427.6) > 0.1 {
+		t.Errorf("week 1 strain = %.1f, want 427.6", got)
+	}
+}
+
 func TestFormDescription(t *testing.T) {
 	tests := []struct {
 		tsb      float64
@@ -443,3 +523,21 @@ func TestFormDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestPaceAnchorHRs(t *testing.T) {
+	zones := NewHRZones(50, 185, 165)
+	if got, want := zones.PaceAnchorHRs(), []float64{131, 144.5, 158}; !slices.Equal(got, want) {
+		t.Errorf("default anchors = %v, want %v", got, want)
+	}
+
+	zones.PaceAnchors = []float64{140, 165}
+	if got := zones.PaceAnchorHRs(); !slices.Equal(got, zones.PaceAnchors) {
+		t.Errorf("configured anchors = %v, want %v", got, zones.PaceAnchors)
+	}
+}
+
+func TestSessionRPE(t *testing.T) {
+	if got := SessionRPE(6, 2700); got != 270 {
+		t.Errorf("SessionRPE(6, 45 min) = %v, want 270", got)
+	}
+}
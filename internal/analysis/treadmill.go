@@ -0,0 +1,44 @@
+package analysis
+
+import "runner/internal/store"
+
+// ScaleDistance multiplies a run's speed and distance streams by factor, for
+// a treadmill or footpod that reads consistently long or short. The input is
+// left unchanged.
+func ScaleDistance(streams []store.StreamPoint, factor float64) []store.StreamPoint {
+	scaled := make([]store.StreamPoint, len(streams))
+	for i, p := range streams {
+		if p.VelocitySmooth != nil {
+			v := *p.VelocitySmooth * factor
+			p.VelocitySmooth = &v
+		}
+		if p.Distance != nil {
+			d := *p.Distance * factor
+			p.Distance = &d
+		}
+		scaled[i] = p
+	}
+	return scaled
+}
+
+// CadenceDistance rebuilds a run's speed and distance streams from cadence
+// and a stride length (meters per step), for indoor runs whose wrist-based
+// distance can't be trusted. Samples without cadence count as stopped. The
+// input is left unchanged.
+func CadenceDistance(streams []store.StreamPoint, stride float64) []store.StreamPoint {
+	rebuilt := make([]store.StreamPoint, len(streams))
+	var dist float64
+	for i, p := range streams {
+		v := 0.0
+		if p.Cadence != nil && *p.Cadence > 0 {
+			v = float64(*p.Cadence*stepsPerStravaCadence) / 60 * stride
+		}
+		if i > 0 {
+			dist += v * float64(p.TimeOffset-streams[i-1].TimeOffset)
+		}
+		d := dist
+		p.VelocitySmooth, p.Distance = &v, &d
+		rebuilt[i] = p
+	}
+	return rebuilt
+}
@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestScaleDistance(t *testing.T) {
+	streams := []store.StreamPoint{
+		{TimeOffset: 0, VelocitySmooth: floatPtr(3), Distance: floatPtr(0)},
+		{TimeOffset: 1, VelocitySmooth: floatPtr(3), Distance: floatPtr(3), Heartrate: intPtr(150)},
+		{TimeOffset: 2},
+	}
+
+	scaled := ScaleDistance(streams, 1.1)
+	if math.Abs(*scaled[1].VelocitySmooth-3.3) > 1e-9 || math.Abs(*scaled[1].Distance-3.3) > 1e-9 {
+		t.Errorf("scaled point = %v m/s, %v m, want 3.3, 3.3", *scaled[1].VelocitySmooth, *scaled[1].Distance)
+	}
+	if *scaled[1].Heartrate != 150 || scaled[2].VelocitySmooth != nil {
+		t.Errorf("other streams changed: %+v", scaled)
+	}
+	if *streams[1].VelocitySmooth != 3 {
+		t.Error("ScaleDistance() modified its input")
+	}
+}
+
+func TestCadenceDistance(t *testing.T) {
+	// 90 single-leg steps a minute is 3 steps a second; at 1.1 m a step that
+	// is 3.3 m/s. The last sample has no cadence.
+	var streams []store.StreamPoint
+	for i := 0; i < 11; i++ {
+		streams = append(streams, store.StreamPoint{TimeOffset: i, Cadence: intPtr(90), VelocitySmooth: floatPtr(2)})
+	}
+	streams = append(streams, store.StreamPoint{TimeOffset: 11})
+
+	rebuilt := CadenceDistance(streams, 1.1)
+	if math.Abs(*rebuilt[5].VelocitySmooth-3.3) > 1e-9 {
+		t.Errorf("velocity = %v, want 3.3", *rebuilt[5].VelocitySmooth)
+	}
+	if math.Abs(*rebuilt[10].Distance-33) > 1e-9 || math.Abs(*rebuilt[11].Distance-33) > 1e-9 {
+		t.Errorf("distance = %v then %v, want 33 both", *rebuilt[10].Distance, *rebuilt[11].Distance)
+	}
+	if *rebuilt[11].VelocitySmooth != 0 {
+		t.Errorf("velocity without cadence = %v, want 0", *rebuilt[11].VelocitySmooth)
+	}
+	if *streams[5].VelocitySmooth != 2 {
+		t.Error("CadenceDistance() modified its input")
+	}
+}
@@ -0,0 +1,170 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+)
+
+// MinTrendPoints is the fewest points a trend is fitted to
+const MinTrendPoints = 4
+
+// trendBandZ scales the prediction interval to roughly 95%
+const trendBandZ = 1.96
+
+// LinearTrend is a least-squares line through a series, with what's needed
+// to put a prediction interval around it
+type LinearTrend struct {
+	Slope     float64
+	Intercept float64
+
+	n          int
+	meanX      float64
+	sxx        float64 // sum of squared x deviations
+	residualSE float64 // standard error of the residuals
+}
+
+// FitLinearTrend fits y = Slope*x + Intercept by least squares. ok is false
+// with fewer than MinTrendPoints points or when every x is the same.
+func FitLinearTrend(xs, ys []float64) (trend LinearTrend, ok bool) {
+	n := len(xs)
+	if n != len(ys) || n < MinTrendPoints {
+		return LinearTrend{}, false
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/float64(n), sumY/float64(n)
+
+	var sxx, sxy float64
+	for i := range xs {
+		dx := xs[i] - meanX
+		sxx += dx * dx
+		sxy += dx * (ys[i] - meanY)
+	}
+	if sxx == 0 {
+		return LinearTrend{}, false
+	}
+
+	trend = LinearTrend{
+		Slope: sxy / sxx,
+		n:     n,
+		meanX: meanX,
+		sxx:   sxx,
+	}
+	trend.Intercept = meanY - trend.Slope*meanX
+
+	var sse float64
+	for i := range xs {
+		r := ys[i] - trend.At(xs[i])
+		sse += r * r
+	}
+	trend.residualSE = math.Sqrt(sse / float64(n-2))
+	return trend, true
+}
+
+// At returns the trend's value at x
+func (t LinearTrend) At(x float64) float64 {
+	return t.Slope*x + t.Intercept
+}
+
+// Band returns the half-width of the ~95% prediction interval at x. It widens
+// the further x lies from the fitted data.
+func (t LinearTrend) Band(x float64) float64 {
+	if t.n == 0 {
+		return 0
+	}
+	dx := x - t.meanX
+	return trendBandZ * t.residualSE * math.Sqrt(1+1/float64(t.n)+dx*dx/t.sxx)
+}
+
+// Correlation returns Pearson's r between two series, from -1 to 1. ok is
+// false with fewer than MinTrendPoints points or when either series is flat.
+func Correlation(xs, ys []float64) (r float64, ok bool) {
+	n := len(xs)
+	if n != len(ys) || n < MinTrendPoints {
+		return 0, false
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/float64(n), sumY/float64(n)
+
+	var sxx, syy, sxy float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sxx += dx * dx
+		syy += dy * dy
+		sxy += dx * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0, false
+	}
+	return sxy / math.Sqrt(sxx*syy), true
+}
+
+// Loess smooths a series with locally weighted linear regression. Each
+// point is refitted from the span fraction of points nearest to it, weighted
+// by a tricube kernel. xs must be sorted ascending.
+func Loess(xs, ys []float64, span float64) []float64 {
+	n := len(xs)
+	smoothed := make([]float64, n)
+	if n < 3 || n != len(ys) {
+		copy(smoothed, ys)
+		return smoothed
+	}
+
+	k := int(math.Ceil(span * float64(n)))
+	if k < 3 {
+		k = 3
+	}
+	if k > n {
+		k = n
+	}
+
+	type neighbor struct {
+		i    int
+		dist float64
+	}
+	for i := range xs {
+		neighbors := make([]neighbor, n)
+		for j := range xs {
+			neighbors[j] = neighbor{j, math.Abs(xs[j] - xs[i])}
+		}
+		sort.Slice(neighbors, func(a, b int) bool { return neighbors[a].dist < neighbors[b].dist })
+		neighbors = neighbors[:k]
+		maxDist := neighbors[k-1].dist
+
+		var sw, swx, swy, swxx, swxy float64
+		for _, nb := range neighbors {
+			w := 1.0
+			if maxDist > 0 {
+				u := nb.dist / (maxDist * 1.0001) // keep the farthest point's weight above zero
+				w = math.Pow(1-u*u*u, 3)
+			}
+			x, y := xs[nb.i], ys[nb.i]
+			sw += w
+			swx += w * x
+			swy += w * y
+			swxx += w * x * x
+			swxy += w * x * y
+		}
+
+		denom := sw*swxx - swx*swx
+		if sw == 0 {
+			smoothed[i] = ys[i]
+		} else if math.Abs(denom) < 1e-12 {
+			smoothed[i] = swy / sw
+		} else {
+			slope := (sw*swxy - swx*swy) / denom
+			intercept := (swy - slope*swx) / sw
+			smoothed[i] = slope*xs[i] + intercept
+		}
+	}
+	return smoothed
+}
@@ -0,0 +1,90 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitLinearTrend(t *testing.T) {
+	xs := []float64{0, 1, 2, 3, 4, 5}
+	ys := []float64{1.0, 1.1, 1.2, 1.3, 1.4, 1.5}
+
+	trend, ok := FitLinearTrend(xs, ys)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	if math.Abs(trend.Slope-0.1) > 1e-9 || math.Abs(trend.Intercept-1.0) > 1e-9 {
+		t.Errorf("got slope %.3f intercept %.3f, want 0.1 and 1.0", trend.Slope, trend.Intercept)
+	}
+	if math.Abs(trend.At(10)-2.0) > 1e-9 {
+		t.Errorf("At(10) = %.3f, want 2.0", trend.At(10))
+	}
+	// A perfect fit has no spread
+	if trend.Band(10) > 1e-6 {
+		t.Errorf("Band(10) = %v, want 0", trend.Band(10))
+	}
+
+	if _, ok := FitLinearTrend(xs[:3], ys[:3]); ok {
+		t.Error("expected no fit from 3 points")
+	}
+}
+
+func TestLinearTrend_BandWidens(t *testing.T) {
+	xs := []float64{0, 1, 2, 3, 4, 5, 6, 7}
+	ys := []float64{1.0, 1.2, 1.1, 1.3, 1.2, 1.4, 1.3, 1.5}
+
+	trend, ok := FitLinearTrend(xs, ys)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	near, far := trend.Band(8), trend.Band(20)
+	if near <= 0 || far <= near {
+		t.Errorf("expected the band to widen with distance, got %.3f then %.3f", near, far)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name   string
+		ys     []float64
+		want   float64
+		wantOK bool
+	}{
+		{"rising together", []float64{2, 4, 6, 8, 10}, 1, true},
+		{"opposite", []float64{5, 4, 3, 2, 1}, -1, true},
+		{"unrelated", []float64{1, 3, 2, 3, 1}, 0, true},
+		{"flat", []float64{3, 3, 3, 3, 3}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := Correlation(xs, tt.ys)
+			if ok != tt.wantOK || math.Abs(r-tt.want) > 1e-9 {
+				t.Errorf("Correlation() = %.3f, %v, want %.3f, %v", r, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := Correlation(xs[:3], xs[:3]); ok {
+		t.Error("expected no correlation from 3 points")
+	}
+}
+
+func TestLoess(t *testing.T) {
+	// A straight line is left alone
+	xs := []float64{0, 1, 2, 3, 4, 5, 6, 7}
+	line := []float64{0, 2, 4, 6, 8, 10, 12, 14}
+	for i, v := range Loess(xs, line, 0.5) {
+		if math.Abs(v-line[i]) > 1e-9 {
+			t.Errorf("Loess changed a line at %d: %.3f, want %.3f", i, v, line[i])
+		}
+	}
+
+	// A spike is pulled toward its neighbors
+	spiky := []float64{1, 1, 1, 5, 1, 1, 1, 1}
+	smoothed := Loess(xs, spiky, 0.6)
+	if smoothed[3] >= 5 || smoothed[3] <= 1 {
+		t.Errorf("expected the spike to be damped, got %.2f", smoothed[3])
+	}
+}
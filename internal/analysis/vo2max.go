@@ -0,0 +1,36 @@
+package analysis
+
+const (
+	// restingVO2 is one MET, the oxygen uptake at rest in ml/kg/min
+	restingVO2 = 3.5
+
+	// Runs whose average HR sits in this fraction of heart rate reserve are
+	// steady and submaximal enough for the HR-VO2 relationship to hold
+	VO2maxMinHRR = 0.5
+	VO2maxMaxHRR = 0.85
+)
+
+// RunningVO2 returns the oxygen cost of running on the flat at speed (m/s)
+// in ml/kg/min, from the ACSM running equation
+func RunningVO2(speed float64) float64 {
+	return restingVO2 + 0.2*speed*60
+}
+
+// EstimateVO2max estimates VO2max (ml/kg/min) from one submaximal run. The
+// oxygen cost of the run's pace is scaled up by how much of the athlete's
+// heart rate reserve it took, since %HRR tracks %VO2 reserve closely during
+// steady running (Swain & Leutholtz). This is the HR-vs-pace extrapolation
+// behind watch estimates such as Firstbeat's, anchored at rest.
+//
+// It returns false when the run's HR is outside VO2maxMinHRR-VO2maxMaxHRR of
+// reserve, where the estimate is unreliable.
+func EstimateVO2max(speed, avgHR, restingHR, maxHR float64) (float64, bool) {
+	if speed <= 0 || maxHR <= restingHR {
+		return 0, false
+	}
+	hrr := (avgHR - restingHR) / (maxHR - restingHR)
+	if hrr < VO2maxMinHRR || hrr > VO2maxMaxHRR {
+		return 0, false
+	}
+	return restingVO2 + (RunningVO2(speed)-restingVO2)/hrr, true
+}
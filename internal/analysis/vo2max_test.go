@@ -0,0 +1,44 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRunningVO2(t *testing.T) {
+	// 10 km/h is 166.7 m/min: 3.5 + 0.2*166.7 = 36.8 ml/kg/min
+	if got := RunningVO2(10000.0 / 3600); math.Abs(got-36.83) > 0.01 {
+		t.Errorf("RunningVO2(10 km/h) = %.2f, want 36.83", got)
+	}
+}
+
+func TestEstimateVO2max(t *testing.T) {
+	tests := []struct {
+		name      string
+		speed, hr float64
+		want      float64
+		wantOK    bool
+	}{
+		// 36.83 ml/kg/min at 70% of reserve: 3.5 + 33.33/0.7
+		{"easy run", 10000.0 / 3600, 50 + 0.7*140, 51.12, true},
+		{"faster at the same HR is fitter", 11000.0 / 3600, 50 + 0.7*140, 55.88, true},
+		{"too easy", 10000.0 / 3600, 110, 0, false},
+		{"too hard", 10000.0 / 3600, 180, 0, false},
+		{"stopped", 0, 150, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EstimateVO2max(tt.speed, tt.hr, 50, 190)
+			if ok != tt.wantOK {
+				t.Fatalf("EstimateVO2max() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("EstimateVO2max() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := EstimateVO2max(3, 150, 60, 60); ok {
+		t.Error("expected no estimate when max HR isn't above resting HR")
+	}
+}
@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"fmt"
+	"strings"
+
 	"golang.org/x/oauth2"
 )
 
@@ -10,9 +13,14 @@ const (
 	TokenURL = "https://www.strava.com/oauth/token"
 )
 
+// RequiredScopes are the scopes the app asks for. Without activity:read_all,
+// Strava leaves private activities out of every response, so they silently
+// never sync.
+var RequiredScopes = []string{"read", "activity:read_all"}
+
 // Scopes required for our app (Strava uses comma-separated scopes)
 var Scopes = []string{
-	"read,activity:read_all",
+	strings.Join(RequiredScopes, ","),
 }
 
 // Config holds the OAuth client credentials
@@ -40,6 +48,48 @@ func NewOAuthConfig(cfg Config) *oauth2.Config {
 type AuthResult struct {
 	Token     *oauth2.Token
 	AthleteID int64
+	Scopes    []string // as granted, since the user can untick some on Strava's page
+}
+
+// ParseScopes splits a comma-separated scope list as Strava sends it
+func ParseScopes(s string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(s, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// MissingScopes returns the required scopes not among granted. No granted
+// scopes at all means they aren't known, as for tokens saved before they were
+// recorded, and reports nothing missing.
+func MissingScopes(granted []string) []string {
+	if len(granted) == 0 {
+		return nil
+	}
+	have := make(map[string]bool, len(granted))
+	for _, scope := range granted {
+		have[scope] = true
+	}
+	var missing []string
+	for _, scope := range RequiredScopes {
+		if !have[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// ScopeWarning explains what a token missing scopes can't do and how to fix
+// it, or returns "" when nothing is missing
+func ScopeWarning(missing []string) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Strava didn't grant %s, so private activities won't sync. "+
+		"Run runner auth and leave every permission ticked on Strava's page.", strings.Join(missing, ", "))
 }
 
 // ExtractAthleteID extracts the athlete ID from the token extras
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		granted string
+		want    []string
+	}{
+		{"read,activity:read_all", nil},
+		{"read,activity:read_all,profile:read_all", nil},
+		{"read,activity:read", []string{"activity:read_all"}},
+		{"read", []string{"activity:read_all"}},
+		{"", nil}, // not recorded
+	}
+	for _, tt := range tests {
+		if got := MissingScopes(ParseScopes(tt.granted)); !slices.Equal(got, tt.want) {
+			t.Errorf("MissingScopes(%q) = %v, want %v", tt.granted, got, tt.want)
+		}
+	}
+}
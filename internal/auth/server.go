@@ -19,16 +19,23 @@ const (
 	AuthTimeout = 5 * time.Minute
 )
 
-// Authenticate runs the OAuth flow with a local callback server
+// Authenticate runs the OAuth flow with a local callback server, printing
+// the URL to open
 func Authenticate(ctx context.Context, cfg *oauth2.Config) (*AuthResult, error) {
+	return AuthenticateWith(ctx, cfg, printAuthURL)
+}
+
+// AuthenticateWith runs the OAuth flow with a local callback server, passing
+// the URL the user must open to showURL once the server is listening
+func AuthenticateWith(ctx context.Context, cfg *oauth2.Config, showURL func(authURL string)) (*AuthResult, error) {
 	// Generate state for CSRF protection
 	state, err := generateState()
 	if err != nil {
 		return nil, fmt.Errorf("generating state: %w", err)
 	}
 
-	// Channel to receive the auth code
-	codeChan := make(chan string, 1)
+	// Channel to receive the auth code and the scopes granted with it
+	codeChan := make(chan callback, 1)
 	errChan := make(chan error, 1)
 
 	// Create server mux (don't use DefaultServeMux)
@@ -64,7 +71,7 @@ func Authenticate(ctx context.Context, cfg *oauth2.Config) (*AuthResult, error)
 </div>
 </body>
 </html>`)
-		codeChan <- code
+		codeChan <- callback{code: code, scope: r.URL.Query().Get("scope")}
 	})
 
 	// Start local server
@@ -82,18 +89,12 @@ func Authenticate(ctx context.Context, cfg *oauth2.Config) (*AuthResult, error)
 	}()
 
 	// Generate auth URL and prompt user
-	authURL := cfg.AuthCodeURL(state, oauth2.AccessTypeOffline)
-	fmt.Println()
-	fmt.Println("To authenticate with Strava, open this URL in your browser:")
-	fmt.Println()
-	fmt.Printf("  %s\n", authURL)
-	fmt.Println()
-	fmt.Println("Waiting for authentication...")
+	showURL(cfg.AuthCodeURL(state, oauth2.AccessTypeOffline))
 
 	// Wait for callback with timeout
-	var code string
+	var cb callback
 	select {
-	case code = <-codeChan:
+	case cb = <-codeChan:
 		// Success
 	case err := <-errChan:
 		shutdownServer(server)
@@ -109,7 +110,7 @@ func Authenticate(ctx context.Context, cfg *oauth2.Config) (*AuthResult, error)
 	shutdownServer(server)
 
 	// Exchange code for token
-	token, err := cfg.Exchange(ctx, code)
+	token, err := cfg.Exchange(ctx, cb.code)
 	if err != nil {
 		return nil, fmt.Errorf("exchanging code for token: %w", err)
 	}
@@ -120,9 +121,26 @@ func Authenticate(ctx context.Context, cfg *oauth2.Config) (*AuthResult, error)
 	return &AuthResult{
 		Token:     token,
 		AthleteID: athleteID,
+		Scopes:    ParseScopes(cb.scope),
 	}, nil
 }
 
+// callback is what Strava redirects back with once the user approves
+type callback struct {
+	code  string
+	scope string
+}
+
+// printAuthURL asks the user to open the auth URL in a browser
+func printAuthURL(authURL string) {
+	fmt.Println()
+	fmt.Println("To authenticate with Strava, open this URL in your browser:")
+	fmt.Println()
+	fmt.Printf("  %s\n", authURL)
+	fmt.Println()
+	fmt.Println("Waiting for authentication...")
+}
+
 // generateState creates a random state string for CSRF protection
 func generateState() (string, error) {
 	b := make([]byte, 16)
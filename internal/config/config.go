@@ -4,21 +4,52 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"runner/internal/paths"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Strava  StravaConfig  `json:"strava"`
-	Athlete AthleteConfig `json:"athlete"`
-	Display DisplayConfig `json:"display"`
+	Strava   StravaConfig   `json:"strava"`
+	Athlete  AthleteConfig  `json:"athlete"`
+	Display  DisplayConfig  `json:"display"`
+	Analysis AnalysisConfig `json:"analysis"`
+	Storage  StorageConfig  `json:"storage"`
+	Logging  LoggingConfig  `json:"logging"`
+	Privacy  PrivacyConfig  `json:"privacy"`
+
+	Notifications NotificationsConfig `json:"notifications"`
+	Hooks         []Hook              `json:"hooks,omitempty"`
 }
 
-// StravaConfig holds Strava API credentials
+// StravaConfig holds Strava API credentials and how requests are retried
 type StravaConfig struct {
 	ClientID     string `json:"client_id"`
 	ClientSecret string `json:"client_secret"`
+
+	// MaxAttempts is how many times a request that fails with a network
+	// error, a 5xx or a 429 is tried, 1 to never retry. Zero uses 4.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// RetryDelaySeconds is the backoff before the first retry, doubled for
+	// each one after it. Zero uses 1 second.
+	RetryDelaySeconds float64 `json:"retry_delay_seconds,omitempty"`
+}
+
+// MaxStravaAttempts caps strava.max_attempts, so a dead connection fails a
+// sync in minutes rather than hours
+const MaxStravaAttempts = 10
+
+// RetryDelay returns the first retry's backoff, zero for the default
+func (c StravaConfig) RetryDelay() time.Duration {
+	return time.Duration(c.RetryDelaySeconds * float64(time.Second))
 }
 
 // AthleteConfig holds athlete-specific settings
@@ -26,14 +57,294 @@ type AthleteConfig struct {
 	RestingHR   float64 `json:"resting_hr"`
 	MaxHR       float64 `json:"max_hr"`
 	ThresholdHR float64 `json:"threshold_hr"`
+
+	// Sex picks the Banister TRIMP coefficient: "male" (the default, 1.92)
+	// or "female" (1.67)
+	Sex string `json:"sex,omitempty"`
+
+	// WeightKg enables the VO2max estimate, which it converts to L/min
+	WeightKg float64 `json:"weight_kg,omitempty"`
+
+	// WeeklyGoalKm draws a goal line on the weekly distance chart
+	WeeklyGoalKm float64 `json:"weekly_goal_km,omitempty"`
+
+	// PaceAnchors are up to MaxPaceAnchors heart rates (bpm) to track pace
+	// at, such as 140, 150 and the threshold HR. Empty uses 60, 70 and 80%
+	// of heart rate reserve.
+	PaceAnchors []float64 `json:"pace_anchors,omitempty"`
 }
 
+// Sexes for AthleteConfig.Sex
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+// MaxPaceAnchors is how many athlete.pace_anchors each run stores a pace for
+const MaxPaceAnchors = 3
+
 // DisplayConfig holds display preferences
 type DisplayConfig struct {
+	// Units is "metric" or "imperial" and picks both units below; either
+	// can still be set on its own to mix them
+	Units        string `json:"units,omitempty"`
 	DistanceUnit string `json:"distance_unit"`
 	PaceUnit     string `json:"pace_unit"`
+
+	// Theme is one of Themes; empty means dark. Colors overrides single
+	// theme colors by name (see ThemeColors) with "#RRGGBB" hex values.
+	Theme  string            `json:"theme,omitempty"`
+	Colors map[string]string `json:"colors,omitempty"`
+
+	// WeekNumbers labels weeks with their ISO week number (W03) instead
+	// of the date they start
+	WeekNumbers bool `json:"week_numbers"`
+}
+
+// Themes are the built-in color schemes for display.theme
+var Themes = []string{"dark", "light", "high-contrast", "colorblind"}
+
+// ThemeColors are the theme colors display.colors can override
+var ThemeColors = []string{
+	"primary", "success", "warning", "error", "info", "accent",
+	"muted", "subtle", "text", "background",
+}
+
+// AnalysisConfig holds switches for how metrics are computed
+type AnalysisConfig struct {
+	// ExcludeFlagged leaves runs with suspect data (GPS spikes, stuck HR
+	// straps) out of personal records and EF trends
+	ExcludeFlagged bool `json:"exclude_flagged"`
+
+	// RiegelExponent is the fatigue exponent of the Riegel race predictor,
+	// T2 = T1 * (D2 / D1) ^ exponent
+	RiegelExponent float64 `json:"riegel_exponent"`
+
+	// RestDayWarningDays is how many days may pass without a rest day
+	// before the dashboard warns. Negative disables the warning.
+	RestDayWarningDays int `json:"rest_day_warning_days"`
+
+	// RampThresholdPct is how far (%) a week's distance may rise above the
+	// week before before it's flagged, as on the trends screen
+	RampThresholdPct float64 `json:"ramp_threshold_pct"`
+
+	// DisableSmoothing computes metrics and personal records from the GPS
+	// streams exactly as recorded, without removing speed spikes and
+	// distance jumps first
+	DisableSmoothing bool `json:"disable_smoothing"`
+
+	// BestEffortSource is where best-effort records come from: "streams"
+	// (the default) finds them in the downloaded streams, "strava" imports
+	// the best efforts Strava computed, at one extra request per run
+	BestEffortSource string `json:"best_effort_source"`
+
+	// DayBuckets is the clock runs are grouped into days, weeks and months
+	// by: "local" (the default) uses the local time where each run started,
+	// "utc" its start time in UTC
+	DayBuckets string `json:"day_buckets"`
+
+	// WeekStart is the first day of the week for weekly totals, charts and
+	// comparisons: "monday" (the default, as in ISO weeks) or "sunday"
+	WeekStart string `json:"week_start"`
+
+	// LoadModel is the per-run load CTL, ATL and TSB are built from:
+	// "trimp" (the default, Banister TRIMP), "hrss" (TRIMP scaled so an
+	// hour at threshold HR scores 100) or "session_rpe" (RPE times minutes,
+	// for runs with an RPE logged)
+	LoadModel string `json:"load_model,omitempty"`
+
+	// CustomMetrics are extra per-run metrics written as expressions,
+	// computed with the standard ones and shown on the activity detail
+	// screen
+	CustomMetrics []CustomMetric `json:"custom_metrics,omitempty"`
+}
+
+// CustomMetric is a per-run metric computed from an arithmetic expression
+// over the run's values, e.g. "time_above_hr(170) / 60". Name is the key it
+// is stored under; Label, if set, is shown instead of it.
+type CustomMetric struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	Expr  string `json:"expr"`
+}
+
+// customMetricName is the form of AnalysisConfig.CustomMetrics names
+var customMetricName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Best effort sources for AnalysisConfig.BestEffortSource
+const (
+	BestEffortsFromStreams = "streams"
+	BestEffortsFromStrava  = "strava"
+)
+
+// StravaBestEfforts reports whether best efforts are imported from Strava
+func (c AnalysisConfig) StravaBestEfforts() bool {
+	return c.BestEffortSource == BestEffortsFromStrava
+}
+
+// Day bucketing clocks for AnalysisConfig.DayBuckets
+const (
+	DayBucketsLocal = "local"
+	DayBucketsUTC   = "utc"
+)
+
+// UTCDays reports whether runs are bucketed into days by their UTC start
+func (c AnalysisConfig) UTCDays() bool {
+	return c.DayBuckets == DayBucketsUTC
+}
+
+// First days of the week for AnalysisConfig.WeekStart
+const (
+	WeekStartMonday = "monday"
+	WeekStartSunday = "sunday"
+)
+
+// FirstWeekday returns the day weeks start on
+func (c AnalysisConfig) FirstWeekday() time.Weekday {
+	if c.WeekStart == WeekStartSunday {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// Load models for AnalysisConfig.LoadModel
+const (
+	LoadModelTRIMP      = "trimp"
+	LoadModelHRSS       = "hrss"
+	LoadModelSessionRPE = "session_rpe"
+)
+
+// LoadModels lists the load models, the default first
+var LoadModels = []string{LoadModelTRIMP, LoadModelHRSS, LoadModelSessionRPE}
+
+// Load returns the load model, filling in the default
+func (c AnalysisConfig) Load() string {
+	if c.LoadModel == "" {
+		return LoadModelTRIMP
+	}
+	return c.LoadModel
+}
+
+// StorageConfig holds database storage options
+type StorageConfig struct {
+	// CompressStreams stores each activity's streams as one compressed blob
+	// instead of a row per second. Run `runner streams migrate` after
+	// changing it to convert existing data.
+	CompressStreams bool `json:"compress_streams"`
+
+	// BackupIntervalHours is how old the newest backup may get before a
+	// new one is taken at startup. Negative disables scheduled backups.
+	BackupIntervalHours int `json:"backup_interval_hours"`
+
+	// BackupKeep is how many backups to retain in the backups directory
+	BackupKeep int `json:"backup_keep"`
+
+	// StreamResolution is how finely streams are downloaded: "full" for
+	// every point, "high", "medium" or "low" for Strava's resampled streams
+	// of about 10,000, 1,000 or 100 points, or "auto" (the default) for full
+	// streams unless the activity is too long to fit MaxStreamPoints.
+	StreamResolution string `json:"stream_resolution,omitempty"`
+
+	// MaxStreamPoints caps the points stored per activity; longer streams
+	// are downsampled evenly before they are saved. Negative stores every
+	// point.
+	MaxStreamPoints int `json:"max_stream_points"`
+}
+
+// Stream resolutions for StorageConfig.StreamResolution
+const (
+	StreamResolutionAuto   = "auto"
+	StreamResolutionFull   = "full"
+	StreamResolutionHigh   = "high"
+	StreamResolutionMedium = "medium"
+	StreamResolutionLow    = "low"
+)
+
+// StreamResolutions lists the valid StorageConfig.StreamResolution values
+var StreamResolutions = []string{StreamResolutionAuto, StreamResolutionFull, StreamResolutionHigh, StreamResolutionMedium, StreamResolutionLow}
+
+// minStreamPoints keeps a capped stream fine enough for metrics, at a point
+// about every ten seconds over a three-hour run
+const minStreamPoints = 1000
+
+// LoggingConfig controls runner.log in the data directory
+type LoggingConfig struct {
+	// Level is debug, info, warn, or error. Debug adds SQL query timings.
+	Level string `json:"level"`
+}
+
+// PrivacyConfig chooses what is kept about other people's reactions to runs
+type PrivacyConfig struct {
+	// SyncSocial stores each run's kudos, comment, and photo counts so the
+	// activity list can show and sort by them. Turning it off deletes the
+	// stored counts on the next sync.
+	SyncSocial bool `json:"sync_social"`
+
+	// SyncPhotos also keeps the URL of each run's primary photo, taken from
+	// the detailed activity. It only applies with SyncSocial.
+	SyncPhotos bool `json:"sync_photos"`
+
+	// Zones are saved places, such as home and work, whose GPS points are
+	// cropped from exports so shared files don't reveal them
+	Zones []PrivacyZone `json:"zones,omitempty"`
+}
+
+// PrivacyZone is a circle around a saved location
+type PrivacyZone struct {
+	Name    string  `json:"name"`
+	Lat     float64 `json:"lat"`
+	Lng     float64 `json:"lng"`
+	RadiusM float64 `json:"radius_m,omitempty"` // DefaultPrivacyRadiusM when 0
+}
+
+// DefaultPrivacyRadiusM is the radius of a privacy zone that doesn't set one
+const DefaultPrivacyRadiusM = 500
+
+// Radius returns the zone's radius in meters
+func (z PrivacyZone) Radius() float64 {
+	if z.RadiusM == 0 {
+		return DefaultPrivacyRadiusM
+	}
+	return z.RadiusM
+}
+
+// NotificationsConfig chooses the desktop notifications sent by
+// `runner sync -every`, which keeps syncing in the background
+type NotificationsConfig struct {
+	// Sync notifies when a sync stores new runs or fails
+	Sync bool `json:"sync"`
+
+	// Records notifies of new personal records
+	Records bool `json:"records"`
+
+	// Warnings notifies of a high acute:chronic load ratio, monotonous
+	// training, or too long without a rest day
+	Warnings bool `json:"warnings"`
+}
+
+// Hook runs a shell command or POSTs to a URL when `runner sync` reaches an
+// event, passing the event as a JSON payload
+type Hook struct {
+	// Event is one of HookEvents
+	Event string `json:"event"`
+
+	// Command runs through the shell with the payload on stdin
+	Command string `json:"command,omitempty"`
+
+	// URL receives the payload as the body of a POST
+	URL string `json:"url,omitempty"`
 }
 
+// Hook events
+const (
+	HookSyncComplete  = "sync_complete"  // after every sync, with its counts or error
+	HookNewPR         = "new_pr"         // once per personal record a sync set
+	HookWeeklySummary = "weekly_summary" // at the first sync after a week ends
+)
+
+// HookEvents are the valid values of Hook.Event
+var HookEvents = []string{HookSyncComplete, HookNewPR, HookWeeklySummary}
+
 // ErrNoConfig is returned when the config file doesn't exist
 var ErrNoConfig = errors.New("config file not found")
 
@@ -49,10 +360,23 @@ func DefaultConfig() Config {
 			DistanceUnit: "km",
 			PaceUnit:     "min/km",
 		},
+		Analysis: AnalysisConfig{
+			RiegelExponent:     1.06,
+			RestDayWarningDays: 10,
+			RampThresholdPct:   10,
+		},
+		Storage: StorageConfig{
+			BackupIntervalHours: 24,
+			BackupKeep:          7,
+			MaxStreamPoints:     30000,
+		},
+		Logging: LoggingConfig{
+			Level: "info",
+		},
 	}
 }
 
-// Load reads the configuration from ~/.runner/config.json
+// Load reads the configuration from config.json (see paths.ConfigFile)
 func Load() (*Config, error) {
 	path, err := getConfigPath()
 	if err != nil {
@@ -72,6 +396,24 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
 
+	// A unit system fills in whichever units aren't set explicitly
+	switch cfg.Display.Units {
+	case "imperial":
+		if cfg.Display.DistanceUnit == "" {
+			cfg.Display.DistanceUnit = "mi"
+		}
+		if cfg.Display.PaceUnit == "" {
+			cfg.Display.PaceUnit = "min/mi"
+		}
+	case "metric":
+		if cfg.Display.DistanceUnit == "" {
+			cfg.Display.DistanceUnit = "km"
+		}
+		if cfg.Display.PaceUnit == "" {
+			cfg.Display.PaceUnit = "min/km"
+		}
+	}
+
 	// Apply defaults for missing values
 	defaults := DefaultConfig()
 	if cfg.Athlete.RestingHR == 0 {
@@ -89,11 +431,32 @@ func Load() (*Config, error) {
 	if cfg.Display.PaceUnit == "" {
 		cfg.Display.PaceUnit = defaults.Display.PaceUnit
 	}
+	if cfg.Analysis.RiegelExponent == 0 {
+		cfg.Analysis.RiegelExponent = defaults.Analysis.RiegelExponent
+	}
+	if cfg.Analysis.RestDayWarningDays == 0 {
+		cfg.Analysis.RestDayWarningDays = defaults.Analysis.RestDayWarningDays
+	}
+	if cfg.Analysis.RampThresholdPct == 0 {
+		cfg.Analysis.RampThresholdPct = defaults.Analysis.RampThresholdPct
+	}
+	if cfg.Storage.BackupIntervalHours == 0 {
+		cfg.Storage.BackupIntervalHours = defaults.Storage.BackupIntervalHours
+	}
+	if cfg.Storage.BackupKeep == 0 {
+		cfg.Storage.BackupKeep = defaults.Storage.BackupKeep
+	}
+	if cfg.Storage.MaxStreamPoints == 0 {
+		cfg.Storage.MaxStreamPoints = defaults.Storage.MaxStreamPoints
+	}
+	if cfg.Logging.Level == "" {
+		cfg.Logging.Level = defaults.Logging.Level
+	}
 
 	return &cfg, nil
 }
 
-// Save writes the configuration to ~/.runner/config.json
+// Save writes the configuration to config.json
 func Save(cfg *Config) error {
 	path, err := getConfigPath()
 	if err != nil {
@@ -118,35 +481,11 @@ func Save(cfg *Config) error {
 	return nil
 }
 
-// CreateExample creates an example config file if none exists
-func CreateExample() error {
-	path, err := getConfigPath()
-	if err != nil {
-		return err
-	}
-
-	// Check if config already exists
-	if _, err := os.Stat(path); err == nil {
-		return nil // Config exists, don't overwrite
-	}
-
-	example := Config{
-		Strava: StravaConfig{
-			ClientID:     "YOUR_CLIENT_ID",
-			ClientSecret: "YOUR_CLIENT_SECRET",
-		},
-		Athlete: AthleteConfig{
-			RestingHR:   50,
-			MaxHR:       185,
-			ThresholdHR: 165,
-		},
-		Display: DisplayConfig{
-			DistanceUnit: "km",
-			PaceUnit:     "min/km",
-		},
-	}
-
-	return Save(&example)
+// HasCredentials reports whether the Strava app credentials are filled in,
+// rather than missing or left as placeholders
+func (c *Config) HasCredentials() bool {
+	return c.Strava.ClientID != "" && c.Strava.ClientID != "YOUR_CLIENT_ID" &&
+		c.Strava.ClientSecret != "" && c.Strava.ClientSecret != "YOUR_CLIENT_SECRET"
 }
 
 // Validate checks if the config has required fields
@@ -157,8 +496,22 @@ func (c *Config) Validate() error {
 	if c.Strava.ClientSecret == "" || c.Strava.ClientSecret == "YOUR_CLIENT_SECRET" {
 		return errors.New("strava.client_secret is required - get it from https://www.strava.com/settings/api")
 	}
+	if c.Strava.MaxAttempts < 0 || c.Strava.MaxAttempts > MaxStravaAttempts {
+		return fmt.Errorf("strava.max_attempts must be 0 for the default, or between 1 and %d, got %d", MaxStravaAttempts, c.Strava.MaxAttempts)
+	}
+	if c.Strava.RetryDelaySeconds < 0 || c.Strava.RetryDelaySeconds > 60 {
+		return fmt.Errorf("strava.retry_delay_seconds must be between 0 and 60, got %v", c.Strava.RetryDelaySeconds)
+	}
+	return c.ValidateSettings()
+}
 
+// ValidateSettings checks everything Validate does except the Strava
+// credentials, for settings edited in the TUI
+func (c *Config) ValidateSettings() error {
 	// Validate display units
+	if c.Display.Units != "" && c.Display.Units != "metric" && c.Display.Units != "imperial" {
+		return fmt.Errorf("display.units must be \"metric\" or \"imperial\", got %q", c.Display.Units)
+	}
 	if c.Display.DistanceUnit != "" && c.Display.DistanceUnit != "km" && c.Display.DistanceUnit != "mi" {
 		return fmt.Errorf("display.distance_unit must be \"km\" or \"mi\", got %q", c.Display.DistanceUnit)
 	}
@@ -166,28 +519,150 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("display.pace_unit must be \"min/km\" or \"min/mi\", got %q", c.Display.PaceUnit)
 	}
 
+	// Validate theme and color overrides
+	if c.Display.Theme != "" && !slices.Contains(Themes, c.Display.Theme) {
+		return fmt.Errorf("display.theme must be one of %s, got %q", strings.Join(Themes, ", "), c.Display.Theme)
+	}
+	for name, hex := range c.Display.Colors {
+		if !slices.Contains(ThemeColors, name) {
+			return fmt.Errorf("display.colors: unknown color %q (want one of %s)", name, strings.Join(ThemeColors, ", "))
+		}
+		if !IsHexColor(hex) {
+			return fmt.Errorf("display.colors.%s must be a hex color like \"#7C3AED\", got %q", name, hex)
+		}
+	}
+
 	// Validate threshold_hr < max_hr when both are set
 	if c.Athlete.ThresholdHR > 0 && c.Athlete.MaxHR > 0 && c.Athlete.ThresholdHR >= c.Athlete.MaxHR {
 		return fmt.Errorf("athlete.threshold_hr (%v) must be less than athlete.max_hr (%v)", c.Athlete.ThresholdHR, c.Athlete.MaxHR)
 	}
+	if c.Athlete.RestingHR > 0 && c.Athlete.ThresholdHR > 0 && c.Athlete.RestingHR >= c.Athlete.ThresholdHR {
+		return fmt.Errorf("athlete.resting_hr (%v) must be less than athlete.threshold_hr (%v)", c.Athlete.RestingHR, c.Athlete.ThresholdHR)
+	}
+
+	switch c.Athlete.Sex {
+	case "", SexMale, SexFemale:
+	default:
+		return fmt.Errorf("athlete.sex must be \"male\" or \"female\", got %q", c.Athlete.Sex)
+	}
+
+	if len(c.Athlete.PaceAnchors) > MaxPaceAnchors {
+		return fmt.Errorf("athlete.pace_anchors takes at most %d heart rates, got %d", MaxPaceAnchors, len(c.Athlete.PaceAnchors))
+	}
+	for _, hr := range c.Athlete.PaceAnchors {
+		if hr <= 0 || (c.Athlete.MaxHR > 0 && hr >= c.Athlete.MaxHR) {
+			return fmt.Errorf("athlete.pace_anchors must be heart rates below athlete.max_hr, got %v", hr)
+		}
+	}
+
+	if c.Athlete.WeeklyGoalKm < 0 {
+		return fmt.Errorf("athlete.weekly_goal_km must not be negative, got %v", c.Athlete.WeeklyGoalKm)
+	}
+
+	// Riegel exponents outside this range predict nonsense
+	if c.Analysis.RiegelExponent != 0 && (c.Analysis.RiegelExponent < 1 || c.Analysis.RiegelExponent > 1.2) {
+		return fmt.Errorf("analysis.riegel_exponent must be between 1.0 and 1.2, got %v", c.Analysis.RiegelExponent)
+	}
+
+	if c.Analysis.RampThresholdPct < 0 || c.Analysis.RampThresholdPct > 100 {
+		return fmt.Errorf("analysis.ramp_threshold_pct must be between 0 and 100, got %v", c.Analysis.RampThresholdPct)
+	}
+
+	switch c.Analysis.BestEffortSource {
+	case "", BestEffortsFromStreams, BestEffortsFromStrava:
+	default:
+		return fmt.Errorf("analysis.best_effort_source must be \"streams\" or \"strava\", got %q", c.Analysis.BestEffortSource)
+	}
+
+	switch c.Analysis.DayBuckets {
+	case "", DayBucketsLocal, DayBucketsUTC:
+	default:
+		return fmt.Errorf("analysis.day_buckets must be \"local\" or \"utc\", got %q", c.Analysis.DayBuckets)
+	}
+
+	switch c.Analysis.WeekStart {
+	case "", WeekStartMonday, WeekStartSunday:
+	default:
+		return fmt.Errorf("analysis.week_start must be \"monday\" or \"sunday\", got %q", c.Analysis.WeekStart)
+	}
+
+	if c.Analysis.LoadModel != "" && !slices.Contains(LoadModels, c.Analysis.LoadModel) {
+		return fmt.Errorf("analysis.load_model must be one of %s, got %q", strings.Join(LoadModels, ", "), c.Analysis.LoadModel)
+	}
+
+	seen := make(map[string]bool)
+	for i, m := range c.Analysis.CustomMetrics {
+		if !customMetricName.MatchString(m.Name) {
+			return fmt.Errorf("analysis.custom_metrics[%d].name must be lowercase letters, digits and underscores, got %q", i, m.Name)
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("analysis.custom_metrics[%d].name %q is used twice", i, m.Name)
+		}
+		seen[m.Name] = true
+		if strings.TrimSpace(m.Expr) == "" {
+			return fmt.Errorf("analysis.custom_metrics[%d].expr is required", i)
+		}
+	}
+
+	for i, z := range c.Privacy.Zones {
+		if z.Lat < -90 || z.Lat > 90 || z.Lng < -180 || z.Lng > 180 {
+			return fmt.Errorf("privacy.zones[%d]: %v,%v is not a valid latitude and longitude", i, z.Lat, z.Lng)
+		}
+		if z.RadiusM < 0 {
+			return fmt.Errorf("privacy.zones[%d].radius_m must not be negative, got %v", i, z.RadiusM)
+		}
+	}
+
+	for i, h := range c.Hooks {
+		if !slices.Contains(HookEvents, h.Event) {
+			return fmt.Errorf("hooks[%d].event must be one of %s, got %q", i, strings.Join(HookEvents, ", "), h.Event)
+		}
+		if (h.Command == "") == (h.URL == "") {
+			return fmt.Errorf("hooks[%d] must set exactly one of command and url", i)
+		}
+		if h.URL != "" {
+			if u, err := url.Parse(h.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("hooks[%d].url must be an http or https URL, got %q", i, h.URL)
+			}
+		}
+	}
+
+	if c.Storage.StreamResolution != "" && !slices.Contains(StreamResolutions, c.Storage.StreamResolution) {
+		return fmt.Errorf("storage.stream_resolution must be one of %s, got %q", strings.Join(StreamResolutions, ", "), c.Storage.StreamResolution)
+	}
+	if c.Storage.MaxStreamPoints > 0 && c.Storage.MaxStreamPoints < minStreamPoints {
+		return fmt.Errorf("storage.max_stream_points must be at least %d, or negative for no cap, got %d", minStreamPoints, c.Storage.MaxStreamPoints)
+	}
+
+	// Validate log level
+	switch c.Logging.Level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("logging.level must be debug, info, warn, or error, got %q", c.Logging.Level)
+	}
 
 	return nil
 }
 
+// IsHexColor reports whether s is a "#RGB" or "#RRGGBB" color
+func IsHexColor(s string) bool {
+	if (len(s) != 4 && len(s) != 7) || s[0] != '#' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
 // getConfigPath returns the path to the config file
 func getConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("getting home directory: %w", err)
-	}
-	return filepath.Join(home, ".runner", "config.json"), nil
+	return paths.ConfigFile()
 }
 
-// GetConfigDir returns the path to the config directory
-func GetConfigDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("getting home directory: %w", err)
-	}
-	return filepath.Join(home, ".runner"), nil
+// Path returns the path to the config file, for messages pointing to it
+func Path() (string, error) {
+	return getConfigPath()
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -26,6 +28,41 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("Display.PaceUnit = %q, want %q", cfg.Display.PaceUnit, "min/km")
 	}
 
+	// Suspect runs are flagged but not excluded by default
+	if cfg.Analysis.ExcludeFlagged {
+		t.Error("Analysis.ExcludeFlagged should be false by default")
+	}
+
+	// Streams are stored one row per sample by default
+	if cfg.Storage.CompressStreams {
+		t.Error("Storage.CompressStreams should be false by default")
+	}
+
+	if cfg.Analysis.RiegelExponent != 1.06 {
+		t.Errorf("Analysis.RiegelExponent = %v, want 1.06", cfg.Analysis.RiegelExponent)
+	}
+	if cfg.Analysis.RestDayWarningDays != 10 {
+		t.Errorf("Analysis.RestDayWarningDays = %d, want 10", cfg.Analysis.RestDayWarningDays)
+	}
+	if cfg.Analysis.RampThresholdPct != 10 {
+		t.Errorf("Analysis.RampThresholdPct = %v, want 10", cfg.Analysis.RampThresholdPct)
+	}
+
+	// A daily backup is kept for a week
+	if cfg.Storage.BackupIntervalHours != 24 {
+		t.Errorf("Storage.BackupIntervalHours = %d, want 24", cfg.Storage.BackupIntervalHours)
+	}
+	if cfg.Storage.BackupKeep != 7 {
+		t.Errorf("Storage.BackupKeep = %d, want 7", cfg.Storage.BackupKeep)
+	}
+	if cfg.Storage.MaxStreamPoints != 30000 {
+		t.Errorf("Storage.MaxStreamPoints = %d, want 30000", cfg.Storage.MaxStreamPoints)
+	}
+
+	if cfg.Logging.Level != "info" {
+		t.Errorf("Logging.Level = %q, want %q", cfg.Logging.Level, "info")
+	}
+
 	// Strava config should be empty by default
 	if cfg.Strava.ClientID != "" {
 		t.Errorf("Strava.ClientID should be empty, got %q", cfg.Strava.ClientID)
@@ -107,6 +144,358 @@ func TestConfigValidate(t *testing.T) {
 			expectError: true,
 			errContains: "client_id", // first error wins
 		},
+		{
+			name: "unknown unit system",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Display: DisplayConfig{Units: "nautical"},
+			},
+			expectError: true,
+			errContains: "display.units",
+		},
+		{
+			name: "theme with color overrides",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Display: DisplayConfig{Theme: "colorblind", Colors: map[string]string{"primary": "#0072B2", "muted": "#999"}},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown theme",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Display: DisplayConfig{Theme: "solarized"},
+			},
+			expectError: true,
+			errContains: "display.theme",
+		},
+		{
+			name: "unknown theme color",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Display: DisplayConfig{Colors: map[string]string{"sparkle": "#FFFFFF"}},
+			},
+			expectError: true,
+			errContains: "sparkle",
+		},
+		{
+			name: "invalid hex color",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Display: DisplayConfig{Colors: map[string]string{"error": "red"}},
+			},
+			expectError: true,
+			errContains: "display.colors.error",
+		},
+		{
+			name: "negative weekly goal",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Athlete: AthleteConfig{WeeklyGoalKm: -10},
+			},
+			expectError: true,
+			errContains: "athlete.weekly_goal_km",
+		},
+		{
+			name: "too many pace anchors",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Athlete: AthleteConfig{MaxHR: 190, PaceAnchors: []float64{130, 140, 150, 160}},
+			},
+			expectError: true,
+			errContains: "athlete.pace_anchors",
+		},
+		{
+			name: "pace anchor above max HR",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Athlete: AthleteConfig{MaxHR: 190, PaceAnchors: []float64{140, 195}},
+			},
+			expectError: true,
+			errContains: "athlete.pace_anchors",
+		},
+		{
+			name: "unknown sex",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Athlete: AthleteConfig{Sex: "f"},
+			},
+			expectError: true,
+			errContains: "athlete.sex",
+		},
+		{
+			name: "unknown load model",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{LoadModel: "tss"},
+			},
+			expectError: true,
+			errContains: "analysis.load_model",
+		},
+		{
+			name: "custom metric named twice",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{CustomMetrics: []CustomMetric{
+					{Name: "hard_minutes", Expr: "time_above_hr(170) / 60"},
+					{Name: "hard_minutes", Expr: "time_above_hr(175) / 60"},
+				}},
+			},
+			expectError: true,
+			errContains: "used twice",
+		},
+		{
+			name: "custom metric with a bad name",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{CustomMetrics: []CustomMetric{{Name: "Hard Minutes", Expr: "1"}}},
+			},
+			expectError: true,
+			errContains: "analysis.custom_metrics[0].name",
+		},
+		{
+			name: "too many strava attempts",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+					MaxAttempts:  25,
+				},
+			},
+			expectError: true,
+			errContains: "strava.max_attempts",
+		},
+		{
+			name: "negative max attempts",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+					MaxAttempts:  -1,
+				},
+			},
+			expectError: true,
+			errContains: "must be 0 for the default",
+		},
+		{
+			name: "negative retry delay",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:          "12345",
+					ClientSecret:      "abc123secret",
+					RetryDelaySeconds: -1,
+				},
+			},
+			expectError: true,
+			errContains: "strava.retry_delay_seconds",
+		},
+		{
+			name: "hook with an unknown event",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Hooks: []Hook{{Event: "sync_done", Command: "true"}},
+			},
+			expectError: true,
+			errContains: "hooks[0].event",
+		},
+		{
+			name: "hook with a command and a url",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Hooks: []Hook{
+					{Event: HookNewPR, URL: "https://hooks.example.com/pr"},
+					{Event: HookSyncComplete, Command: "true", URL: "https://hooks.example.com/sync"},
+				},
+			},
+			expectError: true,
+			errContains: "hooks[1] must set exactly one",
+		},
+		{
+			name: "hook with a bad url",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Hooks: []Hook{{Event: HookWeeklySummary, URL: "hooks.example.com"}},
+			},
+			expectError: true,
+			errContains: "hooks[0].url",
+		},
+		{
+			name: "resting HR above threshold",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Athlete: AthleteConfig{RestingHR: 170, MaxHR: 190, ThresholdHR: 165},
+			},
+			expectError: true,
+			errContains: "athlete.resting_hr",
+		},
+		{
+			name: "riegel exponent out of range",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{RiegelExponent: 1.5},
+			},
+			expectError: true,
+			errContains: "analysis.riegel_exponent",
+		},
+		{
+			name: "negative ramp threshold",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{RampThresholdPct: -5},
+			},
+			expectError: true,
+			errContains: "analysis.ramp_threshold_pct",
+		},
+		{
+			name: "unknown best effort source",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{BestEffortSource: "garmin"},
+			},
+			expectError: true,
+			errContains: "analysis.best_effort_source",
+		},
+		{
+			name: "unknown day buckets",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{DayBuckets: "tokyo"},
+			},
+			expectError: true,
+			errContains: "analysis.day_buckets",
+		},
+		{
+			name: "unknown week start",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Analysis: AnalysisConfig{WeekStart: "friday"},
+			},
+			expectError: true,
+			errContains: "analysis.week_start",
+		},
+		{
+			name: "privacy zone off the map",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Privacy: PrivacyConfig{Zones: []PrivacyZone{{Name: "home", Lat: 105.27, Lng: 40.01}}},
+			},
+			expectError: true,
+			errContains: "privacy.zones[0]",
+		},
+		{
+			name: "unknown stream resolution",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Storage: StorageConfig{StreamResolution: "ultra"},
+			},
+			expectError: true,
+			errContains: "storage.stream_resolution",
+		},
+		{
+			name: "stream point cap too small",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Storage: StorageConfig{MaxStreamPoints: 500},
+			},
+			expectError: true,
+			errContains: "storage.max_stream_points",
+		},
+		{
+			name: "no stream point cap",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Storage: StorageConfig{StreamResolution: "full", MaxStreamPoints: -1},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown log level",
+			config: Config{
+				Strava: StravaConfig{
+					ClientID:     "12345",
+					ClientSecret: "abc123secret",
+				},
+				Logging: LoggingConfig{Level: "verbose"},
+			},
+			expectError: true,
+			errContains: "logging.level",
+		},
 	}
 
 	for _, tt := range tests {
@@ -127,6 +516,37 @@ func TestConfigValidate(t *testing.T) {
 	}
 }
 
+func TestConfigValidateSettings(t *testing.T) {
+	// Settings don't need Strava credentials, but are otherwise checked
+	cfg := DefaultConfig()
+	if err := cfg.ValidateSettings(); err != nil {
+		t.Errorf("ValidateSettings() on defaults = %v, want nil", err)
+	}
+	cfg.Display.DistanceUnit = "furlongs"
+	if err := cfg.ValidateSettings(); err == nil || !containsString(err.Error(), "display.distance_unit") {
+		t.Errorf("ValidateSettings() = %v, want a display.distance_unit error", err)
+	}
+}
+
+func TestConfigHasCredentials(t *testing.T) {
+	tests := []struct {
+		name   string
+		strava StravaConfig
+		want   bool
+	}{
+		{"missing", StravaConfig{}, false},
+		{"placeholders", StravaConfig{ClientID: "YOUR_CLIENT_ID", ClientSecret: "YOUR_CLIENT_SECRET"}, false},
+		{"secret missing", StravaConfig{ClientID: "12345"}, false},
+		{"filled in", StravaConfig{ClientID: "12345", ClientSecret: "abc"}, true},
+	}
+	for _, tt := range tests {
+		cfg := Config{Strava: tt.strava}
+		if got := cfg.HasCredentials(); got != tt.want {
+			t.Errorf("%s: HasCredentials() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsStringHelper(s, substr))
 }
@@ -168,3 +588,39 @@ func TestConfigTypes(t *testing.T) {
 		t.Error("DisplayConfig.DistanceUnit not set correctly")
 	}
 }
+
+func TestLoadUnitSystem(t *testing.T) {
+	tests := []struct {
+		name         string
+		display      string
+		wantDistance string
+		wantPace     string
+	}{
+		{"imperial", `{"units": "imperial"}`, "mi", "min/mi"},
+		{"metric", `{"units": "metric"}`, "km", "min/km"},
+		{"explicit unit wins", `{"units": "imperial", "pace_unit": "min/km"}`, "mi", "min/km"},
+		{"no unit system", `{}`, "km", "min/km"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if err := os.MkdirAll(filepath.Join(home, ".runner"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			data := []byte(`{"display": ` + tt.display + `}`)
+			if err := os.WriteFile(filepath.Join(home, ".runner", "config.json"), data, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Display.DistanceUnit != tt.wantDistance || cfg.Display.PaceUnit != tt.wantPace {
+				t.Errorf("units = %s, %s; want %s, %s", cfg.Display.DistanceUnit, cfg.Display.PaceUnit, tt.wantDistance, tt.wantPace)
+			}
+		})
+	}
+}
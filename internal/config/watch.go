@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce lets a burst of writes, as editors make when saving, settle
+// into one reload
+const watchDebounce = 200 * time.Millisecond
+
+// Watch calls onChange with the reloaded config, or the error loading it,
+// each time the config file changes, until ctx is done. It watches the
+// directory rather than the file, since editors often save by replacing it.
+func Watch(ctx context.Context, onChange func(*Config, error)) error {
+	path, err := getConfigPath()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("watching config directory: %w", err)
+	}
+
+	// Stopped until the first change
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) == path && event.Has(fsnotify.Write|fsnotify.Create) {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			onChange(nil, fmt.Errorf("watching config: %w", err))
+		case <-debounce.C:
+			onChange(Load())
+		}
+	}
+}
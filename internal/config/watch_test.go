@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".runner")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"athlete": {"max_hr": 185}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan *Config, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, func(cfg *Config, err error) {
+			if err != nil {
+				t.Errorf("reload error = %v", err)
+				return
+			}
+			reloads <- cfg
+		})
+	}()
+
+	// Other files in the directory are ignored; the config is picked up
+	// after the writes settle. Retry in case the watch isn't set up yet.
+	if err := os.WriteFile(filepath.Join(dir, "runner.log"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(5 * time.Second)
+	for {
+		if err := os.WriteFile(path, []byte(`{"athlete": {"max_hr": 190}}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case cfg := <-reloads:
+			if cfg.Athlete.MaxHR != 190 {
+				t.Errorf("reloaded MaxHR = %v, want 190", cfg.Athlete.MaxHR)
+			}
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("Watch() = %v", err)
+			}
+			return
+		case <-time.After(500 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no reload after writing the config")
+		}
+	}
+}
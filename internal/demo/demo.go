@@ -0,0 +1,306 @@
+// Package demo generates a synthetic training history, so the app can be
+// explored without a Strava account and screenshots and tests have stable
+// data to work from.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const (
+	// Weeks of training generated, ending with the week of the end date
+	Weeks = 20
+
+	// AthleteID owns every demo activity
+	AthleteID = 1
+
+	// Activity IDs count up from here, oldest first
+	firstActivityID = 1_000_001
+
+	// seed makes the generated data identical from run to run
+	seed = 42
+)
+
+// Athlete returns the heart rate profile the demo runs were generated for.
+// Analyzing them with other zones still works but gives odder metrics.
+func Athlete() config.AthleteConfig {
+	return config.AthleteConfig{RestingHR: 48, MaxHR: 188, ThresholdHR: 168, WeightKg: 64}
+}
+
+// Load fills db with Weeks of synthetic runs ending at end, then computes
+// their metrics, personal records, races and predictions as a sync would.
+// The same end date always produces the same data. It returns the number of
+// runs generated.
+func Load(ctx context.Context, db *store.Store, end time.Time) (int, error) {
+	runs := plan(end)
+	rng := rand.New(rand.NewSource(seed))
+
+	// One blob per activity keeps the load fast
+	db.SetCompressStreams(true)
+
+	for i, r := range runs {
+		id := int64(firstActivityID + i)
+		activity, points := r.simulate(id, rng)
+
+		if err := db.UpsertActivity(activity); err != nil {
+			return 0, fmt.Errorf("storing demo activity: %w", err)
+		}
+		if err := db.SaveStreams(id, points); err != nil {
+			return 0, fmt.Errorf("storing demo streams: %w", err)
+		}
+		if err := db.MarkStreamsSynced(id); err != nil {
+			return 0, fmt.Errorf("storing demo streams: %w", err)
+		}
+		if r.workout.race {
+			if _, err := db.MarkRace(id, store.RaceSourceStrava); err != nil {
+				return 0, fmt.Errorf("marking demo race: %w", err)
+			}
+		}
+	}
+
+	syncSvc := service.NewSyncService(nil, db, Athlete(), config.AnalysisConfig{})
+	if _, err := syncSvc.AnalyzeStored(ctx, nil); err != nil {
+		return 0, fmt.Errorf("analyzing demo activities: %w", err)
+	}
+	return len(runs), nil
+}
+
+// segment is a stretch of a workout run at one effort. It lasts seconds, or
+// until the workout has covered meters when meters is set.
+type segment struct {
+	seconds int
+	meters  float64
+	speed   float64 // m/s
+	hr      float64 // steady-state bpm
+}
+
+type workout struct {
+	name     string
+	segments []segment
+	race     bool
+	driftBPM float64 // HR creep over the run, for long runs
+}
+
+// scheduledRun is a workout on a day
+type scheduledRun struct {
+	start   time.Time
+	workout workout
+}
+
+// plan lays out the training weeks ending with the week of end, skipping
+// runs after end. Fitness improves steadily, every fourth week is easier, and
+// three races punctuate the block.
+func plan(end time.Time) []scheduledRun {
+	weekStart := end.AddDate(0, 0, -((int(end.Weekday())+6)%7)-7*(Weeks-1))
+	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, end.Location())
+
+	var runs []scheduledRun
+	for w := 0; w < Weeks; w++ {
+		f := float64(w) / float64(Weeks-1) // 0 to 1 through the block
+		easy := segment{speed: 2.75 + 0.25*f, hr: 146 - 6*f}
+		recovery := w%4 == 3
+		scale := 1.0
+		if recovery {
+			scale = 0.75
+		}
+
+		days := map[int]workout{
+			1: easyRun("Easy Run", easy, int(45*60*scale)),
+			3: qualityRun(w, f),
+			5: easyRun("Easy Run", easy, int(40*60*scale)),
+			6: longRun(easy, int((70+float64(w)*2)*60*scale)),
+		}
+		if w%2 == 0 && !recovery {
+			days[2] = easyRun("Recovery Run", segment{speed: easy.speed * 0.92, hr: easy.hr - 8}, 30*60)
+		}
+
+		// Races replace that day's run
+		switch w {
+		case 6:
+			days[5] = race("Parkrun 5K", 5000, 4.15+0.25*f, 178)
+		case 12:
+			days[6] = race("City 10K", 10000, 3.95+0.25*f, 174)
+		case Weeks - 2:
+			days[6] = race("Half Marathon", 21097.5, 3.65+0.2*f, 168)
+		}
+
+		for day := 0; day < 7; day++ {
+			wo, ok := days[day]
+			if !ok {
+				continue
+			}
+			start := weekStart.AddDate(0, 0, 7*w+day).Add(6*time.Hour + 30*time.Minute + time.Duration(day*7)*time.Minute)
+			if start.After(end) {
+				continue
+			}
+			runs = append(runs, scheduledRun{start: start, workout: wo})
+		}
+	}
+	return runs
+}
+
+func easyRun(name string, easy segment, seconds int) workout {
+	easy.seconds = seconds
+	return workout{name: name, segments: []segment{easy}}
+}
+
+func longRun(easy segment, seconds int) workout {
+	easy.seconds = seconds
+	easy.speed *= 0.97
+	return workout{name: "Long Run", segments: []segment{easy}, driftBPM: 8}
+}
+
+// qualityRun alternates tempo runs and track intervals. Both stay well
+// short of 10K so race detection doesn't mistake them for races.
+func qualityRun(week int, f float64) workout {
+	warm := segment{seconds: 10 * 60, speed: 2.8 + 0.2*f, hr: 140}
+	cool := segment{seconds: 8 * 60, speed: 2.7 + 0.2*f, hr: 138}
+
+	if week%2 == 0 {
+		tempo := segment{seconds: 20*60 + week*20, speed: 3.55 + 0.25*f, hr: 165}
+		return workout{name: "Tempo Run", segments: []segment{warm, tempo, cool}}
+	}
+
+	segs := []segment{warm}
+	for i := 0; i < 6; i++ {
+		segs = append(segs,
+			segment{meters: 800, speed: 4.3 + 0.2*f, hr: 176},
+			segment{seconds: 120, speed: 2.2, hr: 135},
+		)
+	}
+	segs = append(segs, cool)
+	return workout{name: "Intervals 6x800m", segments: segs}
+}
+
+// race runs slightly over the distance, as GPS usually records
+func race(name string, meters, speed, hr float64) workout {
+	return workout{name: name, race: true, segments: []segment{{meters: meters * 1.01, speed: speed, hr: hr}}}
+}
+
+// Loop the runs trace around, in degrees
+const (
+	homeLat     = 40.7812
+	homeLng     = -73.9665
+	loopMeters  = 2500.0
+	loopRadiusD = loopMeters / (2 * math.Pi) / 111_000
+)
+
+// sampleSeconds is the stream sampling interval, as on watches using smart
+// recording
+const sampleSeconds = 2
+
+// simulate produces the activity summary and streams for a run. HR moves
+// toward each segment's target with a lag, so it climbs into efforts and
+// recovers after them.
+func (r scheduledRun) simulate(id int64, rng *rand.Rand) (*store.Activity, []store.StreamPoint) {
+	var points []store.StreamPoint
+	var dist, elevGain, maxSpeed, hrSum, cadenceSum float64
+	maxHR := 0
+	hr := 95.0
+	alt := altitudeAt(0)
+
+	total := 0
+	for _, s := range r.workout.segments {
+		total += s.seconds
+	}
+
+	t := 0
+	for _, seg := range r.workout.segments {
+		segStart, segDist := t, dist
+		for {
+			if seg.meters > 0 && dist-segDist >= seg.meters {
+				break
+			}
+			if seg.meters == 0 && t-segStart >= seg.seconds {
+				break
+			}
+
+			grade := (altitudeAt(dist+1) - altitudeAt(dist)) * 100
+			speed := seg.speed*(1-grade*0.02) + rng.NormFloat64()*0.06
+			speed = math.Max(speed, 1.0)
+
+			target := seg.hr
+			if r.workout.driftBPM > 0 && total > 0 {
+				target += r.workout.driftBPM * float64(t) / float64(total)
+			}
+			hr += (target - hr) * 0.04 * sampleSeconds
+			bpm := int(math.Round(hr + rng.NormFloat64()*1.2))
+
+			cadence := int(math.Round(78 + (speed-2.5)*5 + rng.NormFloat64()))
+			newAlt := altitudeAt(dist)
+			if newAlt > alt {
+				elevGain += newAlt - alt
+			}
+			alt = newAlt
+
+			angle := dist / loopMeters * 2 * math.Pi
+			lat := homeLat + loopRadiusD*math.Sin(angle)
+			lng := homeLng + loopRadiusD*(1-math.Cos(angle))/math.Cos(homeLat*math.Pi/180)
+
+			points = append(points, pointAt(id, t, dist, speed, bpm, cadence, alt, grade, lat, lng))
+
+			maxSpeed = math.Max(maxSpeed, speed)
+			maxHR = max(maxHR, bpm)
+			hrSum += float64(bpm)
+			cadenceSum += float64(cadence)
+
+			dist += speed * sampleSeconds
+			t += sampleSeconds
+		}
+	}
+
+	n := float64(len(points))
+	avgHR := hrSum / n
+	maxHRf := float64(maxHR)
+	avgCadence := cadenceSum / n
+	local := r.start
+	activity := &store.Activity{
+		ID:                 id,
+		AthleteID:          AthleteID,
+		Name:               r.workout.name,
+		Type:               "Run",
+		StartDate:          local.UTC(),
+		StartDateLocal:     time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC),
+		Timezone:           local.Location().String(),
+		Distance:           math.Round(dist*10) / 10,
+		MovingTime:         t,
+		ElapsedTime:        t + 30 + rng.Intn(90),
+		TotalElevationGain: math.Round(elevGain*10) / 10,
+		AverageSpeed:       dist / float64(t),
+		MaxSpeed:           maxSpeed,
+		AverageHeartrate:   &avgHR,
+		MaxHeartrate:       &maxHRf,
+		AverageCadence:     &avgCadence,
+		HasHeartrate:       true,
+		StreamsSynced:      true,
+	}
+	return activity, points
+}
+
+// altitudeAt gives rolling terrain along the loop
+func altitudeAt(meters float64) float64 {
+	return 40 + 12*math.Sin(meters/loopMeters*2*math.Pi) + 4*math.Sin(meters/400)
+}
+
+func pointAt(id int64, t int, dist, speed float64, hr, cadence int, alt, grade, lat, lng float64) store.StreamPoint {
+	return store.StreamPoint{
+		ActivityID:     id,
+		TimeOffset:     t,
+		Lat:            &lat,
+		Lng:            &lng,
+		Altitude:       &alt,
+		VelocitySmooth: &speed,
+		Heartrate:      &hr,
+		Cadence:        &cadence,
+		GradeSmooth:    &grade,
+		Distance:       &dist,
+	}
+}
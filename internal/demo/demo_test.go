@@ -0,0 +1,95 @@
+package demo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+// end is a fixed date so the generated data never changes
+var end = time.Date(2025, time.June, 15, 20, 0, 0, 0, time.UTC)
+
+func TestLoad(t *testing.T) {
+	db, err := store.OpenMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The dashboard looks back from today
+	n, err := Load(context.Background(), db, time.Now())
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if n < Weeks*4 {
+		t.Errorf("expected at least %d runs, got %d", Weeks*4, n)
+	}
+
+	qs := service.NewQueryService(db, Athlete())
+	dash, err := qs.GetDashboardData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dash.CurrentEF <= 0 || dash.CurrentFitness <= 0 {
+		t.Errorf("expected EF and fitness, got EF %.2f CTL %.0f", dash.CurrentEF, dash.CurrentFitness)
+	}
+	if !dash.EFProjection.OK || dash.EFProjection.SlopePerWeek <= 0 {
+		t.Errorf("expected a rising EF trend, got %+v", dash.EFProjection)
+	}
+
+	prs, err := qs.GetPersonalRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs.RaceDistancePRs) == 0 || len(prs.BestEffortPRs) == 0 {
+		t.Errorf("expected race and effort PRs, got %d and %d", len(prs.RaceDistancePRs), len(prs.BestEffortPRs))
+	}
+
+	races, err := qs.GetRaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(races) != 3 {
+		t.Errorf("expected 3 races, got %d", len(races))
+	}
+
+	preds, err := qs.GetRacePredictions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preds.Predictions) == 0 {
+		t.Error("expected race predictions")
+	}
+
+	// Every run should analyze cleanly
+	metrics, err := db.GetAllMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != n {
+		t.Errorf("expected metrics for all %d runs, got %d", n, len(metrics))
+	}
+	for _, m := range metrics {
+		if len(m.AnomalyFlags) > 0 {
+			t.Errorf("activity %d flagged %v", m.ActivityID, m.AnomalyFlags)
+		}
+	}
+}
+
+func TestPlan_Deterministic(t *testing.T) {
+	a, b := plan(end), plan(end)
+	if len(a) != len(b) {
+		t.Fatal("plan changed between calls")
+	}
+	for i := range a {
+		if !a[i].start.Equal(b[i].start) || a[i].workout.name != b[i].workout.name {
+			t.Fatalf("run %d differs", i)
+		}
+	}
+	if last := a[len(a)-1].start; last.After(end) {
+		t.Errorf("planned a run after the end date: %v", last)
+	}
+}
@@ -0,0 +1,158 @@
+// Package export writes activities in formats other tools import: CSV for
+// training platforms, so history can be moved or mirrored without retyping
+// it, and iCalendar for calendar apps. A run's raw streams can also be written
+// as CSV for a closer look.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+// Format is a target platform's file layout
+type Format string
+
+const (
+	FormatIntervals     Format = "intervals"
+	FormatTrainingPeaks Format = "trainingpeaks"
+	FormatICal          Format = "ical"
+)
+
+// ParseFormat parses a format name, accepting a few common spellings
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "intervals", "intervals.icu", "icu":
+		return FormatIntervals, nil
+	case "trainingpeaks", "tp":
+		return FormatTrainingPeaks, nil
+	case "ical", "ics":
+		return FormatICal, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want intervals, trainingpeaks or ical)", s)
+}
+
+// Columns use the field names each platform uses in its own exports and API
+var (
+	intervalsHeader = []string{
+		"start_date_local", "name", "type", "moving_time", "elapsed_time", "distance",
+		"total_elevation_gain", "average_heartrate", "max_heartrate", "average_cadence",
+		"icu_training_load", "trimp", "icu_rpe", "feel", "tags", "description",
+	}
+	trainingPeaksHeader = []string{
+		"Title", "WorkoutType", "WorkoutDay", "TimeTotalInHours", "DistanceInMeters",
+		"VelocityAverage", "HeartRateAverage", "HeartRateMax", "CadenceAverage", "TSS",
+		"HRZone1Minutes", "HRZone2Minutes", "HRZone3Minutes", "HRZone4Minutes", "HRZone5Minutes",
+		"Rpe", "Feeling", "Tags", "AthleteComments",
+	}
+)
+
+// WriteCSV writes one row per activity in the given format. Training load is
+// the activity's HRSS, an hrTSS-style score where an hour at threshold is
+// about 100, which is what both platforms expect in their load columns.
+// RPE and feel are written when logged; intervals.icu scores feel from 1
+// (strong) to 5 (weak), so it is flipped to match. Tags are joined with
+// commas and the note goes in the description or comments. Values that were
+// never measured are left empty.
+func WriteCSV(w io.Writer, format Format, activities []service.ActivityWithMetrics) error {
+	cw := csv.NewWriter(w)
+
+	header, row := intervalsHeader, intervalsRow
+	if format == FormatTrainingPeaks {
+		header, row = trainingPeaksHeader, trainingPeaksRow
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, a := range activities {
+		if err := cw.Write(append(row(a.Activity, a.Metrics, a.Effort), strings.Join(a.Tags, ","), a.Note)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func intervalsRow(a store.Activity, m store.ActivityMetrics, e *store.ActivityRPE) []string {
+	rpe, feel := "", ""
+	if e != nil {
+		rpe = strconv.Itoa(e.RPE)
+		if e.Feel != nil {
+			feel = strconv.Itoa(store.MaxFeel + store.MinFeel - *e.Feel)
+		}
+	}
+	return []string{
+		a.StartDateLocal.Format("2006-01-02T15:04:05"),
+		a.Name,
+		a.Type,
+		strconv.Itoa(a.MovingTime),
+		strconv.Itoa(a.ElapsedTime),
+		formatFloat(a.Distance, 1),
+		formatFloat(a.TotalElevationGain, 1),
+		formatOptional(a.AverageHeartrate, 0),
+		formatOptional(a.MaxHeartrate, 0),
+		formatOptional(stepsPerMinute(a.AverageCadence), 0),
+		formatOptional(m.HRSS, 0),
+		formatOptional(m.TRIMP, 0),
+		rpe,
+		feel,
+	}
+}
+
+func trainingPeaksRow(a store.Activity, m store.ActivityMetrics, e *store.ActivityRPE) []string {
+	row := []string{
+		a.Name,
+		"Run",
+		a.StartDateLocal.Format("2006-01-02"),
+		formatFloat(float64(a.MovingTime)/3600, 4),
+		formatFloat(a.Distance, 1),
+		formatFloat(a.AverageSpeed, 3),
+		formatOptional(a.AverageHeartrate, 0),
+		formatOptional(a.MaxHeartrate, 0),
+		formatOptional(stepsPerMinute(a.AverageCadence), 0),
+		formatOptional(m.HRSS, 1),
+	}
+	for _, secs := range []*int{m.Z1Seconds, m.Z2Seconds, m.Z3Seconds, m.Z4Seconds, m.Z5Seconds} {
+		if secs == nil {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, formatFloat(float64(*secs)/60, 1))
+	}
+	rpe, feel := "", ""
+	if e != nil {
+		rpe = strconv.Itoa(e.RPE)
+		if e.Feel != nil {
+			feel = strconv.Itoa(*e.Feel)
+		}
+	}
+	return append(row, rpe, feel)
+}
+
+// stepsPerMinute converts Strava's per-foot running cadence to steps per
+// minute
+func stepsPerMinute(cadence *float64) *float64 {
+	if cadence == nil || *cadence <= 0 {
+		return nil
+	}
+	spm := *cadence * 2
+	return &spm
+}
+
+func formatFloat(v float64, precision int) string {
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
+
+func formatOptional(v *float64, precision int) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v, precision)
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+func testActivities() []service.ActivityWithMetrics {
+	hr, cadence, hrss, trimp := 150.0, 85.0, 72.5, 95.0
+	z1, z2 := 600, 1800
+	good := 4
+	start := time.Date(2025, time.March, 3, 7, 30, 0, 0, time.UTC)
+	return []service.ActivityWithMetrics{
+		{
+			Activity: store.Activity{
+				ID: 1, Name: "Tempo, hard", Type: "Run", StartDateLocal: start,
+				Distance: 10000, MovingTime: 2700, ElapsedTime: 2760, AverageSpeed: 3.704,
+				AverageHeartrate: &hr, AverageCadence: &cadence,
+			},
+			Metrics: store.ActivityMetrics{ActivityID: 1, HRSS: &hrss, TRIMP: &trimp, Z1Seconds: &z1, Z2Seconds: &z2},
+			Effort:  &store.ActivityRPE{RPE: 7, Feel: &good},
+			Tags:    []string{"tempo", "track"},
+			Note:    "Windy on the back straight",
+		},
+		{
+			// A run without HR or metrics
+			Activity: store.Activity{ID: 2, Name: "Treadmill", Type: "Run", StartDateLocal: start.AddDate(0, 0, 1), Distance: 5000, MovingTime: 1500},
+			Metrics:  store.ActivityMetrics{ActivityID: 2},
+		},
+	}
+}
+
+func readCSV(t *testing.T, format Format) [][]string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, format, testActivities()); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %d records", len(records))
+	}
+	return records
+}
+
+func column(t *testing.T, records [][]string, row int, name string) string {
+	t.Helper()
+	for i, h := range records[0] {
+		if h == name {
+			return records[row][i]
+		}
+	}
+	t.Fatalf("no %q column", name)
+	return ""
+}
+
+func TestWriteCSV_Intervals(t *testing.T) {
+	records := readCSV(t, FormatIntervals)
+
+	want := map[string]string{
+		"start_date_local":  "2025-03-03T07:30:00",
+		"name":              "Tempo, hard",
+		"moving_time":       "2700",
+		"distance":          "10000.0",
+		"average_heartrate": "150",
+		"average_cadence":   "170",
+		"icu_training_load": "72",
+		"trimp":             "95",
+		"icu_rpe":           "7",
+		"feel":              "2",
+		"tags":              "tempo,track",
+		"description":       "Windy on the back straight",
+	}
+	for name, v := range want {
+		if got := column(t, records, 1, name); got != v {
+			t.Errorf("%s = %q, want %q", name, got, v)
+		}
+	}
+
+	if got := column(t, records, 2, "icu_training_load"); got != "" {
+		t.Errorf("expected no load for a run without metrics, got %q", got)
+	}
+	if got := column(t, records, 2, "icu_rpe"); got != "" {
+		t.Errorf("expected no RPE for a run without one logged, got %q", got)
+	}
+	if got := column(t, records, 2, "tags"); got != "" {
+		t.Errorf("expected no tags for an untagged run, got %q", got)
+	}
+}
+
+func TestWriteCSV_TrainingPeaks(t *testing.T) {
+	records := readCSV(t, FormatTrainingPeaks)
+
+	want := map[string]string{
+		"WorkoutDay":       "2025-03-03",
+		"WorkoutType":      "Run",
+		"TimeTotalInHours": "0.7500",
+		"TSS":              "72.5",
+		"HRZone1Minutes":   "10.0",
+		"HRZone2Minutes":   "30.0",
+		"HRZone3Minutes":   "",
+		"Rpe":              "7",
+		"Feeling":          "4",
+		"Tags":             "tempo,track",
+		"AthleteComments":  "Windy on the back straight",
+	}
+	for name, v := range want {
+		if got := column(t, records, 1, name); got != v {
+			t.Errorf("%s = %q, want %q", name, got, v)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"intervals": FormatIntervals, "Intervals.icu": FormatIntervals, "tp": FormatTrainingPeaks, "ics": FormatICal} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("garmin"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const (
+	metersPerMile = 1609.34
+	metersPerKm   = 1000.0
+
+	// icalLineLimit is the longest line RFC 5545 allows, in octets
+	icalLineLimit = 75
+)
+
+// WriteICal writes the activities as an iCalendar feed with one event per
+// run, spanning its elapsed time. Event UIDs are stable, so calendar apps
+// update events in place when the feed is regenerated.
+func WriteICal(w io.Writer, activities []service.ActivityWithMetrics, display config.DisplayConfig, generatedAt time.Time) error {
+	miles := display.DistanceUnit == "mi"
+	stamp := icalTime(generatedAt)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//runner//Training Log//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Runs",
+	}
+	for _, a := range activities {
+		end := a.Activity.StartDate.Add(time.Duration(max(a.Activity.ElapsedTime, a.Activity.MovingTime)) * time.Second)
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:activity-%d@runner", a.Activity.ID),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+icalTime(a.Activity.StartDate),
+			"DTEND:"+icalTime(end),
+			"SUMMARY:"+icalEscape(eventSummary(a.Activity, miles)),
+			"DESCRIPTION:"+icalEscape(eventDescription(a, miles)),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(icalFold(line))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// eventSummary is the event title, e.g. "Morning Run (10.0 km)"
+func eventSummary(a store.Activity, miles bool) string {
+	return fmt.Sprintf("%s (%s)", a.Name, formatDistance(a.Distance, miles))
+}
+
+// eventDescription lists the run's numbers, then its tags and note
+func eventDescription(run service.ActivityWithMetrics, miles bool) string {
+	a, m, e := run.Activity, run.Metrics, run.Effort
+	lines := []string{
+		"Distance: " + formatDistance(a.Distance, miles),
+		"Moving time: " + formatClock(a.MovingTime),
+	}
+	if a.Distance > 0 && a.MovingTime > 0 {
+		lines = append(lines, "Pace: "+formatPace(float64(a.MovingTime)/a.Distance, miles))
+	}
+	if a.AverageHeartrate != nil {
+		lines = append(lines, fmt.Sprintf("Avg HR: %.0f bpm", *a.AverageHeartrate))
+	}
+	if m.EfficiencyFactor != nil {
+		lines = append(lines, fmt.Sprintf("EF: %.2f", *m.EfficiencyFactor))
+	}
+	if m.HRSS != nil {
+		lines = append(lines, fmt.Sprintf("Load: %.0f", *m.HRSS))
+	}
+	if e != nil {
+		lines = append(lines, "Effort: "+service.FormatEffort(*e))
+	}
+	if len(run.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(run.Tags, ", "))
+	}
+	if run.Note != "" {
+		lines = append(lines, "", run.Note)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatDistance(meters float64, miles bool) string {
+	if miles {
+		return fmt.Sprintf("%.1f mi", meters/metersPerMile)
+	}
+	return fmt.Sprintf("%.1f km", meters/metersPerKm)
+}
+
+// formatPace formats seconds per meter as "M:SS/km" or "M:SS/mi"
+func formatPace(secsPerMeter float64, miles bool) string {
+	unit, label := metersPerKm, "/km"
+	if miles {
+		unit, label = metersPerMile, "/mi"
+	}
+	secs := int(secsPerMeter*unit + 0.5)
+	return fmt.Sprintf("%d:%02d%s", secs/60, secs%60, label)
+}
+
+// formatClock formats seconds as "M:SS" or "H:MM:SS"
+func formatClock(seconds int) string {
+	h, m, s := seconds/3600, (seconds%3600)/60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// icalTime formats a time in UTC, e.g. "20250303T073000Z"
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icalEscape escapes a TEXT value
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icalFold splits a content line into CRLF-terminated lines of at most
+// icalLineLimit octets, continuing each with a leading space. It never splits
+// a UTF-8 sequence.
+func icalFold(line string) string {
+	var b strings.Builder
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = icalLineLimit - 1 // the leading space counts
+	}
+	b.WriteString(line + "\r\n")
+	return b.String()
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
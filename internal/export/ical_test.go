@@ -0,0 +1,66 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"runner/internal/config"
+)
+
+func TestWriteICal(t *testing.T) {
+	activities := testActivities()
+	activities[0].Activity.StartDate = time.Date(2025, time.March, 3, 7, 30, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	generated := time.Date(2025, time.April, 1, 12, 0, 0, 0, time.UTC)
+	if err := WriteICal(&buf, activities, config.DisplayConfig{DistanceUnit: "km"}, generated); err != nil {
+		t.Fatalf("WriteICal failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:activity-1@runner\r\n",
+		"DTSTAMP:20250401T120000Z\r\n",
+		"DTSTART:20250303T073000Z\r\n",
+		"DTEND:20250303T081600Z\r\n", // elapsed time, 46 minutes
+		"SUMMARY:Tempo\\, hard (10.0 km)\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feed missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Errorf("expected 2 events, got %d", strings.Count(out, "BEGIN:VEVENT"))
+	}
+
+	// Unfolded, the description lists the run's stats, then its tags and note
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, `DESCRIPTION:Distance: 10.0 km\nMoving time: 45:00\nPace: 4:30/km\nAvg HR: 150 bpm\nLoad: 72\nEffort: RPE 7\, felt good\nTags: tempo\, track\n\nWindy on the back straight`+"\r\n") {
+		t.Errorf("unexpected description:\n%s", unfolded)
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > icalLineLimit {
+			t.Errorf("line longer than %d octets: %q", icalLineLimit, line)
+		}
+	}
+}
+
+func TestICalFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 60)
+	folded := icalFold(line)
+	if strings.ReplaceAll(folded, "\r\n ", "") != line+"\r\n" {
+		t.Error("folding changed the content")
+	}
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(part) > icalLineLimit {
+			t.Errorf("folded line has %d octets", len(part))
+		}
+		if !isRuneStart(strings.TrimPrefix(part, " ")[0]) {
+			t.Errorf("fold split a UTF-8 sequence: %q", part)
+		}
+	}
+}
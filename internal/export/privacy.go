@@ -0,0 +1,33 @@
+package export
+
+import (
+	"runner/internal/analysis"
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+// CropPrivacyZones returns a copy of points with the position removed from
+// every point inside one of zones, and how many points it cropped. The rest
+// of each point is kept, so heart rate and pace still line up in time.
+func CropPrivacyZones(points []store.StreamPoint, zones []config.PrivacyZone) ([]store.StreamPoint, int) {
+	if len(zones) == 0 {
+		return points, 0
+	}
+	cropped := make([]store.StreamPoint, len(points))
+	n := 0
+	for i, p := range points {
+		cropped[i] = p
+		if p.Lat == nil || p.Lng == nil {
+			continue
+		}
+		pos := analysis.LatLng{Lat: *p.Lat, Lng: *p.Lng}
+		for _, z := range zones {
+			if analysis.HaversineMeters(pos, analysis.LatLng{Lat: z.Lat, Lng: z.Lng}) <= z.Radius() {
+				cropped[i].Lat, cropped[i].Lng = nil, nil
+				n++
+				break
+			}
+		}
+	}
+	return cropped, n
+}
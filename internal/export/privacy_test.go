@@ -0,0 +1,48 @@
+package export
+
+import (
+	"testing"
+
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+func TestCropPrivacyZones(t *testing.T) {
+	// Home in Boulder; 0.001 degrees of latitude is about 111 m
+	home := config.PrivacyZone{Name: "home", Lat: 40.0150, Lng: -105.2705, RadiusM: 300}
+	lats := []float64{40.0150, 40.0170, 40.0190, 40.0300}
+	lng := -105.2705
+	hr := 150
+
+	var points []store.StreamPoint
+	for i := range lats {
+		points = append(points, store.StreamPoint{TimeOffset: i, Lat: &lats[i], Lng: &lng, Heartrate: &hr})
+	}
+	points = append(points, store.StreamPoint{TimeOffset: 4, Heartrate: &hr}) // no GPS
+
+	cropped, n := CropPrivacyZones(points, []config.PrivacyZone{home})
+	if n != 2 {
+		t.Errorf("cropped %d points, want the 2 within 300 m", n)
+	}
+	for i, p := range cropped {
+		inside := i < 2
+		if inside && (p.Lat != nil || p.Lng != nil) {
+			t.Errorf("point %d kept its position inside the zone", i)
+		}
+		if !inside && i < len(lats) && p.Lat == nil {
+			t.Errorf("point %d lost its position outside the zone", i)
+		}
+		if p.Heartrate == nil || p.TimeOffset != i {
+			t.Errorf("point %d = %+v, want the rest of the point kept", i, p)
+		}
+	}
+	if points[0].Lat == nil {
+		t.Error("CropPrivacyZones modified its input")
+	}
+
+	// The default radius applies when none is set
+	home.RadiusM = 0
+	if _, n := CropPrivacyZones(points, []config.PrivacyZone{home}); n != 3 {
+		t.Errorf("cropped %d points with the default radius, want 3", n)
+	}
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"runner/internal/store"
+)
+
+// streamsHeader names the columns after Strava's stream types, in their
+// stored units
+var streamsHeader = []string{
+	"time", "distance", "velocity_smooth", "heartrate", "cadence",
+	"altitude", "grade_smooth", "lat", "lng",
+}
+
+// WriteStreamsCSV writes one row per stream point as stored, for digging
+// into odd metric values in a spreadsheet. Missing samples are left empty.
+func WriteStreamsCSV(w io.Writer, points []store.StreamPoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(streamsHeader); err != nil {
+		return err
+	}
+	for _, p := range points {
+		row := []string{
+			strconv.Itoa(p.TimeOffset),
+			formatOptional(p.Distance, 1),
+			formatOptional(p.VelocitySmooth, 3),
+			formatOptionalInt(p.Heartrate),
+			formatOptionalInt(p.Cadence),
+			formatOptional(p.Altitude, 1),
+			formatOptional(p.GradeSmooth, 1),
+			formatOptional(p.Lat, 6),
+			formatOptional(p.Lng, 6),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
@@ -0,0 +1,38 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"runner/internal/store"
+)
+
+func TestWriteStreamsCSV(t *testing.T) {
+	dist, vel, alt, grade, lat, lng := 1234.56, 3.2, 1610.25, -1.5, 40.0151, -105.2705
+	hr, cad := 152, 88
+	points := []store.StreamPoint{
+		{TimeOffset: 412, Distance: &dist, VelocitySmooth: &vel, Heartrate: &hr, Cadence: &cad,
+			Altitude: &alt, GradeSmooth: &grade, Lat: &lat, Lng: &lng},
+		{TimeOffset: 413}, // a dropout
+	}
+
+	var buf bytes.Buffer
+	if err := WriteStreamsCSV(&buf, points); err != nil {
+		t.Fatalf("WriteStreamsCSV failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	want := [][]string{
+		streamsHeader,
+		{"412", "1234.6", "3.200", "152", "88", "1610.2", "-1.5", "40.015100", "-105.270500"},
+		{"413", "", "", "", "", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("WriteStreamsCSV() =\n%v\nwant\n%v", records, want)
+	}
+}
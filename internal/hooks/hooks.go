@@ -0,0 +1,116 @@
+// Package hooks runs the commands and webhooks configured for sync events,
+// so results can be piped into chat, home automation or scripts.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"runner/internal/config"
+)
+
+// timeout bounds each command or request, so a hung hook can't stall a
+// scheduled sync
+const timeout = 30 * time.Second
+
+// Payload is the JSON a hook receives
+type Payload struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// Fire runs every hook for event with data as the payload. A hook that fails
+// is logged and otherwise ignored, like a desktop notification.
+func Fire(ctx context.Context, hooks []config.Hook, event string, data any) {
+	var body []byte
+	for _, h := range hooks {
+		if h.Event != event {
+			continue
+		}
+		if body == nil {
+			var err error
+			if body, err = json.Marshal(Payload{Event: event, Time: time.Now(), Data: data}); err != nil {
+				slog.Warn("encoding hook payload", "event", event, "error", err)
+				return
+			}
+		}
+		if err := run(ctx, h, body); err != nil {
+			slog.Warn("running hook", "event", event, "error", err)
+		} else {
+			slog.Debug("ran hook", "event", event)
+		}
+	}
+}
+
+// Wants reports whether any hook listens for event, so payloads that take
+// work to build can be skipped
+func Wants(hooks []config.Hook, event string) bool {
+	for _, h := range hooks {
+		if h.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// run sends one payload to a hook's command or URL
+func run(ctx context.Context, h config.Hook, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if h.Command != "" {
+		return runCommand(ctx, h, body)
+	}
+	return post(ctx, h.URL, body)
+}
+
+// runCommand runs a hook's command through the shell, with the payload on
+// stdin and the event name in RUNNER_EVENT
+func runCommand(ctx context.Context, h config.Hook, body []byte) error {
+	name, args := shell(runtime.GOOS, h.Command)
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), "RUNNER_EVENT="+h.Event)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", h.Command, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// shell returns the program and arguments that run command on goos
+func shell(goos, command string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
+
+// post sends the payload to url, treating any non-2xx status as a failure
+func post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "runner")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", url, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
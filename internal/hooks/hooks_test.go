@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"runner/internal/config"
+)
+
+func TestFire_URL(t *testing.T) {
+	var got []Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		got = append(got, p)
+	}))
+	defer srv.Close()
+
+	hooks := []config.Hook{
+		{Event: config.HookSyncComplete, URL: srv.URL},
+		{Event: config.HookNewPR, URL: srv.URL},
+	}
+	Fire(context.Background(), hooks, config.HookSyncComplete, map[string]int{"activities_stored": 2})
+
+	if len(got) != 1 || got[0].Event != config.HookSyncComplete || got[0].Time.IsZero() {
+		t.Fatalf("payloads = %+v, want one sync_complete", got)
+	}
+	if data, ok := got[0].Data.(map[string]any); !ok || data["activities_stored"] != 2.0 {
+		t.Errorf("data = %v, want activities_stored 2", got[0].Data)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	if err := run(context.Background(), config.Hook{URL: srv.URL}, []byte("{}")); err == nil {
+		t.Error("expected an error for a 401")
+	}
+	if runtime.GOOS != "windows" {
+		if err := run(context.Background(), config.Hook{Command: "exit 3"}, []byte("{}")); err == nil {
+			t.Error("expected an error for a failing command")
+		}
+	}
+}
+
+func TestRun_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "payload")
+	hook := config.Hook{Event: config.HookWeeklySummary, Command: `cat > "$OUT"; echo "$RUNNER_EVENT" >> "$OUT"`}
+	t.Setenv("OUT", out)
+
+	if err := run(context.Background(), hook, []byte(`{"event":"weekly_summary"}`)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"event\":\"weekly_summary\"}weekly_summary\n"; string(b) != want {
+		t.Errorf("command got %q, want %q", b, want)
+	}
+}
+
+func TestShell(t *testing.T) {
+	if name, args := shell("linux", "echo hi"); name != "sh" || !slices.Equal(args, []string{"-c", "echo hi"}) {
+		t.Errorf("linux shell = %s %q", name, args)
+	}
+	if name, args := shell("windows", "echo hi"); name != "cmd" || !slices.Equal(args, []string{"/C", "echo hi"}) {
+		t.Errorf("windows shell = %s %q", name, args)
+	}
+}
+
+func TestWants(t *testing.T) {
+	hooks := []config.Hook{{Event: config.HookNewPR, Command: "true"}}
+	if !Wants(hooks, config.HookNewPR) || Wants(hooks, config.HookWeeklySummary) {
+		t.Error("Wants should only match configured events")
+	}
+}
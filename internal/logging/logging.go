@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log file rotation limits
+const (
+	fileName     = "runner.log"
+	maxFileBytes = 5 << 20 // rotate after 5 MB
+	keepFiles    = 3       // runner.log.1 .. runner.log.3
+)
+
+// Path returns the log file path inside dir
+func Path(dir string) string {
+	return filepath.Join(dir, fileName)
+}
+
+// ParseLevel converts a config level name to a slog level. An empty name
+// means info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Setup opens the rotating log file in dir and makes it the destination of
+// the default slog logger. Entries are written as JSON lines so the log
+// screen can parse them back.
+func Setup(dir string, level slog.Level) (io.Closer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+
+	f, err := OpenRotatingFile(Path(dir), maxFileBytes, keepFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := slog.NewJSONHandler(f, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+	return f, nil
+}
+
+// RotatingFile is a log file that is renamed to path.1 (shifting older
+// files up) once it grows past maxBytes
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+	file     *os.File
+	size     int64
+}
+
+// OpenRotatingFile opens path for appending
+func OpenRotatingFile(path string, maxBytes int64, keep int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxBytes: maxBytes, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would overflow the file
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.keep))
+	for i := r.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("rotating log file: %w", err)
+	}
+
+	return r.open()
+}
+
+// Close closes the current log file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Entry is one parsed log line
+type Entry struct {
+	Time  time.Time
+	Level string
+	Msg   string
+	Attrs []string // "key=value", sorted by key
+}
+
+// ReadRecent returns up to n of the newest entries from the log file at path
+// and its most recent rotation, oldest first. Lines that aren't JSON log
+// entries are skipped. A missing log file returns no entries.
+func ReadRecent(path string, n int) ([]Entry, error) {
+	var entries []Entry
+	for _, p := range []string{path + ".1", path} {
+		fileEntries, err := readEntries(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+func readEntries(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if e, ok := parseEntry(scanner.Bytes()); ok {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading log file: %w", err)
+	}
+	return entries, nil
+}
+
+func parseEntry(line []byte) (Entry, bool) {
+	var fields map[string]any
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return Entry{}, false
+	}
+
+	var e Entry
+	if s, ok := fields[slog.TimeKey].(string); ok {
+		e.Time, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	e.Level, _ = fields[slog.LevelKey].(string)
+	e.Msg, _ = fields[slog.MessageKey].(string)
+	delete(fields, slog.TimeKey)
+	delete(fields, slog.LevelKey)
+	delete(fields, slog.MessageKey)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.Attrs = append(e.Attrs, fmt.Sprintf("%s=%v", k, fields[k]))
+	}
+	return e, true
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	f, err := OpenRotatingFile(path, 100, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile() error = %v", err)
+	}
+	defer f.Close()
+
+	line := []byte(strings.Repeat("x", 59) + "\n") // 60 bytes, so each write after the first rotates
+	for i := 0; i < 4; i++ {
+		if _, err := f.Write(line); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", filepath.Base(p), err)
+		}
+		if info.Size() != 60 {
+			t.Errorf("%s is %d bytes, want 60", filepath.Base(p), info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("expected only 2 rotated files to be kept")
+	}
+}
+
+func TestReadRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	f, err := OpenRotatingFile(path, 1<<20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewJSONHandler(f, nil))
+	logger.Info("first", "n", 1)
+	logger.Warn("second", "path", "/athlete/activities", "status", 429)
+	f.Write([]byte("not json\n"))
+	logger.Error("third")
+	f.Close()
+
+	entries, err := ReadRecent(path, 2)
+	if err != nil {
+		t.Fatalf("ReadRecent() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	e := entries[0]
+	if e.Msg != "second" || e.Level != "WARN" || e.Time.IsZero() {
+		t.Errorf("entries[0] = %+v, want the WARN entry", e)
+	}
+	if strings.Join(e.Attrs, " ") != "path=/athlete/activities status=429" {
+		t.Errorf("entries[0].Attrs = %v", e.Attrs)
+	}
+	if entries[1].Msg != "third" || entries[1].Level != "ERROR" {
+		t.Errorf("entries[1] = %+v, want the ERROR entry", entries[1])
+	}
+
+	// A missing file is not an error
+	entries, err = ReadRecent(filepath.Join(t.TempDir(), "missing.log"), 10)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("ReadRecent() on a missing file = %v, %v", entries, err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"WARN", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
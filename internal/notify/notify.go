@@ -0,0 +1,42 @@
+// Package notify shows desktop notifications, so a sync running in the
+// background can report new runs, records and training warnings.
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+)
+
+// appName is shown as the sender of every notification
+const appName = "runner"
+
+// Send shows a desktop notification, using notify-send on Linux and
+// osascript on macOS
+func Send(title, message string) error {
+	name, args, err := command(runtime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, out)
+	}
+	return nil
+}
+
+// command returns the program and arguments that show a notification on goos
+func command(goos, title, message string) (string, []string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=" + appName, title, message}, nil
+	case "darwin":
+		// AppleScript strings use the same quoting and escapes as Go's
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(appName))
+		if title != "" {
+			script += " subtitle " + strconv.Quote(title)
+		}
+		return "osascript", []string{"-e", script}, nil
+	}
+	return "", nil, fmt.Errorf("desktop notifications aren't supported on %s", goos)
+}
@@ -0,0 +1,23 @@
+package notify
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	name, args, err := command("linux", "New PR", "5K: 21:43")
+	if err != nil || name != "notify-send" || !slices.Equal(args, []string{"--app-name=runner", "New PR", "5K: 21:43"}) {
+		t.Errorf("linux command = %s %q, %v", name, args, err)
+	}
+
+	name, args, err = command("darwin", `Sync "done"`, "2 new runs\nall good")
+	want := `display notification "2 new runs\nall good" with title "runner" subtitle "Sync \"done\""`
+	if err != nil || name != "osascript" || len(args) != 2 || args[1] != want {
+		t.Errorf("darwin command = %s %q, %v, want script %s", name, args, err, want)
+	}
+
+	if _, _, err := command("windows", "x", "y"); err == nil {
+		t.Error("expected an error on an unsupported OS")
+	}
+}
@@ -0,0 +1,102 @@
+// Package paths locates the config file and the data directory. Each comes
+// from, in order: a command-line flag, an environment variable, ~/.runner if
+// an older install created it, or the XDG base directories.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Environment variables overriding the default locations
+const (
+	ConfigEnv = "RUNNER_CONFIG" // config file
+	DataEnv   = "RUNNER_DATA"   // data directory
+)
+
+// appName names the directories under the XDG base directories
+const appName = "runner"
+
+// Set from the --config and --data-dir flags
+var (
+	configFile string
+	dataDir    string
+)
+
+// SetConfigFile makes path the config file, ahead of the environment
+func SetConfigFile(path string) {
+	configFile = path
+}
+
+// SetDataDir makes dir the data directory, ahead of the environment
+func SetDataDir(dir string) {
+	dataDir = dir
+}
+
+// ConfigFile returns the path to config.json. By default it is
+// $XDG_CONFIG_HOME/runner/config.json, falling back to ~/.config.
+func ConfigFile() (string, error) {
+	if path := firstSet(configFile, os.Getenv(ConfigEnv)); path != "" {
+		return filepath.Abs(path)
+	}
+	if dir, ok, err := legacyDir(); err != nil || ok {
+		return filepath.Join(dir, "config.json"), err
+	}
+	base, err := xdgDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appName, "config.json"), nil
+}
+
+// DataDir returns the directory holding the database, its backups, the log
+// and exports. By default it is $XDG_DATA_HOME/runner, falling back to
+// ~/.local/share.
+func DataDir() (string, error) {
+	if dir := firstSet(dataDir, os.Getenv(DataEnv)); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if dir, ok, err := legacyDir(); err != nil || ok {
+		return dir, err
+	}
+	base, err := xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appName), nil
+}
+
+// legacyDir returns ~/.runner, which held everything before the XDG
+// locations, and whether it exists. Installs that have it keep using it.
+func legacyDir() (string, bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false, fmt.Errorf("getting home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".runner")
+	info, err := os.Stat(dir)
+	return dir, err == nil && info.IsDir(), nil
+}
+
+// xdgDir returns the XDG base directory named by env, or its default under
+// the home directory. The spec says to ignore relative paths.
+func xdgDir(env, homeDefault string) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, homeDefault), nil
+}
+
+func firstSet(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
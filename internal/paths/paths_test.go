@@ -0,0 +1,93 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocations(t *testing.T) {
+	tests := []struct {
+		name       string
+		legacy     bool
+		env        map[string]string
+		flagConfig string
+		flagData   string
+		wantConfig string // relative to the home directory
+		wantData   string
+	}{
+		{
+			name:       "XDG defaults",
+			wantConfig: ".config/runner/config.json",
+			wantData:   ".local/share/runner",
+		},
+		{
+			name:       "XDG variables",
+			env:        map[string]string{"XDG_CONFIG_HOME": "/xdg/config", "XDG_DATA_HOME": "/xdg/data"},
+			wantConfig: "/xdg/config/runner/config.json",
+			wantData:   "/xdg/data/runner",
+		},
+		{
+			name:       "relative XDG variables are ignored",
+			env:        map[string]string{"XDG_CONFIG_HOME": "config", "XDG_DATA_HOME": "data"},
+			wantConfig: ".config/runner/config.json",
+			wantData:   ".local/share/runner",
+		},
+		{
+			name:       "existing ~/.runner wins over XDG",
+			legacy:     true,
+			env:        map[string]string{"XDG_CONFIG_HOME": "/xdg/config"},
+			wantConfig: ".runner/config.json",
+			wantData:   ".runner",
+		},
+		{
+			name:       "environment wins over ~/.runner",
+			legacy:     true,
+			env:        map[string]string{ConfigEnv: "/env/runner.json", DataEnv: "/env/data"},
+			wantConfig: "/env/runner.json",
+			wantData:   "/env/data",
+		},
+		{
+			name:       "flags win over the environment",
+			env:        map[string]string{ConfigEnv: "/env/runner.json", DataEnv: "/env/data"},
+			flagConfig: "/flag/runner.json",
+			flagData:   "/flag/data",
+			wantConfig: "/flag/runner.json",
+			wantData:   "/flag/data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			for _, env := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", ConfigEnv, DataEnv} {
+				t.Setenv(env, tt.env[env])
+			}
+			if tt.legacy {
+				if err := os.Mkdir(filepath.Join(home, ".runner"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			SetConfigFile(tt.flagConfig)
+			SetDataDir(tt.flagData)
+			t.Cleanup(func() {
+				SetConfigFile("")
+				SetDataDir("")
+			})
+
+			abs := func(p string) string {
+				if filepath.IsAbs(p) {
+					return p
+				}
+				return filepath.Join(home, p)
+			}
+			if got, err := ConfigFile(); err != nil || got != abs(tt.wantConfig) {
+				t.Errorf("ConfigFile() = %q, %v; want %q", got, err, abs(tt.wantConfig))
+			}
+			if got, err := DataDir(); err != nil || got != abs(tt.wantData) {
+				t.Errorf("DataDir() = %q, %v; want %q", got, err, abs(tt.wantData))
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"html/template"
+	"strings"
+)
+
+// markdown renders the document as GitHub-flavored Markdown
+func (d document) markdown() string {
+	var b strings.Builder
+	b.WriteString("# " + d.Title + "\n")
+
+	for _, s := range d.Sections {
+		b.WriteString("\n")
+		if s.Title != "" {
+			b.WriteString("## " + s.Title + "\n\n")
+		}
+		for _, p := range s.Paragraphs {
+			b.WriteString(p + "\n\n")
+		}
+		if len(s.Rows) == 0 {
+			continue
+		}
+		b.WriteString(markdownRow(s.Header))
+		sep := make([]string, len(s.Header))
+		for i := range sep {
+			sep[i] = "---"
+		}
+		b.WriteString(markdownRow(sep))
+		for _, row := range s.Rows {
+			b.WriteString(markdownRow(row))
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = strings.ReplaceAll(c, "|", `\|`)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 720px; margin: 2em auto; color: #1F2937; }
+h1 { color: #7C3AED; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border-bottom: 1px solid #E5E7EB; padding: 4px 12px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .Sections}}
+{{- if .Title}}
+<h2>{{.Title}}</h2>
+{{- end}}
+{{- range .Paragraphs}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Rows}}
+<table>
+{{- if .Header}}
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{- end}}
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// html renders the document as a standalone HTML page
+func (d document) html() (string, error) {
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
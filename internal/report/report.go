@@ -0,0 +1,249 @@
+// Package report renders training summaries as Markdown or HTML documents
+// for archiving or emailing.
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const (
+	metersPerMile = 1609.34
+	metersPerKm   = 1000.0
+	feetPerMeter  = 3.28084
+)
+
+// Format is an output format for a report
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat parses a format name, accepting "markdown" as an alias for "md"
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("unknown report format %q (want md or html)", s)
+}
+
+// Render renders a monthly report in the given format, formatting distances
+// in the display config's units
+func Render(r *service.MonthlyReport, display config.DisplayConfig, format Format) (string, error) {
+	doc := buildMonthly(r, units{miles: display.DistanceUnit == "mi"})
+	if format == FormatHTML {
+		return doc.html()
+	}
+	return doc.markdown(), nil
+}
+
+// document is a report laid out as titled sections, so the Markdown and HTML
+// renderers produce the same content
+type document struct {
+	Title    string
+	Sections []section
+}
+
+type section struct {
+	Title      string
+	Paragraphs []string
+	Header     []string
+	Rows       [][]string
+}
+
+func buildMonthly(r *service.MonthlyReport, u units) document {
+	doc := document{Title: "Training Report: " + r.Start.Format("January 2006")}
+
+	if r.RunCount == 0 {
+		doc.Sections = append(doc.Sections, section{Paragraphs: []string{"No runs recorded this month."}})
+		return doc
+	}
+
+	distance := u.distance(r.Distance)
+	if r.HasPrevious {
+		distance += fmt.Sprintf(" (%+.0f%% vs last month)", r.DistanceDelta)
+	}
+	doc.Sections = append(doc.Sections, section{
+		Title:  "Summary",
+		Header: []string{"Metric", "Value"},
+		Rows: [][]string{
+			{"Runs", fmt.Sprintf("%d", r.RunCount)},
+			{"Distance", distance},
+			{"Time", formatDuration(r.MovingTime)},
+			{"Elevation", u.elevation(r.Elevation)},
+			{"Longest run", u.distance(r.LongestRun)},
+		},
+	})
+
+	volume := section{
+		Title:      "Mileage",
+		Paragraphs: []string{"Daily: " + Sparkline(r.DailyDistance)},
+		Header:     []string{"Week", "Distance"},
+	}
+	for i, label := range r.WeekLabels {
+		volume.Rows = append(volume.Rows, []string{label, u.distance(r.WeeklyDistance[i])})
+	}
+	doc.Sections = append(doc.Sections, volume)
+
+	load := section{
+		Title:  "Training Load",
+		Header: []string{"Metric", "Value"},
+		Rows: [][]string{
+			{"Total TRIMP", fmt.Sprintf("%.0f", r.TotalTRIMP)},
+			{"Fitness (CTL)", fmt.Sprintf("%.0f", r.CTL)},
+			{"Fatigue (ATL)", fmt.Sprintf("%.0f", r.ATL)},
+			{"Form (TSB)", fmt.Sprintf("%.0f", r.TSB)},
+		},
+	}
+	if r.FormDescription != "" {
+		load.Paragraphs = []string{"At month end: " + r.FormDescription + "."}
+	}
+	doc.Sections = append(doc.Sections, load)
+
+	if r.Zones.TotalSeconds > 0 {
+		zones := section{
+			Title: "Zone Distribution",
+			Paragraphs: []string{fmt.Sprintf("%.0f%% easy (Z1-Z2) / %.0f%% hard (Z3+), target %.0f/%.0f.",
+				r.Zones.LowPct, r.Zones.HighPct, service.PolarizationTargetLowPct, 100-service.PolarizationTargetLowPct)},
+			Header: []string{"Zone", "Time", "Share"},
+		}
+		for i, secs := range r.Zones.ZoneSeconds {
+			zones.Rows = append(zones.Rows, []string{
+				fmt.Sprintf("Z%d", i+1),
+				formatDuration(secs),
+				fmt.Sprintf("%.0f%%", float64(secs)/float64(r.Zones.TotalSeconds)*100),
+			})
+		}
+		doc.Sections = append(doc.Sections, zones)
+	}
+
+	prs := section{Title: "Personal Records"}
+	if len(r.PRs) == 0 {
+		prs.Paragraphs = []string{"No new records this month."}
+	} else {
+		prs.Header = []string{"Date", "Record", "Result", "Improvement", "Activity"}
+		for _, pr := range r.PRs {
+			prs.Rows = append(prs.Rows, []string{
+				pr.AchievedAt.Format("Jan 02"), pr.CategoryLabel, u.prResult(pr), u.prMargin(pr), pr.ActivityName,
+			})
+		}
+	}
+	doc.Sections = append(doc.Sections, prs)
+
+	if len(r.EFValues) > 0 {
+		ef := fmt.Sprintf("Average %.2f", r.AvgEF)
+		if r.PrevAvgEF > 0 {
+			ef += fmt.Sprintf(" (%+.2f vs last month)", r.AvgEF-r.PrevAvgEF)
+		}
+		doc.Sections = append(doc.Sections, section{
+			Title:      "Efficiency Factor",
+			Paragraphs: []string{ef + ".", "Per run: " + Sparkline(r.EFValues)},
+		})
+	}
+
+	return doc
+}
+
+// sparkBlocks are the bar characters of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a row of block characters scaled between the
+// smallest and largest value. Zero values are drawn as the lowest block.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// units formats distances in the user's preferred unit
+type units struct {
+	miles bool
+}
+
+func (u units) distance(meters float64) string {
+	if u.miles {
+		return fmt.Sprintf("%.1f mi", meters/metersPerMile)
+	}
+	return fmt.Sprintf("%.1f km", meters/metersPerKm)
+}
+
+func (u units) elevation(meters float64) string {
+	if u.miles {
+		return fmt.Sprintf("%.0f ft", meters*feetPerMeter)
+	}
+	return fmt.Sprintf("%.0f m", meters)
+}
+
+// pace formats a pace in seconds per mile in the user's unit
+func (u units) pace(secsPerMile float64) string {
+	label := "/mi"
+	if !u.miles {
+		secsPerMile = secsPerMile / metersPerMile * metersPerKm
+		label = "/km"
+	}
+	secs := int(math.Round(secsPerMile))
+	return fmt.Sprintf("%d:%02d%s", secs/60, secs%60, label)
+}
+
+func (u units) prResult(pr service.ReportPR) string {
+	switch pr.Mode {
+	case store.CompareDistance:
+		return u.distance(pr.DistanceMeters)
+	case store.ComparePace:
+		if pr.PacePerMile > 0 {
+			return u.pace(pr.PacePerMile)
+		}
+	}
+	return pr.Time
+}
+
+func (u units) prMargin(pr service.ReportPR) string {
+	if pr.Margin <= 0 {
+		return "first"
+	}
+	switch pr.Mode {
+	case store.CompareDistance:
+		return "+" + u.distance(pr.Margin)
+	case store.ComparePace:
+		if !u.miles {
+			return fmt.Sprintf("%.0fs/km faster", pr.Margin/metersPerMile*metersPerKm)
+		}
+		return fmt.Sprintf("%.0fs/mi faster", pr.Margin)
+	}
+	return fmt.Sprintf("%.0fs faster", pr.Margin)
+}
+
+// formatDuration formats seconds as "Xh Ym"
+func formatDuration(seconds int) string {
+	h := seconds / 3600
+	m := (seconds % 3600) / 60
+	if h > 0 {
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
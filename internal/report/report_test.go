@@ -0,0 +1,107 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{0, 3.5, 7}); got != "▁▄█" {
+		t.Errorf("Sparkline() = %q, want ▁▄█", got)
+	}
+	if got := Sparkline([]float64{2, 2}); got != "▁▁" {
+		t.Errorf("Sparkline() of flat values = %q, want ▁▁", got)
+	}
+	if Sparkline(nil) != "" {
+		t.Error("expected an empty sparkline for no values")
+	}
+}
+
+func testReport() *service.MonthlyReport {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.Local)
+	return &service.MonthlyReport{
+		Year:           2025,
+		Month:          time.March,
+		Start:          start,
+		RunCount:       2,
+		Distance:       28000,
+		MovingTime:     9200,
+		LongestRun:     20000,
+		HasPrevious:    true,
+		DistanceDelta:  180,
+		DailyDistance:  []float64{0, 0, 8000},
+		WeekStarts:     []time.Time{start.AddDate(0, 0, -5)},
+		WeekLabels:     []string{"Feb 24"},
+		WeeklyDistance: []float64{8000},
+		EFValues:       []float64{1.3, 1.26},
+		AvgEF:          1.28,
+		PRs: []service.ReportPR{{
+			CategoryLabel:      "10K",
+			PRProgressionPoint: service.PRProgressionPoint{AchievedAt: start.AddDate(0, 0, 15), ActivityName: "Fast <Run>", Time: "45:00", Margin: 30},
+		}},
+	}
+}
+
+func TestRender_Markdown(t *testing.T) {
+	out, err := Render(testReport(), config.DisplayConfig{DistanceUnit: "km"}, FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"# Training Report: March 2025",
+		"| Distance | 28.0 km (+180% vs last month) |",
+		"| Time | 2h 33m |",
+		"Daily: ▁▁█",
+		"| Mar 16 | 10K | 45:00 | 30s faster | Fast <Run> |",
+		"Average 1.28.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Zone Distribution") {
+		t.Error("expected no zone section without HR data")
+	}
+
+	miles, _ := Render(testReport(), config.DisplayConfig{DistanceUnit: "mi"}, FormatMarkdown)
+	if !strings.Contains(miles, "17.4 mi") {
+		t.Error("expected distances in miles")
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	out, err := Render(testReport(), config.DisplayConfig{DistanceUnit: "km"}, FormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "<h1>Training Report: March 2025</h1>") {
+		t.Errorf("missing title:\n%s", out)
+	}
+	if !strings.Contains(out, "Fast &lt;Run&gt;") {
+		t.Error("expected activity names to be escaped")
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	r := &service.MonthlyReport{Start: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local)}
+	out, _ := Render(r, config.DisplayConfig{}, FormatMarkdown)
+	if !strings.Contains(out, "No runs recorded this month.") {
+		t.Errorf("unexpected empty report:\n%s", out)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"md": FormatMarkdown, "Markdown": FormatMarkdown, "html": FormatHTML} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+
+	"runner/internal/store"
+)
+
+// streamStatsRules are AggregateStreamStats' thresholds, for summarizing
+// streams in the store without loading them
+var streamStatsRules = store.StreamStatsRules{
+	MinHeartrate:      MinValidHeartrate,
+	MaxHeartrate:      MaxValidHeartrate,
+	MinMovingSpeed:    MinSpeedForPace,
+	CadenceMultiplier: StravaCadenceMultiplier,
+}
+
+// activityStreamStats summarizes an activity's streams for the stream stats
+// cache. An activity without streams gets zeros.
+func activityStreamStats(activityID int64, streams []store.StreamPoint) *store.ActivityStreamStats {
+	stats := AggregateStreamStats(streams)
+	return &store.ActivityStreamStats{
+		ActivityID:     activityID,
+		MovingTime:     stats.MovingTime,
+		MovingDistance: stats.TotalDistance,
+		HRSum:          stats.HRSum,
+		HRCount:        stats.HRCount,
+		CadenceSum:     stats.CadenceSum,
+		CadenceCount:   stats.CadenceCount,
+		MaxHR:          stats.MaxHR,
+	}
+}
+
+// backfillStreamStats caches stream stats for analyzed activities that don't
+// have them yet, which after the first run is only a quick check. A
+// read-only database is left as it is; runs the writer hasn't cached count
+// as having no streams.
+func (q *QueryService) backfillStreamStats() error {
+	if q.store.ReadOnly() {
+		return nil
+	}
+	ids, err := q.store.ListActivitiesMissingStreamStats()
+	if err != nil {
+		return fmt.Errorf("listing activities without stream stats: %w", err)
+	}
+	statsMap, err := q.store.ComputeStreamStatsForActivities(ids, streamStatsRules)
+	if err != nil {
+		return fmt.Errorf("computing stream stats: %w", err)
+	}
+	for _, id := range ids {
+		if err := q.store.SaveActivityStreamStats(statsMap[id]); err != nil {
+			return fmt.Errorf("saving stream stats for %d: %w", id, err)
+		}
+	}
+	return nil
+}
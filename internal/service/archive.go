@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"runner/internal/store"
+)
+
+// ArchiveSeason moves the runs before before, a day on the local clock, into
+// the archive database at path and returns how many were moved. Each side's
+// weekly summaries are rebuilt so the week split by the cutoff is counted
+// once in each, and all-time browsing adds the two up.
+func (q *QueryService) ArchiveSeason(before time.Time, path string) (int, error) {
+	cutoff := calendarDay(before)
+	moved, err := q.store.ArchiveActivities(cutoff, path)
+	if err != nil || moved == 0 {
+		return moved, err
+	}
+
+	archive, err := store.OpenPath(path)
+	if err != nil {
+		return moved, fmt.Errorf("opening archive: %w", err)
+	}
+	defer archive.Close()
+	archive.SetUTCDays(q.store.UTCDays())
+	archive.SetWeekStart(q.store.WeekStart())
+	if err := archive.DeleteAllWeeklySummaries(); err != nil {
+		return moved, fmt.Errorf("clearing archive weekly summaries: %w", err)
+	}
+	if err := rebuildAllWeeklySummaries(archive); err != nil {
+		return moved, fmt.Errorf("summarizing archive weeks: %w", err)
+	}
+
+	// Weeks before the cutoff are left without runs and removed
+	summaries, err := q.store.GetWeeklySummaries(time.Time{}, q.weekStart(cutoff))
+	if err != nil {
+		return moved, err
+	}
+	weeks := make([]time.Time, len(summaries))
+	for i, s := range summaries {
+		weeks[i] = s.WeekStart
+	}
+	if err := rebuildWeeklySummaries(q.store, weeks); err != nil {
+		return moved, fmt.Errorf("rebuilding weekly summaries: %w", err)
+	}
+	return moved, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"runner/internal/config"
+)
+
+// Sync state keys recording how the stored weekly summaries and fitness
+// trends were bucketed
+const (
+	dayBucketsStateKey = "day_buckets"
+	weekStartStateKey  = "week_start"
+)
+
+// SetBucketing chooses how runs are grouped into days, weeks and months:
+// the clock (the local time where each run started, or UTC) and the first
+// day of the week. Stored weekly summaries and fitness trends bucketed
+// another way are rebuilt, unless the database is read-only.
+func (q *QueryService) SetBucketing(analysisCfg config.AnalysisConfig) error {
+	q.store.SetUTCDays(analysisCfg.UTCDays())
+	q.store.SetWeekStart(analysisCfg.FirstWeekday())
+	if q.store.ReadOnly() {
+		return nil
+	}
+
+	dayBuckets := config.DayBucketsLocal
+	if analysisCfg.UTCDays() {
+		dayBuckets = config.DayBucketsUTC
+	}
+	weekStart := config.WeekStartMonday
+	if analysisCfg.FirstWeekday() == time.Sunday {
+		weekStart = config.WeekStartSunday
+	}
+
+	storedDays, err := q.store.GetSyncState(dayBucketsStateKey)
+	if err != nil {
+		return fmt.Errorf("reading day buckets: %w", err)
+	}
+	storedWeeks, err := q.store.GetSyncState(weekStartStateKey)
+	if err != nil {
+		return fmt.Errorf("reading week start: %w", err)
+	}
+	if storedWeeks == "" {
+		storedWeeks = config.WeekStartMonday // weeks always started Monday before
+	}
+	if storedDays == dayBuckets && storedWeeks == weekStart {
+		return nil
+	}
+
+	// Weekly summaries refill on next use
+	if err := q.store.DeleteAllWeeklySummaries(); err != nil {
+		return fmt.Errorf("clearing weekly summaries: %w", err)
+	}
+	if storedDays != dayBuckets {
+		if err := rebuildFitnessTrends(q.store, time.Now()); err != nil {
+			return fmt.Errorf("rebuilding fitness trends: %w", err)
+		}
+	}
+	if err := q.store.SetSyncState(dayBucketsStateKey, dayBuckets); err != nil {
+		return err
+	}
+	return q.store.SetSyncState(weekStartStateKey, weekStart)
+}
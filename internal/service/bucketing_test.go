@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+func TestSetDayBuckets(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// A Sunday evening run out west is already Monday in UTC
+	run := &store.Activity{
+		ID:             1,
+		AthleteID:      12345,
+		Name:           "Evening Run",
+		Type:           "Run",
+		StartDate:      time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC),
+		StartDateLocal: time.Date(2024, 1, 14, 22, 0, 0, 0, time.UTC),
+		Distance:       8000,
+		MovingTime:     2400,
+		ElapsedTime:    2460,
+		StreamsSynced:  true,
+	}
+	if err := db.UpsertActivity(run); err != nil {
+		t.Fatalf("UpsertActivity() error = %v", err)
+	}
+	createTestMetrics(t, db, 1, nil, floatPtr(60))
+
+	svc := NewQueryService(db, testAthleteConfig())
+	sunday := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	monday := sunday.AddDate(0, 0, 1)
+	tests := []struct {
+		name string
+		cfg  config.AnalysisConfig
+		week time.Time
+	}{
+		{"local", config.AnalysisConfig{}, monday.AddDate(0, 0, -7)},
+		{"utc", config.AnalysisConfig{DayBuckets: config.DayBucketsUTC}, monday},
+		{"local from Sunday", config.AnalysisConfig{WeekStart: config.WeekStartSunday}, sunday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.SetBucketing(tt.cfg); err != nil {
+				t.Fatalf("SetBucketing() error = %v", err)
+			}
+
+			// Summaries bucketed another way were cleared for a refill
+			if count, _ := db.CountWeeklySummaries(); count != 0 {
+				t.Fatalf("weekly summaries after switching = %d, want 0", count)
+			}
+			if err := rebuildAllWeeklySummaries(db); err != nil {
+				t.Fatalf("rebuildAllWeeklySummaries() error = %v", err)
+			}
+			summaries, err := db.GetWeeklySummaries(monday.AddDate(0, 0, -7), monday)
+			if err != nil {
+				t.Fatalf("GetWeeklySummaries() error = %v", err)
+			}
+			if len(summaries) != 1 || !summaries[0].WeekStart.Equal(tt.week) {
+				t.Errorf("GetWeeklySummaries() = %+v, want only the week of %s", summaries, tt.week.Format("Jan 02"))
+			}
+			if got := weekStartOf(db, *run); !got.Equal(tt.week) {
+				t.Errorf("weekStartOf() = %v, want %v", got, tt.week)
+			}
+		})
+	}
+
+	// Applying the same settings again keeps the summaries
+	cfg := tests[len(tests)-1].cfg
+	if err := svc.SetBucketing(cfg); err != nil {
+		t.Fatalf("SetBucketing() error = %v", err)
+	}
+	if count, _ := db.CountWeeklySummaries(); count != 1 {
+		t.Errorf("weekly summaries after applying the same settings = %d, want 1", count)
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	wednesday := time.Date(2024, 1, 17, 18, 30, 0, 0, time.UTC)
+	if got, want := startOfWeek(wednesday, time.Monday), time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("startOfWeek(Monday) = %v, want %v", got, want)
+	}
+	if got, want := startOfWeek(wednesday, time.Sunday), time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("startOfWeek(Sunday) = %v, want %v", got, want)
+	}
+
+	// Both weeks around Jan 17 are ISO week 3, and the week of Dec 30, 2024 is week 1 of 2025
+	for _, start := range []time.Time{startOfWeek(wednesday, time.Monday), startOfWeek(wednesday, time.Sunday)} {
+		if got := isoWeekLabel(start); got != "W03" {
+			t.Errorf("isoWeekLabel(%s) = %q, want W03", start.Format("Mon Jan 02"), got)
+		}
+	}
+	if got := isoWeekLabel(time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)); got != "W01" {
+		t.Errorf("isoWeekLabel(Dec 30, 2024) = %q, want W01", got)
+	}
+}
@@ -8,6 +8,7 @@ const (
 
 	// Unit conversions
 	MetersPerMile           = 1609.34
+	MetersPerKm             = 1000.0
 	StravaCadenceMultiplier = 2.0 // Strava reports single-leg cadence
 
 	// Time windows
@@ -16,6 +17,80 @@ const (
 	EFHistoryDays       = 90
 	ChartWeeks          = 12
 
+	// Trend projection: weeks of history fitted and weeks projected ahead
+	TrendHistoryWeeks    = 16
+	TrendProjectionWeeks = 12
+	TrendLoessSpan       = 0.5
+	TrendMinEffortSecs   = 600 // shortest cached effort that counts toward VDOT
+
+	// Sync batching: activities per Strava page and streams per sync
+	ActivitiesPerPage = 100
+	StreamBatchSize   = 50
+
+	// With privacy.sync_social on, the activity list is fetched again from
+	// this many days before the last sync so recent runs' kudos and comment
+	// counts keep up
+	SocialRefreshDays = 7
+
+	// Typical round trip of one Strava request including the rate limiter's
+	// spacing, used to estimate sync duration
+	EstimatedRequestMillis = 400
+
+	// VO2max estimation: runs from the last VO2maxWindowDays that lasted at
+	// least VO2maxMinRunSecs on mostly flat ground
+	VO2maxWindowDays    = 42
+	VO2maxMinRuns       = 3
+	VO2maxMinRunSecs    = 1200
+	VO2maxMaxClimbPerKm = 10.0 // meters of climbing per km
+
+	// Weekly goal: completed weeks the consistency score covers, and weeks of
+	// history searched for the goal streak
+	GoalConsistencyWeeks = 12
+	GoalStreakWeeks      = 104
+
+	// Days without a rest day before the dashboard warns, unless configured
+	DefaultRestDayWarningDays = 10
+
+	// Injury log: weeks charted, the worst severity, and days of history
+	// before the acute:chronic load ratio is trusted
+	InjuryChartWeeks  = 26
+	MaxInjurySeverity = 5
+	ACWRWarmupDays    = 42
+
+	// An acute:chronic load ratio above this is the injury danger zone
+	ACWRWarning = 1.5
+
+	// Readiness compares resting HR and HRV with their average over the
+	// previous ReadinessBaselineDays, once there are enough days logged
+	ReadinessBaselineDays = 28
+	ReadinessMinBaseline  = 3
+
+	// A best effort this much faster than the standing record from another
+	// run is marked unverified until the athlete confirms it
+	MaxRecordImprovement = 0.10
+
+	// A best effort imported from Strava that differs from the one found in
+	// the streams by more than this is marked unverified
+	StravaEffortTolerance = 0.10
+
+	// Durability scatter: runs shorter than this say little about how long
+	// the aerobic base holds
+	DurabilityMinRunSecs = 1800
+
+	// Performance score: runs shorter than this are too short to carry to
+	// a 10K with Riegel's formula
+	PerformanceMinRunSecs = 600
+
+	// Training plan: the build starts from the average of the last
+	// PlanBaseWeeks completed weeks, and a completed week under PlanMissedPct
+	// of its target is missed, rebuilding the rest of the plan from it
+	PlanBaseWeeks        = 4
+	PlanMissedPct        = 80
+	DefaultTaperWeeks    = 3
+	MaxTaperWeeks        = 6
+	DefaultCutbackEvery  = 4
+	MaxTrainingPlanWeeks = 52
+
 	// Pagination limits
 	RecentActivitiesLimit     = 10
 	HistoricalActivitiesLimit = 200
@@ -27,6 +102,9 @@ const (
 	// Partial mile threshold (0.1 miles in meters)
 	PartialMileThreshold = 160
 
+	// Partial kilometer threshold (0.1 km in meters)
+	PartialKmThreshold = 100
+
 	// Minimum speed for pace calculation (m/s) - filters out stopped time
 	MinSpeedForPace = 0.5
 
@@ -36,3 +114,7 @@ const (
 
 // HRZoneThresholds defines the upper bound percentage of max HR for each zone
 var HRZoneThresholds = []float64{0.6, 0.7, 0.8, 0.9, 1.0}
+
+// CadenceBandLimits defines the upper bound (spm, exclusive) of each cadence
+// band except the last, which is open-ended
+var CadenceBandLimits = []float64{160, 170, 180}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"log/slog"
+
+	"runner/internal/analysis"
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+// customMetricComputers returns the metrics registered in Go code followed
+// by the expression metrics in the config. An expression that doesn't parse
+// is logged and skipped, so one typo doesn't stop a sync.
+func customMetricComputers(metrics []config.CustomMetric) []analysis.MetricComputer {
+	computers := analysis.RegisteredMetrics()
+	for _, m := range metrics {
+		computer, err := analysis.NewExprMetric(m.Name, m.Label, m.Expr)
+		if err != nil {
+			slog.Warn("skipping custom metric", "name", m.Name, "err", err)
+			continue
+		}
+		computers = append(computers, computer)
+	}
+	return computers
+}
+
+// customMetricValues computes the custom metrics for one activity, in the
+// order of computers
+func customMetricValues(in analysis.MetricInput, computers []analysis.MetricComputer) []store.CustomMetric {
+	values := analysis.ComputeCustomMetrics(in, computers)
+	metrics := make([]store.CustomMetric, 0, len(values))
+	for _, c := range computers {
+		if v, ok := values[c.Name()]; ok {
+			metrics = append(metrics, store.CustomMetric{Name: c.Name(), Label: c.Label(), Value: v})
+		}
+	}
+	return metrics
+}
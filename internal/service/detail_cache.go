@@ -0,0 +1,93 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+)
+
+// detailCacheSize is how many activity details are kept, enough for going
+// back and forth between the list and the last few runs opened
+const detailCacheSize = 16
+
+// detailCache keeps recently built activity details, least recently used
+// first out. Entries record the store generation they were built at and
+// are dropped once the store has written since, as after a sync, a
+// recompute or an edit.
+type detailCache struct {
+	mu      sync.Mutex
+	entries map[int64]*list.Element
+	order   *list.List // of *detailEntry, most recently used first
+}
+
+type detailEntry struct {
+	id         int64
+	generation uint64
+	detail     *ActivityDetail
+}
+
+func newDetailCache() *detailCache {
+	return &detailCache{
+		entries: make(map[int64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached detail for id if it was built at generation
+func (c *detailCache) get(id int64, generation uint64) (*ActivityDetail, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*detailEntry)
+	if entry.generation != generation {
+		c.order.Remove(el)
+		delete(c.entries, id)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.detail, true
+}
+
+// put caches detail for id, evicting the least recently used past the size
+func (c *detailCache) put(id int64, generation uint64, detail *ActivityDetail) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[id]; ok {
+		el.Value = &detailEntry{id: id, generation: generation, detail: detail}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[id] = c.order.PushFront(&detailEntry{id: id, generation: generation, detail: detail})
+	if c.order.Len() > detailCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*detailEntry).id)
+	}
+}
+
+// remove drops id, or everything without one
+func (c *detailCache) remove(ids ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(ids) == 0 {
+		c.entries = make(map[int64]*list.Element)
+		c.order.Init()
+		return
+	}
+	for _, id := range ids {
+		if el, ok := c.entries[id]; ok {
+			c.order.Remove(el)
+			delete(c.entries, id)
+		}
+	}
+}
+
+// InvalidateActivityDetails drops cached activity details for ids, or all
+// of them without any. Writes through this process's store invalidate the
+// cache on their own; this is for changes it can't see, such as a sync run
+// by another process.
+func (q *QueryService) InvalidateActivityDetails(ids ...int64) {
+	q.details.remove(ids...)
+}
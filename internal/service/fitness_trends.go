@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+// fitnessTrendDateFormat is how fitness_trends.date is stored
+const fitnessTrendDateFormat = "2006-01-02"
+
+// loadModelStateKey is the sync state key recording the load model the
+// stored fitness trends were built with, so sync keeps using it
+const loadModelStateKey = "load_model"
+
+// runLoad returns a run's load under a config.LoadModels model, or nil when
+// it has none. Session-RPE falls back to TRIMP for runs without an RPE, so
+// a gap in logging doesn't read as rest.
+func runLoad(model string, trimp, hrss *float64, rpe *int, movingTime int) *float64 {
+	switch {
+	case model == config.LoadModelHRSS:
+		return hrss
+	case model == config.LoadModelSessionRPE && rpe != nil:
+		load := analysis.SessionRPE(*rpe, movingTime)
+		return &load
+	}
+	return trimp
+}
+
+// activityRPEs returns the logged RPEs when the load model needs them
+func (q *QueryService) activityRPEs() (map[int64]store.ActivityRPE, error) {
+	if q.loadModel != config.LoadModelSessionRPE {
+		return nil, nil
+	}
+	return q.store.GetActivityRPEs()
+}
+
+// activityLoad returns a run's load under the chosen model, with rpes from
+// activityRPEs
+func (q *QueryService) activityLoad(a store.Activity, m store.ActivityMetrics, rpes map[int64]store.ActivityRPE) *float64 {
+	var rpe *int
+	if v, ok := rpes[a.ID]; ok {
+		rpe = &v.RPE
+	}
+	return runLoad(q.loadModel, m.TRIMP, m.HRSS, rpe, a.MovingTime)
+}
+
+// SetLoadModel chooses the per-run load fitness, fatigue and form are built
+// from, one of config.LoadModels. Stored fitness trends built with another
+// model are rebuilt, unless the database is read-only. HRSS stored before it
+// was scaled to threshold is rescaled first, with the athlete's zones.
+func (q *QueryService) SetLoadModel(model string) error {
+	q.loadModel = model
+	if q.store.ReadOnly() {
+		return nil
+	}
+
+	rescaled, err := q.store.RescaleLegacyHRSS(analysis.HRSSPerTRIMP(athleteZones(q.athleteCfg)))
+	if err != nil {
+		return err
+	}
+	stored, err := q.store.GetSyncState(loadModelStateKey)
+	if err != nil {
+		return fmt.Errorf("reading load model: %w", err)
+	}
+	if stored == "" {
+		stored = config.LoadModelTRIMP // trends were always built from TRIMP before
+	}
+	if stored == model && (rescaled == 0 || model != config.LoadModelHRSS) {
+		return nil
+	}
+	if err := q.store.SetSyncState(loadModelStateKey, model); err != nil {
+		return err
+	}
+	if err := rebuildFitnessTrends(q.store, time.Now()); err != nil {
+		return fmt.Errorf("rebuilding fitness trends: %w", err)
+	}
+	return nil
+}
+
+// rebuildFitnessTrends recomputes the daily fitness trends from every
+// analyzed run, running through to now so rest days since the last run
+// still move fatigue, monotony and strain. Loads follow the model last
+// chosen with SetLoadModel.
+func rebuildFitnessTrends(st *store.Store, now time.Time) error {
+	runs, err := st.ListTrainingLoads()
+	if err != nil {
+		return fmt.Errorf("listing training loads: %w", err)
+	}
+	model, err := st.GetSyncState(loadModelStateKey)
+	if err != nil {
+		return fmt.Errorf("reading load model: %w", err)
+	}
+	if len(runs) == 0 {
+		return st.ReplaceFitnessTrends(nil)
+	}
+
+	// A zero load today carries the trends up to date
+	loads := []analysis.DailyLoad{{Date: st.BucketClock(now)}}
+	type dayTotals struct {
+		runs     int
+		distance float64
+		time     int
+	}
+	days := make(map[string]dayTotals)
+	for _, r := range runs {
+		if load := runLoad(model, r.TRIMP, r.HRSS, r.RPE, r.MovingTime); load != nil {
+			loads = append(loads, analysis.DailyLoad{Date: r.BucketDate, TRIMP: *load})
+		}
+		key := r.BucketDate.Format(fitnessTrendDateFormat)
+		d := days[key]
+		d.runs++
+		d.distance += r.Distance
+		d.time += r.MovingTime
+		days[key] = d
+	}
+
+	metrics := analysis.CalculateFitnessTrend(loads)
+	trends := make([]store.FitnessTrend, len(metrics))
+	for i, m := range metrics {
+		trend := store.FitnessTrend{
+			Date: m.Date.Format(fitnessTrendDateFormat),
+			CTL:  &metrics[i].CTL,
+			ATL:  &metrics[i].ATL,
+			TSB:  &metrics[i].TSB,
+		}
+		if m.Monotony > 0 {
+			trend.Monotony7d = &metrics[i].Monotony
+			trend.Strain7d = &metrics[i].Strain
+		}
+		for back := 0; back < analysis.MonotonyDays; back++ {
+			d := days[m.Date.AddDate(0, 0, -back).Format(fitnessTrendDateFormat)]
+			trend.RunCount7d += d.runs
+			trend.TotalDistance7d += d.distance
+			trend.TotalTime7d += d.time
+		}
+		trends[i] = trend
+	}
+	return st.ReplaceFitnessTrends(trends)
+}
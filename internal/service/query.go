@@ -1,19 +1,41 @@
 package service
 
 import (
+	"slices"
+
+	"runner/internal/analysis"
 	"runner/internal/config"
 	"runner/internal/store"
 )
 
-// QueryService provides read-only queries for the TUI
+// QueryService provides queries for the TUI, along with the few local edits
+// (tags, notes, manual runs, exclusions) the user can make to their own data
 type QueryService struct {
-	store      *store.Store
-	athleteCfg config.AthleteConfig
+	store          *store.Store
+	athleteCfg     config.AthleteConfig
+	riegelExponent float64
+	restDayWarning int
+	rampThreshold  float64 // percent
+	weekNumbers    bool
+	loadModel      string // one of config.LoadModels
+	details        *detailCache
 }
 
 // NewQueryService creates a new query service with athlete config
 func NewQueryService(store *store.Store, athleteCfg config.AthleteConfig) *QueryService {
-	// Apply defaults if not set
+	return &QueryService{
+		store:          store,
+		athleteCfg:     withAthleteDefaults(athleteCfg),
+		riegelExponent: analysis.DefaultRiegelExponent,
+		restDayWarning: DefaultRestDayWarningDays,
+		rampThreshold:  analysis.DefaultRampThresholdPct,
+		loadModel:      config.LoadModelTRIMP,
+		details:        newDetailCache(),
+	}
+}
+
+// withAthleteDefaults fills in the HR values that aren't set
+func withAthleteDefaults(athleteCfg config.AthleteConfig) config.AthleteConfig {
 	if athleteCfg.MaxHR == 0 {
 		athleteCfg.MaxHR = DefaultMaxHR
 	}
@@ -23,10 +45,49 @@ func NewQueryService(store *store.Store, athleteCfg config.AthleteConfig) *Query
 	if athleteCfg.ThresholdHR == 0 {
 		athleteCfg.ThresholdHR = 165
 	}
-	return &QueryService{store: store, athleteCfg: athleteCfg}
+	return athleteCfg
+}
+
+// SetAthleteConfig replaces the athlete settings, as after editing them in
+// settings. Metrics already stored keep the values they were computed with.
+func (q *QueryService) SetAthleteConfig(athleteCfg config.AthleteConfig) {
+	q.athleteCfg = withAthleteDefaults(athleteCfg)
+	q.details.remove() // HR zones follow the configured max and threshold
+}
+
+// SetRiegelExponent sets the fatigue exponent of the Riegel race predictor.
+// Zero keeps the default.
+func (q *QueryService) SetRiegelExponent(exponent float64) {
+	if exponent > 0 && exponent != q.riegelExponent {
+		q.riegelExponent = exponent
+		q.details.remove() // Performance scores are built with it
+	}
+}
+
+// SetRestDayWarningDays sets how many days may pass without a rest day
+// before the dashboard warns. Zero keeps the default; negative disables it.
+func (q *QueryService) SetRestDayWarningDays(days int) {
+	if days != 0 {
+		q.restDayWarning = days
+	}
+}
+
+// SetRampThreshold sets how far (%) a week's distance may rise above the
+// week before before it's flagged. Zero keeps the default.
+func (q *QueryService) SetRampThreshold(pct float64) {
+	if pct > 0 {
+		q.rampThreshold = pct
+	}
 }
 
-// GetActivitiesList returns paginated activities with metrics
+// SetWeekNumbers labels weeks by ISO week number (W03) instead of the date
+// they start.
+func (q *QueryService) SetWeekNumbers(on bool) {
+	q.weekNumbers = on
+}
+
+// GetActivitiesList returns paginated activities with metrics, skipping
+// activities excluded from analysis
 func (q *QueryService) GetActivitiesList(limit, offset int) ([]ActivityWithMetrics, error) {
 	activities, metrics, err := q.store.GetActivitiesWithMetrics(limit, offset)
 	if err != nil {
@@ -43,6 +104,97 @@ func (q *QueryService) GetActivitiesList(limit, offset int) ([]ActivityWithMetri
 	return result, nil
 }
 
+// SearchActivities returns paginated activities with metrics matching the filter,
+// in the given order
+func (q *QueryService) SearchActivities(filter store.ActivityFilter, order store.ActivitySort, limit, offset int) ([]ActivityWithMetrics, error) {
+	activities, metrics, err := q.store.SearchActivitiesWithMetrics(filter, order, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return q.withSocial(withMetrics(activities, metrics))
+}
+
+// SearchActivitiesAfter returns up to limit activities that follow last in
+// the list order, where pos is last's position in the list. A nil last starts
+// from the top. Date orders page from last's start date, so scrolling deep
+// into years of runs costs no more than the first page; other orders fall
+// back to pos as an offset.
+func (q *QueryService) SearchActivitiesAfter(filter store.ActivityFilter, order store.ActivitySort, last *store.Activity, pos, limit int) ([]ActivityWithMetrics, error) {
+	if !sortsByDate(order) {
+		if last == nil {
+			return q.SearchActivities(filter, order, limit, 0)
+		}
+		return q.SearchActivities(filter, order, limit, pos+1)
+	}
+
+	var cursor *store.ActivityCursor
+	if last != nil {
+		cursor = &store.ActivityCursor{StartDate: last.StartDate, ID: last.ID}
+	}
+	activities, metrics, err := q.store.SearchActivitiesByDate(filter, cursor, !order.Ascending, limit)
+	if err != nil {
+		return nil, err
+	}
+	return q.withSocial(withMetrics(activities, metrics))
+}
+
+// SearchActivitiesBefore returns up to limit activities that come before
+// first in the list order, in that order, where pos is first's position in
+// the list. It pages like SearchActivitiesAfter.
+func (q *QueryService) SearchActivitiesBefore(filter store.ActivityFilter, order store.ActivitySort, first store.Activity, pos, limit int) ([]ActivityWithMetrics, error) {
+	if !sortsByDate(order) {
+		start := max(0, pos-limit)
+		return q.SearchActivities(filter, order, pos-start, start)
+	}
+
+	cursor := &store.ActivityCursor{StartDate: first.StartDate, ID: first.ID}
+	activities, metrics, err := q.store.SearchActivitiesByDate(filter, cursor, order.Ascending, limit)
+	if err != nil {
+		return nil, err
+	}
+	// The store walks away from the cursor; the list reads toward it
+	result := withMetrics(activities, metrics)
+	slices.Reverse(result)
+	return q.withSocial(result)
+}
+
+// sortsByDate reports whether order is by start date, which can be paged by
+// keyset
+func sortsByDate(order store.ActivitySort) bool {
+	return order.Field == "" || order.Field == store.SortByDate
+}
+
+// withMetrics pairs activities with their metrics
+func withMetrics(activities []store.Activity, metrics []store.ActivityMetrics) []ActivityWithMetrics {
+	result := make([]ActivityWithMetrics, len(activities))
+	for i := range activities {
+		result[i] = ActivityWithMetrics{
+			Activity: activities[i],
+			Metrics:  metrics[i],
+		}
+	}
+	return result
+}
+
+// withSocial attaches the kudos and comment counts stored for activities
+func (q *QueryService) withSocial(activities []ActivityWithMetrics) ([]ActivityWithMetrics, error) {
+	social, err := q.store.GetAllActivitySocial()
+	if err != nil {
+		return nil, err
+	}
+	for i := range activities {
+		if s, ok := social[activities[i].Activity.ID]; ok {
+			activities[i].Social = &s
+		}
+	}
+	return activities, nil
+}
+
+// CountSearchActivities returns the number of activities matching the filter
+func (q *QueryService) CountSearchActivities(filter store.ActivityFilter) (int, error) {
+	return q.store.CountSearchActivitiesWithMetrics(filter)
+}
+
 // GetActivityDetail returns detailed information about a single activity
 func (q *QueryService) GetActivityDetail(id int64) (*ActivityWithMetrics, []store.StreamPoint, error) {
 	activity, err := q.store.GetActivity(id)
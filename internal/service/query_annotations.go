@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+// ParseTags splits a comma-separated tag list, lowercasing and de-duplicating
+// the entries. Inner whitespace becomes "-" so every tag is a single word that
+// the activities search can match; empty entries are dropped.
+func ParseTags(input string) []string {
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, part := range strings.Split(input, ",") {
+		tag := strings.ToLower(strings.Join(strings.Fields(part), "-"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// SetActivityTags replaces the local tags on an activity
+func (q *QueryService) SetActivityTags(activityID int64, tags []string) error {
+	return q.store.SetActivityTags(activityID, ParseTags(strings.Join(tags, ",")))
+}
+
+// SetActivityExcluded hides an activity from (or restores it to) metrics,
+// trends and personal records. Records the activity held are dropped now;
+// the next sync finds their categories stale and recomputes them from the
+// remaining activities.
+func (q *QueryService) SetActivityExcluded(activityID int64, excluded bool) error {
+	if err := q.store.SetActivityExcluded(activityID, excluded); err != nil {
+		return err
+	}
+	if err := q.refreshWeeklySummary(activityID); err != nil {
+		return err
+	}
+	if err := rebuildFitnessTrends(q.store, time.Now()); err != nil {
+		return fmt.Errorf("updating fitness trends: %w", err)
+	}
+	if excluded {
+		return q.store.VacatePersonalRecordsForActivity(activityID)
+	}
+	return nil
+}
+
+// SetActivityNote saves the local note on an activity; a blank note removes it
+func (q *QueryService) SetActivityNote(activityID int64, note string) error {
+	return q.store.SetActivityNote(activityID, strings.TrimSpace(note))
+}
+
+// ParseTemperature reads a temperature typed as a number with an optional C
+// or F suffix ("28", "28c", "82°F"). A bare number is Fahrenheit when
+// fahrenheit is set. It returns the temperature in Celsius, or nil for blank
+// input.
+func ParseTemperature(input string, fahrenheit bool) (*float64, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if s == "" {
+		return nil, nil
+	}
+	switch {
+	case strings.HasSuffix(s, "f"):
+		fahrenheit, s = true, strings.TrimSuffix(s, "f")
+	case strings.HasSuffix(s, "c"):
+		fahrenheit, s = false, strings.TrimSuffix(s, "c")
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(s, "°"))
+
+	temp, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid temperature %q", input)
+	}
+	if fahrenheit {
+		temp = (temp - 32) * 5 / 9
+	}
+	if temp < -50 || temp > 60 {
+		return nil, fmt.Errorf("temperature %q is out of range", input)
+	}
+	return &temp, nil
+}
+
+// SetActivityTemperature records the temperature during an activity in
+// Celsius; nil removes it
+func (q *QueryService) SetActivityTemperature(activityID int64, tempC *float64) error {
+	return q.store.SetActivityTemperature(activityID, tempC, store.TemperatureSourceManual)
+}
+
+// FeelLabels names each feel score, from store.MinFeel to store.MaxFeel
+var FeelLabels = []string{"awful", "bad", "ok", "good", "great"}
+
+// FeelLabel names a feel score, or returns "" for one out of range
+func FeelLabel(feel int) string {
+	if feel < store.MinFeel || feel > store.MaxFeel {
+		return ""
+	}
+	return FeelLabels[feel-store.MinFeel]
+}
+
+// FormatEffort describes a logged effort, e.g. "RPE 7, felt good"
+func FormatEffort(e store.ActivityRPE) string {
+	s := fmt.Sprintf("RPE %d", e.RPE)
+	if e.Feel != nil {
+		s += ", felt " + FeelLabel(*e.Feel)
+	}
+	return s
+}
+
+// ParseActivityRPE reads a perceived effort typed as an RPE with an optional
+// feel after a slash, either a score or its label ("7", "7/4", "7/good").
+// It returns nil for blank input.
+func ParseActivityRPE(input string) (*store.ActivityRPE, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+	if s == "" {
+		return nil, nil
+	}
+	rpeText, feelText, hasFeel := strings.Cut(s, "/")
+
+	rpe, err := strconv.Atoi(strings.TrimSpace(rpeText))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPE %q", input)
+	}
+	if rpe < store.MinRPE || rpe > store.MaxRPE {
+		return nil, fmt.Errorf("RPE must be between %d and %d", store.MinRPE, store.MaxRPE)
+	}
+	entry := &store.ActivityRPE{RPE: rpe}
+	if !hasFeel {
+		return entry, nil
+	}
+
+	feelText = strings.TrimSpace(feelText)
+	feel, err := strconv.Atoi(feelText)
+	if err != nil {
+		feel = 0
+		for i, label := range FeelLabels {
+			if label == feelText {
+				feel = store.MinFeel + i
+			}
+		}
+		if feel == 0 {
+			return nil, fmt.Errorf("invalid feel %q (use %d-%d or %s)",
+				feelText, store.MinFeel, store.MaxFeel, strings.Join(FeelLabels, ", "))
+		}
+	}
+	if feel < store.MinFeel || feel > store.MaxFeel {
+		return nil, fmt.Errorf("feel must be between %d and %d", store.MinFeel, store.MaxFeel)
+	}
+	entry.Feel = &feel
+	return entry, nil
+}
+
+// GetActivityRPE returns the perceived effort logged for an activity, or nil
+func (q *QueryService) GetActivityRPE(activityID int64) (*store.ActivityRPE, error) {
+	return q.store.GetActivityRPE(activityID)
+}
+
+// SetActivityRPE logs the perceived effort of an activity and how it felt;
+// nil removes it. Under the session-RPE load model the fitness trends are
+// rebuilt, since the run's load changed.
+func (q *QueryService) SetActivityRPE(activityID int64, rpe *store.ActivityRPE) error {
+	if err := q.store.SetActivityRPE(activityID, rpe); err != nil {
+		return err
+	}
+	if q.loadModel != config.LoadModelSessionRPE {
+		return nil
+	}
+	if err := rebuildFitnessTrends(q.store, time.Now()); err != nil {
+		return fmt.Errorf("updating fitness trends: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// BenchmarkAttempt is one run of a benchmark workout
+type BenchmarkAttempt struct {
+	Activity  store.Activity
+	EF        *float64
+	PaceAtHR  float64 // seconds per mile at the benchmark's reference HR, 0 without EF
+	Reference bool    // the run the benchmark was created from
+	Added     bool    // added by hand rather than matched
+}
+
+// BenchmarkDisplay is a benchmark with every attempt at it
+type BenchmarkDisplay struct {
+	store.Benchmark
+	ReferenceName string
+	ReferenceHR   float64            // average HR of the reference run, 0 without HR
+	Attempts      []BenchmarkAttempt // oldest first
+	EFChange      *float64           // latest attempt's EF vs the first, %
+}
+
+// ParseBenchmarkKind parses how a benchmark matches runs: "route" (or "r")
+// for the same start and distance, "duration" (or "d") for the same moving
+// time. Blank means route.
+func ParseBenchmarkKind(input string) (store.BenchmarkKind, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", "r", "route":
+		return store.BenchmarkRoute, nil
+	case "d", "duration":
+		return store.BenchmarkDuration, nil
+	}
+	return "", fmt.Errorf("unknown benchmark kind %q, use route or duration", input)
+}
+
+// AddBenchmark creates a benchmark from a reference run and returns its ID.
+// Later runs that repeat it are matched automatically.
+func (q *QueryService) AddBenchmark(name string, kind store.BenchmarkKind, referenceID int64) (int64, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, errors.New("benchmark name is required")
+	}
+	if kind != store.BenchmarkRoute && kind != store.BenchmarkDuration {
+		return 0, fmt.Errorf("unknown benchmark kind %q", kind)
+	}
+	if _, err := q.store.GetActivity(referenceID); err != nil {
+		return 0, err
+	}
+
+	existing, err := q.BenchmarkByName(name)
+	if err != nil {
+		return 0, err
+	}
+	if existing != nil {
+		return 0, fmt.Errorf("a benchmark named %q already exists", existing.Name)
+	}
+
+	return q.store.CreateBenchmark(&store.Benchmark{Name: name, Kind: kind, ReferenceActivityID: referenceID})
+}
+
+// BenchmarkByName finds a benchmark by name, ignoring case. It returns nil
+// when there is none.
+func (q *QueryService) BenchmarkByName(name string) (*store.Benchmark, error) {
+	benchmarks, err := q.store.ListBenchmarks()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range benchmarks {
+		if strings.EqualFold(b.Name, strings.TrimSpace(name)) {
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
+// DeleteBenchmark removes a benchmark; its runs are kept
+func (q *QueryService) DeleteBenchmark(id int64) error {
+	return q.store.DeleteBenchmark(id)
+}
+
+// SetBenchmarkAttempt adds a run to a benchmark, or removes one that was
+// matched by mistake
+func (q *QueryService) SetBenchmarkAttempt(benchmarkID, activityID int64, included bool) error {
+	return q.store.SetBenchmarkOverride(store.BenchmarkOverride{
+		BenchmarkID: benchmarkID,
+		ActivityID:  activityID,
+		Included:    included,
+	})
+}
+
+// GetBenchmarks returns every benchmark with its attempts: the reference
+// run, analyzed runs that match it, and runs added by hand
+func (q *QueryService) GetBenchmarks() ([]BenchmarkDisplay, error) {
+	benchmarks, err := q.store.ListBenchmarks()
+	if err != nil {
+		return nil, err
+	}
+	if len(benchmarks) == 0 {
+		return nil, nil
+	}
+
+	var runs []store.Activity
+	metrics := make(map[int64]store.ActivityMetrics)
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		activities, ms, err := q.store.GetActivitiesWithMetrics(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, activities...)
+		for _, m := range ms {
+			metrics[m.ActivityID] = m
+		}
+		if len(activities) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+
+	starts := make(map[int64]*analysis.LatLng)
+	start := func(id int64) (*analysis.LatLng, error) {
+		if p, ok := starts[id]; ok {
+			return p, nil
+		}
+		streams, err := q.store.GetStreamSeries(id, store.StreamLatLng)
+		if err != nil {
+			return nil, err
+		}
+		starts[id] = analysis.StartPoint(streams)
+		return starts[id], nil
+	}
+
+	result := make([]BenchmarkDisplay, 0, len(benchmarks))
+	for _, b := range benchmarks {
+		display, err := q.buildBenchmark(b, runs, metrics, start)
+		if err != nil {
+			return nil, fmt.Errorf("benchmark %q: %w", b.Name, err)
+		}
+		result = append(result, display)
+	}
+	return result, nil
+}
+
+// buildBenchmark collects a benchmark's attempts. Overrides win over
+// matching; the reference run is always an attempt.
+func (q *QueryService) buildBenchmark(b store.Benchmark, runs []store.Activity, metrics map[int64]store.ActivityMetrics,
+	start func(int64) (*analysis.LatLng, error)) (BenchmarkDisplay, error) {
+	ref, err := q.store.GetActivity(b.ReferenceActivityID)
+	if err != nil {
+		return BenchmarkDisplay{}, err
+	}
+	refStart, err := start(ref.ID)
+	if err != nil {
+		return BenchmarkDisplay{}, err
+	}
+	refRun := analysis.BenchmarkRun{Distance: ref.Distance, MovingTime: ref.MovingTime, Start: refStart}
+
+	overrides, err := q.store.ListBenchmarkOverrides(b.ID)
+	if err != nil {
+		return BenchmarkDisplay{}, err
+	}
+	included := make(map[int64]bool, len(overrides))
+	for _, o := range overrides {
+		included[o.ActivityID] = o.Included
+	}
+
+	display := BenchmarkDisplay{Benchmark: b, ReferenceName: ref.Name}
+	if ref.AverageHeartrate != nil {
+		display.ReferenceHR = *ref.AverageHeartrate
+	}
+
+	seen := make(map[int64]bool)
+	add := func(a store.Activity, m *store.ActivityMetrics) {
+		seen[a.ID] = true
+		attempt := BenchmarkAttempt{
+			Activity:  a,
+			Reference: a.ID == ref.ID,
+			Added:     included[a.ID] && a.ID != ref.ID,
+		}
+		if m != nil && m.EfficiencyFactor != nil && *m.EfficiencyFactor > 0 {
+			attempt.EF = m.EfficiencyFactor
+			if display.ReferenceHR > 0 {
+				// EF is meters per minute per beat
+				attempt.PaceAtHR = MetersPerMile / (*m.EfficiencyFactor * display.ReferenceHR) * SecondsPerMinute
+			}
+		}
+		display.Attempts = append(display.Attempts, attempt)
+	}
+
+	for _, run := range runs {
+		m := metrics[run.ID]
+		if run.ID == ref.ID {
+			add(run, &m)
+			continue
+		}
+		if in, ok := included[run.ID]; ok {
+			if in {
+				add(run, &m)
+			}
+			continue
+		}
+
+		candidate := analysis.BenchmarkRun{Distance: run.Distance, MovingTime: run.MovingTime}
+		if !analysis.MatchesBenchmark(b.Kind, refRun, candidate) {
+			continue
+		}
+		// Only load streams for runs that already match on distance or time
+		if refStart != nil {
+			if candidate.Start, err = start(run.ID); err != nil {
+				return BenchmarkDisplay{}, err
+			}
+			if !analysis.MatchesBenchmark(b.Kind, refRun, candidate) {
+				continue
+			}
+		}
+		add(run, &m)
+	}
+
+	// The reference and runs added by hand count even without metrics
+	extra := []int64{ref.ID}
+	for _, o := range overrides {
+		if o.Included {
+			extra = append(extra, o.ActivityID)
+		}
+	}
+	for _, id := range extra {
+		if seen[id] {
+			continue
+		}
+		a, err := q.store.GetActivity(id)
+		if errors.Is(err, store.ErrActivityNotFound) {
+			continue
+		}
+		if err != nil {
+			return BenchmarkDisplay{}, err
+		}
+		m, err := q.store.GetActivityMetrics(id)
+		if err != nil {
+			return BenchmarkDisplay{}, err
+		}
+		add(*a, m)
+	}
+
+	sort.Slice(display.Attempts, func(i, j int) bool {
+		return display.Attempts[i].Activity.StartDate.Before(display.Attempts[j].Activity.StartDate)
+	})
+
+	var efs []float64
+	for _, a := range display.Attempts {
+		if a.EF != nil {
+			efs = append(efs, *a.EF)
+		}
+	}
+	if len(efs) > 1 {
+		change := (efs[len(efs)-1] - efs[0]) / efs[0] * 100
+		display.EFChange = &change
+	}
+
+	return display, nil
+}
@@ -31,92 +31,67 @@ type ComparisonStats struct {
 	DeltaEF    float64
 }
 
-// GetPeriodStats returns aggregated stats by week or month
+// GetPeriodStats returns aggregated stats by week or month. Weekly stats
+// come from the weekly summaries and monthly ones are summed in SQL from
+// cached per-activity stream stats; HR and cadence are time-weighted.
 func (q *QueryService) GetPeriodStats(periodType string, numPeriods int) ([]PeriodStats, error) {
-	activities, _, err := q.store.GetActivitiesWithMetrics(PeriodStatsActivityLimit, 0)
-	if err != nil {
-		return nil, err
+	if periodType == "weekly" {
+		return q.getWeeklyPeriodStats(numPeriods)
 	}
 
-	now := time.Now()
+	now := q.bucketNow()
 	stats := make([]PeriodStats, numPeriods)
 
-	// Initialize periods
-	currentMonday := getMonday(now)
+	// Initialize monthly periods - first of month
+	currentFirst := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	for i := 0; i < numPeriods; i++ {
-		var periodStart time.Time
-		var label string
-
-		if periodType == "weekly" {
-			periodStart = currentMonday.AddDate(0, 0, -7*(numPeriods-1-i))
-			label = periodStart.Format("Jan 02")
-		} else {
-			// Monthly - first of month
-			currentFirst := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-			periodStart = currentFirst.AddDate(0, -(numPeriods-1-i), 0)
-			label = periodStart.Format("Jan 2006")
-		}
-
+		periodStart := currentFirst.AddDate(0, -(numPeriods-1-i), 0)
 		stats[i] = PeriodStats{
 			PeriodStart: periodStart,
-			PeriodLabel: label,
+			PeriodLabel: periodStart.Format("Jan 2006"),
 		}
 	}
 
-	// Collect activity IDs for batch stream fetch
-	activityIDs := make([]int64, len(activities))
-	for i, a := range activities {
-		activityIDs[i] = a.ID
+	if err := q.backfillStreamStats(); err != nil {
+		return nil, err
 	}
-
-	// Batch fetch all streams (fixes N+1 query)
-	streamsMap, err := q.store.GetStreamsForActivities(activityIDs)
+	totals, err := q.store.GetMonthlyTotals(stats[0].PeriodStart, currentFirst.AddDate(0, 1, 0))
 	if err != nil {
-		streamsMap = make(map[int64][]store.StreamPoint)
+		return nil, err
 	}
-
-	// Aggregate activities into periods
-	for _, a := range activities {
-		periodIdx := q.findPeriodIndex(a.StartDate, stats, periodType)
-		if periodIdx < 0 {
-			continue
+	for _, t := range totals {
+		if i := q.findPeriodIndex(t.Start, stats, periodType); i >= 0 {
+			stats[i] = periodStatsFromTotals(t, stats[i].PeriodLabel)
 		}
+	}
 
-		stats[periodIdx].RunCount++
-		stats[periodIdx].TotalMiles += metersToMiles(a.Distance)
+	return stats, nil
+}
 
-		streams := streamsMap[a.ID]
-		if len(streams) == 0 {
-			continue
-		}
+// getWeeklyPeriodStats returns per-week stats for the last numWeeks weeks
+func (q *QueryService) getWeeklyPeriodStats(numWeeks int) ([]PeriodStats, error) {
+	summaries, err := q.getWeeklySummaries(numWeeks)
+	if err != nil {
+		return nil, err
+	}
 
-		streamStats := AggregateStreamStats(streams)
-
-		// Accumulate moving time and distance for pace calculation
-		stats[periodIdx].TotalMovingTime += streamStats.MovingTime
-		stats[periodIdx].TotalDistance += streamStats.TotalDistance
-
-		// Weighted contribution to period average
-		if streamStats.HRCount > 0 {
-			activityAvgHR := streamStats.AvgHR()
-			if stats[periodIdx].AvgHR == 0 {
-				stats[periodIdx].AvgHR = activityAvgHR
-			} else {
-				n := float64(stats[periodIdx].RunCount)
-				stats[periodIdx].AvgHR = stats[periodIdx].AvgHR*(n-1)/n + activityAvgHR/n
-			}
+	stats := make([]PeriodStats, len(summaries))
+	for i, w := range summaries {
+		stats[i] = PeriodStats{
+			PeriodStart:     w.WeekStart,
+			PeriodLabel:     q.weekLabel(w.WeekStart),
+			RunCount:        w.RunCount,
+			TotalMiles:      metersToMiles(w.Distance),
+			TotalMovingTime: w.MovingTime,
+			TotalDistance:   w.MovingDistance,
+		}
+		if w.HRCount > 0 {
+			stats[i].AvgHR = w.HRSum / float64(w.HRCount)
 		}
-		if streamStats.CadenceCount > 0 {
-			activityAvgSPM := streamStats.AvgCadence()
-			if stats[periodIdx].AvgSPM == 0 {
-				stats[periodIdx].AvgSPM = activityAvgSPM
-			} else {
-				n := float64(stats[periodIdx].RunCount)
-				stats[periodIdx].AvgSPM = stats[periodIdx].AvgSPM*(n-1)/n + activityAvgSPM/n
-			}
+		if w.CadenceCount > 0 {
+			stats[i].AvgSPM = w.CadenceSum / float64(w.CadenceCount)
 		}
 	}
-
 	return stats, nil
 }
 
@@ -139,16 +114,16 @@ func (q *QueryService) findPeriodIndex(date time.Time, stats []PeriodStats, peri
 
 // GetWeeklyComparisons returns week-over-week and rolling 30-day comparisons
 func (q *QueryService) GetWeeklyComparisons() ([]ComparisonStats, error) {
-	now := time.Now()
-	currentMonday := getMonday(now)
-	lastMonday := currentMonday.AddDate(0, 0, -7)
+	now := q.bucketNow()
+	currentWeekStart := q.weekStart(now)
+	lastWeekStart := currentWeekStart.AddDate(0, 0, -7)
 
 	// This week vs last week
-	thisWeek, err := q.getPeriodStatsForRange(currentMonday, now, "This Week")
+	thisWeek, err := q.getPeriodStatsForRange(currentWeekStart, now, "This Week")
 	if err != nil {
 		return nil, err
 	}
-	lastWeek, err := q.getPeriodStatsForRange(lastMonday, currentMonday, "Last Week")
+	lastWeek, err := q.getPeriodStatsForRange(lastWeekStart, currentWeekStart, "Last Week")
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +141,7 @@ func (q *QueryService) GetWeeklyComparisons() ([]ComparisonStats, error) {
 
 // GetMonthlyComparisons returns month-over-month, year-over-year, and rolling 30-day comparisons
 func (q *QueryService) GetMonthlyComparisons() ([]ComparisonStats, error) {
-	now := time.Now()
+	now := q.bucketNow()
 
 	// This month vs last month
 	thisMonthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
@@ -204,7 +179,7 @@ func (q *QueryService) GetMonthlyComparisons() ([]ComparisonStats, error) {
 
 // getRolling30DayComparison returns last 30 days vs prior 30 days
 func (q *QueryService) getRolling30DayComparison() (ComparisonStats, error) {
-	now := time.Now()
+	now := q.bucketNow()
 	thirtyDaysAgo := now.AddDate(0, 0, -Rolling30Days)
 	sixtyDaysAgo := now.AddDate(0, 0, -Rolling30Days*2)
 
@@ -222,86 +197,38 @@ func (q *QueryService) getRolling30DayComparison() (ComparisonStats, error) {
 
 // getPeriodStatsForRange calculates stats for activities within a date range
 func (q *QueryService) getPeriodStatsForRange(start, end time.Time, label string) (PeriodStats, error) {
-	stats := PeriodStats{
-		PeriodStart: start,
-		PeriodLabel: label,
+	if err := q.backfillStreamStats(); err != nil {
+		return PeriodStats{}, err
 	}
-
-	activities, metrics, err := q.store.GetActivitiesWithMetrics(PeriodStatsActivityLimit, 0)
+	totals, err := q.store.GetPeriodTotals(start, end)
 	if err != nil {
-		return stats, err
-	}
-
-	// Filter activities in range and collect IDs
-	var relevantActivities []store.Activity
-	var relevantMetrics []store.ActivityMetrics
-	var activityIDs []int64
-
-	for i, a := range activities {
-		if !a.StartDate.Before(start) && a.StartDate.Before(end) {
-			relevantActivities = append(relevantActivities, a)
-			relevantMetrics = append(relevantMetrics, metrics[i])
-			activityIDs = append(activityIDs, a.ID)
-		}
+		return PeriodStats{}, err
 	}
+	return periodStatsFromTotals(totals, label), nil
+}
 
-	if len(relevantActivities) == 0 {
-		return stats, nil
+// periodStatsFromTotals turns summed totals into period stats. HR and cadence
+// are time-weighted across the period's runs, and EF is the mean of runs
+// that have one.
+func periodStatsFromTotals(t store.PeriodTotals, label string) PeriodStats {
+	stats := PeriodStats{
+		PeriodStart:     t.Start,
+		PeriodLabel:     label,
+		RunCount:        t.RunCount,
+		TotalMiles:      metersToMiles(t.Distance),
+		TotalMovingTime: t.MovingTime,
+		TotalDistance:   t.MovingDistance,
 	}
-
-	// Batch fetch streams
-	streamsMap, err := q.store.GetStreamsForActivities(activityIDs)
-	if err != nil {
-		streamsMap = make(map[int64][]store.StreamPoint)
+	if t.HRCount > 0 {
+		stats.AvgHR = t.HRSum / float64(t.HRCount)
 	}
-
-	// Aggregate stats
-	var efSum float64
-	var efCount int
-
-	for i, a := range relevantActivities {
-		stats.RunCount++
-		stats.TotalMiles += metersToMiles(a.Distance)
-
-		// EF from metrics
-		if relevantMetrics[i].EfficiencyFactor != nil {
-			efSum += *relevantMetrics[i].EfficiencyFactor
-			efCount++
-		}
-
-		// HR and cadence from streams
-		streams := streamsMap[a.ID]
-		if len(streams) == 0 {
-			continue
-		}
-
-		streamStats := AggregateStreamStats(streams)
-
-		if streamStats.HRCount > 0 {
-			activityAvgHR := streamStats.AvgHR()
-			if stats.AvgHR == 0 {
-				stats.AvgHR = activityAvgHR
-			} else {
-				n := float64(stats.RunCount)
-				stats.AvgHR = stats.AvgHR*(n-1)/n + activityAvgHR/n
-			}
-		}
-		if streamStats.CadenceCount > 0 {
-			activityAvgSPM := streamStats.AvgCadence()
-			if stats.AvgSPM == 0 {
-				stats.AvgSPM = activityAvgSPM
-			} else {
-				n := float64(stats.RunCount)
-				stats.AvgSPM = stats.AvgSPM*(n-1)/n + activityAvgSPM/n
-			}
-		}
+	if t.CadenceCount > 0 {
+		stats.AvgSPM = t.CadenceSum / float64(t.CadenceCount)
 	}
-
-	if efCount > 0 {
-		stats.AvgEF = efSum / float64(efCount)
+	if t.EFCount > 0 {
+		stats.AvgEF = t.EFSum / float64(t.EFCount)
 	}
-
-	return stats, nil
+	return stats
 }
 
 // buildComparison creates a ComparisonStats from two periods
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// CriticalPaceRecentDays is the window of the recent pace curve, so current
+// fitness can be compared against all-time bests
+const CriticalPaceRecentDays = 90
+
+// CriticalPacePoint is the best effort held for one curve duration
+type CriticalPacePoint struct {
+	DurationSeconds int
+	Duration        string // "M:SS" or "H:MM:SS"
+	DistanceMeters  float64
+	PacePerMile     float64 // seconds per mile
+	ActivityID      int64
+	ActivityName    string
+	Date            string
+}
+
+// CriticalPaceCurve is a pace-duration curve with its critical speed fit.
+// HasFit is false when too few efforts fall in the fitting range.
+type CriticalPaceCurve struct {
+	Points []CriticalPacePoint
+
+	HasFit        bool
+	CriticalSpeed float64 // m/s
+	CriticalPace  float64 // seconds per mile
+	DPrime        float64 // meters
+}
+
+// CriticalPaceData holds the all-time and recent pace-duration curves
+type CriticalPaceData struct {
+	AllTime CriticalPaceCurve
+	Recent  CriticalPaceCurve
+}
+
+// GetCriticalPace returns the best pace held for each curve duration, over
+// all time and over the last CriticalPaceRecentDays, from the efforts cached
+// during sync
+func (q *QueryService) GetCriticalPace() (*CriticalPaceData, error) {
+	allTime, err := q.criticalPaceCurve(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	recent, err := q.criticalPaceCurve(time.Now().AddDate(0, 0, -CriticalPaceRecentDays))
+	if err != nil {
+		return nil, err
+	}
+	return &CriticalPaceData{AllTime: allTime, Recent: recent}, nil
+}
+
+func (q *QueryService) criticalPaceCurve(since time.Time) (CriticalPaceCurve, error) {
+	var curve CriticalPaceCurve
+
+	best, err := q.store.GetBestDurationEfforts(since)
+	if err != nil {
+		return curve, err
+	}
+	if len(best) == 0 {
+		return curve, nil
+	}
+
+	ids := make([]int64, len(best))
+	for i, e := range best {
+		ids[i] = e.ActivityID
+	}
+	activities, err := q.store.GetActivitiesByIDs(ids)
+	if err != nil {
+		return curve, err
+	}
+
+	efforts := make([]analysis.DurationEffort, 0, len(best))
+	for _, e := range best {
+		efforts = append(efforts, analysis.DurationEffort{
+			DurationSeconds: e.DurationSeconds,
+			DistanceMeters:  e.DistanceMeters,
+			StartOffset:     e.StartOffset,
+		})
+		curve.Points = append(curve.Points, criticalPacePoint(e, activities))
+	}
+
+	if cs, dPrime, ok := analysis.CriticalSpeed(efforts); ok {
+		curve.HasFit = true
+		curve.CriticalSpeed = cs
+		curve.CriticalPace = analysis.Distance1Mile / cs
+		curve.DPrime = dPrime
+	}
+	return curve, nil
+}
+
+func criticalPacePoint(e store.DurationEffort, activities map[int64]*store.Activity) CriticalPacePoint {
+	p := CriticalPacePoint{
+		DurationSeconds: e.DurationSeconds,
+		Duration:        formatDuration(e.DurationSeconds),
+		DistanceMeters:  e.DistanceMeters,
+		PacePerMile:     analysis.CalculatePacePerMile(e.DistanceMeters, e.DurationSeconds),
+		ActivityID:      e.ActivityID,
+		Date:            e.StartDate.Format("Jan 02, 2006"),
+	}
+	if a, ok := activities[e.ActivityID]; ok {
+		p.ActivityName = a.Name
+	}
+	return p
+}
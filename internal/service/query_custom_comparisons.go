@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ComparisonRange is a span of whole days, [Start, End), for a custom
+// comparison
+type ComparisonRange struct {
+	Start time.Time
+	End   time.Time
+	Label string
+}
+
+// Days returns how many days the range covers
+func (r ComparisonRange) Days() int {
+	return daysBetween(r.Start, r.End)
+}
+
+// spanLabel names a range by its first and last days
+func spanLabel(start, end time.Time) string {
+	last := end.AddDate(0, 0, -1)
+	if start.Year() != last.Year() {
+		return start.Format("Jan 2 2006") + "-" + last.Format("Jan 2 2006")
+	}
+	return start.Format("Jan 2") + "-" + last.Format("Jan 2")
+}
+
+var (
+	lastRangePattern   = regexp.MustCompile(`^last (\d+) ?([dw])$`)
+	beforeRangePattern = regexp.MustCompile(`^(\d+) ?([dw]) before (\d{4}-\d{2}-\d{2})$`)
+)
+
+// ComparisonRangeHelp lists the forms ParseComparisonRange accepts
+const ComparisonRangeHelp = "last 6w, 42d before 2024-05-10, 2024-06, 2024, 2024-03-01..2024-04-15"
+
+// ParseComparisonRange parses a span of days for a custom comparison:
+//
+//	last 6w                  the last 6 weeks (or "last 42d" days) through today
+//	6w before 2024-05-10     the 6 weeks (or days) up to the day before a date
+//	2024-06                  a calendar month
+//	2024                     a calendar year
+//	2024-03-01..2024-04-15   the days between two dates, inclusive
+//
+// Days are calendar days on the day-bucketing clock, as returned by today.
+func ParseComparisonRange(input string, today time.Time) (ComparisonRange, error) {
+	today = calendarDay(today)
+	s := strings.ToLower(strings.Join(strings.Fields(input), " "))
+
+	if m := lastRangePattern.FindStringSubmatch(s); m != nil {
+		days, unit, err := rangeDays(m[1], m[2])
+		if err != nil {
+			return ComparisonRange{}, err
+		}
+		end := today.AddDate(0, 0, 1)
+		return ComparisonRange{Start: end.AddDate(0, 0, -days), End: end, Label: "Last " + m[1] + " " + unit}, nil
+	}
+
+	if m := beforeRangePattern.FindStringSubmatch(s); m != nil {
+		days, _, err := rangeDays(m[1], m[2])
+		if err != nil {
+			return ComparisonRange{}, err
+		}
+		end, err := time.Parse("2006-01-02", m[3])
+		if err != nil {
+			return ComparisonRange{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", m[3])
+		}
+		start := end.AddDate(0, 0, -days)
+		return ComparisonRange{Start: start, End: end, Label: spanLabel(start, end)}, nil
+	}
+
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		start, err := time.Parse("2006-01-02", strings.TrimSpace(from))
+		if err != nil {
+			return ComparisonRange{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", strings.TrimSpace(from))
+		}
+		last, err := time.Parse("2006-01-02", strings.TrimSpace(to))
+		if err != nil {
+			return ComparisonRange{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", strings.TrimSpace(to))
+		}
+		if last.Before(start) {
+			return ComparisonRange{}, errors.New("range ends before it starts")
+		}
+		end := last.AddDate(0, 0, 1)
+		return ComparisonRange{Start: start, End: end, Label: spanLabel(start, end)}, nil
+	}
+
+	if month, err := time.Parse("2006-01", s); err == nil {
+		return ComparisonRange{Start: month, End: month.AddDate(0, 1, 0), Label: month.Format("Jan 2006")}, nil
+	}
+	if year, err := time.Parse("2006", s); err == nil {
+		return ComparisonRange{Start: year, End: year.AddDate(1, 0, 0), Label: year.Format("2006")}, nil
+	}
+
+	return ComparisonRange{}, fmt.Errorf("unknown range %q, try %s", strings.TrimSpace(input), ComparisonRangeHelp)
+}
+
+// rangeDays converts a count of days or weeks to days, with the unit's name
+func rangeDays(count, unit string) (int, string, error) {
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return 0, "", errors.New("range length must be at least 1")
+	}
+	if unit == "w" {
+		return n * 7, pluralize(n, "week"), nil
+	}
+	return n, pluralize(n, "day"), nil
+}
+
+// pluralize returns noun, with an s unless n is 1
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+// PreviousRange returns the span of the same length that ends where r
+// starts, the default to compare a custom range against
+func PreviousRange(r ComparisonRange) ComparisonRange {
+	start := r.Start.AddDate(0, 0, -r.Days())
+	return ComparisonRange{Start: start, End: r.Start, Label: spanLabel(start, r.Start)}
+}
+
+// GetCustomComparison compares two arbitrary ranges of days, such as the
+// last six weeks against the six weeks before an injury
+func (q *QueryService) GetCustomComparison(current, previous ComparisonRange) (ComparisonStats, error) {
+	currentStats, err := q.getPeriodStatsForRange(current.Start, current.End, current.Label)
+	if err != nil {
+		return ComparisonStats{}, err
+	}
+	previousStats, err := q.getPeriodStatsForRange(previous.Start, previous.End, previous.Label)
+	if err != nil {
+		return ComparisonStats{}, err
+	}
+	return buildComparison(current.Label+" vs "+previous.Label, currentStats, previousStats), nil
+}
@@ -29,16 +29,55 @@ type DashboardData struct {
 	// For charts
 	EFHistory        []float64
 	EFDates          []time.Time
+	EFHeatAdjusted   int       // Runs in EFHistory scaled to cool weather for the heat
+	PacingHistory    []float64 // Pacing split % per run, last 90 days
+	StrideHistory    []float64 // Average stride length (m) per run, last 90 days
+	HRRHistory       []float64 // 60s HR recovery (bpm) per run, last 90 days
 	WeeklyMileage    []float64 // Last 12 weeks of mileage
 	WeeklyAvgCadence []float64 // Last 12 weeks avg cadence
 	WeeklyAvgHR      []float64 // Last 12 weeks avg HR
+	WeeklyVertical   []float64 // Last 12 weeks of elevation gain (m)
 	WeeklyLabels     []string  // Week labels (e.g., "Jan 06")
+	WeeklyGoal       float64   // Weekly distance goal in miles, 0 if unset
+
+	// How often weeks reach the goal, zero when no goal is set
+	GoalConsistency analysis.GoalConsistency
+
+	// Fitted trends projected TrendProjectionWeeks ahead
+	EFProjection   TrendProjection
+	VDOTProjection TrendProjection
+
+	// Average pace at each HR anchor over the last EFTrendCompareDays
+	PaceAtHR []AnchorPace
+
+	// VO2max from easy runs' HR and pace, set when weight is configured
+	VO2max VO2maxEstimate
+
+	// Foster's monotony and strain over the 7 days to the last sync, zero
+	// until a full week has been analyzed. HighMonotony is set above
+	// analysis.MonotonyWarning, a known overtraining signal.
+	Monotony     float64
+	Strain       float64
+	HighMonotony bool
+
+	// Run streaks. NeedsRest is set once DaysSinceRest passes
+	// RestDayWarningDays.
+	Streaks            analysis.Streaks
+	RestDayWarningDays int
+	NeedsRest          bool
+
+	// Today's wellness entry and the readiness score it gives with form
+	Readiness ReadinessData
 }
 
 // ActivityWithMetrics combines activity and its metrics
 type ActivityWithMetrics struct {
 	Activity store.Activity
 	Metrics  store.ActivityMetrics
+	Social   *store.ActivitySocial // Kudos and comment counts; nil unless synced
+	Effort   *store.ActivityRPE    // Perceived effort and feel; only set for exports
+	Tags     []string              // Only set for exports
+	Note     string                // Only set for exports
 }
 
 // GetDashboardData fetches all data needed for the dashboard
@@ -56,7 +95,10 @@ func (q *QueryService) GetDashboardData() (*DashboardData, error) {
 	data.CurrentEF, data.EFTrend = q.calculateCurrentEF(recent)
 
 	// Calculate this week's stats
-	data.WeekRunCount, data.WeekDistance, data.WeekTime, data.WeekAvgEF = q.calculateWeekStats(recent)
+	data.WeekRunCount, data.WeekDistance, data.WeekTime, data.WeekAvgEF, err = q.calculateWeekStats()
+	if err != nil {
+		return nil, err
+	}
 
 	// Fitness metrics need more history
 	allActivities, allMetrics, err := q.store.GetActivitiesWithMetrics(HistoricalActivitiesLimit, 0)
@@ -67,14 +109,71 @@ func (q *QueryService) GetDashboardData() (*DashboardData, error) {
 	}
 
 	if len(allActivities) > 0 {
-		data.CurrentFitness, data.CurrentFatigue, data.CurrentForm, data.FormDescription = q.calculateFitnessMetrics(allActivities, allMetrics)
+		rpes, err := q.activityRPEs()
+		if err != nil {
+			return nil, err
+		}
+		data.CurrentFitness, data.CurrentFatigue, data.CurrentForm, data.FormDescription = q.calculateFitnessMetrics(allActivities, allMetrics, rpes)
+	}
+
+	// Runs with a recorded temperature chart at their heat-adjusted EF, so
+	// hot weather doesn't read as lost fitness
+	temps, err := q.store.GetActivityTemperatures()
+	if err != nil {
+		return nil, err
 	}
 
 	// Build EF history for chart
-	data.EFHistory, data.EFDates = q.buildEFHistory(recent)
+	data.EFHistory, data.EFDates, data.EFHeatAdjusted = q.buildEFHistory(recent, temps)
+	data.PacingHistory = q.buildPacingHistory(recent)
+	data.StrideHistory = q.buildStrideHistory(recent)
+	data.HRRHistory = q.buildHRRHistory(allActivities, allMetrics)
+	data.PaceAtHR = q.buildRecentPaceAtHR(allActivities, allMetrics)
 
 	// Build weekly charts
-	data.WeeklyMileage, data.WeeklyAvgCadence, data.WeeklyAvgHR, data.WeeklyLabels = q.buildWeeklyCharts(allActivities)
+	data.WeeklyMileage, data.WeeklyAvgCadence, data.WeeklyAvgHR, data.WeeklyVertical, data.WeeklyLabels = q.buildWeeklyCharts()
+	data.WeeklyGoal = q.athleteCfg.WeeklyGoalKm * 1000 / MetersPerMile
+	if data.WeeklyGoal > 0 {
+		data.GoalConsistency, err = q.buildGoalConsistency(data.WeeklyGoal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Trend projections
+	data.EFProjection = q.buildEFProjection(allActivities, allMetrics, temps)
+	data.VDOTProjection, err = q.buildVDOTProjection()
+	if err != nil {
+		return nil, err
+	}
+	data.VO2max = q.buildVO2maxEstimate(allActivities, allMetrics, data.VDOTProjection)
+
+	runDays, err := q.store.ListRunDays()
+	if err != nil {
+		return nil, err
+	}
+	data.Streaks = analysis.CalculateStreaks(runDays, calendarDay(q.bucketNow()))
+	data.RestDayWarningDays = q.restDayWarning
+	data.NeedsRest = q.restDayWarning > 0 && data.Streaks.DaysSinceRest > q.restDayWarning
+
+	// Monotony and strain are stored with the daily fitness trends
+	trend, err := q.store.GetLatestFitnessTrend()
+	if err != nil {
+		return nil, err
+	}
+	if trend != nil && trend.Monotony7d != nil && trend.Strain7d != nil {
+		data.Monotony, data.Strain = *trend.Monotony7d, *trend.Strain7d
+		data.HighMonotony = data.Monotony > analysis.MonotonyWarning
+	}
+
+	var tsb *float64
+	if len(allActivities) > 0 {
+		tsb = &data.CurrentForm
+	}
+	data.Readiness, err = q.buildReadiness(calendarDay(q.bucketNow()), tsb)
+	if err != nil {
+		return nil, err
+	}
 
 	return data, nil
 }
@@ -142,19 +241,21 @@ func (q *QueryService) calculateCurrentEF(recent []ActivityWithMetrics) (current
 	return currentEF, trend
 }
 
-// calculateWeekStats calculates stats for the current week (Monday start)
-func (q *QueryService) calculateWeekStats(recent []ActivityWithMetrics) (runCount int, distance float64, totalTime int, avgEF float64) {
-	weekStart := getMonday(time.Now())
+// calculateWeekStats calculates stats for the current week
+func (q *QueryService) calculateWeekStats() (runCount int, distance float64, totalTime int, avgEF float64, err error) {
+	weekStart := q.weekStart(q.bucketNow())
+	activities, metrics, err := q.store.GetActivitiesWithMetricsBetween(weekStart, weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
 
 	var efSum float64
-	for _, am := range recent {
-		if !am.Activity.StartDate.Before(weekStart) {
-			runCount++
-			distance += metersToMiles(am.Activity.Distance)
-			totalTime += am.Activity.MovingTime
-			if am.Metrics.EfficiencyFactor != nil {
-				efSum += *am.Metrics.EfficiencyFactor
-			}
+	for i, a := range activities {
+		runCount++
+		distance += metersToMiles(a.Distance)
+		totalTime += a.MovingTime
+		if metrics[i].EfficiencyFactor != nil {
+			efSum += *metrics[i].EfficiencyFactor
 		}
 	}
 
@@ -164,15 +265,16 @@ func (q *QueryService) calculateWeekStats(recent []ActivityWithMetrics) (runCoun
 	return
 }
 
-// calculateFitnessMetrics calculates CTL/ATL/TSB from TRIMP values
-func (q *QueryService) calculateFitnessMetrics(activities []store.Activity, metrics []store.ActivityMetrics) (ctl, atl, tsb float64, formDesc string) {
+// calculateFitnessMetrics calculates CTL/ATL/TSB from each run's load under
+// the chosen model
+func (q *QueryService) calculateFitnessMetrics(activities []store.Activity, metrics []store.ActivityMetrics, rpes map[int64]store.ActivityRPE) (ctl, atl, tsb float64, formDesc string) {
 	var dailyLoads []analysis.DailyLoad
 
 	for i, a := range activities {
-		if metrics[i].TRIMP != nil {
+		if load := q.activityLoad(a, metrics[i], rpes); load != nil {
 			dailyLoads = append(dailyLoads, analysis.DailyLoad{
 				Date:  a.StartDate,
-				TRIMP: *metrics[i].TRIMP,
+				TRIMP: *load,
 			})
 		}
 	}
@@ -184,102 +286,128 @@ func (q *QueryService) calculateFitnessMetrics(activities []store.Activity, metr
 	return 0, 0, 0, ""
 }
 
-// buildEFHistory builds EF chart data for the last 90 days
-func (q *QueryService) buildEFHistory(recent []ActivityWithMetrics) ([]float64, []time.Time) {
+// buildEFHistory builds EF chart data for the last 90 days, adjusting runs
+// with a temperature in temps for the heat. It also returns how many runs
+// were adjusted.
+func (q *QueryService) buildEFHistory(recent []ActivityWithMetrics, temps map[int64]float64) ([]float64, []time.Time, int) {
 	ninetyDaysAgo := time.Now().AddDate(0, 0, -EFHistoryDays)
 
 	var history []float64
 	var dates []time.Time
+	adjusted := 0
 
 	// Iterate in reverse to get oldest first (most recent last)
 	for i := len(recent) - 1; i >= 0; i-- {
 		am := recent[i]
 		if am.Activity.StartDate.After(ninetyDaysAgo) && am.Metrics.EfficiencyFactor != nil {
-			history = append(history, *am.Metrics.EfficiencyFactor)
+			ef := *am.Metrics.EfficiencyFactor
+			if temp, ok := temps[am.Activity.ID]; ok {
+				ef = analysis.HeatAdjustedEF(ef, temp)
+				adjusted++
+			}
+			history = append(history, ef)
 			dates = append(dates, am.Activity.StartDate)
 		}
 	}
-	return history, dates
+	return history, dates, adjusted
 }
 
-// buildWeeklyCharts builds the 12-week mileage, cadence, and HR chart data
-func (q *QueryService) buildWeeklyCharts(activities []store.Activity) (mileage, avgCadence, avgHR []float64, labels []string) {
-	numWeeks := ChartWeeks
-	currentWeekStart := getMonday(time.Now())
-
-	// Initialize weekly buckets
-	mileage = make([]float64, numWeeks)
-	cadenceSum := make([]float64, numWeeks)
-	cadenceCount := make([]int, numWeeks)
-	hrSum := make([]float64, numWeeks)
-	hrCount := make([]int, numWeeks)
-	labels = make([]string, numWeeks)
-
-	// Build labels
-	for i := 0; i < numWeeks; i++ {
-		weekStart := currentWeekStart.AddDate(0, 0, -7*(numWeeks-1-i))
-		labels[i] = weekStart.Format("Jan 02")
-	}
-
-	if len(activities) == 0 {
-		avgCadence = make([]float64, numWeeks)
-		avgHR = make([]float64, numWeeks)
-		return
-	}
+// buildPacingHistory builds pacing split chart data for the last 90 days
+func (q *QueryService) buildPacingHistory(recent []ActivityWithMetrics) []float64 {
+	ninetyDaysAgo := time.Now().AddDate(0, 0, -EFHistoryDays)
 
-	// Filter activities within the 12-week window and collect IDs
-	twelveWeeksAgo := currentWeekStart.AddDate(0, 0, -7*(numWeeks-1))
-	var relevantActivities []store.Activity
-	var activityIDs []int64
-	for _, a := range activities {
-		if !a.StartDate.Before(twelveWeeksAgo) {
-			relevantActivities = append(relevantActivities, a)
-			activityIDs = append(activityIDs, a.ID)
+	var history []float64
+	for i := len(recent) - 1; i >= 0; i-- {
+		am := recent[i]
+		if am.Activity.StartDate.After(ninetyDaysAgo) && am.Metrics.PacingSplit != nil {
+			history = append(history, *am.Metrics.PacingSplit)
 		}
 	}
+	return history
+}
 
-	// Batch fetch all streams for relevant activities (fixes N+1 query)
-	streamsMap, err := q.store.GetStreamsForActivities(activityIDs)
-	if err != nil {
-		streamsMap = make(map[int64][]store.StreamPoint)
-	}
+// buildStrideHistory builds stride length chart data for the last 90 days
+func (q *QueryService) buildStrideHistory(recent []ActivityWithMetrics) []float64 {
+	ninetyDaysAgo := time.Now().AddDate(0, 0, -EFHistoryDays)
 
-	// Aggregate stats per week
-	for _, a := range relevantActivities {
-		weekIdx := q.findWeekIndex(a.StartDate, currentWeekStart, numWeeks)
-		if weekIdx < 0 {
-			continue
+	var history []float64
+	for i := len(recent) - 1; i >= 0; i-- {
+		am := recent[i]
+		if am.Activity.StartDate.After(ninetyDaysAgo) && am.Metrics.AvgStrideLength != nil {
+			history = append(history, *am.Metrics.AvgStrideLength)
 		}
+	}
+	return history
+}
 
-		mileage[weekIdx] += metersToMiles(a.Distance)
+// buildHRRHistory builds HR recovery chart data for the last 90 days. Only
+// runs with a hard effort have a value, so this draws on the full history
+// rather than just the recent activities.
+func (q *QueryService) buildHRRHistory(activities []store.Activity, metrics []store.ActivityMetrics) []float64 {
+	ninetyDaysAgo := time.Now().AddDate(0, 0, -EFHistoryDays)
 
-		streams := streamsMap[a.ID]
-		if len(streams) == 0 {
-			continue
+	var history []float64
+	for i := len(activities) - 1; i >= 0; i-- {
+		if activities[i].StartDate.After(ninetyDaysAgo) && metrics[i].HRR60 != nil {
+			history = append(history, *metrics[i].HRR60)
 		}
-
-		stats := AggregateStreamStats(streams)
-		hrSum[weekIdx] += stats.HRSum
-		hrCount[weekIdx] += stats.HRCount
-		cadenceSum[weekIdx] += stats.CadenceSum
-		cadenceCount[weekIdx] += stats.CadenceCount
 	}
+	return history
+}
+
+// buildWeeklyCharts builds the 12-week mileage, cadence, HR and vertical gain
+// chart data from the weekly summaries
+func (q *QueryService) buildWeeklyCharts() (mileage, avgCadence, avgHR, vertical []float64, labels []string) {
+	numWeeks := ChartWeeks
+	currentWeekStart := q.weekStart(q.bucketNow())
 
-	// Calculate averages
+	mileage = make([]float64, numWeeks)
 	avgCadence = make([]float64, numWeeks)
 	avgHR = make([]float64, numWeeks)
+	vertical = make([]float64, numWeeks)
+	labels = make([]string, numWeeks)
+
+	// Build labels
 	for i := 0; i < numWeeks; i++ {
-		if cadenceCount[i] > 0 {
-			avgCadence[i] = cadenceSum[i] / float64(cadenceCount[i])
+		weekStart := currentWeekStart.AddDate(0, 0, -7*(numWeeks-1-i))
+		labels[i] = q.weekLabel(weekStart)
+	}
+
+	summaries, err := q.getWeeklySummaries(numWeeks)
+	if err != nil {
+		// Dashboard can show partial data
+		return
+	}
+
+	for i, w := range summaries {
+		mileage[i] = metersToMiles(w.Distance)
+		vertical[i] = w.ElevationGain
+		if w.CadenceCount > 0 {
+			avgCadence[i] = w.CadenceSum / float64(w.CadenceCount)
 		}
-		if hrCount[i] > 0 {
-			avgHR[i] = hrSum[i] / float64(hrCount[i])
+		if w.HRCount > 0 {
+			avgHR[i] = w.HRSum / float64(w.HRCount)
 		}
 	}
 
 	return
 }
 
+// buildGoalConsistency scores the last GoalConsistencyWeeks completed weeks
+// against the weekly goal in miles, with the goal streak over up to
+// GoalStreakWeeks
+func (q *QueryService) buildGoalConsistency(goalMiles float64) (analysis.GoalConsistency, error) {
+	summaries, err := q.getWeeklySummaries(GoalStreakWeeks)
+	if err != nil {
+		return analysis.GoalConsistency{}, err
+	}
+	weekly := make([]float64, len(summaries))
+	for i, w := range summaries {
+		weekly[i] = metersToMiles(w.Distance)
+	}
+	return analysis.CalculateGoalConsistency(weekly, goalMiles, GoalConsistencyWeeks), nil
+}
+
 // findWeekIndex returns the index of the week bucket for the given date
 func (q *QueryService) findWeekIndex(date time.Time, currentWeekStart time.Time, numWeeks int) int {
 	for i := 0; i < numWeeks; i++ {
@@ -3,12 +3,14 @@ package service
 import (
 	"fmt"
 
+	"runner/internal/analysis"
 	"runner/internal/store"
 )
 
-// MileSplit represents stats for a single mile
+// MileSplit represents stats for a single mile, or a single kilometer in
+// KmSplits. Pace is per split distance.
 type MileSplit struct {
-	Mile     int
+	Mile     int // split number, starting at 1
 	Duration int     // seconds
 	Pace     string  // "M:SS" format
 	AvgHR    float64
@@ -23,23 +25,66 @@ type HRZoneTime struct {
 	Percent float64
 }
 
+// CadenceBandTime represents running time spent in a cadence band
+type CadenceBandTime struct {
+	Name    string
+	Seconds int
+	Percent float64
+}
+
 // ActivityDetail contains detailed info for a single activity
 type ActivityDetail struct {
 	Activity      ActivityWithMetrics
 	Splits        []MileSplit
+	KmSplits      []MileSplit
 	HRZones       []HRZoneTime
+	CadenceBands  []CadenceBandTime
 	PaceData      []float64 // pace per minute for charting (min/mile)
 	HRData        []float64 // HR per minute for charting
+	CadenceData   []float64 // cadence (spm) per minute for charting
+	DistanceData  []float64 // distance (m) covered by the end of each minute
 	TimeLabels    []string  // time labels for chart
 	AvgHR         float64
 	AvgCadence    float64
 	MaxHR         int // Observed max HR during this activity
 	ConfiguredMax int // Configured max HR used for zone calculations
 	ThresholdHR   int // Configured threshold HR (0 if using %maxHR zones)
+	Tags          []string
+	Note          string
+	TemperatureC  *float64 // Air temperature during the run, if recorded
+	Effort        *store.ActivityRPE // Perceived effort and feel; nil until logged
+	CustomMetrics []store.CustomMetric // Values of the custom metrics, by name
+	AdjustedEF    float64  // EF scaled to cool weather for the heat; 0 without a temperature
+	Performance   *analysis.Performance // Pace normalized for climbing, heat and distance; nil for short runs
+	Warnings      []string // Data-quality warnings from the anomaly flags
+	PacingGrade   string   // "Even", "Positive split", "Negative split"; "" if not computed
+	Hills         *analysis.HillStats // Time and pace by terrain; nil without grade data
+	Trim          *store.ActivityTrim // How the run was trimmed locally; nil if it wasn't
+	Correction    *store.DistanceCorrection // How a treadmill run's distance was corrected; nil if it wasn't
+	Stops         []analysis.Stop // Standstills and auto-pauses, left out of EF and decoupling
+	StoppedTime   int             // Seconds spent in stops
+	Details       *store.ActivityDetails // Description, device and shoes from Strava; nil until fetched
 }
 
-// GetActivityDetailByID returns detailed analysis for a single activity
+// GetActivityDetailByID returns detailed analysis for a single activity.
+// Recently viewed details are served from a cache until the store next
+// writes, so the caller must not modify the result.
 func (q *QueryService) GetActivityDetailByID(id int64) (*ActivityDetail, error) {
+	generation := q.store.Generation()
+	if detail, ok := q.details.get(id, generation); ok {
+		return detail, nil
+	}
+	detail, err := q.buildActivityDetail(id)
+	if err != nil {
+		return nil, err
+	}
+	q.details.put(id, generation, detail)
+	return detail, nil
+}
+
+// buildActivityDetail loads an activity and computes its splits, zones and
+// charts
+func (q *QueryService) buildActivityDetail(id int64) (*ActivityDetail, error) {
 	activity, err := q.store.GetActivity(id)
 	if err != nil {
 		return nil, err
@@ -60,12 +105,65 @@ func (q *QueryService) GetActivityDetailByID(id int64) (*ActivityDetail, error)
 	}
 	if metrics != nil {
 		detail.Activity.Metrics = *metrics
+		for _, flag := range metrics.AnomalyFlags {
+			detail.Warnings = append(detail.Warnings, analysis.AnomalyDescription(flag))
+		}
+		if metrics.PacingSplit != nil {
+			detail.PacingGrade = analysis.PacingGrade(*metrics.PacingSplit)
+		}
 	}
 
+	if detail.Tags, err = q.store.GetActivityTags(id); err != nil {
+		return nil, err
+	}
+	if detail.Note, err = q.store.GetActivityNote(id); err != nil {
+		return nil, err
+	}
+	if detail.TemperatureC, err = q.store.GetActivityTemperature(id); err != nil {
+		return nil, err
+	}
+	if detail.Effort, err = q.store.GetActivityRPE(id); err != nil {
+		return nil, err
+	}
+	if detail.CustomMetrics, err = q.store.GetCustomMetrics(id); err != nil {
+		return nil, err
+	}
+	if detail.Trim, err = q.store.GetActivityTrim(id); err != nil {
+		return nil, err
+	}
+	if detail.Correction, err = q.store.GetDistanceCorrection(id); err != nil {
+		return nil, err
+	}
+	if detail.Details, err = q.store.GetActivityDetails(id); err != nil {
+		return nil, err
+	}
+	if detail.Activity.Social, err = q.store.GetActivitySocial(id); err != nil {
+		return nil, err
+	}
+	if detail.TemperatureC != nil && metrics != nil && metrics.EfficiencyFactor != nil {
+		detail.AdjustedEF = analysis.HeatAdjustedEF(*metrics.EfficiencyFactor, *detail.TemperatureC)
+	}
+	detail.Performance = q.performance(*activity, detail.TemperatureC)
+
 	if len(streams) == 0 {
 		return detail, nil
 	}
 
+	// Splits stored in the metrics phase; calculateFromStreams computes them
+	// for runs analyzed before they were
+	miles, err := q.store.GetActivitySplits(id, store.SplitsStandard)
+	if err != nil {
+		return nil, err
+	}
+	km, err := q.store.GetActivitySplits(id, store.SplitsMetric)
+	if err != nil {
+		return nil, err
+	}
+	if len(miles) > 0 || len(km) > 0 {
+		detail.Splits = mileSplits(miles, MetersPerMile)
+		detail.KmSplits = mileSplits(km, MetersPerKm)
+	}
+
 	// Calculate splits, HR zones, and chart data from streams
 	detail.calculateFromStreams(streams, activity.Distance, int(q.athleteCfg.MaxHR), int(q.athleteCfg.ThresholdHR))
 
@@ -73,40 +171,9 @@ func (q *QueryService) GetActivityDetailByID(id int64) (*ActivityDetail, error)
 }
 
 func (d *ActivityDetail) calculateFromStreams(streams []store.StreamPoint, totalDistance float64, configuredMaxHR int, thresholdHR int) {
-	// Mile splits
-	currentMile := 1
-	mileStartIdx := 0
-	var lastDistance float64
-
-	for i, p := range streams {
-		if p.Distance == nil {
-			continue
-		}
-
-		dist := *p.Distance
-		mileThreshold := float64(currentMile) * MetersPerMile
-
-		if dist >= mileThreshold && lastDistance < mileThreshold {
-			// Completed a mile
-			split := d.calculateSplit(streams, mileStartIdx, i, currentMile)
-			d.Splits = append(d.Splits, split)
-			currentMile++
-			mileStartIdx = i
-		}
-		lastDistance = dist
-	}
-
-	// Add final partial mile if significant (> 0.1 mile)
-	remainingDist := totalDistance - float64(currentMile-1)*MetersPerMile
-	if remainingDist > PartialMileThreshold && mileStartIdx < len(streams)-1 {
-		split := d.calculateSplit(streams, mileStartIdx, len(streams)-1, currentMile)
-		// Adjust pace for partial mile
-		if remainingDist > 0 {
-			partialMiles := remainingDist / MetersPerMile
-			split.Duration = int(float64(split.Duration) / partialMiles)
-			split.Pace = formatPace(split.Duration)
-		}
-		d.Splits = append(d.Splits, split)
+	if d.Splits == nil && d.KmSplits == nil {
+		d.Splits = d.calculateSplits(streams, totalDistance, MetersPerMile, PartialMileThreshold)
+		d.KmSplits = d.calculateSplits(streams, totalDistance, MetersPerKm, PartialKmThreshold)
 	}
 
 	// HR zones (using 5-zone model based on configured max HR)
@@ -115,9 +182,14 @@ func (d *ActivityDetail) calculateFromStreams(streams []store.StreamPoint, total
 
 	// Use configured max HR for zone calculations (not the activity's max)
 	if configuredMaxHR > 0 {
-		d.HRZones = d.calculateHRZones(streams, configuredMaxHR, thresholdHR)
+		d.HRZones = calculateHRZones(streams, configuredMaxHR, thresholdHR)
 	}
 
+	d.CadenceBands = calculateCadenceBands(streams)
+	d.Hills = analysis.ComputeHillStats(streams)
+	d.Stops = analysis.FindStops(streams)
+	d.StoppedTime = analysis.StoppedSeconds(d.Stops)
+
 	// Calculate averages using helper
 	stats := AggregateStreamStats(streams)
 	d.AvgHR = stats.AvgHR()
@@ -145,6 +217,9 @@ func (d *ActivityDetail) buildChartData(streams []store.StreamPoint) {
 		paceCount int
 		hrSum     float64
 		hrCount   int
+		cadSum    float64
+		cadCount  int
+		distance  float64
 	})
 
 	var prevDist float64
@@ -152,6 +227,12 @@ func (d *ActivityDetail) buildChartData(streams []store.StreamPoint) {
 	for _, p := range streams {
 		minute := p.TimeOffset / SecondsPerMinute
 
+		if p.Distance != nil && *p.Distance > minuteData[minute].distance {
+			entry := minuteData[minute]
+			entry.distance = *p.Distance
+			minuteData[minute] = entry
+		}
+
 		// Pace calculation
 		if p.Distance != nil && p.TimeOffset > prevTime {
 			distDelta := *p.Distance - prevDist
@@ -177,6 +258,13 @@ func (d *ActivityDetail) buildChartData(streams []store.StreamPoint) {
 			entry.hrCount++
 			minuteData[minute] = entry
 		}
+
+		if isValidCadence(p.Cadence) {
+			entry := minuteData[minute]
+			entry.cadSum += float64(*p.Cadence) * StravaCadenceMultiplier
+			entry.cadCount++
+			minuteData[minute] = entry
+		}
 	}
 
 	// Find max minute
@@ -206,32 +294,34 @@ func (d *ActivityDetail) buildChartData(streams []store.StreamPoint) {
 			d.HRData = append(d.HRData, 0)
 		}
 
-		d.TimeLabels = append(d.TimeLabels, formatMinutes(m))
-	}
-}
+		if entry.cadCount > 0 {
+			d.CadenceData = append(d.CadenceData, entry.cadSum/float64(entry.cadCount))
+		} else if len(d.CadenceData) > 0 {
+			d.CadenceData = append(d.CadenceData, d.CadenceData[len(d.CadenceData)-1])
+		} else {
+			d.CadenceData = append(d.CadenceData, 0)
+		}
 
-func (d *ActivityDetail) calculateSplit(streams []store.StreamPoint, startIdx, endIdx int, mile int) MileSplit {
-	split := MileSplit{Mile: mile}
+		if entry.distance > 0 {
+			d.DistanceData = append(d.DistanceData, entry.distance)
+		} else if len(d.DistanceData) > 0 {
+			d.DistanceData = append(d.DistanceData, d.DistanceData[len(d.DistanceData)-1])
+		} else {
+			d.DistanceData = append(d.DistanceData, 0)
+		}
 
-	if endIdx <= startIdx || endIdx >= len(streams) {
-		return split
+		d.TimeLabels = append(d.TimeLabels, formatMinutes(m))
 	}
+}
 
-	startTime := streams[startIdx].TimeOffset
-	endTime := streams[endIdx].TimeOffset
-	split.Duration = endTime - startTime
-	split.Pace = formatPace(split.Duration)
-
-	// Calculate averages for this split using the slice
-	splitStreams := streams[startIdx : endIdx+1]
-	stats := AggregateStreamStats(splitStreams)
-	split.AvgHR = stats.AvgHR()
-	split.AvgCad = stats.AvgCadence()
-
-	return split
+// calculateSplits divides the run into splits of splitMeters each. A final
+// partial split longer than partialThreshold is kept, with its pace scaled to
+// the full split distance. Runs analyzed since splits were stored use those.
+func (d *ActivityDetail) calculateSplits(streams []store.StreamPoint, totalDistance, splitMeters, partialThreshold float64) []MileSplit {
+	return mileSplits(computeSplits(d.Activity.Activity.ID, "", streams, totalDistance, splitMeters, partialThreshold), splitMeters)
 }
 
-func (d *ActivityDetail) calculateHRZones(streams []store.StreamPoint, maxHR int, thresholdHR int) []HRZoneTime {
+func calculateHRZones(streams []store.StreamPoint, maxHR int, thresholdHR int) []HRZoneTime {
 	// Guard against division by zero - return empty zones if maxHR is invalid
 	if maxHR <= 0 {
 		return nil
@@ -303,6 +393,45 @@ func (d *ActivityDetail) calculateHRZones(streams []store.StreamPoint, maxHR int
 	return zones
 }
 
+// calculateCadenceBands buckets running time by cadence. Only samples with
+// both cadence and moving speed count, so stops don't land in the lowest band.
+// Returns nil when the activity has no cadence data.
+func calculateCadenceBands(streams []store.StreamPoint) []CadenceBandTime {
+	bands := []CadenceBandTime{
+		{Name: "<160 spm"},
+		{Name: "160-170 spm"},
+		{Name: "170-180 spm"},
+		{Name: "180+ spm"},
+	}
+
+	totalSeconds := 0
+	for _, p := range streams {
+		if !isValidCadence(p.Cadence) || p.VelocitySmooth == nil || *p.VelocitySmooth <= MinSpeedForPace {
+			continue
+		}
+
+		spm := float64(*p.Cadence) * StravaCadenceMultiplier
+		totalSeconds++
+
+		band := len(CadenceBandLimits)
+		for i, limit := range CadenceBandLimits {
+			if spm < limit {
+				band = i
+				break
+			}
+		}
+		bands[band].Seconds++
+	}
+
+	if totalSeconds == 0 {
+		return nil
+	}
+	for i := range bands {
+		bands[i].Percent = float64(bands[i].Seconds) / float64(totalSeconds) * 100
+	}
+	return bands
+}
+
 func formatPace(seconds int) string {
 	mins := seconds / SecondsPerMinute
 	secs := seconds % SecondsPerMinute
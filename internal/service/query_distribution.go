@@ -0,0 +1,144 @@
+package service
+
+import (
+	"time"
+
+	"runner/internal/store"
+)
+
+// PolarizationTargetLowPct is the 80/20 target share of time in Z1-Z2
+const PolarizationTargetLowPct = 80.0
+
+// ZoneDistribution is time in each HR zone over a period
+type ZoneDistribution struct {
+	ZoneSeconds  [5]int // Z1..Z5
+	TotalSeconds int
+	LowPct       float64 // Z1+Z2 share of time, %
+	HighPct      float64 // Z3+ share of time, %
+}
+
+// add accumulates zone seconds from one activity
+func (z *ZoneDistribution) add(seconds [5]int) {
+	for i, secs := range seconds {
+		z.ZoneSeconds[i] += secs
+		z.TotalSeconds += secs
+	}
+}
+
+// finish computes the low/high percentages from the accumulated seconds
+func (z *ZoneDistribution) finish() {
+	if z.TotalSeconds == 0 {
+		return
+	}
+	low := z.ZoneSeconds[0] + z.ZoneSeconds[1]
+	z.LowPct = float64(low) / float64(z.TotalSeconds) * 100
+	z.HighPct = 100 - z.LowPct
+}
+
+// WeeklyZoneDistribution is the zone distribution for one week
+type WeeklyZoneDistribution struct {
+	WeekStart time.Time
+	Label     string // "Jan 06"
+	ZoneDistribution
+}
+
+// TrainingDistribution contains all data for the training distribution screen
+type TrainingDistribution struct {
+	Weeks     []WeeklyZoneDistribution // oldest first
+	Overall   ZoneDistribution
+	TargetLow float64
+}
+
+// GetTrainingDistribution aggregates time in HR zone per week for the last
+// numWeeks weeks, including the current one
+func (q *QueryService) GetTrainingDistribution(numWeeks int) (*TrainingDistribution, error) {
+	data := &TrainingDistribution{TargetLow: PolarizationTargetLowPct}
+
+	currentWeekStart := q.weekStart(q.bucketNow())
+	firstWeekStart := currentWeekStart.AddDate(0, 0, -7*(numWeeks-1))
+
+	data.Weeks = make([]WeeklyZoneDistribution, numWeeks)
+	for i := range data.Weeks {
+		start := firstWeekStart.AddDate(0, 0, 7*i)
+		data.Weeks[i] = WeeklyZoneDistribution{WeekStart: start, Label: q.weekLabel(start)}
+	}
+
+	relevant, relevantMetrics, err := q.store.GetActivitiesWithMetricsBetween(firstWeekStart, currentWeekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+	if len(relevant) == 0 {
+		return data, nil
+	}
+
+	zoneSeconds, err := q.loadZoneSeconds(relevant, relevantMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range relevant {
+		week := q.findWeekIndex(q.store.BucketTime(a), currentWeekStart, numWeeks)
+		if week < 0 {
+			continue
+		}
+
+		data.Weeks[week].add(zoneSeconds[a.ID])
+		data.Overall.add(zoneSeconds[a.ID])
+	}
+
+	for i := range data.Weeks {
+		data.Weeks[i].finish()
+	}
+	data.Overall.finish()
+
+	return data, nil
+}
+
+// loadZoneSeconds returns time in each HR zone per activity. It prefers zone
+// seconds cached at sync time and only loads streams for activities analyzed
+// before the cache existed.
+func (q *QueryService) loadZoneSeconds(activities []store.Activity, metrics []store.ActivityMetrics) (map[int64][5]int, error) {
+	zoneSeconds := make(map[int64][5]int, len(activities))
+	var missingIDs []int64
+	for i, a := range activities {
+		if secs, ok := cachedZoneSeconds(metrics[i]); ok {
+			zoneSeconds[a.ID] = secs
+		} else {
+			missingIDs = append(missingIDs, a.ID)
+		}
+	}
+	if len(missingIDs) == 0 {
+		return zoneSeconds, nil
+	}
+
+	streamsMap, err := q.store.GetStreamsForActivities(missingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	maxHR, thresholdHR := int(q.athleteCfg.MaxHR), int(q.athleteCfg.ThresholdHR)
+	for _, id := range missingIDs {
+		var secs [5]int
+		for i, zone := range calculateHRZones(streamsMap[id], maxHR, thresholdHR) {
+			if i < len(secs) {
+				secs[i] = zone.Seconds
+			}
+		}
+		zoneSeconds[id] = secs
+	}
+	return zoneSeconds, nil
+}
+
+// cachedZoneSeconds returns the per-zone seconds stored with an activity's
+// metrics, if they were computed
+func cachedZoneSeconds(m store.ActivityMetrics) ([5]int, bool) {
+	fields := []*int{m.Z1Seconds, m.Z2Seconds, m.Z3Seconds, m.Z4Seconds, m.Z5Seconds}
+	var secs [5]int
+	for i, f := range fields {
+		if f == nil {
+			return secs, false
+		}
+		secs[i] = *f
+	}
+	return secs, true
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"runner/internal/analysis"
+)
+
+// DurabilityRun is one run on the durability scatter
+type DurabilityRun struct {
+	ActivityID int64
+	Date       time.Time
+	Minutes    float64 // moving time
+	Distance   float64 // meters
+	Decoupling *float64
+	EF         *float64
+}
+
+// DurabilityFit is decoupling and EF fitted against one measure of run
+// length, minutes or meters
+type DurabilityFit struct {
+	Decoupling *analysis.LogCurve // nil with too few runs to fit
+	EF         *analysis.LogCurve
+
+	// Breakdown is the length at which the fitted decoupling reaches
+	// analysis.DecouplingLimit, or zero when it doesn't rise
+	Breakdown float64
+}
+
+// DurabilityData shows how long the aerobic base currently holds: aerobic
+// decoupling and EF against run length, with curves fitted to each
+type DurabilityData struct {
+	Months int
+	Runs   []DurabilityRun // oldest first
+
+	ByDuration DurabilityFit
+	ByDistance DurabilityFit
+
+	LongestMinutes  float64
+	LongestDistance float64
+}
+
+// GetDurability returns the runs of DurabilityMinRunSecs or longer from the
+// last months calendar months, with decoupling and EF fitted against their
+// duration and distance
+func (q *QueryService) GetDurability(months int) (*DurabilityData, error) {
+	now := q.bucketNow()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	activities, metrics, err := q.store.GetActivitiesWithMetricsBetween(first, now.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	data := &DurabilityData{Months: months}
+	for i := len(activities) - 1; i >= 0; i-- {
+		a, m := activities[i], metrics[i]
+		if a.MovingTime < DurabilityMinRunSecs || (m.AerobicDecoupling == nil && m.EfficiencyFactor == nil) {
+			continue
+		}
+		run := DurabilityRun{
+			ActivityID: a.ID,
+			Date:       q.store.BucketTime(a),
+			Minutes:    float64(a.MovingTime) / 60,
+			Distance:   a.Distance,
+			Decoupling: m.AerobicDecoupling,
+			EF:         m.EfficiencyFactor,
+		}
+		data.Runs = append(data.Runs, run)
+		data.LongestMinutes = max(data.LongestMinutes, run.Minutes)
+		data.LongestDistance = max(data.LongestDistance, run.Distance)
+	}
+
+	data.ByDuration = fitDurability(data.Runs, func(r DurabilityRun) float64 { return r.Minutes })
+	data.ByDistance = fitDurability(data.Runs, func(r DurabilityRun) float64 { return r.Distance })
+	return data, nil
+}
+
+// fitDurability fits decoupling and EF against the run length given by x
+func fitDurability(runs []DurabilityRun, x func(DurabilityRun) float64) DurabilityFit {
+	var decX, decY, efX, efY []float64
+	for _, r := range runs {
+		if r.Decoupling != nil {
+			decX = append(decX, x(r))
+			decY = append(decY, *r.Decoupling)
+		}
+		if r.EF != nil {
+			efX = append(efX, x(r))
+			efY = append(efY, *r.EF)
+		}
+	}
+
+	var fit DurabilityFit
+	if curve, ok := analysis.FitLogCurve(decX, decY); ok {
+		fit.Decoupling = &curve
+		if at, ok := curve.Reaches(analysis.DecouplingLimit); ok {
+			fit.Breakdown = at
+		}
+	}
+	if curve, ok := analysis.FitLogCurve(efX, efY); ok {
+		fit.EF = &curve
+	}
+	return fit
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"runner/internal/store"
+)
+
+// GetActivitiesForExport returns every run started on or after since, oldest
+// first, with its metrics when they have been computed and its effort, tags
+// and note when logged. Excluded runs are left out. A zero since exports
+// everything.
+func (q *QueryService) GetActivitiesForExport(since time.Time) ([]ActivityWithMetrics, error) {
+	metrics, err := q.store.GetAllMetrics()
+	if err != nil {
+		return nil, err
+	}
+	metricsByActivity := make(map[int64]int, len(metrics))
+	for i, m := range metrics {
+		metricsByActivity[m.ActivityID] = i
+	}
+	rpes, err := q.store.GetActivityRPEs()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := q.store.GetAllActivityTags()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := q.store.GetActivityNotes()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ActivityWithMetrics
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		activities, err := q.store.ListActivities(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		done := len(activities) < PeriodStatsActivityLimit
+		for _, a := range activities {
+			// Activities come newest first
+			if a.StartDate.Before(since) {
+				done = true
+				break
+			}
+			if a.Excluded {
+				continue
+			}
+			row := ActivityWithMetrics{Activity: a, Metrics: store.ActivityMetrics{ActivityID: a.ID}}
+			if i, ok := metricsByActivity[a.ID]; ok {
+				row.Metrics = metrics[i]
+			}
+			if rpe, ok := rpes[a.ID]; ok {
+				row.Effort = &rpe
+			}
+			row.Tags = tags[a.ID]
+			row.Note = notes[a.ID]
+			result = append(result, row)
+		}
+
+		if done {
+			break
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Activity.StartDate.Before(result[j].Activity.StartDate)
+	})
+	return result, nil
+}
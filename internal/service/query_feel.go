@@ -0,0 +1,81 @@
+package service
+
+import (
+	"time"
+
+	"runner/internal/analysis"
+)
+
+// FeelPoint is one run with a logged feel, against the form it started on
+type FeelPoint struct {
+	ActivityID int64
+	Date       time.Time
+	TSB        float64 // form at the end of the day before the run
+	Feel       int     // store.MinFeel (awful) to store.MaxFeel (great)
+	RPE        int
+}
+
+// FeelVsFormData is how runs felt against form over the last calendar
+// months. Correlation is Pearson's r between TSB and feel, set once enough
+// runs have both.
+type FeelVsFormData struct {
+	Months         int
+	Points         []FeelPoint           // oldest first
+	Fit            *analysis.LinearTrend // feel against TSB; nil with too few points
+	Correlation    float64
+	HasCorrelation bool
+}
+
+// GetFeelVsForm returns the runs of the last months calendar months that
+// have a feel logged, each with the TSB it started on. Form is read from
+// the day before the run, so the run's own load doesn't count against it.
+func (q *QueryService) GetFeelVsForm(months int) (*FeelVsFormData, error) {
+	now := q.bucketNow()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	activities, _, err := q.store.GetActivitiesWithMetricsBetween(first, now.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	rpes, err := q.store.GetActivityRPEs()
+	if err != nil {
+		return nil, err
+	}
+	days, err := q.store.GetFitnessTrends(first.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	tsb := make(map[string]float64, len(days))
+	for _, d := range days {
+		if d.TSB != nil {
+			tsb[d.Date] = *d.TSB
+		}
+	}
+
+	data := &FeelVsFormData{Months: months}
+	var xs, ys []float64
+	for i := len(activities) - 1; i >= 0; i-- {
+		a := activities[i]
+		rpe, ok := rpes[a.ID]
+		if !ok || rpe.Feel == nil {
+			continue
+		}
+		date := calendarDay(q.store.BucketTime(a))
+		form, ok := tsb[date.AddDate(0, 0, -1).Format(fitnessTrendDateFormat)]
+		if !ok {
+			continue
+		}
+		data.Points = append(data.Points, FeelPoint{
+			ActivityID: a.ID,
+			Date:       date,
+			TSB:        form,
+			Feel:       *rpe.Feel,
+			RPE:        rpe.RPE,
+		})
+		xs, ys = append(xs, form), append(ys, float64(*rpe.Feel))
+	}
+	data.Correlation, data.HasCorrelation = analysis.Correlation(xs, ys)
+	if fit, ok := analysis.FitLinearTrend(xs, ys); ok {
+		data.Fit = &fit
+	}
+	return data, nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// InjuryDisplay is one injury on the injury log with the training that led
+// into it
+type InjuryDisplay struct {
+	store.Injury
+	Days    int      // how long it lasted, or has lasted so far
+	RampPct *float64 // week before onset vs the four weeks before that
+	ACWR    *float64 // acute:chronic load (ATL/CTL) the day before onset
+}
+
+// Active reports whether the injury hasn't cleared up yet
+func (d InjuryDisplay) Active() bool {
+	return d.ResolvedOn == nil
+}
+
+// InjuryLoadAnalysis compares the load going into injuries with the load
+// across all training
+type InjuryLoadAnalysis struct {
+	RampInjuries  int     // injuries with enough history for a ramp
+	AvgRampBefore float64 // average ramp into those injuries, %
+	TypicalRamp   float64 // average ramp over every day of training, %
+
+	ACWRInjuries  int
+	AvgACWRBefore float64
+	TypicalACWR   float64
+}
+
+// InjuryLog is the injury screen: the log, how load looked going into
+// injuries, and weekly charts to overlay them on
+type InjuryLog struct {
+	Injuries []InjuryDisplay // most recent onset first
+	Analysis InjuryLoadAnalysis
+
+	// One entry per week for the last InjuryChartWeeks, oldest first
+	WeekLabels   []string
+	WeeklyMiles  []float64
+	WeeklyCTL    []float64 // fitness at the end of each week
+	InjuredWeeks []bool    // an injury was active during the week
+}
+
+// ParseInjuryDate parses the onset or resolved date of an injury as
+// YYYY-MM-DD. Blank means today; future dates are rejected.
+func ParseInjuryDate(input string, today time.Time) (time.Time, error) {
+	today = calendarDay(today)
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return today, nil
+	}
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", input)
+	}
+	if d.After(today) {
+		return time.Time{}, fmt.Errorf("date %s is in the future", s)
+	}
+	return d, nil
+}
+
+// ParseInjurySeverity parses a severity from 1 (niggle) to MaxInjurySeverity
+// (can't run)
+func ParseInjurySeverity(input string) (int, error) {
+	severity, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || severity < 1 || severity > MaxInjurySeverity {
+		return 0, fmt.Errorf("severity must be 1-%d", MaxInjurySeverity)
+	}
+	return severity, nil
+}
+
+// AddInjury logs a new injury and returns its ID
+func (q *QueryService) AddInjury(injury store.Injury) (int64, error) {
+	injury.BodyPart = strings.TrimSpace(injury.BodyPart)
+	injury.Notes = strings.TrimSpace(injury.Notes)
+	if injury.BodyPart == "" {
+		return 0, errors.New("body part is required")
+	}
+	if injury.Severity < 1 || injury.Severity > MaxInjurySeverity {
+		return 0, fmt.Errorf("severity must be 1-%d", MaxInjurySeverity)
+	}
+	return q.store.CreateInjury(&injury)
+}
+
+// ResolveInjury records the day an injury cleared up; nil reopens it
+func (q *QueryService) ResolveInjury(id int64, resolvedOn *time.Time) error {
+	return q.store.SetInjuryResolved(id, resolvedOn)
+}
+
+// DeleteInjury removes an injury from the log
+func (q *QueryService) DeleteInjury(id int64) error {
+	return q.store.DeleteInjury(id)
+}
+
+// GetInjuryLog returns the injury log alongside the training load that
+// preceded each injury
+func (q *QueryService) GetInjuryLog() (*InjuryLog, error) {
+	injuries, err := q.store.ListInjuries()
+	if err != nil {
+		return nil, err
+	}
+	runs, err := q.store.ListTrainingLoads()
+	if err != nil {
+		return nil, err
+	}
+	return q.buildInjuryLog(injuries, runs, calendarDay(q.bucketNow())), nil
+}
+
+// buildInjuryLog works through every day from the first run to today,
+// measuring the ramp and acute:chronic ratio each day so injuries can be
+// compared against typical training
+func (q *QueryService) buildInjuryLog(injuries []store.Injury, runs []store.TrainingLoad, today time.Time) *InjuryLog {
+	result := &InjuryLog{Injuries: make([]InjuryDisplay, len(injuries))}
+	for i, inj := range injuries {
+		end := today
+		if inj.ResolvedOn != nil {
+			end = *inj.ResolvedOn
+		}
+		result.Injuries[i] = InjuryDisplay{Injury: inj, Days: daysBetween(inj.StartedOn, end)}
+	}
+
+	// Daily distance and load from the first run through today
+	var start time.Time
+	if len(runs) > 0 {
+		start = calendarDay(runs[0].BucketDate)
+	} else {
+		start = today
+	}
+	numDays := daysBetween(start, today) + 1
+	if numDays < 1 {
+		numDays = 1
+	}
+	daily := make([]float64, numDays)
+	loads := make([]float64, numDays)
+	for _, r := range runs {
+		d := daysBetween(start, calendarDay(r.BucketDate))
+		if d < 0 || d >= numDays {
+			continue
+		}
+		daily[d] += r.Distance
+		if load := runLoad(q.loadModel, r.TRIMP, r.HRSS, r.RPE, r.MovingTime); load != nil {
+			loads[d] += *load
+		}
+	}
+	dailyLoads := make([]analysis.DailyLoad, numDays)
+	for d := range loads {
+		dailyLoads[d] = analysis.DailyLoad{Date: start.AddDate(0, 0, d), TRIMP: loads[d]}
+	}
+	fitness := analysis.CalculateFitnessTrend(dailyLoads)
+
+	// acwr is the ratio going into day d, i.e. at the end of the day before
+	acwr := func(d int) (float64, bool) {
+		prev := d - 1
+		if prev < ACWRWarmupDays || prev >= len(fitness) || fitness[prev].CTL <= 0 {
+			return 0, false
+		}
+		return fitness[prev].ATL / fitness[prev].CTL, true
+	}
+
+	var rampSum, acwrSum float64
+	var rampDays, acwrDays int
+	for d := 0; d <= numDays; d++ {
+		if pct, ok := analysis.WeeklyRamp(daily, d); ok {
+			rampSum += pct
+			rampDays++
+		}
+		if ratio, ok := acwr(d); ok {
+			acwrSum += ratio
+			acwrDays++
+		}
+	}
+	a := &result.Analysis
+	if rampDays > 0 {
+		a.TypicalRamp = rampSum / float64(rampDays)
+	}
+	if acwrDays > 0 {
+		a.TypicalACWR = acwrSum / float64(acwrDays)
+	}
+
+	for i := range result.Injuries {
+		inj := &result.Injuries[i]
+		d := daysBetween(start, inj.StartedOn)
+		if pct, ok := analysis.WeeklyRamp(daily, d); ok {
+			inj.RampPct = &pct
+			a.RampInjuries++
+			a.AvgRampBefore += pct
+		}
+		if ratio, ok := acwr(d); ok {
+			inj.ACWR = &ratio
+			a.ACWRInjuries++
+			a.AvgACWRBefore += ratio
+		}
+	}
+	if a.RampInjuries > 0 {
+		a.AvgRampBefore /= float64(a.RampInjuries)
+	}
+	if a.ACWRInjuries > 0 {
+		a.AvgACWRBefore /= float64(a.ACWRInjuries)
+	}
+
+	// Weekly charts ending with the current week
+	currentWeek := q.weekStart(today)
+	for w := 0; w < InjuryChartWeeks; w++ {
+		weekStart := currentWeek.AddDate(0, 0, -7*(InjuryChartWeeks-1-w))
+		weekEnd := weekStart.AddDate(0, 0, 7)
+
+		var distance float64
+		for day := weekStart; day.Before(weekEnd); day = day.AddDate(0, 0, 1) {
+			if d := daysBetween(start, day); d >= 0 && d < numDays {
+				distance += daily[d]
+			}
+		}
+		var ctl float64
+		last := daysBetween(start, weekEnd) - 1
+		if last >= len(fitness) {
+			last = len(fitness) - 1
+		}
+		if last >= 0 {
+			ctl = fitness[last].CTL
+		}
+		injured := false
+		for _, inj := range injuries {
+			if inj.StartedOn.Before(weekEnd) && (inj.ResolvedOn == nil || !inj.ResolvedOn.Before(weekStart)) {
+				injured = true
+				break
+			}
+		}
+
+		result.WeekLabels = append(result.WeekLabels, q.weekLabel(weekStart))
+		result.WeeklyMiles = append(result.WeeklyMiles, metersToMiles(distance))
+		result.WeeklyCTL = append(result.WeeklyCTL, ctl)
+		result.InjuredWeeks = append(result.InjuredWeeks, injured)
+	}
+
+	return result
+}
+
+// daysBetween counts whole calendar days from a to b, both midnight UTC
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
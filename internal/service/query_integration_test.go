@@ -1,10 +1,13 @@
 package service
 
 import (
-	"database/sql"
+	"math"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	"runner/internal/analysis"
 	"runner/internal/config"
 	"runner/internal/store"
 
@@ -24,114 +27,11 @@ func testAthleteConfig() config.AthleteConfig {
 func openTestDB(t *testing.T) *store.Store {
 	t.Helper()
 
-	db, err := sql.Open("sqlite", ":memory:")
+	db, err := store.OpenMemory()
 	if err != nil {
 		t.Fatalf("failed to open in-memory database: %v", err)
 	}
-
-	// Enable foreign keys
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		db.Close()
-		t.Fatalf("failed to enable foreign keys: %v", err)
-	}
-
-	// Run migrations inline (copied from store/migrations.go)
-	migrations := []string{
-		`CREATE TABLE IF NOT EXISTS auth (
-			id INTEGER PRIMARY KEY CHECK (id = 1),
-			athlete_id INTEGER NOT NULL,
-			access_token TEXT NOT NULL,
-			refresh_token TEXT NOT NULL,
-			expires_at INTEGER NOT NULL,
-			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS activities (
-			id INTEGER PRIMARY KEY,
-			athlete_id INTEGER NOT NULL,
-			name TEXT NOT NULL,
-			type TEXT NOT NULL,
-			start_date TEXT NOT NULL,
-			start_date_local TEXT NOT NULL,
-			timezone TEXT,
-			distance REAL NOT NULL,
-			moving_time INTEGER NOT NULL,
-			elapsed_time INTEGER NOT NULL,
-			total_elevation_gain REAL,
-			average_speed REAL,
-			max_speed REAL,
-			average_heartrate REAL,
-			max_heartrate REAL,
-			average_cadence REAL,
-			suffer_score INTEGER,
-			has_heartrate INTEGER NOT NULL,
-			streams_synced INTEGER DEFAULT 0,
-			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE INDEX IF NOT EXISTS idx_activities_start_date ON activities(start_date)`,
-		`CREATE TABLE IF NOT EXISTS streams (
-			activity_id INTEGER NOT NULL,
-			time_offset INTEGER NOT NULL,
-			latlng_lat REAL,
-			latlng_lng REAL,
-			altitude REAL,
-			velocity_smooth REAL,
-			heartrate INTEGER,
-			cadence INTEGER,
-			grade_smooth REAL,
-			distance REAL,
-			PRIMARY KEY (activity_id, time_offset),
-			FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
-		)`,
-		`CREATE TABLE IF NOT EXISTS activity_metrics (
-			activity_id INTEGER PRIMARY KEY,
-			efficiency_factor REAL,
-			aerobic_decoupling REAL,
-			cardiac_drift REAL,
-			pace_at_z1 REAL,
-			pace_at_z2 REAL,
-			pace_at_z3 REAL,
-			trimp REAL,
-			hrss REAL,
-			data_quality_score REAL,
-			steady_state_pct REAL,
-			computed_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
-		)`,
-		`CREATE TABLE IF NOT EXISTS fitness_trends (
-			date TEXT PRIMARY KEY,
-			ctl REAL,
-			atl REAL,
-			tsb REAL,
-			efficiency_factor_7d REAL,
-			efficiency_factor_28d REAL,
-			efficiency_factor_90d REAL,
-			run_count_7d INTEGER,
-			total_distance_7d REAL,
-			total_time_7d INTEGER,
-			computed_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS sync_state (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-	}
-
-	for _, m := range migrations {
-		if _, err := db.Exec(m); err != nil {
-			db.Close()
-			t.Fatalf("failed to run migration: %v", err)
-		}
-	}
-
-	// Create store using test helper
-	s := store.NewTestStore(db)
-	t.Cleanup(func() {
-		s.Close()
-	})
-	return s
+	return db
 }
 
 // Helper to create a float64 pointer
@@ -364,6 +264,54 @@ func TestQueryService_GetActivityDetailByID(t *testing.T) {
 		if detail.AvgCadence == 0 {
 			t.Error("expected non-zero AvgCadence")
 		}
+
+		// Distance by minute lines up with the other chart series
+		if len(detail.DistanceData) != len(detail.PaceData) {
+			t.Fatalf("expected %d distance points, got %d", len(detail.PaceData), len(detail.DistanceData))
+		}
+		if got, want := detail.DistanceData[0], 59*3.33; math.Abs(got-want) > 1e-9 {
+			t.Errorf("distance after minute 1 = %.2f, want %.2f", got, want)
+		}
+	})
+	t.Run("includes tags and note", func(t *testing.T) {
+		if err := svc.SetActivityTags(200, []string{" Race", "race", "new shoes "}); err != nil {
+			t.Fatalf("SetActivityTags failed: %v", err)
+		}
+		if err := svc.SetActivityNote(200, "  windy  "); err != nil {
+			t.Fatalf("SetActivityNote failed: %v", err)
+		}
+
+		detail, err := svc.GetActivityDetailByID(200)
+		if err != nil {
+			t.Fatalf("GetActivityDetailByID failed: %v", err)
+		}
+
+		if len(detail.Tags) != 2 || detail.Tags[0] != "new-shoes" || detail.Tags[1] != "race" {
+			t.Errorf("expected tags [new-shoes race], got %v", detail.Tags)
+		}
+		if detail.Note != "windy" {
+			t.Errorf("expected note %q, got %q", "windy", detail.Note)
+		}
+	})
+	t.Run("includes Strava details once fetched", func(t *testing.T) {
+		detail, err := svc.GetActivityDetailByID(200)
+		if err != nil {
+			t.Fatalf("GetActivityDetailByID failed: %v", err)
+		}
+		if detail.Details != nil {
+			t.Errorf("expected no details before fetching, got %+v", detail.Details)
+		}
+
+		if err := db.SaveActivityDetails(store.ActivityDetails{ActivityID: 200, Description: "Parkrun", DeviceName: "COROS PACE 3", FetchedAt: now}, nil, nil); err != nil {
+			t.Fatalf("SaveActivityDetails failed: %v", err)
+		}
+		detail, err = svc.GetActivityDetailByID(200)
+		if err != nil {
+			t.Fatalf("GetActivityDetailByID failed: %v", err)
+		}
+		if detail.Details == nil || detail.Details.Description != "Parkrun" || detail.Details.DeviceName != "COROS PACE 3" {
+			t.Errorf("expected the saved details, got %+v", detail.Details)
+		}
 	})
 }
 
@@ -446,6 +394,90 @@ func TestQueryService_GetPeriodStats(t *testing.T) {
 	})
 }
 
+func TestQueryService_GetPeriodStats_MonthlyTimeWeighted(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// A long easy run, a short hard one, and a run with no streams, all this
+	// month. The runs are saved without cached stream stats, as if analyzed
+	// before the cache existed.
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	createTestActivity(t, db, 1, "Easy", monthStart.Add(7*time.Hour), 10000, 600, floatPtr(140))
+	createTestMetrics(t, db, 1, floatPtr(1.2), floatPtr(50))
+	createTestStreams(t, db, 1, 600, 3.0, 140)
+	createTestActivity(t, db, 2, "Hard", monthStart.Add(8*time.Hour), 5000, 200, floatPtr(170))
+	createTestMetrics(t, db, 2, floatPtr(1.6), floatPtr(40))
+	createTestStreams(t, db, 2, 200, 4.0, 170)
+	createTestActivity(t, db, 3, "Treadmill", monthStart.Add(9*time.Hour), 5000, 1500, nil)
+	createTestMetrics(t, db, 3, nil, nil)
+
+	stats, err := NewQueryService(db, testAthleteConfig()).GetPeriodStats("monthly", 2)
+	if err != nil {
+		t.Fatalf("GetPeriodStats failed: %v", err)
+	}
+	if stats[0].RunCount != 0 {
+		t.Errorf("last month RunCount = %d, want 0", stats[0].RunCount)
+	}
+	month := stats[1]
+	if month.RunCount != 3 || math.Abs(month.TotalMiles-20000/MetersPerMile) > 1e-9 {
+		t.Errorf("got %d runs and %.2f miles, want 3 and %.2f", month.RunCount, month.TotalMiles, 20000/MetersPerMile)
+	}
+	// Weighted by samples, not averaged per run: (600*140 + 200*170) / 800
+	if month.AvgHR != 147.5 {
+		t.Errorf("AvgHR = %v, want 147.5", month.AvgHR)
+	}
+	if math.Abs(month.AvgEF-1.4) > 1e-9 {
+		t.Errorf("AvgEF = %v, want 1.4", month.AvgEF)
+	}
+
+	missing, err := db.ListActivitiesMissingStreamStats()
+	if err != nil {
+		t.Fatalf("ListActivitiesMissingStreamStats failed: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("activities %v still missing stream stats after backfill", missing)
+	}
+}
+
+func TestStreamStatsRules_MatchAggregateStreamStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Stopped, out-of-range HR, missing cadence and distance: every rule
+	points := make([]store.StreamPoint, 300)
+	for i := range points {
+		vel, hr, cad, dist := 3.0, 120+i%60, 85, float64(i)*3
+		switch {
+		case i%17 == 0:
+			vel = 0.3
+		case i%23 == 0:
+			hr = 230
+		case i%31 == 0:
+			hr = 40
+		}
+		points[i] = store.StreamPoint{ActivityID: 1, TimeOffset: i + i/50, VelocitySmooth: &vel, Heartrate: &hr, Cadence: &cad, Distance: &dist}
+		if i%11 == 0 {
+			points[i].Cadence = nil
+		}
+		if i > 290 {
+			points[i].Distance = nil
+		}
+	}
+	createTestActivity(t, db, 1, "Run", time.Now(), 900, 300, floatPtr(150))
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatalf("SaveStreams failed: %v", err)
+	}
+
+	got, err := db.ComputeStreamStatsForActivities([]int64{1}, streamStatsRules)
+	if err != nil {
+		t.Fatalf("ComputeStreamStatsForActivities failed: %v", err)
+	}
+	if want := activityStreamStats(1, points); *got[1] != *want {
+		t.Errorf("store stats = %+v, want %+v as from the points", *got[1], *want)
+	}
+}
+
 func TestQueryService_GetDashboardData(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -523,7 +555,7 @@ func TestQueryService_GetWeeklyComparisons(t *testing.T) {
 
 	// Calculate Monday of this week and last week for deterministic dates
 	now := time.Now()
-	// Find Monday of current week (same logic as getMonday helper)
+	// Find Monday of current week (same logic as startOfWeek)
 	daysFromMonday := (int(now.Weekday()) + 6) % 7
 	monday := now.AddDate(0, 0, -daysFromMonday)
 	thisMonday := time.Date(monday.Year(), monday.Month(), monday.Day(), 12, 0, 0, 0, now.Location())
@@ -651,6 +683,223 @@ func TestQueryService_GetMonthlyComparisons(t *testing.T) {
 	})
 }
 
+func TestQueryService_GetCustomComparison(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// Three runs in the six weeks before an injury, two in the last six weeks
+	injury := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Before Injury", injury.AddDate(0, 0, -7*(i+1)).Add(8*time.Hour), 10000, 3000, floatPtr(150))
+		createTestMetrics(t, db, id, floatPtr(1.25), floatPtr(110))
+		createTestStreams(t, db, id, 100, 3.0, 150)
+	}
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		id := int64(i + 10)
+		createTestActivity(t, db, id, "Comeback", now.AddDate(0, 0, -i-1), 6000, 2000, floatPtr(145))
+		createTestMetrics(t, db, id, floatPtr(1.20), floatPtr(80))
+		createTestStreams(t, db, id, 100, 3.0, 145)
+	}
+
+	current, err := ParseComparisonRange("last 6w", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := ParseComparisonRange("6w before 2024-03-01", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comp, err := svc.GetCustomComparison(current, previous)
+	if err != nil {
+		t.Fatalf("GetCustomComparison failed: %v", err)
+	}
+	if want := "Last 6 weeks vs Jan 19-Feb 29"; comp.Label != want {
+		t.Errorf("expected label %q, got %q", want, comp.Label)
+	}
+	if comp.Current.RunCount != 2 || comp.Previous.RunCount != 3 {
+		t.Errorf("expected 2 vs 3 runs, got %d vs %d", comp.Current.RunCount, comp.Previous.RunCount)
+	}
+	if comp.DeltaRuns != -1 {
+		t.Errorf("expected DeltaRuns=-1, got %d", comp.DeltaRuns)
+	}
+}
+
+func TestQueryService_GetTrends(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+
+	// Two runs last month, one three months ago
+	for i, date := range []time.Time{
+		lastMonth.AddDate(0, 0, 9).Add(12 * time.Hour),
+		lastMonth.AddDate(0, 0, 14).Add(12 * time.Hour),
+		thisMonth.AddDate(0, -3, 11).Add(12 * time.Hour),
+	} {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Run", date, 8000, 2400, floatPtr(148))
+		createTestMetrics(t, db, id, floatPtr(1.2+0.1*float64(i)), floatPtr(100))
+		createTestStreams(t, db, id, 100, 3.0, 148)
+	}
+
+	// Only efforts of TrendMinEffortSecs or longer count toward VDOT
+	if err := db.SaveDurationEfforts(1, []store.DurationEffort{
+		{DurationSeconds: 300, DistanceMeters: 1600},
+		{DurationSeconds: 1200, DistanceMeters: 5000},
+	}); err != nil {
+		t.Fatalf("SaveDurationEfforts failed: %v", err)
+	}
+	if err := rebuildFitnessTrends(db, now); err != nil {
+		t.Fatalf("rebuildFitnessTrends failed: %v", err)
+	}
+	// A hot run scores for the pace it would have held in cool weather
+	hot := 30.0
+	if err := db.SetActivityTemperature(2, &hot, store.TemperatureSourceManual); err != nil {
+		t.Fatalf("SetActivityTemperature failed: %v", err)
+	}
+
+	trends, err := svc.GetTrends(6)
+	if err != nil {
+		t.Fatalf("GetTrends failed: %v", err)
+	}
+	if len(trends) != 6 {
+		t.Fatalf("expected 6 months, got %d", len(trends))
+	}
+	if !trends[5].Month.Equal(thisMonth) {
+		t.Errorf("expected the last month to be %v, got %v", thisMonth, trends[5].Month)
+	}
+
+	last := trends[4]
+	if last.RunCount != 2 {
+		t.Errorf("expected 2 runs last month, got %d", last.RunCount)
+	}
+	if want := metersToMiles(16000); math.Abs(last.Miles-want) > 0.01 {
+		t.Errorf("expected %.2f miles last month, got %.2f", want, last.Miles)
+	}
+	if math.Abs(last.EF-1.25) > 0.001 {
+		t.Errorf("expected EF 1.25 last month, got %.3f", last.EF)
+	}
+	if want := analysis.CalculateVDOT(5000, 1200); math.Abs(last.VDOT-want) > 0.001 {
+		t.Errorf("expected VDOT %.1f last month, got %.1f", want, last.VDOT)
+	}
+	if last.CTL <= 0 || trends[2].CTL <= 0 {
+		t.Errorf("expected CTL in months with runs, got %.1f and %.1f", last.CTL, trends[2].CTL)
+	}
+	if trends[0].CTL != 0 || trends[0].RunCount != 0 {
+		t.Errorf("expected an empty month before the first run, got %+v", trends[0])
+	}
+
+	cool := analysis.NormalizePerformance(8000, 2400, 0, nil, analysis.DefaultRiegelExponent)
+	heat := analysis.NormalizePerformance(8000, 2400, 0, &hot, analysis.DefaultRiegelExponent)
+	if want := (cool.Score + heat.Score) / 2; math.Abs(last.Score-want) > 0.001 || heat.Score <= cool.Score {
+		t.Errorf("expected performance score %.2f last month, above the cool run's %.1f, got %.2f", want, cool.Score, last.Score)
+	}
+	if trends[2].Score != cool.Score || trends[0].Score != 0 {
+		t.Errorf("expected score %.1f three months ago and none before, got %.1f and %.1f", cool.Score, trends[2].Score, trends[0].Score)
+	}
+
+	detail, err := svc.GetActivityDetailByID(2)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	if detail.Performance == nil || *detail.Performance != heat {
+		t.Errorf("expected the detail's performance %+v, got %+v", heat, detail.Performance)
+	}
+}
+
+func TestQueryService_SetRiegelExponentRefreshesDetail(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+	createTestActivity(t, db, 1, "Run", time.Now().AddDate(0, 0, -1), 8000, 2400, floatPtr(150))
+	createTestMetrics(t, db, 1, floatPtr(1.2), floatPtr(90))
+
+	detail, err := svc.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	if detail.Performance == nil {
+		t.Fatal("expected a performance score")
+	}
+	before := detail.Performance.Score
+
+	// The cached detail is rebuilt with the new exponent
+	svc.SetRiegelExponent(1.15)
+	detail, err = svc.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	want := analysis.NormalizePerformance(8000, 2400, 0, nil, 1.15)
+	if detail.Performance == nil || detail.Performance.Score != want.Score || want.Score == before {
+		t.Errorf("score after changing the exponent = %+v, want %.2f (was %.2f)", detail.Performance, want.Score, before)
+	}
+}
+
+func TestQueryService_GetDurability(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// Decoupling of 2% at 30 minutes, rising 3 points for each doubling
+	now := time.Now()
+	for i, minutes := range []int{30, 45, 60, 90, 120} {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Run", now.AddDate(0, 0, -i-1), float64(minutes)*180, minutes*60, floatPtr(148))
+		decoupling := 2 + 3*math.Log2(float64(minutes)/30)
+		if err := db.SaveActivityMetrics(&store.ActivityMetrics{
+			ActivityID:        id,
+			EfficiencyFactor:  floatPtr(1.3 - 0.05*float64(i)),
+			AerobicDecoupling: &decoupling,
+		}); err != nil {
+			t.Fatalf("SaveActivityMetrics failed: %v", err)
+		}
+	}
+	// Too short to count, and too old for a 6-month window
+	createTestActivity(t, db, 10, "Strides", now.AddDate(0, 0, -1), 3000, 900, floatPtr(150))
+	createTestMetrics(t, db, 10, floatPtr(1.4), floatPtr(20))
+	createTestActivity(t, db, 11, "Old Long Run", now.AddDate(-1, 0, 0), 30000, 9000, floatPtr(150))
+	createTestMetrics(t, db, 11, floatPtr(1.1), floatPtr(200))
+
+	data, err := svc.GetDurability(6)
+	if err != nil {
+		t.Fatalf("GetDurability failed: %v", err)
+	}
+	if len(data.Runs) != 5 {
+		t.Fatalf("expected 5 runs, got %d", len(data.Runs))
+	}
+	if data.Runs[0].ActivityID != 5 {
+		t.Errorf("expected the oldest run first, got %d", data.Runs[0].ActivityID)
+	}
+	if data.LongestMinutes != 120 {
+		t.Errorf("expected the longest run to be 120 minutes, got %.0f", data.LongestMinutes)
+	}
+
+	fit := data.ByDuration
+	if fit.Decoupling == nil || fit.EF == nil {
+		t.Fatal("expected decoupling and EF fits")
+	}
+	if math.Abs(fit.Breakdown-60) > 0.01 {
+		t.Errorf("expected decoupling to reach the limit at 60 minutes, got %.2f", fit.Breakdown)
+	}
+	if fit.EF.Slope >= 0 {
+		t.Errorf("expected EF to fall with duration, slope %.3f", fit.EF.Slope)
+	}
+	if math.Abs(data.ByDistance.Breakdown-60*180) > 1 {
+		t.Errorf("expected decoupling to reach the limit at %d m, got %.0f", 60*180, data.ByDistance.Breakdown)
+	}
+}
+
 func TestQueryService_GetPeriodStatsForRange(t *testing.T) {
 	db := openTestDB(t)
 	defer db.Close()
@@ -707,3 +956,1956 @@ func TestQueryService_GetPeriodStatsForRange(t *testing.T) {
 		}
 	})
 }
+
+func TestQueryService_AddManualActivity(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	t.Run("stores activity with HR-based load", func(t *testing.T) {
+		start := time.Date(2024, 3, 10, 18, 30, 0, 0, time.Local)
+		id, err := svc.AddManualActivity(ManualActivity{
+			Name:      "Treadmill",
+			StartTime: start,
+			Distance:  8000,
+			Duration:  2700,
+			AvgHR:     floatPtr(145),
+		})
+		if err != nil {
+			t.Fatalf("AddManualActivity failed: %v", err)
+		}
+		if id >= 0 {
+			t.Errorf("expected negative manual activity ID, got %d", id)
+		}
+
+		list, err := svc.GetActivitiesList(10, 0)
+		if err != nil {
+			t.Fatalf("GetActivitiesList failed: %v", err)
+		}
+		if len(list) != 1 {
+			t.Fatalf("expected 1 activity, got %d", len(list))
+		}
+
+		got := list[0]
+		if !got.Activity.Manual {
+			t.Error("expected activity to be flagged manual")
+		}
+		if got.Activity.StartDateLocal.Hour() != 18 || got.Activity.StartDateLocal.Minute() != 30 {
+			t.Errorf("expected local start 18:30, got %s", got.Activity.StartDateLocal.Format("15:04"))
+		}
+		if got.Metrics.TRIMP == nil || *got.Metrics.TRIMP <= 0 {
+			t.Errorf("expected positive TRIMP, got %v", got.Metrics.TRIMP)
+		}
+		if got.Metrics.EfficiencyFactor != nil {
+			t.Errorf("expected no EF without streams, got %v", *got.Metrics.EfficiencyFactor)
+		}
+	})
+
+	t.Run("defaults name and allows missing HR", func(t *testing.T) {
+		id, err := svc.AddManualActivity(ManualActivity{
+			StartTime: time.Now(),
+			Distance:  5000,
+			Duration:  1800,
+		})
+		if err != nil {
+			t.Fatalf("AddManualActivity failed: %v", err)
+		}
+
+		activity, err := db.GetActivity(id)
+		if err != nil {
+			t.Fatalf("GetActivity failed: %v", err)
+		}
+		if activity.Name != DefaultManualActivityName {
+			t.Errorf("expected name %q, got %q", DefaultManualActivityName, activity.Name)
+		}
+		if activity.HasHeartrate {
+			t.Error("expected HasHeartrate=false")
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			input ManualActivity
+		}{
+			{"missing start", ManualActivity{Distance: 5000, Duration: 1800}},
+			{"zero distance", ManualActivity{StartTime: time.Now(), Duration: 1800}},
+			{"zero duration", ManualActivity{StartTime: time.Now(), Distance: 5000}},
+			{"HR out of range", ManualActivity{StartTime: time.Now(), Distance: 5000, Duration: 1800, AvgHR: floatPtr(300)}},
+		}
+		for _, tt := range tests {
+			if _, err := svc.AddManualActivity(tt.input); err == nil {
+				t.Errorf("%s: expected error", tt.name)
+			}
+		}
+	})
+}
+
+func TestQueryService_SetActivityExcluded(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	now := time.Now()
+	createTestActivity(t, db, 1, "Good Run", now.Add(-24*time.Hour), 8000, 2400, floatPtr(145))
+	createTestMetrics(t, db, 1, floatPtr(1.5), floatPtr(60))
+	createTestActivity(t, db, 2, "Bad Strap", now.Add(-48*time.Hour), 8000, 2400, floatPtr(190))
+	createTestMetrics(t, db, 2, floatPtr(0.5), floatPtr(300))
+
+	if _, err := db.UpsertPersonalRecord(&store.PersonalRecord{
+		Category:        "distance_5k",
+		ActivityID:      2,
+		DistanceMeters:  5000,
+		DurationSeconds: 1200,
+		AchievedAt:      now.Add(-48 * time.Hour),
+	}); err != nil {
+		t.Fatalf("UpsertPersonalRecord failed: %v", err)
+	}
+
+	if err := svc.SetActivityExcluded(2, true); err != nil {
+		t.Fatalf("SetActivityExcluded failed: %v", err)
+	}
+
+	t.Run("drops records held by the activity", func(t *testing.T) {
+		prs, err := db.GetPersonalRecordsForActivity(2)
+		if err != nil {
+			t.Fatalf("GetPersonalRecordsForActivity failed: %v", err)
+		}
+		if len(prs) != 0 {
+			t.Errorf("expected no PRs for excluded activity, got %d", len(prs))
+		}
+	})
+
+	t.Run("left out of aggregates", func(t *testing.T) {
+		list, err := svc.GetActivitiesList(10, 0)
+		if err != nil {
+			t.Fatalf("GetActivitiesList failed: %v", err)
+		}
+		if len(list) != 1 || list[0].Activity.ID != 1 {
+			t.Errorf("expected only activity 1, got %d activities", len(list))
+		}
+
+		stats, err := svc.getPeriodStatsForRange(now.Add(-7*24*time.Hour), now, "This Week")
+		if err != nil {
+			t.Fatalf("getPeriodStatsForRange failed: %v", err)
+		}
+		if stats.RunCount != 1 {
+			t.Errorf("expected 1 run in period stats, got %d", stats.RunCount)
+		}
+	})
+
+	t.Run("still listed in search", func(t *testing.T) {
+		list, err := svc.SearchActivities(store.ActivityFilter{}, store.ActivitySort{}, 10, 0)
+		if err != nil {
+			t.Fatalf("SearchActivities failed: %v", err)
+		}
+		if len(list) != 2 {
+			t.Errorf("expected 2 activities in search, got %d", len(list))
+		}
+	})
+
+	t.Run("restoring includes it again", func(t *testing.T) {
+		if err := svc.SetActivityExcluded(2, false); err != nil {
+			t.Fatalf("SetActivityExcluded(false) failed: %v", err)
+		}
+		list, err := svc.GetActivitiesList(10, 0)
+		if err != nil {
+			t.Fatalf("GetActivitiesList failed: %v", err)
+		}
+		if len(list) != 2 {
+			t.Errorf("expected 2 activities, got %d", len(list))
+		}
+	})
+}
+
+func TestQueryService_GetTrainingDistribution(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, config.AthleteConfig{RestingHR: 50, MaxHR: 200, ThresholdHR: 180})
+
+	// Easy run this week (120 bpm = 67% of LTHR, Z1) and a hard one last week
+	// (170 bpm = 94% of LTHR, Z3)
+	monday := startOfWeek(time.Now(), time.Monday)
+	createTestActivity(t, db, 1, "Easy", monday.Add(time.Hour), 5000, 300, floatPtr(120))
+	createTestMetrics(t, db, 1, nil, nil)
+	createTestStreams(t, db, 1, 300, 3.0, 120)
+	createTestActivity(t, db, 2, "Hard", monday.AddDate(0, 0, -6), 5000, 100, floatPtr(170))
+	createTestMetrics(t, db, 2, nil, nil)
+	createTestStreams(t, db, 2, 100, 4.0, 170)
+
+	data, err := svc.GetTrainingDistribution(4)
+	if err != nil {
+		t.Fatalf("GetTrainingDistribution failed: %v", err)
+	}
+
+	if len(data.Weeks) != 4 {
+		t.Fatalf("expected 4 weeks, got %d", len(data.Weeks))
+	}
+	if !data.Weeks[3].WeekStart.Equal(monday) {
+		t.Errorf("expected last week to start %v, got %v", monday, data.Weeks[3].WeekStart)
+	}
+
+	thisWeek, lastWeek := data.Weeks[3], data.Weeks[2]
+	if thisWeek.ZoneSeconds[0] != 300 || thisWeek.LowPct != 100 {
+		t.Errorf("this week: expected 300s in Z1 and 100%% easy, got %v and %.1f%%", thisWeek.ZoneSeconds, thisWeek.LowPct)
+	}
+	if lastWeek.ZoneSeconds[2] != 100 || lastWeek.HighPct != 100 {
+		t.Errorf("last week: expected 100s in Z3 and 100%% hard, got %v and %.1f%%", lastWeek.ZoneSeconds, lastWeek.HighPct)
+	}
+	if data.Overall.TotalSeconds != 400 || data.Overall.LowPct != 75 {
+		t.Errorf("overall: expected 400s at 75%% easy, got %ds at %.1f%%", data.Overall.TotalSeconds, data.Overall.LowPct)
+	}
+	if data.Weeks[0].TotalSeconds != 0 {
+		t.Errorf("expected empty first week, got %ds", data.Weeks[0].TotalSeconds)
+	}
+}
+
+func TestQueryService_GetTrainingDistribution_CachedZones(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, config.AthleteConfig{RestingHR: 50, MaxHR: 200, ThresholdHR: 180})
+
+	// Cached zone seconds are used as-is, without any streams stored
+	monday := startOfWeek(time.Now(), time.Monday)
+	createTestActivity(t, db, 1, "Cached", monday.Add(time.Hour), 5000, 1000, floatPtr(140))
+	z1, z2, z3, z4, z5 := 200, 600, 100, 100, 0
+	if err := db.SaveActivityMetrics(&store.ActivityMetrics{
+		ActivityID: 1,
+		Z1Seconds:  &z1,
+		Z2Seconds:  &z2,
+		Z3Seconds:  &z3,
+		Z4Seconds:  &z4,
+		Z5Seconds:  &z5,
+	}); err != nil {
+		t.Fatalf("failed to save metrics: %v", err)
+	}
+
+	data, err := svc.GetTrainingDistribution(2)
+	if err != nil {
+		t.Fatalf("GetTrainingDistribution failed: %v", err)
+	}
+
+	want := [5]int{200, 600, 100, 100, 0}
+	if data.Weeks[1].ZoneSeconds != want {
+		t.Errorf("expected cached zone seconds %v, got %v", want, data.Weeks[1].ZoneSeconds)
+	}
+	if data.Overall.TotalSeconds != 1000 || data.Overall.LowPct != 80 {
+		t.Errorf("overall: expected 1000s at 80%% easy, got %ds at %.1f%%", data.Overall.TotalSeconds, data.Overall.LowPct)
+	}
+}
+
+func TestQueryService_WeeklySummaries(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	monday := startOfWeek(time.Now(), time.Monday)
+	createTestActivity(t, db, 1, "Easy", monday.Add(time.Hour), 8046.72, 600, floatPtr(140))
+	createTestMetrics(t, db, 1, nil, floatPtr(50))
+	createTestStreams(t, db, 1, 600, 3.0, 140)
+	createTestActivity(t, db, 2, "Tempo", monday.Add(2*time.Hour), 1609.344, 200, floatPtr(170))
+	createTestMetrics(t, db, 2, nil, floatPtr(40))
+	createTestStreams(t, db, 2, 200, 4.0, 170)
+
+	// First read backfills the summaries
+	stats, err := svc.GetPeriodStats("weekly", 2)
+	if err != nil {
+		t.Fatalf("GetPeriodStats failed: %v", err)
+	}
+	thisWeek := stats[1]
+	if thisWeek.RunCount != 2 || math.Abs(thisWeek.TotalMiles-6) > 0.001 {
+		t.Errorf("expected 2 runs and 6 miles, got %d and %.2f", thisWeek.RunCount, thisWeek.TotalMiles)
+	}
+	// Time-weighted: 600s at 140 and 200s at 170
+	if thisWeek.AvgHR != 147.5 {
+		t.Errorf("expected time-weighted avg HR 147.5, got %.2f", thisWeek.AvgHR)
+	}
+	if count, _ := db.CountWeeklySummaries(); count != 1 {
+		t.Errorf("expected 1 stored summary, got %d", count)
+	}
+	if missing, _ := db.ListActivitiesMissingStreamStats(); len(missing) != 0 {
+		t.Errorf("activities %v missing stream stats after backfill", missing)
+	}
+
+	// Excluding a run updates its week
+	if err := svc.SetActivityExcluded(2, true); err != nil {
+		t.Fatalf("SetActivityExcluded failed: %v", err)
+	}
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	last := len(data.WeeklyMileage) - 1
+	if math.Abs(data.WeeklyMileage[last]-5) > 0.001 || data.WeeklyAvgHR[last] != 140 {
+		t.Errorf("expected 5 miles at 140 bpm after exclusion, got %.2f at %.1f", data.WeeklyMileage[last], data.WeeklyAvgHR[last])
+	}
+}
+
+func TestQueryService_GetLastWeekSummary(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	monday := startOfWeek(time.Now(), time.Monday)
+	createTestActivity(t, db, 1, "Last week", monday.AddDate(0, 0, -3), 8046.72, 600, floatPtr(140))
+	createTestMetrics(t, db, 1, nil, floatPtr(50))
+	createTestStreams(t, db, 1, 600, 3.0, 140)
+	createTestActivity(t, db, 2, "This week", monday.Add(time.Hour), 1609.344, 200, floatPtr(170))
+	createTestMetrics(t, db, 2, nil, floatPtr(40))
+	createTestStreams(t, db, 2, 200, 4.0, 170)
+
+	week, err := svc.GetLastWeekSummary()
+	if err != nil {
+		t.Fatalf("GetLastWeekSummary failed: %v", err)
+	}
+	if !week.WeekStart.Equal(monday.AddDate(0, 0, -7)) || week.RunCount != 1 || week.Distance != 8046.72 {
+		t.Errorf("expected last week's single run, got %+v", week)
+	}
+}
+
+func TestQueryService_WeeklySummaries_CachedStreamStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// No streams are stored, so the summary can only come from the cache
+	monday := startOfWeek(time.Now(), time.Monday)
+	createTestActivity(t, db, 1, "Easy", monday.Add(time.Hour), 8046.72, 2400, floatPtr(150))
+	createTestMetrics(t, db, 1, nil, floatPtr(50))
+	if err := db.SaveActivityStreamStats(&store.ActivityStreamStats{
+		ActivityID: 1, MovingTime: 2400, MovingDistance: 8046.72,
+		HRSum: 160 * 2400, HRCount: 2400, CadenceSum: 170 * 2400, CadenceCount: 2400, MaxHR: 175,
+	}); err != nil {
+		t.Fatalf("SaveActivityStreamStats failed: %v", err)
+	}
+
+	stats, err := svc.GetPeriodStats("weekly", 1)
+	if err != nil {
+		t.Fatalf("GetPeriodStats failed: %v", err)
+	}
+	if stats[0].AvgHR != 160 || stats[0].AvgSPM != 170 || stats[0].TotalMovingTime != 2400 {
+		t.Errorf("expected cached 160 bpm, 170 spm over 2400s, got %+v", stats[0])
+	}
+}
+
+func TestQueryService_HillStats(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	monday := startOfWeek(time.Now(), time.Monday)
+	createTestActivity(t, db, 1, "Hill repeats", monday.Add(time.Hour), 1800, 600, floatPtr(150))
+	createTestMetrics(t, db, 1, nil, floatPtr(50))
+	a, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.TotalElevationGain = 120
+	if err := db.UpsertActivity(a); err != nil {
+		t.Fatal(err)
+	}
+
+	// Five minutes up an 8% grade at 2.5 m/s, then five down at 3.5 m/s
+	points := make([]store.StreamPoint, 600)
+	dist, speed, grade := 0.0, 2.5, 8.0
+	for i := range points {
+		if i == 300 {
+			speed, grade = 3.5, -8
+		}
+		d, v, g := dist, speed, grade
+		points[i] = store.StreamPoint{ActivityID: 1, TimeOffset: i, Distance: &d, VelocitySmooth: &v, GradeSmooth: &g}
+		dist += speed
+	}
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := svc.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	h := detail.Hills
+	if h == nil || h.AscentSeconds != 299 || h.DescentSeconds != 300 {
+		t.Fatalf("expected 299 s up and 300 s down, got %+v", h)
+	}
+	if math.Abs(h.UphillSpeed()-2.5) > 0.01 || math.Abs(h.DownhillSpeed()-3.5) > 0.01 {
+		t.Errorf("uphill/downhill speed = %v/%v, want 2.5/3.5", h.UphillSpeed(), h.DownhillSpeed())
+	}
+
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if got := data.WeeklyVertical[len(data.WeeklyVertical)-1]; got != 120 {
+		t.Errorf("expected 120 m climbed this week, got %v", got)
+	}
+}
+
+func TestQueryService_StreamPages(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	createTestActivity(t, db, 1, "Easy", time.Now(), 1800, 600, floatPtr(140))
+	createTestStreams(t, db, 1, 600, 3.0, 140)
+
+	page, err := svc.GetStreamPage(1, 100, 50)
+	if err != nil {
+		t.Fatalf("GetStreamPage failed: %v", err)
+	}
+	if page.Total != 600 || page.Offset != 100 || len(page.Points) != 50 || page.Points[0].TimeOffset != 100 {
+		t.Errorf("unexpected page: total %d, offset %d, %d points", page.Total, page.Offset, len(page.Points))
+	}
+
+	// Paging past the end shows the last full page
+	if page, _ := svc.GetStreamPage(1, 590, 50); page.Offset != 550 || len(page.Points) != 50 {
+		t.Errorf("expected the last 50 points, got offset %d with %d points", page.Offset, len(page.Points))
+	}
+
+	points, err := svc.GetStreamRange(1, 10, 19)
+	if err != nil {
+		t.Fatalf("GetStreamRange failed: %v", err)
+	}
+	if len(points) != 10 || points[0].TimeOffset != 10 || points[9].TimeOffset != 19 {
+		t.Errorf("expected seconds 10 to 19, got %d points", len(points))
+	}
+}
+
+func TestQueryService_GetYearInReview(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 8, 0, 0, 0, time.UTC) }
+
+	// 2023: one run. 2024: four runs, three of them in March with EF.
+	createTestActivity(t, db, 1, "Old", day(2023, 6, 1), 5000, 1500, nil)
+	createTestActivity(t, db, 2, "Mar A", day(2024, 3, 4), 10000, 3000, floatPtr(150))
+	createTestActivity(t, db, 3, "Mar B", day(2024, 3, 5), 8000, 2400, floatPtr(150))
+	createTestActivity(t, db, 4, "Mar C", day(2024, 3, 20), 6000, 1800, floatPtr(150))
+	createTestActivity(t, db, 5, "May", day(2024, 5, 1), 1000, 300, floatPtr(150))
+	for id, ef := range map[int64]float64{2: 1.5, 3: 1.6, 4: 1.7, 5: 2.5} {
+		createTestMetrics(t, db, id, floatPtr(ef), nil)
+	}
+	if _, err := db.UpsertPersonalRecord(&store.PersonalRecord{
+		Category: "distance_10k", ActivityID: 2, DistanceMeters: 10000, DurationSeconds: 3000, AchievedAt: day(2024, 3, 4),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	years, err := svc.GetYearInReview()
+	if err != nil {
+		t.Fatalf("GetYearInReview failed: %v", err)
+	}
+	if len(years) != 2 || years[0].Year != 2024 || years[1].Year != 2023 {
+		t.Fatalf("expected 2024 then 2023, got %+v", years)
+	}
+
+	y := years[0]
+	if y.RunCount != 4 || y.Distance != 25000 || y.MovingTime != 7500 || y.PRsSet != 1 {
+		t.Errorf("unexpected 2024 totals: %+v", y)
+	}
+	// May has a higher EF but only one run
+	if y.BestEFMonth != time.March || math.Abs(y.BestEF-1.6) > 0.001 {
+		t.Errorf("expected March with EF 1.6, got %s %.2f", y.BestEFMonth, y.BestEF)
+	}
+	if !y.BiggestWeekStart.Equal(startOfWeek(day(2024, 3, 4), time.Monday)) || y.BiggestWeekDistance != 18000 {
+		t.Errorf("expected the week of Mar 4 with 18 km, got %s %.0f", y.BiggestWeekStart, y.BiggestWeekDistance)
+	}
+	if !y.HasPrevious || y.DistanceDelta != 400 || y.RunsDelta != 300 {
+		t.Errorf("expected +400%% distance and +300%% runs, got %+v", y)
+	}
+	if years[1].HasPrevious {
+		t.Error("the first year should have nothing to compare against")
+	}
+}
+
+func TestQueryService_PRProgression(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	older := time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "First 5K", older, 5000, 1500, nil)
+	createTestActivity(t, db, 2, "Faster 5K", newer, 5000, 1440, nil)
+
+	// Newest first, as sync offers them
+	for _, pr := range []*store.PersonalRecord{
+		{Category: "distance_5k", ActivityID: 2, DistanceMeters: 5000, DurationSeconds: 1440, AchievedAt: newer},
+		{Category: "distance_5k", ActivityID: 1, DistanceMeters: 5000, DurationSeconds: 1500, AchievedAt: older},
+	} {
+		if _, err := db.UpsertPersonalRecord(pr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := svc.GetPersonalRecords()
+	if err != nil {
+		t.Fatalf("GetPersonalRecords failed: %v", err)
+	}
+	if len(data.RaceDistancePRs) != 1 {
+		t.Fatalf("expected 1 race PR, got %d", len(data.RaceDistancePRs))
+	}
+	pr := data.RaceDistancePRs[0]
+	if pr.ActivityID != 2 || !pr.HasPrevious || pr.Margin != 60 {
+		t.Errorf("expected activity 2 to improve by 60s, got %+v", pr)
+	}
+
+	progressions, err := svc.GetPRProgressions()
+	if err != nil {
+		t.Fatalf("GetPRProgressions failed: %v", err)
+	}
+	if len(progressions) != 1 || len(progressions[0].Points) != 2 {
+		t.Fatalf("expected one progression with 2 points, got %+v", progressions)
+	}
+	points := progressions[0].Points
+	if points[0].ActivityName != "First 5K" || points[1].DurationSeconds != 1440 || points[1].Margin != 60 {
+		t.Errorf("unexpected progression: %+v", points)
+	}
+}
+
+func TestQueryService_GetCriticalPace(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// An old 25 minute run at 4 m/s and a recent 15 minute run at 3.5 m/s
+	old := time.Now().AddDate(-1, 0, 0)
+	recent := time.Now().AddDate(0, 0, -7)
+	createTestActivity(t, db, 1, "Old Tempo", old, 6000, 1500, nil)
+	createTestActivity(t, db, 2, "Recent Run", recent, 3150, 900, nil)
+	createTestStreams(t, db, 1, 1501, 4.0, 150)
+	createTestStreams(t, db, 2, 901, 3.5, 150)
+
+	for _, id := range []int64{1, 2} {
+		streams, err := db.GetStreams(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var efforts []store.DurationEffort
+		for _, e := range analysis.FindDurationEfforts(streams) {
+			efforts = append(efforts, store.DurationEffort{
+				DurationSeconds: e.DurationSeconds,
+				DistanceMeters:  e.DistanceMeters,
+				StartOffset:     e.StartOffset,
+			})
+		}
+		if err := db.SaveDurationEfforts(id, efforts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := svc.GetCriticalPace()
+	if err != nil {
+		t.Fatalf("GetCriticalPace failed: %v", err)
+	}
+
+	// 1, 2, 5, 10 and 20 minutes all-time, all from the faster old run
+	if len(data.AllTime.Points) != 5 {
+		t.Fatalf("expected 5 all-time points, got %d", len(data.AllTime.Points))
+	}
+	for _, pt := range data.AllTime.Points {
+		if pt.ActivityID != 1 || pt.ActivityName != "Old Tempo" {
+			t.Errorf("expected %s best from Old Tempo, got %+v", pt.Duration, pt)
+		}
+	}
+	if !data.AllTime.HasFit || math.Abs(data.AllTime.CriticalSpeed-4) > 0.01 {
+		t.Errorf("expected a critical speed of 4 m/s, got %+v", data.AllTime)
+	}
+
+	// Only the recent run, which is too short for 20 minutes
+	if len(data.Recent.Points) != 4 {
+		t.Fatalf("expected 4 recent points, got %d", len(data.Recent.Points))
+	}
+	if data.Recent.Points[0].ActivityID != 2 {
+		t.Errorf("expected recent bests from Recent Run, got %+v", data.Recent.Points[0])
+	}
+	if !data.Recent.HasFit || math.Abs(data.Recent.CriticalPace-analysis.Distance1Mile/3.5) > 1 {
+		t.Errorf("expected a recent critical pace at 3.5 m/s, got %+v", data.Recent)
+	}
+}
+
+func TestQueryService_GetRacePredictions_Ensemble(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+	svc.SetRiegelExponent(1.06)
+
+	// A 20:00 5K PR from six months ago and a slower recent 10K
+	prDate := time.Now().AddDate(0, -6, 0)
+	createTestActivity(t, db, 1, "5K PR", prDate, 5000, 1200, nil)
+	createTestActivity(t, db, 2, "Recent 10K", time.Now().AddDate(0, 0, -10), 10000, 2580, nil)
+	// Too old to count as recent
+	createTestActivity(t, db, 3, "Old 10K", time.Now().AddDate(-1, 0, 0), 10000, 2300, nil)
+
+	if _, err := db.UpsertPersonalRecord(&store.PersonalRecord{
+		Category: "distance_5k", ActivityID: 1, DistanceMeters: 5000, DurationSeconds: 1200, AchievedAt: prDate,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpsertRacePrediction(&store.RacePrediction{
+		TargetDistance:   "10k",
+		TargetMeters:     10000,
+		PredictedSeconds: 2490,
+		VDOT:             50,
+		SourceCategory:   "distance_5k",
+		SourceActivityID: 1,
+		Confidence:       "high",
+		ComputedAt:       time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := svc.GetRacePredictions()
+	if err != nil {
+		t.Fatalf("GetRacePredictions failed: %v", err)
+	}
+	if !data.HasRecentRace || data.RecentRace != "10K in 43:00" {
+		t.Errorf("expected the recent 10K as the recent race, got %q", data.RecentRace)
+	}
+	if len(data.Predictions) != 1 {
+		t.Fatalf("expected 1 prediction, got %d", len(data.Predictions))
+	}
+
+	p := data.Predictions[0]
+	wantRiegel := analysis.RiegelPredict(5000, 1200, 10000, 1.06)
+	if p.RiegelSeconds != wantRiegel {
+		t.Errorf("RiegelSeconds = %d, want %d", p.RiegelSeconds, wantRiegel)
+	}
+	// The recent race is already a 10K, so Riegel returns its time
+	if p.RecentSeconds != 2580 {
+		t.Errorf("RecentSeconds = %d, want 2580", p.RecentSeconds)
+	}
+	if p.Ensemble.Models != 3 || p.Ensemble.LowSeconds != 2490 || p.Ensemble.HighSeconds != 2580 {
+		t.Errorf("unexpected ensemble %+v", p.Ensemble)
+	}
+	if p.Range != "41:30 - 43:00 (±0:45)" {
+		t.Errorf("Range = %q", p.Range)
+	}
+}
+
+func TestQueryService_GetDashboardData_Projections(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// One run a week for 8 weeks, EF rising 0.02 and the 20 minute effort
+	// growing 50 m each week
+	now := time.Now()
+	for i := 0; i < 8; i++ {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Weekly Run", now.AddDate(0, 0, -7*i), 8000, 2400, floatPtr(150))
+		createTestMetrics(t, db, id, floatPtr(1.40-float64(i)*0.02), floatPtr(100))
+		err := db.SaveDurationEfforts(id, []store.DurationEffort{
+			{DurationSeconds: 1200, DistanceMeters: 4600 - float64(i)*50},
+			{DurationSeconds: 60, DistanceMeters: 400}, // too short to count
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+
+	ef := data.EFProjection
+	if !ef.OK || ef.Weeks != 8 {
+		t.Fatalf("expected an EF fit over 8 weeks, got OK=%v weeks=%d", ef.OK, ef.Weeks)
+	}
+	if math.Abs(ef.SlopePerWeek-0.02) > 1e-9 {
+		t.Errorf("EF slope = %.4f, want 0.02", ef.SlopePerWeek)
+	}
+	if math.Abs(ef.Current-1.40) > 1e-9 {
+		t.Errorf("EF current = %.3f, want 1.40", ef.Current)
+	}
+	if len(ef.History) != TrendHistoryWeeks || len(ef.Projected) != TrendProjectionWeeks+1 {
+		t.Errorf("unexpected series lengths: history %d, projected %d", len(ef.History), len(ef.Projected))
+	}
+	if !math.IsNaN(ef.History[0]) {
+		t.Error("expected NaN for weeks without runs")
+	}
+	last := len(ef.Projected) - 1
+	if !(ef.Low[last] <= ef.Projected[last] && ef.Projected[last] <= ef.High[last]) {
+		t.Errorf("projection %.3f outside band %.3f-%.3f", ef.Projected[last], ef.Low[last], ef.High[last])
+	}
+
+	vdot := data.VDOTProjection
+	if !vdot.OK || vdot.SlopePerWeek <= 0 {
+		t.Errorf("expected a rising VDOT fit, got OK=%v slope=%.3f", vdot.OK, vdot.SlopePerWeek)
+	}
+
+	t.Run("too few weeks", func(t *testing.T) {
+		db := openTestDB(t)
+		defer db.Close()
+		svc := NewQueryService(db, testAthleteConfig())
+		createTestActivity(t, db, 1, "Run", now, 8000, 2400, floatPtr(150))
+		createTestMetrics(t, db, 1, floatPtr(1.3), floatPtr(100))
+
+		data, err := svc.GetDashboardData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.EFProjection.OK || data.VDOTProjection.OK {
+			t.Error("expected no projection from a single week")
+		}
+	})
+}
+
+func TestQueryService_GetMonthlyReport(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 7, 0, 0, 0, time.Local)
+	}
+	createTestActivity(t, db, 1, "February Run", day(time.February, 20), 10000, 3000, floatPtr(150))
+	createTestActivity(t, db, 2, "Easy", day(time.March, 3), 8000, 2600, floatPtr(140))
+	createTestActivity(t, db, 3, "Long Run", day(time.March, 16), 20000, 6600, floatPtr(145))
+	createTestActivity(t, db, 4, "April Run", day(time.April, 2), 5000, 1500, floatPtr(150))
+	createTestMetrics(t, db, 1, floatPtr(1.20), floatPtr(80))
+	createTestMetrics(t, db, 2, floatPtr(1.30), floatPtr(60))
+	createTestMetrics(t, db, 3, floatPtr(1.26), floatPtr(150))
+	createTestMetrics(t, db, 4, floatPtr(1.40), floatPtr(50))
+
+	r, err := svc.GetMonthlyReport(2025, time.March)
+	if err != nil {
+		t.Fatalf("GetMonthlyReport failed: %v", err)
+	}
+
+	if r.RunCount != 2 || r.Distance != 28000 || r.MovingTime != 9200 {
+		t.Errorf("totals = %d runs, %.0f m, %d s; want 2, 28000, 9200", r.RunCount, r.Distance, r.MovingTime)
+	}
+	if r.LongestRun != 20000 {
+		t.Errorf("LongestRun = %.0f, want 20000", r.LongestRun)
+	}
+	if !r.HasPrevious || math.Abs(r.DistanceDelta-180) > 1e-9 {
+		t.Errorf("DistanceDelta = %.1f (HasPrevious %v), want +180%%", r.DistanceDelta, r.HasPrevious)
+	}
+	if len(r.DailyDistance) != 31 || r.DailyDistance[2] != 8000 || r.DailyDistance[15] != 20000 {
+		t.Errorf("unexpected daily distance %v", r.DailyDistance)
+	}
+
+	// March 2025 starts on a Saturday, so its weeks begin Feb 24 through Mar 31
+	if len(r.WeekStarts) != 6 || !r.WeekStarts[0].Equal(time.Date(2025, time.February, 24, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("unexpected week starts %v", r.WeekStarts)
+	}
+	if r.WeeklyDistance[1] != 8000 || r.WeeklyDistance[2] != 20000 { // Mar 16 is a Sunday
+		t.Errorf("unexpected weekly distance %v", r.WeeklyDistance)
+	}
+
+	if r.TotalTRIMP != 210 {
+		t.Errorf("TotalTRIMP = %.0f, want 210", r.TotalTRIMP)
+	}
+	if r.CTL <= 0 || r.FormDescription == "" {
+		t.Errorf("expected month-end fitness, got CTL %.1f %q", r.CTL, r.FormDescription)
+	}
+
+	if len(r.EFValues) != 2 || r.EFValues[0] != 1.30 || math.Abs(r.AvgEF-1.28) > 1e-9 || r.PrevAvgEF != 1.20 {
+		t.Errorf("unexpected EF: values %v avg %.2f prev %.2f", r.EFValues, r.AvgEF, r.PrevAvgEF)
+	}
+
+	empty, err := svc.GetMonthlyReport(2024, time.June)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.RunCount != 0 || empty.HasPrevious {
+		t.Errorf("expected an empty report, got %+v", empty)
+	}
+}
+
+func TestQueryService_GetActivitiesForExport(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	base := time.Date(2025, time.March, 1, 7, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Old", base, 5000, 1500, nil)
+	createTestActivity(t, db, 2, "With Metrics", base.AddDate(0, 0, 10), 8000, 2400, floatPtr(150))
+	createTestActivity(t, db, 3, "No Metrics", base.AddDate(0, 0, 5), 6000, 1800, nil)
+	createTestActivity(t, db, 4, "Excluded", base.AddDate(0, 0, 7), 6000, 1800, nil)
+	createTestMetrics(t, db, 2, floatPtr(1.3), floatPtr(90))
+	if err := svc.SetActivityExcluded(4, true); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := svc.GetActivitiesForExport(time.Time{})
+	if err != nil {
+		t.Fatalf("GetActivitiesForExport failed: %v", err)
+	}
+	var ids []int64
+	for _, a := range all {
+		ids = append(ids, a.Activity.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 3 || ids[2] != 2 {
+		t.Errorf("expected runs 1, 3, 2 oldest first, got %v", ids)
+	}
+	if all[2].Metrics.TRIMP == nil || all[1].Metrics.TRIMP != nil {
+		t.Error("expected metrics only for the run that has them")
+	}
+
+	// Tags and notes ride along for the exports to write
+	if err := db.SetActivityTags(2, []string{"tempo", "hills"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetActivityNote(2, "Legs heavy"); err != nil {
+		t.Fatal(err)
+	}
+	all, err = svc.GetActivitiesForExport(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := all[2]; !slices.Equal(got.Tags, []string{"hills", "tempo"}) || got.Note != "Legs heavy" {
+		t.Errorf("run 2 exported with tags %v and note %q", got.Tags, got.Note)
+	}
+	if got := all[1]; got.Tags != nil || got.Note != "" {
+		t.Errorf("run 3 exported with tags %v and note %q, want none", got.Tags, got.Note)
+	}
+
+	recent, err := svc.GetActivitiesForExport(base.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 {
+		t.Errorf("expected 2 runs since Mar 2, got %d", len(recent))
+	}
+}
+
+func TestQueryService_GetDashboardData_VO2max(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Four easy runs at 10 km/h and 70% of heart rate reserve, plus a race
+	// and a short jog that don't qualify
+	now := time.Now()
+	easyHR := 50 + 0.7*(185-50)
+	for i := 0; i < 4; i++ {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Easy Run", now.AddDate(0, 0, -3-7*i), 5000, 1800, floatPtr(easyHR))
+		createTestMetrics(t, db, id, floatPtr(1.5), floatPtr(50))
+	}
+	createTestActivity(t, db, 5, "Race", now.AddDate(0, 0, -1), 5000, 1200, floatPtr(180))
+	createTestMetrics(t, db, 5, floatPtr(1.5), floatPtr(80))
+	createTestActivity(t, db, 6, "Jog", now.AddDate(0, 0, -2), 2000, 900, floatPtr(easyHR))
+	createTestMetrics(t, db, 6, floatPtr(1.5), floatPtr(20))
+
+	// Without a weight the estimate is off
+	data, err := NewQueryService(db, testAthleteConfig()).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.VO2max.WeightSet || data.VO2max.OK {
+		t.Errorf("expected no VO2max estimate without weight, got %+v", data.VO2max)
+	}
+
+	athlete := testAthleteConfig()
+	athlete.WeightKg = 70
+	data, err = NewQueryService(db, athlete).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	est := data.VO2max
+	if !est.OK || est.Runs != 4 {
+		t.Fatalf("expected an estimate from 4 runs, got OK=%v runs=%d", est.OK, est.Runs)
+	}
+	if math.Abs(est.Current-51.12) > 0.01 {
+		t.Errorf("VO2max = %.2f, want 51.12", est.Current)
+	}
+	if math.Abs(est.Absolute-51.12*70/1000) > 0.001 {
+		t.Errorf("absolute VO2max = %.3f L/min, want %.3f", est.Absolute, 51.12*70/1000)
+	}
+	if !est.Trend.OK || math.Abs(est.Trend.SlopePerWeek) > 1e-9 {
+		t.Errorf("expected a flat weekly trend, got OK=%v slope=%v", est.Trend.OK, est.Trend.SlopePerWeek)
+	}
+}
+
+func TestQueryService_SearchActivitiesAfterBefore(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Five runs a day apart, getting shorter
+	base := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	for i := int64(1); i <= 5; i++ {
+		start := base.AddDate(0, 0, int(i))
+		createTestActivity(t, db, i, "Run", start, float64(10000-i*1000), 2400, floatPtr(150))
+		createTestMetrics(t, db, i, floatPtr(1.5), floatPtr(50))
+	}
+	svc := NewQueryService(db, testAthleteConfig())
+
+	ids := func(list []ActivityWithMetrics) []int64 {
+		var out []int64
+		for _, am := range list {
+			out = append(out, am.Activity.ID)
+		}
+		return out
+	}
+
+	orders := map[string]store.ActivitySort{
+		"newest first":  {},
+		"oldest first":  {Field: store.SortByDate, Ascending: true},
+		"longest first": {Field: store.SortByDistance},
+	}
+	for name, order := range orders {
+		t.Run(name, func(t *testing.T) {
+			all, err := svc.SearchActivities(store.ActivityFilter{}, order, 10, 0)
+			if err != nil {
+				t.Fatalf("SearchActivities failed: %v", err)
+			}
+
+			// Page forward two at a time, then back from the end
+			var forward []ActivityWithMetrics
+			var last *store.Activity
+			for {
+				page, err := svc.SearchActivitiesAfter(store.ActivityFilter{}, order, last, len(forward)-1, 2)
+				if err != nil {
+					t.Fatalf("SearchActivitiesAfter failed: %v", err)
+				}
+				if len(page) == 0 {
+					break
+				}
+				forward = append(forward, page...)
+				last = &forward[len(forward)-1].Activity
+			}
+			if !slices.Equal(ids(forward), ids(all)) {
+				t.Errorf("paged forward %v, want %v", ids(forward), ids(all))
+			}
+
+			backward := forward[len(forward)-1:]
+			for {
+				page, err := svc.SearchActivitiesBefore(store.ActivityFilter{}, order, backward[0].Activity, len(all)-len(backward), 2)
+				if err != nil {
+					t.Fatalf("SearchActivitiesBefore failed: %v", err)
+				}
+				if len(page) == 0 {
+					break
+				}
+				backward = append(page, backward...)
+			}
+			if !slices.Equal(ids(backward), ids(all)) {
+				t.Errorf("paged backward %v, want %v", ids(backward), ids(all))
+			}
+		})
+	}
+}
+
+func TestQueryService_GetDashboardData_WeekStatsBeyondRecent(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// More runs this week than the recent activities list holds
+	monday := startOfWeek(time.Now(), time.Monday)
+	n := RecentActivitiesLimit + 2
+	for i := 1; i <= n; i++ {
+		createTestActivity(t, db, int64(i), "Run", monday.Add(time.Duration(i)*time.Hour), 5000, 1500, floatPtr(150))
+		createTestMetrics(t, db, int64(i), floatPtr(1.5), floatPtr(50))
+	}
+	// And one the Sunday before, which doesn't count
+	createTestActivity(t, db, int64(n+1), "Run", monday.Add(-time.Hour), 5000, 1500, floatPtr(150))
+	createTestMetrics(t, db, int64(n+1), floatPtr(1.5), floatPtr(50))
+
+	data, err := NewQueryService(db, testAthleteConfig()).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.WeekRunCount != n {
+		t.Errorf("WeekRunCount = %d, want %d", data.WeekRunCount, n)
+	}
+	if data.WeekTime != n*1500 {
+		t.Errorf("WeekTime = %d, want %d", data.WeekTime, n*1500)
+	}
+}
+
+func TestQueryService_GetDashboardData_WeeklyGoal(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	createTestActivity(t, db, 1, "Run", time.Now().AddDate(0, 0, -1), 5000, 1800, floatPtr(150))
+	createTestMetrics(t, db, 1, floatPtr(1.5), floatPtr(50))
+
+	data, err := NewQueryService(db, testAthleteConfig()).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.WeeklyGoal != 0 {
+		t.Errorf("WeeklyGoal = %v without a goal, want 0", data.WeeklyGoal)
+	}
+
+	athlete := testAthleteConfig()
+	athlete.WeeklyGoalKm = 50
+	data, err = NewQueryService(db, athlete).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if want := 50000 / MetersPerMile; math.Abs(data.WeeklyGoal-want) > 1e-9 {
+		t.Errorf("WeeklyGoal = %v mi, want %v", data.WeeklyGoal, want)
+	}
+}
+
+func TestQueryService_GetDashboardData_GoalConsistency(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// 10 km in each of the last three completed weeks, 5 km before that
+	monday := startOfWeek(time.Now(), time.Monday)
+	for i, km := range []float64{5, 10, 10, 10} {
+		start := monday.AddDate(0, 0, -7*(4-i)+2).Add(12 * time.Hour)
+		createTestActivity(t, db, int64(i+1), "Run", start, km*1000, int(km)*330, floatPtr(150))
+		createTestMetrics(t, db, int64(i+1), floatPtr(1.5), floatPtr(50))
+	}
+
+	data, err := NewQueryService(db, testAthleteConfig()).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.GoalConsistency != (analysis.GoalConsistency{}) {
+		t.Errorf("GoalConsistency = %+v without a goal, want zero", data.GoalConsistency)
+	}
+
+	athlete := testAthleteConfig()
+	athlete.WeeklyGoalKm = 10
+	data, err = NewQueryService(db, athlete).GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	want := analysis.GoalConsistency{Percent: 25, Weeks: GoalConsistencyWeeks, Streak: 3}
+	if data.GoalConsistency != want {
+		t.Errorf("GoalConsistency = %+v, want %+v", data.GoalConsistency, want)
+	}
+}
+
+func TestQueryService_GetDashboardData_HeatAdjustedEF(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// The same fitness on a cool day and a hot one: the hot run's EF is 6.5%
+	// lower, which the 30°C adjustment should cancel out
+	now := time.Now()
+	createTestActivity(t, db, 1, "Cool Run", now.AddDate(0, 0, -2), 8000, 2400, floatPtr(145))
+	createTestMetrics(t, db, 1, floatPtr(1.50), floatPtr(50))
+	createTestActivity(t, db, 2, "Hot Run", now.AddDate(0, 0, -1), 8000, 2400, floatPtr(145))
+	createTestMetrics(t, db, 2, floatPtr(1.50/1.065), floatPtr(50))
+
+	svc := NewQueryService(db, testAthleteConfig())
+	temp, err := ParseTemperature("30", false)
+	if err != nil {
+		t.Fatalf("ParseTemperature failed: %v", err)
+	}
+	if err := svc.SetActivityTemperature(2, temp); err != nil {
+		t.Fatalf("SetActivityTemperature failed: %v", err)
+	}
+
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.EFHeatAdjusted != 1 {
+		t.Errorf("EFHeatAdjusted = %d, want 1", data.EFHeatAdjusted)
+	}
+	if len(data.EFHistory) != 2 || math.Abs(data.EFHistory[1]-1.50) > 1e-9 {
+		t.Errorf("EFHistory = %v, want the hot run adjusted to 1.50", data.EFHistory)
+	}
+
+	detail, err := svc.GetActivityDetailByID(2)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	if detail.TemperatureC == nil || *detail.TemperatureC != 30 {
+		t.Errorf("TemperatureC = %v, want 30", detail.TemperatureC)
+	}
+	if math.Abs(detail.AdjustedEF-1.50) > 1e-9 {
+		t.Errorf("AdjustedEF = %v, want 1.50", detail.AdjustedEF)
+	}
+}
+
+func TestQueryService_GetDashboardData_Monotony(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// The same run every day for a week, one a little shorter
+	now := time.Now()
+	for i := 0; i < 7; i++ {
+		id := int64(i + 1)
+		trimp := 60.0
+		if i == 3 {
+			trimp = 50
+		}
+		createTestActivity(t, db, id, "Daily Run", now.AddDate(0, 0, -i), 8000, 2400, floatPtr(145))
+		createTestMetrics(t, db, id, floatPtr(1.5), floatPtr(trimp))
+	}
+
+	svc := NewQueryService(db, testAthleteConfig())
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.Monotony != 0 || data.HighMonotony {
+		t.Errorf("expected no monotony before the trends are computed, got %.2f", data.Monotony)
+	}
+
+	if err := rebuildFitnessTrends(db, now); err != nil {
+		t.Fatalf("rebuildFitnessTrends failed: %v", err)
+	}
+	data, err = svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if math.Abs(data.Monotony-15.50) > 0.01 || !data.HighMonotony {
+		t.Errorf("monotony = %.2f (high %v), want 15.50 and flagged", data.Monotony, data.HighMonotony)
+	}
+	if math.Abs(data.Strain-410*data.Monotony) > 0.01 {
+		t.Errorf("strain = %.1f, want %.1f", data.Strain, 410*data.Monotony)
+	}
+
+	trend, err := db.GetLatestFitnessTrend()
+	if err != nil || trend == nil {
+		t.Fatalf("GetLatestFitnessTrend = %v, %v", trend, err)
+	}
+	if trend.Date != now.UTC().Format("2006-01-02") || trend.RunCount7d != 7 || trend.TotalDistance7d != 56000 {
+		t.Errorf("latest trend = %s with %d runs, %.0f m; want today with 7 runs, 56000 m",
+			trend.Date, trend.RunCount7d, trend.TotalDistance7d)
+	}
+}
+
+func TestQueryService_GetDashboardData_Streaks(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Twelve days running to yesterday after a rest day, and an earlier
+	// three-day streak
+	today := calendarDay(time.Now()).Add(12 * time.Hour)
+	id := int64(1)
+	for _, back := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 20, 21, 22} {
+		createTestActivity(t, db, id, "Run", today.AddDate(0, 0, -back), 5000, 1800, floatPtr(145))
+		id++
+	}
+
+	svc := NewQueryService(db, testAthleteConfig())
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	want := analysis.Streaks{Current: 12, Longest: 12, DaysSinceRest: 13}
+	if data.Streaks != want {
+		t.Errorf("Streaks = %+v, want %+v", data.Streaks, want)
+	}
+	if !data.NeedsRest {
+		t.Error("expected a rest day warning after 13 days")
+	}
+
+	svc.SetRestDayWarningDays(14)
+	if data, _ = svc.GetDashboardData(); data.NeedsRest {
+		t.Error("expected no warning within a 14-day window")
+	}
+	svc.SetRestDayWarningDays(-1)
+	if data, _ = svc.GetDashboardData(); data.NeedsRest {
+		t.Error("expected no warning when disabled")
+	}
+}
+
+func TestQueryService_GetInjuryLog(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Two months of a km a day, then a week at double the distance and load
+	// leading into a shin injury
+	today := calendarDay(time.Now())
+	id := int64(1)
+	for back := 60; back >= 6; back-- {
+		distance, trimp := 1000.0, 50.0
+		if back <= 12 {
+			distance, trimp = 2000, 100
+		}
+		createTestActivity(t, db, id, "Run", today.AddDate(0, 0, -back).Add(7*time.Hour), distance, 360, floatPtr(140))
+		createTestMetrics(t, db, id, nil, floatPtr(trimp))
+		id++
+	}
+
+	svc := NewQueryService(db, testAthleteConfig())
+	if _, err := svc.AddInjury(store.Injury{StartedOn: today.AddDate(0, 0, -5), BodyPart: " Left shin ", Severity: 3}); err != nil {
+		t.Fatalf("AddInjury failed: %v", err)
+	}
+	// An old injury from before any training has nothing to compare against
+	oldID, err := svc.AddInjury(store.Injury{StartedOn: today.AddDate(0, 0, -100), BodyPart: "Calf", Severity: 1})
+	if err != nil {
+		t.Fatalf("AddInjury failed: %v", err)
+	}
+	resolved := today.AddDate(0, 0, -90)
+	if err := svc.ResolveInjury(oldID, &resolved); err != nil {
+		t.Fatalf("ResolveInjury failed: %v", err)
+	}
+	if _, err := svc.AddInjury(store.Injury{StartedOn: today, Severity: 2}); err == nil {
+		t.Error("expected an error without a body part")
+	}
+
+	injuryLog, err := svc.GetInjuryLog()
+	if err != nil {
+		t.Fatalf("GetInjuryLog failed: %v", err)
+	}
+	if len(injuryLog.Injuries) != 2 {
+		t.Fatalf("expected 2 injuries, got %d", len(injuryLog.Injuries))
+	}
+
+	shin := injuryLog.Injuries[0]
+	if shin.BodyPart != "Left shin" || !shin.Active() || shin.Days != 5 {
+		t.Errorf("unexpected shin injury %+v", shin)
+	}
+	if shin.RampPct == nil || math.Abs(*shin.RampPct-100) > 1e-6 {
+		t.Errorf("shin RampPct = %v, want 100", shin.RampPct)
+	}
+	if shin.ACWR == nil || *shin.ACWR <= 1.2 {
+		t.Errorf("shin ACWR = %v, want a spike above 1.2", shin.ACWR)
+	}
+
+	calf := injuryLog.Injuries[1]
+	if calf.Active() || calf.Days != 10 || calf.RampPct != nil || calf.ACWR != nil {
+		t.Errorf("unexpected calf injury %+v", calf)
+	}
+
+	a := injuryLog.Analysis
+	if a.RampInjuries != 1 || a.ACWRInjuries != 1 {
+		t.Errorf("expected one injury with load history, got %+v", a)
+	}
+	if a.AvgRampBefore <= a.TypicalRamp || a.AvgACWRBefore <= a.TypicalACWR {
+		t.Errorf("expected load before injury above typical, got %+v", a)
+	}
+
+	if len(injuryLog.WeeklyMiles) != InjuryChartWeeks || len(injuryLog.InjuredWeeks) != InjuryChartWeeks {
+		t.Fatalf("expected %d weeks, got %d", InjuryChartWeeks, len(injuryLog.WeeklyMiles))
+	}
+	var miles float64
+	for _, m := range injuryLog.WeeklyMiles {
+		miles += m
+	}
+	if want := metersToMiles(7*2000 + 48*1000); math.Abs(miles-want) > 1e-6 {
+		t.Errorf("weekly miles total = %.2f, want %.2f", miles, want)
+	}
+	if !injuryLog.InjuredWeeks[InjuryChartWeeks-1] {
+		t.Error("expected the current week to be marked injured")
+	}
+	if injuryLog.WeeklyCTL[InjuryChartWeeks-1] <= 0 {
+		t.Error("expected fitness in the current week")
+	}
+}
+
+func TestQueryService_GetDashboardData_Readiness(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+	today := calendarDay(time.Now())
+	f := func(v float64) *float64 { return &v }
+
+	data, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	if data.Readiness.OK || data.Readiness.Today != nil {
+		t.Errorf("expected no readiness before logging, got %+v", data.Readiness)
+	}
+
+	// A week of typical mornings, then a poor one today
+	var entries []store.Wellness
+	for back := 1; back <= 7; back++ {
+		entries = append(entries, store.Wellness{Date: today.AddDate(0, 0, -back), RestingHR: f(48), HRV: f(60)})
+	}
+	soreness := 3
+	entries = append(entries, store.Wellness{Date: today, RestingHR: f(53), HRV: f(48), SleepHours: f(6), Soreness: &soreness})
+	if err := svc.SaveWellness(entries...); err != nil {
+		t.Fatalf("SaveWellness failed: %v", err)
+	}
+
+	data, err = svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	r := data.Readiness
+	if !r.OK || r.Today == nil || r.BaselineRestingHR == nil || *r.BaselineRestingHR != 48 {
+		t.Fatalf("unexpected readiness %+v", r)
+	}
+	// No runs, so no form: resting HR 35, HRV 45, sleep 70, soreness 50
+	if want := (35 + 45 + 70 + 50) / 4.0; math.Abs(r.Score-want) > 1e-9 || r.Form != nil {
+		t.Errorf("Score = %.2f, want %.2f without form", r.Score, want)
+	}
+
+	if w, err := svc.GetWellness(time.Now()); err != nil || w == nil || *w.Soreness != 3 {
+		t.Errorf("GetWellness() = %+v, %v", w, err)
+	}
+}
+
+func TestQueryService_Benchmarks(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// An 8 km loop run three times, a longer run, and a run without metrics.
+	// None have GPS, so the loop is matched on distance alone.
+	now := time.Now()
+	createTestActivity(t, db, 1, "Loop", now.AddDate(0, 0, -60), 8000, 2400, floatPtr(150))
+	createTestMetrics(t, db, 1, floatPtr(1.2), floatPtr(60))
+	createTestActivity(t, db, 2, "Loop again", now.AddDate(0, 0, -30), 8100, 2300, floatPtr(150))
+	createTestMetrics(t, db, 2, floatPtr(1.32), floatPtr(60))
+	createTestActivity(t, db, 3, "Loop, windy", now.AddDate(0, 0, -10), 7950, 2500, floatPtr(155))
+	createTestMetrics(t, db, 3, floatPtr(1.1), floatPtr(60))
+	createTestActivity(t, db, 4, "Long run", now.AddDate(0, 0, -5), 16000, 5000, floatPtr(145))
+	createTestMetrics(t, db, 4, floatPtr(1.25), floatPtr(100))
+
+	id, err := svc.AddBenchmark(" Tempo loop ", store.BenchmarkRoute, 1)
+	if err != nil {
+		t.Fatalf("AddBenchmark failed: %v", err)
+	}
+	if _, err := svc.AddBenchmark("tempo LOOP", store.BenchmarkRoute, 2); err == nil {
+		t.Error("expected a duplicate name to fail")
+	}
+	if _, err := svc.AddBenchmark("Missing", store.BenchmarkRoute, 99); err == nil {
+		t.Error("expected an unknown reference run to fail")
+	}
+	if found, err := svc.BenchmarkByName("TEMPO loop"); err != nil || found == nil || found.ID != id {
+		t.Errorf("BenchmarkByName() = %+v, %v, want the tempo loop", found, err)
+	}
+	if found, _ := svc.BenchmarkByName("Hill repeats"); found != nil {
+		t.Errorf("BenchmarkByName() = %+v, want nil", found)
+	}
+
+	attemptIDs := func() []int64 {
+		t.Helper()
+		benchmarks, err := svc.GetBenchmarks()
+		if err != nil {
+			t.Fatalf("GetBenchmarks failed: %v", err)
+		}
+		if len(benchmarks) != 1 || benchmarks[0].ID != id {
+			t.Fatalf("expected the one benchmark, got %+v", benchmarks)
+		}
+		var ids []int64
+		for _, a := range benchmarks[0].Attempts {
+			ids = append(ids, a.Activity.ID)
+		}
+		return ids
+	}
+
+	benchmarks, err := svc.GetBenchmarks()
+	if err != nil {
+		t.Fatalf("GetBenchmarks failed: %v", err)
+	}
+	b := benchmarks[0]
+	if b.Name != "Tempo loop" || b.ReferenceName != "Loop" || b.ReferenceHR != 150 {
+		t.Errorf("unexpected benchmark %+v", b.Benchmark)
+	}
+	if len(b.Attempts) != 3 || !b.Attempts[0].Reference || b.Attempts[2].Activity.ID != 3 {
+		t.Fatalf("expected the three loops oldest first, got %v", attemptIDs())
+	}
+	// 1.32 m/min/bpm at 150 bpm is 198 m/min
+	if want := MetersPerMile / 198 * 60; math.Abs(b.Attempts[1].PaceAtHR-want) > 1e-9 {
+		t.Errorf("PaceAtHR = %.1f s/mi, want %.1f", b.Attempts[1].PaceAtHR, want)
+	}
+	if b.EFChange == nil || math.Abs(*b.EFChange-(1.1-1.2)/1.2*100) > 1e-9 {
+		t.Errorf("EFChange = %v, want first to last change", b.EFChange)
+	}
+
+	// Overrides add and remove runs
+	if err := svc.SetBenchmarkAttempt(id, 3, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetBenchmarkAttempt(id, 4, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := attemptIDs(); len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 4 {
+		t.Errorf("expected attempts [1 2 4] after overrides, got %v", got)
+	}
+
+	if err := svc.DeleteBenchmark(id); err != nil {
+		t.Fatal(err)
+	}
+	if benchmarks, _ := svc.GetBenchmarks(); len(benchmarks) != 0 {
+		t.Errorf("expected no benchmarks after delete, got %d", len(benchmarks))
+	}
+}
+
+func TestQueryService_StoppedTime(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	createTestActivity(t, db, 1, "Lights", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), 3000, 1000, floatPtr(150))
+
+	// Ten minutes running, a minute at a crossing, then the watch paused
+	// for five minutes before five more minutes of running
+	var points []store.StreamPoint
+	t0, dist := 0, 0.0
+	run := func(seconds int, speed float64) {
+		for i := 0; i < seconds; i++ {
+			d, v, hr := dist, speed, 150
+			points = append(points, store.StreamPoint{ActivityID: 1, TimeOffset: t0, Distance: &d, VelocitySmooth: &v, Heartrate: &hr})
+			dist += speed
+			t0++
+		}
+	}
+	run(600, 3)
+	run(60, 0)
+	run(300, 3)
+	t0 += 300
+	run(300, 3)
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := svc.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	if len(detail.Stops) != 2 || detail.StoppedTime != 60+301 {
+		t.Errorf("stops = %v (%d s), want the crossing and the pause (361 s)", detail.Stops, detail.StoppedTime)
+	}
+}
+
+func TestQueryService_GetTrainingWarnings(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+	now := calendarDay(time.Now()).Add(18 * time.Hour)
+
+	warnings, err := svc.GetTrainingWarnings(now)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("GetTrainingWarnings() with no runs = %v, %v, want none", warnings, err)
+	}
+
+	// One easy run two months ago, then twelve hard days in a row
+	createTestActivity(t, db, 1, "Easy", now.AddDate(0, 0, -60), 5000, 1800, floatPtr(140))
+	createTestMetrics(t, db, 1, floatPtr(1.5), floatPtr(40))
+	for back := 1; back <= 12; back++ {
+		id := int64(back + 1)
+		createTestActivity(t, db, id, "Hard", now.AddDate(0, 0, -back), 12000, 3600, floatPtr(160))
+		createTestMetrics(t, db, id, floatPtr(1.5), floatPtr(float64(140+back%2*20)))
+	}
+	if err := rebuildFitnessTrends(db, now); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings, err = svc.GetTrainingWarnings(now)
+	if err != nil {
+		t.Fatalf("GetTrainingWarnings() error = %v", err)
+	}
+	var kinds []string
+	for _, w := range warnings {
+		kinds = append(kinds, w.Kind)
+	}
+	if want := []string{WarningACWR, WarningMonotony, WarningRest}; !slices.Equal(kinds, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+
+	svc.SetRestDayWarningDays(-1)
+	warnings, err = svc.GetTrainingWarnings(now)
+	if err != nil || len(warnings) != 2 {
+		t.Errorf("GetTrainingWarnings() with the rest warning off = %v, %v, want 2", warnings, err)
+	}
+}
+
+func TestQueryService_GetMileageRamp(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	cfg := testAthleteConfig()
+	cfg.WeeklyGoalKm = 40
+	svc := NewQueryService(db, cfg)
+
+	// 20 km, then 25 (+25%), 26 (+4%), and 30 so far this week (+15%)
+	thisWeek := svc.weekStart(svc.bucketNow())
+	for i, km := range []float64{20, 25, 26, 30} {
+		start := thisWeek.AddDate(0, 0, 7*(i-3)).Add(time.Hour)
+		createTestActivity(t, db, int64(i+1), "Run", start, km*1000, int(km)*300, nil)
+		createTestMetrics(t, db, int64(i+1), floatPtr(1.5), floatPtr(50))
+	}
+
+	data, err := svc.GetMileageRamp(3)
+	if err != nil {
+		t.Fatalf("GetMileageRamp failed: %v", err)
+	}
+	if data.Months != 3 || data.ThresholdPct != 10 {
+		t.Errorf("expected 3 months at the default 10%%, got %d at %v", data.Months, data.ThresholdPct)
+	}
+	n := len(data.Weeks)
+	if n < 9 || !data.Weeks[n-1].WeekStart.Equal(thisWeek.AddDate(0, 0, -7)) {
+		t.Fatalf("expected completed weeks ending last week, got %d ending %v", n, data.Weeks[n-1].WeekStart)
+	}
+	if w := data.Weeks[n-3]; w.HasRamp || w.Over {
+		t.Errorf("expected no ramp after a week without running, got %+v", w)
+	}
+	if w := data.Weeks[n-2]; !w.Over || math.Abs(w.RampPct-25) > 0.01 {
+		t.Errorf("expected a flagged 25%% ramp, got %+v", w)
+	}
+	if w := data.Weeks[n-1]; w.Over || math.Abs(w.RampPct-4) > 0.01 {
+		t.Errorf("expected an unflagged 4%% ramp, got %+v", w)
+	}
+	if data.OverWeeks != 1 {
+		t.Errorf("expected 1 week over the threshold, got %d", data.OverWeeks)
+	}
+
+	// Looking ahead: this week and the goal against last week's 26 km
+	if !data.ThisWeek.Over || math.Abs(data.Cap-28600) > 0.01 {
+		t.Errorf("expected this week over a 28.6 km cap, got %+v under %.0f", data.ThisWeek, data.Cap)
+	}
+	if data.Goal != 40000 || !data.GoalOver || math.Abs(data.GoalRampPct-53.85) > 0.01 {
+		t.Errorf("expected the 40 km goal flagged as a 53.85%% ramp, got %v, %v, %.2f", data.Goal, data.GoalOver, data.GoalRampPct)
+	}
+
+	warnings, err := svc.GetTrainingWarnings(time.Now())
+	if err != nil || len(warnings) != 1 || warnings[0].Kind != WarningRamp {
+		t.Errorf("GetTrainingWarnings() = %v, %v, want the ramp warning", warnings, err)
+	}
+
+	// A looser threshold lets this week through
+	svc.SetRampThreshold(20)
+	if data, err = svc.GetMileageRamp(3); err != nil || data.ThisWeek.Over || data.OverWeeks != 1 {
+		t.Errorf("expected only the 25%% week over a 20%% threshold, got %+v, %v", data, err)
+	}
+}
+
+func TestQueryService_TrainingPlan(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewQueryService(db, testAthleteConfig())
+
+	if view, err := svc.GetTrainingPlan(); err != nil || view != nil {
+		t.Fatalf("GetTrainingPlan() = %+v, %v, want nil without a plan", view, err)
+	}
+
+	// Four weeks of 20, 30, 30 and 40 km before this one
+	thisWeek := svc.weekStart(svc.bucketNow())
+	for i, km := range []float64{20, 30, 30, 40} {
+		start := thisWeek.AddDate(0, 0, 7*(i-4)).Add(time.Hour)
+		createTestActivity(t, db, int64(i+1), "Run", start, km*1000, int(km)*300, nil)
+		createTestMetrics(t, db, int64(i+1), floatPtr(1.5), floatPtr(50))
+	}
+
+	raceDate := thisWeek.AddDate(0, 0, 7*11+5)
+	if _, err := svc.CreateTrainingPlan(raceDate, 50000, MaxTaperWeeks+1, 4); err == nil {
+		t.Error("expected an error for too long a taper")
+	}
+	if _, err := svc.CreateTrainingPlan(thisWeek.AddDate(0, 0, 15), 50000, 3, 4); err == nil {
+		t.Error("expected an error for a taper as long as the plan")
+	}
+	plan, err := svc.CreateTrainingPlan(raceDate, 50000, 3, 4)
+	if err != nil {
+		t.Fatalf("CreateTrainingPlan failed: %v", err)
+	}
+	if plan.BaseDistance != 30000 || !plan.StartWeek.Equal(thisWeek) {
+		t.Errorf("expected a 30 km base from this week, got %+v", plan)
+	}
+
+	view, err := svc.GetTrainingPlan()
+	if err != nil {
+		t.Fatalf("GetTrainingPlan failed: %v", err)
+	}
+	if len(view.Weeks) != 12 || view.Current != 0 || view.Weeks[0].Status != PlanCurrent {
+		t.Fatalf("expected 12 weeks starting this week, got %d at %d", len(view.Weeks), view.Current)
+	}
+	if w := view.Weeks[11]; w.Kind != analysis.PlanRace || w.Status != PlanUpcoming {
+		t.Errorf("expected race week last, got %+v", w)
+	}
+	if view.Missed != 0 || view.Adjusted {
+		t.Errorf("expected nothing missed yet, got %d", view.Missed)
+	}
+
+	// A plan that started three weeks ago, with the middle week missed
+	db2 := openTestDB(t)
+	defer db2.Close()
+	svc = NewQueryService(db2, testAthleteConfig())
+	for i, km := range []float64{32, 10, 20} {
+		start := thisWeek.AddDate(0, 0, 7*(i-3)).Add(time.Hour)
+		createTestActivity(t, db2, int64(i+1), "Run", start, km*1000, int(km)*300, nil)
+		createTestMetrics(t, db2, int64(i+1), floatPtr(1.5), floatPtr(50))
+	}
+	if err := db2.SaveTrainingPlan(store.TrainingPlan{
+		RaceDate:     thisWeek.AddDate(0, 0, 7*8),
+		StartWeek:    thisWeek.AddDate(0, 0, -21),
+		BaseDistance: 30000,
+		PeakDistance: 50000,
+		TaperWeeks:   3,
+		CutbackEvery: 4,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err = svc.GetTrainingPlan()
+	if err != nil {
+		t.Fatalf("GetTrainingPlan failed: %v", err)
+	}
+	if view.Current != 3 || view.Missed != 1 || !view.Adjusted {
+		t.Fatalf("expected the second week missed and the plan adjusted, got current %d, %d missed", view.Current, view.Missed)
+	}
+	wantStatus := []PlanWeekStatus{PlanHit, PlanMissed, PlanOver, PlanCurrent}
+	for i, want := range wantStatus {
+		if got := view.Weeks[i].Status; got != want {
+			t.Errorf("week %d status = %s, want %s", i+1, got, want)
+		}
+	}
+	// The week after the miss rebuilds from its 10 km, not the plan
+	if w := view.Weeks[2]; math.Abs(w.Target-11000) > 0.01 || w.Planned <= w.Target {
+		t.Errorf("expected an 11 km target after the miss, got %+v", w)
+	}
+	if w := view.Weeks[3]; w.Kind != analysis.PlanCutback {
+		t.Errorf("expected the fourth week to stay a cutback, got %s", w.Kind)
+	}
+
+	if err := svc.DeleteTrainingPlan(); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := svc.GetTrainingPlan(); view != nil {
+		t.Errorf("expected no plan after deleting, got %+v", view)
+	}
+}
+
+func TestQueryService_GetPaceAtHR(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	cfg := testAthleteConfig()
+	cfg.PaceAnchors = []float64{140, 150, 165}
+	svc := NewQueryService(db, cfg)
+
+	// 5:30/km at 140 bpm three months ago, 5:00/km now; the middle anchor
+	// was never held
+	now := time.Now()
+	earlier := time.Date(now.Year(), now.Month(), 15, 12, 0, 0, 0, time.UTC).AddDate(0, -3, 0)
+	for i, run := range []struct {
+		start time.Time
+		pace  float64
+	}{{earlier, 5.5}, {now.Add(-time.Hour), 5.0}} {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Run", run.start, 10000, 3000, floatPtr(145))
+		if err := db.SaveActivityMetrics(&store.ActivityMetrics{
+			ActivityID: id,
+			PaceAtZ1:   floatPtr(run.pace),
+			PaceAtZ3:   floatPtr(run.pace - 1),
+		}); err != nil {
+			t.Fatalf("SaveActivityMetrics failed: %v", err)
+		}
+	}
+
+	data, err := svc.GetPaceAtHR(6)
+	if err != nil {
+		t.Fatalf("GetPaceAtHR failed: %v", err)
+	}
+	if len(data.Months) != 6 || len(data.Anchors) != 3 {
+		t.Fatalf("expected 6 months and 3 anchors, got %d and %d", len(data.Months), len(data.Anchors))
+	}
+
+	easy := data.Anchors[0]
+	if easy.Label != "140 bpm" || easy.Runs != 2 {
+		t.Errorf("expected 2 runs at 140 bpm, got %d at %q", easy.Runs, easy.Label)
+	}
+	if !easy.HasImprovement || math.Abs(easy.Improvement-10) > 0.01 {
+		t.Errorf("expected a 10%% improvement, got %.2f (set %v)", easy.Improvement, easy.HasImprovement)
+	}
+	if data.Anchors[1].Runs != 0 || data.Anchors[1].HasImprovement {
+		t.Errorf("expected no runs at 150 bpm, got %d", data.Anchors[1].Runs)
+	}
+	if data.Anchors[2].Label != "LTHR" {
+		t.Errorf("expected the threshold anchor to be labeled LTHR, got %q", data.Anchors[2].Label)
+	}
+
+	// The dashboard shows the last 28 days only
+	dash, err := svc.GetDashboardData()
+	if err != nil {
+		t.Fatalf("GetDashboardData failed: %v", err)
+	}
+	want := 5.0 * 60 * MetersPerMile / MetersPerKm
+	if len(dash.PaceAtHR) != 3 || dash.PaceAtHR[0].Runs != 1 || math.Abs(dash.PaceAtHR[0].Pace-want) > 0.01 {
+		t.Errorf("expected the dashboard pace at 140 bpm to be %.1f s/mi from 1 run, got %+v", want, dash.PaceAtHR)
+	}
+}
+
+func TestQueryService_SetLoadModel(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// An hour yesterday: TRIMP 100, HRSS 40, and RPE 5 for a session load of 300
+	createTestActivity(t, db, 1, "Run", time.Now().AddDate(0, 0, -1), 10000, 3600, floatPtr(150))
+	if err := db.SaveActivityMetrics(&store.ActivityMetrics{
+		ActivityID: 1,
+		TRIMP:      floatPtr(100),
+		HRSS:       floatPtr(40),
+	}); err != nil {
+		t.Fatalf("SaveActivityMetrics failed: %v", err)
+	}
+	if err := svc.SetActivityRPE(1, &store.ActivityRPE{RPE: 5}); err != nil {
+		t.Fatalf("SetActivityRPE failed: %v", err)
+	}
+	if err := rebuildFitnessTrends(db, time.Now()); err != nil {
+		t.Fatalf("rebuildFitnessTrends failed: %v", err)
+	}
+
+	ctl := func() float64 {
+		t.Helper()
+		trend, err := db.GetLatestFitnessTrend()
+		if err != nil || trend == nil || trend.CTL == nil {
+			t.Fatalf("GetLatestFitnessTrend() = %v, %v", trend, err)
+		}
+		return *trend.CTL
+	}
+	trimpCTL := ctl()
+
+	tests := []struct {
+		model string
+		ratio float64
+	}{
+		{config.LoadModelHRSS, 0.4},
+		{config.LoadModelSessionRPE, 3},
+		{config.LoadModelTRIMP, 1},
+	}
+	for _, tt := range tests {
+		if err := svc.SetLoadModel(tt.model); err != nil {
+			t.Fatalf("SetLoadModel(%s) failed: %v", tt.model, err)
+		}
+		if got := ctl() / trimpCTL; math.Abs(got-tt.ratio) > 1e-9 {
+			t.Errorf("%s: CTL is %.3f times TRIMP's, want %.1f", tt.model, got, tt.ratio)
+		}
+	}
+
+	// Without an RPE, session-RPE falls back to TRIMP
+	if err := svc.SetLoadModel(config.LoadModelSessionRPE); err != nil {
+		t.Fatalf("SetLoadModel failed: %v", err)
+	}
+	if err := svc.SetActivityRPE(1, nil); err != nil {
+		t.Fatalf("SetActivityRPE failed: %v", err)
+	}
+	if got := ctl(); math.Abs(got-trimpCTL) > 1e-9 {
+		t.Errorf("CTL without an RPE = %.3f, want TRIMP's %.3f", got, trimpCTL)
+	}
+}
+
+func TestQueryService_SetLoadModelRescalesHRSS(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+	if err := svc.SetLoadModel(config.LoadModelHRSS); err != nil {
+		t.Fatalf("SetLoadModel failed: %v", err)
+	}
+
+	// A run analyzed when HRSS equaled TRIMP
+	createTestActivity(t, db, 1, "Run", time.Now().AddDate(0, 0, -1), 10000, 3600, floatPtr(150))
+	if err := db.SaveActivityMetrics(&store.ActivityMetrics{
+		ActivityID: 1,
+		TRIMP:      floatPtr(100),
+		HRSS:       floatPtr(100),
+	}); err != nil {
+		t.Fatalf("SaveActivityMetrics failed: %v", err)
+	}
+	if _, err := db.DB().Exec("UPDATE activity_metrics SET hrss_scaled = 0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rebuildFitnessTrends(db, time.Now()); err != nil {
+		t.Fatalf("rebuildFitnessTrends failed: %v", err)
+	}
+	legacy, err := db.GetLatestFitnessTrend()
+	if err != nil || legacy == nil || legacy.CTL == nil {
+		t.Fatalf("GetLatestFitnessTrend() = %v, %v", legacy, err)
+	}
+
+	// Applying the same model again rescales the run and rebuilds the trends
+	if err := svc.SetLoadModel(config.LoadModelHRSS); err != nil {
+		t.Fatalf("SetLoadModel failed: %v", err)
+	}
+	perTRIMP := analysis.HRSSPerTRIMP(athleteZones(testAthleteConfig()))
+	m, err := db.GetActivityMetrics(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.HRSS == nil || math.Abs(*m.HRSS-100*perTRIMP) > 1e-9 {
+		t.Errorf("HRSS = %v, want %.3f", m.HRSS, 100*perTRIMP)
+	}
+	trend, err := db.GetLatestFitnessTrend()
+	if err != nil || trend == nil || trend.CTL == nil {
+		t.Fatalf("GetLatestFitnessTrend() = %v, %v", trend, err)
+	}
+	if got := *trend.CTL / *legacy.CTL; math.Abs(got-perTRIMP) > 1e-9 {
+		t.Errorf("CTL is %.3f times the legacy one, want %.3f", got, perTRIMP)
+	}
+}
+
+func TestQueryService_GetFeelVsForm(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+
+	// Three hard days in a row feel awful, the easy runs after a rest good
+	now := time.Now()
+	runs := []struct {
+		daysAgo int
+		trimp   float64
+		feel    int
+	}{
+		{12, 200, 0},
+		{11, 200, 1},
+		{10, 200, 1},
+		{5, 50, 4},
+		{2, 50, 5},
+	}
+	for i, r := range runs {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, "Run", now.AddDate(0, 0, -r.daysAgo), 10000, 3600, floatPtr(150))
+		createTestMetrics(t, db, id, floatPtr(1.2), floatPtr(r.trimp))
+		if r.feel == 0 {
+			continue
+		}
+		feel := r.feel
+		if err := svc.SetActivityRPE(id, &store.ActivityRPE{RPE: 6, Feel: &feel}); err != nil {
+			t.Fatalf("SetActivityRPE failed: %v", err)
+		}
+	}
+	if err := rebuildFitnessTrends(db, now); err != nil {
+		t.Fatalf("rebuildFitnessTrends failed: %v", err)
+	}
+
+	data, err := svc.GetFeelVsForm(6)
+	if err != nil {
+		t.Fatalf("GetFeelVsForm failed: %v", err)
+	}
+	if len(data.Points) != 4 {
+		t.Fatalf("expected the 4 runs with a feel, got %+v", data.Points)
+	}
+	if data.Points[0].ActivityID != 2 || data.Points[0].Feel != 1 || data.Points[0].RPE != 6 {
+		t.Errorf("expected the oldest run with a feel first, got %+v", data.Points[0])
+	}
+	if data.Points[1].TSB >= data.Points[0].TSB || data.Points[3].TSB <= data.Points[1].TSB {
+		t.Errorf("expected form to fall through the hard days and recover, got %+v", data.Points)
+	}
+	if !data.HasCorrelation || data.Correlation < 0.5 {
+		t.Errorf("expected feel to track form, got r = %.2f (%v)", data.Correlation, data.HasCorrelation)
+	}
+}
+
+func TestQueryService_ActivityDetailCache(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewQueryService(db, testAthleteConfig())
+	createTestActivity(t, db, 1, "Morning Run", time.Now(), 5000, 1800, floatPtr(150))
+	createTestStreams(t, db, 1, 600, 3.0, 150)
+
+	first, err := svc.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	if again, _ := svc.GetActivityDetailByID(1); again != first {
+		t.Error("second view rebuilt the detail, want it cached")
+	}
+
+	// Any write drops it, such as a note or a sync
+	if err := svc.SetActivityNote(1, "windy"); err != nil {
+		t.Fatalf("SetActivityNote failed: %v", err)
+	}
+	edited, err := svc.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID failed: %v", err)
+	}
+	if edited == first || edited.Note != "windy" {
+		t.Errorf("after a note the detail was stale: note %q", edited.Note)
+	}
+
+	svc.InvalidateActivityDetails(1)
+	if got, _ := svc.GetActivityDetailByID(1); got == edited {
+		t.Error("InvalidateActivityDetails kept the cached detail")
+	}
+
+	// Changing max HR changes the zones
+	cached, _ := svc.GetActivityDetailByID(1)
+	cfg := testAthleteConfig()
+	cfg.MaxHR = 200
+	svc.SetAthleteConfig(cfg)
+	if got, _ := svc.GetActivityDetailByID(1); got == cached || got.ConfiguredMax != 200 {
+		t.Error("SetAthleteConfig kept the cached detail")
+	}
+}
+
+func TestDetailCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newDetailCache()
+	for id := range int64(detailCacheSize) {
+		c.put(id, 1, &ActivityDetail{})
+	}
+	c.get(0, 1) // now the most recently used
+	c.put(detailCacheSize, 1, &ActivityDetail{})
+
+	if _, ok := c.get(0, 1); !ok {
+		t.Error("recently viewed detail 0 was evicted")
+	}
+	if _, ok := c.get(1, 1); ok {
+		t.Error("least recently used detail 1 was kept")
+	}
+	if _, ok := c.get(detailCacheSize, 2); ok {
+		t.Error("detail from an older generation was served")
+	}
+}
+
+func TestQueryService_ArchiveSeason(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "data.db")
+	db, err := store.OpenPath(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cutoff falls midweek, splitting the week of Dec 28
+	runs := []time.Time{
+		time.Date(2019, 6, 5, 7, 0, 0, 0, time.UTC),
+		time.Date(2020, 12, 29, 7, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 2, 7, 0, 0, 0, time.UTC),
+	}
+	for i, start := range runs {
+		createTestActivity(t, db, int64(i+1), "Run", start, 8000, 2400, floatPtr(150))
+		createTestMetrics(t, db, int64(i+1), floatPtr(1.5), floatPtr(60))
+	}
+	qs := NewQueryService(db, testAthleteConfig())
+	if err := rebuildAllWeeklySummaries(db); err != nil {
+		t.Fatal(err)
+	}
+
+	archivePath := filepath.Join(dir, "archives", "old.db")
+	moved, err := qs.ArchiveSeason(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), archivePath)
+	if err != nil {
+		t.Fatalf("ArchiveSeason() error = %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("ArchiveSeason() moved %d, want 2", moved)
+	}
+
+	weeks, err := db.GetWeeklySummaries(time.Time{}, time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weeks) != 1 || weeks[0].RunCount != 1 {
+		t.Errorf("hot weekly summaries = %+v, want the split week with 1 run", weeks)
+	}
+	db.Close()
+
+	all, err := store.OpenPathAllTime(dbPath, []string{archivePath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer all.Close()
+	weeks, err = all.GetWeeklySummaries(time.Time{}, time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weeks) != 2 || weeks[1].RunCount != 2 || weeks[1].Distance != 16000 {
+		t.Errorf("all-time weekly summaries = %+v, want 2019's week and the split week added up", weeks)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// DefaultManualActivityName is used when a manual activity is added without a name
+const DefaultManualActivityName = "Manual Run"
+
+// ManualActivity describes a run entered by hand, e.g. on a treadmill or
+// without a watch
+type ManualActivity struct {
+	Name      string
+	StartTime time.Time // local wall-clock time of the run
+	Distance  float64   // meters
+	Duration  int       // seconds
+	AvgHR     *float64  // optional average heart rate (bpm)
+	RPE       *int      // optional perceived effort (1-10)
+	Feel      *int      // optional feel (1 awful to 5 great), with an RPE
+}
+
+// Validate checks that a manual activity has the fields needed for metrics
+func (m ManualActivity) Validate() error {
+	if m.StartTime.IsZero() {
+		return errors.New("start time is required")
+	}
+	if m.Distance <= 0 {
+		return errors.New("distance must be positive")
+	}
+	if m.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if m.AvgHR != nil && (*m.AvgHR < MinValidHeartrate || *m.AvgHR > MaxValidHeartrate) {
+		return fmt.Errorf("average HR must be between %d and %d", MinValidHeartrate, MaxValidHeartrate)
+	}
+	if m.RPE != nil && (*m.RPE < store.MinRPE || *m.RPE > store.MaxRPE) {
+		return fmt.Errorf("RPE must be between %d and %d", store.MinRPE, store.MaxRPE)
+	}
+	if m.Feel != nil {
+		if m.RPE == nil {
+			return errors.New("feel is logged with an RPE")
+		}
+		if *m.Feel < store.MinFeel || *m.Feel > store.MaxFeel {
+			return fmt.Errorf("feel must be between %d and %d", store.MinFeel, store.MaxFeel)
+		}
+	}
+	return nil
+}
+
+// AddManualActivity stores a manual activity and its training load so it
+// counts toward weekly stats and fitness trends. It returns the new activity ID.
+func (q *QueryService) AddManualActivity(m ManualActivity) (int64, error) {
+	if err := m.Validate(); err != nil {
+		return 0, err
+	}
+
+	name := strings.TrimSpace(m.Name)
+	if name == "" {
+		name = DefaultManualActivityName
+	}
+
+	// start_date_local holds the wall-clock time tagged as UTC, matching Strava
+	local := m.StartTime
+	wallClock := time.Date(local.Year(), local.Month(), local.Day(),
+		local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+
+	activity := store.Activity{
+		Name:             name,
+		Type:             "Run",
+		StartDate:        local.UTC(),
+		StartDateLocal:   wallClock,
+		Timezone:         local.Location().String(),
+		Distance:         m.Distance,
+		MovingTime:       m.Duration,
+		ElapsedTime:      m.Duration,
+		AverageHeartrate: m.AvgHR,
+		HasHeartrate:     m.AvgHR != nil,
+		Manual:           true,
+	}
+	if auth, err := q.store.GetAuth(); err == nil {
+		activity.AthleteID = auth.AthleteID
+	}
+
+	id, err := q.store.CreateManualActivity(&activity)
+	if err != nil {
+		return 0, fmt.Errorf("saving manual activity: %w", err)
+	}
+	activity.ID = id
+
+	// Without streams only the HR-based load can be computed
+	metrics := store.ActivityMetrics{ActivityID: id}
+	if m.AvgHR != nil {
+		zones := athleteZones(q.athleteCfg)
+		trimp := analysis.TRIMP(activity, nil, zones)
+		hrss := analysis.HRSS(activity, nil, zones)
+		metrics.TRIMP = &trimp
+		metrics.HRSS = &hrss
+	}
+	if err := q.store.SaveActivityMetrics(&metrics); err != nil {
+		return 0, fmt.Errorf("saving manual activity metrics: %w", err)
+	}
+	if err := q.store.SaveActivityStreamStats(activityStreamStats(id, nil)); err != nil {
+		return 0, fmt.Errorf("saving manual activity stream stats: %w", err)
+	}
+
+	if m.RPE != nil {
+		if err := q.store.SetActivityRPE(id, &store.ActivityRPE{RPE: *m.RPE, Feel: m.Feel}); err != nil {
+			return 0, fmt.Errorf("saving manual activity RPE: %w", err)
+		}
+	}
+
+	if err := q.refreshWeeklySummary(id); err != nil {
+		return 0, fmt.Errorf("updating weekly summary: %w", err)
+	}
+	if err := rebuildFitnessTrends(q.store, time.Now()); err != nil {
+		return 0, fmt.Errorf("updating fitness trends: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"runner/internal/store"
+)
+
+// PaceAnchor is a heart rate pace is tracked at, in the order of the
+// stored PaceAtZ1..PaceAtZ3 metrics
+type PaceAnchor struct {
+	HR    float64
+	Label string // "LTHR", "140 bpm" or "60% HRR"
+}
+
+// AnchorPace is the average pace at an anchor over a window of runs
+type AnchorPace struct {
+	PaceAnchor
+	Pace float64 // sec/mile, zero without runs
+	Runs int
+}
+
+// PaceAnchorTrend is the monthly pace at one anchor. Pace falls as aerobic
+// fitness improves, so Improvement is positive when the anchor got faster.
+type PaceAnchorTrend struct {
+	PaceAnchor
+	Monthly []float64 // sec/mile per month, zero for months without runs
+	Runs    int
+
+	// Improvement is the % speed gained from the first month with runs to
+	// the latest, set when they differ
+	Improvement    float64
+	HasImprovement bool
+}
+
+// PaceAtHRData is pace at each HR anchor over the last calendar months
+type PaceAtHRData struct {
+	Months  []time.Time
+	Anchors []PaceAnchorTrend
+}
+
+// paceAnchors returns the anchors metrics are computed at, labeled from the
+// athlete config
+func (q *QueryService) paceAnchors() []PaceAnchor {
+	zones := athleteZones(q.athleteCfg)
+	hrs := zones.PaceAnchorHRs()
+	anchors := make([]PaceAnchor, len(hrs))
+	for i, hr := range hrs {
+		label := fmt.Sprintf("%.0f bpm", hr)
+		switch {
+		case len(zones.PaceAnchors) == 0:
+			label = fmt.Sprintf("%d%% HRR", 60+10*i)
+		case hr == zones.ThresholdHR:
+			label = "LTHR"
+		}
+		anchors[i] = PaceAnchor{HR: hr, Label: label}
+	}
+	return anchors
+}
+
+// anchorPace returns a run's pace at anchor i in sec/mile. Metrics store it
+// in min/km.
+func anchorPace(m store.ActivityMetrics, i int) (float64, bool) {
+	pace := [...]*float64{m.PaceAtZ1, m.PaceAtZ2, m.PaceAtZ3}[i]
+	if pace == nil || *pace <= 0 {
+		return 0, false
+	}
+	return *pace * SecondsPerMinute * MetersPerMile / MetersPerKm, true
+}
+
+// GetPaceAtHR returns the average pace at each HR anchor per calendar month
+// over the last months, oldest first and ending with the current one. Runs
+// only have a pace at anchors they held long enough, and runs analyzed
+// before the anchors last changed keep the old ones until metrics are
+// recomputed.
+func (q *QueryService) GetPaceAtHR(months int) (*PaceAtHRData, error) {
+	now := q.bucketNow()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	activities, metrics, err := q.store.GetActivitiesWithMetricsBetween(first, now.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	data := &PaceAtHRData{Months: make([]time.Time, months)}
+	for i := range data.Months {
+		data.Months[i] = first.AddDate(0, i, 0)
+	}
+
+	for i, anchor := range q.paceAnchors() {
+		trend := PaceAnchorTrend{PaceAnchor: anchor, Monthly: make([]float64, months)}
+		counts := make([]int, months)
+		for j, a := range activities {
+			pace, ok := anchorPace(metrics[j], i)
+			if !ok {
+				continue
+			}
+			k := monthIndex(q.store.BucketTime(a), first)
+			if k < 0 || k >= months {
+				continue
+			}
+			trend.Monthly[k] += pace
+			counts[k]++
+			trend.Runs++
+		}
+
+		var firstPace, lastPace float64
+		for k, n := range counts {
+			if n == 0 {
+				continue
+			}
+			trend.Monthly[k] /= float64(n)
+			if firstPace == 0 {
+				firstPace = trend.Monthly[k]
+			}
+			lastPace = trend.Monthly[k]
+		}
+		if firstPace > 0 && lastPace != firstPace {
+			trend.Improvement = (firstPace/lastPace - 1) * 100
+			trend.HasImprovement = true
+		}
+		data.Anchors = append(data.Anchors, trend)
+	}
+
+	return data, nil
+}
+
+// buildRecentPaceAtHR averages pace at each anchor over the runs of the last
+// EFTrendCompareDays
+func (q *QueryService) buildRecentPaceAtHR(activities []store.Activity, metrics []store.ActivityMetrics) []AnchorPace {
+	since := time.Now().AddDate(0, 0, -EFTrendCompareDays)
+
+	anchors := q.paceAnchors()
+	recent := make([]AnchorPace, len(anchors))
+	for i, anchor := range anchors {
+		recent[i].PaceAnchor = anchor
+		for j, a := range activities {
+			if pace, ok := anchorPace(metrics[j], i); ok && a.StartDate.After(since) {
+				recent[i].Pace += pace
+				recent[i].Runs++
+			}
+		}
+		if recent[i].Runs > 0 {
+			recent[i].Pace /= float64(recent[i].Runs)
+		}
+	}
+	return recent
+}
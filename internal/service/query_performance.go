@@ -0,0 +1,20 @@
+package service
+
+import (
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// performance normalizes a run for its climbing, the temperature when one
+// was recorded, and its distance. It's nil for runs shorter than
+// PerformanceMinRunSecs.
+func (q *QueryService) performance(a store.Activity, tempC *float64) *analysis.Performance {
+	if a.MovingTime < PerformanceMinRunSecs {
+		return nil
+	}
+	p := analysis.NormalizePerformance(a.Distance, a.MovingTime, a.TotalElevationGain, tempC, q.riegelExponent)
+	if p.Score == 0 {
+		return nil
+	}
+	return &p
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// PlanWeekStatus says how a week of the training plan went
+type PlanWeekStatus string
+
+// Plan week statuses
+const (
+	PlanUpcoming PlanWeekStatus = "upcoming"
+	PlanCurrent  PlanWeekStatus = "current"
+	PlanHit      PlanWeekStatus = "hit"
+	PlanMissed   PlanWeekStatus = "missed" // under PlanMissedPct of the target
+	PlanOver     PlanWeekStatus = "over"   // past the target by more than the ramp threshold
+)
+
+// PlanWeekDisplay is one week of the training plan with the distance run
+type PlanWeekDisplay struct {
+	WeekStart time.Time
+	Label     string
+	Kind      analysis.PlanWeekKind
+	Planned   float64 // meters, as first planned
+	Target    float64 // meters, after rebuilding for missed weeks
+	Actual    float64 // meters run, so far for the current week
+	Status    PlanWeekStatus
+}
+
+// TrainingPlanView is the training plan screen: every week from the start
+// of the plan through race week
+type TrainingPlanView struct {
+	Plan     store.TrainingPlan
+	Weeks    []PlanWeekDisplay
+	Current  int  // index of this week; len(Weeks) once race week is past
+	Missed   int  // completed weeks under PlanMissedPct of their target
+	Adjusted bool // the weeks after a miss were rebuilt from what was run
+}
+
+// Done reports whether race week is over
+func (v TrainingPlanView) Done() bool {
+	return v.Current >= len(v.Weeks)
+}
+
+// ParsePlanRaceDate parses a race date as YYYY-MM-DD. It must be after today.
+func ParsePlanRaceDate(input string, today time.Time) (time.Time, error) {
+	s := strings.TrimSpace(input)
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", input)
+	}
+	if !d.After(calendarDay(today)) {
+		return time.Time{}, fmt.Errorf("race date %s has to be in the future", s)
+	}
+	return d, nil
+}
+
+// ParsePlanDistance parses a weekly distance in miles, or kilometers unless
+// miles is set, and returns it in meters
+func ParsePlanDistance(input string, miles bool) (float64, error) {
+	distance, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+	if err != nil || distance <= 0 {
+		return 0, fmt.Errorf("invalid distance %q", input)
+	}
+	if miles {
+		return distance * MetersPerMile, nil
+	}
+	return distance * MetersPerKm, nil
+}
+
+// ParsePlanTaperWeeks parses how many weeks to taper, race week included.
+// Blank means DefaultTaperWeeks.
+func ParsePlanTaperWeeks(input string) (int, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return DefaultTaperWeeks, nil
+	}
+	weeks, err := strconv.Atoi(s)
+	if err != nil || weeks < 1 || weeks > MaxTaperWeeks {
+		return 0, fmt.Errorf("taper must be 1-%d weeks", MaxTaperWeeks)
+	}
+	return weeks, nil
+}
+
+// ParsePlanCutbackEvery parses how often a cutback week comes round: every
+// Nth week, or 0 for none. Blank means DefaultCutbackEvery.
+func ParsePlanCutbackEvery(input string) (int, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return DefaultCutbackEvery, nil
+	}
+	every, err := strconv.Atoi(s)
+	if err != nil || every < 0 || every == 1 {
+		return 0, errors.New("cutback weeks come every 2 or more weeks, or 0 for none")
+	}
+	return every, nil
+}
+
+// CreateTrainingPlan plans a build from this week to the race on raceDate,
+// peaking at peak meters a week, replacing any plan already saved. The
+// build starts from the average of the last PlanBaseWeeks completed weeks.
+func (q *QueryService) CreateTrainingPlan(raceDate time.Time, peak float64, taperWeeks, cutbackEvery int) (*store.TrainingPlan, error) {
+	if peak <= 0 {
+		return nil, errors.New("peak weekly distance is required")
+	}
+	if taperWeeks < 1 || taperWeeks > MaxTaperWeeks {
+		return nil, fmt.Errorf("taper must be 1-%d weeks", MaxTaperWeeks)
+	}
+	if cutbackEvery < 0 || cutbackEvery == 1 {
+		return nil, errors.New("cutback weeks come every 2 or more weeks, or 0 for none")
+	}
+
+	plan := store.TrainingPlan{
+		RaceDate:     calendarDay(raceDate),
+		StartWeek:    q.weekStart(calendarDay(q.bucketNow())),
+		PeakDistance: peak,
+		TaperWeeks:   taperWeeks,
+		CutbackEvery: cutbackEvery,
+	}
+	weeks := q.planWeeks(plan)
+	if weeks <= taperWeeks {
+		return nil, fmt.Errorf("a %d week taper leaves no time to build before %s", taperWeeks, plan.RaceDate.Format("Jan 02"))
+	}
+	if weeks > MaxTrainingPlanWeeks {
+		return nil, fmt.Errorf("race is more than %d weeks away", MaxTrainingPlanWeeks)
+	}
+
+	// The current week comes last and isn't over yet
+	summaries, err := q.getWeeklySummaries(PlanBaseWeeks + 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range summaries[:PlanBaseWeeks] {
+		plan.BaseDistance += s.Distance
+	}
+	plan.BaseDistance /= PlanBaseWeeks
+
+	if err := q.store.SaveTrainingPlan(plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// DeleteTrainingPlan removes the training plan
+func (q *QueryService) DeleteTrainingPlan() error {
+	return q.store.DeleteTrainingPlan()
+}
+
+// GetTrainingPlan returns the training plan with the distance run each
+// week, or nil without a plan. Each completed week under PlanMissedPct of
+// its target rebuilds the weeks after it from the distance actually run,
+// so the plan ramps back up safely rather than jumping to where it would
+// have been.
+func (q *QueryService) GetTrainingPlan() (*TrainingPlanView, error) {
+	plan, err := q.store.GetTrainingPlan()
+	if err != nil || plan == nil {
+		return nil, err
+	}
+
+	startWeek := q.weekStart(plan.StartWeek)
+	weeks := q.planWeeks(*plan)
+	current := daysBetween(startWeek, q.weekStart(calendarDay(q.bucketNow()))) / 7
+
+	var summaries []store.WeeklySummary
+	if current >= 0 {
+		if summaries, err = q.getWeeklySummaries(current + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	spec := analysis.MileagePlanSpec{
+		Weeks:        weeks,
+		Peak:         plan.PeakDistance,
+		TaperWeeks:   plan.TaperWeeks,
+		CutbackEvery: plan.CutbackEvery,
+		RampPct:      q.rampThreshold,
+	}
+	planned := analysis.PlanMileage(spec, 0, plan.BaseDistance)
+	targets := append([]analysis.PlanWeek(nil), planned...)
+
+	view := &TrainingPlanView{Plan: *plan, Current: min(max(current, 0), weeks)}
+	for i, p := range planned {
+		weekStart := startWeek.AddDate(0, 0, 7*i)
+		w := PlanWeekDisplay{
+			WeekStart: weekStart,
+			Label:     q.weekLabel(weekStart),
+			Kind:      p.Kind,
+			Planned:   p.Target,
+			Target:    targets[i].Target,
+			Status:    PlanUpcoming,
+		}
+		if i < len(summaries) {
+			w.Actual = summaries[i].Distance
+		}
+
+		switch {
+		case i == current:
+			w.Status = PlanCurrent
+		case i < current:
+			switch {
+			case w.Actual < w.Target*PlanMissedPct/100:
+				w.Status = PlanMissed
+				view.Missed++
+				view.Adjusted = view.Adjusted || i < weeks-1
+				copy(targets[i+1:], analysis.PlanMileage(spec, i+1, w.Actual))
+			case w.Actual > analysis.RampCap(w.Target, q.rampThreshold):
+				w.Status = PlanOver
+			default:
+				w.Status = PlanHit
+			}
+		}
+		view.Weeks = append(view.Weeks, w)
+	}
+	return view, nil
+}
+
+// planWeeks counts the weeks of a plan, its first week through race week
+func (q *QueryService) planWeeks(plan store.TrainingPlan) int {
+	return daysBetween(q.weekStart(plan.StartWeek), q.weekStart(plan.RaceDate))/7 + 1
+}
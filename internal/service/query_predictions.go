@@ -1,18 +1,39 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
 	"runner/internal/analysis"
 	"runner/internal/store"
 )
 
+// RecentRaceDays is how far back the recent-race model looks for a run at a
+// race distance
+const RecentRaceDays = 90
+
 // PredictionDisplay represents a formatted prediction for display
 type PredictionDisplay struct {
 	TargetDistance   string  // "5k", "10k", "half", "marathon"
 	TargetLabel      string  // "5K", "10K", "Half Marathon", "Marathon"
 	PredictedTime    string  // formatted duration "M:SS" or "H:MM:SS"
 	PredictedPace    string  // formatted pace "M:SS/mi"
+	PredictedSeconds int     // predicted finish time, for unit-aware pace
+	TargetMeters     float64 // race distance in meters
 	Confidence       string  // "High", "Medium", "Low"
 	ConfidenceScore  float64
+
+	// Alternative models; zero seconds and "-" when a model has no prediction
+	RiegelSeconds int // Riegel power law from the source PR
+	RiegelTime    string
+	RecentSeconds int // Riegel from the best recent race-distance run
+	RecentTime    string
+
+	// Ensemble of VDOT, Riegel and recent race, with the spread between
+	// models as an uncertainty band
+	Ensemble     analysis.EnsembleEstimate
+	EnsembleTime string
+	Range        string // "41:10 - 42:30 (±0:40)", empty with a single model
 }
 
 // PredictionsData contains all data needed for the predictions screen
@@ -25,6 +46,11 @@ type PredictionsData struct {
 	SourceTime     string // formatted source PR time
 	LastUpdated    string // when predictions were computed
 	HasPredictions bool
+
+	RiegelExponent float64
+	HasRecentRace  bool
+	RecentRace     string // "10K in 41:30"
+	RecentRaceDate string // "Oct 03, 2026"
 }
 
 // GetRacePredictions retrieves all race predictions formatted for display
@@ -54,6 +80,19 @@ func (q *QueryService) GetRacePredictions() (*PredictionsData, error) {
 	if err == nil && sourcePR != nil {
 		data.SourceDate = sourcePR.AchievedAt.Format("Jan 02, 2006")
 		data.SourceTime = formatDuration(sourcePR.DurationSeconds)
+	} else {
+		sourcePR = nil
+	}
+
+	data.RiegelExponent = q.riegelExponent
+	recent, err := q.bestRecentRace()
+	if err != nil {
+		return nil, err
+	}
+	if recent != nil {
+		data.HasRecentRace = true
+		data.RecentRace = formatRaceLabel(recent.Distance) + " in " + formatDuration(recent.MovingTime)
+		data.RecentRaceDate = recent.StartDate.Format("Jan 02, 2006")
 	}
 
 	// Format predictions
@@ -63,15 +102,84 @@ func (q *QueryService) GetRacePredictions() (*PredictionsData, error) {
 			TargetLabel:      analysis.GetTargetLabel(p.TargetDistance),
 			PredictedTime:    formatDuration(p.PredictedSeconds),
 			PredictedPace:    formatPace(int(p.PredictedPace)),
+			PredictedSeconds: p.PredictedSeconds,
+			TargetMeters:     p.TargetMeters,
 			Confidence:       capitalizeFirst(p.Confidence),
 			ConfidenceScore:  p.ConfidenceScore,
 		}
+		if sourcePR != nil {
+			display.RiegelSeconds = analysis.RiegelPredict(sourcePR.DistanceMeters, sourcePR.DurationSeconds, p.TargetMeters, q.riegelExponent)
+		}
+		if recent != nil {
+			display.RecentSeconds = analysis.RiegelPredict(recent.Distance, recent.MovingTime, p.TargetMeters, q.riegelExponent)
+		}
+		display.RiegelTime = formatPredictedTime(display.RiegelSeconds)
+		display.RecentTime = formatPredictedTime(display.RecentSeconds)
+
+		e := analysis.Ensemble(p.PredictedSeconds, display.RiegelSeconds, display.RecentSeconds)
+		display.Ensemble = e
+		display.EnsembleTime = formatPredictedTime(e.MeanSeconds)
+		if e.Models > 1 {
+			display.Range = fmt.Sprintf("%s - %s (±%s)",
+				formatDuration(e.LowSeconds), formatDuration(e.HighSeconds), formatDuration(e.Spread()/2))
+		}
 		data.Predictions = append(data.Predictions, display)
 	}
 
 	return data, nil
 }
 
+// bestRecentRace returns the run at a race distance in the last
+// RecentRaceDays with the highest VDOT, so races at different distances
+// compare fairly. Returns nil when there is none.
+func (q *QueryService) bestRecentRace() (*store.Activity, error) {
+	cutoff := time.Now().AddDate(0, 0, -RecentRaceDays)
+	var best *store.Activity
+	var bestVDOT float64
+
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		activities, err := q.store.ListActivities(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range activities {
+			a := &activities[i]
+			// Newest first, so everything after this is older
+			if a.StartDate.Before(cutoff) {
+				return best, nil
+			}
+			if a.Excluded {
+				continue
+			}
+			if _, _, matches := analysis.GetMatchingRaceCategory(a.Distance); !matches {
+				continue
+			}
+			if vdot := analysis.CalculateVDOT(a.Distance, a.MovingTime); vdot > bestVDOT {
+				best, bestVDOT = a, vdot
+			}
+		}
+
+		if len(activities) < PeriodStatsActivityLimit {
+			return best, nil
+		}
+	}
+}
+
+// formatPredictedTime formats a model's prediction, or "-" when it has none
+func formatPredictedTime(seconds int) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return formatDuration(seconds)
+}
+
+// formatRaceLabel names the race distance an activity matches
+func formatRaceLabel(meters float64) string {
+	category, _, _ := analysis.GetMatchingRaceCategory(meters)
+	return formatCategoryLabel(category)
+}
+
 // formatSourceCategory returns a human-readable label for the source PR category
 func formatSourceCategory(category string) string {
 	labels := map[string]string{
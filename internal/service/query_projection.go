@@ -0,0 +1,132 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// TrendProjection is a weekly series smoothed with loess and projected
+// TrendProjectionWeeks ahead along a linear fit. Weeks without data are
+// skipped when fitting.
+type TrendProjection struct {
+	OK    bool // false when there are fewer than analysis.MinTrendPoints weeks
+	Weeks int  // weeks with data
+
+	// History has one loess-smoothed value per week, oldest first and ending
+	// with the current week. Weeks without data are NaN.
+	History []float64
+
+	// Projected starts at the current week and runs TrendProjectionWeeks
+	// ahead. Low and High are the edges of the ~95% prediction band.
+	Projected []float64
+	Low       []float64
+	High      []float64
+
+	Current      float64 // fitted value for the current week
+	ProjectedEnd float64 // fitted value at the end of the projection
+	SlopePerWeek float64
+}
+
+// buildEFProjection fits the weekly average EF of the last TrendHistoryWeeks,
+// adjusting runs with a temperature in temps for the heat
+func (q *QueryService) buildEFProjection(activities []store.Activity, metrics []store.ActivityMetrics, temps map[int64]float64) TrendProjection {
+	thisWeek := q.weekStart(q.bucketNow())
+	sums := make(map[int]float64)
+	counts := make(map[int]int)
+	for i, a := range activities {
+		if a.Excluded || metrics[i].EfficiencyFactor == nil || *metrics[i].EfficiencyFactor <= 0 {
+			continue
+		}
+		if w := trendWeekIndex(q.store.BucketTime(a), thisWeek); w >= 0 {
+			ef := *metrics[i].EfficiencyFactor
+			if temp, ok := temps[a.ID]; ok {
+				ef = analysis.HeatAdjustedEF(ef, temp)
+			}
+			sums[w] += ef
+			counts[w]++
+		}
+	}
+
+	weekly := make(map[int]float64, len(sums))
+	for w, sum := range sums {
+		weekly[w] = sum / float64(counts[w])
+	}
+	return projectWeekly(weekly)
+}
+
+// buildVDOTProjection fits the weekly best VDOT implied by the cached
+// pace-curve efforts of at least TrendMinEffortSecs
+func (q *QueryService) buildVDOTProjection() (TrendProjection, error) {
+	thisWeek := q.weekStart(q.bucketNow())
+	since := thisWeek.AddDate(0, 0, -7*(TrendHistoryWeeks-1))
+	efforts, err := q.store.GetDurationEfforts(since)
+	if err != nil {
+		return TrendProjection{}, err
+	}
+
+	weekly := make(map[int]float64)
+	for _, e := range efforts {
+		if e.DurationSeconds < TrendMinEffortSecs {
+			continue
+		}
+		w := trendWeekIndex(e.StartDate, thisWeek)
+		if w < 0 {
+			continue
+		}
+		if vdot := analysis.CalculateVDOT(e.DistanceMeters, e.DurationSeconds); vdot > weekly[w] {
+			weekly[w] = vdot
+		}
+	}
+	return projectWeekly(weekly), nil
+}
+
+// trendWeekIndex places a date in the history window, 0 being the oldest
+// week and TrendHistoryWeeks-1 the current one. Returns -1 outside it.
+func trendWeekIndex(date, thisWeek time.Time) int {
+	weeksAgo := int(thisWeek.Sub(startOfWeek(date, thisWeek.Weekday())).Hours()/24+0.5) / 7
+	if weeksAgo < 0 || weeksAgo >= TrendHistoryWeeks {
+		return -1
+	}
+	return TrendHistoryWeeks - 1 - weeksAgo
+}
+
+// projectWeekly smooths the weekly values and projects them ahead
+func projectWeekly(weekly map[int]float64) TrendProjection {
+	var xs, ys []float64
+	for w := 0; w < TrendHistoryWeeks; w++ {
+		if v, ok := weekly[w]; ok {
+			xs = append(xs, float64(w))
+			ys = append(ys, v)
+		}
+	}
+
+	p := TrendProjection{Weeks: len(xs)}
+	trend, ok := analysis.FitLinearTrend(xs, ys)
+	if !ok {
+		return p
+	}
+	p.OK = true
+	p.SlopePerWeek = trend.Slope
+
+	p.History = make([]float64, TrendHistoryWeeks)
+	for i := range p.History {
+		p.History[i] = math.NaN()
+	}
+	for i, v := range analysis.Loess(xs, ys, TrendLoessSpan) {
+		p.History[int(xs[i])] = v
+	}
+
+	for i := 0; i <= TrendProjectionWeeks; i++ {
+		x := float64(TrendHistoryWeeks - 1 + i)
+		v, band := trend.At(x), trend.Band(x)
+		p.Projected = append(p.Projected, v)
+		p.Low = append(p.Low, v-band)
+		p.High = append(p.High, v+band)
+	}
+	p.Current = p.Projected[0]
+	p.ProjectedEnd = p.Projected[TrendProjectionWeeks]
+	return p
+}
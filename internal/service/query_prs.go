@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"runner/internal/store"
 )
@@ -13,12 +14,37 @@ type PersonalRecordDisplay struct {
 	CategoryLabel  string  // e.g., "5K", "1 Mile", "Best 400m"
 	Time           string  // formatted duration "M:SS" or "H:MM:SS"
 	Pace           string  // formatted pace "M:SS/mi"
+	PacePerMile    float64 // seconds per mile, 0 if unknown
 	AvgHR          string  // formatted HR or "-"
 	Date           string  // formatted date
 	ActivityID     int64
 	ActivityName   string
 	IsEffort       bool    // true for best efforts, false for race distances
 	DistanceMeters float64 // for display purposes
+
+	// Improvement over the previous record in this category, from the PR
+	// history: seconds faster, meters further, or seconds per mile faster
+	// depending on Mode. HasPrevious is false for a first record.
+	HasPrevious bool
+	Margin      float64
+	Mode        store.CompareMode
+
+	// Unverified is set on a best effort over doubtful GPS data, or one far
+	// faster than the record before it, until the athlete confirms it
+	Unverified bool
+}
+
+// FastestSplitsShown is how many of the fastest splits the PRs screen lists
+const FastestSplitsShown = 3
+
+// SplitRecordDisplay is one of the fastest mile or kilometer splits
+type SplitRecordDisplay struct {
+	Split        int    // which split of its run, starting at 1
+	Time         string // formatted duration "M:SS"
+	AvgHR        string // formatted HR or "-"
+	Date         string // formatted date
+	ActivityID   int64
+	ActivityName string
 }
 
 // PRsData contains all data needed for the PRs screen
@@ -26,6 +52,10 @@ type PRsData struct {
 	RaceDistancePRs []PersonalRecordDisplay
 	BestEffortPRs   []PersonalRecordDisplay
 	OtherPRs        []PersonalRecordDisplay
+
+	// The fastest full splits at any point in any run, from the stored splits
+	FastestMileSplits []SplitRecordDisplay
+	FastestKmSplits   []SplitRecordDisplay
 }
 
 // GetPersonalRecords retrieves all personal records formatted for display
@@ -68,10 +98,12 @@ func (q *QueryService) GetPersonalRecords() (*PRsData, error) {
 			ActivityID:     r.ActivityID,
 			ActivityName:   activityNames[r.ActivityID],
 			DistanceMeters: r.DistanceMeters,
+			Unverified:     r.Unverified,
 		}
 
 		if r.PacePerMile != nil {
 			display.Pace = formatPace(int(*r.PacePerMile))
+			display.PacePerMile = *r.PacePerMile
 		} else {
 			display.Pace = "-"
 		}
@@ -82,6 +114,10 @@ func (q *QueryService) GetPersonalRecords() (*PRsData, error) {
 			display.AvgHR = "-"
 		}
 
+		if err := q.applyPRHistory(&display); err != nil {
+			return nil, err
+		}
+
 		// Categorize the record
 		switch {
 		case isRaceDistanceCategory(r.Category):
@@ -98,9 +134,40 @@ func (q *QueryService) GetPersonalRecords() (*PRsData, error) {
 	sortPRsByDistance(data.RaceDistancePRs)
 	sortPRsByDistance(data.BestEffortPRs)
 
+	if data.FastestMileSplits, err = q.fastestSplits(store.SplitsStandard); err != nil {
+		return nil, err
+	}
+	if data.FastestKmSplits, err = q.fastestSplits(store.SplitsMetric); err != nil {
+		return nil, err
+	}
+
 	return data, nil
 }
 
+// fastestSplits formats the fastest stored splits in units
+func (q *QueryService) fastestSplits(units string) ([]SplitRecordDisplay, error) {
+	records, err := q.store.GetFastestSplits(units, FastestSplitsShown)
+	if err != nil {
+		return nil, fmt.Errorf("getting fastest %s splits: %w", units, err)
+	}
+	var displays []SplitRecordDisplay
+	for _, r := range records {
+		display := SplitRecordDisplay{
+			Split:        r.Split,
+			Time:         formatDuration(r.Duration),
+			AvgHR:        "-",
+			Date:         r.StartDateLocal.Format("Jan 02, 2006"),
+			ActivityID:   r.ActivityID,
+			ActivityName: r.ActivityName,
+		}
+		if r.AverageHeartrate != nil {
+			display.AvgHR = fmt.Sprintf("%.0f", *r.AverageHeartrate)
+		}
+		displays = append(displays, display)
+	}
+	return displays, nil
+}
+
 // GetActivityPRs retrieves personal records achieved during a specific activity
 func (q *QueryService) GetActivityPRs(activityID int64) ([]PersonalRecordDisplay, error) {
 	records, err := q.store.GetPersonalRecordsForActivity(activityID)
@@ -117,11 +184,13 @@ func (q *QueryService) GetActivityPRs(activityID int64) ([]PersonalRecordDisplay
 			Date:           r.AchievedAt.Format("Jan 02, 2006"),
 			ActivityID:     r.ActivityID,
 			DistanceMeters: r.DistanceMeters,
+			Unverified:     r.Unverified,
 			IsEffort:       isEffortCategory(r.Category),
 		}
 
 		if r.PacePerMile != nil {
 			display.Pace = formatPace(int(*r.PacePerMile))
+			display.PacePerMile = *r.PacePerMile
 		} else {
 			display.Pace = "-"
 		}
@@ -132,12 +201,131 @@ func (q *QueryService) GetActivityPRs(activityID int64) ([]PersonalRecordDisplay
 			display.AvgHR = "-"
 		}
 
+		if err := q.applyPRHistory(&display); err != nil {
+			return nil, err
+		}
+
 		displays = append(displays, display)
 	}
 
 	return displays, nil
 }
 
+// VerifyActivityEfforts confirms that an activity's best efforts are real,
+// clearing the unverified mark on its records now and on every recompute.
+// Race predictions pick the records up on the next sync.
+func (q *QueryService) VerifyActivityEfforts(activityID int64) error {
+	return q.store.VerifyEfforts(activityID, time.Now())
+}
+
+// applyPRHistory fills in how much a record improved on the one before it
+func (q *QueryService) applyPRHistory(display *PersonalRecordDisplay) error {
+	entries, err := q.store.GetPRHistory(display.Category)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ActivityID != display.ActivityID {
+			continue
+		}
+		display.Mode = e.Mode
+		if e.Margin != nil {
+			display.HasPrevious = true
+			display.Margin = *e.Margin
+		}
+		break
+	}
+	return nil
+}
+
+// PRProgressionPoint is one improvement of a personal record
+type PRProgressionPoint struct {
+	Date            string // formatted date
+	AchievedAt      time.Time
+	ActivityID      int64
+	ActivityName    string
+	Time            string // formatted duration
+	DurationSeconds int
+	DistanceMeters  float64
+	PacePerMile     float64 // seconds per mile, 0 if unknown
+	Margin          float64 // improvement over the previous point; 0 for the first
+}
+
+// PRProgression is the history of one personal record category, oldest first
+type PRProgression struct {
+	Category      string
+	CategoryLabel string
+	Mode          store.CompareMode
+	Points        []PRProgressionPoint
+}
+
+// GetPRProgressions returns the improvement history of every category that
+// has a current record, in the order the PRs screen lists them
+func (q *QueryService) GetPRProgressions() ([]PRProgression, error) {
+	data, err := q.GetPersonalRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	var progressions []PRProgression
+	for _, group := range [][]PersonalRecordDisplay{data.RaceDistancePRs, data.BestEffortPRs, data.OtherPRs} {
+		for _, pr := range group {
+			p, err := q.GetPRProgression(pr.Category)
+			if err != nil {
+				return nil, err
+			}
+			if len(p.Points) > 0 {
+				progressions = append(progressions, *p)
+			}
+		}
+	}
+	return progressions, nil
+}
+
+// GetPRProgression returns every improvement of one personal record category
+func (q *QueryService) GetPRProgression(category string) (*PRProgression, error) {
+	entries, err := q.store.GetPRHistory(category)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ActivityID
+	}
+	activities, err := q.store.GetActivitiesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PRProgression{
+		Category:      category,
+		CategoryLabel: formatCategoryLabel(category),
+	}
+	for _, e := range entries {
+		p.Mode = e.Mode
+		point := PRProgressionPoint{
+			Date:            e.AchievedAt.Format("Jan 02, 2006"),
+			AchievedAt:      e.AchievedAt,
+			ActivityID:      e.ActivityID,
+			Time:            formatDuration(e.DurationSeconds),
+			DurationSeconds: e.DurationSeconds,
+			DistanceMeters:  e.DistanceMeters,
+		}
+		if a, ok := activities[e.ActivityID]; ok {
+			point.ActivityName = a.Name
+		}
+		if e.PacePerMile != nil {
+			point.PacePerMile = *e.PacePerMile
+		}
+		if e.Margin != nil {
+			point.Margin = *e.Margin
+		}
+		p.Points = append(p.Points, point)
+	}
+	return p, nil
+}
+
 // formatCategoryLabel returns a human-readable label for a PR category
 func formatCategoryLabel(category string) string {
 	labels := map[string]string{
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// RaceDisplay is one race on the races screen
+type RaceDisplay struct {
+	ActivityID     int64
+	Name           string
+	StartDate      time.Time
+	Date           string
+	RaceLabel      string // "10K", empty when off the standard distances
+	DistanceMeters float64
+	MovingTime     int
+	Time           string
+	PacePerMile    float64
+	Source         store.RaceSource
+	Placing        string
+	IsPR           bool // holds the personal record for its race distance
+}
+
+// GetRaces returns the races that haven't been dismissed, newest first,
+// leaving out excluded activities
+func (q *QueryService) GetRaces() ([]RaceDisplay, error) {
+	races, err := q.store.GetRaces()
+	if err != nil {
+		return nil, err
+	}
+	if len(races) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(races))
+	for i, r := range races {
+		ids[i] = r.ActivityID
+	}
+	activities, err := q.store.GetActivitiesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := q.store.GetAllPersonalRecords()
+	if err != nil {
+		return nil, err
+	}
+	prActivities := make(map[int64]bool)
+	for _, pr := range records {
+		if strings.HasPrefix(pr.Category, "distance_") {
+			prActivities[pr.ActivityID] = true
+		}
+	}
+
+	var result []RaceDisplay
+	for _, r := range races {
+		a, ok := activities[r.ActivityID]
+		if !ok || a.Excluded {
+			continue
+		}
+		d := RaceDisplay{
+			ActivityID:     a.ID,
+			Name:           a.Name,
+			StartDate:      a.StartDate,
+			Date:           a.StartDate.Format("Jan 02, 2006"),
+			DistanceMeters: a.Distance,
+			MovingTime:     a.MovingTime,
+			Time:           formatDuration(a.MovingTime),
+			PacePerMile:    analysis.CalculatePacePerMile(a.Distance, a.MovingTime),
+			Source:         r.Source,
+			Placing:        r.Placing,
+			IsPR:           prActivities[a.ID],
+		}
+		if category, matches := analysis.RaceDistanceFor(a.Distance); matches {
+			d.RaceLabel = formatCategoryLabel(category)
+		}
+		result = append(result, d)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].StartDate.After(result[j].StartDate) })
+	return result, nil
+}
+
+// SetRacePlacing saves the placing notes for a race; a blank note removes them
+func (q *QueryService) SetRacePlacing(activityID int64, placing string) error {
+	return q.store.SetRacePlacing(activityID, strings.TrimSpace(placing))
+}
+
+// DismissRace removes a run that isn't really a race from the races list.
+// Detection won't list it again.
+func (q *QueryService) DismissRace(activityID int64) error {
+	return q.store.DismissRace(activityID)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// WeekRamp is one week's distance and how much it rose over the week before
+type WeekRamp struct {
+	WeekStart time.Time
+	Label     string
+	Distance  float64 // meters
+	RampPct   float64 // over the week before; negative when distance dropped
+	HasRamp   bool    // false after a week without running
+	Over      bool    // ramped more than the threshold
+}
+
+// RampData checks weekly distance against the ramp threshold: looking back
+// over completed weeks, and ahead for the current week and the weekly goal
+type RampData struct {
+	Months       int
+	ThresholdPct float64
+	Weeks        []WeekRamp // completed weeks, oldest first
+	OverWeeks    int        // completed weeks that ramped past the threshold
+
+	// ThisWeek is the current week so far, and Cap the most it can reach
+	// without ramping past the threshold over last week. Cap is zero after
+	// a week without running.
+	ThisWeek WeekRamp
+	Cap      float64 // meters
+
+	// Goal is the weekly goal in meters, zero without one. GoalOver is set
+	// when reaching it this week would ramp past the threshold.
+	Goal        float64
+	GoalRampPct float64
+	GoalOver    bool
+}
+
+// GetMileageRamp returns the week-over-week distance ramp for every week
+// from the start of the last months calendar months, flagging weeks that
+// rose more than the configured threshold over the week before
+func (q *QueryService) GetMileageRamp(months int) (*RampData, error) {
+	now := q.bucketNow()
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	days := q.weekStart(now).Sub(q.weekStart(first)).Hours() / 24
+	weeks := int(math.Round(days/7)) + 1
+
+	// One week more, for the first week's ramp
+	summaries, err := q.getWeeklySummaries(weeks + 1)
+	if err != nil {
+		return nil, err
+	}
+	ramps := q.weekRamps(summaries)
+
+	data := &RampData{
+		Months:       months,
+		ThresholdPct: q.rampThreshold,
+		Weeks:        ramps[:len(ramps)-1],
+		ThisWeek:     ramps[len(ramps)-1],
+	}
+	for _, w := range data.Weeks {
+		if w.Over {
+			data.OverWeeks++
+		}
+	}
+
+	lastWeek := summaries[len(summaries)-2].Distance
+	if lastWeek > 0 {
+		data.Cap = analysis.RampCap(lastWeek, q.rampThreshold)
+	}
+	if q.athleteCfg.WeeklyGoalKm > 0 {
+		data.Goal = q.athleteCfg.WeeklyGoalKm * MetersPerKm
+		if pct, ok := analysis.WeekOverWeekRamp(lastWeek, data.Goal); ok {
+			data.GoalRampPct = pct
+			data.GoalOver = pct > q.rampThreshold
+		}
+	}
+	return data, nil
+}
+
+// weekRamps measures each week after the first against the week before it
+func (q *QueryService) weekRamps(summaries []store.WeeklySummary) []WeekRamp {
+	var ramps []WeekRamp
+	for i := 1; i < len(summaries); i++ {
+		w := WeekRamp{
+			WeekStart: summaries[i].WeekStart,
+			Label:     q.weekLabel(summaries[i].WeekStart),
+			Distance:  summaries[i].Distance,
+		}
+		w.RampPct, w.HasRamp = analysis.WeekOverWeekRamp(summaries[i-1].Distance, w.Distance)
+		w.Over = w.HasRamp && w.RampPct > q.rampThreshold
+		ramps = append(ramps, w)
+	}
+	return ramps
+}
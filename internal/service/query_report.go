@@ -0,0 +1,195 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// MonthlyReport holds the training summary for one calendar month
+type MonthlyReport struct {
+	Year  int
+	Month time.Month
+	Start time.Time // first day of the month, local time
+
+	RunCount   int
+	Distance   float64 // meters
+	MovingTime int     // seconds
+	Elevation  float64 // meters
+	LongestRun float64 // meters
+
+	// Change in distance from the previous month, in percent. HasPrevious
+	// is false when the previous month has no runs.
+	HasPrevious   bool
+	DistanceDelta float64
+
+	DailyDistance  []float64   // meters, one per day of the month
+	WeekStarts     []time.Time // first days of the weeks touching the month
+	WeekLabels     []string    // parallel to WeekStarts
+	WeeklyDistance []float64   // meters run within the month, parallel to WeekStarts
+
+	// Load: total TRIMP for the month and CTL/ATL/TSB on its last day
+	TotalTRIMP      float64
+	CTL             float64
+	ATL             float64
+	TSB             float64
+	FormDescription string
+
+	Zones ZoneDistribution
+
+	// EF of each run with one, oldest first, and the monthly averages
+	EFValues  []float64
+	AvgEF     float64
+	PrevAvgEF float64 // zero when the previous month has no EF
+
+	PRs []ReportPR // records set during the month, oldest first
+}
+
+// ReportPR is a personal record improvement set during a report's month
+type ReportPR struct {
+	CategoryLabel string
+	Mode          store.CompareMode
+	PRProgressionPoint
+}
+
+// GetMonthlyReport aggregates every stored run into a summary of the given
+// month. It reads only the local database.
+func (q *QueryService) GetMonthlyReport(year int, month time.Month) (*MonthlyReport, error) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	prevStart := start.AddDate(0, -1, 0)
+
+	r := &MonthlyReport{
+		Year:          year,
+		Month:         month,
+		Start:         start,
+		DailyDistance: make([]float64, end.AddDate(0, 0, -1).Day()),
+	}
+	for w := q.weekStart(start); w.Before(end); w = w.AddDate(0, 0, 7) {
+		r.WeekStarts = append(r.WeekStarts, w)
+		r.WeekLabels = append(r.WeekLabels, q.weekLabel(w))
+	}
+	r.WeeklyDistance = make([]float64, len(r.WeekStarts))
+
+	type efRun struct {
+		date time.Time
+		ef   float64
+	}
+	var efRuns []efRun
+	var dailyLoads []analysis.DailyLoad
+	var monthActivities []store.Activity
+	var monthMetrics []store.ActivityMetrics
+	var prevDistance, prevEFSum float64
+	var prevEFCount int
+
+	rpes, err := q.activityRPEs()
+	if err != nil {
+		return nil, err
+	}
+
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		activities, metrics, err := q.store.GetActivitiesWithMetrics(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, a := range activities {
+			date := q.store.BucketTime(a)
+			if a.Excluded || !date.Before(end) {
+				continue
+			}
+			m := metrics[i]
+
+			// Fitness at the end of the month depends on all earlier load
+			if load := q.activityLoad(a, m, rpes); load != nil {
+				dailyLoads = append(dailyLoads, analysis.DailyLoad{Date: date, TRIMP: *load})
+			}
+
+			hasEF := m.EfficiencyFactor != nil && *m.EfficiencyFactor > 0
+			switch {
+			case !date.Before(start):
+				r.RunCount++
+				r.Distance += a.Distance
+				r.MovingTime += a.MovingTime
+				r.Elevation += a.TotalElevationGain
+				if a.Distance > r.LongestRun {
+					r.LongestRun = a.Distance
+				}
+				if m.TRIMP != nil {
+					r.TotalTRIMP += *m.TRIMP
+				}
+				r.DailyDistance[date.Day()-1] += a.Distance
+				r.WeeklyDistance[int(q.weekStart(date).Sub(r.WeekStarts[0]).Hours()/24+0.5)/7] += a.Distance
+				if hasEF {
+					efRuns = append(efRuns, efRun{date, *m.EfficiencyFactor})
+				}
+				monthActivities = append(monthActivities, a)
+				monthMetrics = append(monthMetrics, m)
+
+			case !date.Before(prevStart):
+				prevDistance += a.Distance
+				if hasEF {
+					prevEFSum += *m.EfficiencyFactor
+					prevEFCount++
+				}
+			}
+		}
+
+		if len(activities) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+
+	if prevDistance > 0 {
+		r.HasPrevious = true
+		r.DistanceDelta = percentChange(r.Distance, prevDistance)
+	}
+	if prevEFCount > 0 {
+		r.PrevAvgEF = prevEFSum / float64(prevEFCount)
+	}
+
+	sort.Slice(efRuns, func(i, j int) bool { return efRuns[i].date.Before(efRuns[j].date) })
+	var efSum float64
+	for _, run := range efRuns {
+		r.EFValues = append(r.EFValues, run.ef)
+		efSum += run.ef
+	}
+	if len(efRuns) > 0 {
+		r.AvgEF = efSum / float64(len(efRuns))
+	}
+
+	if len(dailyLoads) > 0 {
+		fitness := analysis.GetCurrentFitness(dailyLoads)
+		r.CTL, r.ATL, r.TSB = fitness.CTL, fitness.ATL, fitness.TSB
+		r.FormDescription = analysis.FormDescription(fitness.TSB)
+	}
+
+	if len(monthActivities) > 0 {
+		zoneSeconds, err := q.loadZoneSeconds(monthActivities, monthMetrics)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range monthActivities {
+			r.Zones.add(zoneSeconds[a.ID])
+		}
+		r.Zones.finish()
+	}
+
+	progressions, err := q.GetPRProgressions()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range progressions {
+		for _, point := range p.Points {
+			achieved := q.store.BucketClock(point.AchievedAt)
+			if !achieved.Before(start) && achieved.Before(end) {
+				r.PRs = append(r.PRs, ReportPR{CategoryLabel: p.CategoryLabel, Mode: p.Mode, PRProgressionPoint: point})
+			}
+		}
+	}
+	sort.SliceStable(r.PRs, func(i, j int) bool { return r.PRs[i].AchievedAt.Before(r.PRs[j].AchievedAt) })
+
+	return r, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"sort"
+	"time"
+)
+
+// MinEFMonthRuns is how many runs with an EF a month needs before it can be
+// a year's best EF month, so one lucky run doesn't win it
+const MinEFMonthRuns = 3
+
+// YearReview holds the totals and highlights for one calendar year
+type YearReview struct {
+	Year       int
+	RunCount   int
+	Distance   float64 // meters
+	MovingTime int     // seconds
+	Elevation  float64 // meters
+	PRsSet     int     // current personal records achieved this year
+
+	// Best EF month: highest average EF over a month with at least
+	// MinEFMonthRuns runs. BestEF is zero when no month qualifies.
+	BestEFMonth time.Month
+	BestEF      float64
+
+	// Biggest week by distance; zero when the year has no runs
+	BiggestWeekStart    time.Time
+	BiggestWeekDistance float64 // meters
+
+	// Change from the previous year, in percent. HasPrevious is false for
+	// the first year on record.
+	HasPrevious   bool
+	DistanceDelta float64
+	TimeDelta     float64
+	RunsDelta     float64
+}
+
+// GetYearInReview aggregates every stored run into per-year totals, newest
+// year first. It reads only the local database.
+func (q *QueryService) GetYearInReview() ([]YearReview, error) {
+	metrics, err := q.store.GetAllMetrics()
+	if err != nil {
+		return nil, err
+	}
+	efByActivity := make(map[int64]float64, len(metrics))
+	for _, m := range metrics {
+		if m.EfficiencyFactor != nil && *m.EfficiencyFactor > 0 {
+			efByActivity[m.ActivityID] = *m.EfficiencyFactor
+		}
+	}
+
+	years := make(map[int]*YearReview)
+	weekDistance := make(map[time.Time]float64)
+	type monthKey struct {
+		year  int
+		month time.Month
+	}
+	efSum := make(map[monthKey]float64)
+	efCount := make(map[monthKey]int)
+
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		activities, err := q.store.ListActivities(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range activities {
+			if a.Excluded {
+				continue
+			}
+			date := q.store.BucketTime(a)
+			year := date.Year()
+			y := years[year]
+			if y == nil {
+				y = &YearReview{Year: year}
+				years[year] = y
+			}
+			y.RunCount++
+			y.Distance += a.Distance
+			y.MovingTime += a.MovingTime
+			y.Elevation += a.TotalElevationGain
+
+			weekDistance[q.weekStart(date)] += a.Distance
+
+			if ef, ok := efByActivity[a.ID]; ok {
+				key := monthKey{year, date.Month()}
+				efSum[key] += ef
+				efCount[key]++
+			}
+		}
+
+		if len(activities) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+
+	if len(years) == 0 {
+		return nil, nil
+	}
+
+	// Weeks belong to the year their first day falls in
+	for start, dist := range weekDistance {
+		if y := years[start.Year()]; y != nil && dist > y.BiggestWeekDistance {
+			y.BiggestWeekStart = start
+			y.BiggestWeekDistance = dist
+		}
+	}
+
+	for key, count := range efCount {
+		if count < MinEFMonthRuns {
+			continue
+		}
+		avg := efSum[key] / float64(count)
+		if y := years[key.year]; y != nil && avg > y.BestEF {
+			y.BestEFMonth = key.month
+			y.BestEF = avg
+		}
+	}
+
+	records, err := q.store.GetAllPersonalRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range records {
+		if y := years[pr.AchievedAt.Year()]; y != nil {
+			y.PRsSet++
+		}
+	}
+
+	result := make([]YearReview, 0, len(years))
+	for _, y := range years {
+		result = append(result, *y)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Year > result[j].Year })
+
+	// Compare each year with the one before it
+	for i := 0; i < len(result)-1; i++ {
+		cur, prev := &result[i], result[i+1]
+		if prev.Year != cur.Year-1 {
+			continue
+		}
+		cur.HasPrevious = true
+		cur.DistanceDelta = percentChange(cur.Distance, prev.Distance)
+		cur.TimeDelta = percentChange(float64(cur.MovingTime), float64(prev.MovingTime))
+		cur.RunsDelta = percentChange(float64(cur.RunCount), float64(prev.RunCount))
+	}
+
+	return result, nil
+}
+
+// percentChange returns the change from prev to cur in percent, or zero when
+// there is nothing to compare against
+func percentChange(cur, prev float64) float64 {
+	if prev == 0 {
+		return 0
+	}
+	return (cur - prev) / prev * 100
+}
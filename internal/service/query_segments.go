@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+
+	"runner/internal/analysis"
+)
+
+// SegmentsShown is how many of the fastest efforts each segments board lists
+const SegmentsShown = 10
+
+// SegmentDisplay is one of the fastest efforts over a distance, from
+// anywhere within a run
+type SegmentDisplay struct {
+	Rank         int     // starting at 1
+	Time         string  // formatted duration "M:SS" or "H:MM:SS"
+	PacePerMile  float64 // seconds per mile
+	AvgHR        string  // formatted HR or "-"
+	Date         string  // formatted date
+	ActivityID   int64
+	ActivityName string
+	Unverified   bool // doubtful GPS, as on the PRs screen
+}
+
+// SegmentBoard ranks the fastest efforts over one best-effort distance
+type SegmentBoard struct {
+	Category       string
+	CategoryLabel  string // e.g., "1K", "1 Mile"
+	DistanceMeters float64
+	Segments       []SegmentDisplay
+}
+
+// GetFastestSegments ranks the fastest efforts over each best-effort
+// distance, shortest distance first. Every run contributes its best stretch,
+// so the boards show the depth behind each PR rather than the record alone.
+func (q *QueryService) GetFastestSegments() ([]SegmentBoard, error) {
+	var boards []SegmentBoard
+	for _, dist := range analysis.EffortDistances {
+		category := analysis.EffortCategories[dist]
+		records, err := q.store.GetTopEfforts(category, SegmentsShown)
+		if err != nil {
+			return nil, fmt.Errorf("getting fastest %s efforts: %w", category, err)
+		}
+
+		board := SegmentBoard{
+			Category:       category,
+			CategoryLabel:  formatCategoryLabel(category),
+			DistanceMeters: dist,
+		}
+		for i, r := range records {
+			segment := SegmentDisplay{
+				Rank:         i + 1,
+				Time:         formatDuration(r.DurationSeconds),
+				PacePerMile:  analysis.CalculatePacePerMile(r.DistanceMeters, r.DurationSeconds),
+				AvgHR:        "-",
+				Date:         r.StartDateLocal.Format("Jan 02, 2006"),
+				ActivityID:   r.ActivityID,
+				ActivityName: r.ActivityName,
+				Unverified:   r.Unverified,
+			}
+			if r.AvgHeartrate != nil {
+				segment.AvgHR = fmt.Sprintf("%.0f", *r.AvgHeartrate)
+			}
+			board.Segments = append(board.Segments, segment)
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import "runner/internal/store"
+
+// StreamPage is one page of an activity's raw stream points
+type StreamPage struct {
+	Points []store.StreamPoint
+	Offset int // index of the first point in the whole stream
+	Total  int // points in the whole stream
+}
+
+// GetStreamPage returns up to limit stream points starting at index offset.
+// An offset past the end returns the last page.
+func (q *QueryService) GetStreamPage(activityID int64, offset, limit int) (*StreamPage, error) {
+	total, err := q.store.GetStreamCount(activityID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = total
+	}
+	if offset > total-limit {
+		offset = total - limit
+	}
+	offset = max(offset, 0)
+
+	points, err := q.store.GetStreamPage(activityID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamPage{Points: points, Offset: offset, Total: total}, nil
+}
+
+// GetStreamRange returns the stream points recorded between from and to
+// seconds into the activity, inclusive
+func (q *QueryService) GetStreamRange(activityID int64, from, to int) ([]store.StreamPoint, error) {
+	streams, err := q.store.GetStreams(activityID)
+	if err != nil {
+		return nil, err
+	}
+	var points []store.StreamPoint
+	for _, p := range streams {
+		if p.TimeOffset >= from && p.TimeOffset <= to {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"math"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"runner/internal/config"
+	"runner/internal/store"
 )
 
 func TestFormatPace(t *testing.T) {
@@ -59,6 +64,291 @@ func TestFormatMinutes(t *testing.T) {
 	}
 }
 
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", []string{}},
+		{" , ,", []string{}},
+		{"race", []string{"race"}},
+		{"Race, new  shoes", []string{"new-shoes", "race"}},
+		{"sick,SICK, sick ", []string{"sick"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := ParseTags(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseTags(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTemperature(t *testing.T) {
+	tests := []struct {
+		input      string
+		fahrenheit bool
+		want       float64
+		wantNil    bool
+		wantErr    bool
+	}{
+		{input: "", wantNil: true},
+		{input: "  ", fahrenheit: true, wantNil: true},
+		{input: "28", want: 28},
+		{input: "28c", fahrenheit: true, want: 28},
+		{input: "82.4F", want: 28},
+		{input: "82.4 °F", want: 28},
+		{input: "82.4", fahrenheit: true, want: 28},
+		{input: "-5°C", want: -5},
+		{input: "warm", wantErr: true},
+		{input: "300", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTemperature(tt.input, tt.fahrenheit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTemperature(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("ParseTemperature(%q) = %v, want nil", tt.input, *got)
+				}
+				return
+			}
+			if got == nil || math.Abs(*got-tt.want) > 1e-9 {
+				t.Errorf("ParseTemperature(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseActivityRPE(t *testing.T) {
+	good, awful := 4, 1
+	tests := []struct {
+		input   string
+		want    *store.ActivityRPE
+		wantErr bool
+	}{
+		{input: " "},
+		{input: "7", want: &store.ActivityRPE{RPE: 7}},
+		{input: "7/4", want: &store.ActivityRPE{RPE: 7, Feel: &good}},
+		{input: " 7 / Good", want: &store.ActivityRPE{RPE: 7, Feel: &good}},
+		{input: "9/awful", want: &store.ActivityRPE{RPE: 9, Feel: &awful}},
+		{input: "11", wantErr: true},
+		{input: "7/6", wantErr: true},
+		{input: "7/meh", wantErr: true},
+		{input: "hard", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseActivityRPE(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseActivityRPE(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseActivityRPE(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInjuryDate(t *testing.T) {
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	if got, err := ParseInjuryDate(" ", today); err != nil || !got.Equal(today) {
+		t.Errorf("ParseInjuryDate(blank) = %v, %v; want today", got, err)
+	}
+	want := time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)
+	if got, err := ParseInjuryDate("2024-02-28", today); err != nil || !got.Equal(want) {
+		t.Errorf("ParseInjuryDate() = %v, %v; want %v", got, err, want)
+	}
+	for _, input := range []string{"2024-03-11", "28/02/2024", "yesterday"} {
+		if _, err := ParseInjuryDate(input, today); err == nil {
+			t.Errorf("ParseInjuryDate(%q) expected an error", input)
+		}
+	}
+}
+
+func TestParseComparisonRange(t *testing.T) {
+	today := time.Date(2024, 6, 15, 18, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		input string
+		start time.Time
+		end   time.Time
+		label string
+	}{
+		{"last 6w", day(2024, 5, 5), day(2024, 6, 16), "Last 6 weeks"},
+		{" Last  10d ", day(2024, 6, 6), day(2024, 6, 16), "Last 10 days"},
+		{"last 1w", day(2024, 6, 9), day(2024, 6, 16), "Last 1 week"},
+		{"6w before 2024-05-10", day(2024, 3, 29), day(2024, 5, 10), "Mar 29-May 9"},
+		{"2023-06", day(2023, 6, 1), day(2023, 7, 1), "Jun 2023"},
+		{"2024", day(2024, 1, 1), day(2025, 1, 1), "2024"},
+		{"2023-12-20..2024-01-05", day(2023, 12, 20), day(2024, 1, 6), "Dec 20 2023-Jan 5 2024"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseComparisonRange(tt.input, today)
+			if err != nil {
+				t.Fatalf("ParseComparisonRange(%q) error: %v", tt.input, err)
+			}
+			if !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) || got.Label != tt.label {
+				t.Errorf("ParseComparisonRange(%q) = %v..%v %q, want %v..%v %q",
+					tt.input, got.Start, got.End, got.Label, tt.start, tt.end, tt.label)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "last 0w", "2024-05-10..2024-05-01", "2024-13", "since spring"} {
+		if _, err := ParseComparisonRange(input, today); err == nil {
+			t.Errorf("ParseComparisonRange(%q) expected an error", input)
+		}
+	}
+}
+
+func TestPreviousRange(t *testing.T) {
+	r, err := ParseComparisonRange("last 6w", time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := PreviousRange(r)
+	if !prev.End.Equal(r.Start) || prev.Days() != 42 {
+		t.Errorf("PreviousRange() = %v..%v (%d days), want 42 days ending %v", prev.Start, prev.End, prev.Days(), r.Start)
+	}
+	if prev.Label != "Mar 24-May 4" {
+		t.Errorf("PreviousRange() label = %q, want %q", prev.Label, "Mar 24-May 4")
+	}
+}
+
+func TestParseInjurySeverity(t *testing.T) {
+	if got, err := ParseInjurySeverity(" 3 "); err != nil || got != 3 {
+		t.Errorf("ParseInjurySeverity() = %d, %v; want 3", got, err)
+	}
+	for _, input := range []string{"", "0", "6", "bad"} {
+		if _, err := ParseInjurySeverity(input); err == nil {
+			t.Errorf("ParseInjurySeverity(%q) expected an error", input)
+		}
+	}
+}
+
+func TestParsePlanInputs(t *testing.T) {
+	today := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	want := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+	if got, err := ParsePlanRaceDate(" 2024-06-02 ", today); err != nil || !got.Equal(want) {
+		t.Errorf("ParsePlanRaceDate() = %v, %v; want %v", got, err, want)
+	}
+	for _, input := range []string{"", "2024-03-10", "2024-02-01", "June 2"} {
+		if _, err := ParsePlanRaceDate(input, today); err == nil {
+			t.Errorf("ParsePlanRaceDate(%q) expected an error", input)
+		}
+	}
+
+	if got, err := ParsePlanDistance("50", false); err != nil || got != 50000 {
+		t.Errorf("ParsePlanDistance(km) = %v, %v; want 50000", got, err)
+	}
+	if got, err := ParsePlanDistance("40", true); err != nil || got != 40*MetersPerMile {
+		t.Errorf("ParsePlanDistance(mi) = %v, %v; want %v", got, err, 40*MetersPerMile)
+	}
+	if _, err := ParsePlanDistance("0", false); err == nil {
+		t.Error("ParsePlanDistance(0) expected an error")
+	}
+
+	if got, err := ParsePlanTaperWeeks(""); err != nil || got != DefaultTaperWeeks {
+		t.Errorf("ParsePlanTaperWeeks(blank) = %d, %v; want %d", got, err, DefaultTaperWeeks)
+	}
+	if _, err := ParsePlanTaperWeeks("0"); err == nil {
+		t.Error("ParsePlanTaperWeeks(0) expected an error")
+	}
+	if got, err := ParsePlanCutbackEvery("0"); err != nil || got != 0 {
+		t.Errorf("ParsePlanCutbackEvery(0) = %d, %v; want 0 for none", got, err)
+	}
+	if _, err := ParsePlanCutbackEvery("1"); err == nil {
+		t.Error("ParsePlanCutbackEvery(1) expected an error")
+	}
+}
+
+func TestParseBenchmarkKind(t *testing.T) {
+	for input, want := range map[string]store.BenchmarkKind{
+		"":          store.BenchmarkRoute,
+		"r":         store.BenchmarkRoute,
+		" Duration": store.BenchmarkDuration,
+		"d":         store.BenchmarkDuration,
+	} {
+		if got, err := ParseBenchmarkKind(input); err != nil || got != want {
+			t.Errorf("ParseBenchmarkKind(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseBenchmarkKind("loop"); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
+
+func TestWellnessFieldParse(t *testing.T) {
+	if v, err := WellnessSleep.Parse(" 7.5 "); err != nil || v == nil || *v != 7.5 {
+		t.Errorf("WellnessSleep.Parse() = %v, %v; want 7.5", v, err)
+	}
+	if v, err := WellnessHRV.Parse(""); err != nil || v != nil {
+		t.Errorf("WellnessHRV.Parse(blank) = %v, %v; want nil", v, err)
+	}
+	for _, tt := range []struct {
+		field WellnessField
+		input string
+	}{
+		{WellnessRestingHR, "fast"},
+		{WellnessRestingHR, "200"},
+		{WellnessSleep, "-1"},
+		{WellnessSoreness, "2.5"},
+		{WellnessSoreness, "6"},
+	} {
+		if _, err := tt.field.Parse(tt.input); err == nil {
+			t.Errorf("%s.Parse(%q) expected an error", tt.field.Column, tt.input)
+		}
+	}
+}
+
+func TestParseWellnessCSV(t *testing.T) {
+	input := `Date,RHR,Sleep,Notes,Soreness
+2024-03-01,48,7.5,felt good,2
+2024-03-02,,6,,
+
+2024-03-03,51,,late night,4
+`
+	entries, err := ParseWellnessCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseWellnessCSV failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	first := entries[0]
+	if first.Date.Format("2006-01-02") != "2024-03-01" || *first.RestingHR != 48 ||
+		*first.SleepHours != 7.5 || *first.Soreness != 2 || first.HRV != nil {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if entries[1].RestingHR != nil || *entries[1].SleepHours != 6 || entries[1].Soreness != nil {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+
+	for name, bad := range map[string]string{
+		"no date":      "rhr,hrv\n48,60\n",
+		"no values":    "date,notes\n2024-03-01,x\n",
+		"bad date":     "date,rhr\n03/01/2024,48\n",
+		"out of range": "date,hrv\n2024-03-01,900\n",
+	} {
+		if _, err := ParseWellnessCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
 func TestNewQueryService(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -116,6 +406,70 @@ func TestHRZoneTimeStructure(t *testing.T) {
 	}
 }
 
+func TestCalculateCadenceBands(t *testing.T) {
+	point := func(cadence int, velocity float64) store.StreamPoint {
+		return store.StreamPoint{Cadence: &cadence, VelocitySmooth: &velocity}
+	}
+
+	streams := []store.StreamPoint{
+		point(78, 3.0), // 156 spm
+		point(82, 3.0), // 164 spm
+		point(85, 3.0), // 170 spm, lower bound of the 170-180 band
+		point(88, 3.0), // 176 spm
+		point(92, 3.0), // 184 spm
+		point(40, 0),   // standing, ignored
+		{VelocitySmooth: floatPtr(3.0)},
+	}
+
+	bands := calculateCadenceBands(streams)
+	if len(bands) != 4 {
+		t.Fatalf("expected 4 bands, got %d", len(bands))
+	}
+
+	wantSeconds := []int{1, 1, 2, 1}
+	for i, want := range wantSeconds {
+		if bands[i].Seconds != want {
+			t.Errorf("band %s: Seconds = %d, want %d", bands[i].Name, bands[i].Seconds, want)
+		}
+	}
+	if bands[2].Percent != 40 {
+		t.Errorf("170-180 band Percent = %.1f, want 40", bands[2].Percent)
+	}
+
+	if got := calculateCadenceBands([]store.StreamPoint{{VelocitySmooth: floatPtr(3.0)}}); got != nil {
+		t.Errorf("expected nil bands without cadence, got %v", got)
+	}
+}
+
+func TestCalculateSplits(t *testing.T) {
+	// 2.6 km at a steady 4 m/s (4:10/km), one point per second
+	var streams []store.StreamPoint
+	for i := 0; i <= 650; i++ {
+		streams = append(streams, store.StreamPoint{TimeOffset: i, Distance: floatPtr(float64(i) * 4)})
+	}
+
+	d := &ActivityDetail{}
+	splits := d.calculateSplits(streams, 2600, MetersPerKm, PartialKmThreshold)
+	if len(splits) != 3 {
+		t.Fatalf("expected 3 km splits, got %d", len(splits))
+	}
+	for i, s := range splits {
+		if s.Mile != i+1 || s.Duration != 250 || s.Pace != "4:10" {
+			t.Errorf("split %d = %+v, want 4:10", i+1, s)
+		}
+	}
+
+	// 2.6 km is 1 full mile plus a 0.62 mi partial
+	if miles := d.calculateSplits(streams, 2600, MetersPerMile, PartialMileThreshold); len(miles) != 2 {
+		t.Errorf("expected 2 mile splits, got %d", len(miles))
+	}
+
+	// A final 50 m is too short to count as a split
+	if short := d.calculateSplits(streams[:264], 1050, MetersPerKm, PartialKmThreshold); len(short) != 1 {
+		t.Errorf("expected the short partial km to be dropped, got %d splits", len(short))
+	}
+}
+
 func TestMileSplitStructure(t *testing.T) {
 	// Test that MileSplit struct can be properly used
 	split := MileSplit{
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"time"
+
+	"runner/internal/analysis"
+)
+
+// TrendHorizons are the months of history the trends screen can show
+var TrendHorizons = []int{6, 12, 24}
+
+// MonthTrend is one month of the long-horizon trends. Values are zero for
+// months without data.
+type MonthTrend struct {
+	Month    time.Time
+	RunCount int
+	Miles    float64
+	EF       float64 // mean of the month's runs with one
+	CTL      float64 // on the month's last day, or the latest day this month
+	VDOT     float64 // best implied by an effort of TrendMinEffortSecs or longer
+	Cadence  float64 // time-weighted spm
+	Score    float64 // mean performance score of the month's runs with one
+}
+
+// GetTrends returns the last months calendar months, oldest first and
+// ending with the current one. Every value comes from a table kept up to
+// date by sync: monthly totals from the cached stream stats, CTL from the
+// daily fitness trends, VDOT from the cached pace-curve efforts and
+// performance scores from the activities themselves.
+func (q *QueryService) GetTrends(months int) ([]MonthTrend, error) {
+	stats, err := q.GetPeriodStats("monthly", months)
+	if err != nil {
+		return nil, err
+	}
+	first := stats[0].PeriodStart
+
+	trends := make([]MonthTrend, len(stats))
+	for i, s := range stats {
+		trends[i] = MonthTrend{
+			Month:    s.PeriodStart,
+			RunCount: s.RunCount,
+			Miles:    s.TotalMiles,
+			EF:       s.AvgEF,
+			Cadence:  s.AvgSPM,
+		}
+	}
+
+	// Days come in order, so each month ends up with its last day's CTL
+	days, err := q.store.GetFitnessTrends(first)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range days {
+		date, err := time.Parse(fitnessTrendDateFormat, d.Date)
+		if err != nil || d.CTL == nil {
+			continue
+		}
+		if i := monthIndex(date, first); i >= 0 && i < len(trends) {
+			trends[i].CTL = *d.CTL
+		}
+	}
+
+	// A day early, since efforts are filtered by their UTC start
+	efforts, err := q.store.GetDurationEfforts(first.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range efforts {
+		if e.DurationSeconds < TrendMinEffortSecs {
+			continue
+		}
+		i := monthIndex(q.store.BucketClock(e.StartDate), first)
+		if i < 0 || i >= len(trends) {
+			continue
+		}
+		if vdot := analysis.CalculateVDOT(e.DistanceMeters, e.DurationSeconds); vdot > trends[i].VDOT {
+			trends[i].VDOT = vdot
+		}
+	}
+
+	if err := q.fillPerformanceScores(trends, first); err != nil {
+		return nil, err
+	}
+
+	return trends, nil
+}
+
+// fillPerformanceScores averages the performance scores of each month's runs
+func (q *QueryService) fillPerformanceScores(trends []MonthTrend, first time.Time) error {
+	end := trends[len(trends)-1].Month.AddDate(0, 1, 0)
+	activities, _, err := q.store.GetActivitiesWithMetricsBetween(first, end)
+	if err != nil {
+		return err
+	}
+	temps, err := q.store.GetActivityTemperatures()
+	if err != nil {
+		return err
+	}
+
+	sums := make([]float64, len(trends))
+	counts := make([]int, len(trends))
+	for _, a := range activities {
+		var tempC *float64
+		if t, ok := temps[a.ID]; ok {
+			tempC = &t
+		}
+		p := q.performance(a, tempC)
+		i := monthIndex(q.store.BucketTime(a), first)
+		if p == nil || i < 0 || i >= len(trends) {
+			continue
+		}
+		sums[i] += p.Score
+		counts[i]++
+	}
+	for i := range trends {
+		if counts[i] > 0 {
+			trends[i].Score = sums[i] / float64(counts[i])
+		}
+	}
+	return nil
+}
+
+// monthIndex counts the calendar months from the month of first to the
+// month of t, negative when t is earlier
+func monthIndex(t, first time.Time) int {
+	return (t.Year()-first.Year())*12 + int(t.Month()) - int(first.Month())
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// VO2maxEstimate is VO2max estimated from the HR-vs-pace relationship of
+// easy runs, next to the VDOT the athlete's efforts imply
+type VO2maxEstimate struct {
+	// WeightSet is false until the athlete's weight is configured, and the
+	// estimate isn't made without it
+	WeightSet bool
+
+	// OK is set when at least VO2maxMinRuns runs of the last
+	// VO2maxWindowDays qualified
+	OK       bool
+	Runs     int
+	Current  float64 // ml/kg/min, median of the qualifying runs
+	Absolute float64 // L/min at the configured weight
+
+	// Trend fits the weekly median estimate
+	Trend TrendProjection
+
+	// VDOT is the current fitted VDOT, zero when there isn't one
+	VDOT float64
+}
+
+// buildVO2maxEstimate estimates VO2max from steady, mostly flat runs. Each
+// run gives its own estimate (see analysis.EstimateVO2max) and the median
+// damps the effect of heat, fatigue and wind on any one of them.
+func (q *QueryService) buildVO2maxEstimate(activities []store.Activity, metrics []store.ActivityMetrics, vdot TrendProjection) VO2maxEstimate {
+	est := VO2maxEstimate{WeightSet: q.athleteCfg.WeightKg > 0}
+	if !est.WeightSet {
+		return est
+	}
+	if vdot.OK {
+		est.VDOT = vdot.Current
+	}
+
+	thisWeek := q.weekStart(q.bucketNow())
+	windowStart := time.Now().AddDate(0, 0, -VO2maxWindowDays)
+	var recent []float64
+	weekly := make(map[int][]float64)
+
+	for i, a := range activities {
+		if a.Excluded || len(metrics[i].AnomalyFlags) > 0 || a.AverageHeartrate == nil {
+			continue
+		}
+		if a.MovingTime < VO2maxMinRunSecs || a.Distance <= 0 {
+			continue
+		}
+		if a.TotalElevationGain/(a.Distance/MetersPerKm) > VO2maxMaxClimbPerKm {
+			continue
+		}
+
+		speed := a.Distance / float64(a.MovingTime)
+		vo2max, ok := analysis.EstimateVO2max(speed, *a.AverageHeartrate, q.athleteCfg.RestingHR, q.athleteCfg.MaxHR)
+		if !ok {
+			continue
+		}
+		if a.StartDate.After(windowStart) {
+			recent = append(recent, vo2max)
+		}
+		if w := trendWeekIndex(q.store.BucketTime(a), thisWeek); w >= 0 {
+			weekly[w] = append(weekly[w], vo2max)
+		}
+	}
+
+	medians := make(map[int]float64, len(weekly))
+	for w, values := range weekly {
+		medians[w] = median(values)
+	}
+	est.Trend = projectWeekly(medians)
+
+	est.Runs = len(recent)
+	if est.Runs >= VO2maxMinRuns {
+		est.OK = true
+		est.Current = median(recent)
+		est.Absolute = est.Current * q.athleteCfg.WeightKg / 1000
+	}
+	return est
+}
+
+// median returns the middle value, sorting values in place
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
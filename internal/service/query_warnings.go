@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"runner/internal/analysis"
+)
+
+// Kinds of training warning
+const (
+	WarningACWR     = "acwr"     // acute load far above chronic load
+	WarningMonotony = "monotony" // every day the same load
+	WarningRest     = "rest"     // too long without a rest day
+	WarningRamp     = "ramp"     // this week's distance past the ramp threshold
+)
+
+// TrainingWarning is a sign of overdoing it, as shown on the dashboard
+type TrainingWarning struct {
+	Kind    string
+	Message string
+}
+
+// GetTrainingWarnings returns the warnings that apply now: an acute:chronic
+// load ratio above ACWRWarning once there are ACWRWarmupDays of history,
+// monotony above analysis.MonotonyWarning, no rest day for longer than
+// the configured limit, and a week already ramped past the threshold
+func (q *QueryService) GetTrainingWarnings(now time.Time) ([]TrainingWarning, error) {
+	var warnings []TrainingWarning
+
+	trend, err := q.store.GetLatestFitnessTrend()
+	if err != nil {
+		return nil, err
+	}
+	if trend != nil {
+		loads, err := q.store.ListTrainingLoads()
+		if err != nil {
+			return nil, err
+		}
+		warmedUp := len(loads) > 0 && now.Sub(loads[0].StartDate) >= ACWRWarmupDays*24*time.Hour
+		if warmedUp && trend.ATL != nil && trend.CTL != nil && *trend.CTL > 0 {
+			if acwr := *trend.ATL / *trend.CTL; acwr > ACWRWarning {
+				warnings = append(warnings, TrainingWarning{
+					Kind:    WarningACWR,
+					Message: fmt.Sprintf("Acute:chronic load %.2f, above %.1f: ease off to avoid injury", acwr, ACWRWarning),
+				})
+			}
+		}
+		if trend.Monotony7d != nil && *trend.Monotony7d > analysis.MonotonyWarning {
+			warnings = append(warnings, TrainingWarning{
+				Kind:    WarningMonotony,
+				Message: fmt.Sprintf("Monotony %.1f, above %.1f: vary your days", *trend.Monotony7d, analysis.MonotonyWarning),
+			})
+		}
+	}
+
+	if q.restDayWarning > 0 {
+		runDays, err := q.store.ListRunDays()
+		if err != nil {
+			return nil, err
+		}
+		streaks := analysis.CalculateStreaks(runDays, calendarDay(q.store.BucketClock(now)))
+		if streaks.DaysSinceRest > q.restDayWarning {
+			warnings = append(warnings, TrainingWarning{
+				Kind:    WarningRest,
+				Message: fmt.Sprintf("No rest day in %d days", streaks.DaysSinceRest),
+			})
+		}
+	}
+
+	summaries, err := q.getWeeklySummaries(2)
+	if err != nil {
+		return nil, err
+	}
+	if week := q.weekRamps(summaries)[0]; week.Over {
+		warnings = append(warnings, TrainingWarning{
+			Kind:    WarningRamp,
+			Message: fmt.Sprintf("Distance this week already up %.0f%% on last week, above %.0f%%: hold back", week.RampPct, q.rampThreshold),
+		})
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// WellnessField is one value on a daily wellness entry
+type WellnessField struct {
+	Column   string // CSV column name
+	Label    string
+	Min, Max float64
+	Integer  bool
+}
+
+// The fields of a wellness entry, in form order
+var (
+	WellnessRestingHR = WellnessField{Column: "resting_hr", Label: "Resting HR (bpm)", Min: 25, Max: 120}
+	WellnessHRV       = WellnessField{Column: "hrv", Label: "HRV (ms)", Min: 5, Max: 300}
+	WellnessSleep     = WellnessField{Column: "sleep_hours", Label: "Sleep (hours)", Min: 0, Max: 24}
+	WellnessSoreness  = WellnessField{Column: "soreness", Label: "Soreness (1 none - 5 very)", Min: 1, Max: 5, Integer: true}
+)
+
+// Parse parses a value for the field. Blank means not recorded and gives nil.
+func (f WellnessField) Parse(input string) (*float64, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || (f.Integer && v != math.Trunc(v)) {
+		return nil, fmt.Errorf("invalid %s %q", f.Column, input)
+	}
+	if v < f.Min || v > f.Max {
+		return nil, fmt.Errorf("%s %q is out of range (%g-%g)", f.Column, input, f.Min, f.Max)
+	}
+	return &v, nil
+}
+
+// set stores a parsed value in the field's place on an entry
+func (f WellnessField) set(w *store.Wellness, v *float64) {
+	switch f.Column {
+	case WellnessRestingHR.Column:
+		w.RestingHR = v
+	case WellnessHRV.Column:
+		w.HRV = v
+	case WellnessSleep.Column:
+		w.SleepHours = v
+	case WellnessSoreness.Column:
+		w.Soreness = nil
+		if v != nil {
+			n := int(*v)
+			w.Soreness = &n
+		}
+	}
+}
+
+// wellnessColumns maps the CSV headers other apps use to each field
+var wellnessColumns = map[string]WellnessField{
+	"resting_hr":  WellnessRestingHR,
+	"rhr":         WellnessRestingHR,
+	"resting hr":  WellnessRestingHR,
+	"hrv":         WellnessHRV,
+	"sleep_hours": WellnessSleep,
+	"sleep":       WellnessSleep,
+	"sleep hours": WellnessSleep,
+	"soreness":    WellnessSoreness,
+}
+
+// ParseWellnessCSV reads wellness entries from CSV with a header row. It
+// needs a date column (YYYY-MM-DD) and takes whichever of resting_hr, hrv,
+// sleep_hours and soreness are present; other columns are ignored.
+func ParseWellnessCSV(r io.Reader) ([]store.Wellness, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dateCol := -1
+	fields := make(map[int]WellnessField)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "date" {
+			dateCol = i
+		} else if f, ok := wellnessColumns[name]; ok {
+			fields[i] = f
+		}
+	}
+	if dateCol < 0 {
+		return nil, errors.New("CSV has no date column")
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("CSV has no resting_hr, hrv, sleep_hours or soreness column")
+	}
+
+	var entries []store.Wellness
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if dateCol >= len(record) || strings.TrimSpace(record[dateCol]) == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[dateCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q, use YYYY-MM-DD", line, record[dateCol])
+		}
+
+		w := store.Wellness{Date: date}
+		for i, f := range fields {
+			if i >= len(record) {
+				continue
+			}
+			v, err := f.Parse(record[i])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			f.set(&w, v)
+		}
+		entries = append(entries, w)
+	}
+	return entries, nil
+}
+
+// SaveWellness saves wellness entries, replacing any already logged for the
+// same days
+func (q *QueryService) SaveWellness(entries ...store.Wellness) error {
+	return q.store.SaveWellness(entries)
+}
+
+// GetWellness returns the wellness entry logged for a calendar day, or nil
+func (q *QueryService) GetWellness(day time.Time) (*store.Wellness, error) {
+	return q.store.GetWellness(calendarDay(day))
+}
+
+// ReadinessData is today's wellness and the readiness score it gives
+type ReadinessData struct {
+	Today             *store.Wellness // nil until logged
+	BaselineRestingHR *float64
+	BaselineHRV       *float64
+	analysis.Readiness
+	OK bool
+}
+
+// buildReadiness scores today's readiness from its wellness entry, the
+// baseline of the days before, and training form
+func (q *QueryService) buildReadiness(today time.Time, tsb *float64) (ReadinessData, error) {
+	entries, err := q.store.ListWellnessSince(today.AddDate(0, 0, -ReadinessBaselineDays))
+	if err != nil {
+		return ReadinessData{}, err
+	}
+
+	var data ReadinessData
+	var rhr, hrv []float64
+	for i, w := range entries {
+		if w.Date.Equal(today) {
+			data.Today = &entries[i]
+			continue
+		}
+		if w.RestingHR != nil {
+			rhr = append(rhr, *w.RestingHR)
+		}
+		if w.HRV != nil {
+			hrv = append(hrv, *w.HRV)
+		}
+	}
+	data.BaselineRestingHR = wellnessBaseline(rhr)
+	data.BaselineHRV = wellnessBaseline(hrv)
+	if data.Today == nil {
+		return data, nil
+	}
+
+	data.Readiness, data.OK = analysis.CalculateReadiness(analysis.ReadinessInput{
+		RestingHR:         data.Today.RestingHR,
+		BaselineRestingHR: data.BaselineRestingHR,
+		HRV:               data.Today.HRV,
+		BaselineHRV:       data.BaselineHRV,
+		SleepHours:        data.Today.SleepHours,
+		Soreness:          data.Today.Soreness,
+		TSB:               tsb,
+	})
+	return data, nil
+}
+
+// wellnessBaseline averages values once there are enough to trust
+func wellnessBaseline(values []float64) *float64 {
+	if len(values) < ReadinessMinBaseline {
+		return nil
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	return &mean
+}
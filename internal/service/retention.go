@@ -0,0 +1,18 @@
+package service
+
+import "errors"
+
+// PurgeStreams deletes the streams of runs that started more than years
+// ago and returns how many runs lost them. Everything computed from the
+// streams stays. Stream stats are cached first so weekly summaries keep
+// their moving time, heart rate and cadence.
+func (q *QueryService) PurgeStreams(years int) (int, error) {
+	if years < 1 {
+		return 0, errors.New("streams must be kept for at least 1 year")
+	}
+	if err := q.backfillStreamStats(); err != nil {
+		return 0, err
+	}
+	cutoff := calendarDay(q.bucketNow()).AddDate(-years, 0, 0)
+	return q.store.PurgeStreams(cutoff)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"runner/internal/store"
+)
+
+// splitUnits are the units splits are stored in, with the shortest final
+// partial split worth keeping
+var splitUnits = []struct {
+	units   string
+	meters  float64
+	partial float64
+}{
+	{store.SplitsStandard, MetersPerMile, PartialMileThreshold},
+	{store.SplitsMetric, MetersPerKm, PartialKmThreshold},
+}
+
+// activitySplits computes a run's mile and kilometer splits for storage
+func activitySplits(activity store.Activity, streams []store.StreamPoint) []store.ActivitySplit {
+	var splits []store.ActivitySplit
+	for _, u := range splitUnits {
+		splits = append(splits, computeSplits(activity.ID, u.units, streams, activity.Distance, u.meters, u.partial)...)
+	}
+	return splits
+}
+
+// computeSplits divides the run into splits of splitMeters each. A final
+// partial split is kept when it covers more than partialThreshold meters.
+func computeSplits(activityID int64, units string, streams []store.StreamPoint, totalDistance, splitMeters, partialThreshold float64) []store.ActivitySplit {
+	var splits []store.ActivitySplit
+	current := 1
+	startIdx := 0
+	var lastDistance float64
+
+	for i, p := range streams {
+		if p.Distance == nil {
+			continue
+		}
+
+		dist := *p.Distance
+		threshold := float64(current) * splitMeters
+
+		if dist >= threshold && lastDistance < threshold {
+			// Completed a split
+			split := computeSplit(streams, startIdx, i)
+			split.Split, split.Distance = current, splitMeters
+			splits = append(splits, split)
+			current++
+			startIdx = i
+		}
+		lastDistance = dist
+	}
+
+	// Add final partial split if significant
+	remainingDist := totalDistance - float64(current-1)*splitMeters
+	if remainingDist > partialThreshold && startIdx < len(streams)-1 {
+		split := computeSplit(streams, startIdx, len(streams)-1)
+		split.Split, split.Distance, split.Partial = current, remainingDist, true
+		splits = append(splits, split)
+	}
+
+	for i := range splits {
+		splits[i].ActivityID, splits[i].Units = activityID, units
+	}
+	return splits
+}
+
+// computeSplit summarizes streams[startIdx:endIdx+1]
+func computeSplit(streams []store.StreamPoint, startIdx, endIdx int) store.ActivitySplit {
+	var split store.ActivitySplit
+	if endIdx <= startIdx || endIdx >= len(streams) {
+		return split
+	}
+
+	split.StartOffset = streams[startIdx].TimeOffset
+	split.Duration = streams[endIdx].TimeOffset - split.StartOffset
+
+	stats := AggregateStreamStats(streams[startIdx : endIdx+1])
+	if stats.HRCount > 0 {
+		hr := stats.AvgHR()
+		split.AverageHeartrate = &hr
+	}
+	if stats.CadenceCount > 0 {
+		cad := stats.AvgCadence()
+		split.AverageCadence = &cad
+	}
+	return split
+}
+
+// mileSplits converts stored splits for display. A partial split's
+// duration is scaled up to a pace per full split.
+func mileSplits(splits []store.ActivitySplit, splitMeters float64) []MileSplit {
+	var out []MileSplit
+	for _, s := range splits {
+		split := MileSplit{Mile: s.Split, Duration: s.Duration}
+		if s.Partial {
+			split.Duration = int(float64(s.Duration) / (s.Distance / splitMeters))
+		}
+		split.Pace = formatPace(split.Duration)
+		if s.AverageHeartrate != nil {
+			split.AvgHR = *s.AverageHeartrate
+		}
+		if s.AverageCadence != nil {
+			split.AvgCad = *s.AverageCadence
+		}
+		out = append(out, split)
+	}
+	return out
+}
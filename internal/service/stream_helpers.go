@@ -11,6 +11,7 @@ import (
 type StreamStats struct {
 	HRSum         float64
 	HRCount       int
+	MaxHR         int
 	CadenceSum    float64
 	CadenceCount  int
 	MovingTime    int     // seconds of moving time (velocity > MinSpeedForPace)
@@ -24,6 +25,7 @@ func AggregateStreamStats(streams []store.StreamPoint) StreamStats {
 		if isValidHeartrate(p.Heartrate) {
 			stats.HRSum += float64(*p.Heartrate)
 			stats.HRCount++
+			stats.MaxHR = max(stats.MaxHR, *p.Heartrate)
 		}
 		if isValidCadence(p.Cadence) {
 			stats.CadenceSum += float64(*p.Cadence) * StravaCadenceMultiplier
@@ -75,11 +77,25 @@ func metersToMiles(meters float64) float64 {
 	return meters / MetersPerMile
 }
 
-// getMonday returns the Monday of the week containing t, at midnight
-func getMonday(t time.Time) time.Time {
-	daysFromMonday := (int(t.Weekday()) + 6) % 7 // Monday = 0
-	monday := t.AddDate(0, 0, -daysFromMonday)
-	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, monday.Location())
+// startOfWeek returns the first day of the week containing t, at midnight,
+// for weeks starting on first
+func startOfWeek(t time.Time, first time.Weekday) time.Time {
+	daysIntoWeek := (int(t.Weekday()) - int(first) + 7) % 7
+	start := t.AddDate(0, 0, -daysIntoWeek)
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+}
+
+// isoWeekLabel names the week starting at start by its ISO week number. A
+// week starting Sunday takes the number of the Monday to Saturday it holds.
+func isoWeekLabel(start time.Time) string {
+	_, week := start.AddDate(0, 0, 3).ISOWeek()
+	return fmt.Sprintf("W%02d", week)
+}
+
+// calendarDay returns the local calendar day of t as midnight UTC, the form
+// run days are stored in
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
 
 // formatDuration formats seconds as "H:MM:SS" or "M:SS"
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"log/slog"
+
+	"runner/internal/config"
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// SetStorageConfig chooses the resolution streams are downloaded at and the
+// most points stored per activity. It must not be called while a sync is
+// running; streams already stored keep their points.
+func (s *SyncService) SetStorageConfig(storageCfg config.StorageConfig) {
+	s.streamResolution = storageCfg.StreamResolution
+	s.maxStreamPoints = storageCfg.MaxStreamPoints
+}
+
+// resampledResolutions are Strava's resampled stream resolutions, finest
+// first
+var resampledResolutions = []strava.Resolution{strava.ResolutionHigh, strava.ResolutionMedium, strava.ResolutionLow}
+
+// resolutionFor picks the resolution to download an activity's streams at.
+// Automatically that's every point, unless the activity is long enough at a
+// point a second to need capping. Then it's the finest resampled resolution
+// whose points fit under the cap, which saves downloading points only to
+// throw them away.
+func (s *SyncService) resolutionFor(activity store.Activity) strava.Resolution {
+	switch s.streamResolution {
+	case config.StreamResolutionFull:
+		return strava.ResolutionFull
+	case config.StreamResolutionHigh:
+		return strava.ResolutionHigh
+	case config.StreamResolutionMedium:
+		return strava.ResolutionMedium
+	case config.StreamResolutionLow:
+		return strava.ResolutionLow
+	}
+	if s.maxStreamPoints <= 0 || activity.ElapsedTime <= s.maxStreamPoints {
+		return strava.ResolutionFull
+	}
+	for _, r := range resampledResolutions {
+		if r.Points() <= s.maxStreamPoints {
+			return r
+		}
+	}
+	return strava.ResolutionLow
+}
+
+// capStreamPoints downsamples points to the configured cap, keeping evenly
+// spaced points including the first and last. Every value is cumulative or
+// instantaneous, so dropping points keeps totals and averages close.
+func (s *SyncService) capStreamPoints(activityID int64, points []store.StreamPoint) []store.StreamPoint {
+	if s.maxStreamPoints <= 0 || len(points) <= s.maxStreamPoints {
+		return points
+	}
+	slog.Info("streams downsampled", "activity_id", activityID, "points", len(points), "kept", s.maxStreamPoints)
+	return downsamplePoints(points, s.maxStreamPoints)
+}
+
+// downsamplePoints returns n evenly spaced points from points, n >= 2
+func downsamplePoints(points []store.StreamPoint, n int) []store.StreamPoint {
+	if n >= len(points) {
+		return points
+	}
+	n = max(n, 2)
+	kept := make([]store.StreamPoint, n)
+	last := len(points) - 1
+	for i := range kept {
+		kept[i] = points[i*last/(n-1)]
+	}
+	return kept
+}
@@ -2,7 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"runner/internal/analysis"
@@ -13,17 +20,88 @@ import (
 
 // SyncService orchestrates syncing data from Strava
 type SyncService struct {
-	client  *strava.Client
-	store   *store.Store
-	hrZones analysis.HRZones
+	client         *strava.Client
+	store          *store.Store
+	hrZones        analysis.HRZones
+	excludeFlagged bool
+	smoothGPS      bool
+	stravaEfforts  bool
+	syncSocial     bool
+	syncPhotos     bool
+	customMetrics  []analysis.MetricComputer
+
+	// How streams are downloaded and capped; see SetStorageConfig
+	streamResolution string
+	maxStreamPoints  int
+
+	// resume is non-nil while the sync is paused and closed to resume it
+	mu     sync.Mutex
+	resume chan struct{}
+
+	// onSyncDone is called after each sync; see SetOnSyncDone
+	onSyncDone func(ctx context.Context, result *SyncResult, err error)
 }
 
-// NewSyncService creates a new sync service with athlete config for HR calculations
-func NewSyncService(client *strava.Client, store *store.Store, athleteCfg config.AthleteConfig) *SyncService {
+// NewSyncService creates a new sync service with athlete config for HR
+// calculations and analysis config for handling suspect data
+func NewSyncService(client *strava.Client, store *store.Store, athleteCfg config.AthleteConfig, analysisCfg config.AnalysisConfig) *SyncService {
 	return &SyncService{
-		client:  client,
-		store:   store,
-		hrZones: analysis.NewHRZones(athleteCfg.RestingHR, athleteCfg.MaxHR, athleteCfg.ThresholdHR),
+		client:         client,
+		store:          store,
+		hrZones:        athleteZones(athleteCfg),
+		excludeFlagged: analysisCfg.ExcludeFlagged,
+		smoothGPS:      !analysisCfg.DisableSmoothing,
+		stravaEfforts:  analysisCfg.StravaBestEfforts(),
+		customMetrics:  customMetricComputers(analysisCfg.CustomMetrics),
+	}
+}
+
+// SetAthleteConfig replaces the HR values metrics are computed with. It must
+// not be called while a sync is running; runs already analyzed only change
+// when metrics are recomputed.
+func (s *SyncService) SetAthleteConfig(athleteCfg config.AthleteConfig) {
+	s.hrZones = athleteZones(athleteCfg)
+}
+
+// athleteZones returns the HR values, TRIMP coefficient and pace anchors
+// metrics are computed with
+func athleteZones(athleteCfg config.AthleteConfig) analysis.HRZones {
+	zones := analysis.NewHRZones(athleteCfg.RestingHR, athleteCfg.MaxHR, athleteCfg.ThresholdHR)
+	zones.PaceAnchors = athleteCfg.PaceAnchors
+	if athleteCfg.Sex == config.SexFemale {
+		zones.Banister = analysis.BanisterFemale
+	}
+	return zones
+}
+
+// SetAnalysisConfig replaces the switches for suspect data and smoothing
+// and the custom metrics. It must not be called while a sync is running.
+func (s *SyncService) SetAnalysisConfig(analysisCfg config.AnalysisConfig) {
+	s.excludeFlagged = analysisCfg.ExcludeFlagged
+	s.smoothGPS = !analysisCfg.DisableSmoothing
+	s.stravaEfforts = analysisCfg.StravaBestEfforts()
+	s.customMetrics = customMetricComputers(analysisCfg.CustomMetrics)
+}
+
+// SetPrivacyConfig chooses whether kudos, comment and photo counts and
+// photo URLs are stored. It must not be called while a sync is running.
+func (s *SyncService) SetPrivacyConfig(privacyCfg config.PrivacyConfig) {
+	s.syncSocial = privacyCfg.SyncSocial
+	s.syncPhotos = privacyCfg.SyncSocial && privacyCfg.SyncPhotos
+}
+
+// SetOnSyncDone registers fn to be called after every sync and retry that
+// took the sync lock, whoever started it, with its result or error. It runs
+// once the lock is released and before Sync returns. It must not be called
+// while a sync is running.
+func (s *SyncService) SetOnSyncDone(fn func(ctx context.Context, result *SyncResult, err error)) {
+	s.onSyncDone = fn
+}
+
+// syncDone calls the SetOnSyncDone callback, if any
+func (s *SyncService) syncDone(ctx context.Context, result *SyncResult, err error) {
+	if s.onSyncDone != nil {
+		s.onSyncDone(ctx, result, err)
 	}
 }
 
@@ -33,11 +111,13 @@ type SyncProgress struct {
 	Total           int
 	Completed       int
 	CurrentActivity string
+	Worker          int // which worker has CurrentActivity in the metrics and records phases, from 1
 	Error           error
 }
 
 // reportError sends an error to the progress channel if available
 func reportError(progress chan<- SyncProgress, phase string, err error) {
+	slog.Warn("sync error", "phase", phase, "error", err.Error())
 	if progress != nil {
 		progress <- SyncProgress{
 			Phase: phase,
@@ -51,49 +131,440 @@ type SyncResult struct {
 	ActivitiesFetched    int
 	ActivitiesStored     int
 	StreamsFetched       int
+	DetailsFetched       int
 	MetricsComputed      int
 	PRsComputed          int
+	RecordsRepaired      int // stale categories recomputed from the remaining runs
+	RacesFound           int
 	PredictionsComputed  int
 	RunsWithHR           int
+	NewRecords           []NewRecord // records improved, for the summary
 	Errors               []error
+	Failures             []SyncFailure
+
+	// Where Sync spent its time, phase by phase, with totals over the
+	// phases. Retries and single-activity syncs leave these empty.
+	Phases      []PhaseTiming
+	Duration    time.Duration
+	Requests    int
+	BytesStored int64
+}
+
+// SyncFailure is one item that failed during a sync, kept so the failed
+// work can be retried. ActivityID is zero for failures that aren't tied to
+// an activity.
+type SyncFailure struct {
+	Phase        string
+	ActivityID   int64
+	ActivityName string
+	Err          error
+}
+
+// Retryable reports whether RetryFailed can redo the failed work
+func (f SyncFailure) Retryable() bool {
+	return f.ActivityID != 0 || f.Phase == "predictions"
+}
+
+// fail records an error in the result and reports it on the progress channel
+func (r *SyncResult) fail(progress chan<- SyncProgress, phase string, activityID int64, activityName string, err error) {
+	r.Errors = append(r.Errors, err)
+	r.Failures = append(r.Failures, SyncFailure{
+		Phase:        phase,
+		ActivityID:   activityID,
+		ActivityName: activityName,
+		Err:          err,
+	})
+	reportError(progress, phase, err)
+}
+
+// MissingScope reports whether Strava refused any request because the token
+// lacks a permission, which only reauthorizing fixes
+func (r *SyncResult) MissingScope() bool {
+	for _, err := range r.Errors {
+		if errors.Is(err, strava.ErrMissingScope) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncPhase is one step of a sync. The values double as the phase names on
+// progress updates and failures.
+type SyncPhase string
+
+const (
+	PhaseActivities      SyncPhase = "activities"
+	PhaseStreams         SyncPhase = "streams"
+	PhaseMetrics         SyncPhase = "metrics"
+	PhasePersonalRecords SyncPhase = "personal_records"
+	PhaseRaces           SyncPhase = "races"
+	PhasePredictions     SyncPhase = "predictions"
+)
+
+// AllPhases lists every phase in the order a sync runs them
+var AllPhases = []SyncPhase{PhaseActivities, PhaseStreams, PhaseMetrics, PhasePersonalRecords, PhaseRaces, PhasePredictions}
+
+// LocalPhases are the phases that work only on the local database
+var LocalPhases = []SyncPhase{PhaseMetrics, PhasePersonalRecords, PhaseRaces, PhasePredictions}
+
+// phaseAliases are short names ParseSyncPhases accepts besides the phase
+// names themselves
+var phaseAliases = map[string]SyncPhase{
+	"prs":     PhasePersonalRecords,
+	"records": PhasePersonalRecords,
+}
+
+// ParseSyncPhases parses a comma-separated list of phase names, such as
+// "metrics,prs". "local" selects the phases that need no API calls, "remote"
+// the ones that do, and "all" every phase. The result is in run order.
+func ParseSyncPhases(s string) ([]SyncPhase, error) {
+	selected := make(map[SyncPhase]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case "all":
+			for _, p := range AllPhases {
+				selected[p] = true
+			}
+			continue
+		case "local":
+			for _, p := range LocalPhases {
+				selected[p] = true
+			}
+			continue
+		case "remote":
+			selected[PhaseActivities] = true
+			selected[PhaseStreams] = true
+			continue
+		}
+		phase, ok := phaseAliases[name]
+		if !ok {
+			phase = SyncPhase(name)
+		}
+		if !slices.Contains(AllPhases, phase) {
+			return nil, fmt.Errorf("unknown sync phase %q (want activities, streams, metrics, prs, races or predictions)", name)
+		}
+		selected[phase] = true
+	}
+
+	var phases []SyncPhase
+	for _, p := range AllPhases {
+		if selected[p] {
+			phases = append(phases, p)
+		}
+	}
+	if len(phases) == 0 {
+		return nil, errors.New("no sync phases selected")
+	}
+	return phases, nil
+}
+
+// SyncOptions selects the work a sync does. The zero value is a full sync.
+type SyncOptions struct {
+	// Phases to run, in any order; nil runs all of them
+	Phases []SyncPhase
+
+	// RecomputeMetrics recomputes metrics for every run with streams rather
+	// than only new ones, for after the athlete's HR settings change
+	RecomputeMetrics bool
+
+	// RecomputeRecords analyzes every run with streams for personal records
+	// rather than only new or changed ones, for after GPS smoothing is
+	// switched
+	RecomputeRecords bool
+}
+
+// Runs reports whether the options include a phase
+func (o SyncOptions) Runs(phase SyncPhase) bool {
+	return o.Phases == nil || slices.Contains(o.Phases, phase)
 }
 
-// SyncAll performs a full sync: activities -> streams
+// NeedsStrava reports whether any selected phase calls the Strava API
+func (o SyncOptions) NeedsStrava() bool {
+	return o.Runs(PhaseActivities) || o.Runs(PhaseStreams)
+}
+
+// SyncAll performs a full sync: activities -> streams -> metrics -> personal
+// records -> races -> predictions
 func (s *SyncService) SyncAll(ctx context.Context, progress chan<- SyncProgress) (*SyncResult, error) {
+	return s.Sync(ctx, SyncOptions{}, progress)
+}
+
+// AnalyzeStored runs the local phases of a sync (metrics, personal records,
+// races and predictions) on activities already in the database, without
+// contacting Strava
+func (s *SyncService) AnalyzeStored(ctx context.Context, progress chan<- SyncProgress) (*SyncResult, error) {
+	return s.Sync(ctx, SyncOptions{Phases: LocalPhases}, progress)
+}
+
+// Sync runs the phases selected by opts in order. Phases depend on the
+// data earlier ones leave behind, so running one alone works on whatever
+// the last full sync stored.
+func (s *SyncService) Sync(ctx context.Context, opts SyncOptions, progress chan<- SyncProgress) (result *SyncResult, err error) {
 	if progress != nil {
 		defer close(progress)
 	}
 
+	release, err := s.acquireSyncLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { s.syncDone(ctx, result, err) }()
+	defer release()
+
+	result = &SyncResult{}
+	start := time.Now()
+	slog.Info("sync started", "phases", opts.Phases, "recompute_metrics", opts.RecomputeMetrics,
+		"recompute_records", opts.RecomputeRecords)
+	defer func() {
+		if err != nil {
+			slog.Error("sync failed", "error", err.Error())
+		}
+		result.Duration = time.Since(start)
+		slog.Info("sync finished", "ms", result.Duration.Milliseconds(),
+			"activities", result.ActivitiesStored, "streams", result.StreamsFetched, "details", result.DetailsFetched,
+			"metrics", result.MetricsComputed, "requests", result.Requests, "bytes", result.BytesStored,
+			"errors", len(result.Errors))
+	}()
+
+	metrics := s.computeMetrics
+	if opts.RecomputeMetrics {
+		metrics = s.recomputeMetrics
+	}
+	records := s.computePersonalRecords
+	if opts.RecomputeRecords {
+		records = s.recomputePersonalRecords
+	}
+
+	steps := []struct {
+		phase SyncPhase
+		what  string
+		run   func(context.Context, chan<- SyncProgress, *SyncResult) error
+	}{
+		{PhaseActivities, "syncing activities", s.syncActivities},
+		{PhaseStreams, "syncing streams", s.syncStreams},
+		{PhaseMetrics, "computing metrics", metrics},
+		{PhasePersonalRecords, "computing personal records", records},
+		// Races before predictions, which prefer them as their source
+		{PhaseRaces, "detecting races", s.detectRaces},
+		{PhasePredictions, "computing predictions", s.computeRacePredictions},
+	}
+	for _, step := range steps {
+		if !opts.Runs(step.phase) {
+			continue
+		}
+		timer := s.startPhase(step.phase)
+		err := step.run(ctx, progress, result)
+		timer.finish(result)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", step.what, err)
+		}
+	}
+
+	return result, nil
+}
+
+// RetryFailed redoes the work behind the retryable failures of an earlier
+// sync, one activity at a time: activities are fetched again (their streams
+// follow on the next sync), streams are downloaded again, and metrics and
+// personal records are recomputed. Predictions are regenerated if they or
+// any PRs failed.
+func (s *SyncService) RetryFailed(ctx context.Context, failures []SyncFailure) (result *SyncResult, err error) {
+	release, err := s.acquireSyncLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { s.syncDone(ctx, result, err) }()
+	defer release()
+
+	result = &SyncResult{}
+	slog.Info("retrying failed sync items", "count", len(failures))
+
+	type item struct {
+		phase      string
+		activityID int64
+	}
+	seen := make(map[item]bool)
+	weeks := make(map[time.Time]bool)
+	predictions := false
+
+	for _, f := range failures {
+		if !f.Retryable() || seen[item{f.Phase, f.ActivityID}] {
+			continue
+		}
+		seen[item{f.Phase, f.ActivityID}] = true
+
+		if err := s.checkpoint(ctx); err != nil {
+			return result, err
+		}
+
+		if f.Phase == "activities" {
+			a, err := s.client.GetActivity(ctx, f.ActivityID)
+			if err != nil {
+				result.fail(nil, "activities", f.ActivityID, f.ActivityName, fmt.Errorf("fetching activity %d: %w", f.ActivityID, err))
+				continue
+			}
+			result.ActivitiesFetched++
+			if err := s.storeActivity(*a); err != nil {
+				result.fail(nil, "activities", f.ActivityID, f.ActivityName, fmt.Errorf("storing activity %d: %w", f.ActivityID, err))
+				continue
+			}
+			result.ActivitiesStored++
+			continue
+		}
+
+		if f.Phase == "predictions" {
+			predictions = true
+			continue
+		}
+
+		activity, err := s.store.GetActivity(f.ActivityID)
+		if err != nil {
+			result.fail(nil, f.Phase, f.ActivityID, f.ActivityName, fmt.Errorf("getting activity %d: %w", f.ActivityID, err))
+			continue
+		}
+
+		switch f.Phase {
+		case "streams":
+			if s.syncActivityStreams(ctx, *activity, nil, result) {
+				result.StreamsFetched++
+			}
+		case "details":
+			if s.fetchDetails(ctx, *activity, nil, result) {
+				result.DetailsFetched++
+				if s.stravaEfforts {
+					s.analyzeActivityPRs(activity, nil, result)
+					predictions = true
+				}
+			}
+		case "metrics":
+			if s.computeActivityMetrics(*activity, nil, result) {
+				result.MetricsComputed++
+				weeks[weekStartOf(s.store, *activity)] = true
+			}
+		case "personal_records":
+			s.analyzeActivityPRs(activity, nil, result)
+			predictions = true
+		case "races":
+			s.markDetectedRace(activity, nil, result)
+			predictions = true
+		}
+	}
+
+	if len(weeks) > 0 {
+		if err := s.updateWeeklySummaries(weeks); err != nil {
+			result.fail(nil, "metrics", 0, "", err)
+		}
+	}
+
+	if predictions {
+		if err := s.computeRacePredictions(ctx, nil, result); err != nil {
+			return result, fmt.Errorf("computing predictions: %w", err)
+		}
+	}
+
+	slog.Info("retry finished", "errors", len(result.Errors))
+	return result, nil
+}
+
+// ResyncActivity fetches one activity's summary and streams from Strava
+// again and recomputes its metrics and personal records, for when the
+// activity was corrected on Strava after it was first synced. It also undoes
+// local trims and distance corrections. Failures are returned as an error as
+// well as recorded in the result.
+func (s *SyncService) ResyncActivity(ctx context.Context, activityID int64) (*SyncResult, error) {
+	existing, err := s.store.GetActivity(activityID)
+	if err != nil {
+		return nil, fmt.Errorf("getting activity %d: %w", activityID, err)
+	}
+	if existing.Manual {
+		return nil, fmt.Errorf("activity %d was added manually and isn't on Strava", activityID)
+	}
+
 	result := &SyncResult{}
+	slog.Info("resyncing activity", "activity_id", activityID)
 
-	// Phase 1: Sync activity summaries
-	if err := s.syncActivities(ctx, progress, result); err != nil {
-		return result, fmt.Errorf("syncing activities: %w", err)
+	a, err := s.client.GetActivity(ctx, activityID)
+	if err != nil {
+		return result, fmt.Errorf("fetching activity %d: %w", activityID, err)
 	}
+	result.ActivitiesFetched++
 
-	// Phase 2: Fetch streams for activities that need them
-	if err := s.syncStreams(ctx, progress, result); err != nil {
-		return result, fmt.Errorf("syncing streams: %w", err)
+	// Resyncing restores Strava's version of a run edited locally
+	if err := s.store.DeleteActivityTrim(activityID); err != nil {
+		return result, fmt.Errorf("clearing trim for %d: %w", activityID, err)
+	}
+	if err := s.store.DeleteDistanceCorrection(activityID); err != nil {
+		return result, fmt.Errorf("clearing distance correction for %d: %w", activityID, err)
+	}
+	activity := convertActivity(*a)
+	if err := s.storeActivity(*a); err != nil {
+		return result, fmt.Errorf("storing activity %d: %w", activityID, err)
 	}
+	result.ActivitiesStored++
 
-	// Phase 3: Compute metrics for activities that need them
-	if err := s.computeMetrics(ctx, progress, result); err != nil {
-		return result, fmt.Errorf("computing metrics: %w", err)
+	if !s.syncActivityStreams(ctx, *activity, nil, result) {
+		return result, result.Errors[len(result.Errors)-1]
 	}
+	result.StreamsFetched++
 
-	// Phase 4: Compute personal records
-	if err := s.computePersonalRecords(ctx, progress, result); err != nil {
-		return result, fmt.Errorf("computing personal records: %w", err)
+	// GetActivity returned the detailed activity, so its details are fresh
+	if err := s.saveDetails(*a); err != nil {
+		result.fail(nil, "details", activityID, a.Name, fmt.Errorf("saving details for %d: %w", activityID, err))
+	} else {
+		result.DetailsFetched++
+	}
+
+	if s.computeActivityMetrics(*activity, nil, result) {
+		result.MetricsComputed++
 	}
 
-	// Phase 5: Compute race predictions
-	if err := s.computeRacePredictions(ctx, progress, result); err != nil {
+	// A corrected start time can move the run to another week
+	weeks := map[time.Time]bool{
+		weekStartOf(s.store, *existing): true,
+		weekStartOf(s.store, *activity): true,
+	}
+	if err := s.updateWeeklySummaries(weeks); err != nil {
+		result.fail(nil, "metrics", 0, "", err)
+	}
+
+	// Drop this run's records first so a corrected, slower run doesn't keep
+	// them, then let the other runs compete for them again
+	held, err := s.store.GetPersonalRecordsForActivity(activityID)
+	if err != nil {
+		return result, fmt.Errorf("getting records for %d: %w", activityID, err)
+	}
+	if err := s.store.DeletePersonalRecordsForActivity(activityID); err != nil {
+		return result, fmt.Errorf("clearing records for %d: %w", activityID, err)
+	}
+	if err := s.store.DeleteDurationEfforts(activityID); err != nil {
+		return result, fmt.Errorf("clearing duration efforts for %d: %w", activityID, err)
+	}
+	updated, err := s.store.GetActivity(activityID)
+	if err != nil {
+		return result, fmt.Errorf("getting activity %d: %w", activityID, err)
+	}
+	s.analyzeActivityPRs(updated, nil, result)
+	if err := s.refillRecords(ctx, recordCategories(held), result); err != nil {
+		return result, err
+	}
+
+	if err := s.computeRacePredictions(ctx, nil, result); err != nil {
 		return result, fmt.Errorf("computing predictions: %w", err)
 	}
 
+	if len(result.Errors) > 0 {
+		return result, result.Errors[0]
+	}
 	return result, nil
 }
 
+// activitySyncPageKey is the sync state key holding the next activities
+// page to fetch while a paginated sync is unfinished
+const activitySyncPageKey = "activity_sync_page"
+
 // syncActivities fetches all activities from Strava and stores them
 func (s *SyncService) syncActivities(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
 	// Get last sync time
@@ -105,8 +576,7 @@ func (s *SyncService) syncActivities(ctx context.Context, progress chan<- SyncPr
 		if parseErr != nil {
 			// Corrupted sync state - log error and sync from beginning
 			syncErr := fmt.Errorf("parsing last sync time %q, will sync from beginning: %w", lastSyncStr, parseErr)
-			result.Errors = append(result.Errors, syncErr)
-			reportError(progress, "activities", syncErr)
+			result.fail(progress, "activities", 0, "", syncErr)
 			after = time.Time{} // Reset to zero time
 		}
 	}
@@ -115,17 +585,40 @@ func (s *SyncService) syncActivities(ctx context.Context, progress chan<- SyncPr
 		progress <- SyncProgress{Phase: "activities", Total: 0, Completed: 0}
 	}
 
+	if err := s.pruneSocial(); err != nil {
+		result.fail(progress, "activities", 0, "", err)
+	}
+
+	// Kudos and comments keep coming in after a run is stored, so recent
+	// runs are fetched again to refresh their counts
+	fetchAfter := after
+	if s.syncSocial && !after.IsZero() {
+		fetchAfter = after.AddDate(0, 0, -SocialRefreshDays)
+	}
+
+	// Pick up after the last page an interrupted sync stored. Uploads since
+	// then can only move activities onto later pages, so none are missed.
 	page := 1
-	perPage := 100
+	if pageStr, _ := s.store.GetSyncState(activitySyncPageKey); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 1 {
+			page = p
+			slog.Info("resuming activity sync", "page", page)
+		}
+	}
+	perPage := ActivitiesPerPage
+
+	// Runs deleted locally stay deleted
+	deleted, err := s.store.DeletedActivityIDs()
+	if err != nil {
+		return fmt.Errorf("listing deleted activities: %w", err)
+	}
 
 	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+		if err := s.checkpoint(ctx); err != nil {
+			return err
 		}
 
-		activities, err := s.client.GetActivities(ctx, after, page, perPage)
+		activities, err := s.client.GetActivities(ctx, fetchAfter, page, perPage)
 		if err != nil {
 			return fmt.Errorf("fetching page %d: %w", page, err)
 		}
@@ -138,12 +631,21 @@ func (s *SyncService) syncActivities(ctx context.Context, progress chan<- SyncPr
 
 		for _, a := range activities {
 			// Only store runs with HR data
-			if a.Type == "Run" && a.HasHeartrate {
-				storeActivity := convertActivity(a)
-				if err := s.store.UpsertActivity(storeActivity); err != nil {
+			if a.Type == "Run" && a.HasHeartrate && !deleted[a.ID] {
+				if a.StartDate.Before(after) {
+					refreshed, err := s.refreshSocial(a)
+					if err != nil {
+						refreshErr := fmt.Errorf("refreshing kudos for %d: %w", a.ID, err)
+						result.fail(progress, "activities", a.ID, a.Name, refreshErr)
+						continue
+					}
+					if refreshed {
+						continue
+					}
+				}
+				if err := s.storeActivity(a); err != nil {
 					storeErr := fmt.Errorf("storing activity %d: %w", a.ID, err)
-					result.Errors = append(result.Errors, storeErr)
-					reportError(progress, "activities", storeErr)
+					result.fail(progress, "activities", a.ID, a.Name, storeErr)
 					continue
 				}
 				result.ActivitiesStored++
@@ -164,18 +666,50 @@ func (s *SyncService) syncActivities(ctx context.Context, progress chan<- SyncPr
 		}
 
 		page++
+		s.store.SetSyncState(activitySyncPageKey, strconv.Itoa(page))
 	}
 
 	// Update last sync time
 	s.store.SetSyncState("last_activity_sync", time.Now().Format(time.RFC3339))
+	s.store.SetSyncState(activitySyncPageKey, "")
+
+	return nil
+}
 
+// storeActivity saves a fetched activity and records Strava's race flag.
+// Runs trimmed or corrected locally keep their edited summary.
+func (s *SyncService) storeActivity(a strava.Activity) error {
+	activity := convertActivity(a)
+	if err := s.keepLocalSummary(activity); err != nil {
+		return fmt.Errorf("checking local edits: %w", err)
+	}
+	if err := s.store.UpsertActivity(activity); err != nil {
+		return err
+	}
+	if err := s.saveSocial(a, ""); err != nil {
+		return fmt.Errorf("saving kudos and comment counts: %w", err)
+	}
+	if a.IsRace() {
+		if _, err := s.store.MarkRace(a.ID, store.RaceSourceStrava); err != nil {
+			return fmt.Errorf("marking race: %w", err)
+		}
+	}
 	return nil
 }
 
-// syncStreams fetches detailed stream data for activities that need it
+// syncStreams fetches detailed stream data for activities that need it,
+// then the detailed activity for runs with streams
 func (s *SyncService) syncStreams(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
+	if err := s.syncStreamBatch(ctx, progress, result); err != nil {
+		return err
+	}
+	return s.syncDetails(ctx, progress, result)
+}
+
+// syncStreamBatch downloads streams for up to StreamBatchSize activities
+func (s *SyncService) syncStreamBatch(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
 	// Get activities that need streams (limit to batch size to respect rate limits)
-	activities, err := s.store.GetActivitiesNeedingStreams(50)
+	activities, err := s.store.GetActivitiesNeedingStreams(StreamBatchSize)
 	if err != nil {
 		return fmt.Errorf("getting activities needing streams: %w", err)
 	}
@@ -189,10 +723,8 @@ func (s *SyncService) syncStreams(ctx context.Context, progress chan<- SyncProgr
 	}
 
 	for i, activity := range activities {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+		if err := s.checkpoint(ctx); err != nil {
+			return err
 		}
 
 		if progress != nil {
@@ -204,35 +736,9 @@ func (s *SyncService) syncStreams(ctx context.Context, progress chan<- SyncProgr
 			}
 		}
 
-		streams, err := s.client.GetActivityStreams(ctx, activity.ID)
-		if err != nil {
-			// Log error but continue - some activities may not have streams
-			streamErr := fmt.Errorf("activity %d (%s): %w", activity.ID, activity.Name, err)
-			result.Errors = append(result.Errors, streamErr)
-			reportError(progress, "streams", streamErr)
-			continue
+		if s.syncActivityStreams(ctx, activity, progress, result) {
+			result.StreamsFetched++
 		}
-
-		// Convert and store streams
-		points := convertStreams(activity.ID, streams)
-		if len(points) > 0 {
-			if err := s.store.SaveStreams(activity.ID, points); err != nil {
-				saveErr := fmt.Errorf("saving streams for %d: %w", activity.ID, err)
-				result.Errors = append(result.Errors, saveErr)
-				reportError(progress, "streams", saveErr)
-				continue
-			}
-		}
-
-		// Mark activity as having streams synced
-		if err := s.store.MarkStreamsSynced(activity.ID); err != nil {
-			markErr := fmt.Errorf("marking synced for %d: %w", activity.ID, err)
-			result.Errors = append(result.Errors, markErr)
-			reportError(progress, "streams", markErr)
-			continue
-		}
-
-		result.StreamsFetched++
 	}
 
 	if progress != nil {
@@ -246,6 +752,37 @@ func (s *SyncService) syncStreams(ctx context.Context, progress chan<- SyncProgr
 	return nil
 }
 
+// syncActivityStreams downloads and stores the streams for one activity,
+// reporting whether it succeeded
+func (s *SyncService) syncActivityStreams(ctx context.Context, activity store.Activity, progress chan<- SyncProgress, result *SyncResult) bool {
+	streams, err := s.client.GetActivityStreams(ctx, activity.ID, s.resolutionFor(activity))
+	if err != nil {
+		// Log error but continue - some activities may not have streams
+		streamErr := fmt.Errorf("activity %d (%s): %w", activity.ID, activity.Name, err)
+		result.fail(progress, "streams", activity.ID, activity.Name, streamErr)
+		return false
+	}
+
+	// Convert and store streams
+	points := s.capStreamPoints(activity.ID, convertStreams(activity.ID, streams))
+	if len(points) > 0 {
+		if err := s.store.SaveStreams(activity.ID, points); err != nil {
+			saveErr := fmt.Errorf("saving streams for %d: %w", activity.ID, err)
+			result.fail(progress, "streams", activity.ID, activity.Name, saveErr)
+			return false
+		}
+	}
+
+	// Mark activity as having streams synced
+	if err := s.store.MarkStreamsSynced(activity.ID); err != nil {
+		markErr := fmt.Errorf("marking synced for %d: %w", activity.ID, err)
+		result.fail(progress, "streams", activity.ID, activity.Name, markErr)
+		return false
+	}
+
+	return true
+}
+
 // computeMetrics calculates fitness metrics for activities that need them
 func (s *SyncService) computeMetrics(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
 	// Get activities that have streams but no metrics
@@ -253,7 +790,41 @@ func (s *SyncService) computeMetrics(ctx context.Context, progress chan<- SyncPr
 	if err != nil {
 		return fmt.Errorf("getting activities needing metrics: %w", err)
 	}
+	if err := s.computeMetricsFor(ctx, activities, progress, result); err != nil {
+		return err
+	}
+	s.updateFitnessTrends(progress, result)
+	return nil
+}
 
+// recomputeMetrics recalculates metrics for every activity with streams,
+// replacing those computed with earlier HR settings
+func (s *SyncService) recomputeMetrics(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
+	var activities []store.Activity
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		page, err := s.store.ListActivities(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return fmt.Errorf("listing activities: %w", err)
+		}
+		for _, a := range page {
+			if a.StreamsSynced {
+				activities = append(activities, a)
+			}
+		}
+		if len(page) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+	if err := s.computeMetricsFor(ctx, activities, progress, result); err != nil {
+		return err
+	}
+	s.updateFitnessTrends(progress, result)
+	return nil
+}
+
+// computeMetricsFor computes metrics for the given activities and updates
+// the weekly summaries of the weeks they fall in
+func (s *SyncService) computeMetricsFor(ctx context.Context, activities []store.Activity, progress chan<- SyncProgress, result *SyncResult) error {
 	if len(activities) == 0 {
 		return nil
 	}
@@ -262,66 +833,182 @@ func (s *SyncService) computeMetrics(ctx context.Context, progress chan<- SyncPr
 		progress <- SyncProgress{Phase: "metrics", Total: len(activities), Completed: 0}
 	}
 
-	zones := s.hrZones
-
-	for i, activity := range activities {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+	weeks := make(map[time.Time]bool)
 
-		if progress != nil {
-			progress <- SyncProgress{
-				Phase:           "metrics",
-				Total:           len(activities),
-				Completed:       i,
-				CurrentActivity: activity.Name,
+	err := analyzeInOrder(ctx, s, "metrics", activities, progress, s.prepareActivityMetrics,
+		func(activity store.Activity, work activityMetricsWork) {
+			if s.saveActivityMetrics(activity, work, progress, result) {
+				result.MetricsComputed++
+				weeks[weekStartOf(s.store, activity)] = true
 			}
-		}
+		})
 
-		// Get streams for this activity
-		streams, err := s.store.GetStreams(activity.ID)
-		if err != nil {
-			getErr := fmt.Errorf("getting streams for %d: %w", activity.ID, err)
-			result.Errors = append(result.Errors, getErr)
-			reportError(progress, "metrics", getErr)
-			continue
-		}
+	// Keep the weekly summaries in step with the newly analyzed runs, even
+	// when the phase stopped partway
+	if err := s.updateWeeklySummaries(weeks); err != nil {
+		result.fail(progress, "metrics", 0, "", err)
+	}
+	return err
+}
 
-		if len(streams) == 0 {
-			continue
-		}
+// computeActivityMetrics computes and saves the metrics for one activity,
+// reporting whether any were saved
+func (s *SyncService) computeActivityMetrics(activity store.Activity, progress chan<- SyncProgress, result *SyncResult) bool {
+	return s.saveActivityMetrics(activity, s.prepareActivityMetrics(activity), progress, result)
+}
 
-		// Compute metrics
-		metrics := analysis.ComputeActivityMetrics(activity, streams, zones)
+// activityMetricsWork is everything computed from one run's streams for the
+// metrics phase, ready to be saved
+type activityMetricsWork struct {
+	err     error
+	metrics *store.ActivityMetrics // nil when the run has no streams
+	stats   *store.ActivityStreamStats
+	splits  []store.ActivitySplit
+	custom  []store.CustomMetric
+}
 
-		// Save metrics
-		if err := s.store.SaveActivityMetrics(&metrics); err != nil {
-			saveErr := fmt.Errorf("saving metrics for %d: %w", activity.ID, err)
-			result.Errors = append(result.Errors, saveErr)
-			reportError(progress, "metrics", saveErr)
-			continue
-		}
+// prepareActivityMetrics reads an activity's streams and computes its
+// metrics, stream totals, splits and custom metrics without saving them
+func (s *SyncService) prepareActivityMetrics(activity store.Activity) activityMetricsWork {
+	streams, err := s.store.GetStreams(activity.ID)
+	if err != nil {
+		return activityMetricsWork{err: fmt.Errorf("getting streams for %d: %w", activity.ID, err)}
+	}
+	if len(streams) == 0 {
+		return activityMetricsWork{}
+	}
 
-		result.MetricsComputed++
+	analyzed := s.analysisStreams(streams)
+	metrics := analysis.ComputeActivityMetrics(activity, analyzed, s.hrZones)
+
+	// Flags describe the data as recorded, not the smoothed copy
+	if s.smoothGPS && metrics.DataQualityScore != nil {
+		metrics.AnomalyFlags = analysis.DetectAnomalies(streams, *metrics.DataQualityScore, s.hrZones)
 	}
 
-	if progress != nil {
-		progress <- SyncProgress{
-			Phase:     "metrics",
-			Total:     len(activities),
-			Completed: len(activities),
-		}
+	// Cache time in HR zone so weekly reports don't need raw streams
+	s.setZoneSeconds(&metrics, streams)
+
+	// Keep suspect runs out of EF trends when configured
+	if s.excludeFlagged && len(metrics.AnomalyFlags) > 0 {
+		metrics.EfficiencyFactor = nil
 	}
 
-	return nil
+	return activityMetricsWork{
+		metrics: &metrics,
+		// Stream totals let period stats be summed without streams, and
+		// stored splits spare the detail screen and split rankings a rescan
+		stats:  activityStreamStats(activity.ID, streams),
+		splits: activitySplits(activity, streams),
+		// Custom metrics see the standard ones as saved
+		custom: customMetricValues(analysis.MetricInput{
+			Activity: activity,
+			Streams:  analyzed,
+			Zones:    s.hrZones,
+			Metrics:  metrics,
+		}, s.customMetrics),
+	}
+}
+
+// saveActivityMetrics saves what prepareActivityMetrics computed for an
+// activity, reporting whether any metrics were saved
+func (s *SyncService) saveActivityMetrics(activity store.Activity, work activityMetricsWork, progress chan<- SyncProgress, result *SyncResult) bool {
+	if work.err != nil {
+		result.fail(progress, "metrics", activity.ID, activity.Name, work.err)
+		return false
+	}
+	if work.metrics == nil {
+		return false
+	}
+
+	if err := s.store.SaveActivityMetrics(work.metrics); err != nil {
+		saveErr := fmt.Errorf("saving metrics for %d: %w", activity.ID, err)
+		result.fail(progress, "metrics", activity.ID, activity.Name, saveErr)
+		return false
+	}
+
+	if err := s.store.SaveActivityStreamStats(work.stats); err != nil {
+		saveErr := fmt.Errorf("saving stream stats for %d: %w", activity.ID, err)
+		result.fail(progress, "metrics", activity.ID, activity.Name, saveErr)
+		return false
+	}
+
+	if err := s.store.SaveActivitySplits(activity.ID, work.splits); err != nil {
+		saveErr := fmt.Errorf("saving splits for %d: %w", activity.ID, err)
+		result.fail(progress, "metrics", activity.ID, activity.Name, saveErr)
+		return false
+	}
+
+	if err := s.store.SaveCustomMetrics(activity.ID, work.custom); err != nil {
+		saveErr := fmt.Errorf("saving custom metrics for %d: %w", activity.ID, err)
+		result.fail(progress, "metrics", activity.ID, activity.Name, saveErr)
+		return false
+	}
+
+	return true
 }
 
-// computePersonalRecords analyzes activities for personal records
+// analysisStreams returns the streams metrics and records are computed
+// from: smoothed unless smoothing is turned off. The stored streams are
+// never changed.
+func (s *SyncService) analysisStreams(streams []store.StreamPoint) []store.StreamPoint {
+	if !s.smoothGPS {
+		return streams
+	}
+	return analysis.SmoothStreams(streams)
+}
+
+// updateWeeklySummaries rebuilds the summaries for the given weeks, or for
+// every week if the table has never been filled
+func (s *SyncService) updateWeeklySummaries(weeks map[time.Time]bool) error {
+	count, err := s.store.CountWeeklySummaries()
+	if err != nil {
+		return fmt.Errorf("counting weekly summaries: %w", err)
+	}
+	if count == 0 {
+		return rebuildAllWeeklySummaries(s.store)
+	}
+
+	list := make([]time.Time, 0, len(weeks))
+	for w := range weeks {
+		list = append(list, w)
+	}
+	return rebuildWeeklySummaries(s.store, list)
+}
+
+// updateFitnessTrends rebuilds the daily fitness trends. They move every
+// day, so this runs on every metrics pass, not only when runs were analyzed.
+func (s *SyncService) updateFitnessTrends(progress chan<- SyncProgress, result *SyncResult) {
+	if err := rebuildFitnessTrends(s.store, time.Now()); err != nil {
+		result.fail(progress, "metrics", 0, "", fmt.Errorf("updating fitness trends: %w", err))
+	}
+}
+
+// setZoneSeconds fills the cached per-zone seconds using the same zones as
+// the activity detail screen
+func (s *SyncService) setZoneSeconds(metrics *store.ActivityMetrics, streams []store.StreamPoint) {
+	zones := calculateHRZones(streams, int(s.hrZones.MaxHR), int(s.hrZones.ThresholdHR))
+	if len(zones) != 5 {
+		return
+	}
+	fields := []**int{&metrics.Z1Seconds, &metrics.Z2Seconds, &metrics.Z3Seconds, &metrics.Z4Seconds, &metrics.Z5Seconds}
+	for i, zone := range zones {
+		secs := zone.Seconds
+		*fields[i] = &secs
+	}
+}
+
+// computePersonalRecords analyzes activities for personal records. Only
+// runs not analyzed since their streams or summary last changed are read;
+// the rest already competed and their best efforts are cached.
 func (s *SyncService) computePersonalRecords(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
-	// Get all activities with streams for PR analysis
-	activities, err := s.store.ListActivities(500, 0)
+	// Records of runs deleted or excluded since the last sync go first, so
+	// the runs below compete against what actually stands
+	if err := s.repairRecords(ctx, result); err != nil {
+		return fmt.Errorf("repairing stale records: %w", err)
+	}
+
+	activities, err := s.store.GetActivitiesNeedingEfforts()
 	if err != nil {
 		return fmt.Errorf("getting activities for PR analysis: %w", err)
 	}
@@ -334,144 +1021,373 @@ func (s *SyncService) computePersonalRecords(ctx context.Context, progress chan<
 		progress <- SyncProgress{Phase: "personal_records", Total: len(activities), Completed: 0}
 	}
 
-	for i, activity := range activities {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+	return analyzeInOrder(ctx, s, "personal_records", activities, progress, s.findActivityEfforts,
+		func(activity store.Activity, work activityEffortsWork) {
+			s.applyActivityPRs(&activity, work, progress, result)
+		})
+}
 
-		if progress != nil {
-			progress <- SyncProgress{
-				Phase:           "personal_records",
-				Total:           len(activities),
-				Completed:       i,
-				CurrentActivity: activity.Name,
-			}
-		}
+// recomputePersonalRecords analyzes every activity with streams for
+// personal records again
+func (s *SyncService) recomputePersonalRecords(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
+	if err := s.store.ResetEffortsComputed(); err != nil {
+		return fmt.Errorf("resetting analyzed runs: %w", err)
+	}
+	return s.computePersonalRecords(ctx, progress, result)
+}
+
+// analyzeActivityPRs checks one activity for race-distance PRs, other
+// achievements, and best efforts within its streams. Once that succeeds,
+// the activity is marked so later syncs skip it.
+func (s *SyncService) analyzeActivityPRs(activity *store.Activity, progress chan<- SyncProgress, result *SyncResult) {
+	s.applyActivityPRs(activity, s.findActivityEfforts(*activity), progress, result)
+}
+
+// activityEffortsWork is what one run's streams yield for the records
+// phase, ready to be offered as records and saved
+type activityEffortsWork struct {
+	err      error
+	recorded []store.StreamPoint // as recorded; nil when there's nothing to analyze
+
+	// Pace-curve efforts, nil when they're already cached
+	duration    []store.DurationEffort
+	durationErr error
+
+	verified bool // the athlete confirmed the run's efforts as real
+	found    []foundEffort
+}
 
-		// Skip activities without streams
-		if !activity.StreamsSynced {
+// foundEffort is a run's best effort over one of the effort distances
+type foundEffort struct {
+	category string
+	effort   *analysis.BestEffort
+	disputed bool // Strava's time for it differs from the streams'
+}
+
+// findActivityEfforts reads an activity's streams and finds its best
+// efforts without offering or saving anything. Runs that don't compete for
+// records are left alone.
+func (s *SyncService) findActivityEfforts(activity store.Activity) activityEffortsWork {
+	if !activity.StreamsSynced || activity.Excluded || (s.excludeFlagged && s.hasAnomalies(activity.ID)) {
+		return activityEffortsWork{}
+	}
+
+	recorded, err := s.store.GetStreams(activity.ID)
+	if err != nil {
+		return activityEffortsWork{err: fmt.Errorf("getting streams for PR analysis %d: %w", activity.ID, err)}
+	}
+	if len(recorded) == 0 {
+		return activityEffortsWork{}
+	}
+	streams := s.analysisStreams(recorded)
+	work := activityEffortsWork{recorded: recorded}
+
+	work.duration, work.durationErr = s.findDurationEfforts(&activity, streams)
+
+	if work.verified, err = s.store.EffortsVerified(activity.ID); err != nil {
+		work.err = fmt.Errorf("checking effort verification for %d: %w", activity.ID, err)
+		return work
+	}
+	imported, err := s.importedBestEfforts(&activity)
+	if err != nil {
+		work.err = fmt.Errorf("getting Strava best efforts for %d: %w", activity.ID, err)
+		return work
+	}
+
+	for _, targetDist := range slices.Sorted(maps.Keys(analysis.EffortCategories)) {
+		effort, disputed := bestEffort(streams, recorded, imported, targetDist)
+		if effort == nil {
 			continue
 		}
+		work.found = append(work.found, foundEffort{
+			category: analysis.EffortCategories[targetDist],
+			effort:   effort,
+			disputed: disputed,
+		})
+	}
+	return work
+}
 
-		// Check if activity matches a race distance
-		if category, _, matches := analysis.GetMatchingRaceCategory(activity.Distance); matches {
-			pacePerMile := analysis.CalculatePacePerMile(activity.Distance, activity.MovingTime)
-			pr := &store.PersonalRecord{
-				Category:        category,
-				ActivityID:      activity.ID,
-				DistanceMeters:  activity.Distance,
-				DurationSeconds: activity.MovingTime,
-				PacePerMile:     &pacePerMile,
-				AvgHeartrate:    activity.AverageHeartrate,
-				AchievedAt:      activity.StartDate,
-			}
-			if updated, err := s.store.UpsertPersonalRecord(pr); err != nil {
-				prErr := fmt.Errorf("saving distance PR for %d: %w", activity.ID, err)
-				result.Errors = append(result.Errors, prErr)
-				reportError(progress, "personal_records", prErr)
-			} else if updated {
-				result.PRsComputed++
-			}
+// applyActivityPRs offers an activity's summary and the best efforts
+// findActivityEfforts found to the records and saves the efforts. Whether
+// an effort looks too good depends on the records standing, so runs are
+// applied one at a time.
+func (s *SyncService) applyActivityPRs(activity *store.Activity, work activityEffortsWork, progress chan<- SyncProgress, result *SyncResult) {
+	errs := len(result.Errors)
+	defer func() {
+		if len(result.Errors) > errs {
+			return
+		}
+		if err := s.store.MarkEffortsComputed(activity.ID); err != nil {
+			markErr := fmt.Errorf("marking %d analyzed: %w", activity.ID, err)
+			result.fail(progress, "personal_records", activity.ID, activity.Name, markErr)
 		}
+	}()
 
-		// Check other achievements: longest run, highest elevation, fastest avg pace
-		s.checkOtherAchievements(&activity, result, progress)
+	// Skip activities without streams or excluded from analysis
+	if !activity.StreamsSynced || activity.Excluded {
+		return
+	}
+	if s.excludeFlagged && s.hasAnomalies(activity.ID) {
+		// Keep flagged runs off the effort rankings too
+		if err := s.store.SaveActivityBestEfforts(activity.ID, nil); err != nil {
+			clearErr := fmt.Errorf("clearing best efforts for %d: %w", activity.ID, err)
+			result.fail(progress, "personal_records", activity.ID, activity.Name, clearErr)
+		}
+		return
+	}
 
-		// Get streams for best effort analysis
-		streams, err := s.store.GetStreams(activity.ID)
-		if err != nil {
-			getErr := fmt.Errorf("getting streams for PR analysis %d: %w", activity.ID, err)
-			result.Errors = append(result.Errors, getErr)
-			reportError(progress, "personal_records", getErr)
-			continue
+	// Check the records its summary competes for: race distance, longest
+	// run, highest elevation, fastest avg pace
+	for _, c := range summaryRecords(activity) {
+		if s.offerRecord(c.pr, c.mode, activity, result, progress) {
+			s.noteRecord(result, c.pr, c.mode, activity.Name)
 		}
+	}
 
-		if len(streams) == 0 {
-			continue
+	if work.durationErr != nil {
+		result.fail(progress, "personal_records", activity.ID, activity.Name, work.durationErr)
+	} else if len(work.duration) > 0 {
+		if err := s.store.SaveDurationEfforts(activity.ID, work.duration); err != nil {
+			saveErr := fmt.Errorf("saving duration efforts for %d: %w", activity.ID, err)
+			result.fail(progress, "personal_records", activity.ID, activity.Name, saveErr)
 		}
+	}
+	if work.err != nil {
+		result.fail(progress, "personal_records", activity.ID, activity.Name, work.err)
+		return
+	}
+	if work.recorded == nil {
+		return
+	}
 
-		// Find best efforts for each target distance
-		for targetDist, category := range analysis.EffortCategories {
-			effort := analysis.FindBestEffort(streams, targetDist)
-			if effort == nil {
+	// Offer the best effort for each target distance
+	var efforts []store.ActivityBestEffort
+	for _, f := range work.found {
+		category, effort := f.category, f.effort
+
+		unverified := false
+		if !work.verified {
+			var err error
+			if unverified, err = s.effortUnverified(work.recorded, activity.ID, category, effort); err != nil {
+				effortErr := fmt.Errorf("checking %s effort for %d: %w", category, activity.ID, err)
+				result.fail(progress, "personal_records", activity.ID, activity.Name, effortErr)
 				continue
 			}
+			unverified = unverified || f.disputed
+		}
 
-			pacePerMile := analysis.CalculatePacePerMile(effort.DistanceMeters, effort.DurationSeconds)
-			var avgHR *float64
-			if effort.AvgHeartrate > 0 {
-				avgHR = &effort.AvgHeartrate
-			}
-			startOffset := effort.StartOffset
-			endOffset := effort.EndOffset
-
-			pr := &store.PersonalRecord{
-				Category:        category,
-				ActivityID:      activity.ID,
-				DistanceMeters:  effort.DistanceMeters,
-				DurationSeconds: effort.DurationSeconds,
-				PacePerMile:     &pacePerMile,
-				AvgHeartrate:    avgHR,
-				AchievedAt:      activity.StartDate,
-				StartOffset:     &startOffset,
-				EndOffset:       &endOffset,
-			}
-			if updated, err := s.store.UpsertPersonalRecord(pr); err != nil {
-				effortErr := fmt.Errorf("saving effort PR for %d: %w", activity.ID, err)
-				result.Errors = append(result.Errors, effortErr)
-				reportError(progress, "personal_records", effortErr)
-			} else if updated {
-				result.PRsComputed++
-			}
+		pacePerMile := analysis.CalculatePacePerMile(effort.DistanceMeters, effort.DurationSeconds)
+		var avgHR *float64
+		if effort.AvgHeartrate > 0 {
+			avgHR = &effort.AvgHeartrate
+		}
+		startOffset := effort.StartOffset
+		endOffset := effort.EndOffset
+
+		efforts = append(efforts, store.ActivityBestEffort{
+			ActivityID:      activity.ID,
+			Category:        category,
+			DistanceMeters:  effort.DistanceMeters,
+			DurationSeconds: effort.DurationSeconds,
+			StartOffset:     startOffset,
+			EndOffset:       endOffset,
+			AvgHeartrate:    avgHR,
+			Unverified:      unverified,
+		})
+
+		pr := &store.PersonalRecord{
+			Category:        category,
+			ActivityID:      activity.ID,
+			DistanceMeters:  effort.DistanceMeters,
+			DurationSeconds: effort.DurationSeconds,
+			PacePerMile:     &pacePerMile,
+			AvgHeartrate:    avgHR,
+			AchievedAt:      activity.StartDate,
+			StartOffset:     &startOffset,
+			EndOffset:       &endOffset,
+			Unverified:      unverified,
+		}
+		if updated, err := s.store.UpsertPersonalRecord(pr); err != nil {
+			effortErr := fmt.Errorf("saving effort PR for %d: %w", activity.ID, err)
+			result.fail(progress, "personal_records", activity.ID, activity.Name, effortErr)
+		} else if updated {
+			s.noteRecord(result, pr, store.CompareDuration, activity.Name)
 		}
 	}
 
-	if progress != nil {
-		progress <- SyncProgress{
-			Phase:     "personal_records",
-			Total:     len(activities),
-			Completed: len(activities),
-		}
+	if err := s.store.SaveActivityBestEfforts(activity.ID, efforts); err != nil {
+		saveErr := fmt.Errorf("saving best efforts for %d: %w", activity.ID, err)
+		result.fail(progress, "personal_records", activity.ID, activity.Name, saveErr)
 	}
+}
 
-	return nil
+// effortUnverified reports whether a best effort should be marked unverified:
+// its stretch of the recorded streams has GPS errors, or it beats the
+// standing record from another run by more than MaxRecordImprovement
+func (s *SyncService) effortUnverified(recorded []store.StreamPoint, activityID int64, category string, effort *analysis.BestEffort) (bool, error) {
+	if analysis.SegmentHasGPSErrors(recorded, effort.StartOffset, effort.EndOffset) {
+		return true, nil
+	}
+
+	record, err := s.store.GetPersonalRecordByCategory(category)
+	if errors.Is(err, store.ErrPersonalRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// The run's own record, or a doubtful one, is no yardstick
+	if record.ActivityID == activityID || record.Unverified {
+		return false, nil
+	}
+	return float64(effort.DurationSeconds) < float64(record.DurationSeconds)*(1-MaxRecordImprovement), nil
+}
+
+// findDurationEfforts finds the activity's pace-curve efforts unless they
+// are already cached, returning nil then. Streams don't change once synced,
+// so each activity is scanned only once; ResyncActivity clears the cache
+// first.
+func (s *SyncService) findDurationEfforts(activity *store.Activity, streams []store.StreamPoint) ([]store.DurationEffort, error) {
+	cached, err := s.store.HasDurationEfforts(activity.ID)
+	if err != nil {
+		return nil, fmt.Errorf("checking duration efforts for %d: %w", activity.ID, err)
+	}
+	if cached {
+		return nil, nil
+	}
+
+	var efforts []store.DurationEffort
+	for _, e := range analysis.FindDurationEfforts(streams) {
+		efforts = append(efforts, store.DurationEffort{
+			ActivityID:      activity.ID,
+			DurationSeconds: e.DurationSeconds,
+			DistanceMeters:  e.DistanceMeters,
+			StartOffset:     e.StartOffset,
+		})
+	}
+	return efforts, nil
+}
+
+// hasAnomalies reports whether the activity's metrics carry anomaly flags
+func (s *SyncService) hasAnomalies(activityID int64) bool {
+	metrics, err := s.store.GetActivityMetrics(activityID)
+	if err != nil || metrics == nil {
+		return false
+	}
+	return len(metrics.AnomalyFlags) > 0
 }
 
-// checkOtherAchievements checks for longest run, highest elevation, fastest average pace
-func (s *SyncService) checkOtherAchievements(activity *store.Activity, result *SyncResult, progress chan<- SyncProgress) {
+// candidateRecord is a performance offered as its category's record
+type candidateRecord struct {
+	pr   *store.PersonalRecord
+	mode store.CompareMode
+}
+
+// summaryRecords lists the records an activity's summary competes for: its
+// race distance, longest run, highest elevation and fastest average pace
+func summaryRecords(activity *store.Activity) []candidateRecord {
 	pacePerMile := analysis.CalculatePacePerMile(activity.Distance, activity.MovingTime)
+	record := func(category string, distance float64, mode store.CompareMode) candidateRecord {
+		pace := pacePerMile
+		return candidateRecord{mode: mode, pr: &store.PersonalRecord{
+			Category:        category,
+			ActivityID:      activity.ID,
+			DistanceMeters:  distance,
+			DurationSeconds: activity.MovingTime,
+			PacePerMile:     &pace,
+			AvgHeartrate:    activity.AverageHeartrate,
+			AchievedAt:      activity.StartDate,
+		}}
+	}
+
+	var records []candidateRecord
+	if category, _, matches := analysis.GetMatchingRaceCategory(activity.Distance); matches {
+		records = append(records, record(category, activity.Distance, store.CompareDuration))
+	}
 
 	// Longest run - compare by distance
-	s.upsertAchievement("longest_run", activity, activity.Distance, pacePerMile, store.CompareDistance, result, progress)
+	records = append(records, record("longest_run", activity.Distance, store.CompareDistance))
 
 	// Highest elevation - compare by elevation (stored in distance field)
-	s.upsertAchievement("highest_elevation", activity, activity.TotalElevationGain, pacePerMile, store.CompareDistance, result, progress)
+	records = append(records, record("highest_elevation", activity.TotalElevationGain, store.CompareDistance))
 
 	// Fastest pace - compare by pace (only for runs > 1 mile)
 	if activity.Distance >= analysis.Distance1Mile {
-		s.upsertAchievement("fastest_pace", activity, activity.Distance, pacePerMile, store.ComparePace, result, progress)
+		records = append(records, record("fastest_pace", activity.Distance, store.ComparePace))
+	}
+	return records
+}
+
+// offerRecord saves pr as its category's record if it beats the standing
+// one, reporting whether it did
+func (s *SyncService) offerRecord(pr *store.PersonalRecord, mode store.CompareMode, activity *store.Activity, result *SyncResult, progress chan<- SyncProgress) bool {
+	updated, err := s.store.UpsertPersonalRecordWithMode(pr, mode)
+	if err != nil {
+		upsertErr := fmt.Errorf("saving %s PR for %d: %w", pr.Category, activity.ID, err)
+		result.fail(progress, "personal_records", activity.ID, activity.Name, upsertErr)
+		return false
+	}
+	return updated
+}
+
+// detectRaces lists runs at a near-exact race distance and a much faster
+// pace than the athlete's median as races. Runs already listed, including
+// dismissed ones, keep their entry.
+func (s *SyncService) detectRaces(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
+	if progress != nil {
+		progress <- SyncProgress{Phase: "races", Total: 1, Completed: 0}
+	}
+
+	var runs []store.Activity
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		if err := s.checkpoint(ctx); err != nil {
+			return err
+		}
+
+		activities, err := s.store.ListActivities(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return fmt.Errorf("listing activities: %w", err)
+		}
+		for _, a := range activities {
+			if !a.Excluded {
+				runs = append(runs, a)
+			}
+		}
+		if len(activities) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+
+	paces := make([]float64, len(runs))
+	for i, a := range runs {
+		paces[i] = analysis.CalculatePacePerMile(a.Distance, a.MovingTime)
+	}
+	median := analysis.MedianPace(paces)
+
+	for i := range runs {
+		if analysis.IsLikelyRace(runs[i].Distance, runs[i].MovingTime, median) {
+			s.markDetectedRace(&runs[i], progress, result)
+		}
+	}
+
+	if progress != nil {
+		progress <- SyncProgress{Phase: "races", Total: 1, Completed: 1}
 	}
+	return nil
 }
 
-// upsertAchievement creates or updates a PR using the specified comparison mode
-func (s *SyncService) upsertAchievement(category string, activity *store.Activity, distance, pace float64, mode store.CompareMode, result *SyncResult, progress chan<- SyncProgress) {
-	pr := &store.PersonalRecord{
-		Category:        category,
-		ActivityID:      activity.ID,
-		DistanceMeters:  distance,
-		DurationSeconds: activity.MovingTime,
-		PacePerMile:     &pace,
-		AvgHeartrate:    activity.AverageHeartrate,
-		AchievedAt:      activity.StartDate,
+// markDetectedRace lists an activity as a detected race unless it's already
+// listed
+func (s *SyncService) markDetectedRace(activity *store.Activity, progress chan<- SyncProgress, result *SyncResult) {
+	added, err := s.store.MarkRace(activity.ID, store.RaceSourceDetected)
+	if err != nil {
+		raceErr := fmt.Errorf("marking race %d: %w", activity.ID, err)
+		result.fail(progress, "races", activity.ID, activity.Name, raceErr)
+		return
 	}
-	if updated, err := s.store.UpsertPersonalRecordWithMode(pr, mode); err != nil {
-		upsertErr := fmt.Errorf("saving %s PR: %w", category, err)
-		result.Errors = append(result.Errors, upsertErr)
-		reportError(progress, "personal_records", upsertErr)
-	} else if updated {
-		result.PRsComputed++
+	if added {
+		result.RacesFound++
 	}
 }
 
@@ -492,8 +1408,20 @@ func (s *SyncService) computeRacePredictions(ctx context.Context, progress chan<
 		return nil
 	}
 
-	// Select the best source PR for predictions
-	sourcePR := analysis.SelectBestSourcePR(prs)
+	races, err := s.store.GetRaces()
+	if err != nil {
+		return fmt.Errorf("getting races: %w", err)
+	}
+	raceIDs := make(map[int64]bool, len(races))
+	for _, r := range races {
+		raceIDs[r.ActivityID] = true
+	}
+
+	// Unverified efforts may be GPS errors, so they don't set predictions
+	prs = slices.DeleteFunc(prs, func(pr store.PersonalRecord) bool { return pr.Unverified })
+
+	// Select the best source PR for predictions, preferring races
+	sourcePR := analysis.SelectBestSourcePRWithRaces(prs, raceIDs)
 	if sourcePR == nil {
 		// No suitable PR found (all too old or wrong category)
 		return nil
@@ -534,8 +1462,7 @@ func (s *SyncService) computeRacePredictions(ctx context.Context, progress chan<
 
 		if err := s.store.UpsertRacePrediction(storePred); err != nil {
 			predErr := fmt.Errorf("saving prediction for %s: %w", pred.TargetName, err)
-			result.Errors = append(result.Errors, predErr)
-			reportError(progress, "predictions", predErr)
+			result.fail(progress, "predictions", 0, "", predErr)
 			continue
 		}
 		result.PredictionsComputed++
@@ -553,6 +1480,71 @@ func (s *SyncService) RateLimitStatus() (shortRemaining, dailyRemaining int) {
 	return s.client.RateLimitStatus()
 }
 
+// RateLimits returns the request limits of the 15-minute and daily windows
+func (s *SyncService) RateLimits() (shortLimit, dailyLimit int) {
+	return s.client.RateLimits()
+}
+
+// RateLimitWait returns when a sync held back by the rate limit will carry
+// on, or the zero time if it isn't waiting
+func (s *SyncService) RateLimitWait() time.Time {
+	return s.client.RateLimitWait()
+}
+
+// RequestCount returns how many API requests have been made so far
+func (s *SyncService) RequestCount() int {
+	return s.client.RequestCount()
+}
+
+// RecentRequests returns the client's latest API requests, oldest first
+func (s *SyncService) RecentRequests() []strava.RequestRecord {
+	return s.client.RecentRequests()
+}
+
+// Pause holds a running sync at the next activity boundary until Resume is
+// called. Work already done is kept.
+func (s *SyncService) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resume == nil {
+		s.resume = make(chan struct{})
+	}
+}
+
+// Resume lets a paused sync carry on
+func (s *SyncService) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resume != nil {
+		close(s.resume)
+		s.resume = nil
+	}
+}
+
+// Paused reports whether syncs are paused
+func (s *SyncService) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resume != nil
+}
+
+// checkpoint is called between items of work. It blocks while the sync is
+// paused and returns the context's error once it's cancelled.
+func (s *SyncService) checkpoint(ctx context.Context) error {
+	s.mu.Lock()
+	resume := s.resume
+	s.mu.Unlock()
+
+	if resume != nil {
+		select {
+		case <-resume:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ctx.Err()
+}
+
 // convertActivity converts a Strava API activity to a store activity
 func convertActivity(a strava.Activity) *store.Activity {
 	activity := &store.Activity{
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"math"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// importedBestEfforts returns Strava's best efforts for an activity when
+// they are the PR source. Runs trimmed or corrected locally no longer match
+// what Strava measured, so they get none.
+func (s *SyncService) importedBestEfforts(activity *store.Activity) ([]store.StravaBestEffort, error) {
+	if !s.stravaEfforts {
+		return nil, nil
+	}
+	trim, err := s.store.GetActivityTrim(activity.ID)
+	if err != nil {
+		return nil, err
+	}
+	correction, err := s.store.GetDistanceCorrection(activity.ID)
+	if err != nil {
+		return nil, err
+	}
+	if trim != nil || correction != nil {
+		return nil, nil
+	}
+	return s.store.GetStravaBestEfforts(activity.ID)
+}
+
+// bestEffort returns an activity's best effort over targetDist: Strava's
+// when one was imported, otherwise the fastest stretch of the streams.
+// disputed reports that the two disagree by more than StravaEffortTolerance.
+func bestEffort(streams, recorded []store.StreamPoint, imported []store.StravaBestEffort, targetDist float64) (effort *analysis.BestEffort, disputed bool) {
+	found := analysis.FindBestEffort(streams, targetDist)
+	fromStrava := stravaEffort(recorded, imported, targetDist)
+	if fromStrava == nil {
+		return found, false
+	}
+	if found != nil {
+		diff := math.Abs(float64(found.DurationSeconds - fromStrava.DurationSeconds))
+		disputed = diff > float64(fromStrava.DurationSeconds)*StravaEffortTolerance
+	}
+	return fromStrava, disputed
+}
+
+// stravaEffort converts Strava's best effort over targetDist, if imported,
+// using the recorded streams it indexes for offsets and heart rate. Strava
+// names its distances, so they are matched to ours within a couple of meters.
+func stravaEffort(recorded []store.StreamPoint, imported []store.StravaBestEffort, targetDist float64) *analysis.BestEffort {
+	for _, e := range imported {
+		if math.Abs(e.Distance-targetDist) > 2 {
+			continue
+		}
+		if e.StartIndex < 0 || e.StartIndex >= e.EndIndex || e.EndIndex >= len(recorded) {
+			return nil // streams changed since Strava measured it
+		}
+
+		effort := &analysis.BestEffort{
+			DistanceMeters:  e.Distance,
+			DurationSeconds: e.ElapsedTime,
+			StartOffset:     recorded[e.StartIndex].TimeOffset,
+			EndOffset:       recorded[e.EndIndex].TimeOffset,
+		}
+		var hrSum float64
+		var hrCount int
+		for _, p := range recorded[e.StartIndex : e.EndIndex+1] {
+			if p.Heartrate != nil && *p.Heartrate > 0 {
+				hrSum += float64(*p.Heartrate)
+				hrCount++
+			}
+		}
+		if hrCount > 0 {
+			effort.AvgHeartrate = hrSum / float64(hrCount)
+		}
+		return effort
+	}
+	return nil
+}
+
+// convertBestEfforts converts Strava's best efforts for storage
+func convertBestEfforts(efforts []strava.BestEffort) []store.StravaBestEffort {
+	converted := make([]store.StravaBestEffort, 0, len(efforts))
+	for _, e := range efforts {
+		converted = append(converted, store.StravaBestEffort{
+			Name:        e.Name,
+			Distance:    e.Distance,
+			ElapsedTime: e.ElapsedTime,
+			MovingTime:  e.MovingTime,
+			StartIndex:  e.StartIndex,
+			EndIndex:    e.EndIndex,
+		})
+	}
+	return converted
+}
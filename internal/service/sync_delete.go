@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// DeleteActivity deletes a run with everything stored for it and brings
+// what it counted toward up to date: its week's summary, fitness trends and
+// race predictions. Each record it held goes to the best remaining run.
+// Later syncs leave it out.
+func (s *SyncService) DeleteActivity(ctx context.Context, activityID int64) (*SyncResult, error) {
+	result := &SyncResult{}
+	slog.Info("deleting activity", "activity_id", activityID)
+
+	activity, err := s.store.GetActivity(activityID)
+	if err != nil {
+		return result, fmt.Errorf("getting activity %d: %w", activityID, err)
+	}
+	held, err := s.store.GetPersonalRecordsForActivity(activityID)
+	if err != nil {
+		return result, fmt.Errorf("getting records for %d: %w", activityID, err)
+	}
+
+	if err := s.store.DeleteActivity(activityID); err != nil {
+		return result, fmt.Errorf("deleting activity %d: %w", activityID, err)
+	}
+
+	if err := s.updateWeeklySummaries(map[time.Time]bool{weekStartOf(s.store, *activity): true}); err != nil {
+		result.fail(nil, "metrics", 0, "", err)
+	}
+	s.updateFitnessTrends(nil, result)
+
+	if err := s.refillRecords(ctx, recordCategories(held), result); err != nil {
+		return result, err
+	}
+
+	if err := s.computeRacePredictions(ctx, nil, result); err != nil {
+		return result, fmt.Errorf("computing predictions: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return result, result.Errors[0]
+	}
+	return result, nil
+}
+
+// repairRecords recomputes the categories whose record or history still
+// points at a run that was deleted or excluded, or whose record is missing
+// while its history remains. Each is cleared and rebuilt from the runs
+// stored.
+func (s *SyncService) repairRecords(ctx context.Context, result *SyncResult) error {
+	stale, err := s.store.StaleRecordCategories()
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	slog.Info("recomputing stale records", "categories", stale)
+
+	if err := s.store.ClearRecords(stale); err != nil {
+		return err
+	}
+	if err := s.refillRecords(ctx, stale, result); err != nil {
+		return err
+	}
+	result.RecordsRepaired += len(stale)
+	return nil
+}
+
+// recordCategories returns the category of each record
+func recordCategories(records []store.PersonalRecord) []string {
+	categories := make([]string, len(records))
+	for i, pr := range records {
+		categories[i] = pr.Category
+	}
+	return categories
+}
+
+// refillRecords finds the record in each category from the runs stored,
+// for categories whose record was lost. Every run is offered again, so the
+// PR history regains the improvements the lost record had hidden. Best
+// efforts come from those cached when each run was analyzed, without
+// reading streams.
+func (s *SyncService) refillRecords(ctx context.Context, categories []string, result *SyncResult) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	vacant := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		vacant[c] = true
+	}
+
+	// Runs eligible for records, as analyzeActivityPRs picks them
+	eligible := make(map[int64]*store.Activity)
+	for offset := 0; ; offset += PeriodStatsActivityLimit {
+		if err := s.checkpoint(ctx); err != nil {
+			return err
+		}
+		page, err := s.store.ListActivities(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return fmt.Errorf("listing activities: %w", err)
+		}
+		for i := range page {
+			a := &page[i]
+			if !a.StreamsSynced || a.Excluded || (s.excludeFlagged && s.hasAnomalies(a.ID)) {
+				continue
+			}
+			eligible[a.ID] = a
+			for _, c := range summaryRecords(a) {
+				if vacant[c.pr.Category] {
+					s.offerRecord(c.pr, c.mode, a, result, nil)
+				}
+			}
+		}
+		if len(page) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+
+	for _, category := range analysis.EffortCategories {
+		if !vacant[category] {
+			continue
+		}
+		efforts, err := s.store.GetTopEfforts(category, len(eligible))
+		if err != nil {
+			return fmt.Errorf("getting %s efforts: %w", category, err)
+		}
+		for _, e := range efforts {
+			a := eligible[e.ActivityID]
+			if a == nil {
+				continue
+			}
+			pacePerMile := analysis.CalculatePacePerMile(e.DistanceMeters, e.DurationSeconds)
+			startOffset, endOffset := e.StartOffset, e.EndOffset
+			pr := &store.PersonalRecord{
+				Category:        category,
+				ActivityID:      a.ID,
+				DistanceMeters:  e.DistanceMeters,
+				DurationSeconds: e.DurationSeconds,
+				PacePerMile:     &pacePerMile,
+				AvgHeartrate:    e.AvgHeartrate,
+				AchievedAt:      a.StartDate,
+				StartOffset:     &startOffset,
+				EndOffset:       &endOffset,
+				Unverified:      e.Unverified,
+			}
+			s.offerRecord(pr, store.CompareDuration, a, result, nil)
+		}
+	}
+	return nil
+}
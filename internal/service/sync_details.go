@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// syncDetails fetches the detailed activity for runs whose streams are
+// synced, up to StreamBatchSize of them, for the description, device, shoes,
+// calories, splits and best efforts the activity list leaves out
+func (s *SyncService) syncDetails(ctx context.Context, progress chan<- SyncProgress, result *SyncResult) error {
+	ids, err := s.store.GetActivitiesNeedingDetails(StreamBatchSize)
+	if err != nil {
+		return fmt.Errorf("getting activities needing details: %w", err)
+	}
+
+	for i, id := range ids {
+		if err := s.checkpoint(ctx); err != nil {
+			return err
+		}
+
+		activity, err := s.store.GetActivity(id)
+		if err != nil {
+			result.fail(progress, "details", id, "", fmt.Errorf("getting activity %d: %w", id, err))
+			continue
+		}
+		if progress != nil {
+			progress <- SyncProgress{
+				Phase:           "streams",
+				Total:           len(ids),
+				Completed:       i,
+				CurrentActivity: activity.Name,
+			}
+		}
+		if s.fetchDetails(ctx, *activity, progress, result) {
+			result.DetailsFetched++
+		}
+	}
+
+	if progress != nil && len(ids) > 0 {
+		progress <- SyncProgress{Phase: "streams", Total: len(ids), Completed: len(ids)}
+	}
+	return nil
+}
+
+// fetchDetails fetches and stores one run's detailed activity, reporting
+// whether it succeeded
+func (s *SyncService) fetchDetails(ctx context.Context, activity store.Activity, progress chan<- SyncProgress, result *SyncResult) bool {
+	detail, err := s.client.GetActivity(ctx, activity.ID)
+	if err != nil {
+		fetchErr := fmt.Errorf("activity %d (%s): %w", activity.ID, activity.Name, err)
+		result.fail(progress, "details", activity.ID, activity.Name, fetchErr)
+		return false
+	}
+	if err := s.saveDetails(*detail); err != nil {
+		saveErr := fmt.Errorf("saving details for %d: %w", activity.ID, err)
+		result.fail(progress, "details", activity.ID, activity.Name, saveErr)
+		return false
+	}
+	return true
+}
+
+// saveDetails stores the fields of a detailed activity that the activity
+// list leaves out, and its up-to-date kudos and comment counts
+func (s *SyncService) saveDetails(a strava.Activity) error {
+	details := store.ActivityDetails{
+		ActivityID:  a.ID,
+		Description: a.Description,
+		DeviceName:  a.DeviceName,
+		Calories:    a.Calories,
+		GearID:      a.GearID,
+		FetchedAt:   time.Now(),
+	}
+	if a.Gear != nil {
+		details.GearName = a.Gear.Name
+	}
+
+	var splits []store.StravaSplit
+	splits = append(splits, convertSplits(store.SplitsMetric, a.SplitsMetric)...)
+	splits = append(splits, convertSplits(store.SplitsStandard, a.SplitsStandard)...)
+
+	if err := s.store.SaveActivityDetails(details, splits, convertBestEfforts(a.BestEfforts)); err != nil {
+		return err
+	}
+	if err := s.saveSocial(a, a.PrimaryPhotoURL()); err != nil {
+		return fmt.Errorf("saving kudos and comment counts: %w", err)
+	}
+	return nil
+}
+
+// convertSplits converts Strava's splits in units for storage
+func convertSplits(units string, splits []strava.Split) []store.StravaSplit {
+	converted := make([]store.StravaSplit, 0, len(splits))
+	for _, sp := range splits {
+		split := store.StravaSplit{
+			Units:               units,
+			Split:               sp.Split,
+			Distance:            sp.Distance,
+			ElapsedTime:         sp.ElapsedTime,
+			MovingTime:          sp.MovingTime,
+			ElevationDifference: sp.ElevationDifference,
+			AverageSpeed:        sp.AverageSpeed,
+		}
+		if sp.AverageHeartrate > 0 {
+			hr := sp.AverageHeartrate
+			split.AverageHeartrate = &hr
+		}
+		converted = append(converted, split)
+	}
+	return converted
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/store"
+)
+
+// Limits on typed corrections; anything outside is almost surely a typo
+const (
+	minDistanceFactor = 0.5
+	maxDistanceFactor = 2.0
+	minStrideLength   = 0.4 // meters per step
+	maxStrideLength   = 2.5
+)
+
+// strideSampleRuns is how many recent GPS runs the typical stride length
+// is taken from
+const strideSampleRuns = 10
+
+// DistanceFix is a requested distance correction for a treadmill or indoor
+// run
+type DistanceFix struct {
+	Method store.DistanceCorrectionMethod
+	Factor float64 // calibration factor, factor method only
+	Stride float64 // meters per step for the cadence method, 0 for the typical stride
+}
+
+// ParseDistanceFix reads a distance correction: a calibration factor such
+// as "1.05" when the run read 5% short, "cadence" (or "c") to rebuild the
+// distance from cadence and the typical stride of recent GPS runs, or
+// "cadence 1.1" with a stride length in meters.
+func ParseDistanceFix(input string) (DistanceFix, error) {
+	fields := strings.Fields(strings.ToLower(input))
+	if len(fields) == 0 {
+		return DistanceFix{}, errors.New("enter a factor such as 1.05, or cadence")
+	}
+
+	if fields[0] == "c" || fields[0] == "cadence" {
+		fix := DistanceFix{Method: store.CorrectionCadence}
+		switch len(fields) {
+		case 1:
+			return fix, nil
+		case 2:
+			stride, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "m"), 64)
+			if err != nil || stride < minStrideLength || stride > maxStrideLength {
+				return DistanceFix{}, fmt.Errorf("invalid stride length %q, use meters per step such as 1.1", fields[1])
+			}
+			fix.Stride = stride
+			return fix, nil
+		}
+		return DistanceFix{}, fmt.Errorf("invalid correction %q", input)
+	}
+
+	if len(fields) != 1 {
+		return DistanceFix{}, fmt.Errorf("invalid correction %q", input)
+	}
+	factor, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || factor < minDistanceFactor || factor > maxDistanceFactor {
+		return DistanceFix{}, fmt.Errorf("invalid factor %q, use a number such as 1.05", fields[0])
+	}
+	return DistanceFix{Method: store.CorrectionFactor, Factor: factor}, nil
+}
+
+// CorrectDistance fixes the distance of a run without GPS, such as a
+// treadmill run, by scaling its speed and distance streams or rebuilding
+// them from cadence. The summary, metrics and personal records are then
+// recomputed and the correction is recorded, so later syncs keep it.
+// Nothing is sent to Strava; ResyncActivity restores the original.
+func (s *SyncService) CorrectDistance(ctx context.Context, activityID int64, fix DistanceFix) (*SyncResult, error) {
+	existing, err := s.store.GetActivity(activityID)
+	if err != nil {
+		return nil, fmt.Errorf("getting activity %d: %w", activityID, err)
+	}
+	streams, err := s.store.GetStreams(activityID)
+	if err != nil {
+		return nil, fmt.Errorf("getting streams for %d: %w", activityID, err)
+	}
+	if len(streams) < 2 {
+		return nil, fmt.Errorf("activity %d has no streams to correct", activityID)
+	}
+	if analysis.StartPoint(streams) != nil {
+		return nil, fmt.Errorf("activity %d has GPS; distance correction is for treadmill and indoor runs", activityID)
+	}
+
+	var edited []store.StreamPoint
+	var stride *float64
+	switch fix.Method {
+	case store.CorrectionFactor:
+		edited = analysis.ScaleDistance(streams, fix.Factor)
+	case store.CorrectionCadence:
+		if AggregateStreamStats(streams).CadenceCount == 0 {
+			return nil, fmt.Errorf("activity %d has no cadence to rebuild the distance from", activityID)
+		}
+		length := fix.Stride
+		if length == 0 {
+			if length, err = s.typicalStride(); err != nil {
+				return nil, err
+			}
+			if length == 0 {
+				return nil, errors.New("no recent GPS runs with cadence to learn your stride from; enter one, such as cadence 1.1")
+			}
+		}
+		stride = &length
+		edited = analysis.CadenceDistance(streams, length)
+	default:
+		return nil, fmt.Errorf("unknown distance correction %q", fix.Method)
+	}
+
+	slog.Info("correcting activity distance", "activity_id", activityID, "method", fix.Method)
+	return s.replaceStreams(ctx, *existing, streams, edited, func() error {
+		err := s.store.SetDistanceCorrection(store.DistanceCorrection{
+			ActivityID:       activityID,
+			Method:           fix.Method,
+			StrideLength:     stride,
+			OriginalDistance: existing.Distance,
+			CorrectedAt:      time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("recording distance correction for %d: %w", activityID, err)
+		}
+		return nil
+	})
+}
+
+// typicalStride returns the median stride length (meters per step) of the
+// most recent runs with GPS, or 0 when there are none
+func (s *SyncService) typicalStride() (float64, error) {
+	var strides []float64
+	for offset := 0; len(strides) < strideSampleRuns; offset += PeriodStatsActivityLimit {
+		activities, metrics, err := s.store.GetActivitiesWithMetrics(PeriodStatsActivityLimit, offset)
+		if err != nil {
+			return 0, fmt.Errorf("getting activities: %w", err)
+		}
+		for i, m := range metrics {
+			if m.AvgStrideLength == nil || *m.AvgStrideLength <= 0 {
+				continue
+			}
+			streams, err := s.store.GetStreamSeries(activities[i].ID, store.StreamLatLng)
+			if err != nil {
+				return 0, fmt.Errorf("getting streams for %d: %w", activities[i].ID, err)
+			}
+			if analysis.StartPoint(streams) == nil {
+				continue
+			}
+			if strides = append(strides, *m.AvgStrideLength); len(strides) == strideSampleRuns {
+				break
+			}
+		}
+		if len(activities) < PeriodStatsActivityLimit {
+			break
+		}
+	}
+	return median(strides), nil
+}
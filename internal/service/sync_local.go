@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"runner/internal/store"
+)
+
+// replaceStreams saves locally edited streams for an activity, recomputes
+// its summary from them and then its metrics, weekly summary, fitness
+// trends and personal records. record saves what was edited once the new
+// summary is stored, so later syncs keep it.
+func (s *SyncService) replaceStreams(ctx context.Context, existing store.Activity, streams, edited []store.StreamPoint, record func() error) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	if err := s.store.SaveStreams(existing.ID, edited); err != nil {
+		return result, fmt.Errorf("saving edited streams for %d: %w", existing.ID, err)
+	}
+	updated := editedSummary(existing, streams, edited)
+	if err := s.store.UpsertActivity(&updated); err != nil {
+		return result, fmt.Errorf("saving edited activity %d: %w", existing.ID, err)
+	}
+	if err := record(); err != nil {
+		return result, err
+	}
+
+	if s.computeActivityMetrics(updated, nil, result) {
+		result.MetricsComputed++
+	}
+	if err := s.updateWeeklySummaries(map[time.Time]bool{weekStartOf(s.store, updated): true}); err != nil {
+		result.fail(nil, "metrics", 0, "", err)
+	}
+	s.updateFitnessTrends(nil, result)
+
+	// The unedited run's records may have come from the bad stretch; the
+	// other runs compete for them again
+	held, err := s.store.GetPersonalRecordsForActivity(existing.ID)
+	if err != nil {
+		return result, fmt.Errorf("getting records for %d: %w", existing.ID, err)
+	}
+	if err := s.store.DeletePersonalRecordsForActivity(existing.ID); err != nil {
+		return result, fmt.Errorf("clearing records for %d: %w", existing.ID, err)
+	}
+	if err := s.store.DeleteDurationEfforts(existing.ID); err != nil {
+		return result, fmt.Errorf("clearing duration efforts for %d: %w", existing.ID, err)
+	}
+	s.analyzeActivityPRs(&updated, nil, result)
+	if err := s.refillRecords(ctx, recordCategories(held), result); err != nil {
+		return result, err
+	}
+
+	if err := s.computeRacePredictions(ctx, nil, result); err != nil {
+		return result, fmt.Errorf("computing predictions: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return result, result.Errors[0]
+	}
+	return result, nil
+}
+
+// editedSummary recomputes an activity's summary from its edited streams.
+// Strava's elevation gain is scaled by the share of the climbing that was
+// kept, since summing the raw altitude stream overcounts. The suffer score
+// covered the original recording and is dropped.
+func editedSummary(a store.Activity, streams, edited []store.StreamPoint) store.Activity {
+	stats := AggregateStreamStats(edited)
+	last := edited[len(edited)-1]
+
+	a.ElapsedTime = last.TimeOffset - edited[0].TimeOffset
+	a.MovingTime = stats.MovingTime
+	if a.MovingTime == 0 {
+		a.MovingTime = a.ElapsedTime
+	}
+	a.Distance = stats.TotalDistance
+	if a.Distance == 0 {
+		for i := 1; i < len(edited); i++ {
+			if v := edited[i].VelocitySmooth; v != nil {
+				a.Distance += *v * float64(edited[i].TimeOffset-edited[i-1].TimeOffset)
+			}
+		}
+	}
+	a.AverageSpeed = 0
+	if a.MovingTime > 0 {
+		a.AverageSpeed = a.Distance / float64(a.MovingTime)
+	}
+
+	a.MaxSpeed = 0
+	for _, p := range edited {
+		if p.VelocitySmooth != nil && *p.VelocitySmooth > a.MaxSpeed {
+			a.MaxSpeed = *p.VelocitySmooth
+		}
+	}
+
+	a.AverageHeartrate, a.MaxHeartrate = nil, nil
+	if stats.HRCount > 0 {
+		avg, peak := stats.AvgHR(), float64(stats.MaxHR)
+		a.AverageHeartrate, a.MaxHeartrate = &avg, &peak
+	}
+	// Strava's average cadence counts one foot, like the stream
+	a.AverageCadence = nil
+	if stats.CadenceCount > 0 {
+		avg := stats.AvgCadence() / StravaCadenceMultiplier
+		a.AverageCadence = &avg
+	}
+
+	if full := altitudeClimb(streams); full > 0 {
+		a.TotalElevationGain *= altitudeClimb(edited) / full
+	}
+	a.SufferScore = nil
+	return a
+}
+
+// altitudeClimb sums the rises in the altitude stream
+func altitudeClimb(streams []store.StreamPoint) float64 {
+	var climb float64
+	var prev *float64
+	for _, p := range streams {
+		if p.Altitude == nil {
+			continue
+		}
+		if prev != nil && *p.Altitude > *prev {
+			climb += *p.Altitude - *prev
+		}
+		prev = p.Altitude
+	}
+	return climb
+}
+
+// keepLocalSummary replaces a fetched activity's distance, times and
+// averages with the stored ones when the activity was trimmed or had its
+// distance corrected locally, so a sync doesn't undo the edit
+func (s *SyncService) keepLocalSummary(a *store.Activity) error {
+	trim, err := s.store.GetActivityTrim(a.ID)
+	if err != nil {
+		return err
+	}
+	correction, err := s.store.GetDistanceCorrection(a.ID)
+	if err != nil {
+		return err
+	}
+	if trim == nil && correction == nil {
+		return nil
+	}
+
+	stored, err := s.store.GetActivity(a.ID)
+	if err != nil {
+		return err
+	}
+	a.Distance = stored.Distance
+	a.MovingTime = stored.MovingTime
+	a.ElapsedTime = stored.ElapsedTime
+	a.TotalElevationGain = stored.TotalElevationGain
+	a.AverageSpeed = stored.AverageSpeed
+	a.MaxSpeed = stored.MaxSpeed
+	a.AverageHeartrate = stored.AverageHeartrate
+	a.MaxHeartrate = stored.MaxHeartrate
+	a.AverageCadence = stored.AverageCadence
+	a.SufferScore = stored.SufferScore
+	return nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"runner/internal/store"
+)
+
+// syncLockHeartbeat is how often a running sync refreshes its lock, well
+// inside store.SyncLockTTL
+const syncLockHeartbeat = 30 * time.Second
+
+// lockOwner names this process in the sync lock, e.g. "laptop:4182"
+func lockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// acquireSyncLock takes the sync lock so a scheduled sync and the TUI don't
+// sync at once, and keeps it live until the returned release is called. It
+// fails with a *store.SyncLockedError if another process is syncing.
+func (s *SyncService) acquireSyncLock(ctx context.Context) (release func(), err error) {
+	owner := lockOwner()
+	if err := s.store.AcquireSyncLock(owner, time.Now()); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(syncLockHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.store.RefreshSyncLock(owner, time.Now()); err != nil {
+					slog.Warn("refreshing sync lock", "error", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		if err := s.store.ReleaseSyncLock(owner); err != nil {
+			slog.Warn("releasing sync lock", "error", err)
+		}
+	}, nil
+}
+
+// SyncElsewhere returns the lock of a sync running in another process, or
+// nil if none is
+func (s *SyncService) SyncElsewhere() (*store.SyncLock, error) {
+	lock, err := s.store.GetSyncLock(time.Now())
+	if err != nil || lock == nil || lock.Owner == lockOwner() {
+		return nil, err
+	}
+	return lock, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"runner/internal/strava"
+)
+
+// SyncPreview is a dry run of a sync: what it would fetch and what that
+// would cost in API requests, worked out from the first activities page
+type SyncPreview struct {
+	// Since is when activities were last synced, zero on a first sync
+	Since time.Time
+
+	// Activities on the first page, and the runs with HR among them that
+	// would be stored. MorePages is set when the page was full, so the
+	// counts are lower bounds.
+	Activities int
+	NewRuns    int
+	MorePages  bool
+
+	// Runs already stored that are waiting for streams, the streams this
+	// sync would download, and the syncs needed to download them all
+	StreamsPending  int
+	StreamsThisSync int
+	SyncsToFinish   int
+
+	// Detailed activities this sync would fetch for runs with streams
+	DetailsThisSync int
+
+	// Requests the sync would make, and the rate limit budget left after
+	// the preview's own request
+	Requests       int
+	ShortRemaining int
+	DailyRemaining int
+	ShortLimit     int
+	DailyLimit     int
+
+	// Duration is the estimated sync time, including any waits for the
+	// 15-minute window to reset
+	Duration time.Duration
+}
+
+// ExceedsDaily reports whether the sync would run out of daily requests
+func (p SyncPreview) ExceedsDaily() bool {
+	return p.Requests > p.DailyRemaining
+}
+
+// Preview fetches the first page of new activities and estimates the cost
+// of a sync without storing anything. It costs one API request.
+func (s *SyncService) Preview(ctx context.Context) (*SyncPreview, error) {
+	preview := &SyncPreview{}
+	if lastSyncStr, _ := s.store.GetSyncState("last_activity_sync"); lastSyncStr != "" {
+		// A corrupt value means the sync starts from the beginning
+		preview.Since, _ = time.Parse(time.RFC3339, lastSyncStr)
+	}
+
+	activities, err := s.client.GetActivities(ctx, preview.Since, 1, ActivitiesPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("fetching first page: %w", err)
+	}
+	preview.Activities = len(activities)
+	preview.MorePages = len(activities) == ActivitiesPerPage
+
+	for _, a := range activities {
+		if a.Type != "Run" || !a.HasHeartrate {
+			continue
+		}
+		if stored, err := s.store.GetActivity(a.ID); err == nil && stored.StreamsSynced {
+			continue
+		}
+		preview.NewRuns++
+	}
+
+	preview.StreamsPending, err = s.store.CountActivitiesNeedingStreams()
+	if err != nil {
+		return nil, fmt.Errorf("counting activities needing streams: %w", err)
+	}
+
+	// New runs can overlap runs already waiting for streams when the last
+	// sync was interrupted, so this errs high
+	streams := preview.StreamsPending + preview.NewRuns
+	preview.StreamsThisSync = min(streams, StreamBatchSize)
+	preview.SyncsToFinish = (streams + StreamBatchSize - 1) / StreamBatchSize
+
+	// Runs whose streams arrive in this sync need details too
+	detailsPending, err := s.store.CountActivitiesNeedingDetails()
+	if err != nil {
+		return nil, fmt.Errorf("counting activities needing details: %w", err)
+	}
+	preview.DetailsThisSync = min(detailsPending+preview.StreamsThisSync, StreamBatchSize)
+
+	// The sync fetches pages until one comes back short
+	pages := 1
+	if preview.MorePages {
+		pages = 2
+	}
+	preview.Requests = pages + preview.StreamsThisSync + preview.DetailsThisSync
+
+	preview.ShortRemaining, preview.DailyRemaining = s.client.RateLimitStatus()
+	preview.ShortLimit, preview.DailyLimit = s.client.RateLimits()
+	preview.Duration = estimateSyncDuration(preview.Requests, preview.ShortRemaining, preview.ShortLimit)
+
+	return preview, nil
+}
+
+// estimateSyncDuration estimates how long requests take, waiting out a
+// 15-minute window each time the short limit is used up
+func estimateSyncDuration(requests, shortRemaining, shortLimit int) time.Duration {
+	d := time.Duration(requests) * EstimatedRequestMillis * time.Millisecond
+	if over := requests - max(shortRemaining, 0); over > 0 && shortLimit > 0 {
+		windows := (over + shortLimit - 1) / shortLimit
+		d += time.Duration(windows) * strava.ShortWindow
+	}
+	return d
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"log/slog"
+
+	"runner/internal/store"
+)
+
+// NewRecord is a personal record set during a sync. Previous is the record
+// it beat as it stood before the sync, or nil for a first record.
+type NewRecord struct {
+	Record       store.PersonalRecord
+	Previous     *store.PersonalRecord
+	Mode         store.CompareMode
+	ActivityName string
+}
+
+// Label names the record's category, e.g. "5K" or "Longest Run"
+func (r NewRecord) Label() string {
+	return formatCategoryLabel(r.Record.Category)
+}
+
+// Margin returns how much the record improved on the previous one: seconds
+// faster, meters further, or seconds per mile faster depending on Mode. It is
+// zero for a first record.
+func (r NewRecord) Margin() float64 {
+	if r.Previous == nil {
+		return 0
+	}
+	switch r.Mode {
+	case store.CompareDistance:
+		return r.Record.DistanceMeters - r.Previous.DistanceMeters
+	case store.ComparePace:
+		if r.Record.PacePerMile == nil || r.Previous.PacePerMile == nil {
+			return 0
+		}
+		return *r.Previous.PacePerMile - *r.Record.PacePerMile
+	default:
+		return float64(r.Previous.DurationSeconds - r.Record.DurationSeconds)
+	}
+}
+
+// noteRecord counts a record the sync improved and keeps it for the summary.
+// A category improved twice in one sync is listed once, with the latest
+// record and the one that stood before the sync.
+func (s *SyncService) noteRecord(result *SyncResult, pr *store.PersonalRecord, mode store.CompareMode, activityName string) {
+	result.PRsComputed++
+	for i, r := range result.NewRecords {
+		if r.Record.Category == pr.Category {
+			result.NewRecords[i].Record = *pr
+			result.NewRecords[i].ActivityName = activityName
+			return
+		}
+	}
+
+	// The PR history says what the record beat; without it the summary only
+	// loses the comparison
+	previous, err := s.store.GetPreviousRecord(pr.Category, pr.ActivityID)
+	if err != nil {
+		slog.Warn("looking up previous record", "category", pr.Category, "error", err)
+	}
+	result.NewRecords = append(result.NewRecords, NewRecord{
+		Record:       *pr,
+		Previous:     previous,
+		Mode:         mode,
+		ActivityName: activityName,
+	})
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// saveSocial stores a run's kudos, comment and photo counts when
+// privacy.sync_social is on. photoURL comes from the detailed activity; an
+// empty one keeps the URL already stored.
+func (s *SyncService) saveSocial(a strava.Activity, photoURL string) error {
+	if !s.syncSocial {
+		return nil
+	}
+	if !s.syncPhotos {
+		photoURL = ""
+	}
+	return s.store.SaveActivitySocial(store.ActivitySocial{
+		ActivityID:   a.ID,
+		KudosCount:   a.KudosCount,
+		CommentCount: a.CommentCount,
+		PhotoCount:   a.TotalPhotoCount,
+		PhotoURL:     photoURL,
+	})
+}
+
+// pruneSocial deletes what privacy settings no longer allow keeping: every
+// count with privacy.sync_social off, or the photo URLs with
+// privacy.sync_photos off
+func (s *SyncService) pruneSocial() error {
+	if !s.syncSocial {
+		if err := s.store.DeleteActivitySocial(); err != nil {
+			return fmt.Errorf("deleting kudos and comment counts: %w", err)
+		}
+		return nil
+	}
+	if !s.syncPhotos {
+		if err := s.store.ClearActivityPhotoURLs(); err != nil {
+			return fmt.Errorf("deleting photo URLs: %w", err)
+		}
+	}
+	return nil
+}
+
+// refreshSocial updates the counts of a run fetched again only because it
+// falls in the SocialRefreshDays window, reporting false if the run isn't
+// stored yet and so still needs storing
+func (s *SyncService) refreshSocial(a strava.Activity) (bool, error) {
+	if _, err := s.store.GetActivity(a.ID); errors.Is(err, store.ErrActivityNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, s.saveSocial(a, "")
+}
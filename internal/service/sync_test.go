@@ -0,0 +1,1382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/config"
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+func TestSyncService_RetryFailed(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Only local phases are retried here, so no Strava client is needed
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Morning Run", startDate, 5000, 1800, floatPtr(150))
+	createTestStreams(t, db, 1, 1800, 2.78, 150)
+
+	failures := []SyncFailure{
+		{Phase: "metrics", ActivityID: 1, ActivityName: "Morning Run", Err: errors.New("database is locked")},
+		{Phase: "metrics", ActivityID: 1, ActivityName: "Morning Run", Err: errors.New("database is locked")},
+		{Phase: "personal_records", ActivityID: 1, ActivityName: "Morning Run", Err: errors.New("database is locked")},
+		{Phase: "metrics", Err: errors.New("rebuilding weekly summaries")}, // not tied to an activity
+	}
+
+	result, err := svc.RetryFailed(context.Background(), failures)
+	if err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	if len(result.Failures) != 0 {
+		t.Fatalf("RetryFailed() failures = %v, want none", result.Failures)
+	}
+	if result.MetricsComputed != 1 {
+		t.Errorf("MetricsComputed = %d, want 1 (duplicates retried once)", result.MetricsComputed)
+	}
+
+	metrics, err := db.GetActivityMetrics(1)
+	if err != nil || metrics == nil || metrics.EfficiencyFactor == nil {
+		t.Fatalf("expected metrics with EF after retry, got %+v, %v", metrics, err)
+	}
+
+	prs, err := db.GetAllPersonalRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) == 0 {
+		t.Error("expected personal records after retrying PR analysis")
+	}
+
+	// A missing activity is reported again rather than dropped
+	result, err = svc.RetryFailed(context.Background(), []SyncFailure{{Phase: "metrics", ActivityID: 99, Err: errors.New("x")}})
+	if err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].ActivityID != 99 {
+		t.Errorf("RetryFailed() failures = %v, want one for activity 99", result.Failures)
+	}
+}
+
+func TestSyncService_ResyncActivity_Manual(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Manual runs are rejected before Strava is contacted
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	qs := NewQueryService(db, testAthleteConfig())
+
+	id, err := qs.AddManualActivity(ManualActivity{
+		Name:      "Treadmill",
+		StartTime: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		Distance:  5000,
+		Duration:  1800,
+	})
+	if err != nil {
+		t.Fatalf("AddManualActivity() error = %v", err)
+	}
+
+	if _, err := svc.ResyncActivity(context.Background(), id); err == nil {
+		t.Error("expected an error resyncing a manual activity")
+	}
+	if _, err := svc.ResyncActivity(context.Background(), 99); err == nil {
+		t.Error("expected an error resyncing a missing activity")
+	}
+}
+
+func TestSyncService_TrimActivity(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Trimming is local, so no Strava client is needed
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+
+	// 30 minutes running at 2.78 m/s, then 5 minutes driving home with the
+	// watch still recording
+	var points []store.StreamPoint
+	dist := 0.0
+	for i := 0; i < 2100; i++ {
+		speed, hr := 2.78, 150
+		if i >= 1800 {
+			speed, hr = 12, 95
+		}
+		if i > 0 {
+			dist += speed
+		}
+		d := dist
+		points = append(points, store.StreamPoint{ActivityID: 1, TimeOffset: i, VelocitySmooth: &speed, Heartrate: &hr, Distance: &d})
+	}
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Morning Run", startDate, dist, 2100, floatPtr(145))
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.TrimActivity(context.Background(), 1, 3000); err == nil {
+		t.Error("expected an error trimming after the end of the streams")
+	}
+	if _, err := svc.TrimActivity(context.Background(), 1, 0); err == nil {
+		t.Error("expected an error trimming away the whole run")
+	}
+
+	if _, err := svc.TrimActivity(context.Background(), 1, 1799); err != nil {
+		t.Fatalf("TrimActivity() error = %v", err)
+	}
+
+	a, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(a.Distance-1799*2.78) > 0.01 || a.MovingTime != 1799 || a.ElapsedTime != 1799 {
+		t.Errorf("trimmed distance/moving/elapsed = %.1f/%d/%d, want %.1f/1799/1799", a.Distance, a.MovingTime, a.ElapsedTime, 1799*2.78)
+	}
+	if a.AverageHeartrate == nil || *a.AverageHeartrate != 150 {
+		t.Errorf("trimmed average HR = %v, want 150", a.AverageHeartrate)
+	}
+	if n, _ := db.GetStreamCount(1); n != 1800 {
+		t.Errorf("stream points after trim = %d, want 1800", n)
+	}
+	if missing, _ := db.ListActivitiesMissingStreamStats(); len(missing) != 0 {
+		t.Errorf("activities %v missing stream stats after recomputing metrics", missing)
+	}
+
+	trim, err := db.GetActivityTrim(1)
+	if err != nil || trim == nil {
+		t.Fatalf("GetActivityTrim() = %v, %v, want a trim", trim, err)
+	}
+	if trim.EndOffset != 1799 || trim.OriginalDistance != dist || trim.OriginalElapsedTime != 2160 {
+		t.Errorf("trim = %+v, want end 1799 and the original distance and time", trim)
+	}
+
+	if m, err := db.GetActivityMetrics(1); err != nil || m == nil || m.EfficiencyFactor == nil {
+		t.Errorf("expected metrics recomputed after the trim, got %+v, %v", m, err)
+	}
+	// Records come from the run, not the drive
+	prs, err := db.GetAllPersonalRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) == 0 {
+		t.Fatal("expected personal records from the trimmed run")
+	}
+	for _, pr := range prs {
+		if pr.PacePerMile != nil && *pr.PacePerMile < 570 {
+			t.Errorf("%s record pace %.0f s/mi includes the drive", pr.Category, *pr.PacePerMile)
+		}
+	}
+
+	// A later sync keeps the trimmed summary
+	fetched := &store.Activity{ID: 1, Name: "Morning Run", Distance: dist, MovingTime: 2100, ElapsedTime: 2160}
+	if err := svc.keepLocalSummary(fetched); err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Distance != a.Distance || fetched.MovingTime != 1799 || fetched.Name != "Morning Run" {
+		t.Errorf("synced activity = %+v, want the trimmed summary", fetched)
+	}
+}
+
+func TestParseDistanceFix(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    DistanceFix
+		wantErr bool
+	}{
+		{"1.05", DistanceFix{Method: store.CorrectionFactor, Factor: 1.05}, false},
+		{" cadence ", DistanceFix{Method: store.CorrectionCadence}, false},
+		{"c 1.1m", DistanceFix{Method: store.CorrectionCadence, Stride: 1.1}, false},
+		{"", DistanceFix{}, true},
+		{"3", DistanceFix{}, true},
+		{"cadence 5", DistanceFix{}, true},
+		{"1.05 1.1", DistanceFix{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDistanceFix(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDistanceFix(%q) = %+v, %v, want %+v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSyncService_CorrectDistance(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Treadmill run: 30 minutes at 90 steps a minute per foot, with the
+	// watch reading 2.5 m/s
+	treadmill := func(id int64) {
+		var points []store.StreamPoint
+		for i := 0; i < 1800; i++ {
+			speed, dist, hr, cad := 2.5, float64(i)*2.5, 150, 90
+			points = append(points, store.StreamPoint{ActivityID: id, TimeOffset: i, VelocitySmooth: &speed, Distance: &dist, Heartrate: &hr, Cadence: &cad})
+		}
+		createTestActivity(t, db, id, "Treadmill", startDate, 1799*2.5, 1800, floatPtr(150))
+		if err := db.SaveStreams(id, points); err != nil {
+			t.Fatal(err)
+		}
+	}
+	treadmill(1)
+	treadmill(2)
+
+	// The watch read 4% short
+	if _, err := svc.CorrectDistance(context.Background(), 1, DistanceFix{Method: store.CorrectionFactor, Factor: 1.04}); err != nil {
+		t.Fatalf("CorrectDistance() error = %v", err)
+	}
+	a, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(a.Distance-1799*2.5*1.04) > 0.01 || math.Abs(a.AverageSpeed-a.Distance/float64(a.MovingTime)) > 1e-9 {
+		t.Errorf("corrected distance/speed = %.1f/%.3f, want %.1f", a.Distance, a.AverageSpeed, 1799*2.5*1.04)
+	}
+	c, err := db.GetDistanceCorrection(1)
+	if err != nil || c == nil || c.Method != store.CorrectionFactor || c.OriginalDistance != 1799*2.5 {
+		t.Errorf("GetDistanceCorrection() = %+v, %v, want a factor correction from %.1f m", c, err, 1799*2.5)
+	}
+	if m, err := db.GetActivityMetrics(1); err != nil || m == nil || m.EfficiencyFactor == nil {
+		t.Errorf("expected metrics recomputed after the correction, got %+v, %v", m, err)
+	}
+
+	// Without GPS runs there's no typical stride to use
+	if _, err := svc.CorrectDistance(context.Background(), 2, DistanceFix{Method: store.CorrectionCadence}); err == nil {
+		t.Error("expected an error without a stride to use")
+	}
+
+	// A GPS run at 3 m/s and 90 steps a minute per foot strides 1 m
+	var points []store.StreamPoint
+	for i := 0; i < 1800; i++ {
+		speed, dist, hr, cad, lat, lng := 3.0, float64(i)*3, 150, 90, 40.0, -105.0
+		points = append(points, store.StreamPoint{ActivityID: 3, TimeOffset: i, VelocitySmooth: &speed, Distance: &dist, Heartrate: &hr, Cadence: &cad, Lat: &lat, Lng: &lng})
+	}
+	createTestActivity(t, db, 3, "Road Run", startDate.Add(-24*time.Hour), 1799*3, 1800, floatPtr(150))
+	if err := db.SaveStreams(3, points); err != nil {
+		t.Fatal(err)
+	}
+	if !svc.computeActivityMetrics(store.Activity{ID: 3, Name: "Road Run"}, nil, &SyncResult{}) {
+		t.Fatal("expected metrics for the GPS run")
+	}
+
+	if _, err := svc.CorrectDistance(context.Background(), 3, DistanceFix{Method: store.CorrectionFactor, Factor: 1.1}); err == nil {
+		t.Error("expected an error correcting a GPS run")
+	}
+
+	if _, err := svc.CorrectDistance(context.Background(), 2, DistanceFix{Method: store.CorrectionCadence}); err != nil {
+		t.Fatalf("CorrectDistance() error = %v", err)
+	}
+	a, err = db.GetActivity(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(a.Distance-1799*3) > 0.5 {
+		t.Errorf("cadence distance = %.1f, want %.1f from a 1 m stride", a.Distance, 1799*3.0)
+	}
+	c, err = db.GetDistanceCorrection(2)
+	if err != nil || c == nil || c.StrideLength == nil || math.Abs(*c.StrideLength-1) > 1e-6 {
+		t.Errorf("GetDistanceCorrection() = %+v, %v, want cadence with a 1 m stride", c, err)
+	}
+}
+
+func TestSyncService_SmoothGPS(t *testing.T) {
+	// 20 minutes at 3 m/s with half a minute of GPS drift adding 20 m a
+	// second, which would be a world-record 400m
+	var points []store.StreamPoint
+	dist := 0.0
+	for i := 0; i < 1200; i++ {
+		speed := 3.0
+		if i >= 600 && i < 630 {
+			speed = 20
+		}
+		if i > 0 {
+			dist += speed
+		}
+		d, v := dist, speed
+		points = append(points, store.StreamPoint{ActivityID: 1, TimeOffset: i, VelocitySmooth: &v, Distance: &d})
+	}
+
+	best400 := func(cfg config.AnalysisConfig) int {
+		t.Helper()
+		db := openTestDB(t)
+		defer db.Close()
+		createTestActivity(t, db, 1, "Morning Run", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), dist, 1200, nil)
+		if err := db.SaveStreams(1, points); err != nil {
+			t.Fatal(err)
+		}
+		a, err := db.GetActivity(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		svc := NewSyncService(nil, db, testAthleteConfig(), cfg)
+		svc.analyzeActivityPRs(a, nil, &SyncResult{})
+		prs, err := db.GetAllPersonalRecords()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pr := range prs {
+			if pr.Category == "effort_400m" {
+				return pr.DurationSeconds
+			}
+		}
+		t.Fatal("no 400m record")
+		return 0
+	}
+
+	if secs := best400(config.AnalysisConfig{}); secs < 130 {
+		t.Errorf("smoothed 400m = %ds, want about 133s at the real pace", secs)
+	}
+	if secs := best400(config.AnalysisConfig{DisableSmoothing: true}); secs >= 130 {
+		t.Errorf("raw 400m = %ds, want the GPS drift to show without smoothing", secs)
+	}
+}
+
+func TestSyncService_CustomMetrics(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// 20 minutes at 3 m/s, the second half above 170 bpm
+	var points []store.StreamPoint
+	for i := 0; i < 1200; i++ {
+		speed, dist, hr := 3.0, float64(i)*3, 150
+		if i >= 600 {
+			hr = 175
+		}
+		points = append(points, store.StreamPoint{ActivityID: 1, TimeOffset: i, VelocitySmooth: &speed, Distance: &dist, Heartrate: &hr})
+	}
+	createTestActivity(t, db, 1, "Morning Run", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), 1199*3, 1200, floatPtr(162))
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatal(err)
+	}
+	a, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{CustomMetrics: []config.CustomMetric{
+		{Name: "hard_minutes", Label: "Minutes above 170", Expr: "time_above_hr(170) / 60"},
+		{Name: "load_per_km", Expr: "trimp / (distance / 1000)"},
+		{Name: "cadence", Expr: "avg_cadence"}, // not recorded
+		{Name: "broken", Expr: "time_above_hr("},
+	}})
+	if !svc.computeActivityMetrics(*a, nil, &SyncResult{}) {
+		t.Fatal("expected metrics to be saved")
+	}
+
+	detail, err := NewQueryService(db, testAthleteConfig()).GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID() error = %v", err)
+	}
+	got := detail.CustomMetrics
+	if len(got) != 2 {
+		t.Fatalf("expected the 2 metrics with values, got %+v", got)
+	}
+	if got[0].Name != "hard_minutes" || got[0].Label != "Minutes above 170" || math.Abs(got[0].Value-10) > 1e-9 {
+		t.Errorf("hard_minutes = %+v, want 10 minutes", got[0])
+	}
+	if got[1].Name != "load_per_km" || got[1].Label != "load_per_km" || got[1].Value <= 0 {
+		t.Errorf("load_per_km = %+v, want a positive value labeled by its name", got[1])
+	}
+}
+
+func TestSyncService_StoresSplits(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// About 3.8 km, the second kilometer faster, with heart rate throughout
+	var points []store.StreamPoint
+	var dist float64
+	for i := 0; i <= 1000; i++ {
+		speed, hr := 3.5, 150
+		if dist >= 1000 && dist < 2000 {
+			speed, hr = 5.0, 170
+		}
+		d := dist
+		points = append(points, store.StreamPoint{ActivityID: 1, TimeOffset: i, VelocitySmooth: &speed, Distance: &d, Heartrate: &hr})
+		dist += speed
+	}
+	total := *points[len(points)-1].Distance
+	createTestActivity(t, db, 1, "Morning Run", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), total, 1000, floatPtr(155))
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatal(err)
+	}
+	a, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Before the metrics phase the detail screen computes splits itself
+	qs := NewQueryService(db, testAthleteConfig())
+	before, err := qs.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID() error = %v", err)
+	}
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	if !svc.computeActivityMetrics(*a, nil, &SyncResult{}) {
+		t.Fatal("expected metrics to be saved")
+	}
+	stored, err := db.GetActivitySplits(1, store.SplitsMetric)
+	if err != nil {
+		t.Fatalf("GetActivitySplits() error = %v", err)
+	}
+	if len(stored) != 4 || !stored[3].Partial || stored[1].Duration != 200 {
+		t.Fatalf("stored km splits = %+v, want 3 full and a partial, the second 200 s", stored)
+	}
+
+	// Stored splits read back as the ones computed from streams
+	after, err := qs.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID() error = %v", err)
+	}
+	if !slices.Equal(after.KmSplits, before.KmSplits) || !slices.Equal(after.Splits, before.Splits) {
+		t.Errorf("stored splits %+v %+v differ from computed %+v %+v", after.KmSplits, after.Splits, before.KmSplits, before.Splits)
+	}
+
+	prs, err := qs.GetPersonalRecords()
+	if err != nil {
+		t.Fatalf("GetPersonalRecords() error = %v", err)
+	}
+	if len(prs.FastestKmSplits) != 3 || prs.FastestKmSplits[0].Split != 2 || prs.FastestKmSplits[0].Time != "3:20" || prs.FastestKmSplits[0].AvgHR != "170" {
+		t.Errorf("FastestKmSplits = %+v, want 3 with km 2 in 3:20 at 170 first", prs.FastestKmSplits)
+	}
+	if len(prs.FastestMileSplits) != 2 || prs.FastestMileSplits[0].Split != 1 {
+		t.Errorf("FastestMileSplits = %+v, want the two full miles, the first faster", prs.FastestMileSplits)
+	}
+}
+
+func TestSyncService_RanksFastestSegments(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	// Three steady 2.4 km runs; only the fastest holds the 1K record
+	for i, speed := range []float64{3.5, 4.0, 3.0} {
+		id := int64(i + 1)
+		var points []store.StreamPoint
+		for sec := 0; sec <= 800; sec++ {
+			d, v := float64(sec)*speed, speed
+			points = append(points, store.StreamPoint{ActivityID: id, TimeOffset: sec, VelocitySmooth: &v, Distance: &d})
+		}
+		start := time.Date(2024, 1, 10+i, 10, 0, 0, 0, time.UTC)
+		createTestActivity(t, db, id, fmt.Sprintf("Run %d", id), start, *points[len(points)-1].Distance, 800, nil)
+		if err := db.SaveStreams(id, points); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	for id := int64(1); id <= 3; id++ {
+		a, err := db.GetActivity(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.analyzeActivityPRs(a, nil, &SyncResult{})
+	}
+
+	qs := NewQueryService(db, testAthleteConfig())
+	boards, err := qs.GetFastestSegments()
+	if err != nil {
+		t.Fatalf("GetFastestSegments() error = %v", err)
+	}
+	if len(boards) != len(analysis.EffortDistances) || boards[0].Category != "effort_400m" {
+		t.Fatalf("GetFastestSegments() = %d boards starting %q, want one per effort distance from 400m", len(boards), boards[0].Category)
+	}
+
+	byCategory := make(map[string]SegmentBoard)
+	for _, b := range boards {
+		byCategory[b.Category] = b
+	}
+	km := byCategory["effort_1k"].Segments
+	var ids []int64
+	for _, s := range km {
+		ids = append(ids, s.ActivityID)
+	}
+	if !slices.Equal(ids, []int64{2, 1, 3}) {
+		t.Fatalf("1K segments from activities %v, want [2 1 3], fastest first", ids)
+	}
+	if km[0].Rank != 1 || km[0].Time != "4:10" || km[0].ActivityName != "Run 2" || km[0].Date != "Jan 11, 2024" {
+		t.Errorf("fastest 1K = %+v, want Run 2 in 4:10 on Jan 11", km[0])
+	}
+	if len(byCategory["effort_5k"].Segments) != 0 {
+		t.Errorf("5K segments = %+v, want none from 2.4 km runs", byCategory["effort_5k"].Segments)
+	}
+
+	// Flagged runs drop off the boards when they're excluded from analysis
+	if err := db.SaveActivityMetrics(&store.ActivityMetrics{ActivityID: 3, AnomalyFlags: []string{"gps_spike"}}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := db.GetActivity(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{ExcludeFlagged: true}).analyzeActivityPRs(a, nil, &SyncResult{})
+	if boards, err = qs.GetFastestSegments(); err != nil || len(boards[1].Segments) != 2 {
+		t.Errorf("1K segments after flagging a run = %+v, %v; want 2", boards[1].Segments, err)
+	}
+}
+
+func TestSyncService_UnverifiedEfforts(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	qs := NewQueryService(db, testAthleteConfig())
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	record := func(category string) store.PersonalRecord {
+		t.Helper()
+		pr, err := db.GetPersonalRecordByCategory(category)
+		if err != nil {
+			t.Fatalf("GetPersonalRecordByCategory(%s) error = %v", category, err)
+		}
+		return *pr
+	}
+	analyze := func(id int64) {
+		t.Helper()
+		a, err := db.GetActivity(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.analyzeActivityPRs(a, nil, &SyncResult{})
+	}
+
+	// A clean 20 minutes at 3 m/s sets the first records
+	createTestActivity(t, db, 1, "Easy Run", startDate, 3600, 1200, floatPtr(150))
+	createTestStreams(t, db, 1, 1200, 3, 150)
+	analyze(1)
+	if pr := record("effort_1k"); pr.Unverified {
+		t.Error("first clean record marked unverified")
+	}
+
+	// A clean run at 4 m/s beats the 1k record by a quarter, too much to
+	// take on trust
+	createTestActivity(t, db, 2, "Fast Run", startDate.AddDate(0, 0, 1), 4800, 1200, floatPtr(150))
+	createTestStreams(t, db, 2, 1200, 4, 150)
+	analyze(2)
+	pr := record("effort_1k")
+	if pr.ActivityID != 2 || !pr.Unverified {
+		t.Fatalf("1k record = activity %d, unverified %v, want activity 2 unverified", pr.ActivityID, pr.Unverified)
+	}
+
+	// Confirming it clears the mark, and recomputing keeps it clear
+	if err := qs.VerifyActivityEfforts(2); err != nil {
+		t.Fatalf("VerifyActivityEfforts() error = %v", err)
+	}
+	if pr := record("effort_1k"); pr.Unverified {
+		t.Error("1k record still unverified after confirming")
+	}
+	if err := db.DeletePersonalRecordsForActivity(2); err != nil {
+		t.Fatal(err)
+	}
+	analyze(2)
+	if pr := record("effort_1k"); pr.ActivityID != 2 || pr.Unverified {
+		t.Errorf("recomputed 1k record = activity %d, unverified %v, want activity 2 verified", pr.ActivityID, pr.Unverified)
+	}
+
+	// An effort over a GPS spike in the recorded streams is doubted even
+	// though smoothing took the spike out of its time. Every mile in this
+	// ten-minute run crosses the spike.
+	var points []store.StreamPoint
+	dist := 0.0
+	for i := 0; i < 600; i++ {
+		speed := 3.9
+		if i == 300 {
+			speed = 30
+		}
+		if i > 0 {
+			dist += speed
+		}
+		d, v := dist, speed
+		points = append(points, store.StreamPoint{ActivityID: 3, TimeOffset: i, VelocitySmooth: &v, Distance: &d})
+	}
+	createTestActivity(t, db, 3, "Glitchy Run", startDate.AddDate(0, 0, 2), dist, 600, nil)
+	if err := db.SaveStreams(3, points); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeletePersonalRecordsForActivity(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeletePersonalRecordsForActivity(2); err != nil {
+		t.Fatal(err)
+	}
+	analyze(3)
+	if pr := record("effort_1mi"); pr.ActivityID != 3 || !pr.Unverified {
+		t.Errorf("mile record = activity %d, unverified %v, want activity 3 unverified", pr.ActivityID, pr.Unverified)
+	}
+}
+
+func TestSyncService_StravaBestEfforts(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Easy Run", startDate, 3600, 1200, floatPtr(150))
+	createTestStreams(t, db, 1, 1200, 3, 150)
+
+	// Strava's 1k agrees with the streams' 334s; its mile is far faster
+	// than the 536s in the streams
+	if err := db.SaveActivityDetails(store.ActivityDetails{ActivityID: 1, FetchedAt: startDate}, nil, []store.StravaBestEffort{
+		{Name: "1k", Distance: 1000, ElapsedTime: 320, MovingTime: 320, StartIndex: 100, EndIndex: 420},
+		{Name: "1 mile", Distance: 1609, ElapsedTime: 400, MovingTime: 400, StartIndex: 0, EndIndex: 400},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	analyze := func(source string) {
+		t.Helper()
+		if err := db.DeletePersonalRecordsForActivity(1); err != nil {
+			t.Fatal(err)
+		}
+		svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{BestEffortSource: source})
+		a, err := db.GetActivity(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.analyzeActivityPRs(a, nil, &SyncResult{})
+	}
+	record := func(category string) store.PersonalRecord {
+		t.Helper()
+		pr, err := db.GetPersonalRecordByCategory(category)
+		if err != nil {
+			t.Fatalf("GetPersonalRecordByCategory(%s) error = %v", category, err)
+		}
+		return *pr
+	}
+
+	// From streams, the imported efforts are ignored
+	analyze(config.BestEffortsFromStreams)
+	if pr := record("effort_1k"); pr.DurationSeconds != 334 {
+		t.Errorf("1k from streams = %ds, want 334s", pr.DurationSeconds)
+	}
+
+	analyze(config.BestEffortsFromStrava)
+	pr := record("effort_1k")
+	if pr.DurationSeconds != 320 || pr.Unverified {
+		t.Errorf("1k from Strava = %ds, unverified %v, want 320s verified", pr.DurationSeconds, pr.Unverified)
+	}
+	if pr.StartOffset == nil || *pr.StartOffset != 100 || pr.AvgHeartrate == nil || *pr.AvgHeartrate != 150 {
+		t.Errorf("1k from Strava offsets/HR = %v/%v, want start 100 at 150 bpm", pr.StartOffset, pr.AvgHeartrate)
+	}
+	if pr := record("effort_1mi"); pr.DurationSeconds != 400 || !pr.Unverified {
+		t.Errorf("mile from Strava = %ds, unverified %v, want 400s unverified", pr.DurationSeconds, pr.Unverified)
+	}
+	// Categories Strava didn't measure still come from the streams
+	if pr := record("effort_400m"); pr.DurationSeconds != 134 {
+		t.Errorf("400m = %ds, want 134s from the streams", pr.DurationSeconds)
+	}
+}
+
+func TestSyncFailure_Retryable(t *testing.T) {
+	tests := []struct {
+		failure SyncFailure
+		want    bool
+	}{
+		{SyncFailure{Phase: "streams", ActivityID: 1}, true},
+		{SyncFailure{Phase: "predictions"}, true},
+		{SyncFailure{Phase: "activities"}, false},
+		{SyncFailure{Phase: "metrics"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.failure.Retryable(); got != tt.want {
+			t.Errorf("%+v.Retryable() = %v, want %v", tt.failure, got, tt.want)
+		}
+	}
+}
+
+func TestSyncResult_MissingScope(t *testing.T) {
+	r := SyncResult{Errors: []error{errors.New("API error 404: not found")}}
+	if r.MissingScope() {
+		t.Error("MissingScope() = true for a 404")
+	}
+	r.Errors = append(r.Errors, fmt.Errorf("fetching streams: %w", strava.ErrMissingScope))
+	if !r.MissingScope() {
+		t.Error("MissingScope() = false with a wrapped ErrMissingScope")
+	}
+}
+
+func TestSyncService_StreamResolution(t *testing.T) {
+	svc := NewSyncService(nil, nil, testAthleteConfig(), config.AnalysisConfig{})
+	svc.SetStorageConfig(config.StorageConfig{MaxStreamPoints: 30000})
+
+	short := store.Activity{ID: 1, ElapsedTime: 3600}
+	ultra := store.Activity{ID: 2, ElapsedTime: 30 * 3600}
+	if r := svc.resolutionFor(short); r != strava.ResolutionFull {
+		t.Errorf("resolution for an hour = %q, want full", r)
+	}
+	if r := svc.resolutionFor(ultra); r != strava.ResolutionHigh {
+		t.Errorf("resolution for 30 hours = %q, want high", r)
+	}
+	// A cap below high's 10,000 points downloads the finest resolution under it
+	svc.SetStorageConfig(config.StorageConfig{MaxStreamPoints: 5000})
+	if r := svc.resolutionFor(ultra); r != strava.ResolutionMedium {
+		t.Errorf("resolution for 30 hours capped at 5000 = %q, want medium", r)
+	}
+	svc.SetStorageConfig(config.StorageConfig{StreamResolution: config.StreamResolutionMedium, MaxStreamPoints: -1})
+	if r := svc.resolutionFor(short); r != strava.ResolutionMedium {
+		t.Errorf("resolution when set = %q, want medium", r)
+	}
+
+	points := make([]store.StreamPoint, 10001)
+	for i := range points {
+		points[i].TimeOffset = i
+	}
+	if got := svc.capStreamPoints(1, points); len(got) != len(points) {
+		t.Errorf("uncapped kept %d points, want all %d", len(got), len(points))
+	}
+	svc.SetStorageConfig(config.StorageConfig{MaxStreamPoints: 1001})
+	got := svc.capStreamPoints(1, points)
+	if len(got) != 1001 || got[0].TimeOffset != 0 || got[1].TimeOffset != 10 || got[1000].TimeOffset != 10000 {
+		t.Errorf("capped to %d points at %d, %d ... %d; want 1001 every 10s from 0 to 10000",
+			len(got), got[0].TimeOffset, got[1].TimeOffset, got[len(got)-1].TimeOffset)
+	}
+}
+
+func TestSyncService_DetectRaces(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	qs := NewQueryService(db, testAthleteConfig())
+
+	// Ten easy runs at about 9:00/mi set the median pace
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i := int64(1); i <= 10; i++ {
+		createTestActivity(t, db, i, "Easy Run", start.AddDate(0, 0, int(i)), 8000, 2700, nil)
+	}
+	// A fast 5K, and a fast run that isn't a race distance
+	createTestActivity(t, db, 11, "Parkrun", start.AddDate(0, 0, 20), 5050, 1380, nil)
+	createTestActivity(t, db, 12, "Tempo", start.AddDate(0, 0, 21), 7000, 1900, nil)
+
+	result := &SyncResult{}
+	if err := svc.detectRaces(context.Background(), nil, result); err != nil {
+		t.Fatalf("detectRaces() error = %v", err)
+	}
+	if result.RacesFound != 1 {
+		t.Errorf("RacesFound = %d, want 1", result.RacesFound)
+	}
+
+	races, err := qs.GetRaces()
+	if err != nil {
+		t.Fatalf("GetRaces() error = %v", err)
+	}
+	if len(races) != 1 || races[0].ActivityID != 11 || races[0].RaceLabel != "5K" {
+		t.Fatalf("expected the parkrun as a 5K race, got %+v", races)
+	}
+
+	// A dismissed race stays dismissed and isn't counted again
+	if err := qs.DismissRace(11); err != nil {
+		t.Fatal(err)
+	}
+	result = &SyncResult{}
+	if err := svc.detectRaces(context.Background(), nil, result); err != nil {
+		t.Fatalf("detectRaces() error = %v", err)
+	}
+	if result.RacesFound != 0 {
+		t.Errorf("RacesFound = %d after dismissing, want 0", result.RacesFound)
+	}
+	if races, _ := qs.GetRaces(); len(races) != 0 {
+		t.Errorf("expected no races after dismissing, got %+v", races)
+	}
+}
+
+func TestSyncService_Social(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	qs := NewQueryService(db, testAthleteConfig())
+
+	run := strava.Activity{
+		ID: 1, Name: "Long Run", Type: "Run", HasHeartrate: true,
+		StartDate: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		Distance:  16000, MovingTime: 5400,
+		KudosCount: 4, CommentCount: 1, TotalPhotoCount: 2,
+		Photos: &strava.Photos{Count: 2, Primary: &strava.Photo{URLs: map[string]string{
+			"100": "https://example.com/small.jpg",
+			"600": "https://example.com/large.jpg",
+		}}},
+	}
+	social := func() *store.ActivitySocial {
+		t.Helper()
+		s, err := db.GetActivitySocial(1)
+		if err != nil {
+			t.Fatalf("GetActivitySocial() error = %v", err)
+		}
+		return s
+	}
+
+	// Off by default
+	if err := svc.storeActivity(run); err != nil {
+		t.Fatalf("storeActivity() error = %v", err)
+	}
+	if s := social(); s != nil {
+		t.Fatalf("stored %+v with privacy.sync_social off", s)
+	}
+
+	// Counts without the photo URL
+	svc.SetPrivacyConfig(config.PrivacyConfig{SyncSocial: true})
+	if err := svc.saveDetails(run); err != nil {
+		t.Fatalf("saveDetails() error = %v", err)
+	}
+	if s := social(); s == nil || s.KudosCount != 4 || s.CommentCount != 1 || s.PhotoCount != 2 || s.PhotoURL != "" {
+		t.Fatalf("social = %+v, want counts without a photo URL", s)
+	}
+
+	// The largest primary photo, kept when later counts come without it
+	svc.SetPrivacyConfig(config.PrivacyConfig{SyncSocial: true, SyncPhotos: true})
+	if err := svc.saveDetails(run); err != nil {
+		t.Fatalf("saveDetails() error = %v", err)
+	}
+	run.KudosCount = 9
+	if refreshed, err := svc.refreshSocial(run); err != nil || !refreshed {
+		t.Fatalf("refreshSocial() = %v, %v, want the stored run refreshed", refreshed, err)
+	}
+	if s := social(); s == nil || s.KudosCount != 9 || s.PhotoURL != "https://example.com/large.jpg" {
+		t.Fatalf("social = %+v, want 9 kudos and the 600px photo", s)
+	}
+	if refreshed, err := svc.refreshSocial(strava.Activity{ID: 2}); err != nil || refreshed {
+		t.Errorf("refreshSocial() = %v, %v for a run not stored yet, want false", refreshed, err)
+	}
+
+	detail, err := qs.GetActivityDetailByID(1)
+	if err != nil {
+		t.Fatalf("GetActivityDetailByID() error = %v", err)
+	}
+	if detail.Activity.Social == nil || detail.Activity.Social.KudosCount != 9 {
+		t.Errorf("detail social = %+v, want 9 kudos", detail.Activity.Social)
+	}
+
+	// Turning the settings off deletes what they allowed
+	svc.SetPrivacyConfig(config.PrivacyConfig{SyncSocial: true})
+	if err := svc.pruneSocial(); err != nil {
+		t.Fatalf("pruneSocial() error = %v", err)
+	}
+	if s := social(); s == nil || s.PhotoURL != "" || s.KudosCount != 9 {
+		t.Errorf("social = %+v with photos off, want counts only", s)
+	}
+	svc.SetPrivacyConfig(config.PrivacyConfig{})
+	if err := svc.pruneSocial(); err != nil {
+		t.Fatalf("pruneSocial() error = %v", err)
+	}
+	if s := social(); s != nil {
+		t.Errorf("social = %+v with privacy.sync_social off, want none", s)
+	}
+}
+
+func TestEstimateSyncDuration(t *testing.T) {
+	per := EstimatedRequestMillis * time.Millisecond
+	tests := []struct {
+		name                                 string
+		requests, shortRemaining, shortLimit int
+		want                                 time.Duration
+	}{
+		{"within budget", 51, 100, 100, 51 * per},
+		{"one window wait", 51, 20, 100, 51*per + 15*time.Minute},
+		{"two window waits", 240, 40, 100, 240*per + 30*time.Minute},
+		{"budget already spent", 10, -3, 100, 10*per + 15*time.Minute},
+		{"nothing to do", 0, 100, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateSyncDuration(tt.requests, tt.shortRemaining, tt.shortLimit); got != tt.want {
+				t.Errorf("estimateSyncDuration(%d, %d, %d) = %v, want %v", tt.requests, tt.shortRemaining, tt.shortLimit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyncPhases(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []SyncPhase
+		wantErr bool
+	}{
+		{"metrics", []SyncPhase{PhaseMetrics}, false},
+		{"predictions, PRs,metrics", []SyncPhase{PhaseMetrics, PhasePersonalRecords, PhasePredictions}, false},
+		{"local", LocalPhases, false},
+		{"remote", []SyncPhase{PhaseActivities, PhaseStreams}, false},
+		{"all", AllPhases, false},
+		{"streams,streams", []SyncPhase{PhaseStreams}, false},
+		{"splits", nil, true},
+		{" , ", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSyncPhases(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSyncPhases(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSyncPhases(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSyncService_Sync_SelectedPhases(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Morning Run", startDate, 5000, 1800, floatPtr(150))
+	createTestStreams(t, db, 1, 1800, 2.78, 150)
+
+	// Local phases only, so no Strava client is needed
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	opts := SyncOptions{Phases: []SyncPhase{PhaseMetrics}}
+	if opts.NeedsStrava() {
+		t.Error("NeedsStrava() = true for metrics only")
+	}
+	result, err := svc.Sync(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.MetricsComputed != 1 {
+		t.Errorf("MetricsComputed = %d, want 1", result.MetricsComputed)
+	}
+	if len(result.Phases) != 1 || result.Phases[0].Phase != PhaseMetrics || result.Phases[0].RateLimit != nil {
+		t.Errorf("Phases = %+v, want only metrics, without a rate limit", result.Phases)
+	}
+	if result.Requests != 0 || result.BytesStored <= 0 || result.Duration < result.Phases[0].Duration {
+		t.Errorf("got %d requests, %d bytes in %s, want no requests and the metrics stored",
+			result.Requests, result.BytesStored, result.Duration)
+	}
+	prs, err := db.GetAllPersonalRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 0 {
+		t.Errorf("got %d personal records, want none when that phase isn't selected", len(prs))
+	}
+	before, err := db.GetActivityMetrics(1)
+	if err != nil || before == nil || before.TRIMP == nil {
+		t.Fatalf("expected metrics with TRIMP, got %+v, %v", before, err)
+	}
+
+	// After the athlete's HR settings change, new-only metrics skip the run
+	// and recomputing picks the new zones up
+	athlete := testAthleteConfig()
+	athlete.MaxHR -= 15
+	svc = NewSyncService(nil, db, athlete, config.AnalysisConfig{})
+	result, err = svc.Sync(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.MetricsComputed != 0 {
+		t.Errorf("MetricsComputed = %d, want 0 without recompute", result.MetricsComputed)
+	}
+
+	opts.RecomputeMetrics = true
+	result, err = svc.Sync(context.Background(), opts, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.MetricsComputed != 1 {
+		t.Errorf("MetricsComputed = %d, want 1 with recompute", result.MetricsComputed)
+	}
+	after, err := db.GetActivityMetrics(1)
+	if err != nil || after == nil || after.TRIMP == nil {
+		t.Fatalf("expected metrics with TRIMP, got %+v, %v", after, err)
+	}
+	if *after.TRIMP == *before.TRIMP {
+		t.Errorf("TRIMP = %v after recompute, want it to change with max HR", *after.TRIMP)
+	}
+}
+
+func TestSyncService_PauseAndCancel(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Morning Run", startDate, 5000, 1800, floatPtr(150))
+	createTestStreams(t, db, 1, 1800, 2.78, 150)
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	opts := SyncOptions{Phases: []SyncPhase{PhaseMetrics}}
+
+	// A paused sync waits until resumed
+	svc.Pause()
+	if !svc.Paused() {
+		t.Fatal("Paused() = false after Pause()")
+	}
+	done := make(chan *SyncResult)
+	go func() {
+		result, err := svc.Sync(context.Background(), opts, nil)
+		if err != nil {
+			t.Errorf("Sync() error = %v", err)
+		}
+		done <- result
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("sync finished while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	svc.Resume()
+	if result := <-done; result.MetricsComputed != 1 {
+		t.Errorf("MetricsComputed = %d after resuming, want 1", result.MetricsComputed)
+	}
+
+	// Cancelling a paused sync stops it without doing the work
+	createTestActivity(t, db, 2, "Evening Run", startDate.Add(8*time.Hour), 5000, 1800, floatPtr(150))
+	createTestStreams(t, db, 2, 1800, 2.78, 150)
+	svc.Pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := svc.Sync(ctx, opts, nil)
+		errs <- err
+	}()
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Sync() error = %v, want context.Canceled", err)
+	}
+	svc.Resume()
+
+	if m, err := db.GetActivityMetrics(2); err != nil || m != nil {
+		t.Errorf("GetActivityMetrics(2) = %+v, %v; want none after cancelling", m, err)
+	}
+}
+
+func TestSyncService_OnSyncDone(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	startDate := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	createTestActivity(t, db, 1, "Morning Run", startDate, 5000, 1800, floatPtr(150))
+	createTestStreams(t, db, 1, 1800, 2.78, 150)
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	var calls []*SyncResult
+	svc.SetOnSyncDone(func(ctx context.Context, result *SyncResult, err error) {
+		if err != nil {
+			t.Errorf("OnSyncDone error = %v", err)
+		}
+		// The lock is already released for whatever the callback starts
+		if lock, _ := db.GetSyncLock(time.Now()); lock != nil {
+			t.Errorf("sync lock held by %s during OnSyncDone", lock.Owner)
+		}
+		calls = append(calls, result)
+	})
+
+	result, err := svc.Sync(context.Background(), SyncOptions{Phases: []SyncPhase{PhaseMetrics}}, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(calls) != 1 || calls[0] != result {
+		t.Fatalf("OnSyncDone called with %v, want the sync's result once", calls)
+	}
+
+	// A sync that can't take the lock didn't happen, so isn't reported
+	if err := db.AcquireSyncLock("elsewhere:1", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var locked *store.SyncLockedError
+	if _, err := svc.Sync(context.Background(), SyncOptions{}, nil); !errors.As(err, &locked) {
+		t.Fatalf("Sync() error = %v, want SyncLockedError", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("OnSyncDone called %d times, want only for the sync that ran", len(calls))
+	}
+}
+
+func TestSyncService_NewRecords(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	startDate := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	analyze := func(id int64, result *SyncResult) {
+		t.Helper()
+		a, err := db.GetActivity(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.analyzeActivityPRs(a, nil, result)
+	}
+	find := func(result *SyncResult, category string) *NewRecord {
+		for i := range result.NewRecords {
+			if result.NewRecords[i].Record.Category == category {
+				return &result.NewRecords[i]
+			}
+		}
+		return nil
+	}
+
+	createTestActivity(t, db, 1, "March Run", startDate, 3600, 1200, floatPtr(150))
+	createTestStreams(t, db, 1, 1200, 3, 150)
+	first := &SyncResult{}
+	analyze(1, first)
+	if r := find(first, "effort_1k"); r == nil || r.Previous != nil || r.Margin() != 0 {
+		t.Fatalf("first 1k record = %+v, want one with nothing before it", r)
+	}
+	if first.PRsComputed != len(first.NewRecords) {
+		t.Errorf("PRsComputed = %d, want one per new record (%d)", first.PRsComputed, len(first.NewRecords))
+	}
+
+	// Two faster runs in one sync list the 1k once, against March's record
+	createTestActivity(t, db, 2, "Faster Run", startDate.AddDate(0, 2, 0), 3720, 1200, floatPtr(150))
+	createTestStreams(t, db, 2, 1200, 3.1, 150)
+	createTestActivity(t, db, 3, "Fastest Run", startDate.AddDate(0, 3, 0), 3840, 1200, floatPtr(150))
+	createTestStreams(t, db, 3, 1200, 3.2, 150)
+	result := &SyncResult{}
+	analyze(2, result)
+	analyze(3, result)
+
+	r := find(result, "effort_1k")
+	if r == nil {
+		t.Fatal("expected a new 1k record")
+	}
+	if r.Record.ActivityID != 3 || r.ActivityName != "Fastest Run" {
+		t.Errorf("1k record from %d %q, want the fastest run", r.Record.ActivityID, r.ActivityName)
+	}
+	if r.Previous == nil || r.Previous.ActivityID != 1 {
+		t.Fatalf("previous 1k record = %+v, want March's", r.Previous)
+	}
+	if want := float64(r.Previous.DurationSeconds - r.Record.DurationSeconds); r.Margin() != want || want <= 0 {
+		t.Errorf("Margin() = %v, want %v", r.Margin(), want)
+	}
+	if r.Label() != "1K" {
+		t.Errorf("Label() = %q", r.Label())
+	}
+}
+
+func TestSyncService_DeleteActivity(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	startDate := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	for i, speed := range []float64{3, 3.2, 3.1} {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, fmt.Sprintf("Run %d", id), startDate.AddDate(0, 0, 7*i), 1200*speed, 1200, floatPtr(150))
+		createTestStreams(t, db, id, 1200, speed, 150)
+	}
+	for id := int64(1); id <= 3; id++ {
+		a, err := db.GetActivity(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.analyzeActivityPRs(a, nil, &SyncResult{})
+	}
+	if pr, _ := db.GetPersonalRecordByCategory("effort_1k"); pr == nil || pr.ActivityID != 2 {
+		t.Fatalf("1k record before deleting = %+v, want run 2's", pr)
+	}
+
+	if _, err := svc.DeleteActivity(context.Background(), 2); err != nil {
+		t.Fatalf("DeleteActivity() error = %v", err)
+	}
+
+	if _, err := db.GetActivity(2); !errors.Is(err, store.ErrActivityNotFound) {
+		t.Errorf("GetActivity(2) error = %v, want not found", err)
+	}
+	if deleted, _ := db.DeletedActivityIDs(); !deleted[2] {
+		t.Error("deleted run wasn't remembered, so sync would bring it back")
+	}
+
+	// The next best run holds the records again, and the history shows
+	// the improvement the deleted run had hidden
+	for _, category := range []string{"effort_1k", "longest_run"} {
+		pr, err := db.GetPersonalRecordByCategory(category)
+		if err != nil || pr.ActivityID != 3 {
+			t.Errorf("%s record after deleting = %+v, %v; want run 3's", category, pr, err)
+		}
+	}
+	history, err := db.GetPRHistory("effort_1k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, e := range history {
+		ids = append(ids, e.ActivityID)
+	}
+	if !slices.Equal(ids, []int64{1, 3}) {
+		t.Errorf("1k history from runs %v, want [1 3]", ids)
+	}
+}
+
+func TestSyncService_RepairRecords(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	startDate := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	for i, speed := range []float64{3, 3.2, 3.1} {
+		id := int64(i + 1)
+		createTestActivity(t, db, id, fmt.Sprintf("Run %d", id), startDate.AddDate(0, 0, 7*i), 1200*speed, 1200, floatPtr(150))
+		createTestStreams(t, db, id, 1200, speed, 150)
+	}
+	for id := int64(1); id <= 3; id++ {
+		a, err := db.GetActivity(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.analyzeActivityPRs(a, nil, &SyncResult{})
+	}
+
+	// Excluded behind the service's back, so its records still stand
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &SyncResult{}
+	if err := svc.repairRecords(context.Background(), result); err != nil {
+		t.Fatalf("repairRecords() error = %v", err)
+	}
+	if result.RecordsRepaired == 0 {
+		t.Error("RecordsRepaired = 0, want the categories run 2 held")
+	}
+	for _, category := range []string{"effort_1k", "longest_run"} {
+		pr, err := db.GetPersonalRecordByCategory(category)
+		if err != nil || pr.ActivityID != 3 {
+			t.Errorf("%s record after repair = %+v, %v; want run 3's", category, pr, err)
+		}
+	}
+	history, err := db.GetPRHistory("effort_1k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, e := range history {
+		ids = append(ids, e.ActivityID)
+	}
+	if !slices.Equal(ids, []int64{1, 3}) {
+		t.Errorf("1k history from runs %v, want [1 3]", ids)
+	}
+	if stale, _ := db.StaleRecordCategories(); len(stale) != 0 {
+		t.Errorf("categories still stale after repair: %v", stale)
+	}
+}
+
+func TestSyncService_ComputePersonalRecordsIncremental(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+	startDate := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	addRun := func(id int64, speed float64) {
+		createTestActivity(t, db, id, fmt.Sprintf("Run %d", id), startDate.AddDate(0, 0, 7*int(id)), 1200*speed, 1200, floatPtr(150))
+		createTestStreams(t, db, id, 1200, speed, 150)
+	}
+	needing := func() int {
+		t.Helper()
+		activities, err := db.GetActivitiesNeedingEfforts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(activities)
+	}
+
+	addRun(1, 3)
+	addRun(2, 3.2)
+	if err := svc.computePersonalRecords(context.Background(), nil, &SyncResult{}); err != nil {
+		t.Fatalf("computePersonalRecords() error = %v", err)
+	}
+	if n := needing(); n != 0 {
+		t.Fatalf("%d runs still need analysis after a sync", n)
+	}
+
+	// Only the new run is read; it takes the record from the cached ones
+	addRun(3, 3.4)
+	if n := needing(); n != 1 {
+		t.Fatalf("%d runs need analysis after adding one, want 1", n)
+	}
+	result := &SyncResult{}
+	if err := svc.computePersonalRecords(context.Background(), nil, result); err != nil {
+		t.Fatalf("computePersonalRecords() error = %v", err)
+	}
+	if pr, err := db.GetPersonalRecordByCategory("effort_1k"); err != nil || pr.ActivityID != 3 {
+		t.Errorf("1k record = %+v, %v; want run 3's", pr, err)
+	}
+	if result.PRsComputed == 0 {
+		t.Error("PRsComputed = 0, want the records run 3 set")
+	}
+
+	if err := svc.recomputePersonalRecords(context.Background(), nil, &SyncResult{}); err != nil {
+		t.Fatalf("recomputePersonalRecords() error = %v", err)
+	}
+	if n := needing(); n != 0 {
+		t.Errorf("%d runs still need analysis after recomputing", n)
+	}
+}
+
+func TestAnalyzeInOrder(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+	svc := NewSyncService(nil, db, testAthleteConfig(), config.AnalysisConfig{})
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	activities := make([]store.Activity, 40)
+	for i := range activities {
+		activities[i] = store.Activity{ID: int64(i + 1), Name: fmt.Sprintf("Run %d", i+1)}
+	}
+
+	// Later runs finish first, yet they're applied in order
+	progress := make(chan SyncProgress, 2*len(activities)+1)
+	var applied []int64
+	err := analyzeInOrder(context.Background(), svc, "metrics", activities, progress,
+		func(a store.Activity) int64 {
+			time.Sleep(time.Duration(len(activities)-int(a.ID)) * 100 * time.Microsecond)
+			return a.ID * 10
+		},
+		func(a store.Activity, r int64) {
+			if r != a.ID*10 {
+				t.Errorf("run %d got result %d", a.ID, r)
+			}
+			applied = append(applied, a.ID)
+		})
+	if err != nil {
+		t.Fatalf("analyzeInOrder() error = %v", err)
+	}
+	close(progress)
+
+	for i, id := range applied {
+		if id != int64(i+1) {
+			t.Fatalf("applied %v, want runs in order", applied)
+		}
+	}
+	if len(applied) != len(activities) {
+		t.Fatalf("applied %d runs, want %d", len(applied), len(activities))
+	}
+
+	workers := make(map[int]bool)
+	last := 0
+	for p := range progress {
+		if p.Worker > 0 {
+			workers[p.Worker] = true
+			continue
+		}
+		last = p.Completed
+	}
+	if len(workers) < 2 || len(workers) > 4 {
+		t.Errorf("progress came from workers %v, want 2 to 4 of them", workers)
+	}
+	if last != len(activities) {
+		t.Errorf("last progress Completed = %d, want %d", last, len(activities))
+	}
+
+	// Cancelling stops before the next run is applied
+	ctx, cancel := context.WithCancel(context.Background())
+	applied = nil
+	err = analyzeInOrder(ctx, svc, "metrics", activities, nil,
+		func(a store.Activity) int64 { return a.ID },
+		func(a store.Activity, _ int64) {
+			applied = append(applied, a.ID)
+			if len(applied) == 3 {
+				cancel()
+			}
+		})
+	if !errors.Is(err, context.Canceled) || len(applied) != 3 {
+		t.Errorf("after cancelling: error = %v, applied %v; want context.Canceled after 3", err, applied)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+)
+
+// PhaseTiming is what one phase of a sync took, to diagnose slow syncs
+type PhaseTiming struct {
+	Phase    SyncPhase
+	Duration time.Duration
+	Requests int // Strava API requests sent
+
+	// BytesStored is how much the data in the database grew, negative when
+	// the phase freed more than it wrote
+	BytesStored int64
+
+	// RateLimit is the API usage when the phase finished, nil without a
+	// Strava client
+	RateLimit *RateLimitSnapshot
+}
+
+// RateLimitSnapshot is Strava's rate limit usage at one moment, as last
+// reported by the API and counted since
+type RateLimitSnapshot struct {
+	ShortUsage int
+	ShortLimit int
+	DailyUsage int
+	DailyLimit int
+}
+
+// phaseTimer measures one phase from when it was started
+type phaseTimer struct {
+	s        *SyncService
+	phase    SyncPhase
+	start    time.Time
+	requests int
+	bytes    int64
+}
+
+// startPhase starts measuring a phase
+func (s *SyncService) startPhase(phase SyncPhase) phaseTimer {
+	return phaseTimer{
+		s:        s,
+		phase:    phase,
+		start:    time.Now(),
+		requests: s.requestCount(),
+		bytes:    s.usedBytes(),
+	}
+}
+
+// finish records the phase's timing in the result and adds it to the totals
+func (t phaseTimer) finish(result *SyncResult) {
+	timing := PhaseTiming{
+		Phase:       t.phase,
+		Duration:    time.Since(t.start),
+		Requests:    t.s.requestCount() - t.requests,
+		BytesStored: t.s.usedBytes() - t.bytes,
+		RateLimit:   t.s.rateLimitSnapshot(),
+	}
+	slog.Info("sync phase finished", "phase", t.phase, "ms", timing.Duration.Milliseconds(),
+		"requests", timing.Requests, "bytes", timing.BytesStored)
+
+	result.Phases = append(result.Phases, timing)
+	result.Requests += timing.Requests
+	result.BytesStored += timing.BytesStored
+}
+
+// requestCount returns the API requests sent so far, zero without a client
+func (s *SyncService) requestCount() int {
+	if s.client == nil {
+		return 0
+	}
+	return s.client.RequestCount()
+}
+
+// usedBytes returns the size of the stored data. A failure only costs the
+// timing its byte count, so it is logged rather than returned.
+func (s *SyncService) usedBytes() int64 {
+	used, err := s.store.UsedBytes()
+	if err != nil {
+		slog.Debug("measuring database size", "error", err)
+	}
+	return used
+}
+
+// rateLimitSnapshot returns the current API usage, or nil without a client
+func (s *SyncService) rateLimitSnapshot() *RateLimitSnapshot {
+	if s.client == nil {
+		return nil
+	}
+	shortLimit, dailyLimit := s.client.RateLimits()
+	shortRemaining, dailyRemaining := s.client.RateLimitStatus()
+	return &RateLimitSnapshot{
+		ShortUsage: shortLimit - shortRemaining,
+		ShortLimit: shortLimit,
+		DailyUsage: dailyLimit - dailyRemaining,
+		DailyLimit: dailyLimit,
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"runner/internal/store"
+)
+
+// TrimActivity cuts an activity's streams after endOffset seconds, for when
+// the watch kept recording after the run. The summary, metrics and personal
+// records are recomputed from the points that are left and the trim is
+// recorded, so later syncs keep the trimmed summary. Nothing is sent to
+// Strava; ResyncActivity restores the original.
+func (s *SyncService) TrimActivity(ctx context.Context, activityID int64, endOffset int) (*SyncResult, error) {
+	existing, err := s.store.GetActivity(activityID)
+	if err != nil {
+		return nil, fmt.Errorf("getting activity %d: %w", activityID, err)
+	}
+	streams, err := s.store.GetStreams(activityID)
+	if err != nil {
+		return nil, fmt.Errorf("getting streams for %d: %w", activityID, err)
+	}
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("activity %d has no streams to trim", activityID)
+	}
+
+	var kept []store.StreamPoint
+	for _, p := range streams {
+		if p.TimeOffset <= endOffset {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) < 2 {
+		return nil, fmt.Errorf("trimming at %s would leave nothing of the run", formatDuration(endOffset))
+	}
+	if len(kept) == len(streams) {
+		return nil, fmt.Errorf("nothing was recorded after %s", formatDuration(endOffset))
+	}
+
+	slog.Info("trimming activity", "activity_id", activityID, "end_offset", endOffset, "points_removed", len(streams)-len(kept))
+	return s.replaceStreams(ctx, *existing, streams, kept, func() error {
+		err := s.store.SetActivityTrim(store.ActivityTrim{
+			ActivityID:          activityID,
+			EndOffset:           kept[len(kept)-1].TimeOffset,
+			OriginalDistance:    existing.Distance,
+			OriginalElapsedTime: existing.ElapsedTime,
+			TrimmedAt:           time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("recording trim for %d: %w", activityID, err)
+		}
+		return nil
+	})
+}
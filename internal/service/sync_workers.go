@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"runner/internal/store"
+)
+
+// analysisWorkers returns how many of n runs the metrics and records phases
+// analyze at once: one per CPU Go schedules on, and never more than there
+// are runs
+func analysisWorkers(n int) int {
+	return max(1, min(runtime.GOMAXPROCS(0), n))
+}
+
+// analyzeInOrder analyzes activities on a pool of workers and applies the
+// results in the order of activities, on the calling goroutine. prepare
+// reads the run's streams and does the computing, so it may run on any
+// worker at once with others and must not write. apply saves what prepare
+// found and sees every run in order, so records and the sync result come
+// out the same whichever worker finishes first.
+//
+// Each worker reports the run it picks up, tagged with its number and
+// without a Completed count; each applied run moves the count on. Pausing
+// stops handing out runs once the workers' queue is used up.
+func analyzeInOrder[R any](ctx context.Context, s *SyncService, phase string, activities []store.Activity, progress chan<- SyncProgress,
+	prepare func(store.Activity) R, apply func(store.Activity, R)) error {
+	total := len(activities)
+	workers := analysisWorkers(total)
+
+	// Up to two runs per worker are handed out ahead of the one being
+	// applied, so workers don't sit idle while results are saved
+	jobs := make(chan int, 2*workers)
+	results := make([]chan R, total)
+	for i := range results {
+		results[i] = make(chan R, 1)
+	}
+
+	var wg sync.WaitGroup
+	for w := 1; w <= workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := range jobs {
+				// Runs queued before a cancel are never applied
+				if ctx.Err() != nil {
+					continue
+				}
+				if progress != nil {
+					progress <- SyncProgress{
+						Phase:           phase,
+						Total:           total,
+						CurrentActivity: activities[i].Name,
+						Worker:          worker,
+					}
+				}
+				results[i] <- prepare(activities[i])
+			}
+		}(w)
+	}
+	// Workers send progress, so they finish before the caller can close it
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	next := 0
+	for next < total && next < cap(jobs) {
+		jobs <- next
+		next++
+	}
+
+	for i, activity := range activities {
+		if err := s.checkpoint(ctx); err != nil {
+			return err
+		}
+		var r R
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		apply(activity, r)
+
+		if progress != nil {
+			progress <- SyncProgress{Phase: phase, Total: total, Completed: i + 1}
+		}
+		if next < total {
+			jobs <- next
+			next++
+		}
+	}
+	return nil
+}
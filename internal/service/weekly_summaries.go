@@ -0,0 +1,192 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"runner/internal/store"
+)
+
+// weekStartOf returns the first day of the week an activity belongs to on
+// the day-bucketing clock
+func weekStartOf(st *store.Store, a store.Activity) time.Time {
+	return startOfWeek(st.BucketTime(a), st.WeekStart())
+}
+
+// bucketNow returns the current time on the day-bucketing clock, the one
+// this week, month and today are taken from
+func (q *QueryService) bucketNow() time.Time {
+	return q.store.BucketClock(time.Now())
+}
+
+// weekStart returns the first day of the week containing t
+func (q *QueryService) weekStart(t time.Time) time.Time {
+	return startOfWeek(t, q.store.WeekStart())
+}
+
+// weekLabel names the week starting at start, by its ISO week number with
+// display.week_numbers or else by its first day
+func (q *QueryService) weekLabel(start time.Time) string {
+	if q.weekNumbers {
+		return isoWeekLabel(start)
+	}
+	return start.Format("Jan 02")
+}
+
+// rebuildWeeklySummaries recomputes the stored summary for each given week
+// from its activities and their cached stream stats. Weeks left without runs
+// are removed.
+func rebuildWeeklySummaries(st *store.Store, weeks []time.Time) error {
+	for _, weekStart := range weeks {
+		activities, err := st.GetWeekActivities(weekStart, weekStart.AddDate(0, 0, 7))
+		if err != nil {
+			return fmt.Errorf("getting activities for week of %s: %w", weekStart.Format("Jan 02"), err)
+		}
+
+		if len(activities) == 0 {
+			if err := st.DeleteWeeklySummary(weekStart); err != nil {
+				return fmt.Errorf("deleting summary for week of %s: %w", weekStart.Format("Jan 02"), err)
+			}
+			continue
+		}
+
+		if err := fillWeekStreamStats(st, activities); err != nil {
+			return fmt.Errorf("caching stream stats for week of %s: %w", weekStart.Format("Jan 02"), err)
+		}
+
+		summary := store.WeeklySummary{WeekStart: weekStart}
+		for _, a := range activities {
+			summary.RunCount++
+			summary.Distance += a.Distance
+			summary.ElevationGain += a.ElevationGain
+			if a.TRIMP != nil {
+				summary.TRIMP += *a.TRIMP
+			}
+
+			stats := a.StreamStats
+			summary.MovingTime += stats.MovingTime
+			summary.MovingDistance += stats.MovingDistance
+			summary.HRSum += stats.HRSum
+			summary.HRCount += stats.HRCount
+			summary.CadenceSum += stats.CadenceSum
+			summary.CadenceCount += stats.CadenceCount
+		}
+
+		if err := st.SaveWeeklySummary(&summary); err != nil {
+			return fmt.Errorf("saving summary for week of %s: %w", weekStart.Format("Jan 02"), err)
+		}
+	}
+	return nil
+}
+
+// fillWeekStreamStats computes and caches stream stats for the activities
+// analyzed before the cache existed, the only ones whose streams are read
+func fillWeekStreamStats(st *store.Store, activities []store.WeekActivity) error {
+	var missingIDs []int64
+	for _, a := range activities {
+		if a.StreamStats == nil {
+			missingIDs = append(missingIDs, a.ID)
+		}
+	}
+	if len(missingIDs) == 0 {
+		return nil
+	}
+
+	statsMap, err := st.ComputeStreamStatsForActivities(missingIDs, streamStatsRules)
+	if err != nil {
+		return fmt.Errorf("computing stream stats: %w", err)
+	}
+	for i := range activities {
+		if activities[i].StreamStats != nil {
+			continue
+		}
+		stats := statsMap[activities[i].ID]
+		if err := st.SaveActivityStreamStats(stats); err != nil {
+			return fmt.Errorf("saving stream stats for %d: %w", activities[i].ID, err)
+		}
+		activities[i].StreamStats = stats
+	}
+	return nil
+}
+
+// rebuildAllWeeklySummaries fills the weekly summaries for every week with an
+// analyzed run. Used to backfill databases created before the table existed.
+func rebuildAllWeeklySummaries(st *store.Store) error {
+	dates, err := st.ListAnalyzedStartDates()
+	if err != nil {
+		return fmt.Errorf("listing activity dates: %w", err)
+	}
+
+	seen := make(map[time.Time]bool)
+	var weeks []time.Time
+	for _, d := range dates {
+		week := startOfWeek(d, st.WeekStart())
+		if !seen[week] {
+			seen[week] = true
+			weeks = append(weeks, week)
+		}
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Before(weeks[j]) })
+
+	return rebuildWeeklySummaries(st, weeks)
+}
+
+// refreshWeeklySummary rebuilds the summary for the week of one activity
+// after it was added or excluded. Nothing is done until the table is first
+// filled, since that fill covers every week.
+func (q *QueryService) refreshWeeklySummary(activityID int64) error {
+	count, err := q.store.CountWeeklySummaries()
+	if err != nil || count == 0 {
+		return err
+	}
+	activity, err := q.store.GetActivity(activityID)
+	if err != nil {
+		return err
+	}
+	return rebuildWeeklySummaries(q.store, []time.Time{weekStartOf(q.store, *activity)})
+}
+
+// getWeeklySummaries returns numWeeks summaries ending with the current week,
+// oldest first, with empty entries for weeks without runs. The table is
+// backfilled on first use.
+func (q *QueryService) getWeeklySummaries(numWeeks int) ([]store.WeeklySummary, error) {
+	count, err := q.store.CountWeeklySummaries()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		if err := rebuildAllWeeklySummaries(q.store); err != nil {
+			return nil, err
+		}
+	}
+
+	currentWeekStart := q.weekStart(q.bucketNow())
+	firstWeekStart := currentWeekStart.AddDate(0, 0, -7*(numWeeks-1))
+
+	stored, err := q.store.GetWeeklySummaries(firstWeekStart, currentWeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]store.WeeklySummary, numWeeks)
+	for i := range summaries {
+		summaries[i].WeekStart = firstWeekStart.AddDate(0, 0, 7*i)
+	}
+	for _, s := range stored {
+		if i := q.findWeekIndex(s.WeekStart, currentWeekStart, numWeeks); i >= 0 {
+			summaries[i] = s
+		}
+	}
+	return summaries, nil
+}
+
+// GetLastWeekSummary returns the summary of the last completed week, with
+// WeekStart set and everything else zero when it had no runs
+func (q *QueryService) GetLastWeekSummary() (store.WeeklySummary, error) {
+	summaries, err := q.getWeeklySummaries(2)
+	if err != nil {
+		return store.WeeklySummary{}, err
+	}
+	return summaries[0], nil
+}
@@ -0,0 +1,250 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Running migrations again must not fail on already-added columns
+	if err := migrate(db.DB()); err != nil {
+		t.Fatalf("second migrate() error = %v", err)
+	}
+}
+
+func TestCreateManualActivity(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	start := time.Date(2024, 2, 1, 7, 0, 0, 0, time.UTC)
+	avgHR := 140.0
+	manual := &Activity{
+		Name:             "Treadmill",
+		Type:             "Run",
+		StartDate:        start,
+		StartDateLocal:   start,
+		Distance:         8000,
+		MovingTime:       2400,
+		ElapsedTime:      2400,
+		AverageHeartrate: &avgHR,
+	}
+
+	id1, err := db.CreateManualActivity(manual)
+	if err != nil {
+		t.Fatalf("CreateManualActivity() error = %v", err)
+	}
+	if id1 != -1 {
+		t.Errorf("first manual ID = %d, want -1", id1)
+	}
+
+	id2, err := db.CreateManualActivity(manual)
+	if err != nil {
+		t.Fatalf("CreateManualActivity() second error = %v", err)
+	}
+	if id2 != -2 {
+		t.Errorf("second manual ID = %d, want -2", id2)
+	}
+
+	got, err := db.GetActivity(id1)
+	if err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+	if !got.Manual {
+		t.Error("expected Manual = true")
+	}
+	if !got.HasHeartrate || got.AverageHeartrate == nil || *got.AverageHeartrate != avgHR {
+		t.Errorf("expected average HR %v, got %v", avgHR, got.AverageHeartrate)
+	}
+	if got.AverageSpeed != 8000.0/2400.0 {
+		t.Errorf("AverageSpeed = %v, want %v", got.AverageSpeed, 8000.0/2400.0)
+	}
+
+	// Manual activities never need streams from Strava
+	needing, err := db.GetActivitiesNeedingStreams(10)
+	if err != nil {
+		t.Fatalf("GetActivitiesNeedingStreams() error = %v", err)
+	}
+	for _, a := range needing {
+		if a.Manual {
+			t.Errorf("manual activity %d listed as needing streams", a.ID)
+		}
+	}
+	count, err := db.CountActivitiesNeedingStreams()
+	if err != nil {
+		t.Fatalf("CountActivitiesNeedingStreams() error = %v", err)
+	}
+	if count != len(needing) {
+		t.Errorf("CountActivitiesNeedingStreams() = %d, want %d", count, len(needing))
+	}
+
+	// Strava activities are not flagged
+	strava, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatalf("GetActivity(1) error = %v", err)
+	}
+	if strava.Manual {
+		t.Error("expected Strava activity to have Manual = false")
+	}
+}
+
+func TestSetActivityExcluded(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	for _, id := range []int64{1, 2} {
+		if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: id}); err != nil {
+			t.Fatalf("SaveActivityMetrics(%d) error = %v", id, err)
+		}
+	}
+
+	if err := db.SetActivityExcluded(1, true); err != nil {
+		t.Fatalf("SetActivityExcluded() error = %v", err)
+	}
+
+	got, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+	if !got.Excluded {
+		t.Error("expected Excluded = true")
+	}
+
+	// Aggregations skip excluded activities
+	activities, _, err := db.GetActivitiesWithMetrics(10, 0)
+	if err != nil {
+		t.Fatalf("GetActivitiesWithMetrics() error = %v", err)
+	}
+	if len(activities) != 1 || activities[0].ID != 2 {
+		t.Errorf("GetActivitiesWithMetrics() returned %d activities, want only activity 2", len(activities))
+	}
+
+	// The activities list still shows them
+	activities, _, err = db.SearchActivitiesWithMetrics(ActivityFilter{}, ActivitySort{}, 10, 0)
+	if err != nil {
+		t.Fatalf("SearchActivitiesWithMetrics() error = %v", err)
+	}
+	if len(activities) != 2 {
+		t.Errorf("SearchActivitiesWithMetrics() returned %d activities, want 2", len(activities))
+	}
+
+	if err := db.SetActivityExcluded(1, false); err != nil {
+		t.Fatalf("SetActivityExcluded(false) error = %v", err)
+	}
+	activities, _, err = db.GetActivitiesWithMetrics(10, 0)
+	if err != nil {
+		t.Fatalf("GetActivitiesWithMetrics() error = %v", err)
+	}
+	if len(activities) != 2 {
+		t.Errorf("after restoring, GetActivitiesWithMetrics() returned %d activities, want 2", len(activities))
+	}
+
+	if err := db.SetActivityExcluded(999, true); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("SetActivityExcluded(999) error = %v, want ErrActivityNotFound", err)
+	}
+}
+
+func TestListRunDays(t *testing.T) {
+	db := setupTestDB(t) // Activity 1 on Jan 15, activity 2 on Jan 20
+
+	days, err := db.ListRunDays()
+	if err != nil {
+		t.Fatalf("ListRunDays() error = %v", err)
+	}
+	want := []time.Time{
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(days, want) {
+		t.Errorf("ListRunDays() = %v, want %v", days, want)
+	}
+
+	// Excluded runs don't count toward streaks
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatalf("SetActivityExcluded() error = %v", err)
+	}
+	days, _ = db.ListRunDays()
+	if !reflect.DeepEqual(days, want[:1]) {
+		t.Errorf("ListRunDays() after excluding = %v, want %v", days, want[:1])
+	}
+}
+
+func TestEffortsComputed(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	needing := func() []int64 {
+		t.Helper()
+		activities, err := db.GetActivitiesNeedingEfforts()
+		if err != nil {
+			t.Fatalf("GetActivitiesNeedingEfforts() error = %v", err)
+		}
+		var ids []int64
+		for _, a := range activities {
+			ids = append(ids, a.ID)
+		}
+		return ids
+	}
+	markAll := func() {
+		t.Helper()
+		for _, id := range []int64{1, 2} {
+			if err := db.MarkEffortsComputed(id); err != nil {
+				t.Fatalf("MarkEffortsComputed(%d) error = %v", id, err)
+			}
+		}
+	}
+
+	if got := needing(); !reflect.DeepEqual(got, []int64{2, 1}) {
+		t.Fatalf("needing efforts = %v, want [2 1]", got)
+	}
+	markAll()
+	if got := needing(); len(got) != 0 {
+		t.Fatalf("needing efforts after marking = %v, want none", got)
+	}
+
+	// Storing the same summary again keeps the mark; a changed one clears it
+	a, err := db.GetActivity(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Name = "Renamed"
+	if err := db.UpsertActivity(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := needing(); len(got) != 0 {
+		t.Errorf("needing efforts after a rename = %v, want none", got)
+	}
+	a.Distance = 5100
+	if err := db.UpsertActivity(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := needing(); !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("needing efforts after a distance change = %v, want [1]", got)
+	}
+
+	// New streams clear it, and excluded runs wait until they're restored
+	markAll()
+	if err := db.MarkStreamsSynced(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetActivityExcluded(1, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := needing(); !reflect.DeepEqual(got, []int64{2}) {
+		t.Errorf("needing efforts = %v, want [2]", got)
+	}
+	if err := db.SetActivityExcluded(1, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := needing(); !reflect.DeepEqual(got, []int64{2, 1}) {
+		t.Errorf("needing efforts after restoring = %v, want [2 1]", got)
+	}
+
+	markAll()
+	if err := db.ResetEffortsComputed(); err != nil {
+		t.Fatal(err)
+	}
+	if got := needing(); len(got) != 2 {
+		t.Errorf("needing efforts after a reset = %v, want both", got)
+	}
+}
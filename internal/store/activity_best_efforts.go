@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveActivityBestEfforts replaces a run's best efforts. Saving none clears
+// them, as for a run no longer analyzed for records.
+func (s *Store) SaveActivityBestEfforts(activityID int64, efforts []ActivityBestEffort) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		ctx := context.Background()
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteActivityBestEfforts(ctx, activityID); err != nil {
+			return fmt.Errorf("deleting existing best efforts: %w", err)
+		}
+		for _, e := range efforts {
+			if err := qtx.InsertActivityBestEffort(ctx, sqlc.InsertActivityBestEffortParams{
+				ActivityID:      activityID,
+				Category:        e.Category,
+				DistanceMeters:  e.DistanceMeters,
+				DurationSeconds: int64(e.DurationSeconds),
+				StartOffset:     int64(e.StartOffset),
+				EndOffset:       int64(e.EndOffset),
+				AvgHeartrate:    ptrToNullFloat64(e.AvgHeartrate),
+				Unverified:      boolToInt64(e.Unverified),
+			}); err != nil {
+				return fmt.Errorf("saving %s best effort: %w", e.Category, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetTopEfforts returns the fastest efforts in category across every
+// non-excluded run, fastest first, at most one per run
+func (s *Store) GetTopEfforts(category string, limit int) ([]EffortRecord, error) {
+	rows, err := s.queries.ListTopEfforts(context.Background(), sqlc.ListTopEffortsParams{
+		Category: category,
+		Limit:    int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := make([]EffortRecord, 0, len(rows))
+	for _, row := range rows {
+		start, err := time.Parse(time.RFC3339, row.StartDateLocal)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start_date_local %q: %w", row.StartDateLocal, err)
+		}
+		records = append(records, EffortRecord{
+			ActivityBestEffort: ActivityBestEffort{
+				ActivityID:      row.ActivityID,
+				Category:        row.Category,
+				DistanceMeters:  row.DistanceMeters,
+				DurationSeconds: int(row.DurationSeconds),
+				StartOffset:     int(row.StartOffset),
+				EndOffset:       int(row.EndOffset),
+				AvgHeartrate:    nullFloat64ToPtr(row.AvgHeartrate),
+				Unverified:      row.Unverified == 1,
+			},
+			ActivityName:   row.Name,
+			StartDateLocal: start,
+		})
+	}
+	return records, nil
+}
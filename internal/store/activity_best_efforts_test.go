@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestActivityBestEfforts(t *testing.T) {
+	db := setupTestDB(t) // Activity 1: 5 km on Jan 15, activity 2: 10 km on Jan 20
+
+	hr := 160.0
+	effort := func(category string, distance float64, duration int) ActivityBestEffort {
+		return ActivityBestEffort{Category: category, DistanceMeters: distance, DurationSeconds: duration,
+			StartOffset: 60, EndOffset: 60 + duration, AvgHeartrate: &hr}
+	}
+
+	// Saving twice replaces the first
+	if err := db.SaveActivityBestEfforts(1, []ActivityBestEffort{effort("effort_1k", 1000, 330)}); err != nil {
+		t.Fatalf("SaveActivityBestEfforts(1) error = %v", err)
+	}
+	if err := db.SaveActivityBestEfforts(1, []ActivityBestEffort{
+		effort("effort_1k", 1000, 250), effort("effort_5k", 5000, 1400),
+	}); err != nil {
+		t.Fatalf("SaveActivityBestEfforts(1) error = %v", err)
+	}
+	unverified := effort("effort_1k", 1000, 240)
+	unverified.Unverified, unverified.AvgHeartrate = true, nil
+	if err := db.SaveActivityBestEfforts(2, []ActivityBestEffort{unverified}); err != nil {
+		t.Fatalf("SaveActivityBestEfforts(2) error = %v", err)
+	}
+
+	top, err := db.GetTopEfforts("effort_1k", 10)
+	if err != nil {
+		t.Fatalf("GetTopEfforts() error = %v", err)
+	}
+	if len(top) != 2 || top[0].DurationSeconds != 240 || top[1].DurationSeconds != 250 {
+		t.Fatalf("GetTopEfforts() = %+v, want the 240s then 250s efforts", top)
+	}
+	if top[0].ActivityName != "Another Run" || top[0].StartDateLocal.Day() != 20 || !top[0].Unverified || top[0].AvgHeartrate != nil {
+		t.Errorf("fastest effort = %+v, want Another Run's unverified effort without heart rate", top[0])
+	}
+	if top[1].ActivityID != 1 || *top[1].AvgHeartrate != 160 || top[1].EndOffset != 310 {
+		t.Errorf("second effort = %+v, want activity 1's with its heart rate and offsets", top[1])
+	}
+
+	if top, err := db.GetTopEfforts("effort_1k", 1); err != nil || len(top) != 1 {
+		t.Errorf("GetTopEfforts(limit 1) = %d efforts, %v; want 1", len(top), err)
+	}
+
+	// Excluded runs don't count
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatal(err)
+	}
+	if top, err := db.GetTopEfforts("effort_1k", 10); err != nil || len(top) != 1 || top[0].ActivityID != 1 {
+		t.Errorf("GetTopEfforts() after excluding = %+v, %v; want only activity 1", top, err)
+	}
+
+	// Saving none clears a run's efforts
+	if err := db.SaveActivityBestEfforts(1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if top, err := db.GetTopEfforts("effort_5k", 10); err != nil || len(top) != 0 {
+		t.Errorf("GetTopEfforts() after clearing = %+v, %v; want none", top, err)
+	}
+}
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveActivityDetails stores what the detailed activity endpoint returned
+// for a run, replacing its earlier splits and best efforts
+func (s *Store) SaveActivityDetails(d ActivityDetails, splits []StravaSplit, efforts []StravaBestEffort) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		ctx := context.Background()
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.UpsertActivityDetails(ctx, sqlc.UpsertActivityDetailsParams{
+			ActivityID:  d.ActivityID,
+			Description: d.Description,
+			DeviceName:  d.DeviceName,
+			Calories:    d.Calories,
+			GearID:      d.GearID,
+			GearName:    d.GearName,
+			FetchedAt:   d.FetchedAt.UTC().Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("saving details: %w", err)
+		}
+
+		if err := qtx.DeleteStravaSplits(ctx, d.ActivityID); err != nil {
+			return fmt.Errorf("deleting existing splits: %w", err)
+		}
+		for _, sp := range splits {
+			if err := qtx.InsertStravaSplit(ctx, sqlc.InsertStravaSplitParams{
+				ActivityID:          d.ActivityID,
+				Units:               sp.Units,
+				Split:               int64(sp.Split),
+				Distance:            sp.Distance,
+				ElapsedTime:         int64(sp.ElapsedTime),
+				MovingTime:          int64(sp.MovingTime),
+				ElevationDifference: sp.ElevationDifference,
+				AverageSpeed:        sp.AverageSpeed,
+				AverageHeartrate:    ptrToNullFloat64(sp.AverageHeartrate),
+			}); err != nil {
+				return fmt.Errorf("saving %s split %d: %w", sp.Units, sp.Split, err)
+			}
+		}
+
+		if err := qtx.DeleteStravaBestEfforts(ctx, d.ActivityID); err != nil {
+			return fmt.Errorf("deleting existing best efforts: %w", err)
+		}
+		// Strava's efforts help check the run's own, so it's analyzed again
+		if len(efforts) > 0 {
+			if err := qtx.ClearEffortsComputed(ctx, d.ActivityID); err != nil {
+				return fmt.Errorf("marking best efforts for analysis: %w", err)
+			}
+		}
+		for _, e := range efforts {
+			if err := qtx.InsertStravaBestEffort(ctx, sqlc.InsertStravaBestEffortParams{
+				ActivityID:  d.ActivityID,
+				Name:        e.Name,
+				Distance:    e.Distance,
+				ElapsedTime: int64(e.ElapsedTime),
+				MovingTime:  int64(e.MovingTime),
+				StartIndex:  int64(e.StartIndex),
+				EndIndex:    int64(e.EndIndex),
+			}); err != nil {
+				return fmt.Errorf("saving %s best effort: %w", e.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetActivityDetails returns a run's detailed fields, or nil if they
+// haven't been fetched
+func (s *Store) GetActivityDetails(activityID int64) (*ActivityDetails, error) {
+	row, err := s.queries.GetActivityDetails(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fetchedAt, err := time.Parse(time.RFC3339, row.FetchedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parsing fetched_at: %w", err)
+	}
+	return &ActivityDetails{
+		ActivityID:  row.ActivityID,
+		Description: row.Description,
+		DeviceName:  row.DeviceName,
+		Calories:    row.Calories,
+		GearID:      row.GearID,
+		GearName:    row.GearName,
+		FetchedAt:   fetchedAt,
+	}, nil
+}
+
+// GetStravaSplits returns Strava's splits for a run in units (SplitsMetric
+// or SplitsStandard), in order
+func (s *Store) GetStravaSplits(activityID int64, units string) ([]StravaSplit, error) {
+	rows, err := s.queries.ListStravaSplits(context.Background(), sqlc.ListStravaSplitsParams{
+		ActivityID: activityID,
+		Units:      units,
+	})
+	if err != nil {
+		return nil, err
+	}
+	splits := make([]StravaSplit, 0, len(rows))
+	for _, row := range rows {
+		splits = append(splits, StravaSplit{
+			ActivityID:          row.ActivityID,
+			Units:               row.Units,
+			Split:               int(row.Split),
+			Distance:            row.Distance,
+			ElapsedTime:         int(row.ElapsedTime),
+			MovingTime:          int(row.MovingTime),
+			ElevationDifference: row.ElevationDifference,
+			AverageSpeed:        row.AverageSpeed,
+			AverageHeartrate:    nullFloat64ToPtr(row.AverageHeartrate),
+		})
+	}
+	return splits, nil
+}
+
+// GetActivitiesNeedingDetails returns the IDs of synced runs with streams
+// whose detailed activity hasn't been fetched, newest first
+func (s *Store) GetActivitiesNeedingDetails(limit int) ([]int64, error) {
+	return s.queries.GetActivitiesNeedingDetails(context.Background(), int64(limit))
+}
+
+// CountActivitiesNeedingDetails counts the runs GetActivitiesNeedingDetails
+// would return without a limit
+func (s *Store) CountActivitiesNeedingDetails() (int, error) {
+	n, err := s.queries.CountActivitiesNeedingDetails(context.Background())
+	return int(n), err
+}
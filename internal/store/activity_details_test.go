@@ -0,0 +1,83 @@
+package store
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestActivityDetails(t *testing.T) {
+	db := setupTestDB(t)
+	fetched := time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)
+
+	// Both test activities have streams and no details yet
+	ids, err := db.GetActivitiesNeedingDetails(10)
+	if err != nil {
+		t.Fatalf("GetActivitiesNeedingDetails failed: %v", err)
+	}
+	if !slices.Equal(ids, []int64{2, 1}) {
+		t.Fatalf("GetActivitiesNeedingDetails() = %v, want [2 1]", ids)
+	}
+	if d, err := db.GetActivityDetails(1); err != nil || d != nil {
+		t.Fatalf("GetActivityDetails() = %v, %v before fetching, want nil", d, err)
+	}
+
+	hr := 152.0
+	if err := db.SaveActivityDetails(
+		ActivityDetails{ActivityID: 1, Description: "Tempo by the river", DeviceName: "Garmin Forerunner 255", Calories: 410, GearID: "g1", GearName: "Pegasus 40", FetchedAt: fetched},
+		[]StravaSplit{
+			{Units: SplitsMetric, Split: 2, Distance: 1000, ElapsedTime: 298, MovingTime: 295, AverageSpeed: 3.39},
+			{Units: SplitsMetric, Split: 1, Distance: 1000, ElapsedTime: 305, MovingTime: 300, AverageSpeed: 3.33, AverageHeartrate: &hr},
+			{Units: SplitsStandard, Split: 1, Distance: 1609, ElapsedTime: 490, MovingTime: 485, AverageSpeed: 3.32},
+		},
+		[]StravaBestEffort{{Name: "1k", Distance: 1000, ElapsedTime: 290, MovingTime: 290, StartIndex: 100, EndIndex: 390}},
+	); err != nil {
+		t.Fatalf("SaveActivityDetails failed: %v", err)
+	}
+	// A run with nothing extra is still marked fetched
+	if err := db.SaveActivityDetails(ActivityDetails{ActivityID: 2, FetchedAt: fetched}, nil, nil); err != nil {
+		t.Fatalf("SaveActivityDetails failed: %v", err)
+	}
+
+	if n, err := db.CountActivitiesNeedingDetails(); err != nil || n != 0 {
+		t.Errorf("CountActivitiesNeedingDetails() = %d, %v after fetching, want 0", n, err)
+	}
+
+	d, err := db.GetActivityDetails(1)
+	if err != nil || d == nil {
+		t.Fatalf("GetActivityDetails() = %v, %v", d, err)
+	}
+	if d.Description != "Tempo by the river" || d.DeviceName != "Garmin Forerunner 255" || d.GearName != "Pegasus 40" || d.Calories != 410 || !d.FetchedAt.Equal(fetched) {
+		t.Errorf("GetActivityDetails() = %+v", d)
+	}
+
+	km, err := db.GetStravaSplits(1, SplitsMetric)
+	if err != nil {
+		t.Fatalf("GetStravaSplits failed: %v", err)
+	}
+	if len(km) != 2 || km[0].Split != 1 || km[0].AverageHeartrate == nil || *km[0].AverageHeartrate != hr || km[1].AverageHeartrate != nil {
+		t.Errorf("GetStravaSplits(metric) = %+v, want splits 1 and 2 with HR on the first", km)
+	}
+	if miles, _ := db.GetStravaSplits(1, SplitsStandard); len(miles) != 1 {
+		t.Errorf("GetStravaSplits(standard) = %+v, want 1 split", miles)
+	}
+
+	efforts, err := db.GetStravaBestEfforts(1)
+	if err != nil || len(efforts) != 1 || efforts[0].EndIndex != 390 {
+		t.Errorf("GetStravaBestEfforts() = %+v, %v, want the 1k", efforts, err)
+	}
+
+	// Fetching again replaces the splits and best efforts
+	if err := db.SaveActivityDetails(ActivityDetails{ActivityID: 1, Description: "Edited", FetchedAt: fetched}, nil, nil); err != nil {
+		t.Fatalf("SaveActivityDetails failed: %v", err)
+	}
+	if d, _ := db.GetActivityDetails(1); d.Description != "Edited" {
+		t.Errorf("Description = %q after refetching, want Edited", d.Description)
+	}
+	if km, _ := db.GetStravaSplits(1, SplitsMetric); len(km) != 0 {
+		t.Errorf("GetStravaSplits() = %+v after refetching, want none", km)
+	}
+	if efforts, _ := db.GetStravaBestEfforts(1); len(efforts) != 0 {
+		t.Errorf("GetStravaBestEfforts() = %+v after refetching, want none", efforts)
+	}
+}
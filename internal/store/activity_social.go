@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveActivitySocial stores a run's kudos, comment and photo counts. An
+// empty PhotoURL keeps the one already stored.
+func (s *Store) SaveActivitySocial(a ActivitySocial) error {
+	return s.queries.UpsertActivitySocial(context.Background(), sqlc.UpsertActivitySocialParams{
+		ActivityID:   a.ActivityID,
+		KudosCount:   int64(a.KudosCount),
+		CommentCount: int64(a.CommentCount),
+		PhotoCount:   int64(a.PhotoCount),
+		PhotoUrl:     toNullString(a.PhotoURL),
+	})
+}
+
+// GetActivitySocial returns a run's kudos, comment and photo counts, or nil
+// if none are stored
+func (s *Store) GetActivitySocial(activityID int64) (*ActivitySocial, error) {
+	row, err := s.queries.GetActivitySocial(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	social := activitySocialFromRow(row)
+	return &social, nil
+}
+
+// GetAllActivitySocial returns the stored counts of every run, by activity ID
+func (s *Store) GetAllActivitySocial() (map[int64]ActivitySocial, error) {
+	rows, err := s.queries.ListActivitySocial(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	social := make(map[int64]ActivitySocial, len(rows))
+	for _, row := range rows {
+		social[row.ActivityID] = activitySocialFromRow(row)
+	}
+	return social, nil
+}
+
+// DeleteActivitySocial removes the counts and photo URLs of every run
+func (s *Store) DeleteActivitySocial() error {
+	return s.queries.DeleteAllActivitySocial(context.Background())
+}
+
+// ClearActivityPhotoURLs removes every stored photo URL, keeping the counts
+func (s *Store) ClearActivityPhotoURLs() error {
+	return s.queries.ClearActivityPhotoURLs(context.Background())
+}
+
+func activitySocialFromRow(row sqlc.ActivitySocial) ActivitySocial {
+	return ActivitySocial{
+		ActivityID:   row.ActivityID,
+		KudosCount:   int(row.KudosCount),
+		CommentCount: int(row.CommentCount),
+		PhotoCount:   int(row.PhotoCount),
+		PhotoURL:     row.PhotoUrl.String,
+	}
+}
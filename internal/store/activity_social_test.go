@@ -0,0 +1,49 @@
+package store
+
+import "testing"
+
+func TestActivitySocial(t *testing.T) {
+	db := setupTestDB(t)
+
+	if s, err := db.GetActivitySocial(1); err != nil || s != nil {
+		t.Fatalf("GetActivitySocial() = %v, %v before syncing, want nil", s, err)
+	}
+
+	if err := db.SaveActivitySocial(ActivitySocial{ActivityID: 1, KudosCount: 3, CommentCount: 1, PhotoCount: 2, PhotoURL: "https://example.com/p.jpg"}); err != nil {
+		t.Fatalf("SaveActivitySocial failed: %v", err)
+	}
+	// Counts from the activity list come without the photo URL
+	if err := db.SaveActivitySocial(ActivitySocial{ActivityID: 1, KudosCount: 9, CommentCount: 2, PhotoCount: 2}); err != nil {
+		t.Fatalf("SaveActivitySocial failed: %v", err)
+	}
+	if err := db.SaveActivitySocial(ActivitySocial{ActivityID: 2, KudosCount: 1}); err != nil {
+		t.Fatalf("SaveActivitySocial failed: %v", err)
+	}
+
+	s, err := db.GetActivitySocial(1)
+	if err != nil || s == nil {
+		t.Fatalf("GetActivitySocial() = %v, %v", s, err)
+	}
+	want := ActivitySocial{ActivityID: 1, KudosCount: 9, CommentCount: 2, PhotoCount: 2, PhotoURL: "https://example.com/p.jpg"}
+	if *s != want {
+		t.Errorf("GetActivitySocial() = %+v, want %+v", *s, want)
+	}
+
+	if err := db.ClearActivityPhotoURLs(); err != nil {
+		t.Fatalf("ClearActivityPhotoURLs failed: %v", err)
+	}
+	all, err := db.GetAllActivitySocial()
+	if err != nil {
+		t.Fatalf("GetAllActivitySocial failed: %v", err)
+	}
+	if len(all) != 2 || all[1].PhotoURL != "" || all[1].KudosCount != 9 || all[2].KudosCount != 1 {
+		t.Errorf("GetAllActivitySocial() = %+v after clearing photo URLs", all)
+	}
+
+	if err := db.DeleteActivitySocial(); err != nil {
+		t.Fatalf("DeleteActivitySocial failed: %v", err)
+	}
+	if all, err := db.GetAllActivitySocial(); err != nil || len(all) != 0 {
+		t.Errorf("GetAllActivitySocial() = %v, %v after deleting, want none", all, err)
+	}
+}
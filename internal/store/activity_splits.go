@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveActivitySplits replaces a run's computed splits in every unit
+func (s *Store) SaveActivitySplits(activityID int64, splits []ActivitySplit) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		ctx := context.Background()
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteActivitySplits(ctx, activityID); err != nil {
+			return fmt.Errorf("deleting existing splits: %w", err)
+		}
+		for _, sp := range splits {
+			if err := qtx.InsertActivitySplit(ctx, sqlc.InsertActivitySplitParams{
+				ActivityID:       activityID,
+				Units:            sp.Units,
+				Split:            int64(sp.Split),
+				Distance:         sp.Distance,
+				Partial:          boolToInt64(sp.Partial),
+				Duration:         int64(sp.Duration),
+				StartOffset:      int64(sp.StartOffset),
+				AverageHeartrate: ptrToNullFloat64(sp.AverageHeartrate),
+				AverageCadence:   ptrToNullFloat64(sp.AverageCadence),
+			}); err != nil {
+				return fmt.Errorf("saving %s split %d: %w", sp.Units, sp.Split, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetActivitySplits returns a run's computed splits in units (SplitsMetric
+// or SplitsStandard), in order. It's empty for runs analyzed before splits
+// were stored.
+func (s *Store) GetActivitySplits(activityID int64, units string) ([]ActivitySplit, error) {
+	rows, err := s.queries.ListActivitySplits(context.Background(), sqlc.ListActivitySplitsParams{
+		ActivityID: activityID,
+		Units:      units,
+	})
+	if err != nil {
+		return nil, err
+	}
+	splits := make([]ActivitySplit, 0, len(rows))
+	for _, row := range rows {
+		splits = append(splits, activitySplitFromRow(row))
+	}
+	return splits, nil
+}
+
+// GetFastestSplits returns the fastest full splits in units across every
+// non-excluded run, fastest first, wherever in the run they fell
+func (s *Store) GetFastestSplits(units string, limit int) ([]SplitRecord, error) {
+	rows, err := s.queries.ListFastestSplits(context.Background(), sqlc.ListFastestSplitsParams{
+		Units: units,
+		Limit: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	records := make([]SplitRecord, 0, len(rows))
+	for _, row := range rows {
+		start, err := time.Parse(time.RFC3339, row.StartDateLocal)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start_date_local %q: %w", row.StartDateLocal, err)
+		}
+		records = append(records, SplitRecord{
+			ActivitySplit: activitySplitFromRow(sqlc.ActivitySplit{
+				ActivityID:       row.ActivityID,
+				Units:            row.Units,
+				Split:            row.Split,
+				Distance:         row.Distance,
+				Partial:          row.Partial,
+				Duration:         row.Duration,
+				StartOffset:      row.StartOffset,
+				AverageHeartrate: row.AverageHeartrate,
+				AverageCadence:   row.AverageCadence,
+			}),
+			ActivityName:   row.Name,
+			StartDateLocal: start,
+		})
+	}
+	return records, nil
+}
+
+func activitySplitFromRow(row sqlc.ActivitySplit) ActivitySplit {
+	return ActivitySplit{
+		ActivityID:       row.ActivityID,
+		Units:            row.Units,
+		Split:            int(row.Split),
+		Distance:         row.Distance,
+		Partial:          row.Partial == 1,
+		Duration:         int(row.Duration),
+		StartOffset:      int(row.StartOffset),
+		AverageHeartrate: nullFloat64ToPtr(row.AverageHeartrate),
+		AverageCadence:   nullFloat64ToPtr(row.AverageCadence),
+	}
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestActivitySplits(t *testing.T) {
+	db := setupTestDB(t) // Activity 1: 5 km on Jan 15, activity 2: 10 km on Jan 20
+
+	hr := 150.0
+	km := func(activityID int64, split, duration int) ActivitySplit {
+		return ActivitySplit{ActivityID: activityID, Units: SplitsMetric, Split: split, Distance: 1000,
+			Duration: duration, StartOffset: (split - 1) * 300, AverageHeartrate: &hr}
+	}
+
+	// Saving twice replaces the first
+	if err := db.SaveActivitySplits(1, []ActivitySplit{km(1, 1, 400)}); err != nil {
+		t.Fatalf("SaveActivitySplits(1) error = %v", err)
+	}
+	one := []ActivitySplit{
+		km(1, 1, 300), km(1, 2, 290),
+		{ActivityID: 1, Units: SplitsMetric, Split: 3, Distance: 500, Partial: true, Duration: 100, StartOffset: 590},
+		{ActivityID: 1, Units: SplitsStandard, Split: 1, Distance: 1609.344, Duration: 480},
+	}
+	if err := db.SaveActivitySplits(1, one); err != nil {
+		t.Fatalf("SaveActivitySplits(1) error = %v", err)
+	}
+	if err := db.SaveActivitySplits(2, []ActivitySplit{km(2, 1, 280), km(2, 2, 295)}); err != nil {
+		t.Fatalf("SaveActivitySplits(2) error = %v", err)
+	}
+
+	got, err := db.GetActivitySplits(1, SplitsMetric)
+	if err != nil {
+		t.Fatalf("GetActivitySplits() error = %v", err)
+	}
+	if len(got) != 3 || got[1].Duration != 290 || *got[1].AverageHeartrate != 150 {
+		t.Fatalf("GetActivitySplits() = %+v, want the 3 km splits saved last", got)
+	}
+	if !got[2].Partial || got[2].AverageHeartrate != nil || got[2].StartOffset != 590 {
+		t.Errorf("partial split = %+v, want partial without heart rate", got[2])
+	}
+
+	// The partial split is fastest but isn't a full kilometer
+	fastest, err := db.GetFastestSplits(SplitsMetric, 3)
+	if err != nil {
+		t.Fatalf("GetFastestSplits() error = %v", err)
+	}
+	var durations []int
+	for _, r := range fastest {
+		durations = append(durations, r.Duration)
+	}
+	if len(fastest) != 3 || durations[0] != 280 || durations[1] != 290 || durations[2] != 295 {
+		t.Fatalf("GetFastestSplits() durations = %v, want [280 290 295]", durations)
+	}
+	if fastest[0].ActivityName != "Another Run" || fastest[0].StartDateLocal.Day() != 20 {
+		t.Errorf("fastest split from %q on %v, want Another Run on Jan 20", fastest[0].ActivityName, fastest[0].StartDateLocal)
+	}
+
+	// Excluded runs don't count
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatalf("SetActivityExcluded() error = %v", err)
+	}
+	fastest, err = db.GetFastestSplits(SplitsMetric, 3)
+	if err != nil {
+		t.Fatalf("GetFastestSplits() error = %v", err)
+	}
+	if len(fastest) != 2 || fastest[0].Duration != 290 {
+		t.Errorf("GetFastestSplits() = %+v, want activity 1's two full splits", fastest)
+	}
+}
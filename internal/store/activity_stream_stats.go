@@ -0,0 +1,213 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveActivityStreamStats stores or replaces an activity's stream aggregates.
+func (s *Store) SaveActivityStreamStats(stats *ActivityStreamStats) error {
+	return s.queries.SaveActivityStreamStats(context.Background(), sqlc.SaveActivityStreamStatsParams{
+		ActivityID:     stats.ActivityID,
+		MovingTime:     int64(stats.MovingTime),
+		MovingDistance: stats.MovingDistance,
+		HrSum:          stats.HRSum,
+		HrCount:        int64(stats.HRCount),
+		CadenceSum:     stats.CadenceSum,
+		CadenceCount:   int64(stats.CadenceCount),
+		MaxHr:          sql.NullInt64{Int64: int64(stats.MaxHR), Valid: true},
+	})
+}
+
+// ListActivitiesMissingStreamStats returns the IDs of analyzed activities
+// without complete cached stream aggregates, such as those analyzed before
+// the cache or its max HR existed.
+func (s *Store) ListActivitiesMissingStreamStats() ([]int64, error) {
+	return s.queries.ListActivitiesMissingStreamStats(context.Background())
+}
+
+// StreamStatsRules are the thresholds the service summarizes streams with,
+// passed in so stream stats can be computed without loading the points
+type StreamStatsRules struct {
+	MinHeartrate      int     // readings must be above this bpm to count
+	MaxHeartrate      int     // and below this one
+	MinMovingSpeed    float64 // m/s a point must exceed to count as moving
+	CadenceMultiplier float64 // turns stored cadence into steps per minute
+}
+
+// ComputeStreamStatsForActivities summarizes activities' streams into
+// stream stats without returning the points, for callers that only need
+// totals and averages. Row-stored streams are summed in SQL; compressed
+// ones are decoded and summed here. Every requested ID gets an entry, of
+// zeros if it has no streams.
+func (s *Store) ComputeStreamStatsForActivities(activityIDs []int64, rules StreamStatsRules) (map[int64]*ActivityStreamStats, error) {
+	result := make(map[int64]*ActivityStreamStats, len(activityIDs))
+	for _, id := range activityIDs {
+		result[id] = &ActivityStreamStats{ActivityID: id}
+	}
+	for _, chunk := range chunkIDs(activityIDs) {
+		if err := s.computeStreamStats(chunk, rules, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// computeStreamStats fills result for one chunk of activities
+func (s *Store) computeStreamStats(activityIDs []int64, rules StreamStatsRules, result map[int64]*ActivityStreamStats) error {
+	placeholders, idArgs := inClause(activityIDs)
+
+	blobRows, err := s.db.Query(`SELECT activity_id, data FROM stream_blobs WHERE activity_id IN (`+placeholders+`)`, idArgs...)
+	if err != nil {
+		return err
+	}
+	defer blobRows.Close()
+
+	for blobRows.Next() {
+		var id int64
+		var data []byte
+		if err := blobRows.Scan(&id, &data); err != nil {
+			return err
+		}
+		points, err := decodeStreams(id, data)
+		if err != nil {
+			return fmt.Errorf("decoding streams for %d: %w", id, err)
+		}
+		result[id] = summarizeStreams(id, points, rules)
+	}
+	if err := blobRows.Err(); err != nil {
+		return err
+	}
+	blobRows.Close()
+
+	// Moving time needs each point's predecessor, hence the window
+	query := `
+		SELECT activity_id,
+			COALESCE(SUM(CASE WHEN velocity_smooth > ? THEN time_offset - prev_offset END), 0),
+			COALESCE(SUM(CASE WHEN heartrate > ? AND heartrate < ? THEN heartrate END), 0),
+			COUNT(CASE WHEN heartrate > ? AND heartrate < ? THEN 1 END),
+			COALESCE(MAX(CASE WHEN heartrate > ? AND heartrate < ? THEN heartrate END), 0),
+			COALESCE(SUM(CASE WHEN cadence > 0 THEN cadence END), 0),
+			COUNT(CASE WHEN cadence > 0 THEN 1 END),
+			COALESCE((SELECT d.distance FROM streams d
+				WHERE d.activity_id = w.activity_id AND d.distance IS NOT NULL
+				ORDER BY d.time_offset DESC LIMIT 1), 0)
+		FROM (
+			SELECT activity_id, time_offset, velocity_smooth, heartrate, cadence,
+				LAG(time_offset) OVER (PARTITION BY activity_id ORDER BY time_offset) AS prev_offset
+			FROM streams
+			WHERE activity_id IN (` + placeholders + `)
+		) w
+		GROUP BY activity_id`
+
+	args := []interface{}{rules.MinMovingSpeed,
+		rules.MinHeartrate, rules.MaxHeartrate,
+		rules.MinHeartrate, rules.MaxHeartrate,
+		rules.MinHeartrate, rules.MaxHeartrate}
+	rows, err := s.db.Query(query, append(args, idArgs...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var st ActivityStreamStats
+		var cadenceSum float64
+		if err := rows.Scan(&st.ActivityID, &st.MovingTime, &st.HRSum, &st.HRCount, &st.MaxHR,
+			&cadenceSum, &st.CadenceCount, &st.MovingDistance); err != nil {
+			return err
+		}
+		st.CadenceSum = cadenceSum * rules.CadenceMultiplier
+		result[st.ActivityID] = &st
+	}
+	return rows.Err()
+}
+
+// summarizeStreams is computeStreamStats' SQL for decoded points
+func summarizeStreams(activityID int64, points []StreamPoint, rules StreamStatsRules) *ActivityStreamStats {
+	st := &ActivityStreamStats{ActivityID: activityID}
+	for i, p := range points {
+		if p.Heartrate != nil && *p.Heartrate > rules.MinHeartrate && *p.Heartrate < rules.MaxHeartrate {
+			st.HRSum += float64(*p.Heartrate)
+			st.HRCount++
+			st.MaxHR = max(st.MaxHR, *p.Heartrate)
+		}
+		if p.Cadence != nil && *p.Cadence > 0 {
+			st.CadenceSum += float64(*p.Cadence) * rules.CadenceMultiplier
+			st.CadenceCount++
+		}
+		if i > 0 && p.VelocitySmooth != nil && *p.VelocitySmooth > rules.MinMovingSpeed {
+			st.MovingTime += p.TimeOffset - points[i-1].TimeOffset
+		}
+		if p.Distance != nil {
+			st.MovingDistance = *p.Distance
+		}
+	}
+	return st
+}
+
+// GetPeriodTotals sums the analyzed, non-excluded activities that started in
+// [start, end) on the day-bucketing clock.
+func (s *Store) GetPeriodTotals(start, end time.Time) (PeriodTotals, error) {
+	row, err := s.queries.GetPeriodTotals(context.Background(), sqlc.GetPeriodTotalsParams{
+		UtcDays:   s.utcDays,
+		StartDate: formatBound(start),
+		EndDate:   formatBound(end),
+	})
+	if err != nil {
+		return PeriodTotals{}, err
+	}
+	return PeriodTotals{
+		Start:          start,
+		RunCount:       int(row.RunCount),
+		Distance:       row.Distance,
+		MovingTime:     int(row.MovingTime),
+		MovingDistance: row.MovingDistance,
+		HRSum:          row.HrSum,
+		HRCount:        int(row.HrCount),
+		CadenceSum:     row.CadenceSum,
+		CadenceCount:   int(row.CadenceCount),
+		EFSum:          row.EfSum,
+		EFCount:        int(row.EfCount),
+	}, nil
+}
+
+// GetMonthlyTotals sums the analyzed, non-excluded activities by calendar
+// month on the day-bucketing clock, from the month of from up to but not
+// including the month of to. Months without runs are left out.
+func (s *Store) GetMonthlyTotals(from, to time.Time) ([]PeriodTotals, error) {
+	rows, err := s.queries.GetMonthlyTotals(context.Background(), sqlc.GetMonthlyTotalsParams{
+		UtcDays:   s.utcDays,
+		FromMonth: from.Format("2006-01"),
+		ToMonth:   to.Format("2006-01"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make([]PeriodTotals, 0, len(rows))
+	for _, row := range rows {
+		month, err := time.Parse("2006-01", row.Month)
+		if err != nil {
+			return nil, fmt.Errorf("parsing month %q: %w", row.Month, err)
+		}
+		totals = append(totals, PeriodTotals{
+			Start:          month,
+			RunCount:       int(row.RunCount),
+			Distance:       row.Distance,
+			MovingTime:     int(row.MovingTime),
+			MovingDistance: row.MovingDistance,
+			HRSum:          row.HrSum,
+			HRCount:        int(row.HrCount),
+			CadenceSum:     row.CadenceSum,
+			CadenceCount:   int(row.CadenceCount),
+			EFSum:          row.EfSum,
+			EFCount:        int(row.EfCount),
+		})
+	}
+	return totals, nil
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityStreamStats(t *testing.T) {
+	db := setupTestDB(t) // Activity 1: 5 km on Jan 15, activity 2: 10 km on Jan 20
+
+	ef := 1.4
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1, EfficiencyFactor: &ef}); err != nil {
+		t.Fatalf("SaveActivityMetrics(1) error = %v", err)
+	}
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 2}); err != nil {
+		t.Fatalf("SaveActivityMetrics(2) error = %v", err)
+	}
+
+	missing, err := db.ListActivitiesMissingStreamStats()
+	if err != nil {
+		t.Fatalf("ListActivitiesMissingStreamStats() error = %v", err)
+	}
+	if len(missing) != 2 {
+		t.Fatalf("missing = %v, want both activities", missing)
+	}
+
+	// Saving twice replaces the first
+	for _, hr := range []float64{100, 150} {
+		if err := db.SaveActivityStreamStats(&ActivityStreamStats{
+			ActivityID: 1, MovingTime: 1500, MovingDistance: 4990,
+			HRSum: hr * 1500, HRCount: 1500, CadenceSum: 170 * 1400, CadenceCount: 1400,
+		}); err != nil {
+			t.Fatalf("SaveActivityStreamStats() error = %v", err)
+		}
+	}
+	missing, err = db.ListActivitiesMissingStreamStats()
+	if err != nil {
+		t.Fatalf("ListActivitiesMissingStreamStats() error = %v", err)
+	}
+	if len(missing) != 1 || missing[0] != 2 {
+		t.Errorf("missing = %v, want [2]", missing)
+	}
+
+	want := PeriodTotals{
+		RunCount: 2, Distance: 15000, MovingTime: 1500, MovingDistance: 4990,
+		HRSum: 150 * 1500, HRCount: 1500, CadenceSum: 170 * 1400, CadenceCount: 1400,
+		EFSum: 1.4, EFCount: 1,
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	totals, err := db.GetPeriodTotals(start, start.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("GetPeriodTotals() error = %v", err)
+	}
+	want.Start = start
+	if totals != want {
+		t.Errorf("GetPeriodTotals() = %+v, want %+v", totals, want)
+	}
+
+	empty, err := db.GetPeriodTotals(start.AddDate(0, 1, 0), start.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatalf("GetPeriodTotals() error = %v", err)
+	}
+	if empty.RunCount != 0 || empty.Distance != 0 {
+		t.Errorf("empty period = %+v, want zero", empty)
+	}
+
+	monthly, err := db.GetMonthlyTotals(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetMonthlyTotals() error = %v", err)
+	}
+	want.Start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if len(monthly) != 1 || monthly[0] != want {
+		t.Errorf("GetMonthlyTotals() = %+v, want [%+v]", monthly, want)
+	}
+}
+
+func TestGetWeekActivities_StreamStats(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+	want := ActivityStreamStats{
+		ActivityID: 1, MovingTime: 1500, MovingDistance: 4990,
+		HRSum: 150 * 1500, HRCount: 1500, MaxHR: 172,
+	}
+	if err := db.SaveActivityStreamStats(&want); err != nil {
+		t.Fatalf("SaveActivityStreamStats() error = %v", err)
+	}
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := db.GetWeekActivities(start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetWeekActivities() error = %v", err)
+	}
+	if len(activities) != 1 || activities[0].StreamStats == nil {
+		t.Fatalf("GetWeekActivities() = %+v, want activity 1 with stream stats", activities)
+	}
+	if got := *activities[0].StreamStats; got != want {
+		t.Errorf("StreamStats = %+v, want %+v", got, want)
+	}
+	if got := activities[0].StreamStats.AvgHR(); got != 150 {
+		t.Errorf("AvgHR() = %v, want 150", got)
+	}
+
+	// Rows cached before max HR existed are treated as missing
+	if _, err := db.db.Exec(`UPDATE activity_stream_stats SET max_hr = NULL`); err != nil {
+		t.Fatalf("clearing max_hr: %v", err)
+	}
+	activities, err = db.GetWeekActivities(start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetWeekActivities() error = %v", err)
+	}
+	if activities[0].StreamStats != nil {
+		t.Errorf("StreamStats = %+v, want nil without max HR", activities[0].StreamStats)
+	}
+	missing, err := db.ListActivitiesMissingStreamStats()
+	if err != nil {
+		t.Fatalf("ListActivitiesMissingStreamStats() error = %v", err)
+	}
+	if len(missing) != 1 || missing[0] != 1 {
+		t.Errorf("missing = %v, want [1]", missing)
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestActivityTags(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	tags, err := db.GetActivityTags(1)
+	if err != nil {
+		t.Fatalf("GetActivityTags() error = %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("GetActivityTags() = %v, want none", tags)
+	}
+
+	if err := db.SetActivityTags(1, []string{"race", "new shoes"}); err != nil {
+		t.Fatalf("SetActivityTags() error = %v", err)
+	}
+	tags, _ = db.GetActivityTags(1)
+	if want := []string{"new shoes", "race"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("GetActivityTags() = %v, want %v", tags, want)
+	}
+
+	// Setting again replaces rather than appends
+	if err := db.SetActivityTags(1, []string{"sick"}); err != nil {
+		t.Fatalf("SetActivityTags() error = %v", err)
+	}
+	tags, _ = db.GetActivityTags(1)
+	if want := []string{"sick"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("GetActivityTags() = %v, want %v", tags, want)
+	}
+
+	// Other activities are unaffected
+	tags, _ = db.GetActivityTags(2)
+	if len(tags) != 0 {
+		t.Errorf("GetActivityTags(2) = %v, want none", tags)
+	}
+
+	all, err := db.GetAllActivityTags()
+	if err != nil {
+		t.Fatalf("GetAllActivityTags() error = %v", err)
+	}
+	if want := map[int64][]string{1: {"sick"}}; !reflect.DeepEqual(all, want) {
+		t.Errorf("GetAllActivityTags() = %v, want %v", all, want)
+	}
+}
+
+func TestActivityNote(t *testing.T) {
+	db := setupTestDB(t)
+
+	note, err := db.GetActivityNote(1)
+	if err != nil {
+		t.Fatalf("GetActivityNote() error = %v", err)
+	}
+	if note != "" {
+		t.Errorf("GetActivityNote() = %q, want empty", note)
+	}
+
+	if err := db.SetActivityNote(1, "felt sick"); err != nil {
+		t.Fatalf("SetActivityNote() error = %v", err)
+	}
+	if err := db.SetActivityNote(1, "felt sick, cut short"); err != nil {
+		t.Fatalf("SetActivityNote() update error = %v", err)
+	}
+	note, _ = db.GetActivityNote(1)
+	if note != "felt sick, cut short" {
+		t.Errorf("GetActivityNote() = %q, want %q", note, "felt sick, cut short")
+	}
+	if notes, err := db.GetActivityNotes(); err != nil || len(notes) != 1 || notes[1] != note {
+		t.Errorf("GetActivityNotes() = %v, %v, want activity 1's note", notes, err)
+	}
+
+	if err := db.SetActivityNote(1, ""); err != nil {
+		t.Fatalf("SetActivityNote() clear error = %v", err)
+	}
+	note, _ = db.GetActivityNote(1)
+	if note != "" {
+		t.Errorf("GetActivityNote() after clear = %q, want empty", note)
+	}
+}
+
+func TestActivityTemperature(t *testing.T) {
+	db := setupTestDB(t)
+
+	temp, err := db.GetActivityTemperature(1)
+	if err != nil {
+		t.Fatalf("GetActivityTemperature() error = %v", err)
+	}
+	if temp != nil {
+		t.Errorf("GetActivityTemperature() = %v, want nil", *temp)
+	}
+
+	hot, warm := 31.5, 24.0
+	if err := db.SetActivityTemperature(1, &hot, TemperatureSourceManual); err != nil {
+		t.Fatalf("SetActivityTemperature() error = %v", err)
+	}
+	if err := db.SetActivityTemperature(2, &warm, TemperatureSourceManual); err != nil {
+		t.Fatalf("SetActivityTemperature(2) error = %v", err)
+	}
+	temp, _ = db.GetActivityTemperature(1)
+	if temp == nil || *temp != hot {
+		t.Errorf("GetActivityTemperature() = %v, want %v", temp, hot)
+	}
+
+	temps, err := db.GetActivityTemperatures()
+	if err != nil {
+		t.Fatalf("GetActivityTemperatures() error = %v", err)
+	}
+	if want := map[int64]float64{1: hot, 2: warm}; !reflect.DeepEqual(temps, want) {
+		t.Errorf("GetActivityTemperatures() = %v, want %v", temps, want)
+	}
+
+	if err := db.SetActivityTemperature(1, nil, TemperatureSourceManual); err != nil {
+		t.Fatalf("SetActivityTemperature() clear error = %v", err)
+	}
+	temp, _ = db.GetActivityTemperature(1)
+	if temp != nil {
+		t.Errorf("GetActivityTemperature() after clear = %v, want nil", *temp)
+	}
+}
+
+func TestActivityRPE(t *testing.T) {
+	db := setupTestDB(t)
+
+	rpe, err := db.GetActivityRPE(1)
+	if err != nil {
+		t.Fatalf("GetActivityRPE() error = %v", err)
+	}
+	if rpe != nil {
+		t.Errorf("GetActivityRPE() = %v, want nil", *rpe)
+	}
+
+	good, awful := 4, 6
+	hard := ActivityRPE{RPE: 8, Feel: &good}
+	easy := ActivityRPE{RPE: 3}
+	if err := db.SetActivityRPE(1, &hard); err != nil {
+		t.Fatalf("SetActivityRPE() error = %v", err)
+	}
+	if err := db.SetActivityRPE(2, &easy); err != nil {
+		t.Fatalf("SetActivityRPE(2) error = %v", err)
+	}
+	if err := db.SetActivityRPE(2, &ActivityRPE{RPE: 11}); err == nil {
+		t.Error("SetActivityRPE() accepted an RPE of 11")
+	}
+	if err := db.SetActivityRPE(2, &ActivityRPE{RPE: 5, Feel: &awful}); err == nil {
+		t.Error("SetActivityRPE() accepted a feel of 6")
+	}
+	rpe, _ = db.GetActivityRPE(1)
+	if rpe == nil || !reflect.DeepEqual(*rpe, hard) {
+		t.Errorf("GetActivityRPE() = %v, want %v", rpe, hard)
+	}
+
+	rpes, err := db.GetActivityRPEs()
+	if err != nil {
+		t.Fatalf("GetActivityRPEs() error = %v", err)
+	}
+	if want := map[int64]ActivityRPE{1: hard, 2: easy}; !reflect.DeepEqual(rpes, want) {
+		t.Errorf("GetActivityRPEs() = %v, want %v", rpes, want)
+	}
+
+	if err := db.SetActivityRPE(1, nil); err != nil {
+		t.Fatalf("SetActivityRPE() clear error = %v", err)
+	}
+	rpe, _ = db.GetActivityRPE(1)
+	if rpe != nil {
+		t.Errorf("GetActivityRPE() after clear = %v, want nil", *rpe)
+	}
+}
@@ -0,0 +1,433 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"runner/internal/paths"
+)
+
+// archiveExt is the extension of season archive files
+const archiveExt = ".db"
+
+// mergedRecordOrder ranks one category's personal records across databases,
+// best first, the way each category's compare mode does
+const mergedRecordOrder = `CASE
+		WHEN category IN ('longest_run', 'highest_elevation') THEN -distance_meters
+		WHEN category = 'fastest_pace' THEN pace_per_mile
+		ELSE duration_seconds END, achieved_at`
+
+// activityTable is a table holding rows that belong to activities
+type activityTable struct {
+	name    string
+	column  string       // the column referencing activities(id)
+	parents []foreignKey // its other foreign keys
+}
+
+type foreignKey struct {
+	column, parent, parentColumn string
+}
+
+// ArchiveDir returns the directory holding season archives
+func ArchiveDir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archives"), nil
+}
+
+// ArchivePath returns the file the archive called name is kept in
+func ArchivePath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid archive name %q", name)
+	}
+	dir, err := ArchiveDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+archiveExt), nil
+}
+
+// ListArchives returns the archive files in the archives directory, by name
+func ListArchives() ([]string, error) {
+	dir, err := ArchiveDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing archives: %w", err)
+	}
+
+	var archives []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), archiveExt) {
+			archives = append(archives, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(archives)
+	return archives, nil
+}
+
+// ArchiveActivities moves the activities that started before before, on
+// the local clock, into the archive database at archivePath with every row
+// that belongs to them, and returns how many were moved. The archive is
+// created with the full schema if it doesn't exist, so it can be opened on
+// its own too. Rows that also belong to something left behind, such as an
+// attempt at a benchmark whose reference run stays, are dropped. Tables not
+// tied to activities, such as weekly summaries and fitness trends, stay.
+func (s *Store) ArchiveActivities(before time.Time, archivePath string) (int, error) {
+	archive, err := OpenPath(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("creating archive: %w", err)
+	}
+	if err := archive.Close(); err != nil {
+		return 0, err
+	}
+
+	tables, err := s.activityTables()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := before.Format(time.RFC3339)
+
+	var moved int
+	err = s.write(func() error {
+		ctx := context.Background()
+		conn, err := s.db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS archive`, archivePath); err != nil {
+			return fmt.Errorf("attaching archive: %w", err)
+		}
+		defer conn.ExecContext(ctx, `DETACH DATABASE archive`)
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		// Parents first, so every row copied has its parents in the archive
+		if err := copyColumnsMatch(tx, "activities"); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO archive.activities SELECT * FROM main.activities
+			WHERE start_date_local < ?`, cutoff); err != nil {
+			return fmt.Errorf("archiving activities: %w", err)
+		}
+		archived := `SELECT id FROM main.activities WHERE start_date_local < ?`
+		for _, t := range tables {
+			where := []string{fmt.Sprintf("%q IN (%s)", t.column, archived)}
+			for _, fk := range t.parents {
+				where = append(where, fmt.Sprintf("(%q IS NULL OR %q IN (SELECT %q FROM archive.%q))",
+					fk.column, fk.column, fk.parentColumn, fk.parent))
+			}
+			columns, err := tableColumns(tx, "main", t.name)
+			if err != nil {
+				return err
+			}
+			query := fmt.Sprintf(`INSERT OR IGNORE INTO archive.%q (%s) SELECT %s FROM main.%q WHERE %s`,
+				t.name, columns, columns, t.name, strings.Join(where, " AND "))
+			if _, err := tx.Exec(query, cutoff); err != nil {
+				return fmt.Errorf("archiving %s: %w", t.name, err)
+			}
+		}
+
+		// Deleting the activities cascades to everything that belongs to them
+		res, err := tx.Exec(`DELETE FROM main.activities WHERE start_date_local < ?`, cutoff)
+		if err != nil {
+			return fmt.Errorf("removing archived activities: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		moved = int(n)
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// FirstActivityDate returns when the earliest activity started, on the
+// local clock, or nil without activities
+func (s *Store) FirstActivityDate() (*time.Time, error) {
+	var first sql.NullString
+	if err := s.db.QueryRow(`SELECT MIN(start_date_local) FROM activities`).Scan(&first); err != nil {
+		return nil, fmt.Errorf("finding first activity: %w", err)
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, first.String)
+	if err != nil {
+		return nil, fmt.Errorf("parsing first activity date: %w", err)
+	}
+	return &t, nil
+}
+
+// copyColumnsMatch checks that table has the same columns in the same
+// order in the main database and the archive, as SELECT * copying needs
+func copyColumnsMatch(tx *sql.Tx, table string) error {
+	mainCols, err := tableColumns(tx, "main", table)
+	if err != nil {
+		return err
+	}
+	archiveCols, err := tableColumns(tx, "archive", table)
+	if err != nil {
+		return err
+	}
+	if mainCols != archiveCols {
+		return fmt.Errorf("archive's %s columns don't match; open it with this version first", table)
+	}
+	return nil
+}
+
+// activityTables lists the tables whose rows belong to activities, through
+// a foreign key to activities(id), with the tables they also depend on
+// after them
+func (s *Store) activityTables() ([]activityTable, error) {
+	rows, err := s.db.Query(`
+		SELECT m.name, f."from", f."table", f."to"
+		FROM sqlite_master m, pragma_foreign_key_list(m.name) f
+		WHERE m.type = 'table'
+		ORDER BY m.name, f.id`)
+	if err != nil {
+		return nil, fmt.Errorf("listing foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*activityTable)
+	var names []string
+	for rows.Next() {
+		var table string
+		var fk foreignKey
+		if err := rows.Scan(&table, &fk.column, &fk.parent, &fk.parentColumn); err != nil {
+			return nil, fmt.Errorf("listing foreign keys: %w", err)
+		}
+		t, ok := byName[table]
+		if !ok {
+			t = &activityTable{name: table}
+			byName[table] = t
+			names = append(names, table)
+		}
+		if fk.parent == "activities" && t.column == "" {
+			t.column = fk.column
+		} else {
+			t.parents = append(t.parents, fk)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing foreign keys: %w", err)
+	}
+
+	// Tables depending only on activities first
+	var tables []activityTable
+	for _, pass := range []bool{false, true} {
+		for _, name := range names {
+			if t := byName[name]; t.column != "" && (len(t.parents) > 0) == pass {
+				tables = append(tables, *t)
+			}
+		}
+	}
+	return tables, nil
+}
+
+// tableColumns returns the quoted column names of a table in schema, comma
+// separated
+func tableColumns(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, schema, table string) (string, error) {
+	names, err := columnNames(q, schema, table)
+	if err != nil {
+		return "", err
+	}
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, ", "), nil
+}
+
+// columnNames returns the columns of a table in schema in order, or none
+// when it doesn't exist there
+func columnNames(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, schema, table string) ([]string, error) {
+	rows, err := q.Query(`SELECT name FROM pragma_table_info(?, ?) ORDER BY cid`, table, schema)
+	if err != nil {
+		return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("reading columns of %s: %w", table, err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// OpenAllTime opens the database read-only with every season archive
+// attached, for all-time queries (see OpenPathAllTime)
+func OpenAllTime() (*Store, error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting db path: %w", err)
+	}
+	archives, err := ListArchives()
+	if err != nil {
+		return nil, err
+	}
+	return OpenPathAllTime(dbPath, archives)
+}
+
+// OpenPathAllTime opens the database at dbPath read-only with the archives
+// attached. Temporary views named after the activity tables take their
+// place, combining the rows of every database, so every query reads across
+// all of them unchanged: weekly summaries of a week split between databases
+// are added up, and each personal record category keeps its best record.
+// The views live on one connection, so the store only ever opens one.
+func OpenPathAllTime(dbPath string, archives []string) (*Store, error) {
+	s, err := OpenPathReadOnly(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) == 0 {
+		return s, nil
+	}
+	s.db.SetMaxOpenConns(1)
+	s.db.SetMaxIdleConns(1)
+
+	if err := s.attachArchives(archives); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// attachArchives attaches each archive read-only and creates the views
+// combining them with the main database
+func (s *Store) attachArchives(archives []string) error {
+	schemas := []string{"main"}
+	for i, path := range archives {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("opening archive: %w", err)
+		}
+		schema := fmt.Sprintf("archive_%d", i+1)
+		uri := "file:" + filepath.ToSlash(path) + "?mode=ro"
+		if _, err := s.db.Exec(`ATTACH DATABASE ? AS `+schema, uri); err != nil {
+			return fmt.Errorf("attaching archive %s: %w", filepath.Base(path), err)
+		}
+		schemas = append(schemas, schema)
+	}
+
+	tables, err := s.activityTables()
+	if err != nil {
+		return err
+	}
+	merged := []string{"activities"}
+	for _, t := range tables {
+		merged = append(merged, t.name)
+	}
+	merged = append(merged, "weekly_summaries")
+
+	for _, table := range merged {
+		columns, err := columnNames(s.db, "main", table)
+		if err != nil {
+			return err
+		}
+		union, err := s.unionSelect(table, columns, schemas)
+		if err != nil {
+			return err
+		}
+
+		var view string
+		switch table {
+		case "personal_records":
+			view = fmt.Sprintf(`SELECT %s FROM (SELECT *, ROW_NUMBER() OVER (PARTITION BY category ORDER BY %s) AS rank
+				FROM (%s)) WHERE rank = 1`, quoteColumns(columns), mergedRecordOrder, union)
+		case "weekly_summaries":
+			view = weeklySummariesView(columns, union)
+		default:
+			view = union
+		}
+		if _, err := s.db.Exec(fmt.Sprintf(`CREATE TEMP VIEW %q AS %s`, table, view)); err != nil {
+			return fmt.Errorf("combining %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// unionSelect selects columns of table from every schema that has it. An
+// archive made before a column was added reads NULL for it.
+func (s *Store) unionSelect(table string, columns, schemas []string) (string, error) {
+	var parts []string
+	for _, schema := range schemas {
+		have, err := columnNames(s.db, schema, table)
+		if err != nil {
+			return "", err
+		}
+		if len(have) == 0 {
+			continue
+		}
+		present := make(map[string]bool, len(have))
+		for _, c := range have {
+			present[c] = true
+		}
+		selected := make([]string, len(columns))
+		for i, c := range columns {
+			if present[c] {
+				selected[i] = fmt.Sprintf("%q", c)
+			} else {
+				selected[i] = fmt.Sprintf("NULL AS %q", c)
+			}
+		}
+		parts = append(parts, fmt.Sprintf("SELECT %s FROM %s.%q", strings.Join(selected, ", "), schema, table))
+	}
+	return strings.Join(parts, " UNION ALL "), nil
+}
+
+// weeklySummariesView adds up the summaries of a week split between
+// databases. Every column but the week and its update time is a total.
+func weeklySummariesView(columns []string, union string) string {
+	selected := make([]string, len(columns))
+	for i, c := range columns {
+		switch c {
+		case "week_start":
+			selected[i] = fmt.Sprintf("%q", c)
+		case "updated_at":
+			selected[i] = fmt.Sprintf("MAX(%q) AS %q", c, c)
+		default:
+			selected[i] = fmt.Sprintf("SUM(%q) AS %q", c, c)
+		}
+	}
+	return fmt.Sprintf(`SELECT %s FROM (%s) GROUP BY week_start`, strings.Join(selected, ", "), union)
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = fmt.Sprintf("%q", c)
+	}
+	return strings.Join(quoted, ", ")
+}
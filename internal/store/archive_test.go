@@ -0,0 +1,108 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveActivities(t *testing.T) {
+	dir := t.TempDir()
+	db, err := OpenPath(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs := []struct {
+		id       int64
+		start    time.Time
+		distance float64
+	}{
+		{1, time.Date(2019, 5, 4, 8, 0, 0, 0, time.UTC), 10000},
+		{2, time.Date(2020, 12, 31, 23, 0, 0, 0, time.UTC), 21100},
+		{3, time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC), 5000},
+	}
+	for _, r := range runs {
+		a := &Activity{ID: r.id, AthleteID: 1, Name: "Run", Type: "Run", StartDate: r.start,
+			StartDateLocal: r.start, Distance: r.distance, MovingTime: int(r.distance / 3)}
+		if err := db.UpsertActivity(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SetActivityTags(1, []string{"race"}); err != nil {
+		t.Fatal(err)
+	}
+	// The longest run is archived, so the hot database loses the record
+	for _, pr := range []PersonalRecord{
+		{Category: "longest_run", ActivityID: 2, DistanceMeters: 21100, DurationSeconds: 7000, AchievedAt: runs[1].start},
+		{Category: "distance_5k", ActivityID: 3, DistanceMeters: 5000, DurationSeconds: 1500, AchievedAt: runs[2].start},
+	} {
+		if _, err := db.UpsertPersonalRecord(&pr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archivePath := filepath.Join(dir, "archives", "2016-2020.db")
+	moved, err := db.ArchiveActivities(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), archivePath)
+	if err != nil {
+		t.Fatalf("ArchiveActivities() error = %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("ArchiveActivities() moved %d, want 2", moved)
+	}
+
+	if n, _ := db.CountActivities(); n != 1 {
+		t.Errorf("hot database has %d activities, want 1", n)
+	}
+	if pr, _ := db.GetPersonalRecordByCategory("longest_run"); pr != nil {
+		t.Errorf("hot database kept the archived record %+v", pr)
+	}
+
+	archive, err := OpenPath(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := archive.CountActivities(); n != 2 {
+		t.Errorf("archive has %d activities, want 2", n)
+	}
+	if tags, _ := archive.GetActivityTags(1); len(tags) != 1 || tags[0] != "race" {
+		t.Errorf("archive tags = %v, want [race]", tags)
+	}
+	archive.Close()
+
+	// Attached, the archive reads as if it had never left
+	db.Close()
+	all, err := OpenPathAllTime(filepath.Join(dir, "data.db"), []string{archivePath})
+	if err != nil {
+		t.Fatalf("OpenPathAllTime() error = %v", err)
+	}
+	defer all.Close()
+
+	if n, err := all.CountActivities(); err != nil || n != 3 {
+		t.Errorf("all-time CountActivities() = %d, %v, want 3", n, err)
+	}
+	if a, err := all.GetActivity(1); err != nil || a == nil {
+		t.Errorf("all-time GetActivity(1) = %v, %v, want the archived run", a, err)
+	}
+	records, err := all.GetAllPersonalRecords()
+	if err != nil {
+		t.Fatalf("all-time GetAllPersonalRecords() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("all-time records = %+v, want both categories", records)
+	}
+	if err := all.SetActivityTags(3, nil); err == nil {
+		t.Error("all-time store allowed a write")
+	}
+}
+
+func TestArchivePath(t *testing.T) {
+	for _, name := range []string{"", "../data", ".hidden", `a\b`} {
+		if _, err := ArchivePath(name); err == nil {
+			t.Errorf("ArchivePath(%q) succeeded, want an error", name)
+		}
+	}
+	if _, err := ArchivePath("2016-2020"); err != nil {
+		t.Errorf("ArchivePath() error = %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/paths"
+)
+
+// Reasons recorded in backup file names
+const (
+	BackupManual     = "manual"
+	BackupScheduled  = "scheduled"
+	BackupMigration  = "migration"
+	BackupPreRestore = "pre-restore"
+	BackupPreArchive = "pre-archive"
+)
+
+// backupTimeFormat is the timestamp in backup file names, which sorts by age
+const backupTimeFormat = "20060102-150405"
+
+// Backup is a snapshot of the database in the backups directory
+type Backup struct {
+	Path      string
+	CreatedAt time.Time
+	Reason    string
+	Bytes     int64
+
+	modTime time.Time
+}
+
+// backupDir returns the directory holding database backups
+func backupDir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
+
+// newBackupPath returns a fresh file name for a backup taken now
+func newBackupPath(reason string) (string, error) {
+	dir, err := backupDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	stamp := time.Now().UTC().Format(backupTimeFormat)
+	path := filepath.Join(dir, fmt.Sprintf("data-%s-%s.db", stamp, reason))
+	// Two backups in the same second get a numeric suffix
+	for i := 2; fileExists(path); i++ {
+		path = filepath.Join(dir, fmt.Sprintf("data-%s-%s-%d.db", stamp, reason, i))
+	}
+	return path, nil
+}
+
+// Backup writes a consistent snapshot of the database to the backups
+// directory. VACUUM INTO is safe while the database is in use and produces a
+// compacted copy.
+func (s *Store) Backup(reason string) (*Backup, error) {
+	return backupDB(s.db, reason)
+}
+
+func backupDB(db *sql.DB, reason string) (*Backup, error) {
+	path, err := newBackupPath(reason)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec("VACUUM INTO ?", path); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("writing backup: %w", err)
+	}
+	return readBackup(path)
+}
+
+// BackupIfDue takes a scheduled backup when the newest backup is older than
+// interval, then prunes to keep copies. It returns nil when no backup was
+// needed.
+func (s *Store) BackupIfDue(interval time.Duration, keep int) (*Backup, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) > 0 && time.Since(backups[0].CreatedAt) < interval {
+		return nil, nil
+	}
+
+	b, err := s.Backup(BackupScheduled)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := PruneBackups(keep); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+
+// ListBackups returns the backups on disk, newest first
+func ListBackups() ([]Backup, error) {
+	dir, err := backupDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading backup directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "data-") || !strings.HasSuffix(e.Name(), ".db") {
+			continue
+		}
+		b, err := readBackup(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, *b)
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+	return backups, nil
+}
+
+// readBackup describes a backup file from its name and size
+func readBackup(path string) (*Backup, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// data-20240115-100000-reason.db
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "data-"), ".db")
+	if len(name) < len(backupTimeFormat)+2 {
+		return nil, fmt.Errorf("unrecognized backup name %s", filepath.Base(path))
+	}
+	createdAt, err := time.Parse(backupTimeFormat, name[:len(backupTimeFormat)])
+	if err != nil {
+		return nil, fmt.Errorf("unrecognized backup name %s", filepath.Base(path))
+	}
+
+	// Drop the suffix added to backups taken in the same second
+	reason := name[len(backupTimeFormat)+1:]
+	if i := strings.LastIndex(reason, "-"); i > 0 {
+		if _, err := strconv.Atoi(reason[i+1:]); err == nil {
+			reason = reason[:i]
+		}
+	}
+
+	return &Backup{
+		Path:      path,
+		CreatedAt: createdAt,
+		Reason:    reason,
+		Bytes:     info.Size(),
+		modTime:   info.ModTime(),
+	}, nil
+}
+
+// PruneBackups deletes all but the newest keep backups and returns how many
+// were removed. A keep of zero or less removes nothing.
+func PruneBackups(keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	backups, err := ListBackups()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil {
+			return removed, fmt.Errorf("removing backup: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RestoreBackup replaces the database file with a backup. The current
+// database is saved as a pre-restore backup first, so a restore can itself
+// be undone. The database must not be open while restoring.
+func RestoreBackup(path string) error {
+	if err := checkBackup(path); err != nil {
+		return err
+	}
+
+	dbPath, err := getDBPath()
+	if err != nil {
+		return fmt.Errorf("getting db path: %w", err)
+	}
+
+	if fileExists(dbPath) {
+		if err := backupCurrent(dbPath); err != nil {
+			return fmt.Errorf("saving current database: %w", err)
+		}
+	}
+
+	// Copy beside the database and rename so a failed copy can't leave a
+	// half-written database behind
+	tmp := dbPath + ".restore"
+	if err := copyFile(path, tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("copying backup: %w", err)
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("replacing database: %w", err)
+	}
+
+	// Journal files belong to the old database
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		os.Remove(dbPath + suffix)
+	}
+	return nil
+}
+
+// backupCurrent saves the database at dbPath as a pre-restore backup.
+// Commits can still be in the -wal file, which the restore deletes, so the
+// copy goes through SQLite. A file SQLite can't read is copied as it is, so
+// a damaged database is still kept.
+func backupCurrent(dbPath string) error {
+	db, err := sql.Open("sqlite", connString(dbPath, false))
+	if err != nil {
+		return err
+	}
+	_, err = backupDB(db, BackupPreRestore)
+	db.Close()
+	if err == nil {
+		return nil
+	}
+
+	safety, err := newBackupPath(BackupPreRestore)
+	if err != nil {
+		return err
+	}
+	return copyFile(dbPath, safety)
+}
+
+// checkBackup verifies that a file is a readable, intact database
+func checkBackup(path string) error {
+	if !fileExists(path) {
+		return fmt.Errorf("backup %s not found", path)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("opening backup: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA quick_check").Scan(&result); err != nil {
+		return fmt.Errorf("checking backup: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup failed integrity check: %s", result)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
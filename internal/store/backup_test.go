@@ -0,0 +1,220 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"runner/internal/paths"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	b, err := db.Backup(BackupManual)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if b.Reason != BackupManual || b.Bytes <= 0 {
+		t.Errorf("Backup() = %+v, want a non-empty manual backup", b)
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 1 || backups[0].Path != b.Path {
+		t.Fatalf("ListBackups() = %+v, want the one backup", backups)
+	}
+
+	// Restore over an existing database file and check the activities came back
+	dbPath, err := getDBPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dbPath, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreBackup(b.Path); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+
+	restored, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	var count int
+	if err := restored.QueryRow("SELECT COUNT(*) FROM activities").Scan(&count); err != nil {
+		t.Fatalf("reading restored database: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("restored database has %d activities, want 2", count)
+	}
+
+	// The replaced file is kept so the restore can be undone
+	backups, err = ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected a pre-restore backup, got %+v", backups)
+	}
+	var preRestore bool
+	for _, b := range backups {
+		preRestore = preRestore || b.Reason == BackupPreRestore
+	}
+	if !preRestore {
+		t.Errorf("expected a pre-restore backup, got %+v", backups)
+	}
+}
+
+func TestRestoreBackup_RejectsInvalidFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(paths.DataEnv, home)
+
+	bad := filepath.Join(home, "bad.db")
+	if err := os.WriteFile(bad, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreBackup(bad); err == nil {
+		t.Error("expected an error restoring a file that isn't a database")
+	}
+	if err := RestoreBackup(filepath.Join(home, "missing.db")); err == nil {
+		t.Error("expected an error restoring a missing file")
+	}
+}
+
+func TestBackupIfDueAndPrune(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	db := setupTestDB(t)
+
+	// No backups yet, so one is due
+	b, err := db.BackupIfDue(24*time.Hour, 2)
+	if err != nil || b == nil {
+		t.Fatalf("BackupIfDue() = %v, %v; want a new backup", b, err)
+	}
+	if b.Reason != BackupScheduled {
+		t.Errorf("Reason = %q, want %q", b.Reason, BackupScheduled)
+	}
+
+	// The fresh backup means the next one isn't due
+	if b, err := db.BackupIfDue(24*time.Hour, 2); err != nil || b != nil {
+		t.Fatalf("BackupIfDue() = %v, %v; want no backup", b, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := db.Backup(BackupManual); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := PruneBackups(2)
+	if err != nil {
+		t.Fatalf("PruneBackups() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("PruneBackups() removed %d, want 2", removed)
+	}
+	backups, err := ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("got %d backups after pruning, want 2", len(backups))
+	}
+}
+
+func TestHasPendingMigrations(t *testing.T) {
+	db := setupTestDB(t)
+
+	pending, err := hasPendingMigrations(db.db)
+	if err != nil || pending {
+		t.Fatalf("hasPendingMigrations() = %v, %v on a migrated database; want false", pending, err)
+	}
+
+	// A database from before the zone columns were added needs migrating
+	if _, err := db.db.Exec("ALTER TABLE activity_metrics DROP COLUMN z5_seconds"); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = hasPendingMigrations(db.db)
+	if err != nil || !pending {
+		t.Errorf("hasPendingMigrations() = %v, %v with a missing column; want true", pending, err)
+	}
+}
+
+func TestRestoreBackup_KeepsWALCommits(t *testing.T) {
+	t.Setenv(paths.DataEnv, t.TempDir())
+	dbPath, err := getDBPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Leave a commit in the -wal file, as a process that didn't close
+	// cleanly would
+	current, err := sql.Open("sqlite", connString(dbPath, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer current.Close()
+	current.SetMaxOpenConns(1)
+	if err := enableWAL(current); err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		"PRAGMA wal_autocheckpoint = 0",
+		"CREATE TABLE notes (body TEXT)",
+		"INSERT INTO notes VALUES ('only in the wal')",
+	} {
+		if _, err := current.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if info, err := os.Stat(dbPath + "-wal"); err != nil || info.Size() == 0 {
+		t.Fatalf("expected commits in the -wal file, got %v, %v", info, err)
+	}
+
+	b, err := backupDB(current, BackupManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreBackup(b.Path); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var preRestore string
+	for _, b := range backups {
+		if b.Reason == BackupPreRestore {
+			preRestore = b.Path
+		}
+	}
+	if preRestore == "" {
+		t.Fatalf("expected a pre-restore backup, got %+v", backups)
+	}
+
+	saved, err := sql.Open("sqlite", preRestore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer saved.Close()
+	var body string
+	if err := saved.QueryRow("SELECT body FROM notes").Scan(&body); err != nil {
+		t.Fatalf("reading pre-restore backup: %v", err)
+	}
+	if body != "only in the wal" {
+		t.Errorf("pre-restore backup has %q, want the -wal commit", body)
+	}
+}
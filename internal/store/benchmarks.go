@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"runner/internal/store/sqlc"
+)
+
+// ListBenchmarks returns every benchmark, by name.
+func (s *Store) ListBenchmarks() ([]Benchmark, error) {
+	rows, err := s.queries.ListBenchmarks(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	benchmarks := make([]Benchmark, len(rows))
+	for i, row := range rows {
+		benchmarks[i] = Benchmark{
+			ID:                  row.ID,
+			Name:                row.Name,
+			Kind:                BenchmarkKind(row.Kind),
+			ReferenceActivityID: row.ReferenceActivityID,
+		}
+	}
+	return benchmarks, nil
+}
+
+// CreateBenchmark saves a new benchmark and returns its ID. Names are
+// unique.
+func (s *Store) CreateBenchmark(benchmark *Benchmark) (int64, error) {
+	result, err := s.queries.CreateBenchmark(context.Background(), sqlc.CreateBenchmarkParams{
+		Name:                benchmark.Name,
+		Kind:                string(benchmark.Kind),
+		ReferenceActivityID: benchmark.ReferenceActivityID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// DeleteBenchmark removes a benchmark along with its overrides. The runs
+// themselves are untouched.
+func (s *Store) DeleteBenchmark(id int64) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		result, err := qtx.DeleteBenchmark(context.Background(), id)
+		if err != nil {
+			return err
+		}
+		if err := benchmarkAffected(result); err != nil {
+			return err
+		}
+		// Foreign keys aren't enforced on every connection, so clear the
+		// overrides by hand
+		if err := qtx.DeleteBenchmarkActivities(context.Background(), id); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// ListBenchmarkOverrides returns the runs added to or removed from a
+// benchmark by hand.
+func (s *Store) ListBenchmarkOverrides(benchmarkID int64) ([]BenchmarkOverride, error) {
+	rows, err := s.queries.ListBenchmarkActivities(context.Background(), benchmarkID)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make([]BenchmarkOverride, len(rows))
+	for i, row := range rows {
+		overrides[i] = BenchmarkOverride{
+			BenchmarkID: row.BenchmarkID,
+			ActivityID:  row.ActivityID,
+			Included:    row.Included != 0,
+		}
+	}
+	return overrides, nil
+}
+
+// SetBenchmarkOverride adds a run to a benchmark or removes it, whether or
+// not it matches.
+func (s *Store) SetBenchmarkOverride(o BenchmarkOverride) error {
+	return s.queries.SetBenchmarkActivity(context.Background(), sqlc.SetBenchmarkActivityParams{
+		BenchmarkID: o.BenchmarkID,
+		ActivityID:  o.ActivityID,
+		Included:    boolToInt64(o.Included),
+	})
+}
+
+func benchmarkAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrBenchmarkNotFound
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBenchmarks(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	maf, err := db.CreateBenchmark(&Benchmark{Name: "MAF test", Kind: BenchmarkDuration, ReferenceActivityID: 1})
+	if err != nil {
+		t.Fatalf("CreateBenchmark failed: %v", err)
+	}
+	if _, err := db.CreateBenchmark(&Benchmark{Name: "Lake loop", Kind: BenchmarkRoute, ReferenceActivityID: 2}); err != nil {
+		t.Fatalf("CreateBenchmark failed: %v", err)
+	}
+	if _, err := db.CreateBenchmark(&Benchmark{Name: "MAF test", Kind: BenchmarkRoute, ReferenceActivityID: 2}); err == nil {
+		t.Error("expected a duplicate name to fail")
+	}
+
+	benchmarks, err := db.ListBenchmarks()
+	if err != nil {
+		t.Fatalf("ListBenchmarks failed: %v", err)
+	}
+	if len(benchmarks) != 2 || benchmarks[0].Name != "Lake loop" || benchmarks[1].ID != maf {
+		t.Fatalf("unexpected benchmarks %+v", benchmarks)
+	}
+	if benchmarks[1].Kind != BenchmarkDuration || benchmarks[1].ReferenceActivityID != 1 {
+		t.Errorf("unexpected MAF benchmark %+v", benchmarks[1])
+	}
+
+	// Overrides replace each other
+	if err := db.SetBenchmarkOverride(BenchmarkOverride{BenchmarkID: maf, ActivityID: 2, Included: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetBenchmarkOverride(BenchmarkOverride{BenchmarkID: maf, ActivityID: 2, Included: false}); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err := db.ListBenchmarkOverrides(maf)
+	if err != nil {
+		t.Fatalf("ListBenchmarkOverrides failed: %v", err)
+	}
+	if len(overrides) != 1 || overrides[0].ActivityID != 2 || overrides[0].Included {
+		t.Errorf("unexpected overrides %+v", overrides)
+	}
+
+	if err := db.DeleteBenchmark(maf); err != nil {
+		t.Fatal(err)
+	}
+	if overrides, _ := db.ListBenchmarkOverrides(maf); len(overrides) != 0 {
+		t.Errorf("expected overrides to go with the benchmark, got %+v", overrides)
+	}
+	if err := db.DeleteBenchmark(maf); !errors.Is(err, ErrBenchmarkNotFound) {
+		t.Errorf("expected ErrBenchmarkNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveCustomMetrics replaces an activity's custom metric values, so metrics
+// no longer computed don't linger.
+func (s *Store) SaveCustomMetrics(activityID int64, metrics []CustomMetric) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		ctx := context.Background()
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteCustomMetrics(ctx, activityID); err != nil {
+			return fmt.Errorf("deleting custom metrics: %w", err)
+		}
+		for _, m := range metrics {
+			if err := qtx.InsertCustomMetric(ctx, sqlc.InsertCustomMetricParams{
+				ActivityID: activityID,
+				Name:       m.Name,
+				Label:      m.Label,
+				Value:      m.Value,
+			}); err != nil {
+				return fmt.Errorf("saving custom metric %q: %w", m.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetCustomMetrics returns an activity's custom metric values, by name.
+func (s *Store) GetCustomMetrics(activityID int64) ([]CustomMetric, error) {
+	rows, err := s.queries.ListCustomMetrics(context.Background(), activityID)
+	if err != nil {
+		return nil, err
+	}
+	metrics := make([]CustomMetric, 0, len(rows))
+	for _, row := range rows {
+		metrics = append(metrics, CustomMetric{Name: row.Name, Label: row.Label, Value: row.Value})
+	}
+	return metrics, nil
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCustomMetrics(t *testing.T) {
+	db := setupTestDB(t)
+
+	got, err := db.GetCustomMetrics(1)
+	if err != nil {
+		t.Fatalf("GetCustomMetrics() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("GetCustomMetrics() = %v, want none", got)
+	}
+
+	first := []CustomMetric{
+		{Name: "hard_minutes", Label: "Minutes above 170", Value: 12.5},
+		{Name: "beats_per_km", Label: "Beats per km", Value: 620},
+	}
+	if err := db.SaveCustomMetrics(1, first); err != nil {
+		t.Fatalf("SaveCustomMetrics() error = %v", err)
+	}
+	if err := db.SaveCustomMetrics(2, first[:1]); err != nil {
+		t.Fatalf("SaveCustomMetrics(2) error = %v", err)
+	}
+
+	// Sorted by name
+	got, _ = db.GetCustomMetrics(1)
+	if want := []CustomMetric{first[1], first[0]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCustomMetrics() = %v, want %v", got, want)
+	}
+
+	// Saving again drops metrics no longer computed
+	if err := db.SaveCustomMetrics(1, first[1:]); err != nil {
+		t.Fatalf("SaveCustomMetrics() again error = %v", err)
+	}
+	got, _ = db.GetCustomMetrics(1)
+	if !reflect.DeepEqual(got, first[1:]) {
+		t.Errorf("GetCustomMetrics() after resave = %v, want %v", got, first[1:])
+	}
+	got, _ = db.GetCustomMetrics(2)
+	if !reflect.DeepEqual(got, first[:1]) {
+		t.Errorf("GetCustomMetrics(2) = %v, want %v", got, first[:1])
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"runner/internal/paths"
+
 	_ "modernc.org/sqlite"
 )
 
@@ -22,6 +24,18 @@ var ErrPersonalRecordNotFound = errors.New("personal record not found")
 // ErrPredictionNotFound is returned when a prediction doesn't exist
 var ErrPredictionNotFound = errors.New("prediction not found")
 
+// ErrInjuryNotFound is returned when an injury doesn't exist
+var ErrInjuryNotFound = errors.New("injury not found")
+
+// ErrTrainingPlanNotFound is returned when there is no training plan
+var ErrTrainingPlanNotFound = errors.New("no training plan")
+
+// ErrBenchmarkNotFound is returned when a benchmark doesn't exist
+var ErrBenchmarkNotFound = errors.New("benchmark not found")
+
+// ErrClosed is returned by writes made after the store was closed
+var ErrClosed = errors.New("store is closed")
+
 // CompareMode determines how personal records are compared
 type CompareMode int
 
@@ -32,28 +46,44 @@ const (
 )
 
 // Open opens the SQLite database, creating it if necessary.
-// The database is stored at ~/.runner/data.db
+// The database is data.db in the data directory (see paths.DataDir)
 func Open() (*Store, error) {
 	dbPath, err := getDBPath()
 	if err != nil {
 		return nil, fmt.Errorf("getting db path: %w", err)
 	}
+	return OpenPath(dbPath)
+}
 
+// OpenPath opens the SQLite database at dbPath, creating it and its directory
+// if necessary
+func OpenPath(dbPath string) (*Store, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("creating data directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", connString(dbPath, false))
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
+	if err := enableWAL(db); err != nil {
+		db.Close()
+		return nil, err
+	}
 
-	// Enable foreign keys
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+	// Snapshot existing data before the schema changes
+	pending, err := hasPendingMigrations(db)
+	if err != nil {
 		db.Close()
-		return nil, fmt.Errorf("enabling foreign keys: %w", err)
+		return nil, fmt.Errorf("checking migrations: %w", err)
+	}
+	if pending {
+		if _, err := backupDB(db, BackupMigration); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("backing up before migration: %w", err)
+		}
 	}
 
 	// Run migrations
@@ -65,11 +95,49 @@ func Open() (*Store, error) {
 	return newStore(db), nil
 }
 
+// OpenReadOnly opens the existing database without write access, for
+// browsing a copy on a network share or one another process writes to
+func OpenReadOnly() (*Store, error) {
+	dbPath, err := getDBPath()
+	if err != nil {
+		return nil, fmt.Errorf("getting db path: %w", err)
+	}
+	return OpenPathReadOnly(dbPath)
+}
+
+// OpenPathReadOnly opens the existing database at dbPath without write
+// access. Nothing is migrated, so a database from an older version must be
+// opened read-write once first.
+func OpenPathReadOnly(dbPath string) (*Store, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, err // there's nothing to browse
+	}
+
+	db, err := sql.Open("sqlite", connString(dbPath, true))
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	pending, err := hasPendingMigrations(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("checking migrations: %w", err)
+	}
+	if pending {
+		db.Close()
+		return nil, errors.New("the database needs upgrading; open it once without read-only mode")
+	}
+
+	s := newStore(db)
+	s.readOnly = true
+	return s, nil
+}
+
 // getDBPath returns the path to the SQLite database file
 func getDBPath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := paths.DataDir()
 	if err != nil {
-		return "", fmt.Errorf("getting home directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(home, ".runner", "data.db"), nil
+	return filepath.Join(dir, "data.db"), nil
 }
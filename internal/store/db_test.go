@@ -0,0 +1,96 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenPathReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+
+	if _, err := OpenPathReadOnly(path); err == nil {
+		t.Fatal("expected an error opening a missing database read-only")
+	}
+
+	rw, err := OpenPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.UpsertActivity(&Activity{ID: 1, Name: "Morning Run", Type: "Run", StartDate: time.Now(), Distance: 5000}); err != nil {
+		t.Fatal(err)
+	}
+	defer rw.Close()
+
+	ro, err := OpenPathReadOnly(path)
+	if err != nil {
+		t.Fatalf("OpenPathReadOnly() error = %v", err)
+	}
+	defer ro.Close()
+
+	if !ro.ReadOnly() || rw.ReadOnly() {
+		t.Errorf("ReadOnly() = %v for the read-only store, %v for the other", ro.ReadOnly(), rw.ReadOnly())
+	}
+	if _, err := ro.GetActivity(1); err != nil {
+		t.Errorf("GetActivity() error = %v, want reads to work", err)
+	}
+	err = ro.SetActivityNote(1, "edited")
+	if err == nil || !strings.Contains(err.Error(), "readonly") {
+		t.Errorf("SetActivityNote() error = %v, want a read-only error", err)
+	}
+}
+
+func TestAuthScopes(t *testing.T) {
+	s := setupTestDB(t)
+
+	expires := time.Unix(1700000000, 0)
+	if err := s.SaveAuth(&Auth{AthleteID: 7, AccessToken: "a", RefreshToken: "r", ExpiresAt: expires, Scopes: "read,activity:read"}); err != nil {
+		t.Fatal(err)
+	}
+	// Refreshing keeps the scopes, which come only from authorizing
+	if err := s.UpdateTokens("a2", "r2", expires.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetAuth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "a2" || got.Scopes != "read,activity:read" {
+		t.Errorf("GetAuth() = %+v, want the new token with the scopes kept", got)
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	db := setupTestDB(t)
+
+	// A write waiting its turn when the store closes fails rather than panics
+	started, release := make(chan struct{}), make(chan struct{})
+	go db.write(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	waiting := make(chan error)
+	go func() { waiting <- db.SetActivityTags(1, []string{"late"}) }()
+
+	closed := make(chan error)
+	go func() { closed <- db.Close() }()
+	close(release)
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := <-waiting; err != nil && !errors.Is(err, ErrClosed) {
+		t.Errorf("write racing Close = %v, want nil or ErrClosed", err)
+	}
+
+	if err := db.SetActivityTags(1, []string{"after"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("write after Close = %v, want ErrClosed", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// GetDistanceCorrection returns how an activity's distance was corrected, or
+// nil if it wasn't.
+func (s *Store) GetDistanceCorrection(activityID int64) (*DistanceCorrection, error) {
+	row, err := s.queries.GetDistanceCorrection(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	correctedAt, err := time.Parse(time.RFC3339, row.CorrectedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parsing correction time %q: %w", row.CorrectedAt, err)
+	}
+	return &DistanceCorrection{
+		ActivityID:       row.ActivityID,
+		Method:           DistanceCorrectionMethod(row.Method),
+		StrideLength:     nullFloat64ToPtr(row.StrideLength),
+		OriginalDistance: row.OriginalDistance,
+		CorrectedAt:      correctedAt,
+	}, nil
+}
+
+// SetDistanceCorrection records a distance correction. Correcting an
+// activity again replaces the method; the original distance stays that of
+// the first correction.
+func (s *Store) SetDistanceCorrection(c DistanceCorrection) error {
+	return s.queries.SetDistanceCorrection(context.Background(), sqlc.SetDistanceCorrectionParams{
+		ActivityID:       c.ActivityID,
+		Method:           string(c.Method),
+		StrideLength:     ptrToNullFloat64(c.StrideLength),
+		OriginalDistance: c.OriginalDistance,
+		CorrectedAt:      c.CorrectedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// DeleteDistanceCorrection forgets a distance correction, for when the
+// activity is restored from Strava.
+func (s *Store) DeleteDistanceCorrection(activityID int64) error {
+	return s.queries.DeleteDistanceCorrection(context.Background(), activityID)
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDistanceCorrections(t *testing.T) {
+	db := setupTestDB(t) // Uses activity ID 1 from the setup
+
+	if c, err := db.GetDistanceCorrection(1); err != nil || c != nil {
+		t.Fatalf("GetDistanceCorrection() = %+v, %v before any correction, want nil", c, err)
+	}
+
+	at := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+	if err := db.SetDistanceCorrection(DistanceCorrection{ActivityID: 1, Method: CorrectionFactor, OriginalDistance: 5000, CorrectedAt: at}); err != nil {
+		t.Fatalf("SetDistanceCorrection failed: %v", err)
+	}
+	// Correcting again switches the method but keeps the first original
+	stride := 1.1
+	if err := db.SetDistanceCorrection(DistanceCorrection{ActivityID: 1, Method: CorrectionCadence, StrideLength: &stride, OriginalDistance: 5250, CorrectedAt: at}); err != nil {
+		t.Fatalf("SetDistanceCorrection failed: %v", err)
+	}
+
+	c, err := db.GetDistanceCorrection(1)
+	if err != nil || c == nil {
+		t.Fatalf("GetDistanceCorrection() = %+v, %v", c, err)
+	}
+	if c.Method != CorrectionCadence || c.StrideLength == nil || *c.StrideLength != 1.1 || c.OriginalDistance != 5000 || !c.CorrectedAt.Equal(at) {
+		t.Errorf("GetDistanceCorrection() = %+v, want cadence at 1.1 m from 5000 m", c)
+	}
+
+	if err := db.DeleteDistanceCorrection(1); err != nil {
+		t.Fatalf("DeleteDistanceCorrection failed: %v", err)
+	}
+	if c, err := db.GetDistanceCorrection(1); err != nil || c != nil {
+		t.Errorf("GetDistanceCorrection() = %+v, %v after delete, want nil", c, err)
+	}
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// SaveDurationEfforts replaces the cached pace-curve efforts for an activity
+func (s *Store) SaveDurationEfforts(activityID int64, efforts []DurationEffort) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteDurationEfforts(context.Background(), activityID); err != nil {
+			return fmt.Errorf("deleting existing efforts: %w", err)
+		}
+		for _, e := range efforts {
+			if err := qtx.InsertDurationEffort(context.Background(), sqlc.InsertDurationEffortParams{
+				ActivityID:      activityID,
+				DurationSeconds: int64(e.DurationSeconds),
+				DistanceMeters:  e.DistanceMeters,
+				StartOffset:     int64(e.StartOffset),
+			}); err != nil {
+				return fmt.Errorf("saving %ds effort: %w", e.DurationSeconds, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteDurationEfforts removes the cached pace-curve efforts for an activity
+func (s *Store) DeleteDurationEfforts(activityID int64) error {
+	return s.queries.DeleteDurationEfforts(context.Background(), activityID)
+}
+
+// HasDurationEfforts reports whether an activity's pace-curve efforts are cached
+func (s *Store) HasDurationEfforts(activityID int64) (bool, error) {
+	exists, err := s.queries.HasDurationEfforts(context.Background(), activityID)
+	return exists != 0, err
+}
+
+// GetDurationEfforts returns every cached effort among activities started on
+// or after since, skipping excluded activities. A zero since covers all
+// activities. Efforts are ordered by duration, farthest first.
+func (s *Store) GetDurationEfforts(since time.Time) ([]DurationEffort, error) {
+	var sinceStr string
+	if !since.IsZero() {
+		sinceStr = since.UTC().Format(time.RFC3339)
+	}
+	rows, err := s.queries.GetDurationEffortsSince(context.Background(), sinceStr)
+	if err != nil {
+		return nil, err
+	}
+
+	efforts := make([]DurationEffort, 0, len(rows))
+	for _, row := range rows {
+		startDate, err := time.Parse(time.RFC3339, row.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start_date %q: %w", row.StartDate, err)
+		}
+		efforts = append(efforts, DurationEffort{
+			ActivityID:      row.ActivityID,
+			DurationSeconds: int(row.DurationSeconds),
+			DistanceMeters:  row.DistanceMeters,
+			StartOffset:     int(row.StartOffset),
+			StartDate:       startDate,
+		})
+	}
+	return efforts, nil
+}
+
+// GetBestDurationEfforts returns the farthest cached effort for each duration
+// among activities started on or after since, skipping excluded activities.
+// A zero since covers all activities.
+func (s *Store) GetBestDurationEfforts(since time.Time) ([]DurationEffort, error) {
+	efforts, err := s.GetDurationEfforts(since)
+	if err != nil {
+		return nil, err
+	}
+
+	// Efforts come farthest first within each duration
+	var best []DurationEffort
+	for _, e := range efforts {
+		if len(best) > 0 && best[len(best)-1].DurationSeconds == e.DurationSeconds {
+			continue
+		}
+		best = append(best, e)
+	}
+	return best, nil
+}
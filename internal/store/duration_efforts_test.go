@@ -0,0 +1,93 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationEfforts(t *testing.T) {
+	db := setupTestDB(t)
+
+	if cached, err := db.HasDurationEfforts(1); err != nil || cached {
+		t.Fatalf("HasDurationEfforts() = %v, %v; want false", cached, err)
+	}
+
+	// Activity 1 is faster over 5 minutes, activity 2 over 10
+	if err := db.SaveDurationEfforts(1, []DurationEffort{
+		{DurationSeconds: 300, DistanceMeters: 1500, StartOffset: 60},
+		{DurationSeconds: 600, DistanceMeters: 2800, StartOffset: 0},
+	}); err != nil {
+		t.Fatalf("SaveDurationEfforts failed: %v", err)
+	}
+	if err := db.SaveDurationEfforts(2, []DurationEffort{
+		{DurationSeconds: 300, DistanceMeters: 1400, StartOffset: 0},
+		{DurationSeconds: 600, DistanceMeters: 2900, StartOffset: 120},
+	}); err != nil {
+		t.Fatalf("SaveDurationEfforts failed: %v", err)
+	}
+
+	if cached, err := db.HasDurationEfforts(1); err != nil || !cached {
+		t.Fatalf("HasDurationEfforts() = %v, %v; want true", cached, err)
+	}
+
+	best, err := db.GetBestDurationEfforts(time.Time{})
+	if err != nil {
+		t.Fatalf("GetBestDurationEfforts failed: %v", err)
+	}
+	if len(best) != 2 {
+		t.Fatalf("expected 2 durations, got %d", len(best))
+	}
+	if best[0].DurationSeconds != 300 || best[0].ActivityID != 1 || best[0].StartOffset != 60 {
+		t.Errorf("unexpected 5 minute best: %+v", best[0])
+	}
+	if best[1].DurationSeconds != 600 || best[1].ActivityID != 2 {
+		t.Errorf("unexpected 10 minute best: %+v", best[1])
+	}
+	if !best[1].StartDate.Equal(time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected start date %v", best[1].StartDate)
+	}
+
+	// Only activity 2 is on or after the cutoff
+	recent, err := db.GetBestDurationEfforts(time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetBestDurationEfforts failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].ActivityID != 2 {
+		t.Errorf("expected activity 2's efforts only, got %+v", recent)
+	}
+
+	// Saving again replaces rather than adds
+	if err := db.SaveDurationEfforts(2, []DurationEffort{
+		{DurationSeconds: 300, DistanceMeters: 1450, StartOffset: 0},
+	}); err != nil {
+		t.Fatalf("SaveDurationEfforts failed: %v", err)
+	}
+	recent, err = db.GetBestDurationEfforts(time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetBestDurationEfforts failed: %v", err)
+	}
+	if len(recent) != 1 || recent[0].DistanceMeters != 1450 {
+		t.Errorf("expected the replaced effort only, got %+v", recent)
+	}
+
+	// Excluded activities drop out of the curve
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatal(err)
+	}
+	best, err = db.GetBestDurationEfforts(time.Time{})
+	if err != nil {
+		t.Fatalf("GetBestDurationEfforts failed: %v", err)
+	}
+	for _, e := range best {
+		if e.ActivityID == 2 {
+			t.Errorf("excluded activity still in the curve: %+v", e)
+		}
+	}
+
+	if err := db.DeleteDurationEfforts(1); err != nil {
+		t.Fatalf("DeleteDurationEfforts failed: %v", err)
+	}
+	if cached, _ := db.HasDurationEfforts(1); cached {
+		t.Error("expected efforts to be deleted")
+	}
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// EffortsVerified reports whether the athlete confirmed an activity's best
+// efforts as real, so they aren't marked unverified.
+func (s *Store) EffortsVerified(activityID int64) (bool, error) {
+	_, err := s.queries.GetEffortVerification(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// VerifyEfforts records that an activity's best efforts are real and clears
+// the unverified mark on the records it holds. The next sync analyzes the
+// activity again so its cached efforts lose the mark too.
+func (s *Store) VerifyEfforts(activityID int64, at time.Time) error {
+	err := s.queries.SetEffortVerification(context.Background(), sqlc.SetEffortVerificationParams{
+		ActivityID: activityID,
+		VerifiedAt: at.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.queries.ClearEffortsComputed(context.Background(), activityID); err != nil {
+		return err
+	}
+	return s.queries.VerifyPersonalRecordsForActivity(context.Background(), activityID)
+}
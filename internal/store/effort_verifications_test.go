@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyEfforts(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	end := 90
+	for _, pr := range []*PersonalRecord{
+		{Category: "effort_400m", ActivityID: 1, DistanceMeters: 400, DurationSeconds: 60, AchievedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), EndOffset: &end, Unverified: true},
+		{Category: "effort_1k", ActivityID: 2, DistanceMeters: 1000, DurationSeconds: 200, AchievedAt: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), Unverified: true},
+	} {
+		if _, err := db.UpsertPersonalRecord(pr); err != nil {
+			t.Fatalf("UpsertPersonalRecord failed: %v", err)
+		}
+	}
+
+	pr, err := db.GetPersonalRecordByCategory("effort_400m")
+	if err != nil {
+		t.Fatalf("GetPersonalRecordByCategory failed: %v", err)
+	}
+	if !pr.Unverified {
+		t.Error("expected the 400m record to be stored as unverified")
+	}
+
+	verified, err := db.EffortsVerified(1)
+	if err != nil || verified {
+		t.Fatalf("EffortsVerified(1) = %v, %v before verifying, want false", verified, err)
+	}
+
+	if err := db.VerifyEfforts(1, time.Now()); err != nil {
+		t.Fatalf("VerifyEfforts failed: %v", err)
+	}
+	if verified, err := db.EffortsVerified(1); err != nil || !verified {
+		t.Errorf("EffortsVerified(1) = %v, %v, want true", verified, err)
+	}
+
+	records, err := db.GetAllPersonalRecords()
+	if err != nil {
+		t.Fatalf("GetAllPersonalRecords failed: %v", err)
+	}
+	for _, r := range records {
+		// Only the verified activity's record is cleared
+		if want := r.ActivityID == 2; r.Unverified != want {
+			t.Errorf("%s unverified = %v, want %v", r.Category, r.Unverified, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListTrainingLoads(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	// Only analyzed runs count
+	trimp := 85.0
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 2, TRIMP: &trimp}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+
+	loads, err := db.ListTrainingLoads()
+	if err != nil {
+		t.Fatalf("ListTrainingLoads() error = %v", err)
+	}
+	want := []TrainingLoad{{
+		StartDate:  time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC),
+		BucketDate: time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC),
+		Distance:   10000,
+		MovingTime: 3000,
+		TRIMP:      &trimp,
+	}}
+	if !reflect.DeepEqual(loads, want) {
+		t.Errorf("ListTrainingLoads() = %+v, want %+v", loads, want)
+	}
+}
+
+func TestFitnessTrends(t *testing.T) {
+	db := setupTestDB(t)
+
+	latest, err := db.GetLatestFitnessTrend()
+	if err != nil {
+		t.Fatalf("GetLatestFitnessTrend() error = %v", err)
+	}
+	if latest != nil {
+		t.Errorf("GetLatestFitnessTrend() = %+v, want nil", latest)
+	}
+
+	ctl, atl, tsb, monotony, strain := 40.0, 55.0, -15.0, 2.3, 1150.0
+	trends := []FitnessTrend{
+		{Date: "2024-01-14", CTL: &ctl, ATL: &atl, TSB: &tsb, RunCount7d: 3, TotalDistance7d: 21000, TotalTime7d: 6300},
+		{Date: "2024-01-15", CTL: &ctl, ATL: &atl, TSB: &tsb, RunCount7d: 4, TotalDistance7d: 26000, TotalTime7d: 7800,
+			Monotony7d: &monotony, Strain7d: &strain},
+	}
+	if err := db.ReplaceFitnessTrends(trends); err != nil {
+		t.Fatalf("ReplaceFitnessTrends() error = %v", err)
+	}
+	latest, err = db.GetLatestFitnessTrend()
+	if err != nil {
+		t.Fatalf("GetLatestFitnessTrend() error = %v", err)
+	}
+	if latest == nil || !reflect.DeepEqual(*latest, trends[1]) {
+		t.Errorf("GetLatestFitnessTrend() = %+v, want %+v", latest, trends[1])
+	}
+
+	since, err := db.GetFitnessTrends(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetFitnessTrends() error = %v", err)
+	}
+	if !reflect.DeepEqual(since, trends[1:]) {
+		t.Errorf("GetFitnessTrends() = %+v, want %+v", since, trends[1:])
+	}
+
+	// Replacing drops days that are no longer in the trends
+	if err := db.ReplaceFitnessTrends(trends[:1]); err != nil {
+		t.Fatalf("ReplaceFitnessTrends() error = %v", err)
+	}
+	latest, _ = db.GetLatestFitnessTrend()
+	if latest == nil || latest.Date != "2024-01-14" || latest.Monotony7d != nil {
+		t.Errorf("GetLatestFitnessTrend() after replace = %+v, want 2024-01-14 without monotony", latest)
+	}
+}
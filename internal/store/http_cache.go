@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// HTTPCacheEntries caps the responses kept in the HTTP cache; the ones
+// stored longest ago are dropped first
+const HTTPCacheEntries = 500
+
+// httpCacheTime stores times at a fixed width, so they sort as text
+const httpCacheTime = "2006-01-02T15:04:05.000000Z"
+
+// CachedResponse is an API response kept with the validators that let it be
+// revalidated rather than downloaded again
+type CachedResponse struct {
+	URL          string
+	ETag         string
+	LastModified string
+	Body         []byte
+	StoredAt     time.Time
+}
+
+// GetCachedResponse returns the cached response for url, or nil if there
+// isn't one
+func (s *Store) GetCachedResponse(url string) (*CachedResponse, error) {
+	row, err := s.queries.GetHTTPCacheEntry(context.Background(), url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cached response: %w", err)
+	}
+	storedAt, _ := time.Parse(httpCacheTime, row.StoredAt)
+	return &CachedResponse{
+		URL:          row.Url,
+		ETag:         row.Etag,
+		LastModified: row.LastModified,
+		Body:         row.Body,
+		StoredAt:     storedAt,
+	}, nil
+}
+
+// SaveCachedResponse stores a response, replacing any for the same URL, and
+// trims the cache to HTTPCacheEntries
+func (s *Store) SaveCachedResponse(r CachedResponse) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		err := qtx.SaveHTTPCacheEntry(context.Background(), sqlc.SaveHTTPCacheEntryParams{
+			Url:          r.URL,
+			Etag:         r.ETag,
+			LastModified: r.LastModified,
+			Body:         r.Body,
+			StoredAt:     r.StoredAt.UTC().Format(httpCacheTime),
+		})
+		if err != nil {
+			return fmt.Errorf("saving cached response: %w", err)
+		}
+		if err := qtx.PruneHTTPCache(context.Background(), HTTPCacheEntries); err != nil {
+			return fmt.Errorf("pruning response cache: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCachedResponses(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	const url = "https://www.strava.com/api/v3/activities/1"
+
+	if r, err := db.GetCachedResponse(url); err != nil || r != nil {
+		t.Fatalf("GetCachedResponse = %v, %v before saving, want nil", r, err)
+	}
+
+	if err := db.SaveCachedResponse(CachedResponse{URL: url, ETag: `W/"a1"`, Body: []byte(`{"id":1}`), StoredAt: now}); err != nil {
+		t.Fatalf("SaveCachedResponse failed: %v", err)
+	}
+	// Saving again replaces the entry
+	if err := db.SaveCachedResponse(CachedResponse{URL: url, ETag: `W/"b2"`, Body: []byte(`{"id":1,"name":"Run"}`), StoredAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("SaveCachedResponse failed: %v", err)
+	}
+	r, err := db.GetCachedResponse(url)
+	if err != nil || r == nil {
+		t.Fatalf("GetCachedResponse = %v, %v", r, err)
+	}
+	if r.ETag != `W/"b2"` || string(r.Body) != `{"id":1,"name":"Run"}` || !r.StoredAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("got %+v, want the second save", r)
+	}
+
+	// The cache keeps only the most recently stored entries
+	for i := range HTTPCacheEntries {
+		entry := CachedResponse{URL: fmt.Sprintf("%s/page/%d", url, i), Body: []byte("[]"), StoredAt: now.Add(time.Duration(i+2) * time.Minute)}
+		if err := db.SaveCachedResponse(entry); err != nil {
+			t.Fatalf("SaveCachedResponse failed: %v", err)
+		}
+	}
+	if r, err := db.GetCachedResponse(url); err != nil || r != nil {
+		t.Errorf("oldest entry = %v, %v, want it pruned", r, err)
+	}
+	if r, err := db.GetCachedResponse(url + "/page/0"); err != nil || r == nil {
+		t.Errorf("GetCachedResponse(page 0) = %v, %v, want it kept", r, err)
+	}
+}
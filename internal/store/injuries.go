@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// injuryDateLayout is how injury dates are stored.
+const injuryDateLayout = "2006-01-02"
+
+// ListInjuries returns every logged injury, most recent onset first.
+func (s *Store) ListInjuries() ([]Injury, error) {
+	rows, err := s.queries.ListInjuries(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	injuries := make([]Injury, 0, len(rows))
+	for _, row := range rows {
+		started, err := time.Parse(injuryDateLayout, row.StartedOn)
+		if err != nil {
+			return nil, fmt.Errorf("parsing started_on %q: %w", row.StartedOn, err)
+		}
+		injury := Injury{
+			ID:        row.ID,
+			StartedOn: started,
+			BodyPart:  row.BodyPart,
+			Severity:  int(row.Severity),
+			Notes:     row.Notes,
+		}
+		if row.ResolvedOn.Valid {
+			resolved, err := time.Parse(injuryDateLayout, row.ResolvedOn.String)
+			if err != nil {
+				return nil, fmt.Errorf("parsing resolved_on %q: %w", row.ResolvedOn.String, err)
+			}
+			injury.ResolvedOn = &resolved
+		}
+		injuries = append(injuries, injury)
+	}
+	return injuries, nil
+}
+
+// CreateInjury logs a new injury and returns its ID. ResolvedOn is ignored;
+// use SetInjuryResolved once it has cleared up.
+func (s *Store) CreateInjury(injury *Injury) (int64, error) {
+	result, err := s.queries.CreateInjury(context.Background(), sqlc.CreateInjuryParams{
+		StartedOn: injury.StartedOn.Format(injuryDateLayout),
+		BodyPart:  injury.BodyPart,
+		Severity:  int64(injury.Severity),
+		Notes:     injury.Notes,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// SetInjuryResolved records the day an injury cleared up, or reopens it when
+// resolvedOn is nil.
+func (s *Store) SetInjuryResolved(id int64, resolvedOn *time.Time) error {
+	var resolved sql.NullString
+	if resolvedOn != nil {
+		resolved = sql.NullString{String: resolvedOn.Format(injuryDateLayout), Valid: true}
+	}
+	result, err := s.queries.SetInjuryResolved(context.Background(), sqlc.SetInjuryResolvedParams{
+		ResolvedOn: resolved,
+		ID:         id,
+	})
+	if err != nil {
+		return err
+	}
+	return injuryAffected(result)
+}
+
+// DeleteInjury removes an injury from the log.
+func (s *Store) DeleteInjury(id int64) error {
+	result, err := s.queries.DeleteInjury(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	return injuryAffected(result)
+}
+
+func injuryAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrInjuryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInjuries(t *testing.T) {
+	db := setupTestDB(t)
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	shin, err := db.CreateInjury(&Injury{StartedOn: day("2024-01-10"), BodyPart: "Left shin", Severity: 2})
+	if err != nil {
+		t.Fatalf("CreateInjury failed: %v", err)
+	}
+	if _, err := db.CreateInjury(&Injury{StartedOn: day("2024-02-01"), BodyPart: "Achilles", Severity: 4, Notes: "sore on stairs"}); err != nil {
+		t.Fatalf("CreateInjury failed: %v", err)
+	}
+
+	resolved := day("2024-01-24")
+	if err := db.SetInjuryResolved(shin, &resolved); err != nil {
+		t.Fatalf("SetInjuryResolved failed: %v", err)
+	}
+
+	injuries, err := db.ListInjuries()
+	if err != nil {
+		t.Fatalf("ListInjuries failed: %v", err)
+	}
+	if len(injuries) != 2 {
+		t.Fatalf("expected 2 injuries, got %d", len(injuries))
+	}
+	if injuries[0].BodyPart != "Achilles" || injuries[0].Notes != "sore on stairs" || injuries[0].ResolvedOn != nil {
+		t.Errorf("unexpected newest injury %+v", injuries[0])
+	}
+	if injuries[1].ResolvedOn == nil || !injuries[1].ResolvedOn.Equal(resolved) || injuries[1].Severity != 2 {
+		t.Errorf("unexpected shin injury %+v", injuries[1])
+	}
+
+	// Reopening clears the resolved date
+	if err := db.SetInjuryResolved(shin, nil); err != nil {
+		t.Fatal(err)
+	}
+	if injuries, _ := db.ListInjuries(); injuries[1].ResolvedOn != nil {
+		t.Errorf("expected reopened injury, got %+v", injuries[1])
+	}
+
+	if err := db.DeleteInjury(shin); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteInjury(shin); !errors.Is(err, ErrInjuryNotFound) {
+		t.Errorf("expected ErrInjuryNotFound, got %v", err)
+	}
+	if err := db.SetInjuryResolved(shin, nil); !errors.Is(err, ErrInjuryNotFound) {
+		t.Errorf("expected ErrInjuryNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// TableStat describes one table's footprint in the database file
+type TableStat struct {
+	Name  string
+	Rows  int
+	Bytes int64 // pages used by the table and its indexes
+}
+
+// DBStats summarizes the database file
+type DBStats struct {
+	FileBytes int64 // total size of the database file
+	FreeBytes int64 // unused pages that a VACUUM would reclaim
+	Tables    []TableStat
+}
+
+// OrphanCount is the number of rows in a table that reference an activity
+// that no longer exists
+type OrphanCount struct {
+	Table string
+	Rows  int
+}
+
+// ForeignKeyViolation is one row reported by PRAGMA foreign_key_check
+type ForeignKeyViolation struct {
+	Table  string
+	RowID  int64
+	Parent string
+}
+
+// DataClassUsage is the footprint of one kind of data, over its tables
+type DataClassUsage struct {
+	Class       string
+	Description string
+	Tables      []string
+	Rows        int
+	Bytes       int64
+}
+
+// dataClasses groups the tables by what they hold, for showing which kind
+// of data takes the space. Tables not listed count as other.
+var dataClasses = []struct {
+	name, description string
+	tables            []string
+}{
+	{"streams", "second-by-second samples downloaded from Strava", []string{
+		"streams", "stream_blobs",
+	}},
+	{"activities", "activity summaries, details and kudos from Strava", []string{
+		"activities", "activity_details", "activity_social", "strava_splits", "strava_best_efforts",
+	}},
+	{"analysis", "metrics, splits, efforts and trends computed from runs", []string{
+		"activity_metrics", "activity_stream_stats", "activity_splits", "activity_best_efforts",
+		"duration_efforts", "custom_metrics", "weekly_summaries", "fitness_trends", "race_predictions",
+	}},
+	{"records", "personal records and their history", []string{
+		"personal_records", "pr_history",
+	}},
+	{"entries", "what you entered: notes, tags, races, injuries, wellness", []string{
+		"activity_notes", "activity_tags", "activity_weather", "activity_rpe", "races",
+		"injuries", "wellness", "benchmarks", "benchmark_activities", "activity_trims",
+		"activity_distance_corrections", "effort_verifications", "training_plan", "deleted_activities",
+	}},
+	{"internal", "sign-in, sync state and the API response cache", []string{
+		"auth", "sync_state", "sync_lock", "http_cache",
+	}},
+}
+
+// UsageByClass returns how much of the database each kind of data takes,
+// largest first
+func (s *Store) UsageByClass() ([]DataClassUsage, error) {
+	stats, err := s.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	classOf := make(map[string]int)
+	usage := make([]DataClassUsage, len(dataClasses), len(dataClasses)+1)
+	for i, c := range dataClasses {
+		usage[i] = DataClassUsage{Class: c.name, Description: c.description}
+		for _, t := range c.tables {
+			classOf[t] = i
+		}
+	}
+	other := DataClassUsage{Class: "other", Description: "tables not in another class"}
+	for _, t := range stats.Tables {
+		u := &other
+		if i, ok := classOf[t.Name]; ok {
+			u = &usage[i]
+		}
+		u.Tables = append(u.Tables, t.Name)
+		u.Rows += t.Rows
+		u.Bytes += t.Bytes
+	}
+	if len(other.Tables) > 0 {
+		usage = append(usage, other)
+	}
+
+	sort.SliceStable(usage, func(i, j int) bool {
+		return usage[i].Bytes > usage[j].Bytes
+	})
+	return usage, nil
+}
+
+// activityRefs lists every column that references activities(id)
+var activityRefs = []struct {
+	table  string
+	column string
+}{
+	{"streams", "activity_id"},
+	{"stream_blobs", "activity_id"},
+	{"activity_metrics", "activity_id"},
+	{"personal_records", "activity_id"},
+	{"pr_history", "activity_id"},
+	{"duration_efforts", "activity_id"},
+	{"races", "activity_id"},
+	{"race_predictions", "source_activity_id"},
+	{"activity_tags", "activity_id"},
+	{"activity_notes", "activity_id"},
+	{"activity_weather", "activity_id"},
+	{"activity_rpe", "activity_id"},
+	{"custom_metrics", "activity_id"},
+}
+
+// Stats returns the file size and per-table row counts and sizes, largest
+// table first.
+func (s *Store) Stats() (*DBStats, error) {
+	var pageSize, pageCount, freePages int64
+	if err := s.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return nil, fmt.Errorf("reading page size: %w", err)
+	}
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return nil, fmt.Errorf("reading page count: %w", err)
+	}
+	if err := s.db.QueryRow("PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return nil, fmt.Errorf("reading freelist count: %w", err)
+	}
+
+	stats := &DBStats{
+		FileBytes: pageSize * pageCount,
+		FreeBytes: pageSize * freePages,
+	}
+
+	// Table and index sizes, with indexes counted toward their table
+	sizes := make(map[string]int64)
+	rows, err := s.db.Query(`
+		SELECT m.tbl_name, SUM(d.pgsize)
+		FROM dbstat d
+		JOIN sqlite_master m ON d.name = m.name
+		GROUP BY m.tbl_name`)
+	if err != nil {
+		return nil, fmt.Errorf("reading table sizes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, fmt.Errorf("reading table sizes: %w", err)
+		}
+		sizes[name] = size
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading table sizes: %w", err)
+	}
+	rows.Close()
+
+	tables, err := s.tableNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range tables {
+		var count int
+		if err := s.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %q", name)).Scan(&count); err != nil {
+			return nil, fmt.Errorf("counting rows in %s: %w", name, err)
+		}
+		stats.Tables = append(stats.Tables, TableStat{Name: name, Rows: count, Bytes: sizes[name]})
+	}
+
+	sort.SliceStable(stats.Tables, func(i, j int) bool {
+		return stats.Tables[i].Bytes > stats.Tables[j].Bytes
+	})
+	return stats, nil
+}
+
+// UsedBytes returns the bytes the database's data takes up, the file size
+// less its free pages
+func (s *Store) UsedBytes() (int64, error) {
+	var used int64
+	err := s.db.QueryRow(`
+		SELECT (p.page_count - f.freelist_count) * s.page_size
+		FROM pragma_page_count() p, pragma_freelist_count() f, pragma_page_size() s`).Scan(&used)
+	if err != nil {
+		return 0, fmt.Errorf("reading database size: %w", err)
+	}
+	return used, nil
+}
+
+// Vacuum rebuilds the database file, reclaiming space from deleted rows.
+func (s *Store) Vacuum() error {
+	return s.write(func() error {
+		_, err := s.db.Exec("VACUUM")
+		return err
+	})
+}
+
+// tableNames returns the application tables in the database
+func (s *Store) tableNames() ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("listing tables: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// IntegrityCheck runs SQLite's integrity check and returns the problems it
+// reports, or nil if the database is sound.
+func (s *Store) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("running integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, fmt.Errorf("running integrity check: %w", err)
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// ForeignKeyViolations returns rows whose foreign keys point at missing rows.
+func (s *Store) ForeignKeyViolations() ([]ForeignKeyViolation, error) {
+	rows, err := s.db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("checking foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	var violations []ForeignKeyViolation
+	for rows.Next() {
+		var v ForeignKeyViolation
+		var fkid int
+		if err := rows.Scan(&v.Table, &v.RowID, &v.Parent, &fkid); err != nil {
+			return nil, fmt.Errorf("checking foreign keys: %w", err)
+		}
+		violations = append(violations, v)
+	}
+	return violations, rows.Err()
+}
+
+// FindOrphans counts rows per table that reference a missing activity. Only
+// tables with orphans are returned.
+func (s *Store) FindOrphans() ([]OrphanCount, error) {
+	var orphans []OrphanCount
+	for _, ref := range activityRefs {
+		var count int
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s NOT IN (SELECT id FROM activities)`, ref.table, ref.column)
+		if err := s.db.QueryRow(query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("counting orphans in %s: %w", ref.table, err)
+		}
+		if count > 0 {
+			orphans = append(orphans, OrphanCount{Table: ref.table, Rows: count})
+		}
+	}
+	return orphans, nil
+}
+
+// DeleteOrphans removes rows that reference a missing activity and returns
+// how many were deleted.
+func (s *Store) DeleteOrphans() (int, error) {
+	var deleted int
+	err := s.writeTx(func(tx *sql.Tx) error {
+		for _, ref := range activityRefs {
+			query := fmt.Sprintf(`DELETE FROM %s WHERE %s NOT IN (SELECT id FROM activities)`, ref.table, ref.column)
+			res, err := tx.Exec(query)
+			if err != nil {
+				return fmt.Errorf("deleting orphans in %s: %w", ref.table, err)
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
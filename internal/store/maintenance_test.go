@@ -0,0 +1,97 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.FileBytes <= 0 {
+		t.Errorf("expected a positive file size, got %d", stats.FileBytes)
+	}
+
+	var activities *TableStat
+	for i := range stats.Tables {
+		if stats.Tables[i].Name == "activities" {
+			activities = &stats.Tables[i]
+		}
+	}
+	if activities == nil {
+		t.Fatal("expected activities in table stats")
+	}
+	if activities.Rows != 2 || activities.Bytes <= 0 {
+		t.Errorf("activities: got %d rows and %d bytes, want 2 rows and a positive size", activities.Rows, activities.Bytes)
+	}
+
+	used, err := db.UsedBytes()
+	if err != nil {
+		t.Fatalf("UsedBytes() error = %v", err)
+	}
+	if used <= 0 || used != stats.FileBytes-stats.FreeBytes {
+		t.Errorf("UsedBytes() = %d, want file size less free pages, %d", used, stats.FileBytes-stats.FreeBytes)
+	}
+}
+
+func TestVerifyAndRepair(t *testing.T) {
+	db := setupTestDB(t)
+
+	if problems, err := db.IntegrityCheck(); err != nil || problems != nil {
+		t.Fatalf("IntegrityCheck() = %v, %v; want no problems", problems, err)
+	}
+
+	// Insert rows for a missing activity, as a database written without
+	// foreign key enforcement could contain
+	if _, err := db.DB().Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatalf("disabling foreign keys: %v", err)
+	}
+	for _, q := range []string{
+		"INSERT INTO streams (activity_id, time_offset) VALUES (99, 0), (99, 1)",
+		"INSERT INTO activity_metrics (activity_id) VALUES (99)",
+		"INSERT INTO activity_metrics (activity_id) VALUES (1)",
+	} {
+		if _, err := db.DB().Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	if _, err := db.DB().Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("enabling foreign keys: %v", err)
+	}
+
+	violations, err := db.ForeignKeyViolations()
+	if err != nil {
+		t.Fatalf("ForeignKeyViolations() error = %v", err)
+	}
+	if len(violations) != 3 {
+		t.Errorf("expected 3 foreign key violations, got %d", len(violations))
+	}
+
+	orphans, err := db.FindOrphans()
+	if err != nil {
+		t.Fatalf("FindOrphans() error = %v", err)
+	}
+	want := []OrphanCount{{Table: "streams", Rows: 2}, {Table: "activity_metrics", Rows: 1}}
+	if len(orphans) != len(want) || orphans[0] != want[0] || orphans[1] != want[1] {
+		t.Errorf("FindOrphans() = %v, want %v", orphans, want)
+	}
+
+	deleted, err := db.DeleteOrphans()
+	if err != nil {
+		t.Fatalf("DeleteOrphans() error = %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("DeleteOrphans() = %d, want 3", deleted)
+	}
+	if orphans, _ := db.FindOrphans(); len(orphans) != 0 {
+		t.Errorf("expected no orphans after repair, got %v", orphans)
+	}
+
+	// Metrics for a real activity are kept
+	if has, _ := db.HasMetrics(1); !has {
+		t.Error("expected metrics for activity 1 to survive repair")
+	}
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSaveActivityMetrics_RoundTrip(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	split, variability := 4.5, 2.1
+	z1, z2, z3, z4, z5 := 600, 1200, 300, 60, 0
+	want := ActivityMetrics{
+		ActivityID:      1,
+		AnomalyFlags:    []string{"pace_spike", "hr_flatline"},
+		PacingSplit:     &split,
+		PaceVariability: &variability,
+		Z1Seconds:       &z1,
+		Z2Seconds:       &z2,
+		Z3Seconds:       &z3,
+		Z4Seconds:       &z4,
+		Z5Seconds:       &z5,
+	}
+	if err := db.SaveActivityMetrics(&want); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 2}); err != nil {
+		t.Fatalf("SaveActivityMetrics(2) error = %v", err)
+	}
+
+	got, err := db.GetActivityMetrics(1)
+	if err != nil {
+		t.Fatalf("GetActivityMetrics() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("GetActivityMetrics() = %+v, want %+v", *got, want)
+	}
+
+	// Activities without flags read back as nil, not an empty flag
+	clean, err := db.GetActivityMetrics(2)
+	if err != nil {
+		t.Fatalf("GetActivityMetrics(2) error = %v", err)
+	}
+	if clean.AnomalyFlags != nil || clean.PacingSplit != nil || clean.Z1Seconds != nil {
+		t.Errorf("expected no flags, pacing or zone time, got %+v", *clean)
+	}
+
+	_, metrics, err := db.GetActivitiesWithMetrics(10, 0)
+	if err != nil {
+		t.Fatalf("GetActivitiesWithMetrics() error = %v", err)
+	}
+	for _, m := range metrics {
+		if m.ActivityID == 1 && !reflect.DeepEqual(m, want) {
+			t.Errorf("GetActivitiesWithMetrics() metrics = %+v, want %+v", m, want)
+		}
+	}
+}
+
+func TestRescaleLegacyHRSS(t *testing.T) {
+	db := setupTestDB(t)
+
+	// HRSS saved when it equaled TRIMP
+	trimp := 120.0
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1, TRIMP: &trimp, HRSS: &trimp}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+	if _, err := db.db.Exec("ALTER TABLE activity_metrics DROP COLUMN hrss_scaled"); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(db.db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	// Metrics saved since are already on the new scale
+	hrss := 50.0
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 2, TRIMP: &trimp, HRSS: &hrss}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+
+	n, err := db.RescaleLegacyHRSS(0.5)
+	if err != nil || n != 1 {
+		t.Fatalf("RescaleLegacyHRSS() = %d, %v, want 1 run", n, err)
+	}
+	for id, want := range map[int64]float64{1: 60, 2: 50} {
+		m, err := db.GetActivityMetrics(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.HRSS == nil || *m.HRSS != want {
+			t.Errorf("activity %d HRSS = %v, want %v", id, m.HRSS, want)
+		}
+	}
+
+	// Rescaling happens once
+	if n, err := db.RescaleLegacyHRSS(0.5); err != nil || n != 0 {
+		t.Errorf("second RescaleLegacyHRSS() = %d, %v, want 0", n, err)
+	}
+}
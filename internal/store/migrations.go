@@ -1,147 +1,608 @@
 package store
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// columnMigration adds a column to a table created by an earlier version
+type columnMigration struct {
+	table      string
+	column     string
+	definition string
+}
+
+// schemaMigrations creates every table and index. Each statement is
+// idempotent so they all run on every start.
+var schemaMigrations = []string{
+	// Authentication (singleton row)
+	`CREATE TABLE IF NOT EXISTS auth (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		athlete_id INTEGER NOT NULL,
+		access_token TEXT NOT NULL,
+		refresh_token TEXT NOT NULL,
+		expires_at INTEGER NOT NULL,
+		scopes TEXT NOT NULL DEFAULT '',
+		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Activities (summary data from /athlete/activities)
+	`CREATE TABLE IF NOT EXISTS activities (
+		id INTEGER PRIMARY KEY,
+		athlete_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		start_date TEXT NOT NULL,
+		start_date_local TEXT NOT NULL,
+		timezone TEXT,
+		distance REAL NOT NULL,
+		moving_time INTEGER NOT NULL,
+		elapsed_time INTEGER NOT NULL,
+		total_elevation_gain REAL,
+		average_speed REAL,
+		max_speed REAL,
+		average_heartrate REAL,
+		max_heartrate REAL,
+		average_cadence REAL,
+		suffer_score INTEGER,
+		has_heartrate INTEGER NOT NULL,
+		streams_synced INTEGER DEFAULT 0,
+		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_activities_start_date ON activities(start_date)`,
+	`CREATE INDEX IF NOT EXISTS idx_activities_type ON activities(type)`,
+	`CREATE INDEX IF NOT EXISTS idx_activities_has_hr ON activities(has_heartrate)`,
+
+	// Streams (second-by-second data from /activities/{id}/streams)
+	`CREATE TABLE IF NOT EXISTS streams (
+		activity_id INTEGER NOT NULL,
+		time_offset INTEGER NOT NULL,
+		latlng_lat REAL,
+		latlng_lng REAL,
+		altitude REAL,
+		velocity_smooth REAL,
+		heartrate INTEGER,
+		cadence INTEGER,
+		grade_smooth REAL,
+		distance REAL,
+		PRIMARY KEY (activity_id, time_offset),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_streams_activity ON streams(activity_id)`,
+
+	// Stream Blobs (compressed columnar streams, one row per activity)
+	`CREATE TABLE IF NOT EXISTS stream_blobs (
+		activity_id INTEGER PRIMARY KEY,
+		point_count INTEGER NOT NULL,
+		data BLOB NOT NULL,
+		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Computed Metrics (per activity)
+	`CREATE TABLE IF NOT EXISTS activity_metrics (
+		activity_id INTEGER PRIMARY KEY,
+		efficiency_factor REAL,
+		aerobic_decoupling REAL,
+		cardiac_drift REAL,
+		pace_at_z1 REAL,
+		pace_at_z2 REAL,
+		pace_at_z3 REAL,
+		trimp REAL,
+		hrss REAL,
+		data_quality_score REAL,
+		steady_state_pct REAL,
+		computed_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Daily Fitness Trends
+	`CREATE TABLE IF NOT EXISTS fitness_trends (
+		date TEXT PRIMARY KEY,
+		ctl REAL,
+		atl REAL,
+		tsb REAL,
+		efficiency_factor_7d REAL,
+		efficiency_factor_28d REAL,
+		efficiency_factor_90d REAL,
+		run_count_7d INTEGER,
+		total_distance_7d REAL,
+		total_time_7d INTEGER,
+		computed_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Sync State (key-value store for sync tracking)
+	`CREATE TABLE IF NOT EXISTS sync_state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Personal Records (PRs for race distances and best efforts)
+	`CREATE TABLE IF NOT EXISTS personal_records (
+		id INTEGER PRIMARY KEY,
+		category TEXT NOT NULL UNIQUE,
+		activity_id INTEGER NOT NULL,
+		distance_meters REAL NOT NULL,
+		duration_seconds INTEGER NOT NULL,
+		pace_per_mile REAL,
+		avg_heartrate REAL,
+		achieved_at TEXT NOT NULL,
+		start_offset INTEGER,
+		end_offset INTEGER,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_personal_records_activity ON personal_records(activity_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_personal_records_category ON personal_records(category)`,
+
+	// PR History (every improvement of each personal record, oldest first;
+	// margin is the gain over the previous entry in the category)
+	`CREATE TABLE IF NOT EXISTS pr_history (
+		id INTEGER PRIMARY KEY,
+		category TEXT NOT NULL,
+		activity_id INTEGER NOT NULL,
+		distance_meters REAL NOT NULL,
+		duration_seconds INTEGER NOT NULL,
+		pace_per_mile REAL,
+		achieved_at TEXT NOT NULL,
+		compare_mode INTEGER NOT NULL,
+		margin REAL,
+		UNIQUE (category, activity_id),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_pr_history_activity ON pr_history(activity_id)`,
+
+	// Duration Efforts (farthest distance covered in each pace-curve
+	// duration, per activity)
+	`CREATE TABLE IF NOT EXISTS duration_efforts (
+		activity_id INTEGER NOT NULL,
+		duration_seconds INTEGER NOT NULL,
+		distance_meters REAL NOT NULL,
+		start_offset INTEGER NOT NULL,
+		PRIMARY KEY (activity_id, duration_seconds),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Races (flagged on Strava or detected from distance and pace)
+	`CREATE TABLE IF NOT EXISTS races (
+		activity_id INTEGER PRIMARY KEY,
+		source TEXT NOT NULL,
+		placing TEXT NOT NULL DEFAULT '',
+		dismissed INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Race Predictions (VDOT-based predictions)
+	`CREATE TABLE IF NOT EXISTS race_predictions (
+		id INTEGER PRIMARY KEY,
+		target_distance TEXT NOT NULL UNIQUE,
+		target_meters REAL NOT NULL,
+		predicted_seconds INTEGER NOT NULL,
+		predicted_pace REAL NOT NULL,
+		vdot REAL NOT NULL,
+		source_category TEXT NOT NULL,
+		source_activity_id INTEGER NOT NULL,
+		confidence TEXT NOT NULL,
+		confidence_score REAL NOT NULL,
+		computed_at TEXT NOT NULL,
+		FOREIGN KEY (source_activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Activity Tags (local-only labels such as "race" or "new shoes")
+	`CREATE TABLE IF NOT EXISTS activity_tags (
+		activity_id INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (activity_id, tag),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_activity_tags_tag ON activity_tags(tag)`,
+
+	// Activity Notes (local-only free text)
+	`CREATE TABLE IF NOT EXISTS activity_notes (
+		activity_id INTEGER PRIMARY KEY,
+		note TEXT NOT NULL,
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Activity Weather (conditions during a run, entered by hand for now)
+	`CREATE TABLE IF NOT EXISTS activity_weather (
+		activity_id INTEGER PRIMARY KEY,
+		temperature_c REAL NOT NULL,
+		source TEXT NOT NULL DEFAULT 'manual',
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Activity RPE (perceived effort, 1-10, and how the run felt, 1-5,
+	// entered by hand)
+	`CREATE TABLE IF NOT EXISTS activity_rpe (
+		activity_id INTEGER PRIMARY KEY,
+		rpe INTEGER NOT NULL CHECK (rpe BETWEEN 1 AND 10),
+		feel INTEGER CHECK (feel BETWEEN 1 AND 5),
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Weekly Summaries (per-week totals maintained during sync, keyed by
+	// the Monday that starts the ISO week)
+	`CREATE TABLE IF NOT EXISTS weekly_summaries (
+		week_start TEXT PRIMARY KEY,
+		run_count INTEGER NOT NULL DEFAULT 0,
+		distance REAL NOT NULL DEFAULT 0,
+		moving_time INTEGER NOT NULL DEFAULT 0,
+		moving_distance REAL NOT NULL DEFAULT 0,
+		hr_sum REAL NOT NULL DEFAULT 0,
+		hr_count INTEGER NOT NULL DEFAULT 0,
+		cadence_sum REAL NOT NULL DEFAULT 0,
+		cadence_count INTEGER NOT NULL DEFAULT 0,
+		trimp REAL NOT NULL DEFAULT 0,
+		elevation_gain REAL NOT NULL DEFAULT 0,
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Injuries (local log of injuries and niggles, dates as YYYY-MM-DD)
+	`CREATE TABLE IF NOT EXISTS injuries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_on TEXT NOT NULL,
+		body_part TEXT NOT NULL,
+		severity INTEGER NOT NULL,
+		notes TEXT NOT NULL DEFAULT '',
+		resolved_on TEXT,
+		created_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Daily wellness (one manual or imported entry per day, date as YYYY-MM-DD)
+	`CREATE TABLE IF NOT EXISTS wellness (
+		date TEXT PRIMARY KEY,
+		resting_hr REAL,
+		hrv REAL,
+		sleep_hours REAL,
+		soreness INTEGER,
+		updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Benchmarks (repeated test workouts matched by route or duration)
+	`CREATE TABLE IF NOT EXISTS benchmarks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		kind TEXT NOT NULL,
+		reference_activity_id INTEGER NOT NULL,
+		created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (reference_activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Benchmark attempts added or removed by hand
+	`CREATE TABLE IF NOT EXISTS benchmark_activities (
+		benchmark_id INTEGER NOT NULL,
+		activity_id INTEGER NOT NULL,
+		included INTEGER NOT NULL,
+		PRIMARY KEY (benchmark_id, activity_id),
+		FOREIGN KEY (benchmark_id) REFERENCES benchmarks(id) ON DELETE CASCADE,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Activities trimmed locally
+	`CREATE TABLE IF NOT EXISTS activity_trims (
+		activity_id INTEGER PRIMARY KEY,
+		end_offset INTEGER NOT NULL,
+		original_distance REAL NOT NULL,
+		original_elapsed_time INTEGER NOT NULL,
+		trimmed_at TEXT NOT NULL,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Treadmill distance corrections
+	`CREATE TABLE IF NOT EXISTS activity_distance_corrections (
+		activity_id INTEGER PRIMARY KEY,
+		method TEXT NOT NULL,
+		stride_length REAL,
+		original_distance REAL NOT NULL,
+		corrected_at TEXT NOT NULL,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Activities whose best efforts the athlete confirmed despite GPS doubts
+	`CREATE TABLE IF NOT EXISTS effort_verifications (
+		activity_id INTEGER PRIMARY KEY,
+		verified_at TEXT NOT NULL,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Per-activity stream aggregates summed by period stats
+	`CREATE TABLE IF NOT EXISTS activity_stream_stats (
+		activity_id INTEGER PRIMARY KEY,
+		moving_time INTEGER NOT NULL DEFAULT 0,
+		moving_distance REAL NOT NULL DEFAULT 0,
+		hr_sum REAL NOT NULL DEFAULT 0,
+		hr_count INTEGER NOT NULL DEFAULT 0,
+		cadence_sum REAL NOT NULL DEFAULT 0,
+		cadence_count INTEGER NOT NULL DEFAULT 0,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Fields from the detailed activity endpoint
+	`CREATE TABLE IF NOT EXISTS activity_details (
+		activity_id INTEGER PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		device_name TEXT NOT NULL DEFAULT '',
+		calories REAL NOT NULL DEFAULT 0,
+		gear_id TEXT NOT NULL DEFAULT '',
+		gear_name TEXT NOT NULL DEFAULT '',
+		fetched_at TEXT NOT NULL,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Strava's splits per kilometer and per mile
+	`CREATE TABLE IF NOT EXISTS strava_splits (
+		activity_id INTEGER NOT NULL,
+		units TEXT NOT NULL,
+		split INTEGER NOT NULL,
+		distance REAL NOT NULL,
+		elapsed_time INTEGER NOT NULL,
+		moving_time INTEGER NOT NULL,
+		elevation_difference REAL NOT NULL DEFAULT 0,
+		average_speed REAL NOT NULL,
+		average_heartrate REAL,
+		PRIMARY KEY (activity_id, units, split),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Strava's best efforts per activity, imported as a source for PRs
+	`CREATE TABLE IF NOT EXISTS strava_best_efforts (
+		activity_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		distance REAL NOT NULL,
+		elapsed_time INTEGER NOT NULL,
+		moving_time INTEGER NOT NULL,
+		start_index INTEGER NOT NULL,
+		end_index INTEGER NOT NULL,
+		PRIMARY KEY (activity_id, name),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Which process is syncing (singleton row), so a second one can wait
+	`CREATE TABLE IF NOT EXISTS sync_lock (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		owner TEXT NOT NULL,
+		started_at TEXT NOT NULL,
+		heartbeat_at TEXT NOT NULL
+	)`,
+
+	// Kudos, comment and photo counts, kept only when privacy.sync_social is on
+	`CREATE TABLE IF NOT EXISTS activity_social (
+		activity_id INTEGER PRIMARY KEY,
+		kudos_count INTEGER NOT NULL DEFAULT 0,
+		comment_count INTEGER NOT NULL DEFAULT 0,
+		photo_count INTEGER NOT NULL DEFAULT 0,
+		photo_url TEXT,
+		updated_at TEXT NOT NULL,
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Per-run values of the custom metrics, by metric name
+	`CREATE TABLE IF NOT EXISTS custom_metrics (
+		activity_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		label TEXT NOT NULL,
+		value REAL NOT NULL,
+		PRIMARY KEY (activity_id, name),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+
+	// Strava responses kept with their validators, so a request can be
+	// revalidated with If-None-Match or If-Modified-Since
+	`CREATE TABLE IF NOT EXISTS http_cache (
+		url TEXT PRIMARY KEY,
+		etag TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		body BLOB NOT NULL,
+		stored_at TEXT NOT NULL
+	)`,
+
+	// Mile and kilometer splits computed from streams in the metrics phase
+	`CREATE TABLE IF NOT EXISTS activity_splits (
+		activity_id INTEGER NOT NULL,
+		units TEXT NOT NULL,
+		split INTEGER NOT NULL,
+		distance REAL NOT NULL,
+		partial INTEGER NOT NULL DEFAULT 0,
+		duration INTEGER NOT NULL,
+		start_offset INTEGER NOT NULL,
+		average_heartrate REAL,
+		average_cadence REAL,
+		PRIMARY KEY (activity_id, units, split),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_activity_splits_duration ON activity_splits(units, duration)`,
+	`CREATE TABLE IF NOT EXISTS activity_best_efforts (
+		activity_id INTEGER NOT NULL,
+		category TEXT NOT NULL,
+		distance_meters REAL NOT NULL,
+		duration_seconds INTEGER NOT NULL,
+		start_offset INTEGER NOT NULL,
+		end_offset INTEGER NOT NULL,
+		avg_heartrate REAL,
+		unverified INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (activity_id, category),
+		FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_activity_best_efforts_duration ON activity_best_efforts(category, duration_seconds)`,
+
+	// Mileage plan toward a race (one at a time, dates as YYYY-MM-DD)
+	`CREATE TABLE IF NOT EXISTS training_plan (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		race_date TEXT NOT NULL,
+		start_week TEXT NOT NULL,
+		base_distance REAL NOT NULL,
+		peak_distance REAL NOT NULL,
+		taper_weeks INTEGER NOT NULL,
+		cutback_every INTEGER NOT NULL,
+		created_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+
+	// Activities deleted locally, so sync doesn't bring them back
+	`CREATE TABLE IF NOT EXISTS deleted_activities (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		start_date TEXT NOT NULL,
+		deleted_at TEXT DEFAULT CURRENT_TIMESTAMP
+	)`,
+}
+
+// columnMigrations lists columns added after a table was first released.
+// SQLite has no ADD COLUMN IF NOT EXISTS, so each is checked first.
+var columnMigrations = []columnMigration{
+	{"activities", "manual", "INTEGER NOT NULL DEFAULT 0"},
+	{"activities", "excluded", "INTEGER NOT NULL DEFAULT 0"},
+	{"activity_metrics", "anomaly_flags", "TEXT"},
+	{"activity_metrics", "pacing_split", "REAL"},
+	{"activity_metrics", "pace_variability", "REAL"},
+	{"activity_metrics", "avg_stride_length", "REAL"},
+	{"activity_metrics", "hrr_60", "REAL"},
+	{"activity_metrics", "z1_seconds", "INTEGER"},
+	{"activity_metrics", "z2_seconds", "INTEGER"},
+	{"activity_metrics", "z3_seconds", "INTEGER"},
+	{"activity_metrics", "z4_seconds", "INTEGER"},
+	{"activity_metrics", "z5_seconds", "INTEGER"},
+	{"fitness_trends", "monotony_7d", "REAL"},
+	{"personal_records", "unverified", "INTEGER NOT NULL DEFAULT 0"},
+	{"fitness_trends", "strain_7d", "REAL"},
+	{"weekly_summaries", "elevation_gain", "REAL NOT NULL DEFAULT 0"},
+	{"activity_stream_stats", "max_hr", "INTEGER"},
+	{"activity_rpe", "feel", "INTEGER CHECK (feel BETWEEN 1 AND 5)"},
+	{"auth", "scopes", "TEXT NOT NULL DEFAULT ''"},
+	{"activities", "efforts_computed", "INTEGER NOT NULL DEFAULT 0"},
+	{"activity_metrics", "hrss_scaled", "INTEGER NOT NULL DEFAULT 1"},
+}
+
+// columnBackfills run once when their column is added to an existing table,
+// keyed by "table.column"
+var columnBackfills = map[string]string{
+	// Emptied summaries are rebuilt from activities on first use
+	"weekly_summaries.elevation_gain": `DELETE FROM weekly_summaries`,
+	// HRSS used to equal TRIMP; RescaleLegacyHRSS puts these on the
+	// per-hour scale once the HR zones are known
+	"activity_metrics.hrss_scaled": `UPDATE activity_metrics SET hrss_scaled = 0 WHERE hrss IS NOT NULL`,
+}
+
+// createTablePattern extracts the table name from a CREATE TABLE migration
+var createTablePattern = regexp.MustCompile(`CREATE TABLE IF NOT EXISTS (\w+)`)
 
 // migrate runs all database migrations
 func migrate(db *sql.DB) error {
-	migrations := []string{
-		// Authentication (singleton row)
-		`CREATE TABLE IF NOT EXISTS auth (
-			id INTEGER PRIMARY KEY CHECK (id = 1),
-			athlete_id INTEGER NOT NULL,
-			access_token TEXT NOT NULL,
-			refresh_token TEXT NOT NULL,
-			expires_at INTEGER NOT NULL,
-			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Activities (summary data from /athlete/activities)
-		`CREATE TABLE IF NOT EXISTS activities (
-			id INTEGER PRIMARY KEY,
-			athlete_id INTEGER NOT NULL,
-			name TEXT NOT NULL,
-			type TEXT NOT NULL,
-			start_date TEXT NOT NULL,
-			start_date_local TEXT NOT NULL,
-			timezone TEXT,
-			distance REAL NOT NULL,
-			moving_time INTEGER NOT NULL,
-			elapsed_time INTEGER NOT NULL,
-			total_elevation_gain REAL,
-			average_speed REAL,
-			max_speed REAL,
-			average_heartrate REAL,
-			max_heartrate REAL,
-			average_cadence REAL,
-			suffer_score INTEGER,
-			has_heartrate INTEGER NOT NULL,
-			streams_synced INTEGER DEFAULT 0,
-			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_activities_start_date ON activities(start_date)`,
-		`CREATE INDEX IF NOT EXISTS idx_activities_type ON activities(type)`,
-		`CREATE INDEX IF NOT EXISTS idx_activities_has_hr ON activities(has_heartrate)`,
-
-		// Streams (second-by-second data from /activities/{id}/streams)
-		`CREATE TABLE IF NOT EXISTS streams (
-			activity_id INTEGER NOT NULL,
-			time_offset INTEGER NOT NULL,
-			latlng_lat REAL,
-			latlng_lng REAL,
-			altitude REAL,
-			velocity_smooth REAL,
-			heartrate INTEGER,
-			cadence INTEGER,
-			grade_smooth REAL,
-			distance REAL,
-			PRIMARY KEY (activity_id, time_offset),
-			FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
-		)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_streams_activity ON streams(activity_id)`,
-
-		// Computed Metrics (per activity)
-		`CREATE TABLE IF NOT EXISTS activity_metrics (
-			activity_id INTEGER PRIMARY KEY,
-			efficiency_factor REAL,
-			aerobic_decoupling REAL,
-			cardiac_drift REAL,
-			pace_at_z1 REAL,
-			pace_at_z2 REAL,
-			pace_at_z3 REAL,
-			trimp REAL,
-			hrss REAL,
-			data_quality_score REAL,
-			steady_state_pct REAL,
-			computed_at TEXT DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
-		)`,
-
-		// Daily Fitness Trends
-		`CREATE TABLE IF NOT EXISTS fitness_trends (
-			date TEXT PRIMARY KEY,
-			ctl REAL,
-			atl REAL,
-			tsb REAL,
-			efficiency_factor_7d REAL,
-			efficiency_factor_28d REAL,
-			efficiency_factor_90d REAL,
-			run_count_7d INTEGER,
-			total_distance_7d REAL,
-			total_time_7d INTEGER,
-			computed_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Sync State (key-value store for sync tracking)
-		`CREATE TABLE IF NOT EXISTS sync_state (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL,
-			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Personal Records (PRs for race distances and best efforts)
-		`CREATE TABLE IF NOT EXISTS personal_records (
-			id INTEGER PRIMARY KEY,
-			category TEXT NOT NULL UNIQUE,
-			activity_id INTEGER NOT NULL,
-			distance_meters REAL NOT NULL,
-			duration_seconds INTEGER NOT NULL,
-			pace_per_mile REAL,
-			avg_heartrate REAL,
-			achieved_at TEXT NOT NULL,
-			start_offset INTEGER,
-			end_offset INTEGER,
-			FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
-		)`,
-
-		`CREATE INDEX IF NOT EXISTS idx_personal_records_activity ON personal_records(activity_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_personal_records_category ON personal_records(category)`,
-
-		// Race Predictions (VDOT-based predictions)
-		`CREATE TABLE IF NOT EXISTS race_predictions (
-			id INTEGER PRIMARY KEY,
-			target_distance TEXT NOT NULL UNIQUE,
-			target_meters REAL NOT NULL,
-			predicted_seconds INTEGER NOT NULL,
-			predicted_pace REAL NOT NULL,
-			vdot REAL NOT NULL,
-			source_category TEXT NOT NULL,
-			source_activity_id INTEGER NOT NULL,
-			confidence TEXT NOT NULL,
-			confidence_score REAL NOT NULL,
-			computed_at TEXT NOT NULL,
-			FOREIGN KEY (source_activity_id) REFERENCES activities(id) ON DELETE CASCADE
-		)`,
+	for _, m := range schemaMigrations {
+		if _, err := db.Exec(m); err != nil {
+			return err
+		}
 	}
 
-	for _, m := range migrations {
-		if _, err := db.Exec(m); err != nil {
+	for _, c := range columnMigrations {
+		if err := addColumnIfMissing(db, c); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// hasPendingMigrations reports whether migrate would add tables or columns
+// to an existing database. A brand new database has nothing pending.
+func hasPendingMigrations(db *sql.DB) (bool, error) {
+	existing := make(map[string]bool)
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table'`)
+	if err != nil {
+		return false, fmt.Errorf("listing tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, fmt.Errorf("listing tables: %w", err)
+		}
+		existing[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("listing tables: %w", err)
+	}
+	rows.Close()
+
+	if !existing["activities"] {
+		return false, nil
+	}
+
+	for _, m := range schemaMigrations {
+		if match := createTablePattern.FindStringSubmatch(m); match != nil && !existing[match[1]] {
+			return true, nil
+		}
+	}
+	for _, c := range columnMigrations {
+		if !existing[c.table] {
+			continue
+		}
+		found, err := hasColumn(db, c.table, c.column)
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addColumnIfMissing adds a column unless the table already has it
+func addColumnIfMissing(db *sql.DB, c columnMigration) error {
+	found, err := hasColumn(db, c.table, c.column)
+	if err != nil || found {
+		return err
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+		return fmt.Errorf("adding column %s.%s: %w", c.table, c.column, err)
+	}
+	if backfill, ok := columnBackfills[c.table+"."+c.column]; ok {
+		if _, err := db.Exec(backfill); err != nil {
+			return fmt.Errorf("filling column %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
+// hasColumn reports whether a table has the named column
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, fmt.Errorf("reading columns of %s: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("reading columns of %s: %w", table, err)
+	}
+	return false, nil
+}
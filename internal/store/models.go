@@ -8,6 +8,7 @@ type Auth struct {
 	AccessToken  string    `db:"access_token"`
 	RefreshToken string    `db:"refresh_token"`
 	ExpiresAt    time.Time `db:"expires_at"`
+	Scopes       string    `db:"scopes"` // comma separated as granted; empty if not recorded
 }
 
 // Activity represents a Strava activity summary
@@ -31,6 +32,8 @@ type Activity struct {
 	SufferScore        *int      `db:"suffer_score"`        // nullable
 	HasHeartrate       bool      `db:"has_heartrate"`
 	StreamsSynced      bool      `db:"streams_synced"`
+	Manual             bool      `db:"manual"`              // entered locally, not from Strava
+	Excluded           bool      `db:"excluded"`            // hidden from metrics, trends and PRs
 }
 
 // StreamPoint represents a single data point from activity streams
@@ -60,6 +63,16 @@ type ActivityMetrics struct {
 	HRSS              *float64 `db:"hrss"`
 	DataQualityScore  *float64 `db:"data_quality_score"`
 	SteadyStatePct    *float64 `db:"steady_state_pct"`
+	AnomalyFlags      []string `db:"anomaly_flags"`     // Suspect-data flags, empty when the data looks sane
+	PacingSplit       *float64 `db:"pacing_split"`      // Second half vs first half pace, %; negative is a negative split
+	PaceVariability   *float64 `db:"pace_variability"`  // Coefficient of variation of per-km split paces, %
+	AvgStrideLength   *float64 `db:"avg_stride_length"` // Meters per step
+	HRR60             *float64 `db:"hrr_60"`            // Largest 60s HR drop after a hard effort, bpm
+	Z1Seconds         *int     `db:"z1_seconds"`        // time in HR zone 1
+	Z2Seconds         *int     `db:"z2_seconds"`        // time in HR zone 2
+	Z3Seconds         *int     `db:"z3_seconds"`        // time in HR zone 3
+	Z4Seconds         *int     `db:"z4_seconds"`        // time in HR zone 4
+	Z5Seconds         *int     `db:"z5_seconds"`        // time in HR zone 5
 }
 
 // FitnessTrend represents daily aggregated fitness metrics
@@ -74,6 +87,25 @@ type FitnessTrend struct {
 	RunCount7d          int      `db:"run_count_7d"`
 	TotalDistance7d     float64  `db:"total_distance_7d"`
 	TotalTime7d         int      `db:"total_time_7d"`
+	Monotony7d          *float64 `db:"monotony_7d"` // Foster's monotony over the 7 days ending on Date
+	Strain7d            *float64 `db:"strain_7d"`   // 7-day load x monotony
+}
+
+// TrainingLoad is an analyzed run's contribution to the daily fitness trends
+type TrainingLoad struct {
+	StartDate  time.Time
+	BucketDate time.Time // StartDate on the day-bucketing clock
+	Distance   float64 // meters
+	MovingTime int     // seconds
+	TRIMP      *float64
+	HRSS       *float64
+	RPE        *int // perceived effort, 1-10, when logged
+}
+
+// ActivityRPE is how hard a run felt, logged by hand after it
+type ActivityRPE struct {
+	RPE  int  // perceived effort, MinRPE to MaxRPE
+	Feel *int // how the runner felt, MinFeel (awful) to MaxFeel (great)
 }
 
 // PersonalRecord represents a personal best for a specific category
@@ -88,6 +120,236 @@ type PersonalRecord struct {
 	AchievedAt      time.Time `db:"achieved_at"`
 	StartOffset     *int      `db:"start_offset"`     // for best efforts: start time offset in stream
 	EndOffset       *int      `db:"end_offset"`       // for best efforts: end time offset in stream
+	Unverified      bool      `db:"unverified"`       // best effort with doubtful GPS, not confirmed by the athlete
+}
+
+// PRHistoryEntry is one improvement in a personal record's progression
+type PRHistoryEntry struct {
+	ID              int64       `db:"id"`
+	Category        string      `db:"category"`
+	ActivityID      int64       `db:"activity_id"`
+	DistanceMeters  float64     `db:"distance_meters"`
+	DurationSeconds int         `db:"duration_seconds"`
+	PacePerMile     *float64    `db:"pace_per_mile"` // seconds per mile
+	AchievedAt      time.Time   `db:"achieved_at"`
+	Mode            CompareMode `db:"compare_mode"`
+	// Margin is the gain over the previous entry: seconds faster, meters
+	// further, or seconds per mile faster depending on Mode. Nil for the
+	// first entry in a category.
+	Margin *float64 `db:"margin"`
+}
+
+// DurationEffort is the farthest distance an activity covered in one of the
+// pace-curve durations
+type DurationEffort struct {
+	ActivityID      int64     `db:"activity_id"`
+	DurationSeconds int       `db:"duration_seconds"`
+	DistanceMeters  float64   `db:"distance_meters"`
+	StartOffset     int       `db:"start_offset"`
+	StartDate       time.Time `db:"start_date"` // of the activity, when read back
+}
+
+// ActivityDetails holds the fields only Strava's detailed activity endpoint
+// returns
+type ActivityDetails struct {
+	ActivityID  int64     `db:"activity_id"`
+	Description string    `db:"description"`
+	DeviceName  string    `db:"device_name"`
+	Calories    float64   `db:"calories"` // kcal
+	GearID      string    `db:"gear_id"`
+	GearName    string    `db:"gear_name"` // shoes, e.g. "Pegasus 40"
+	FetchedAt   time.Time `db:"fetched_at"`
+}
+
+// ActivitySocial is how other athletes reacted to a run on Strava
+type ActivitySocial struct {
+	ActivityID   int64  `db:"activity_id"`
+	KudosCount   int    `db:"kudos_count"`
+	CommentCount int    `db:"comment_count"`
+	PhotoCount   int    `db:"photo_count"`
+	PhotoURL     string `db:"photo_url"` // primary photo, empty unless synced
+}
+
+// Units of Strava's splits, also used for the splits computed from streams
+const (
+	SplitsMetric   = "metric"   // per kilometer
+	SplitsStandard = "standard" // per mile
+)
+
+// StravaSplit is one of Strava's per-kilometer or per-mile splits
+type StravaSplit struct {
+	ActivityID          int64    `db:"activity_id"`
+	Units               string   `db:"units"`                // SplitsMetric or SplitsStandard
+	Split               int      `db:"split"`                // starting at 1
+	Distance            float64  `db:"distance"`             // meters
+	ElapsedTime         int      `db:"elapsed_time"`         // seconds
+	MovingTime          int      `db:"moving_time"`          // seconds
+	ElevationDifference float64  `db:"elevation_difference"` // meters
+	AverageSpeed        float64  `db:"average_speed"`        // m/s
+	AverageHeartrate    *float64 `db:"average_heartrate"`    // nullable
+}
+
+// ActivitySplit is a mile or kilometer split computed from a run's streams
+type ActivitySplit struct {
+	ActivityID       int64    `db:"activity_id"`
+	Units            string   `db:"units"`             // SplitsMetric or SplitsStandard
+	Split            int      `db:"split"`             // starting at 1
+	Distance         float64  `db:"distance"`          // meters; a full unit unless Partial
+	Partial          bool     `db:"partial"`           // the shorter final split
+	Duration         int      `db:"duration"`          // seconds
+	StartOffset      int      `db:"start_offset"`      // seconds into the streams
+	AverageHeartrate *float64 `db:"average_heartrate"` // nil without heart rate
+	AverageCadence   *float64 `db:"average_cadence"`   // steps per minute; nil without cadence
+}
+
+// SplitRecord is a split with the run it was in, for split rankings
+type SplitRecord struct {
+	ActivitySplit
+	ActivityName   string
+	StartDateLocal time.Time
+}
+
+// ActivityBestEffort is a run's fastest stretch over one best-effort
+// distance, whether or not it's the personal record
+type ActivityBestEffort struct {
+	ActivityID      int64    `db:"activity_id"`
+	Category        string   `db:"category"`         // e.g., "effort_1k", "effort_1mi"
+	DistanceMeters  float64  `db:"distance_meters"`
+	DurationSeconds int      `db:"duration_seconds"`
+	StartOffset     int      `db:"start_offset"`     // seconds into the streams
+	EndOffset       int      `db:"end_offset"`
+	AvgHeartrate    *float64 `db:"avg_heartrate"`    // nil without heart rate
+	Unverified      bool     `db:"unverified"`       // doubtful GPS, as for personal records
+}
+
+// EffortRecord is a best effort with the run it was in, for effort rankings
+type EffortRecord struct {
+	ActivityBestEffort
+	ActivityName   string
+	StartDateLocal time.Time
+}
+
+// CustomMetric is one run's value of a metric computed by an
+// analysis.MetricComputer, with the label it was computed under
+type CustomMetric struct {
+	Name  string
+	Label string
+	Value float64
+}
+
+// StravaBestEffort is Strava's fastest time over a standard distance within
+// an activity. StartIndex and EndIndex point into the activity's streams.
+type StravaBestEffort struct {
+	ActivityID  int64   `db:"activity_id"`
+	Name        string  `db:"name"`
+	Distance    float64 `db:"distance"`     // meters
+	ElapsedTime int     `db:"elapsed_time"` // seconds
+	MovingTime  int     `db:"moving_time"`  // seconds
+	StartIndex  int     `db:"start_index"`
+	EndIndex    int     `db:"end_index"`
+}
+
+// RaceSource says how an activity came to be listed as a race
+type RaceSource string
+
+// Race sources
+const (
+	RaceSourceStrava   RaceSource = "strava"   // workout type set to Race on Strava
+	RaceSourceDetected RaceSource = "detected" // race distance at a much faster pace than usual
+)
+
+// Race marks an activity as a race
+type Race struct {
+	ActivityID int64      `db:"activity_id"`
+	Source     RaceSource `db:"source"`
+	Placing    string     `db:"placing"` // free-form, e.g. "3rd of 41, 1st M40"
+	Dismissed  bool       `db:"dismissed"`
+}
+
+// BenchmarkKind says how runs are matched to a benchmark's reference run
+type BenchmarkKind string
+
+// Benchmark kinds
+const (
+	BenchmarkRoute    BenchmarkKind = "route"    // same start and distance, e.g. a tempo loop
+	BenchmarkDuration BenchmarkKind = "duration" // same moving time, e.g. a 30 minute MAF test
+)
+
+// Benchmark is a repeated test workout tracked across attempts
+type Benchmark struct {
+	ID                  int64         `db:"id"`
+	Name                string        `db:"name"`
+	Kind                BenchmarkKind `db:"kind"`
+	ReferenceActivityID int64         `db:"reference_activity_id"`
+}
+
+// BenchmarkOverride adds a run to a benchmark, or removes a matched one
+type BenchmarkOverride struct {
+	BenchmarkID int64 `db:"benchmark_id"`
+	ActivityID  int64 `db:"activity_id"`
+	Included    bool  `db:"included"`
+}
+
+// ActivityTrim records an activity cut short locally. The activity's
+// summary and streams hold the trimmed values; the originals are kept here.
+type ActivityTrim struct {
+	ActivityID          int64     `db:"activity_id"`
+	EndOffset           int       `db:"end_offset"`            // last kept second
+	OriginalDistance    float64   `db:"original_distance"`     // meters
+	OriginalElapsedTime int       `db:"original_elapsed_time"` // seconds
+	TrimmedAt           time.Time `db:"trimmed_at"`
+}
+
+// DistanceCorrectionMethod says how a run's distance was corrected
+type DistanceCorrectionMethod string
+
+// Distance correction methods
+const (
+	CorrectionFactor  DistanceCorrectionMethod = "factor"  // speed and distance scaled by a calibration factor
+	CorrectionCadence DistanceCorrectionMethod = "cadence" // speed rebuilt from cadence × stride length
+)
+
+// DistanceCorrection records a treadmill or indoor run whose distance was
+// corrected locally. The activity's summary and streams hold the corrected
+// values.
+type DistanceCorrection struct {
+	ActivityID       int64                    `db:"activity_id"`
+	Method           DistanceCorrectionMethod `db:"method"`
+	StrideLength     *float64                 `db:"stride_length"`     // meters per step, cadence only
+	OriginalDistance float64                  `db:"original_distance"` // meters
+	CorrectedAt      time.Time                `db:"corrected_at"`
+}
+
+// Injury is an injury or niggle logged by the athlete. Dates are calendar
+// days at midnight UTC.
+type Injury struct {
+	ID         int64      `db:"id"`
+	StartedOn  time.Time  `db:"started_on"`
+	BodyPart   string     `db:"body_part"`
+	Severity   int        `db:"severity"` // 1 (niggle) to 5 (can't run)
+	Notes      string     `db:"notes"`
+	ResolvedOn *time.Time `db:"resolved_on"` // nil while still active
+}
+
+// TrainingPlan is a mileage build toward a race. Dates are calendar days at
+// midnight UTC.
+type TrainingPlan struct {
+	RaceDate     time.Time `db:"race_date"`
+	StartWeek    time.Time `db:"start_week"`    // first day of the plan's first week
+	BaseDistance float64   `db:"base_distance"` // meters a week the build starts from
+	PeakDistance float64   `db:"peak_distance"` // meters a week at the top of the build
+	TaperWeeks   int       `db:"taper_weeks"`   // race week included
+	CutbackEvery int       `db:"cutback_every"` // every Nth build week eases off; 0 for none
+}
+
+// Wellness is one day's wellness entry, logged by hand or imported. Any
+// field may be missing. Date is the calendar day at midnight UTC.
+type Wellness struct {
+	Date       time.Time `db:"date"`
+	RestingHR  *float64  `db:"resting_hr"`  // bpm
+	HRV        *float64  `db:"hrv"`         // ms, e.g. morning rMSSD
+	SleepHours *float64  `db:"sleep_hours"` // the night before
+	Soreness   *int      `db:"soreness"`    // 1 (none) to 5 (very sore)
 }
 
 // RacePrediction represents a predicted race time
@@ -104,3 +366,116 @@ type RacePrediction struct {
 	ConfidenceScore  float64   `db:"confidence_score"`
 	ComputedAt       time.Time `db:"computed_at"`
 }
+
+// WeeklySummary holds pre-aggregated totals for one week
+type WeeklySummary struct {
+	WeekStart      time.Time `db:"week_start"` // first day of the week, midnight
+	RunCount       int       `db:"run_count"`
+	Distance       float64   `db:"distance"`        // meters, from activity summaries
+	MovingTime     int       `db:"moving_time"`     // seconds moving, from streams
+	MovingDistance float64   `db:"moving_distance"` // meters, from streams
+	HRSum          float64   `db:"hr_sum"`
+	HRCount        int       `db:"hr_count"`
+	CadenceSum     float64   `db:"cadence_sum"` // steps per minute
+	CadenceCount   int       `db:"cadence_count"`
+	TRIMP          float64   `db:"trimp"`
+	ElevationGain  float64   `db:"elevation_gain"` // meters, from activity summaries
+}
+
+// ActivityStreamStats are an activity's stream aggregates, cached with its
+// metrics so period stats can be summed without loading streams
+type ActivityStreamStats struct {
+	ActivityID     int64
+	MovingTime     int     // seconds moving
+	MovingDistance float64 // meters, from streams
+	HRSum          float64
+	HRCount        int
+	CadenceSum     float64 // steps per minute
+	CadenceCount   int
+	MaxHR          int // bpm, 0 without heart rate
+}
+
+// AvgHR returns the average heart rate, or 0 without heart rate data
+func (s ActivityStreamStats) AvgHR() float64 {
+	if s.HRCount == 0 {
+		return 0
+	}
+	return s.HRSum / float64(s.HRCount)
+}
+
+// AvgCadence returns the average cadence, or 0 without cadence data
+func (s ActivityStreamStats) AvgCadence() float64 {
+	if s.CadenceCount == 0 {
+		return 0
+	}
+	return s.CadenceSum / float64(s.CadenceCount)
+}
+
+// PeriodTotals sums the analyzed, non-excluded activities in a period
+type PeriodTotals struct {
+	Start          time.Time // first of the month, local midnight, for monthly totals
+	RunCount       int
+	Distance       float64 // meters, from activity summaries
+	MovingTime     int     // seconds moving, from streams
+	MovingDistance float64 // meters, from streams
+	HRSum          float64
+	HRCount        int
+	CadenceSum     float64 // steps per minute
+	CadenceCount   int
+	EFSum          float64
+	EFCount        int
+}
+
+// WeekActivity is an analyzed activity counted in a weekly summary
+type WeekActivity struct {
+	ID            int64
+	Distance      float64 // meters
+	ElevationGain float64 // meters
+	TRIMP         *float64
+	StreamStats   *ActivityStreamStats // nil until cached at sync time
+}
+
+// ActivityFilter narrows an activity search. Zero-valued fields are ignored.
+type ActivityFilter struct {
+	Name        string    // case-insensitive substring of the activity name or note
+	Type        string    // exact activity type, e.g. "Run"
+	After       time.Time // start_date_local on or after
+	Before      time.Time // start_date_local before
+	MinDistance float64   // meters
+	MaxDistance float64   // meters
+	HasPR       bool      // only activities holding a personal record
+	Tag         string    // only activities with this tag
+}
+
+// IsEmpty reports whether the filter matches every activity.
+func (f ActivityFilter) IsEmpty() bool {
+	return f == ActivityFilter{}
+}
+
+// ActivitySortField is a column the activities list can be ordered by.
+type ActivitySortField string
+
+// Activity sort fields
+const (
+	SortByDate       ActivitySortField = "date"
+	SortByDistance   ActivitySortField = "distance"
+	SortByDuration   ActivitySortField = "duration"
+	SortByPace       ActivitySortField = "pace"
+	SortByEF         ActivitySortField = "ef"
+	SortByTRIMP      ActivitySortField = "trimp"
+	SortByDecoupling ActivitySortField = "decoupling"
+	SortByKudos      ActivitySortField = "kudos"
+)
+
+// ActivitySort orders an activity search. The zero value sorts newest first.
+type ActivitySort struct {
+	Field     ActivitySortField
+	Ascending bool
+}
+
+// ActivityCursor is the activity a keyset page of a date-ordered search
+// starts after.
+type ActivityCursor struct {
+	StartDate time.Time
+	ID        int64
+}
@@ -1,64 +1,45 @@
 package store
 
 import (
-	"database/sql"
+	"errors"
 	"testing"
 	"time"
-
-	_ "modernc.org/sqlite"
 )
 
 // setupTestDB creates an in-memory database for testing
 func setupTestDB(t *testing.T) *Store {
 	t.Helper()
 
-	sqlDB, err := sql.Open("sqlite", ":memory:")
+	store, err := OpenMemory()
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
-
-	// Enable foreign keys
-	if _, err := sqlDB.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		sqlDB.Close()
-		t.Fatalf("Failed to enable foreign keys: %v", err)
-	}
-
-	// Run migrations
-	if err := migrate(sqlDB); err != nil {
-		sqlDB.Close()
-		t.Fatalf("Failed to run migrations: %v", err)
-	}
-
-	store := newStore(sqlDB)
+	t.Cleanup(func() {
+		store.Close()
+	})
 
 	// Insert a test activity for foreign key constraints
-	_, err = sqlDB.Exec(`
+	_, err = store.db.Exec(`
 		INSERT INTO activities (id, athlete_id, name, type, start_date, start_date_local,
 			distance, moving_time, elapsed_time, has_heartrate, streams_synced)
 		VALUES (1, 123, 'Test Run', 'Run', '2024-01-15T10:00:00Z', '2024-01-15T10:00:00Z',
 			5000, 1500, 1600, 1, 1)
 	`)
 	if err != nil {
-		sqlDB.Close()
 		t.Fatalf("Failed to insert test activity: %v", err)
 	}
 
 	// Insert a second test activity
-	_, err = sqlDB.Exec(`
+	_, err = store.db.Exec(`
 		INSERT INTO activities (id, athlete_id, name, type, start_date, start_date_local,
 			distance, moving_time, elapsed_time, has_heartrate, streams_synced)
 		VALUES (2, 123, 'Another Run', 'Run', '2024-01-20T10:00:00Z', '2024-01-20T10:00:00Z',
 			10000, 3000, 3100, 1, 1)
 	`)
 	if err != nil {
-		sqlDB.Close()
 		t.Fatalf("Failed to insert second test activity: %v", err)
 	}
 
-	t.Cleanup(func() {
-		sqlDB.Close()
-	})
-
 	return store
 }
 
@@ -306,3 +287,160 @@ func TestPersonalRecord_WithOffsets(t *testing.T) {
 		t.Error("EndOffset not saved correctly")
 	}
 }
+
+func TestPRHistory_OutOfOrder(t *testing.T) {
+	db := setupTestDB(t)
+	if _, err := db.DB().Exec(`
+		INSERT INTO activities (id, athlete_id, name, type, start_date, start_date_local,
+			distance, moving_time, elapsed_time, has_heartrate, streams_synced)
+		VALUES (3, 123, 'Third Run', 'Run', '2024-02-01T10:00:00Z', '2024-02-01T10:00:00Z',
+			5000, 1400, 1450, 1, 1)
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	offer := func(activityID int64, day time.Time, seconds int) {
+		t.Helper()
+		_, err := db.UpsertPersonalRecord(&PersonalRecord{
+			Category: "distance_5k", ActivityID: activityID, DistanceMeters: 5000, DurationSeconds: seconds, AchievedAt: day,
+		})
+		if err != nil {
+			t.Fatalf("UpsertPersonalRecord failed: %v", err)
+		}
+	}
+	history := func() []PRHistoryEntry {
+		t.Helper()
+		entries, err := db.GetPRHistory("distance_5k")
+		if err != nil {
+			t.Fatalf("GetPRHistory failed: %v", err)
+		}
+		return entries
+	}
+
+	jan15 := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	jan20 := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+	feb1 := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	// Sync offers the newest run first
+	offer(3, feb1, 1400)
+	offer(2, jan20, 1450)
+	offer(1, jan15, 1500)
+	offer(1, jan15, 1500) // offering again changes nothing
+
+	entries := history()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []int64{1, 2, 3} {
+		if entries[i].ActivityID != want {
+			t.Errorf("entry %d is activity %d, want %d", i, entries[i].ActivityID, want)
+		}
+	}
+	if entries[0].Margin != nil || entries[1].Margin == nil || *entries[1].Margin != 50 || *entries[2].Margin != 50 {
+		t.Errorf("expected margins nil, 50, 50; got %v, %v, %v", entries[0].Margin, entries[1].Margin, entries[2].Margin)
+	}
+
+	prev, err := db.GetPreviousRecord("distance_5k", 3)
+	if err != nil || prev == nil || prev.ActivityID != 2 {
+		t.Fatalf("GetPreviousRecord = %+v, %v; want activity 2", prev, err)
+	}
+
+	// Re-analyzing the oldest run with a faster time makes the middle one
+	// no longer an improvement
+	offer(1, jan15, 1420)
+	entries = history()
+	if len(entries) != 2 || entries[0].ActivityID != 1 || entries[1].ActivityID != 3 {
+		t.Fatalf("expected activities 1 and 3, got %+v", entries)
+	}
+	if *entries[1].Margin != 20 {
+		t.Errorf("expected margin 20, got %v", *entries[1].Margin)
+	}
+
+	// Deleting a run's records drops it from the history too
+	if err := db.DeletePersonalRecordsForActivity(1); err != nil {
+		t.Fatal(err)
+	}
+	entries = history()
+	if len(entries) != 1 || entries[0].Margin != nil {
+		t.Errorf("expected activity 3 alone with no margin, got %+v", entries)
+	}
+	if prev, _ := db.GetPreviousRecord("distance_5k", 3); prev != nil {
+		t.Errorf("expected no previous record, got %+v", prev)
+	}
+}
+
+func TestPRHistory_DistanceMode(t *testing.T) {
+	db := setupTestDB(t)
+
+	for _, pr := range []*PersonalRecord{
+		{Category: "longest_run", ActivityID: 2, DistanceMeters: 10000, AchievedAt: time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)},
+		{Category: "longest_run", ActivityID: 1, DistanceMeters: 5000, AchievedAt: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+	} {
+		if _, err := db.UpsertPersonalRecordWithMode(pr, CompareDistance); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := db.GetPRHistory("longest_run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Margin == nil || *entries[1].Margin != 5000 {
+		t.Errorf("expected the 10K run to improve by 5000 m, got %+v", entries)
+	}
+}
+
+func TestStaleRecordCategories(t *testing.T) {
+	db := setupTestDB(t)
+
+	jan15 := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	jan20 := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+	for _, pr := range []*PersonalRecord{
+		{Category: "distance_5k", ActivityID: 1, DistanceMeters: 5000, DurationSeconds: 1500, AchievedAt: jan15},
+		{Category: "distance_5k", ActivityID: 2, DistanceMeters: 5000, DurationSeconds: 1450, AchievedAt: jan20},
+		{Category: "distance_10k", ActivityID: 1, DistanceMeters: 10000, DurationSeconds: 3100, AchievedAt: jan15},
+	} {
+		if _, err := db.UpsertPersonalRecord(pr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stale, err := db.StaleRecordCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Fatalf("expected no stale categories, got %v", stale)
+	}
+
+	// Excluding run 2 leaves its 5K record pointing at a run that no
+	// longer counts; dropping run 1's 10K record leaves its history behind
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.VacatePersonalRecordsForActivity(1); err != nil {
+		t.Fatal(err)
+	}
+	stale, err = db.StaleRecordCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 2 || stale[0] != "distance_10k" || stale[1] != "distance_5k" {
+		t.Fatalf("expected distance_10k and distance_5k, got %v", stale)
+	}
+
+	if err := db.ClearRecords(stale); err != nil {
+		t.Fatal(err)
+	}
+	for _, category := range stale {
+		if _, err := db.GetPersonalRecordByCategory(category); !errors.Is(err, ErrPersonalRecordNotFound) {
+			t.Errorf("%s record still there after clearing: %v", category, err)
+		}
+		if entries, _ := db.GetPRHistory(category); len(entries) != 0 {
+			t.Errorf("%s history still has %d entries", category, len(entries))
+		}
+	}
+	if stale, _ := db.StaleRecordCategories(); len(stale) != 0 {
+		t.Errorf("expected nothing stale after clearing, got %v", stale)
+	}
+}
@@ -0,0 +1,233 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// GetPRHistory returns every improvement of a personal record, oldest first
+func (s *Store) GetPRHistory(category string) ([]PRHistoryEntry, error) {
+	rows, err := s.queries.GetPRHistory(context.Background(), category)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]PRHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		e, err := prHistoryRowToEntry(row)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// recordPRHistory adds pr to its category's progression when it beats every
+// earlier entry. Later entries it matches or beats are no longer improvements
+// and are removed. Sync visits activities newest first, so performances can
+// arrive in any order and the history still ends up chronological.
+func (s *Store) recordPRHistory(pr *PersonalRecord, mode CompareMode) error {
+	ctx := context.Background()
+	entries, err := s.GetPRHistory(pr.Category)
+	if err != nil {
+		return err
+	}
+
+	candidate := PRHistoryEntry{
+		Category:        pr.Category,
+		ActivityID:      pr.ActivityID,
+		DistanceMeters:  pr.DistanceMeters,
+		DurationSeconds: pr.DurationSeconds,
+		PacePerMile:     pr.PacePerMile,
+		AchievedAt:      pr.AchievedAt,
+		Mode:            mode,
+	}
+
+	changed := false
+	var others []PRHistoryEntry
+	for _, e := range entries {
+		if e.ActivityID != pr.ActivityID {
+			others = append(others, e)
+			continue
+		}
+		if e.sameResult(candidate) {
+			return nil
+		}
+		// The activity was analyzed again with a different result
+		if err := s.queries.DeletePRHistory(ctx, e.ID); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	improves := true
+	for _, e := range others {
+		if !e.AchievedAt.After(pr.AchievedAt) && !candidate.beats(e) {
+			improves = false
+			break
+		}
+	}
+
+	if improves {
+		err := s.queries.InsertPRHistory(ctx, sqlc.InsertPRHistoryParams{
+			Category:        pr.Category,
+			ActivityID:      pr.ActivityID,
+			DistanceMeters:  pr.DistanceMeters,
+			DurationSeconds: int64(pr.DurationSeconds),
+			PacePerMile:     ptrToNullFloat64(pr.PacePerMile),
+			AchievedAt:      pr.AchievedAt.Format(time.RFC3339),
+			CompareMode:     int64(mode),
+		})
+		if err != nil {
+			return err
+		}
+		for _, e := range others {
+			if e.AchievedAt.After(pr.AchievedAt) && !e.beats(candidate) {
+				if err := s.queries.DeletePRHistory(ctx, e.ID); err != nil {
+					return err
+				}
+			}
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.refreshPRHistoryMargins(pr.Category)
+}
+
+// StaleRecordCategories returns the categories whose record or history no
+// longer stands: a record or history entry from a run that was deleted or
+// excluded, or a history left without a record. Each needs to be recomputed
+// from the remaining runs.
+func (s *Store) StaleRecordCategories() ([]string, error) {
+	return s.queries.ListStaleRecordCategories(context.Background())
+}
+
+// ClearRecords removes the record and the whole PR history of each category,
+// so they can be rebuilt from scratch
+func (s *Store) ClearRecords(categories []string) error {
+	ctx := context.Background()
+	for _, category := range categories {
+		if err := s.queries.DeletePersonalRecord(ctx, category); err != nil {
+			return fmt.Errorf("clearing %s record: %w", category, err)
+		}
+		if err := s.queries.DeletePRHistoryForCategory(ctx, category); err != nil {
+			return fmt.Errorf("clearing %s history: %w", category, err)
+		}
+	}
+	return nil
+}
+
+// deletePRHistoryForActivity removes an activity's history entries and fixes
+// the margins of the entries that followed them
+func (s *Store) deletePRHistoryForActivity(activityID int64) error {
+	ctx := context.Background()
+	categories, err := s.queries.GetPRHistoryCategoriesForActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if err := s.queries.DeletePRHistoryForActivity(ctx, activityID); err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if err := s.refreshPRHistoryMargins(category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshPRHistoryMargins recomputes each entry's margin over the entry
+// before it, updating only the rows that changed
+func (s *Store) refreshPRHistoryMargins(category string) error {
+	entries, err := s.GetPRHistory(category)
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		var margin *float64
+		if i > 0 {
+			m := e.marginOver(entries[i-1])
+			margin = &m
+		}
+		if floatPtrsEqual(margin, e.Margin) {
+			continue
+		}
+		err := s.queries.UpdatePRHistoryMargin(context.Background(), sqlc.UpdatePRHistoryMarginParams{
+			Margin: ptrToNullFloat64(margin),
+			ID:     e.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// beats reports whether e is strictly better than other under e's mode
+func (e PRHistoryEntry) beats(other PRHistoryEntry) bool {
+	switch e.Mode {
+	case CompareDistance:
+		return e.DistanceMeters > other.DistanceMeters
+	case ComparePace:
+		if e.PacePerMile == nil {
+			return false
+		}
+		return other.PacePerMile == nil || *e.PacePerMile < *other.PacePerMile
+	default:
+		return e.DurationSeconds < other.DurationSeconds
+	}
+}
+
+// marginOver returns how much e improved on prev, positive when better
+func (e PRHistoryEntry) marginOver(prev PRHistoryEntry) float64 {
+	switch e.Mode {
+	case CompareDistance:
+		return e.DistanceMeters - prev.DistanceMeters
+	case ComparePace:
+		if e.PacePerMile == nil || prev.PacePerMile == nil {
+			return 0
+		}
+		return *prev.PacePerMile - *e.PacePerMile
+	default:
+		return float64(prev.DurationSeconds - e.DurationSeconds)
+	}
+}
+
+// sameResult reports whether two entries record the same performance
+func (e PRHistoryEntry) sameResult(other PRHistoryEntry) bool {
+	return e.DistanceMeters == other.DistanceMeters &&
+		e.DurationSeconds == other.DurationSeconds &&
+		floatPtrsEqual(e.PacePerMile, other.PacePerMile) &&
+		e.AchievedAt.Equal(other.AchievedAt)
+}
+
+func floatPtrsEqual(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func prHistoryRowToEntry(row sqlc.PrHistory) (PRHistoryEntry, error) {
+	achievedAt, err := time.Parse(time.RFC3339, row.AchievedAt)
+	if err != nil {
+		return PRHistoryEntry{}, fmt.Errorf("parsing achieved_at %q: %w", row.AchievedAt, err)
+	}
+	return PRHistoryEntry{
+		ID:              row.ID,
+		Category:        row.Category,
+		ActivityID:      row.ActivityID,
+		DistanceMeters:  row.DistanceMeters,
+		DurationSeconds: int(row.DurationSeconds),
+		PacePerMile:     nullFloat64ToPtr(row.PacePerMile),
+		AchievedAt:      achievedAt,
+		Mode:            CompareMode(row.CompareMode),
+		Margin:          nullFloat64ToPtr(row.Margin),
+	}, nil
+}
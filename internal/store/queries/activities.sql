@@ -23,13 +23,20 @@ ON CONFLICT(id) DO UPDATE SET
     average_cadence = excluded.average_cadence,
     suffer_score = excluded.suffer_score,
     has_heartrate = excluded.has_heartrate,
+    efforts_computed = CASE
+        WHEN activities.start_date = excluded.start_date
+            AND activities.distance = excluded.distance
+            AND activities.moving_time = excluded.moving_time
+            AND activities.elapsed_time = excluded.elapsed_time
+            AND COALESCE(activities.total_elevation_gain, 0) = COALESCE(excluded.total_elevation_gain, 0)
+        THEN activities.efforts_computed ELSE 0 END,
     updated_at = CURRENT_TIMESTAMP;
 
 -- name: GetActivity :one
 SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
     distance, moving_time, elapsed_time, total_elevation_gain,
     average_speed, max_speed, average_heartrate, max_heartrate,
-    average_cadence, suffer_score, has_heartrate, streams_synced
+    average_cadence, suffer_score, has_heartrate, streams_synced, manual, excluded
 FROM activities
 WHERE id = ?;
 
@@ -37,7 +44,7 @@ WHERE id = ?;
 SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
     distance, moving_time, elapsed_time, total_elevation_gain,
     average_speed, max_speed, average_heartrate, max_heartrate,
-    average_cadence, suffer_score, has_heartrate, streams_synced
+    average_cadence, suffer_score, has_heartrate, streams_synced, manual, excluded
 FROM activities
 ORDER BY start_date DESC
 LIMIT ? OFFSET ?;
@@ -48,13 +55,17 @@ SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
     average_speed, max_speed, average_heartrate, max_heartrate,
     average_cadence, suffer_score, has_heartrate, streams_synced
 FROM activities
-WHERE streams_synced = 0 AND has_heartrate = 1
+WHERE streams_synced = 0 AND has_heartrate = 1 AND manual = 0
 ORDER BY start_date DESC
 LIMIT ?;
 
+-- name: CountActivitiesNeedingStreams :one
+SELECT COUNT(*) FROM activities
+WHERE streams_synced = 0 AND has_heartrate = 1 AND manual = 0;
+
 -- name: MarkStreamsSynced :execresult
 UPDATE activities
-SET streams_synced = 1, updated_at = CURRENT_TIMESTAMP
+SET streams_synced = 1, efforts_computed = 0, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?;
 
 -- name: CountActivities :one
@@ -69,3 +80,45 @@ FROM activities a
 WHERE a.streams_synced = 1
 AND NOT EXISTS (SELECT 1 FROM activity_metrics m WHERE m.activity_id = a.id)
 ORDER BY a.start_date DESC;
+
+-- name: NextManualActivityID :one
+SELECT CAST(COALESCE(MIN(id), 0) - 1 AS INTEGER) AS id FROM activities WHERE id < 0;
+
+-- name: InsertManualActivity :exec
+INSERT INTO activities (
+    id, athlete_id, name, type, start_date, start_date_local, timezone,
+    distance, moving_time, elapsed_time, average_speed, average_heartrate,
+    has_heartrate, streams_synced, manual, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, 1, CURRENT_TIMESTAMP);
+
+-- name: SetActivityExcluded :execresult
+UPDATE activities
+SET excluded = ?, efforts_computed = 0, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: ListRunDays :many
+SELECT DISTINCT CAST(date(CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN start_date ELSE start_date_local END) AS TEXT) AS day
+FROM activities
+WHERE excluded = 0
+ORDER BY day;
+
+-- name: DeleteActivity :execresult
+DELETE FROM activities WHERE id = ?;
+
+-- name: GetActivitiesNeedingEfforts :many
+SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
+    distance, moving_time, elapsed_time, total_elevation_gain,
+    average_speed, max_speed, average_heartrate, max_heartrate,
+    average_cadence, suffer_score, has_heartrate, streams_synced, manual, excluded
+FROM activities
+WHERE streams_synced = 1 AND excluded = 0 AND efforts_computed = 0
+ORDER BY start_date DESC;
+
+-- name: MarkEffortsComputed :exec
+UPDATE activities SET efforts_computed = 1 WHERE id = ?;
+
+-- name: ClearEffortsComputed :exec
+UPDATE activities SET efforts_computed = 0 WHERE id = ?;
+
+-- name: ResetEffortsComputed :exec
+UPDATE activities SET efforts_computed = 0;
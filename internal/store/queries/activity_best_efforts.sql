@@ -0,0 +1,17 @@
+-- name: DeleteActivityBestEfforts :exec
+DELETE FROM activity_best_efforts WHERE activity_id = ?;
+
+-- name: InsertActivityBestEffort :exec
+INSERT INTO activity_best_efforts (
+    activity_id, category, distance_meters, duration_seconds, start_offset,
+    end_offset, avg_heartrate, unverified
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListTopEfforts :many
+SELECT e.activity_id, e.category, e.distance_meters, e.duration_seconds, e.start_offset,
+    e.end_offset, e.avg_heartrate, e.unverified, a.name, a.start_date_local
+FROM activity_best_efforts e
+JOIN activities a ON a.id = e.activity_id
+WHERE e.category = ? AND a.excluded = 0 AND e.duration_seconds > 0
+ORDER BY e.duration_seconds, a.start_date_local
+LIMIT ?;
@@ -0,0 +1,28 @@
+-- name: CountActivitiesNeedingDetails :one
+SELECT COUNT(*) FROM activities a
+LEFT JOIN activity_details d ON d.activity_id = a.id
+WHERE a.streams_synced = 1 AND a.manual = 0 AND d.activity_id IS NULL;
+
+-- name: GetActivitiesNeedingDetails :many
+SELECT a.id FROM activities a
+LEFT JOIN activity_details d ON d.activity_id = a.id
+WHERE a.streams_synced = 1 AND a.manual = 0 AND d.activity_id IS NULL
+ORDER BY a.start_date DESC
+LIMIT ?;
+
+-- name: GetActivityDetails :one
+SELECT activity_id, description, device_name, calories, gear_id, gear_name, fetched_at
+FROM activity_details
+WHERE activity_id = ?;
+
+-- name: UpsertActivityDetails :exec
+INSERT INTO activity_details (
+    activity_id, description, device_name, calories, gear_id, gear_name, fetched_at
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    description = excluded.description,
+    device_name = excluded.device_name,
+    calories = excluded.calories,
+    gear_id = excluded.gear_id,
+    gear_name = excluded.gear_name,
+    fetched_at = excluded.fetched_at;
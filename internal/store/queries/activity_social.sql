@@ -0,0 +1,27 @@
+-- name: ClearActivityPhotoURLs :exec
+UPDATE activity_social SET photo_url = NULL WHERE photo_url IS NOT NULL;
+
+-- name: DeleteAllActivitySocial :exec
+DELETE FROM activity_social;
+
+-- name: GetActivitySocial :one
+SELECT activity_id, kudos_count, comment_count, photo_count, photo_url, updated_at
+FROM activity_social
+WHERE activity_id = ?;
+
+-- name: ListActivitySocial :many
+SELECT activity_id, kudos_count, comment_count, photo_count, photo_url, updated_at
+FROM activity_social;
+
+-- name: UpsertActivitySocial :exec
+-- A NULL photo_url keeps the one stored, since only the detailed activity
+-- has it
+INSERT INTO activity_social (
+    activity_id, kudos_count, comment_count, photo_count, photo_url, updated_at
+) VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET
+    kudos_count = excluded.kudos_count,
+    comment_count = excluded.comment_count,
+    photo_count = excluded.photo_count,
+    photo_url = COALESCE(excluded.photo_url, activity_social.photo_url),
+    updated_at = CURRENT_TIMESTAMP;
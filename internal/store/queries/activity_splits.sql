@@ -0,0 +1,24 @@
+-- name: DeleteActivitySplits :exec
+DELETE FROM activity_splits WHERE activity_id = ?;
+
+-- name: InsertActivitySplit :exec
+INSERT INTO activity_splits (
+    activity_id, units, split, distance, partial, duration, start_offset,
+    average_heartrate, average_cadence
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListActivitySplits :many
+SELECT activity_id, units, split, distance, partial, duration, start_offset,
+    average_heartrate, average_cadence
+FROM activity_splits
+WHERE activity_id = ? AND units = ?
+ORDER BY split;
+
+-- name: ListFastestSplits :many
+SELECT s.activity_id, s.units, s.split, s.distance, s.partial, s.duration, s.start_offset,
+    s.average_heartrate, s.average_cadence, a.name, a.start_date_local
+FROM activity_splits s
+JOIN activities a ON a.id = s.activity_id
+WHERE s.units = ? AND s.partial = 0 AND a.excluded = 0 AND s.duration > 0
+ORDER BY s.duration, a.start_date_local
+LIMIT ?;
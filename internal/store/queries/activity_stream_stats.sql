@@ -0,0 +1,59 @@
+-- name: SaveActivityStreamStats :exec
+INSERT INTO activity_stream_stats (
+    activity_id, moving_time, moving_distance, hr_sum, hr_count, cadence_sum, cadence_count, max_hr
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    moving_time = excluded.moving_time,
+    moving_distance = excluded.moving_distance,
+    hr_sum = excluded.hr_sum,
+    hr_count = excluded.hr_count,
+    cadence_sum = excluded.cadence_sum,
+    cadence_count = excluded.cadence_count,
+    max_hr = excluded.max_hr;
+
+-- name: ListActivitiesMissingStreamStats :many
+SELECT m.activity_id
+FROM activity_metrics m
+LEFT JOIN activity_stream_stats s ON s.activity_id = m.activity_id
+WHERE s.activity_id IS NULL OR s.max_hr IS NULL
+ORDER BY m.activity_id;
+
+-- name: GetPeriodTotals :one
+SELECT COUNT(*) AS run_count,
+    CAST(COALESCE(SUM(a.distance), 0) AS REAL) AS distance,
+    CAST(COALESCE(SUM(s.moving_time), 0) AS INTEGER) AS moving_time,
+    CAST(COALESCE(SUM(s.moving_distance), 0) AS REAL) AS moving_distance,
+    CAST(COALESCE(SUM(s.hr_sum), 0) AS REAL) AS hr_sum,
+    CAST(COALESCE(SUM(s.hr_count), 0) AS INTEGER) AS hr_count,
+    CAST(COALESCE(SUM(s.cadence_sum), 0) AS REAL) AS cadence_sum,
+    CAST(COALESCE(SUM(s.cadence_count), 0) AS INTEGER) AS cadence_count,
+    CAST(COALESCE(SUM(m.efficiency_factor), 0) AS REAL) AS ef_sum,
+    COUNT(m.efficiency_factor) AS ef_count
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+LEFT JOIN activity_stream_stats s ON s.activity_id = a.id
+WHERE a.excluded = 0
+AND CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END >= sqlc.arg('start_date')
+AND CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END < sqlc.arg('end_date');
+
+-- name: GetMonthlyTotals :many
+-- Months are calendar months on the day-bucketing clock, local or UTC
+SELECT CAST(substr(CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END, 1, 7) AS TEXT) AS month,
+    COUNT(*) AS run_count,
+    CAST(COALESCE(SUM(a.distance), 0) AS REAL) AS distance,
+    CAST(COALESCE(SUM(s.moving_time), 0) AS INTEGER) AS moving_time,
+    CAST(COALESCE(SUM(s.moving_distance), 0) AS REAL) AS moving_distance,
+    CAST(COALESCE(SUM(s.hr_sum), 0) AS REAL) AS hr_sum,
+    CAST(COALESCE(SUM(s.hr_count), 0) AS INTEGER) AS hr_count,
+    CAST(COALESCE(SUM(s.cadence_sum), 0) AS REAL) AS cadence_sum,
+    CAST(COALESCE(SUM(s.cadence_count), 0) AS INTEGER) AS cadence_count,
+    CAST(COALESCE(SUM(m.efficiency_factor), 0) AS REAL) AS ef_sum,
+    COUNT(m.efficiency_factor) AS ef_count
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+LEFT JOIN activity_stream_stats s ON s.activity_id = a.id
+WHERE a.excluded = 0
+AND substr(CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END, 1, 7) >= sqlc.arg('from_month')
+AND substr(CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END, 1, 7) < sqlc.arg('to_month')
+GROUP BY month
+ORDER BY month;
@@ -0,0 +1,61 @@
+-- name: GetActivityTags :many
+SELECT tag FROM activity_tags WHERE activity_id = ? ORDER BY tag;
+
+-- name: ListActivityTags :many
+SELECT activity_id, tag FROM activity_tags ORDER BY activity_id, tag;
+
+-- name: AddActivityTag :exec
+INSERT OR IGNORE INTO activity_tags (activity_id, tag) VALUES (?, ?);
+
+-- name: DeleteActivityTags :exec
+DELETE FROM activity_tags WHERE activity_id = ?;
+
+-- name: GetActivityNote :one
+SELECT note FROM activity_notes WHERE activity_id = ?;
+
+-- name: ListActivityNotes :many
+SELECT activity_id, note FROM activity_notes;
+
+-- name: SetActivityNote :exec
+INSERT INTO activity_notes (activity_id, note, updated_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET
+    note = excluded.note,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteActivityNote :exec
+DELETE FROM activity_notes WHERE activity_id = ?;
+
+-- name: GetActivityTemperature :one
+SELECT temperature_c FROM activity_weather WHERE activity_id = ?;
+
+-- name: ListActivityTemperatures :many
+SELECT activity_id, temperature_c FROM activity_weather;
+
+-- name: SetActivityTemperature :exec
+INSERT INTO activity_weather (activity_id, temperature_c, source, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET
+    temperature_c = excluded.temperature_c,
+    source = excluded.source,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteActivityTemperature :exec
+DELETE FROM activity_weather WHERE activity_id = ?;
+
+-- name: GetActivityRPE :one
+SELECT rpe, feel FROM activity_rpe WHERE activity_id = ?;
+
+-- name: ListActivityRPEs :many
+SELECT activity_id, rpe, feel FROM activity_rpe;
+
+-- name: SetActivityRPE :exec
+INSERT INTO activity_rpe (activity_id, rpe, feel, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(activity_id) DO UPDATE SET
+    rpe = excluded.rpe,
+    feel = excluded.feel,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteActivityRPE :exec
+DELETE FROM activity_rpe WHERE activity_id = ?;
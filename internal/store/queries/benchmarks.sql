@@ -0,0 +1,24 @@
+-- name: ListBenchmarks :many
+SELECT id, name, kind, reference_activity_id
+FROM benchmarks
+ORDER BY name;
+
+-- name: CreateBenchmark :execresult
+INSERT INTO benchmarks (name, kind, reference_activity_id)
+VALUES (?, ?, ?);
+
+-- name: DeleteBenchmark :execresult
+DELETE FROM benchmarks WHERE id = ?;
+
+-- name: DeleteBenchmarkActivities :exec
+DELETE FROM benchmark_activities WHERE benchmark_id = ?;
+
+-- name: ListBenchmarkActivities :many
+SELECT benchmark_id, activity_id, included
+FROM benchmark_activities
+WHERE benchmark_id = ?;
+
+-- name: SetBenchmarkActivity :exec
+INSERT INTO benchmark_activities (benchmark_id, activity_id, included)
+VALUES (?, ?, ?)
+ON CONFLICT(benchmark_id, activity_id) DO UPDATE SET included = excluded.included;
@@ -0,0 +1,12 @@
+-- name: DeleteCustomMetrics :exec
+DELETE FROM custom_metrics WHERE activity_id = ?;
+
+-- name: InsertCustomMetric :exec
+INSERT INTO custom_metrics (activity_id, name, label, value)
+VALUES (?, ?, ?, ?);
+
+-- name: ListCustomMetrics :many
+SELECT activity_id, name, label, value
+FROM custom_metrics
+WHERE activity_id = ?
+ORDER BY name;
@@ -0,0 +1,6 @@
+-- name: InsertDeletedActivity :exec
+INSERT OR REPLACE INTO deleted_activities (id, name, start_date)
+VALUES (?, ?, ?);
+
+-- name: ListDeletedActivityIDs :many
+SELECT id FROM deleted_activities ORDER BY id;
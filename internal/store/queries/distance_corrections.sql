@@ -0,0 +1,16 @@
+-- name: GetDistanceCorrection :one
+SELECT activity_id, method, stride_length, original_distance, corrected_at
+FROM activity_distance_corrections
+WHERE activity_id = ?;
+
+-- name: SetDistanceCorrection :exec
+-- Correcting again keeps the original distance from the first correction
+INSERT INTO activity_distance_corrections (activity_id, method, stride_length, original_distance, corrected_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    method = excluded.method,
+    stride_length = excluded.stride_length,
+    corrected_at = excluded.corrected_at;
+
+-- name: DeleteDistanceCorrection :exec
+DELETE FROM activity_distance_corrections WHERE activity_id = ?;
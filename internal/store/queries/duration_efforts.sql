@@ -0,0 +1,16 @@
+-- name: InsertDurationEffort :exec
+INSERT INTO duration_efforts (activity_id, duration_seconds, distance_meters, start_offset)
+VALUES (?, ?, ?, ?);
+
+-- name: DeleteDurationEfforts :exec
+DELETE FROM duration_efforts WHERE activity_id = ?;
+
+-- name: HasDurationEfforts :one
+SELECT EXISTS(SELECT 1 FROM duration_efforts WHERE activity_id = ?);
+
+-- name: GetDurationEffortsSince :many
+SELECT e.activity_id, e.duration_seconds, e.distance_meters, e.start_offset, a.start_date
+FROM duration_efforts e
+JOIN activities a ON a.id = e.activity_id
+WHERE a.excluded = 0 AND a.start_date >= ?
+ORDER BY e.duration_seconds, e.distance_meters DESC;
@@ -0,0 +1,7 @@
+-- name: GetEffortVerification :one
+SELECT verified_at FROM effort_verifications WHERE activity_id = ?;
+
+-- name: SetEffortVerification :exec
+INSERT INTO effort_verifications (activity_id, verified_at)
+VALUES (?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET verified_at = excluded.verified_at;
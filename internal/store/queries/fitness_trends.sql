@@ -0,0 +1,33 @@
+-- name: ListTrainingLoads :many
+SELECT a.start_date, CAST(CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END AS TEXT) AS bucket_date,
+    a.distance, a.moving_time, m.trimp, m.hrss, r.rpe
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+LEFT JOIN activity_rpe r ON a.id = r.activity_id
+WHERE a.excluded = 0
+ORDER BY a.start_date;
+
+-- name: DeleteFitnessTrends :exec
+DELETE FROM fitness_trends;
+
+-- name: InsertFitnessTrend :exec
+INSERT INTO fitness_trends (
+    date, ctl, atl, tsb, run_count_7d, total_distance_7d, total_time_7d,
+    monotony_7d, strain_7d, computed_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP);
+
+-- name: GetFitnessTrendsSince :many
+SELECT date, ctl, atl, tsb, efficiency_factor_7d, efficiency_factor_28d,
+    efficiency_factor_90d, run_count_7d, total_distance_7d, total_time_7d,
+    monotony_7d, strain_7d
+FROM fitness_trends
+WHERE date >= ?
+ORDER BY date;
+
+-- name: GetLatestFitnessTrend :one
+SELECT date, ctl, atl, tsb, efficiency_factor_7d, efficiency_factor_28d,
+    efficiency_factor_90d, run_count_7d, total_distance_7d, total_time_7d,
+    monotony_7d, strain_7d
+FROM fitness_trends
+ORDER BY date DESC
+LIMIT 1;
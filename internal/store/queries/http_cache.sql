@@ -0,0 +1,17 @@
+-- name: GetHTTPCacheEntry :one
+SELECT url, etag, last_modified, body, stored_at
+FROM http_cache
+WHERE url = ?;
+
+-- name: SaveHTTPCacheEntry :exec
+INSERT INTO http_cache (url, etag, last_modified, body, stored_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(url) DO UPDATE SET
+    etag = excluded.etag,
+    last_modified = excluded.last_modified,
+    body = excluded.body,
+    stored_at = excluded.stored_at;
+
+-- name: PruneHTTPCache :exec
+DELETE FROM http_cache
+WHERE url NOT IN (SELECT url FROM http_cache ORDER BY stored_at DESC LIMIT ?);
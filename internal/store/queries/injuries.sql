@@ -0,0 +1,14 @@
+-- name: ListInjuries :many
+SELECT id, started_on, body_part, severity, notes, resolved_on
+FROM injuries
+ORDER BY started_on DESC, id DESC;
+
+-- name: CreateInjury :execresult
+INSERT INTO injuries (started_on, body_part, severity, notes)
+VALUES (?, ?, ?, ?);
+
+-- name: SetInjuryResolved :execresult
+UPDATE injuries SET resolved_on = ? WHERE id = ?;
+
+-- name: DeleteInjury :execresult
+DELETE FROM injuries WHERE id = ?;
@@ -2,8 +2,8 @@
 INSERT INTO activity_metrics (
     activity_id, efficiency_factor, aerobic_decoupling, cardiac_drift,
     pace_at_z1, pace_at_z2, pace_at_z3, trimp, hrss,
-    data_quality_score, steady_state_pct, computed_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+    data_quality_score, steady_state_pct, anomaly_flags, pacing_split, pace_variability, avg_stride_length, hrr_60, z1_seconds, z2_seconds, z3_seconds, z4_seconds, z5_seconds, computed_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(activity_id) DO UPDATE SET
     efficiency_factor = excluded.efficiency_factor,
     aerobic_decoupling = excluded.aerobic_decoupling,
@@ -13,14 +13,29 @@ ON CONFLICT(activity_id) DO UPDATE SET
     pace_at_z3 = excluded.pace_at_z3,
     trimp = excluded.trimp,
     hrss = excluded.hrss,
+    hrss_scaled = 1,
     data_quality_score = excluded.data_quality_score,
     steady_state_pct = excluded.steady_state_pct,
+    anomaly_flags = excluded.anomaly_flags,
+    pacing_split = excluded.pacing_split,
+    pace_variability = excluded.pace_variability,
+    avg_stride_length = excluded.avg_stride_length,
+    hrr_60 = excluded.hrr_60,
+    z1_seconds = excluded.z1_seconds,
+    z2_seconds = excluded.z2_seconds,
+    z3_seconds = excluded.z3_seconds,
+    z4_seconds = excluded.z4_seconds,
+    z5_seconds = excluded.z5_seconds,
     computed_at = CURRENT_TIMESTAMP;
 
+-- name: RescaleLegacyHRSS :execresult
+UPDATE activity_metrics SET hrss = trimp * CAST(sqlc.arg('per_trimp') AS REAL), hrss_scaled = 1
+WHERE hrss_scaled = 0;
+
 -- name: GetActivityMetrics :one
 SELECT activity_id, efficiency_factor, aerobic_decoupling, cardiac_drift,
     pace_at_z1, pace_at_z2, pace_at_z3, trimp, hrss,
-    data_quality_score, steady_state_pct
+    data_quality_score, steady_state_pct, anomaly_flags, pacing_split, pace_variability, avg_stride_length, hrr_60, z1_seconds, z2_seconds, z3_seconds, z4_seconds, z5_seconds
 FROM activity_metrics
 WHERE activity_id = ?;
 
@@ -30,7 +45,7 @@ SELECT 1 FROM activity_metrics WHERE activity_id = ? LIMIT 1;
 -- name: GetAllMetrics :many
 SELECT m.activity_id, m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
     m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
-    m.data_quality_score, m.steady_state_pct
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
 FROM activity_metrics m
 JOIN activities a ON m.activity_id = a.id
 ORDER BY a.start_date DESC;
@@ -38,15 +53,127 @@ ORDER BY a.start_date DESC;
 -- name: CountMetrics :one
 SELECT COUNT(*) FROM activity_metrics;
 
+-- name: GetActivitiesWithMetricsBetween :many
+SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
+    a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
+    a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
+    m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
+    m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE a.excluded = 0
+AND CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END >= sqlc.arg('start_date')
+AND CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END < sqlc.arg('end_date')
+ORDER BY a.start_date DESC;
+
 -- name: GetActivitiesWithMetricsRaw :many
 SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
     a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
     a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
-    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
     m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
     m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
-    m.data_quality_score, m.steady_state_pct
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
 FROM activities a
 JOIN activity_metrics m ON a.id = m.activity_id
+WHERE a.excluded = 0
 ORDER BY a.start_date DESC
 LIMIT ? OFFSET ?;
+
+-- name: SearchActivitiesWithMetrics :many
+SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
+    a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
+    a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
+    m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
+    m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE (sqlc.narg('name') IS NULL
+    OR instr(lower(a.name), lower(CAST(sqlc.narg('name') AS TEXT))) > 0
+    OR EXISTS (SELECT 1 FROM activity_notes n WHERE n.activity_id = a.id AND instr(lower(n.note), lower(CAST(sqlc.narg('name') AS TEXT))) > 0))
+AND (sqlc.narg('type') IS NULL OR a.type = sqlc.narg('type') COLLATE NOCASE)
+AND (sqlc.narg('start_after') IS NULL OR a.start_date_local >= sqlc.narg('start_after'))
+AND (sqlc.narg('start_before') IS NULL OR a.start_date_local < sqlc.narg('start_before'))
+AND (sqlc.narg('min_distance') IS NULL OR a.distance >= sqlc.narg('min_distance'))
+AND (sqlc.narg('max_distance') IS NULL OR a.distance <= sqlc.narg('max_distance'))
+AND (CAST(sqlc.arg('has_pr') AS INTEGER) = 0 OR EXISTS (SELECT 1 FROM personal_records p WHERE p.activity_id = a.id))
+AND (sqlc.narg('tag') IS NULL OR EXISTS (SELECT 1 FROM activity_tags t WHERE t.activity_id = a.id AND t.tag = sqlc.narg('tag') COLLATE NOCASE))
+ORDER BY
+    CASE WHEN CAST(sqlc.arg('sort_asc') AS INTEGER) = 1 THEN
+        CASE CAST(sqlc.arg('sort_by') AS TEXT)
+            WHEN 'distance' THEN a.distance
+            WHEN 'duration' THEN a.moving_time
+            WHEN 'pace' THEN a.moving_time / a.distance
+            WHEN 'ef' THEN m.efficiency_factor
+            WHEN 'trimp' THEN m.trimp
+            WHEN 'decoupling' THEN m.aerobic_decoupling
+            WHEN 'kudos' THEN (SELECT s.kudos_count FROM activity_social s WHERE s.activity_id = a.id)
+            ELSE a.start_date
+        END
+    END ASC NULLS LAST,
+    CASE WHEN CAST(sqlc.arg('sort_asc') AS INTEGER) = 0 THEN
+        CASE CAST(sqlc.arg('sort_by') AS TEXT)
+            WHEN 'distance' THEN a.distance
+            WHEN 'duration' THEN a.moving_time
+            WHEN 'pace' THEN a.moving_time / a.distance
+            WHEN 'ef' THEN m.efficiency_factor
+            WHEN 'trimp' THEN m.trimp
+            WHEN 'decoupling' THEN m.aerobic_decoupling
+            WHEN 'kudos' THEN (SELECT s.kudos_count FROM activity_social s WHERE s.activity_id = a.id)
+            ELSE a.start_date
+        END
+    END DESC NULLS LAST,
+    a.start_date DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: SearchActivitiesWithMetricsKeyset :many
+-- Pages through a search by start date from a cursor row rather than an
+-- offset, so deep pages cost the same as the first. older walks back in time,
+-- newest first; otherwise forward, oldest first. A NULL cursor starts at the
+-- newest or oldest activity.
+SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
+    a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
+    a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
+    m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
+    m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE (sqlc.narg('name') IS NULL
+    OR instr(lower(a.name), lower(CAST(sqlc.narg('name') AS TEXT))) > 0
+    OR EXISTS (SELECT 1 FROM activity_notes n WHERE n.activity_id = a.id AND instr(lower(n.note), lower(CAST(sqlc.narg('name') AS TEXT))) > 0))
+AND (sqlc.narg('type') IS NULL OR a.type = sqlc.narg('type') COLLATE NOCASE)
+AND (sqlc.narg('start_after') IS NULL OR a.start_date_local >= sqlc.narg('start_after'))
+AND (sqlc.narg('start_before') IS NULL OR a.start_date_local < sqlc.narg('start_before'))
+AND (sqlc.narg('min_distance') IS NULL OR a.distance >= sqlc.narg('min_distance'))
+AND (sqlc.narg('max_distance') IS NULL OR a.distance <= sqlc.narg('max_distance'))
+AND (CAST(sqlc.arg('has_pr') AS INTEGER) = 0 OR EXISTS (SELECT 1 FROM personal_records p WHERE p.activity_id = a.id))
+AND (sqlc.narg('tag') IS NULL OR EXISTS (SELECT 1 FROM activity_tags t WHERE t.activity_id = a.id AND t.tag = sqlc.narg('tag') COLLATE NOCASE))
+AND (sqlc.narg('cursor_date') IS NULL
+    OR (CAST(sqlc.arg('older') AS INTEGER) = 1 AND (a.start_date < sqlc.narg('cursor_date') OR (a.start_date = sqlc.narg('cursor_date') AND a.id < sqlc.arg('cursor_id'))))
+    OR (CAST(sqlc.arg('older') AS INTEGER) = 0 AND (a.start_date > sqlc.narg('cursor_date') OR (a.start_date = sqlc.narg('cursor_date') AND a.id > sqlc.arg('cursor_id')))))
+ORDER BY
+    CASE WHEN CAST(sqlc.arg('older') AS INTEGER) = 1 THEN a.start_date END DESC,
+    CASE WHEN CAST(sqlc.arg('older') AS INTEGER) = 1 THEN a.id END DESC,
+    a.start_date ASC, a.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: CountSearchActivitiesWithMetrics :one
+SELECT COUNT(*)
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE (sqlc.narg('name') IS NULL
+    OR instr(lower(a.name), lower(CAST(sqlc.narg('name') AS TEXT))) > 0
+    OR EXISTS (SELECT 1 FROM activity_notes n WHERE n.activity_id = a.id AND instr(lower(n.note), lower(CAST(sqlc.narg('name') AS TEXT))) > 0))
+AND (sqlc.narg('type') IS NULL OR a.type = sqlc.narg('type') COLLATE NOCASE)
+AND (sqlc.narg('start_after') IS NULL OR a.start_date_local >= sqlc.narg('start_after'))
+AND (sqlc.narg('start_before') IS NULL OR a.start_date_local < sqlc.narg('start_before'))
+AND (sqlc.narg('min_distance') IS NULL OR a.distance >= sqlc.narg('min_distance'))
+AND (sqlc.narg('max_distance') IS NULL OR a.distance <= sqlc.narg('max_distance'))
+AND (CAST(sqlc.arg('has_pr') AS INTEGER) = 0 OR EXISTS (SELECT 1 FROM personal_records p WHERE p.activity_id = a.id))
+AND (sqlc.narg('tag') IS NULL OR EXISTS (SELECT 1 FROM activity_tags t WHERE t.activity_id = a.id AND t.tag = sqlc.narg('tag') COLLATE NOCASE));
@@ -1,8 +1,9 @@
 -- name: InsertPersonalRecord :exec
 INSERT INTO personal_records (
     category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(category) DO UPDATE SET
     activity_id = excluded.activity_id,
     distance_meters = excluded.distance_meters,
@@ -11,26 +12,75 @@ ON CONFLICT(category) DO UPDATE SET
     avg_heartrate = excluded.avg_heartrate,
     achieved_at = excluded.achieved_at,
     start_offset = excluded.start_offset,
-    end_offset = excluded.end_offset;
+    end_offset = excluded.end_offset,
+    unverified = excluded.unverified;
 
 -- name: GetPersonalRecordByCategory :one
 SELECT id, category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
 FROM personal_records
 WHERE category = ?;
 
 -- name: GetAllPersonalRecords :many
 SELECT id, category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
 FROM personal_records
 ORDER BY category;
 
 -- name: GetPersonalRecordsForActivity :many
 SELECT id, category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
 FROM personal_records
 WHERE activity_id = ?
 ORDER BY category;
 
 -- name: DeletePersonalRecordsForActivity :exec
 DELETE FROM personal_records WHERE activity_id = ?;
+
+-- name: VerifyPersonalRecordsForActivity :exec
+UPDATE personal_records SET unverified = 0 WHERE activity_id = ?;
+
+-- name: InsertPRHistory :exec
+INSERT INTO pr_history (
+    category, activity_id, distance_meters, duration_seconds,
+    pace_per_mile, achieved_at, compare_mode, margin
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetPRHistory :many
+SELECT id, category, activity_id, distance_meters, duration_seconds,
+    pace_per_mile, achieved_at, compare_mode, margin
+FROM pr_history
+WHERE category = ?
+ORDER BY achieved_at, id;
+
+-- name: GetPRHistoryCategoriesForActivity :many
+SELECT DISTINCT category FROM pr_history WHERE activity_id = ?;
+
+-- name: UpdatePRHistoryMargin :exec
+UPDATE pr_history SET margin = ? WHERE id = ?;
+
+-- name: DeletePRHistory :exec
+DELETE FROM pr_history WHERE id = ?;
+
+-- name: DeletePRHistoryForActivity :exec
+DELETE FROM pr_history WHERE activity_id = ?;
+
+-- name: DeletePersonalRecord :exec
+DELETE FROM personal_records WHERE category = ?;
+
+-- name: DeletePRHistoryForCategory :exec
+DELETE FROM pr_history WHERE category = ?;
+
+-- name: ListStaleRecordCategories :many
+SELECT category FROM personal_records
+WHERE activity_id NOT IN (SELECT id FROM activities WHERE excluded = 0)
+UNION
+SELECT category FROM pr_history
+WHERE activity_id NOT IN (SELECT id FROM activities WHERE excluded = 0)
+UNION
+SELECT category FROM pr_history
+WHERE category NOT IN (SELECT category FROM personal_records)
+ORDER BY category;
@@ -0,0 +1,16 @@
+-- name: UpsertRace :execrows
+-- Strava's race flag outranks detection; other sources never overwrite
+INSERT INTO races (activity_id, source)
+VALUES (?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET source = excluded.source
+WHERE races.source = 'detected' AND excluded.source <> 'detected';
+
+-- name: ListRaces :many
+SELECT activity_id, source, placing, dismissed FROM races
+WHERE dismissed = 0;
+
+-- name: SetRacePlacing :exec
+UPDATE races SET placing = ? WHERE activity_id = ?;
+
+-- name: DismissRace :exec
+UPDATE races SET dismissed = 1 WHERE activity_id = ?;
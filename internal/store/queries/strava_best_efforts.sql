@@ -0,0 +1,13 @@
+-- name: DeleteStravaBestEfforts :exec
+DELETE FROM strava_best_efforts WHERE activity_id = ?;
+
+-- name: InsertStravaBestEffort :exec
+INSERT INTO strava_best_efforts (
+    activity_id, name, distance, elapsed_time, moving_time, start_index, end_index
+) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListStravaBestEfforts :many
+SELECT activity_id, name, distance, elapsed_time, moving_time, start_index, end_index
+FROM strava_best_efforts
+WHERE activity_id = ?
+ORDER BY distance;
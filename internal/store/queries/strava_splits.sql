@@ -0,0 +1,15 @@
+-- name: DeleteStravaSplits :exec
+DELETE FROM strava_splits WHERE activity_id = ?;
+
+-- name: InsertStravaSplit :exec
+INSERT INTO strava_splits (
+    activity_id, units, split, distance, elapsed_time, moving_time,
+    elevation_difference, average_speed, average_heartrate
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListStravaSplits :many
+SELECT activity_id, units, split, distance, elapsed_time, moving_time,
+    elevation_difference, average_speed, average_heartrate
+FROM strava_splits
+WHERE activity_id = ? AND units = ?
+ORDER BY split;
@@ -17,8 +17,42 @@ ORDER BY time_offset;
 -- name: GetStreamCount :one
 SELECT COUNT(*) FROM streams WHERE activity_id = ?;
 
+-- name: GetStreamPage :many
+SELECT activity_id, time_offset, latlng_lat, latlng_lng, altitude,
+    velocity_smooth, heartrate, cadence, grade_smooth, distance
+FROM streams
+WHERE activity_id = ?
+ORDER BY time_offset
+LIMIT ? OFFSET ?;
+
 -- name: HasStreams :one
 SELECT 1 FROM streams WHERE activity_id = ? LIMIT 1;
 
 -- name: DeleteStreams :exec
 DELETE FROM streams WHERE activity_id = ?;
+
+-- name: GetStreamBlob :one
+SELECT point_count, data FROM stream_blobs WHERE activity_id = ?;
+
+-- name: SaveStreamBlob :exec
+INSERT INTO stream_blobs (activity_id, point_count, data)
+VALUES (?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    point_count = excluded.point_count,
+    data = excluded.data;
+
+-- name: DeleteStreamBlob :exec
+DELETE FROM stream_blobs WHERE activity_id = ?;
+
+-- name: ListRowStreamActivityIDs :many
+SELECT DISTINCT activity_id FROM streams ORDER BY activity_id;
+
+-- name: ListBlobStreamActivityIDs :many
+SELECT activity_id FROM stream_blobs ORDER BY activity_id;
+
+-- name: ListActivitiesWithStreamsBefore :many
+SELECT id FROM activities
+WHERE start_date_local < ?
+AND (EXISTS (SELECT 1 FROM streams s WHERE s.activity_id = activities.id)
+    OR EXISTS (SELECT 1 FROM stream_blobs b WHERE b.activity_id = activities.id))
+ORDER BY id;
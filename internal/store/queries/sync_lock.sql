@@ -0,0 +1,17 @@
+-- name: AcquireSyncLock :execresult
+INSERT INTO sync_lock (id, owner, started_at, heartbeat_at)
+VALUES (1, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+    owner = excluded.owner,
+    started_at = excluded.started_at,
+    heartbeat_at = excluded.heartbeat_at
+WHERE sync_lock.owner = excluded.owner OR sync_lock.heartbeat_at < ?;
+
+-- name: GetSyncLock :one
+SELECT id, owner, started_at, heartbeat_at FROM sync_lock WHERE id = 1;
+
+-- name: RefreshSyncLock :exec
+UPDATE sync_lock SET heartbeat_at = ? WHERE owner = ?;
+
+-- name: ReleaseSyncLock :exec
+DELETE FROM sync_lock WHERE owner = ?;
@@ -0,0 +1,19 @@
+-- name: GetTrainingPlan :one
+SELECT race_date, start_week, base_distance, peak_distance, taper_weeks, cutback_every
+FROM training_plan
+WHERE id = 1;
+
+-- name: SaveTrainingPlan :exec
+INSERT INTO training_plan (id, race_date, start_week, base_distance, peak_distance, taper_weeks, cutback_every, created_at)
+VALUES (1, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(id) DO UPDATE SET
+    race_date = excluded.race_date,
+    start_week = excluded.start_week,
+    base_distance = excluded.base_distance,
+    peak_distance = excluded.peak_distance,
+    taper_weeks = excluded.taper_weeks,
+    cutback_every = excluded.cutback_every,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteTrainingPlan :execresult
+DELETE FROM training_plan WHERE id = 1;
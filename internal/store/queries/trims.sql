@@ -0,0 +1,15 @@
+-- name: GetActivityTrim :one
+SELECT activity_id, end_offset, original_distance, original_elapsed_time, trimmed_at
+FROM activity_trims
+WHERE activity_id = ?;
+
+-- name: SetActivityTrim :exec
+-- Trimming again keeps the original summary from the first trim
+INSERT INTO activity_trims (activity_id, end_offset, original_distance, original_elapsed_time, trimmed_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    end_offset = excluded.end_offset,
+    trimmed_at = excluded.trimmed_at;
+
+-- name: DeleteActivityTrim :exec
+DELETE FROM activity_trims WHERE activity_id = ?;
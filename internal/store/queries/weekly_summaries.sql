@@ -0,0 +1,51 @@
+-- name: SaveWeeklySummary :exec
+INSERT INTO weekly_summaries (
+    week_start, run_count, distance, moving_time, moving_distance,
+    hr_sum, hr_count, cadence_sum, cadence_count, trimp, elevation_gain, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(week_start) DO UPDATE SET
+    run_count = excluded.run_count,
+    distance = excluded.distance,
+    moving_time = excluded.moving_time,
+    moving_distance = excluded.moving_distance,
+    hr_sum = excluded.hr_sum,
+    hr_count = excluded.hr_count,
+    cadence_sum = excluded.cadence_sum,
+    cadence_count = excluded.cadence_count,
+    trimp = excluded.trimp,
+    elevation_gain = excluded.elevation_gain,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetWeeklySummaries :many
+SELECT week_start, run_count, distance, moving_time, moving_distance,
+    hr_sum, hr_count, cadence_sum, cadence_count, trimp, elevation_gain
+FROM weekly_summaries
+WHERE week_start >= sqlc.arg('from_week') AND week_start <= sqlc.arg('to_week')
+ORDER BY week_start;
+
+-- name: DeleteWeeklySummary :exec
+DELETE FROM weekly_summaries WHERE week_start = ?;
+
+-- name: DeleteAllWeeklySummaries :exec
+DELETE FROM weekly_summaries;
+
+-- name: CountWeeklySummaries :one
+SELECT COUNT(*) FROM weekly_summaries;
+
+-- name: GetWeekActivities :many
+SELECT a.id, a.distance, a.total_elevation_gain, m.trimp,
+    s.moving_time, s.moving_distance, s.hr_sum, s.hr_count, s.cadence_sum, s.cadence_count, s.max_hr
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+LEFT JOIN activity_stream_stats s ON s.activity_id = a.id
+WHERE a.excluded = 0
+AND CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END >= sqlc.arg('week_start')
+AND CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END < sqlc.arg('week_end')
+ORDER BY a.start_date;
+
+-- name: ListAnalyzedStartDates :many
+SELECT CAST(CASE WHEN CAST(sqlc.arg('utc_days') AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END AS TEXT) AS start_date
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE a.excluded = 0
+ORDER BY a.start_date;
@@ -0,0 +1,20 @@
+-- name: GetWellness :one
+SELECT date, resting_hr, hrv, sleep_hours, soreness
+FROM wellness
+WHERE date = ?;
+
+-- name: ListWellnessSince :many
+SELECT date, resting_hr, hrv, sleep_hours, soreness
+FROM wellness
+WHERE date >= ?
+ORDER BY date;
+
+-- name: UpsertWellness :exec
+INSERT INTO wellness (date, resting_hr, hrv, sleep_hours, soreness, updated_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(date) DO UPDATE SET
+    resting_hr = excluded.resting_hr,
+    hrv = excluded.hrv,
+    sleep_hours = excluded.sleep_hours,
+    soreness = excluded.soreness,
+    updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+
+	"runner/internal/store/sqlc"
+)
+
+// MarkRace lists an activity as a race and reports whether that changed
+// anything. A Strava race flag replaces a detected one, but a dismissed race
+// stays dismissed and its placing is kept.
+func (s *Store) MarkRace(activityID int64, source RaceSource) (bool, error) {
+	n, err := s.queries.UpsertRace(context.Background(), sqlc.UpsertRaceParams{
+		ActivityID: activityID,
+		Source:     string(source),
+	})
+	return n > 0, err
+}
+
+// GetRaces returns every race that hasn't been dismissed, in no particular
+// order
+func (s *Store) GetRaces() ([]Race, error) {
+	rows, err := s.queries.ListRaces(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	races := make([]Race, len(rows))
+	for i, row := range rows {
+		races[i] = Race{
+			ActivityID: row.ActivityID,
+			Source:     RaceSource(row.Source),
+			Placing:    row.Placing,
+			Dismissed:  row.Dismissed != 0,
+		}
+	}
+	return races, nil
+}
+
+// SetRacePlacing saves the placing notes for a race
+func (s *Store) SetRacePlacing(activityID int64, placing string) error {
+	return s.queries.SetRacePlacing(context.Background(), sqlc.SetRacePlacingParams{
+		Placing:    placing,
+		ActivityID: activityID,
+	})
+}
+
+// DismissRace hides a race from the list. Later syncs don't list it again.
+func (s *Store) DismissRace(activityID int64) error {
+	return s.queries.DismissRace(context.Background(), activityID)
+}
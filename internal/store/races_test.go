@@ -0,0 +1,45 @@
+package store
+
+import "testing"
+
+func TestRaces(t *testing.T) {
+	db := setupTestDB(t)
+
+	added, err := db.MarkRace(1, RaceSourceDetected)
+	if err != nil || !added {
+		t.Fatalf("MarkRace() = %v, %v; want true", added, err)
+	}
+	// Detecting it again changes nothing
+	if added, _ := db.MarkRace(1, RaceSourceDetected); added {
+		t.Error("expected a repeat detection to be a no-op")
+	}
+	if err := db.SetRacePlacing(1, "3rd of 41"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Strava's flag replaces detection but keeps the placing
+	if added, _ := db.MarkRace(1, RaceSourceStrava); !added {
+		t.Error("expected Strava to replace a detected race")
+	}
+	if added, _ := db.MarkRace(1, RaceSourceDetected); added {
+		t.Error("expected detection not to replace a Strava race")
+	}
+
+	races, err := db.GetRaces()
+	if err != nil {
+		t.Fatalf("GetRaces failed: %v", err)
+	}
+	if len(races) != 1 {
+		t.Fatalf("expected 1 race, got %d", len(races))
+	}
+	if races[0].Source != RaceSourceStrava || races[0].Placing != "3rd of 41" {
+		t.Errorf("unexpected race %+v", races[0])
+	}
+
+	if err := db.DismissRace(1); err != nil {
+		t.Fatal(err)
+	}
+	if races, _ := db.GetRaces(); len(races) != 0 {
+		t.Errorf("expected no races after dismissing, got %+v", races)
+	}
+}
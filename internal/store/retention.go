@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// DeleteActivity removes an activity and every row that belongs to it:
+// streams, metrics, splits, notes, records and the rest. The activity is
+// remembered as deleted so later syncs leave it out.
+func (s *Store) DeleteActivity(id int64) error {
+	a, err := s.GetActivity(id)
+	if err != nil {
+		return err
+	}
+
+	// Fix the margins of the PR history entries that followed its records
+	if err := s.DeletePersonalRecordsForActivity(id); err != nil {
+		return fmt.Errorf("clearing records: %w", err)
+	}
+
+	return s.writeTx(func(tx *sql.Tx) error {
+		ctx := context.Background()
+		qtx := s.queries.WithTx(tx)
+		err := qtx.InsertDeletedActivity(ctx, sqlc.InsertDeletedActivityParams{
+			ID:        a.ID,
+			Name:      a.Name,
+			StartDate: a.StartDate.Format(time.RFC3339),
+		})
+		if err != nil {
+			return fmt.Errorf("recording deletion: %w", err)
+		}
+		if _, err := qtx.DeleteActivity(ctx, id); err != nil {
+			return fmt.Errorf("deleting activity: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeletedActivityIDs returns the IDs of the activities deleted locally
+func (s *Store) DeletedActivityIDs() (map[int64]bool, error) {
+	ids, err := s.queries.ListDeletedActivityIDs(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	deleted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	return deleted, nil
+}
+
+// PurgeStreams deletes the streams of activities that started before
+// before, on the local clock, and returns how many activities lost them.
+// The activities stay marked as synced, so their streams aren't downloaded
+// again, and everything computed from the streams is kept.
+func (s *Store) PurgeStreams(before time.Time) (int, error) {
+	ctx := context.Background()
+	ids, err := s.queries.ListActivitiesWithStreamsBefore(ctx, before.Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("listing streams to purge: %w", err)
+	}
+
+	err = s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		for _, id := range ids {
+			if err := qtx.DeleteStreamBlob(ctx, id); err != nil {
+				return fmt.Errorf("purging streams for %d: %w", id, err)
+			}
+			if err := qtx.DeleteStreams(ctx, id); err != nil {
+				return fmt.Errorf("purging streams for %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPurgeStreams(t *testing.T) {
+	db := setupTestDB(t) // Activity 1 on Jan 15, 2024 and 2 on Jan 20
+
+	speed := 3.0
+	for _, id := range []int64{1, 2} {
+		points := []StreamPoint{{ActivityID: id, TimeOffset: 0, VelocitySmooth: &speed}, {ActivityID: id, TimeOffset: 1, VelocitySmooth: &speed}}
+		if err := db.SaveStreams(id, points); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ef := 1.5
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1, EfficiencyFactor: &ef}); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := db.PurgeStreams(time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("PurgeStreams() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeStreams() = %d, want 1", purged)
+	}
+	if has, _ := db.HasStreams(1); has {
+		t.Error("activity 1 kept its streams")
+	}
+	if has, _ := db.HasStreams(2); !has {
+		t.Error("activity 2 lost its streams")
+	}
+	if m, _ := db.GetActivityMetrics(1); m == nil || m.EfficiencyFactor == nil {
+		t.Errorf("activity 1 metrics = %+v, want them kept", m)
+	}
+	if a, _ := db.GetActivity(1); a == nil || !a.StreamsSynced {
+		t.Error("activity 1 is no longer marked synced, so its streams would download again")
+	}
+}
+
+func TestDeleteActivity(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := db.SetActivityTags(2, []string{"race"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteActivity(2); err != nil {
+		t.Fatalf("DeleteActivity() error = %v", err)
+	}
+	if _, err := db.GetActivity(2); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("GetActivity() error = %v, want not found", err)
+	}
+	if tags, _ := db.GetActivityTags(2); len(tags) != 0 {
+		t.Errorf("tags = %v, want them deleted with the activity", tags)
+	}
+	deleted, err := db.DeletedActivityIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || !deleted[2] {
+		t.Errorf("DeletedActivityIDs() = %v, want 2", deleted)
+	}
+
+	if err := db.DeleteActivity(99); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("DeleteActivity(99) error = %v, want not found", err)
+	}
+}
+
+func TestUsageByClass(t *testing.T) {
+	db := setupTestDB(t)
+
+	usage, err := db.UsageByClass()
+	if err != nil {
+		t.Fatalf("UsageByClass() error = %v", err)
+	}
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every table is counted in exactly one class
+	counted := make(map[string]bool)
+	for _, u := range usage {
+		if u.Class == "other" {
+			t.Errorf("tables %v aren't in a data class", u.Tables)
+		}
+		for _, table := range u.Tables {
+			if counted[table] {
+				t.Errorf("table %s counted twice", table)
+			}
+			counted[table] = true
+		}
+	}
+	if len(counted) != len(stats.Tables) {
+		t.Errorf("classes cover %d tables, want %d", len(counted), len(stats.Tables))
+	}
+	for i := 1; i < len(usage); i++ {
+		if usage[i].Bytes > usage[i-1].Bytes {
+			t.Errorf("usage isn't sorted largest first: %s before %s", usage[i-1].Class, usage[i].Class)
+		}
+	}
+}
@@ -9,6 +9,7 @@ CREATE TABLE auth (
     access_token TEXT NOT NULL,
     refresh_token TEXT NOT NULL,
     expires_at INTEGER NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '', -- comma separated as granted; empty if not recorded
     created_at TEXT DEFAULT CURRENT_TIMESTAMP,
     updated_at TEXT DEFAULT CURRENT_TIMESTAMP
 );
@@ -35,7 +36,10 @@ CREATE TABLE activities (
     has_heartrate INTEGER NOT NULL,
     streams_synced INTEGER DEFAULT 0,
     created_at TEXT DEFAULT CURRENT_TIMESTAMP,
-    updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+    updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    manual INTEGER NOT NULL DEFAULT 0, -- entered locally, never synced from Strava
+    excluded INTEGER NOT NULL DEFAULT 0, -- hidden from metrics, trends and PRs
+    efforts_computed INTEGER NOT NULL DEFAULT 0 -- analyzed for PRs since its streams or summary last changed
 );
 
 CREATE INDEX idx_activities_start_date ON activities(start_date);
@@ -60,6 +64,15 @@ CREATE TABLE streams (
 
 CREATE INDEX idx_streams_activity ON streams(activity_id);
 
+-- Stream Blobs (compressed columnar streams, one row per activity)
+CREATE TABLE stream_blobs (
+    activity_id INTEGER PRIMARY KEY,
+    point_count INTEGER NOT NULL,
+    data BLOB NOT NULL,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
 -- Computed Metrics (per activity)
 CREATE TABLE activity_metrics (
     activity_id INTEGER PRIMARY KEY,
@@ -74,6 +87,17 @@ CREATE TABLE activity_metrics (
     data_quality_score REAL,
     steady_state_pct REAL,
     computed_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    anomaly_flags TEXT,
+    pacing_split REAL,
+    pace_variability REAL,
+    avg_stride_length REAL,
+    hrr_60 REAL,
+    z1_seconds INTEGER,
+    z2_seconds INTEGER,
+    z3_seconds INTEGER,
+    z4_seconds INTEGER,
+    z5_seconds INTEGER,
+    hrss_scaled INTEGER NOT NULL DEFAULT 1, -- 0 for HRSS stored when it equaled TRIMP
     FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
 );
 
@@ -89,7 +113,9 @@ CREATE TABLE fitness_trends (
     run_count_7d INTEGER,
     total_distance_7d REAL,
     total_time_7d INTEGER,
-    computed_at TEXT DEFAULT CURRENT_TIMESTAMP
+    computed_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    monotony_7d REAL,
+    strain_7d REAL
 );
 
 -- Sync State (key-value store for sync tracking)
@@ -111,12 +137,49 @@ CREATE TABLE personal_records (
     achieved_at TEXT NOT NULL,
     start_offset INTEGER,
     end_offset INTEGER,
+    unverified INTEGER NOT NULL DEFAULT 0, -- best effort with doubtful GPS, not yet confirmed
     FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
 );
 
 CREATE INDEX idx_personal_records_activity ON personal_records(activity_id);
 CREATE INDEX idx_personal_records_category ON personal_records(category);
 
+-- PR History (every improvement of each personal record)
+CREATE TABLE pr_history (
+    id INTEGER PRIMARY KEY,
+    category TEXT NOT NULL,
+    activity_id INTEGER NOT NULL,
+    distance_meters REAL NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    pace_per_mile REAL,
+    achieved_at TEXT NOT NULL,
+    compare_mode INTEGER NOT NULL,
+    margin REAL,
+    UNIQUE (category, activity_id),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pr_history_activity ON pr_history(activity_id);
+
+-- Duration Efforts (farthest distance in each pace-curve duration)
+CREATE TABLE duration_efforts (
+    activity_id INTEGER NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    distance_meters REAL NOT NULL,
+    start_offset INTEGER NOT NULL,
+    PRIMARY KEY (activity_id, duration_seconds),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Races (flagged on Strava or detected from distance and pace)
+CREATE TABLE races (
+    activity_id INTEGER PRIMARY KEY,
+    source TEXT NOT NULL,               -- 'strava' or 'detected'
+    placing TEXT NOT NULL DEFAULT '',   -- free-form placing notes
+    dismissed INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
 -- Race Predictions (VDOT-based predictions)
 CREATE TABLE race_predictions (
     id INTEGER PRIMARY KEY,
@@ -132,3 +195,286 @@ CREATE TABLE race_predictions (
     computed_at TEXT NOT NULL,
     FOREIGN KEY (source_activity_id) REFERENCES activities(id) ON DELETE CASCADE
 );
+
+-- Activity Tags (local-only labels such as "race" or "new shoes")
+CREATE TABLE activity_tags (
+    activity_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (activity_id, tag),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_activity_tags_tag ON activity_tags(tag);
+
+-- Activity Notes (local-only free text)
+CREATE TABLE activity_notes (
+    activity_id INTEGER PRIMARY KEY,
+    note TEXT NOT NULL,
+    updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Activity Weather (conditions during a run, entered by hand for now)
+CREATE TABLE activity_weather (
+    activity_id INTEGER PRIMARY KEY,
+    temperature_c REAL NOT NULL,
+    source TEXT NOT NULL DEFAULT 'manual',
+    updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Activity RPE (perceived effort, 1-10, and how the run felt, 1-5,
+-- entered by hand)
+CREATE TABLE activity_rpe (
+    activity_id INTEGER PRIMARY KEY,
+    rpe INTEGER NOT NULL CHECK (rpe BETWEEN 1 AND 10),
+    feel INTEGER CHECK (feel BETWEEN 1 AND 5),
+    updated_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Weekly Summaries (per-week totals maintained during sync)
+CREATE TABLE weekly_summaries (
+    week_start TEXT PRIMARY KEY,
+    run_count INTEGER NOT NULL DEFAULT 0,
+    distance REAL NOT NULL DEFAULT 0,
+    moving_time INTEGER NOT NULL DEFAULT 0,
+    moving_distance REAL NOT NULL DEFAULT 0,
+    hr_sum REAL NOT NULL DEFAULT 0,
+    hr_count INTEGER NOT NULL DEFAULT 0,
+    cadence_sum REAL NOT NULL DEFAULT 0,
+    cadence_count INTEGER NOT NULL DEFAULT 0,
+    trimp REAL NOT NULL DEFAULT 0,
+    elevation_gain REAL NOT NULL DEFAULT 0,
+    updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Injuries (local log of injuries and niggles, dates as YYYY-MM-DD)
+CREATE TABLE injuries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_on TEXT NOT NULL,
+    body_part TEXT NOT NULL,
+    severity INTEGER NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    resolved_on TEXT,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Daily wellness (one manual or imported entry per day, date as YYYY-MM-DD)
+CREATE TABLE wellness (
+    date TEXT PRIMARY KEY,
+    resting_hr REAL,
+    hrv REAL,
+    sleep_hours REAL,
+    soreness INTEGER,
+    updated_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Benchmarks (repeated test workouts; runs are matched to the reference
+-- activity by route or duration)
+CREATE TABLE benchmarks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL,                 -- 'route' or 'duration'
+    reference_activity_id INTEGER NOT NULL,
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (reference_activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Benchmark attempts added or removed by hand, overriding matching
+CREATE TABLE benchmark_activities (
+    benchmark_id INTEGER NOT NULL,
+    activity_id INTEGER NOT NULL,
+    included INTEGER NOT NULL,          -- 1 added, 0 removed
+    PRIMARY KEY (benchmark_id, activity_id),
+    FOREIGN KEY (benchmark_id) REFERENCES benchmarks(id) ON DELETE CASCADE,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Activities cut short locally, e.g. when the watch ran on after the run.
+-- Sync keeps the trimmed summary; a resync restores Strava's.
+CREATE TABLE activity_trims (
+    activity_id INTEGER PRIMARY KEY,
+    end_offset INTEGER NOT NULL,        -- last kept second of the streams
+    original_distance REAL NOT NULL,    -- meters, before the first trim
+    original_elapsed_time INTEGER NOT NULL,
+    trimmed_at TEXT NOT NULL,           -- RFC3339
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Treadmill and indoor runs whose distance was corrected locally, by a
+-- calibration factor or from cadence and stride length
+CREATE TABLE activity_distance_corrections (
+    activity_id INTEGER PRIMARY KEY,
+    method TEXT NOT NULL,               -- 'factor' or 'cadence'
+    stride_length REAL,                 -- meters per step, cadence only
+    original_distance REAL NOT NULL,    -- meters, before the first correction
+    corrected_at TEXT NOT NULL,         -- RFC3339
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Activities whose best efforts the athlete confirmed as real, so records
+-- from them aren't marked unverified when recomputed
+CREATE TABLE effort_verifications (
+    activity_id INTEGER PRIMARY KEY,
+    verified_at TEXT NOT NULL,          -- RFC3339
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Stream aggregates per analyzed activity, saved with its metrics, so period
+-- stats are summed in SQL instead of loading streams. Activities without
+-- streams get a row of zeros.
+CREATE TABLE activity_stream_stats (
+    activity_id INTEGER PRIMARY KEY,
+    moving_time INTEGER NOT NULL DEFAULT 0,     -- seconds moving
+    moving_distance REAL NOT NULL DEFAULT 0,    -- meters, from streams
+    hr_sum REAL NOT NULL DEFAULT 0,
+    hr_count INTEGER NOT NULL DEFAULT 0,
+    cadence_sum REAL NOT NULL DEFAULT 0,        -- steps per minute
+    cadence_count INTEGER NOT NULL DEFAULT 0,
+    max_hr INTEGER,                             -- bpm; NULL for rows cached before it was added
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- The process currently syncing (singleton row). A sync refreshes the
+-- heartbeat while it runs; a row whose heartbeat has gone stale is ignored.
+CREATE TABLE sync_lock (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    owner TEXT NOT NULL,                -- hostname:pid
+    started_at TEXT NOT NULL,           -- RFC3339
+    heartbeat_at TEXT NOT NULL          -- RFC3339
+);
+
+-- Fields only the detailed activity endpoint returns, one row per activity
+-- fetched. Strava's splits and best efforts come from the same response.
+CREATE TABLE activity_details (
+    activity_id INTEGER PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    device_name TEXT NOT NULL DEFAULT '',  -- e.g. 'Garmin Forerunner 255'
+    calories REAL NOT NULL DEFAULT 0,      -- kcal
+    gear_id TEXT NOT NULL DEFAULT '',
+    gear_name TEXT NOT NULL DEFAULT '',    -- shoes
+    fetched_at TEXT NOT NULL,              -- RFC3339
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Strava's per-kilometer ('metric') and per-mile ('standard') splits
+CREATE TABLE strava_splits (
+    activity_id INTEGER NOT NULL,
+    units TEXT NOT NULL,                -- 'metric' or 'standard'
+    split INTEGER NOT NULL,             -- starting at 1
+    distance REAL NOT NULL,             -- meters
+    elapsed_time INTEGER NOT NULL,      -- seconds
+    moving_time INTEGER NOT NULL,       -- seconds
+    elevation_difference REAL NOT NULL DEFAULT 0, -- meters
+    average_speed REAL NOT NULL,        -- m/s
+    average_heartrate REAL,             -- bpm
+    PRIMARY KEY (activity_id, units, split),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Strava's own best efforts per activity, from the detailed activity
+-- endpoint. Indexes point into the activity's streams.
+CREATE TABLE strava_best_efforts (
+    activity_id INTEGER NOT NULL,
+    name TEXT NOT NULL,                 -- e.g. '5k', '1 mile'
+    distance REAL NOT NULL,             -- meters
+    elapsed_time INTEGER NOT NULL,      -- seconds
+    moving_time INTEGER NOT NULL,       -- seconds
+    start_index INTEGER NOT NULL,
+    end_index INTEGER NOT NULL,
+    PRIMARY KEY (activity_id, name),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Kudos, comment and photo counts per run, kept only when
+-- privacy.sync_social is on
+CREATE TABLE activity_social (
+    activity_id INTEGER PRIMARY KEY,
+    kudos_count INTEGER NOT NULL DEFAULT 0,
+    comment_count INTEGER NOT NULL DEFAULT 0,
+    photo_count INTEGER NOT NULL DEFAULT 0,
+    photo_url TEXT,                     -- primary photo, with privacy.sync_photos
+    updated_at TEXT NOT NULL,
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Per-run values of the custom metrics: expressions from
+-- analysis.custom_metrics and metrics registered in Go code
+CREATE TABLE custom_metrics (
+    activity_id INTEGER NOT NULL,
+    name TEXT NOT NULL,                 -- e.g. 'hard_minutes'
+    label TEXT NOT NULL,                -- shown on the activity detail screen
+    value REAL NOT NULL,
+    PRIMARY KEY (activity_id, name),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+-- Strava responses kept with their ETag and Last-Modified, so a page fetched
+-- before is revalidated instead of downloaded again. Capped to the most
+-- recently stored entries.
+CREATE TABLE http_cache (
+    url TEXT PRIMARY KEY,               -- full request URL with its query
+    etag TEXT NOT NULL DEFAULT '',
+    last_modified TEXT NOT NULL DEFAULT '',
+    body BLOB NOT NULL,
+    stored_at TEXT NOT NULL             -- RFC 3339
+);
+
+-- Mile ('standard') and kilometer ('metric') splits computed from streams in
+-- the metrics phase, so the detail screen and split queries don't scan
+-- streams
+CREATE TABLE activity_splits (
+    activity_id INTEGER NOT NULL,
+    units TEXT NOT NULL,                -- 'metric' or 'standard'
+    split INTEGER NOT NULL,             -- starting at 1
+    distance REAL NOT NULL,             -- meters; a full unit unless partial
+    partial INTEGER NOT NULL DEFAULT 0, -- 1 for a shorter final split
+    duration INTEGER NOT NULL,          -- seconds
+    start_offset INTEGER NOT NULL,      -- seconds into the streams
+    average_heartrate REAL,             -- bpm
+    average_cadence REAL,               -- steps per minute
+    PRIMARY KEY (activity_id, units, split),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_activity_splits_duration ON activity_splits(units, duration);
+
+-- Each run's fastest stretch over every best-effort distance, saved by the
+-- personal records phase so efforts can be ranked across runs, not just the
+-- record holder
+CREATE TABLE activity_best_efforts (
+    activity_id INTEGER NOT NULL,
+    category TEXT NOT NULL,             -- 'effort_1k', 'effort_1mi', ...
+    distance_meters REAL NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    start_offset INTEGER NOT NULL,      -- seconds into the streams
+    end_offset INTEGER NOT NULL,
+    avg_heartrate REAL,                 -- bpm
+    unverified INTEGER NOT NULL DEFAULT 0, -- 1 when the GPS is doubtful
+    PRIMARY KEY (activity_id, category),
+    FOREIGN KEY (activity_id) REFERENCES activities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_activity_best_efforts_duration ON activity_best_efforts(category, duration_seconds);
+
+-- Mileage plan toward a race. There is one at a time; saving a new plan
+-- replaces it.
+CREATE TABLE training_plan (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    race_date TEXT NOT NULL,            -- YYYY-MM-DD
+    start_week TEXT NOT NULL,           -- first day of the plan's first week
+    base_distance REAL NOT NULL,        -- meters a week the build starts from
+    peak_distance REAL NOT NULL,        -- meters a week at the top of the build
+    taper_weeks INTEGER NOT NULL,       -- race week included
+    cutback_every INTEGER NOT NULL,     -- every Nth build week eases off; 0 for none
+    created_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Activities deleted locally. Sync skips them so they stay deleted.
+CREATE TABLE deleted_activities (
+    id INTEGER PRIMARY KEY,             -- Strava activity ID
+    name TEXT NOT NULL,
+    start_date TEXT NOT NULL,
+    deleted_at TEXT DEFAULT CURRENT_TIMESTAMP
+);
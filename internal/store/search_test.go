@@ -0,0 +1,244 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchActivitiesWithMetrics(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	for _, id := range []int64{1, 2} {
+		if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: id}); err != nil {
+			t.Fatalf("SaveActivityMetrics(%d) error = %v", id, err)
+		}
+	}
+	if _, err := db.UpsertPersonalRecord(&PersonalRecord{
+		Category:        "distance_10k",
+		ActivityID:      2,
+		DistanceMeters:  10000,
+		DurationSeconds: 3000,
+		AchievedAt:      time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("UpsertPersonalRecord() error = %v", err)
+	}
+	if err := db.SetActivityTags(1, []string{"treadmill"}); err != nil {
+		t.Fatalf("SetActivityTags() error = %v", err)
+	}
+	if err := db.SetActivityNote(2, "New shoes felt great"); err != nil {
+		t.Fatalf("SetActivityNote() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filter  ActivityFilter
+		wantIDs []int64
+	}{
+		{"empty filter matches all", ActivityFilter{}, []int64{2, 1}},
+		{"name substring is case-insensitive", ActivityFilter{Name: "another"}, []int64{2}},
+		{"name with no match", ActivityFilter{Name: "tempo"}, []int64{}},
+		{"type is case-insensitive", ActivityFilter{Type: "run"}, []int64{2, 1}},
+		{"type with no match", ActivityFilter{Type: "Ride"}, []int64{}},
+		{"after", ActivityFilter{After: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)}, []int64{2}},
+		{"before", ActivityFilter{Before: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)}, []int64{1}},
+		{"min distance", ActivityFilter{MinDistance: 6000}, []int64{2}},
+		{"max distance", ActivityFilter{MaxDistance: 6000}, []int64{1}},
+		{"has PR", ActivityFilter{HasPR: true}, []int64{2}},
+		{"text matches note", ActivityFilter{Name: "new shoes"}, []int64{2}},
+		{"tag is case-insensitive", ActivityFilter{Tag: "Treadmill"}, []int64{1}},
+		{"tag with no match", ActivityFilter{Tag: "race"}, []int64{}},
+		{"combined", ActivityFilter{Name: "run", MaxDistance: 6000, HasPR: true}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activities, metrics, err := db.SearchActivitiesWithMetrics(tt.filter, ActivitySort{}, 10, 0)
+			if err != nil {
+				t.Fatalf("SearchActivitiesWithMetrics() error = %v", err)
+			}
+			if len(activities) != len(metrics) {
+				t.Fatalf("got %d activities but %d metrics", len(activities), len(metrics))
+			}
+			if len(activities) != len(tt.wantIDs) {
+				t.Fatalf("got %d activities, want %d", len(activities), len(tt.wantIDs))
+			}
+			for i, want := range tt.wantIDs {
+				if activities[i].ID != want {
+					t.Errorf("activities[%d].ID = %d, want %d", i, activities[i].ID, want)
+				}
+			}
+
+			count, err := db.CountSearchActivitiesWithMetrics(tt.filter)
+			if err != nil {
+				t.Fatalf("CountSearchActivitiesWithMetrics() error = %v", err)
+			}
+			if count != len(tt.wantIDs) {
+				t.Errorf("CountSearchActivitiesWithMetrics() = %d, want %d", count, len(tt.wantIDs))
+			}
+		})
+	}
+}
+
+func TestSearchActivitiesWithMetrics_Sort(t *testing.T) {
+	db := setupTestDB(t) // Activity 1: 5 km on Jan 15, activity 2: 10 km on Jan 20
+
+	ef := 1.5
+	trimp1, trimp2 := 80.0, 40.0
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1, EfficiencyFactor: &ef, TRIMP: &trimp1}); err != nil {
+		t.Fatalf("SaveActivityMetrics(1) error = %v", err)
+	}
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 2, TRIMP: &trimp2}); err != nil {
+		t.Fatalf("SaveActivityMetrics(2) error = %v", err)
+	}
+	if err := db.SaveActivitySocial(ActivitySocial{ActivityID: 1, KudosCount: 12}); err != nil {
+		t.Fatalf("SaveActivitySocial(1) error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		order   ActivitySort
+		wantIDs []int64
+	}{
+		{"default is newest first", ActivitySort{}, []int64{2, 1}},
+		{"date ascending", ActivitySort{Field: SortByDate, Ascending: true}, []int64{1, 2}},
+		{"distance descending", ActivitySort{Field: SortByDistance}, []int64{2, 1}},
+		{"distance ascending", ActivitySort{Field: SortByDistance, Ascending: true}, []int64{1, 2}},
+		{"duration ascending", ActivitySort{Field: SortByDuration, Ascending: true}, []int64{1, 2}},
+		{"trimp descending", ActivitySort{Field: SortByTRIMP}, []int64{1, 2}},
+		{"trimp ascending", ActivitySort{Field: SortByTRIMP, Ascending: true}, []int64{2, 1}},
+		{"missing EF sorts last descending", ActivitySort{Field: SortByEF}, []int64{1, 2}},
+		{"missing EF sorts last ascending", ActivitySort{Field: SortByEF, Ascending: true}, []int64{1, 2}},
+		{"equal pace falls back to newest first", ActivitySort{Field: SortByPace, Ascending: true}, []int64{2, 1}},
+		{"missing kudos sort last", ActivitySort{Field: SortByKudos, Ascending: true}, []int64{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activities, _, err := db.SearchActivitiesWithMetrics(ActivityFilter{}, tt.order, 10, 0)
+			if err != nil {
+				t.Fatalf("SearchActivitiesWithMetrics() error = %v", err)
+			}
+			if len(activities) != len(tt.wantIDs) {
+				t.Fatalf("got %d activities, want %d", len(activities), len(tt.wantIDs))
+			}
+			for i, want := range tt.wantIDs {
+				if activities[i].ID != want {
+					t.Errorf("activities[%d].ID = %d, want %d", i, activities[i].ID, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchActivitiesByDate(t *testing.T) {
+	db := setupTestDB(t) // Activity 1 on Jan 15, activity 2 on Jan 20
+
+	// Activity 3 starts at the same moment as 2, so ties fall back to ID
+	if _, err := db.DB().Exec(`
+		INSERT INTO activities (id, athlete_id, name, type, start_date, start_date_local,
+			distance, moving_time, elapsed_time, has_heartrate, streams_synced)
+		VALUES (3, 123, 'Double', 'Run', '2024-01-20T10:00:00Z', '2024-01-20T10:00:00Z',
+			3000, 900, 900, 1, 1)
+	`); err != nil {
+		t.Fatalf("inserting activity 3: %v", err)
+	}
+	for _, id := range []int64{1, 2, 3} {
+		if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: id}); err != nil {
+			t.Fatalf("SaveActivityMetrics(%d) error = %v", id, err)
+		}
+	}
+	jan20 := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		filter  ActivityFilter
+		cursor  *ActivityCursor
+		older   bool
+		limit   int
+		wantIDs []int64
+	}{
+		{"newest first", ActivityFilter{}, nil, true, 10, []int64{3, 2, 1}},
+		{"oldest first", ActivityFilter{}, nil, false, 10, []int64{1, 2, 3}},
+		{"limit", ActivityFilter{}, nil, true, 2, []int64{3, 2}},
+		{"older than a tie", ActivityFilter{}, &ActivityCursor{StartDate: jan20, ID: 3}, true, 10, []int64{2, 1}},
+		{"newer than a tie", ActivityFilter{}, &ActivityCursor{StartDate: jan20, ID: 2}, false, 10, []int64{3}},
+		{"older than the oldest", ActivityFilter{}, &ActivityCursor{StartDate: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), ID: 1}, true, 10, []int64{}},
+		{"filtered", ActivityFilter{MinDistance: 4000}, &ActivityCursor{StartDate: jan20, ID: 3}, true, 10, []int64{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activities, metrics, err := db.SearchActivitiesByDate(tt.filter, tt.cursor, tt.older, tt.limit)
+			if err != nil {
+				t.Fatalf("SearchActivitiesByDate() error = %v", err)
+			}
+			if len(activities) != len(metrics) {
+				t.Fatalf("got %d activities but %d metrics", len(activities), len(metrics))
+			}
+			if len(activities) != len(tt.wantIDs) {
+				t.Fatalf("got %d activities, want %d", len(activities), len(tt.wantIDs))
+			}
+			for i, want := range tt.wantIDs {
+				if activities[i].ID != want {
+					t.Errorf("activities[%d].ID = %d, want %d", i, activities[i].ID, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetActivitiesWithMetricsBetween(t *testing.T) {
+	db := setupTestDB(t) // Activity 1 on Jan 15, activity 2 on Jan 20, both 10:00 UTC
+
+	for _, id := range []int64{1, 2} {
+		if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: id}); err != nil {
+			t.Fatalf("SaveActivityMetrics(%d) error = %v", id, err)
+		}
+	}
+	est := time.FixedZone("EST", -5*3600)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantIDs    []int64
+	}{
+		{"both, newest first", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), []int64{2, 1}},
+		{"start is inclusive", time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), []int64{2}},
+		{"end is exclusive", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC), []int64{1}},
+		{"end within the start's second", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 20, 10, 0, 0, 5e8, time.UTC), []int64{2}},
+		{"start within the start's second", time.Date(2024, 1, 20, 10, 0, 0, 5e8, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), []int64{}},
+		{"other time zones compare as UTC", time.Date(2024, 1, 20, 5, 0, 0, 0, est), time.Date(2024, 1, 20, 6, 0, 0, 0, est), []int64{2}},
+		{"none", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activities, metrics, err := db.GetActivitiesWithMetricsBetween(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("GetActivitiesWithMetricsBetween() error = %v", err)
+			}
+			if len(activities) != len(metrics) {
+				t.Fatalf("got %d activities but %d metrics", len(activities), len(metrics))
+			}
+			if len(activities) != len(tt.wantIDs) {
+				t.Fatalf("got %d activities, want %d", len(activities), len(tt.wantIDs))
+			}
+			for i, want := range tt.wantIDs {
+				if activities[i].ID != want {
+					t.Errorf("activities[%d].ID = %d, want %d", i, activities[i].ID, want)
+				}
+			}
+		})
+	}
+
+	if err := db.SetActivityExcluded(1, true); err != nil {
+		t.Fatalf("SetActivityExcluded() error = %v", err)
+	}
+	activities, _, err := db.GetActivitiesWithMetricsBetween(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetActivitiesWithMetricsBetween() error = %v", err)
+	}
+	if len(activities) != 1 || activities[0].ID != 2 {
+		t.Errorf("got %d activities after excluding 1, want only 2", len(activities))
+	}
+}
@@ -10,6 +10,15 @@ import (
 	"database/sql"
 )
 
+const clearEffortsComputed = `-- name: ClearEffortsComputed :exec
+UPDATE activities SET efforts_computed = 0 WHERE id = ?
+`
+
+func (q *Queries) ClearEffortsComputed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, clearEffortsComputed, id)
+	return err
+}
+
 const countActivities = `-- name: CountActivities :one
 SELECT COUNT(*) FROM activities
 `
@@ -21,6 +30,97 @@ func (q *Queries) CountActivities(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countActivitiesNeedingStreams = `-- name: CountActivitiesNeedingStreams :one
+SELECT COUNT(*) FROM activities
+WHERE streams_synced = 0 AND has_heartrate = 1 AND manual = 0
+`
+
+func (q *Queries) CountActivitiesNeedingStreams(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActivitiesNeedingStreams)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getActivitiesNeedingEfforts = `-- name: GetActivitiesNeedingEfforts :many
+SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
+    distance, moving_time, elapsed_time, total_elevation_gain,
+    average_speed, max_speed, average_heartrate, max_heartrate,
+    average_cadence, suffer_score, has_heartrate, streams_synced, manual, excluded
+FROM activities
+WHERE streams_synced = 1 AND excluded = 0 AND efforts_computed = 0
+ORDER BY start_date DESC
+`
+
+type GetActivitiesNeedingEffortsRow struct {
+	ID                 int64           `db:"id"`
+	AthleteID          int64           `db:"athlete_id"`
+	Name               string          `db:"name"`
+	Type               string          `db:"type"`
+	StartDate          string          `db:"start_date"`
+	StartDateLocal     string          `db:"start_date_local"`
+	Timezone           sql.NullString  `db:"timezone"`
+	Distance           float64         `db:"distance"`
+	MovingTime         int64           `db:"moving_time"`
+	ElapsedTime        int64           `db:"elapsed_time"`
+	TotalElevationGain sql.NullFloat64 `db:"total_elevation_gain"`
+	AverageSpeed       sql.NullFloat64 `db:"average_speed"`
+	MaxSpeed           sql.NullFloat64 `db:"max_speed"`
+	AverageHeartrate   sql.NullFloat64 `db:"average_heartrate"`
+	MaxHeartrate       sql.NullFloat64 `db:"max_heartrate"`
+	AverageCadence     sql.NullFloat64 `db:"average_cadence"`
+	SufferScore        sql.NullInt64   `db:"suffer_score"`
+	HasHeartrate       int64           `db:"has_heartrate"`
+	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
+}
+
+func (q *Queries) GetActivitiesNeedingEfforts(ctx context.Context) ([]GetActivitiesNeedingEffortsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesNeedingEfforts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivitiesNeedingEffortsRow{}
+	for rows.Next() {
+		var i GetActivitiesNeedingEffortsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.Name,
+			&i.Type,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.AverageCadence,
+			&i.SufferScore,
+			&i.HasHeartrate,
+			&i.StreamsSynced,
+			&i.Manual,
+			&i.Excluded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getActivitiesNeedingMetrics = `-- name: GetActivitiesNeedingMetrics :many
 SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
     a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
@@ -103,7 +203,7 @@ SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
     average_speed, max_speed, average_heartrate, max_heartrate,
     average_cadence, suffer_score, has_heartrate, streams_synced
 FROM activities
-WHERE streams_synced = 0 AND has_heartrate = 1
+WHERE streams_synced = 0 AND has_heartrate = 1 AND manual = 0
 ORDER BY start_date DESC
 LIMIT ?
 `
@@ -177,7 +277,7 @@ const getActivity = `-- name: GetActivity :one
 SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
     distance, moving_time, elapsed_time, total_elevation_gain,
     average_speed, max_speed, average_heartrate, max_heartrate,
-    average_cadence, suffer_score, has_heartrate, streams_synced
+    average_cadence, suffer_score, has_heartrate, streams_synced, manual, excluded
 FROM activities
 WHERE id = ?
 `
@@ -202,6 +302,8 @@ type GetActivityRow struct {
 	SufferScore        sql.NullInt64   `db:"suffer_score"`
 	HasHeartrate       int64           `db:"has_heartrate"`
 	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
 }
 
 func (q *Queries) GetActivity(ctx context.Context, id int64) (GetActivityRow, error) {
@@ -227,15 +329,60 @@ func (q *Queries) GetActivity(ctx context.Context, id int64) (GetActivityRow, er
 		&i.SufferScore,
 		&i.HasHeartrate,
 		&i.StreamsSynced,
+		&i.Manual,
+		&i.Excluded,
 	)
 	return i, err
 }
 
+const insertManualActivity = `-- name: InsertManualActivity :exec
+INSERT INTO activities (
+    id, athlete_id, name, type, start_date, start_date_local, timezone,
+    distance, moving_time, elapsed_time, average_speed, average_heartrate,
+    has_heartrate, streams_synced, manual, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, 1, CURRENT_TIMESTAMP)
+`
+
+type InsertManualActivityParams struct {
+	ID               int64           `db:"id"`
+	AthleteID        int64           `db:"athlete_id"`
+	Name             string          `db:"name"`
+	Type             string          `db:"type"`
+	StartDate        string          `db:"start_date"`
+	StartDateLocal   string          `db:"start_date_local"`
+	Timezone         sql.NullString  `db:"timezone"`
+	Distance         float64         `db:"distance"`
+	MovingTime       int64           `db:"moving_time"`
+	ElapsedTime      int64           `db:"elapsed_time"`
+	AverageSpeed     sql.NullFloat64 `db:"average_speed"`
+	AverageHeartrate sql.NullFloat64 `db:"average_heartrate"`
+	HasHeartrate     int64           `db:"has_heartrate"`
+}
+
+func (q *Queries) InsertManualActivity(ctx context.Context, arg InsertManualActivityParams) error {
+	_, err := q.db.ExecContext(ctx, insertManualActivity,
+		arg.ID,
+		arg.AthleteID,
+		arg.Name,
+		arg.Type,
+		arg.StartDate,
+		arg.StartDateLocal,
+		arg.Timezone,
+		arg.Distance,
+		arg.MovingTime,
+		arg.ElapsedTime,
+		arg.AverageSpeed,
+		arg.AverageHeartrate,
+		arg.HasHeartrate,
+	)
+	return err
+}
+
 const listActivities = `-- name: ListActivities :many
 SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
     distance, moving_time, elapsed_time, total_elevation_gain,
     average_speed, max_speed, average_heartrate, max_heartrate,
-    average_cadence, suffer_score, has_heartrate, streams_synced
+    average_cadence, suffer_score, has_heartrate, streams_synced, manual, excluded
 FROM activities
 ORDER BY start_date DESC
 LIMIT ? OFFSET ?
@@ -266,6 +413,8 @@ type ListActivitiesRow struct {
 	SufferScore        sql.NullInt64   `db:"suffer_score"`
 	HasHeartrate       int64           `db:"has_heartrate"`
 	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
 }
 
 func (q *Queries) ListActivities(ctx context.Context, arg ListActivitiesParams) ([]ListActivitiesRow, error) {
@@ -297,6 +446,8 @@ func (q *Queries) ListActivities(ctx context.Context, arg ListActivitiesParams)
 			&i.SufferScore,
 			&i.HasHeartrate,
 			&i.StreamsSynced,
+			&i.Manual,
+			&i.Excluded,
 		); err != nil {
 			return nil, err
 		}
@@ -311,9 +462,48 @@ func (q *Queries) ListActivities(ctx context.Context, arg ListActivitiesParams)
 	return items, nil
 }
 
+const listRunDays = `-- name: ListRunDays :many
+SELECT DISTINCT CAST(date(CASE WHEN CAST(?1 AS BOOLEAN) THEN start_date ELSE start_date_local END) AS TEXT) AS day
+FROM activities
+WHERE excluded = 0
+ORDER BY day
+`
+
+func (q *Queries) ListRunDays(ctx context.Context, utcDays bool) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listRunDays, utcDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		items = append(items, day)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markEffortsComputed = `-- name: MarkEffortsComputed :exec
+UPDATE activities SET efforts_computed = 1 WHERE id = ?
+`
+
+func (q *Queries) MarkEffortsComputed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markEffortsComputed, id)
+	return err
+}
+
 const markStreamsSynced = `-- name: MarkStreamsSynced :execresult
 UPDATE activities
-SET streams_synced = 1, updated_at = CURRENT_TIMESTAMP
+SET streams_synced = 1, efforts_computed = 0, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?
 `
 
@@ -321,6 +511,41 @@ func (q *Queries) MarkStreamsSynced(ctx context.Context, id int64) (sql.Result,
 	return q.db.ExecContext(ctx, markStreamsSynced, id)
 }
 
+const nextManualActivityID = `-- name: NextManualActivityID :one
+SELECT CAST(COALESCE(MIN(id), 0) - 1 AS INTEGER) AS id FROM activities WHERE id < 0
+`
+
+func (q *Queries) NextManualActivityID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextManualActivityID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const resetEffortsComputed = `-- name: ResetEffortsComputed :exec
+UPDATE activities SET efforts_computed = 0
+`
+
+func (q *Queries) ResetEffortsComputed(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, resetEffortsComputed)
+	return err
+}
+
+const setActivityExcluded = `-- name: SetActivityExcluded :execresult
+UPDATE activities
+SET excluded = ?, efforts_computed = 0, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type SetActivityExcludedParams struct {
+	Excluded int64 `db:"excluded"`
+	ID       int64 `db:"id"`
+}
+
+func (q *Queries) SetActivityExcluded(ctx context.Context, arg SetActivityExcludedParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, setActivityExcluded, arg.Excluded, arg.ID)
+}
+
 const upsertActivity = `-- name: UpsertActivity :exec
 INSERT INTO activities (
     id, athlete_id, name, type, start_date, start_date_local, timezone,
@@ -346,6 +571,13 @@ ON CONFLICT(id) DO UPDATE SET
     average_cadence = excluded.average_cadence,
     suffer_score = excluded.suffer_score,
     has_heartrate = excluded.has_heartrate,
+    efforts_computed = CASE
+        WHEN activities.start_date = excluded.start_date
+            AND activities.distance = excluded.distance
+            AND activities.moving_time = excluded.moving_time
+            AND activities.elapsed_time = excluded.elapsed_time
+            AND COALESCE(activities.total_elevation_gain, 0) = COALESCE(excluded.total_elevation_gain, 0)
+        THEN activities.efforts_computed ELSE 0 END,
     updated_at = CURRENT_TIMESTAMP
 `
 
@@ -395,3 +627,11 @@ func (q *Queries) UpsertActivity(ctx context.Context, arg UpsertActivityParams)
 	)
 	return err
 }
+
+const deleteActivity = `-- name: DeleteActivity :execresult
+DELETE FROM activities WHERE id = ?
+`
+
+func (q *Queries) DeleteActivity(ctx context.Context, id int64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteActivity, id)
+}
@@ -11,7 +11,7 @@ import (
 )
 
 const getAuth = `-- name: GetAuth :one
-SELECT athlete_id, access_token, refresh_token, expires_at
+SELECT athlete_id, access_token, refresh_token, expires_at, scopes
 FROM auth
 WHERE id = 1
 `
@@ -21,6 +21,7 @@ type GetAuthRow struct {
 	AccessToken  string `db:"access_token"`
 	RefreshToken string `db:"refresh_token"`
 	ExpiresAt    int64  `db:"expires_at"`
+	Scopes       string `db:"scopes"`
 }
 
 func (q *Queries) GetAuth(ctx context.Context) (GetAuthRow, error) {
@@ -31,18 +32,20 @@ func (q *Queries) GetAuth(ctx context.Context) (GetAuthRow, error) {
 		&i.AccessToken,
 		&i.RefreshToken,
 		&i.ExpiresAt,
+		&i.Scopes,
 	)
 	return i, err
 }
 
 const saveAuth = `-- name: SaveAuth :exec
-INSERT INTO auth (id, athlete_id, access_token, refresh_token, expires_at, updated_at)
-VALUES (1, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+INSERT INTO auth (id, athlete_id, access_token, refresh_token, expires_at, scopes, updated_at)
+VALUES (1, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(id) DO UPDATE SET
     athlete_id = excluded.athlete_id,
     access_token = excluded.access_token,
     refresh_token = excluded.refresh_token,
     expires_at = excluded.expires_at,
+    scopes = excluded.scopes,
     updated_at = CURRENT_TIMESTAMP
 `
 
@@ -51,6 +54,7 @@ type SaveAuthParams struct {
 	AccessToken  string `db:"access_token"`
 	RefreshToken string `db:"refresh_token"`
 	ExpiresAt    int64  `db:"expires_at"`
+	Scopes       string `db:"scopes"`
 }
 
 func (q *Queries) SaveAuth(ctx context.Context, arg SaveAuthParams) error {
@@ -59,6 +63,7 @@ func (q *Queries) SaveAuth(ctx context.Context, arg SaveAuthParams) error {
 		arg.AccessToken,
 		arg.RefreshToken,
 		arg.ExpiresAt,
+		arg.Scopes,
 	)
 	return err
 }
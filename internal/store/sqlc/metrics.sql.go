@@ -21,16 +21,191 @@ func (q *Queries) CountMetrics(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countSearchActivitiesWithMetrics = `-- name: CountSearchActivitiesWithMetrics :one
+SELECT COUNT(*)
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE (?1 IS NULL
+    OR instr(lower(a.name), lower(CAST(?1 AS TEXT))) > 0
+    OR EXISTS (SELECT 1 FROM activity_notes n WHERE n.activity_id = a.id AND instr(lower(n.note), lower(CAST(?1 AS TEXT))) > 0))
+AND (?2 IS NULL OR a.type = ?2 COLLATE NOCASE)
+AND (?3 IS NULL OR a.start_date_local >= ?3)
+AND (?4 IS NULL OR a.start_date_local < ?4)
+AND (?5 IS NULL OR a.distance >= ?5)
+AND (?6 IS NULL OR a.distance <= ?6)
+AND (CAST(?7 AS INTEGER) = 0 OR EXISTS (SELECT 1 FROM personal_records p WHERE p.activity_id = a.id))
+AND (?8 IS NULL OR EXISTS (SELECT 1 FROM activity_tags t WHERE t.activity_id = a.id AND t.tag = ?8 COLLATE NOCASE))
+`
+
+type CountSearchActivitiesWithMetricsParams struct {
+	Name        sql.NullString  `db:"name"`
+	Type        sql.NullString  `db:"type"`
+	StartAfter  sql.NullString  `db:"start_after"`
+	StartBefore sql.NullString  `db:"start_before"`
+	MinDistance sql.NullFloat64 `db:"min_distance"`
+	MaxDistance sql.NullFloat64 `db:"max_distance"`
+	HasPr       int64           `db:"has_pr"`
+	Tag         sql.NullString  `db:"tag"`
+}
+
+func (q *Queries) CountSearchActivitiesWithMetrics(ctx context.Context, arg CountSearchActivitiesWithMetricsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchActivitiesWithMetrics,
+		arg.Name,
+		arg.Type,
+		arg.StartAfter,
+		arg.StartBefore,
+		arg.MinDistance,
+		arg.MaxDistance,
+		arg.HasPr,
+		arg.Tag,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getActivitiesWithMetricsBetween = `-- name: GetActivitiesWithMetricsBetween :many
+SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
+    a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
+    a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
+    m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
+    m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE a.excluded = 0
+AND CASE WHEN CAST(?1 AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END >= ?2
+AND CASE WHEN CAST(?1 AS BOOLEAN) THEN a.start_date ELSE a.start_date_local END < ?3
+ORDER BY a.start_date DESC
+`
+
+type GetActivitiesWithMetricsBetweenParams struct {
+	UtcDays   bool   `db:"utc_days"`
+	StartDate string `db:"start_date"`
+	EndDate   string `db:"end_date"`
+}
+
+type GetActivitiesWithMetricsBetweenRow struct {
+	ID                 int64           `db:"id"`
+	AthleteID          int64           `db:"athlete_id"`
+	Name               string          `db:"name"`
+	Type               string          `db:"type"`
+	StartDate          string          `db:"start_date"`
+	StartDateLocal     string          `db:"start_date_local"`
+	Timezone           sql.NullString  `db:"timezone"`
+	Distance           float64         `db:"distance"`
+	MovingTime         int64           `db:"moving_time"`
+	ElapsedTime        int64           `db:"elapsed_time"`
+	TotalElevationGain sql.NullFloat64 `db:"total_elevation_gain"`
+	AverageSpeed       sql.NullFloat64 `db:"average_speed"`
+	MaxSpeed           sql.NullFloat64 `db:"max_speed"`
+	AverageHeartrate   sql.NullFloat64 `db:"average_heartrate"`
+	MaxHeartrate       sql.NullFloat64 `db:"max_heartrate"`
+	AverageCadence     sql.NullFloat64 `db:"average_cadence"`
+	SufferScore        sql.NullInt64   `db:"suffer_score"`
+	HasHeartrate       int64           `db:"has_heartrate"`
+	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
+	EfficiencyFactor   sql.NullFloat64 `db:"efficiency_factor"`
+	AerobicDecoupling  sql.NullFloat64 `db:"aerobic_decoupling"`
+	CardiacDrift       sql.NullFloat64 `db:"cardiac_drift"`
+	PaceAtZ1           sql.NullFloat64 `db:"pace_at_z1"`
+	PaceAtZ2           sql.NullFloat64 `db:"pace_at_z2"`
+	PaceAtZ3           sql.NullFloat64 `db:"pace_at_z3"`
+	Trimp              sql.NullFloat64 `db:"trimp"`
+	Hrss               sql.NullFloat64 `db:"hrss"`
+	DataQualityScore   sql.NullFloat64 `db:"data_quality_score"`
+	SteadyStatePct     sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags       sql.NullString  `db:"anomaly_flags"`
+	PacingSplit        sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability    sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength    sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60              sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds          sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds          sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds          sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds          sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds          sql.NullInt64   `db:"z5_seconds"`
+}
+
+func (q *Queries) GetActivitiesWithMetricsBetween(ctx context.Context, arg GetActivitiesWithMetricsBetweenParams) ([]GetActivitiesWithMetricsBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, getActivitiesWithMetricsBetween, arg.UtcDays, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetActivitiesWithMetricsBetweenRow{}
+	for rows.Next() {
+		var i GetActivitiesWithMetricsBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.Name,
+			&i.Type,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.AverageCadence,
+			&i.SufferScore,
+			&i.HasHeartrate,
+			&i.StreamsSynced,
+			&i.Manual,
+			&i.Excluded,
+			&i.EfficiencyFactor,
+			&i.AerobicDecoupling,
+			&i.CardiacDrift,
+			&i.PaceAtZ1,
+			&i.PaceAtZ2,
+			&i.PaceAtZ3,
+			&i.Trimp,
+			&i.Hrss,
+			&i.DataQualityScore,
+			&i.SteadyStatePct,
+			&i.AnomalyFlags,
+			&i.PacingSplit,
+			&i.PaceVariability,
+			&i.AvgStrideLength,
+			&i.Hrr60,
+			&i.Z1Seconds,
+			&i.Z2Seconds,
+			&i.Z3Seconds,
+			&i.Z4Seconds,
+			&i.Z5Seconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getActivitiesWithMetricsRaw = `-- name: GetActivitiesWithMetricsRaw :many
 SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
     a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
     a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
-    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
     m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
     m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
-    m.data_quality_score, m.steady_state_pct
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
 FROM activities a
 JOIN activity_metrics m ON a.id = m.activity_id
+WHERE a.excluded = 0
 ORDER BY a.start_date DESC
 LIMIT ? OFFSET ?
 `
@@ -60,6 +235,8 @@ type GetActivitiesWithMetricsRawRow struct {
 	SufferScore        sql.NullInt64   `db:"suffer_score"`
 	HasHeartrate       int64           `db:"has_heartrate"`
 	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
 	EfficiencyFactor   sql.NullFloat64 `db:"efficiency_factor"`
 	AerobicDecoupling  sql.NullFloat64 `db:"aerobic_decoupling"`
 	CardiacDrift       sql.NullFloat64 `db:"cardiac_drift"`
@@ -70,6 +247,16 @@ type GetActivitiesWithMetricsRawRow struct {
 	Hrss               sql.NullFloat64 `db:"hrss"`
 	DataQualityScore   sql.NullFloat64 `db:"data_quality_score"`
 	SteadyStatePct     sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags       sql.NullString  `db:"anomaly_flags"`
+	PacingSplit        sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability    sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength    sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60              sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds          sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds          sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds          sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds          sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds          sql.NullInt64   `db:"z5_seconds"`
 }
 
 func (q *Queries) GetActivitiesWithMetricsRaw(ctx context.Context, arg GetActivitiesWithMetricsRawParams) ([]GetActivitiesWithMetricsRawRow, error) {
@@ -101,6 +288,8 @@ func (q *Queries) GetActivitiesWithMetricsRaw(ctx context.Context, arg GetActivi
 			&i.SufferScore,
 			&i.HasHeartrate,
 			&i.StreamsSynced,
+			&i.Manual,
+			&i.Excluded,
 			&i.EfficiencyFactor,
 			&i.AerobicDecoupling,
 			&i.CardiacDrift,
@@ -111,6 +300,16 @@ func (q *Queries) GetActivitiesWithMetricsRaw(ctx context.Context, arg GetActivi
 			&i.Hrss,
 			&i.DataQualityScore,
 			&i.SteadyStatePct,
+			&i.AnomalyFlags,
+			&i.PacingSplit,
+			&i.PaceVariability,
+			&i.AvgStrideLength,
+			&i.Hrr60,
+			&i.Z1Seconds,
+			&i.Z2Seconds,
+			&i.Z3Seconds,
+			&i.Z4Seconds,
+			&i.Z5Seconds,
 		); err != nil {
 			return nil, err
 		}
@@ -128,7 +327,7 @@ func (q *Queries) GetActivitiesWithMetricsRaw(ctx context.Context, arg GetActivi
 const getActivityMetrics = `-- name: GetActivityMetrics :one
 SELECT activity_id, efficiency_factor, aerobic_decoupling, cardiac_drift,
     pace_at_z1, pace_at_z2, pace_at_z3, trimp, hrss,
-    data_quality_score, steady_state_pct
+    data_quality_score, steady_state_pct, anomaly_flags, pacing_split, pace_variability, avg_stride_length, hrr_60, z1_seconds, z2_seconds, z3_seconds, z4_seconds, z5_seconds
 FROM activity_metrics
 WHERE activity_id = ?
 `
@@ -145,6 +344,16 @@ type GetActivityMetricsRow struct {
 	Hrss              sql.NullFloat64 `db:"hrss"`
 	DataQualityScore  sql.NullFloat64 `db:"data_quality_score"`
 	SteadyStatePct    sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags      sql.NullString  `db:"anomaly_flags"`
+	PacingSplit       sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability   sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength   sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60             sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds         sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds         sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds         sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds         sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds         sql.NullInt64   `db:"z5_seconds"`
 }
 
 func (q *Queries) GetActivityMetrics(ctx context.Context, activityID int64) (GetActivityMetricsRow, error) {
@@ -162,6 +371,16 @@ func (q *Queries) GetActivityMetrics(ctx context.Context, activityID int64) (Get
 		&i.Hrss,
 		&i.DataQualityScore,
 		&i.SteadyStatePct,
+		&i.AnomalyFlags,
+		&i.PacingSplit,
+		&i.PaceVariability,
+		&i.AvgStrideLength,
+		&i.Hrr60,
+		&i.Z1Seconds,
+		&i.Z2Seconds,
+		&i.Z3Seconds,
+		&i.Z4Seconds,
+		&i.Z5Seconds,
 	)
 	return i, err
 }
@@ -169,7 +388,7 @@ func (q *Queries) GetActivityMetrics(ctx context.Context, activityID int64) (Get
 const getAllMetrics = `-- name: GetAllMetrics :many
 SELECT m.activity_id, m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
     m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
-    m.data_quality_score, m.steady_state_pct
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
 FROM activity_metrics m
 JOIN activities a ON m.activity_id = a.id
 ORDER BY a.start_date DESC
@@ -187,6 +406,16 @@ type GetAllMetricsRow struct {
 	Hrss              sql.NullFloat64 `db:"hrss"`
 	DataQualityScore  sql.NullFloat64 `db:"data_quality_score"`
 	SteadyStatePct    sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags      sql.NullString  `db:"anomaly_flags"`
+	PacingSplit       sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability   sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength   sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60             sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds         sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds         sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds         sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds         sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds         sql.NullInt64   `db:"z5_seconds"`
 }
 
 func (q *Queries) GetAllMetrics(ctx context.Context) ([]GetAllMetricsRow, error) {
@@ -210,6 +439,16 @@ func (q *Queries) GetAllMetrics(ctx context.Context) ([]GetAllMetricsRow, error)
 			&i.Hrss,
 			&i.DataQualityScore,
 			&i.SteadyStatePct,
+			&i.AnomalyFlags,
+			&i.PacingSplit,
+			&i.PaceVariability,
+			&i.AvgStrideLength,
+			&i.Hrr60,
+			&i.Z1Seconds,
+			&i.Z2Seconds,
+			&i.Z3Seconds,
+			&i.Z4Seconds,
+			&i.Z5Seconds,
 		); err != nil {
 			return nil, err
 		}
@@ -235,12 +474,21 @@ func (q *Queries) HasMetrics(ctx context.Context, activityID int64) (int64, erro
 	return column_1, err
 }
 
+const rescaleLegacyHRSS = `-- name: RescaleLegacyHRSS :execresult
+UPDATE activity_metrics SET hrss = trimp * CAST(? AS REAL), hrss_scaled = 1
+WHERE hrss_scaled = 0
+`
+
+func (q *Queries) RescaleLegacyHRSS(ctx context.Context, perTrimp float64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, rescaleLegacyHRSS, perTrimp)
+}
+
 const saveActivityMetrics = `-- name: SaveActivityMetrics :exec
 INSERT INTO activity_metrics (
     activity_id, efficiency_factor, aerobic_decoupling, cardiac_drift,
     pace_at_z1, pace_at_z2, pace_at_z3, trimp, hrss,
-    data_quality_score, steady_state_pct, computed_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+    data_quality_score, steady_state_pct, anomaly_flags, pacing_split, pace_variability, avg_stride_length, hrr_60, z1_seconds, z2_seconds, z3_seconds, z4_seconds, z5_seconds, computed_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 ON CONFLICT(activity_id) DO UPDATE SET
     efficiency_factor = excluded.efficiency_factor,
     aerobic_decoupling = excluded.aerobic_decoupling,
@@ -250,8 +498,19 @@ ON CONFLICT(activity_id) DO UPDATE SET
     pace_at_z3 = excluded.pace_at_z3,
     trimp = excluded.trimp,
     hrss = excluded.hrss,
+    hrss_scaled = 1,
     data_quality_score = excluded.data_quality_score,
     steady_state_pct = excluded.steady_state_pct,
+    anomaly_flags = excluded.anomaly_flags,
+    pacing_split = excluded.pacing_split,
+    pace_variability = excluded.pace_variability,
+    avg_stride_length = excluded.avg_stride_length,
+    hrr_60 = excluded.hrr_60,
+    z1_seconds = excluded.z1_seconds,
+    z2_seconds = excluded.z2_seconds,
+    z3_seconds = excluded.z3_seconds,
+    z4_seconds = excluded.z4_seconds,
+    z5_seconds = excluded.z5_seconds,
     computed_at = CURRENT_TIMESTAMP
 `
 
@@ -267,6 +526,16 @@ type SaveActivityMetricsParams struct {
 	Hrss              sql.NullFloat64 `db:"hrss"`
 	DataQualityScore  sql.NullFloat64 `db:"data_quality_score"`
 	SteadyStatePct    sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags      sql.NullString  `db:"anomaly_flags"`
+	PacingSplit       sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability   sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength   sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60             sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds         sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds         sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds         sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds         sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds         sql.NullInt64   `db:"z5_seconds"`
 }
 
 func (q *Queries) SaveActivityMetrics(ctx context.Context, arg SaveActivityMetricsParams) error {
@@ -282,6 +551,373 @@ func (q *Queries) SaveActivityMetrics(ctx context.Context, arg SaveActivityMetri
 		arg.Hrss,
 		arg.DataQualityScore,
 		arg.SteadyStatePct,
+		arg.AnomalyFlags,
+		arg.PacingSplit,
+		arg.PaceVariability,
+		arg.AvgStrideLength,
+		arg.Hrr60,
+		arg.Z1Seconds,
+		arg.Z2Seconds,
+		arg.Z3Seconds,
+		arg.Z4Seconds,
+		arg.Z5Seconds,
 	)
 	return err
 }
+
+const searchActivitiesWithMetrics = `-- name: SearchActivitiesWithMetrics :many
+SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
+    a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
+    a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
+    m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
+    m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE (?1 IS NULL
+    OR instr(lower(a.name), lower(CAST(?1 AS TEXT))) > 0
+    OR EXISTS (SELECT 1 FROM activity_notes n WHERE n.activity_id = a.id AND instr(lower(n.note), lower(CAST(?1 AS TEXT))) > 0))
+AND (?2 IS NULL OR a.type = ?2 COLLATE NOCASE)
+AND (?3 IS NULL OR a.start_date_local >= ?3)
+AND (?4 IS NULL OR a.start_date_local < ?4)
+AND (?5 IS NULL OR a.distance >= ?5)
+AND (?6 IS NULL OR a.distance <= ?6)
+AND (CAST(?7 AS INTEGER) = 0 OR EXISTS (SELECT 1 FROM personal_records p WHERE p.activity_id = a.id))
+AND (?8 IS NULL OR EXISTS (SELECT 1 FROM activity_tags t WHERE t.activity_id = a.id AND t.tag = ?8 COLLATE NOCASE))
+ORDER BY
+    CASE WHEN CAST(?9 AS INTEGER) = 1 THEN
+        CASE CAST(?10 AS TEXT)
+            WHEN 'distance' THEN a.distance
+            WHEN 'duration' THEN a.moving_time
+            WHEN 'pace' THEN a.moving_time / a.distance
+            WHEN 'ef' THEN m.efficiency_factor
+            WHEN 'trimp' THEN m.trimp
+            WHEN 'decoupling' THEN m.aerobic_decoupling
+            WHEN 'kudos' THEN (SELECT s.kudos_count FROM activity_social s WHERE s.activity_id = a.id)
+            ELSE a.start_date
+        END
+    END ASC NULLS LAST,
+    CASE WHEN CAST(?9 AS INTEGER) = 0 THEN
+        CASE CAST(?10 AS TEXT)
+            WHEN 'distance' THEN a.distance
+            WHEN 'duration' THEN a.moving_time
+            WHEN 'pace' THEN a.moving_time / a.distance
+            WHEN 'ef' THEN m.efficiency_factor
+            WHEN 'trimp' THEN m.trimp
+            WHEN 'decoupling' THEN m.aerobic_decoupling
+            WHEN 'kudos' THEN (SELECT s.kudos_count FROM activity_social s WHERE s.activity_id = a.id)
+            ELSE a.start_date
+        END
+    END DESC NULLS LAST,
+    a.start_date DESC
+LIMIT ?11 OFFSET ?12
+`
+
+type SearchActivitiesWithMetricsParams struct {
+	Name        sql.NullString  `db:"name"`
+	Type        sql.NullString  `db:"type"`
+	StartAfter  sql.NullString  `db:"start_after"`
+	StartBefore sql.NullString  `db:"start_before"`
+	MinDistance sql.NullFloat64 `db:"min_distance"`
+	MaxDistance sql.NullFloat64 `db:"max_distance"`
+	HasPr       int64           `db:"has_pr"`
+	Tag         sql.NullString  `db:"tag"`
+	SortAsc     int64           `db:"sort_asc"`
+	SortBy      string          `db:"sort_by"`
+	Limit       int64           `db:"limit"`
+	Offset      int64           `db:"offset"`
+}
+
+type SearchActivitiesWithMetricsRow struct {
+	ID                 int64           `db:"id"`
+	AthleteID          int64           `db:"athlete_id"`
+	Name               string          `db:"name"`
+	Type               string          `db:"type"`
+	StartDate          string          `db:"start_date"`
+	StartDateLocal     string          `db:"start_date_local"`
+	Timezone           sql.NullString  `db:"timezone"`
+	Distance           float64         `db:"distance"`
+	MovingTime         int64           `db:"moving_time"`
+	ElapsedTime        int64           `db:"elapsed_time"`
+	TotalElevationGain sql.NullFloat64 `db:"total_elevation_gain"`
+	AverageSpeed       sql.NullFloat64 `db:"average_speed"`
+	MaxSpeed           sql.NullFloat64 `db:"max_speed"`
+	AverageHeartrate   sql.NullFloat64 `db:"average_heartrate"`
+	MaxHeartrate       sql.NullFloat64 `db:"max_heartrate"`
+	AverageCadence     sql.NullFloat64 `db:"average_cadence"`
+	SufferScore        sql.NullInt64   `db:"suffer_score"`
+	HasHeartrate       int64           `db:"has_heartrate"`
+	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
+	EfficiencyFactor   sql.NullFloat64 `db:"efficiency_factor"`
+	AerobicDecoupling  sql.NullFloat64 `db:"aerobic_decoupling"`
+	CardiacDrift       sql.NullFloat64 `db:"cardiac_drift"`
+	PaceAtZ1           sql.NullFloat64 `db:"pace_at_z1"`
+	PaceAtZ2           sql.NullFloat64 `db:"pace_at_z2"`
+	PaceAtZ3           sql.NullFloat64 `db:"pace_at_z3"`
+	Trimp              sql.NullFloat64 `db:"trimp"`
+	Hrss               sql.NullFloat64 `db:"hrss"`
+	DataQualityScore   sql.NullFloat64 `db:"data_quality_score"`
+	SteadyStatePct     sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags       sql.NullString  `db:"anomaly_flags"`
+	PacingSplit        sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability    sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength    sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60              sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds          sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds          sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds          sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds          sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds          sql.NullInt64   `db:"z5_seconds"`
+}
+
+func (q *Queries) SearchActivitiesWithMetrics(ctx context.Context, arg SearchActivitiesWithMetricsParams) ([]SearchActivitiesWithMetricsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchActivitiesWithMetrics,
+		arg.Name,
+		arg.Type,
+		arg.StartAfter,
+		arg.StartBefore,
+		arg.MinDistance,
+		arg.MaxDistance,
+		arg.HasPr,
+		arg.Tag,
+		arg.SortAsc,
+		arg.SortBy,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchActivitiesWithMetricsRow{}
+	for rows.Next() {
+		var i SearchActivitiesWithMetricsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.Name,
+			&i.Type,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.AverageCadence,
+			&i.SufferScore,
+			&i.HasHeartrate,
+			&i.StreamsSynced,
+			&i.Manual,
+			&i.Excluded,
+			&i.EfficiencyFactor,
+			&i.AerobicDecoupling,
+			&i.CardiacDrift,
+			&i.PaceAtZ1,
+			&i.PaceAtZ2,
+			&i.PaceAtZ3,
+			&i.Trimp,
+			&i.Hrss,
+			&i.DataQualityScore,
+			&i.SteadyStatePct,
+			&i.AnomalyFlags,
+			&i.PacingSplit,
+			&i.PaceVariability,
+			&i.AvgStrideLength,
+			&i.Hrr60,
+			&i.Z1Seconds,
+			&i.Z2Seconds,
+			&i.Z3Seconds,
+			&i.Z4Seconds,
+			&i.Z5Seconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchActivitiesWithMetricsKeyset = `-- name: SearchActivitiesWithMetricsKeyset :many
+SELECT a.id, a.athlete_id, a.name, a.type, a.start_date, a.start_date_local, a.timezone,
+    a.distance, a.moving_time, a.elapsed_time, a.total_elevation_gain,
+    a.average_speed, a.max_speed, a.average_heartrate, a.max_heartrate,
+    a.average_cadence, a.suffer_score, a.has_heartrate, a.streams_synced, a.manual, a.excluded,
+    m.efficiency_factor, m.aerobic_decoupling, m.cardiac_drift,
+    m.pace_at_z1, m.pace_at_z2, m.pace_at_z3, m.trimp, m.hrss,
+    m.data_quality_score, m.steady_state_pct, m.anomaly_flags, m.pacing_split, m.pace_variability, m.avg_stride_length, m.hrr_60, m.z1_seconds, m.z2_seconds, m.z3_seconds, m.z4_seconds, m.z5_seconds
+FROM activities a
+JOIN activity_metrics m ON a.id = m.activity_id
+WHERE (?1 IS NULL
+    OR instr(lower(a.name), lower(CAST(?1 AS TEXT))) > 0
+    OR EXISTS (SELECT 1 FROM activity_notes n WHERE n.activity_id = a.id AND instr(lower(n.note), lower(CAST(?1 AS TEXT))) > 0))
+AND (?2 IS NULL OR a.type = ?2 COLLATE NOCASE)
+AND (?3 IS NULL OR a.start_date_local >= ?3)
+AND (?4 IS NULL OR a.start_date_local < ?4)
+AND (?5 IS NULL OR a.distance >= ?5)
+AND (?6 IS NULL OR a.distance <= ?6)
+AND (CAST(?7 AS INTEGER) = 0 OR EXISTS (SELECT 1 FROM personal_records p WHERE p.activity_id = a.id))
+AND (?8 IS NULL OR EXISTS (SELECT 1 FROM activity_tags t WHERE t.activity_id = a.id AND t.tag = ?8 COLLATE NOCASE))
+AND (?9 IS NULL
+    OR (CAST(?11 AS INTEGER) = 1 AND (a.start_date < ?9 OR (a.start_date = ?9 AND a.id < ?10)))
+    OR (CAST(?11 AS INTEGER) = 0 AND (a.start_date > ?9 OR (a.start_date = ?9 AND a.id > ?10))))
+ORDER BY
+    CASE WHEN CAST(?11 AS INTEGER) = 1 THEN a.start_date END DESC,
+    CASE WHEN CAST(?11 AS INTEGER) = 1 THEN a.id END DESC,
+    a.start_date ASC, a.id ASC
+LIMIT ?12
+`
+
+type SearchActivitiesWithMetricsKeysetParams struct {
+	Name        sql.NullString  `db:"name"`
+	Type        sql.NullString  `db:"type"`
+	StartAfter  sql.NullString  `db:"start_after"`
+	StartBefore sql.NullString  `db:"start_before"`
+	MinDistance sql.NullFloat64 `db:"min_distance"`
+	MaxDistance sql.NullFloat64 `db:"max_distance"`
+	HasPr       int64           `db:"has_pr"`
+	Tag         sql.NullString  `db:"tag"`
+	CursorDate  sql.NullString  `db:"cursor_date"`
+	CursorID    int64           `db:"cursor_id"`
+	Older       int64           `db:"older"`
+	Limit       int64           `db:"limit"`
+}
+
+type SearchActivitiesWithMetricsKeysetRow struct {
+	ID                 int64           `db:"id"`
+	AthleteID          int64           `db:"athlete_id"`
+	Name               string          `db:"name"`
+	Type               string          `db:"type"`
+	StartDate          string          `db:"start_date"`
+	StartDateLocal     string          `db:"start_date_local"`
+	Timezone           sql.NullString  `db:"timezone"`
+	Distance           float64         `db:"distance"`
+	MovingTime         int64           `db:"moving_time"`
+	ElapsedTime        int64           `db:"elapsed_time"`
+	TotalElevationGain sql.NullFloat64 `db:"total_elevation_gain"`
+	AverageSpeed       sql.NullFloat64 `db:"average_speed"`
+	MaxSpeed           sql.NullFloat64 `db:"max_speed"`
+	AverageHeartrate   sql.NullFloat64 `db:"average_heartrate"`
+	MaxHeartrate       sql.NullFloat64 `db:"max_heartrate"`
+	AverageCadence     sql.NullFloat64 `db:"average_cadence"`
+	SufferScore        sql.NullInt64   `db:"suffer_score"`
+	HasHeartrate       int64           `db:"has_heartrate"`
+	StreamsSynced      int64           `db:"streams_synced"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
+	EfficiencyFactor   sql.NullFloat64 `db:"efficiency_factor"`
+	AerobicDecoupling  sql.NullFloat64 `db:"aerobic_decoupling"`
+	CardiacDrift       sql.NullFloat64 `db:"cardiac_drift"`
+	PaceAtZ1           sql.NullFloat64 `db:"pace_at_z1"`
+	PaceAtZ2           sql.NullFloat64 `db:"pace_at_z2"`
+	PaceAtZ3           sql.NullFloat64 `db:"pace_at_z3"`
+	Trimp              sql.NullFloat64 `db:"trimp"`
+	Hrss               sql.NullFloat64 `db:"hrss"`
+	DataQualityScore   sql.NullFloat64 `db:"data_quality_score"`
+	SteadyStatePct     sql.NullFloat64 `db:"steady_state_pct"`
+	AnomalyFlags       sql.NullString  `db:"anomaly_flags"`
+	PacingSplit        sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability    sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength    sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60              sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds          sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds          sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds          sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds          sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds          sql.NullInt64   `db:"z5_seconds"`
+}
+
+// Pages through a search by start date from a cursor row rather than an
+// offset, so deep pages cost the same as the first. older walks back in time,
+// newest first; otherwise forward, oldest first. A NULL cursor starts at the
+// newest or oldest activity.
+func (q *Queries) SearchActivitiesWithMetricsKeyset(ctx context.Context, arg SearchActivitiesWithMetricsKeysetParams) ([]SearchActivitiesWithMetricsKeysetRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchActivitiesWithMetricsKeyset,
+		arg.Name,
+		arg.Type,
+		arg.StartAfter,
+		arg.StartBefore,
+		arg.MinDistance,
+		arg.MaxDistance,
+		arg.HasPr,
+		arg.Tag,
+		arg.CursorDate,
+		arg.CursorID,
+		arg.Older,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchActivitiesWithMetricsKeysetRow{}
+	for rows.Next() {
+		var i SearchActivitiesWithMetricsKeysetRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AthleteID,
+			&i.Name,
+			&i.Type,
+			&i.StartDate,
+			&i.StartDateLocal,
+			&i.Timezone,
+			&i.Distance,
+			&i.MovingTime,
+			&i.ElapsedTime,
+			&i.TotalElevationGain,
+			&i.AverageSpeed,
+			&i.MaxSpeed,
+			&i.AverageHeartrate,
+			&i.MaxHeartrate,
+			&i.AverageCadence,
+			&i.SufferScore,
+			&i.HasHeartrate,
+			&i.StreamsSynced,
+			&i.Manual,
+			&i.Excluded,
+			&i.EfficiencyFactor,
+			&i.AerobicDecoupling,
+			&i.CardiacDrift,
+			&i.PaceAtZ1,
+			&i.PaceAtZ2,
+			&i.PaceAtZ3,
+			&i.Trimp,
+			&i.Hrss,
+			&i.DataQualityScore,
+			&i.SteadyStatePct,
+			&i.AnomalyFlags,
+			&i.PacingSplit,
+			&i.PaceVariability,
+			&i.AvgStrideLength,
+			&i.Hrr60,
+			&i.Z1Seconds,
+			&i.Z2Seconds,
+			&i.Z3Seconds,
+			&i.Z4Seconds,
+			&i.Z5Seconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -30,6 +30,38 @@ type Activity struct {
 	StreamsSynced      int64           `db:"streams_synced"`
 	CreatedAt          sql.NullString  `db:"created_at"`
 	UpdatedAt          sql.NullString  `db:"updated_at"`
+	Manual             int64           `db:"manual"`
+	Excluded           int64           `db:"excluded"`
+	EffortsComputed    int64           `db:"efforts_computed"`
+}
+
+type ActivityBestEffort struct {
+	ActivityID      int64           `db:"activity_id"`
+	Category        string          `db:"category"`
+	DistanceMeters  float64         `db:"distance_meters"`
+	DurationSeconds int64           `db:"duration_seconds"`
+	StartOffset     int64           `db:"start_offset"`
+	EndOffset       int64           `db:"end_offset"`
+	AvgHeartrate    sql.NullFloat64 `db:"avg_heartrate"`
+	Unverified      int64           `db:"unverified"`
+}
+
+type ActivityDetail struct {
+	ActivityID  int64   `db:"activity_id"`
+	Description string  `db:"description"`
+	DeviceName  string  `db:"device_name"`
+	Calories    float64 `db:"calories"`
+	GearID      string  `db:"gear_id"`
+	GearName    string  `db:"gear_name"`
+	FetchedAt   string  `db:"fetched_at"`
+}
+
+type ActivityDistanceCorrection struct {
+	ActivityID       int64           `db:"activity_id"`
+	Method           string          `db:"method"`
+	StrideLength     sql.NullFloat64 `db:"stride_length"`
+	OriginalDistance float64         `db:"original_distance"`
+	CorrectedAt      string          `db:"corrected_at"`
 }
 
 type ActivityMetric struct {
@@ -45,6 +77,75 @@ type ActivityMetric struct {
 	DataQualityScore  sql.NullFloat64 `db:"data_quality_score"`
 	SteadyStatePct    sql.NullFloat64 `db:"steady_state_pct"`
 	ComputedAt        sql.NullString  `db:"computed_at"`
+	AnomalyFlags      sql.NullString  `db:"anomaly_flags"`
+	PacingSplit       sql.NullFloat64 `db:"pacing_split"`
+	PaceVariability   sql.NullFloat64 `db:"pace_variability"`
+	AvgStrideLength   sql.NullFloat64 `db:"avg_stride_length"`
+	Hrr60             sql.NullFloat64 `db:"hrr_60"`
+	Z1Seconds         sql.NullInt64   `db:"z1_seconds"`
+	Z2Seconds         sql.NullInt64   `db:"z2_seconds"`
+	Z3Seconds         sql.NullInt64   `db:"z3_seconds"`
+	Z4Seconds         sql.NullInt64   `db:"z4_seconds"`
+	Z5Seconds         sql.NullInt64   `db:"z5_seconds"`
+	HrssScaled        int64           `db:"hrss_scaled"`
+}
+
+type ActivityNote struct {
+	ActivityID int64          `db:"activity_id"`
+	Note       string         `db:"note"`
+	UpdatedAt  sql.NullString `db:"updated_at"`
+}
+
+type ActivitySocial struct {
+	ActivityID   int64          `db:"activity_id"`
+	KudosCount   int64          `db:"kudos_count"`
+	CommentCount int64          `db:"comment_count"`
+	PhotoCount   int64          `db:"photo_count"`
+	PhotoUrl     sql.NullString `db:"photo_url"`
+	UpdatedAt    string         `db:"updated_at"`
+}
+
+type ActivitySplit struct {
+	ActivityID       int64           `db:"activity_id"`
+	Units            string          `db:"units"`
+	Split            int64           `db:"split"`
+	Distance         float64         `db:"distance"`
+	Partial          int64           `db:"partial"`
+	Duration         int64           `db:"duration"`
+	StartOffset      int64           `db:"start_offset"`
+	AverageHeartrate sql.NullFloat64 `db:"average_heartrate"`
+	AverageCadence   sql.NullFloat64 `db:"average_cadence"`
+}
+
+type ActivityStreamStat struct {
+	ActivityID     int64         `db:"activity_id"`
+	MovingTime     int64         `db:"moving_time"`
+	MovingDistance float64       `db:"moving_distance"`
+	HrSum          float64       `db:"hr_sum"`
+	HrCount        int64         `db:"hr_count"`
+	CadenceSum     float64       `db:"cadence_sum"`
+	CadenceCount   int64         `db:"cadence_count"`
+	MaxHr          sql.NullInt64 `db:"max_hr"`
+}
+
+type ActivityTag struct {
+	ActivityID int64  `db:"activity_id"`
+	Tag        string `db:"tag"`
+}
+
+type ActivityTrim struct {
+	ActivityID          int64   `db:"activity_id"`
+	EndOffset           int64   `db:"end_offset"`
+	OriginalDistance    float64 `db:"original_distance"`
+	OriginalElapsedTime int64   `db:"original_elapsed_time"`
+	TrimmedAt           string  `db:"trimmed_at"`
+}
+
+type ActivityWeather struct {
+	ActivityID   int64          `db:"activity_id"`
+	TemperatureC float64        `db:"temperature_c"`
+	Source       string         `db:"source"`
+	UpdatedAt    sql.NullString `db:"updated_at"`
 }
 
 type Auth struct {
@@ -53,10 +154,51 @@ type Auth struct {
 	AccessToken  string         `db:"access_token"`
 	RefreshToken string         `db:"refresh_token"`
 	ExpiresAt    int64          `db:"expires_at"`
+	Scopes       string         `db:"scopes"`
 	CreatedAt    sql.NullString `db:"created_at"`
 	UpdatedAt    sql.NullString `db:"updated_at"`
 }
 
+type Benchmark struct {
+	ID                  int64          `db:"id"`
+	Name                string         `db:"name"`
+	Kind                string         `db:"kind"`
+	ReferenceActivityID int64          `db:"reference_activity_id"`
+	CreatedAt           sql.NullString `db:"created_at"`
+}
+
+type BenchmarkActivity struct {
+	BenchmarkID int64 `db:"benchmark_id"`
+	ActivityID  int64 `db:"activity_id"`
+	Included    int64 `db:"included"`
+}
+
+type CustomMetric struct {
+	ActivityID int64   `db:"activity_id"`
+	Name       string  `db:"name"`
+	Label      string  `db:"label"`
+	Value      float64 `db:"value"`
+}
+
+type DeletedActivity struct {
+	ID        int64          `db:"id"`
+	Name      string         `db:"name"`
+	StartDate string         `db:"start_date"`
+	DeletedAt sql.NullString `db:"deleted_at"`
+}
+
+type DurationEffort struct {
+	ActivityID      int64   `db:"activity_id"`
+	DurationSeconds int64   `db:"duration_seconds"`
+	DistanceMeters  float64 `db:"distance_meters"`
+	StartOffset     int64   `db:"start_offset"`
+}
+
+type EffortVerification struct {
+	ActivityID int64  `db:"activity_id"`
+	VerifiedAt string `db:"verified_at"`
+}
+
 type FitnessTrend struct {
 	Date                string          `db:"date"`
 	Ctl                 sql.NullFloat64 `db:"ctl"`
@@ -69,6 +211,26 @@ type FitnessTrend struct {
 	TotalDistance7d     sql.NullFloat64 `db:"total_distance_7d"`
 	TotalTime7d         sql.NullInt64   `db:"total_time_7d"`
 	ComputedAt          sql.NullString  `db:"computed_at"`
+	Monotony7d          sql.NullFloat64 `db:"monotony_7d"`
+	Strain7d            sql.NullFloat64 `db:"strain_7d"`
+}
+
+type HttpCache struct {
+	Url          string `db:"url"`
+	Etag         string `db:"etag"`
+	LastModified string `db:"last_modified"`
+	Body         []byte `db:"body"`
+	StoredAt     string `db:"stored_at"`
+}
+
+type Injury struct {
+	ID         int64          `db:"id"`
+	StartedOn  string         `db:"started_on"`
+	BodyPart   string         `db:"body_part"`
+	Severity   int64          `db:"severity"`
+	Notes      string         `db:"notes"`
+	ResolvedOn sql.NullString `db:"resolved_on"`
+	CreatedAt  sql.NullString `db:"created_at"`
 }
 
 type PersonalRecord struct {
@@ -82,6 +244,26 @@ type PersonalRecord struct {
 	AchievedAt      string          `db:"achieved_at"`
 	StartOffset     sql.NullInt64   `db:"start_offset"`
 	EndOffset       sql.NullInt64   `db:"end_offset"`
+	Unverified      int64           `db:"unverified"`
+}
+
+type PrHistory struct {
+	ID              int64           `db:"id"`
+	Category        string          `db:"category"`
+	ActivityID      int64           `db:"activity_id"`
+	DistanceMeters  float64         `db:"distance_meters"`
+	DurationSeconds int64           `db:"duration_seconds"`
+	PacePerMile     sql.NullFloat64 `db:"pace_per_mile"`
+	AchievedAt      string          `db:"achieved_at"`
+	CompareMode     int64           `db:"compare_mode"`
+	Margin          sql.NullFloat64 `db:"margin"`
+}
+
+type Race struct {
+	ActivityID int64  `db:"activity_id"`
+	Source     string `db:"source"`
+	Placing    string `db:"placing"`
+	Dismissed  int64  `db:"dismissed"`
 }
 
 type RacePrediction struct {
@@ -111,8 +293,79 @@ type Stream struct {
 	Distance       sql.NullFloat64 `db:"distance"`
 }
 
+type StravaBestEffort struct {
+	ActivityID  int64   `db:"activity_id"`
+	Name        string  `db:"name"`
+	Distance    float64 `db:"distance"`
+	ElapsedTime int64   `db:"elapsed_time"`
+	MovingTime  int64   `db:"moving_time"`
+	StartIndex  int64   `db:"start_index"`
+	EndIndex    int64   `db:"end_index"`
+}
+
+type StravaSplit struct {
+	ActivityID          int64           `db:"activity_id"`
+	Units               string          `db:"units"`
+	Split               int64           `db:"split"`
+	Distance            float64         `db:"distance"`
+	ElapsedTime         int64           `db:"elapsed_time"`
+	MovingTime          int64           `db:"moving_time"`
+	ElevationDifference float64         `db:"elevation_difference"`
+	AverageSpeed        float64         `db:"average_speed"`
+	AverageHeartrate    sql.NullFloat64 `db:"average_heartrate"`
+}
+
+type StreamBlob struct {
+	ActivityID int64          `db:"activity_id"`
+	PointCount int64          `db:"point_count"`
+	Data       []byte         `db:"data"`
+	CreatedAt  sql.NullString `db:"created_at"`
+}
+
+type SyncLock struct {
+	ID          int64  `db:"id"`
+	Owner       string `db:"owner"`
+	StartedAt   string `db:"started_at"`
+	HeartbeatAt string `db:"heartbeat_at"`
+}
+
 type SyncState struct {
 	Key       string         `db:"key"`
 	Value     string         `db:"value"`
 	UpdatedAt sql.NullString `db:"updated_at"`
 }
+
+type TrainingPlan struct {
+	ID           int64          `db:"id"`
+	RaceDate     string         `db:"race_date"`
+	StartWeek    string         `db:"start_week"`
+	BaseDistance float64        `db:"base_distance"`
+	PeakDistance float64        `db:"peak_distance"`
+	TaperWeeks   int64          `db:"taper_weeks"`
+	CutbackEvery int64          `db:"cutback_every"`
+	CreatedAt    sql.NullString `db:"created_at"`
+}
+
+type WeeklySummary struct {
+	WeekStart      string         `db:"week_start"`
+	RunCount       int64          `db:"run_count"`
+	Distance       float64        `db:"distance"`
+	MovingTime     int64          `db:"moving_time"`
+	MovingDistance float64        `db:"moving_distance"`
+	HrSum          float64        `db:"hr_sum"`
+	HrCount        int64          `db:"hr_count"`
+	CadenceSum     float64        `db:"cadence_sum"`
+	CadenceCount   int64          `db:"cadence_count"`
+	Trimp          float64        `db:"trimp"`
+	ElevationGain  float64        `db:"elevation_gain"`
+	UpdatedAt      sql.NullString `db:"updated_at"`
+}
+
+type Wellness struct {
+	Date       string          `db:"date"`
+	RestingHr  sql.NullFloat64 `db:"resting_hr"`
+	Hrv        sql.NullFloat64 `db:"hrv"`
+	SleepHours sql.NullFloat64 `db:"sleep_hours"`
+	Soreness   sql.NullInt64   `db:"soreness"`
+	UpdatedAt  sql.NullString  `db:"updated_at"`
+}
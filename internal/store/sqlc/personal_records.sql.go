@@ -10,6 +10,42 @@ import (
 	"database/sql"
 )
 
+const deletePRHistory = `-- name: DeletePRHistory :exec
+DELETE FROM pr_history WHERE id = ?
+`
+
+func (q *Queries) DeletePRHistory(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deletePRHistory, id)
+	return err
+}
+
+const deletePRHistoryForActivity = `-- name: DeletePRHistoryForActivity :exec
+DELETE FROM pr_history WHERE activity_id = ?
+`
+
+func (q *Queries) DeletePRHistoryForActivity(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePRHistoryForActivity, activityID)
+	return err
+}
+
+const deletePRHistoryForCategory = `-- name: DeletePRHistoryForCategory :exec
+DELETE FROM pr_history WHERE category = ?
+`
+
+func (q *Queries) DeletePRHistoryForCategory(ctx context.Context, category string) error {
+	_, err := q.db.ExecContext(ctx, deletePRHistoryForCategory, category)
+	return err
+}
+
+const deletePersonalRecord = `-- name: DeletePersonalRecord :exec
+DELETE FROM personal_records WHERE category = ?
+`
+
+func (q *Queries) DeletePersonalRecord(ctx context.Context, category string) error {
+	_, err := q.db.ExecContext(ctx, deletePersonalRecord, category)
+	return err
+}
+
 const deletePersonalRecordsForActivity = `-- name: DeletePersonalRecordsForActivity :exec
 DELETE FROM personal_records WHERE activity_id = ?
 `
@@ -21,7 +57,8 @@ func (q *Queries) DeletePersonalRecordsForActivity(ctx context.Context, activity
 
 const getAllPersonalRecords = `-- name: GetAllPersonalRecords :many
 SELECT id, category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
 FROM personal_records
 ORDER BY category
 `
@@ -46,6 +83,48 @@ func (q *Queries) GetAllPersonalRecords(ctx context.Context) ([]PersonalRecord,
 			&i.AchievedAt,
 			&i.StartOffset,
 			&i.EndOffset,
+			&i.Unverified,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPRHistory = `-- name: GetPRHistory :many
+SELECT id, category, activity_id, distance_meters, duration_seconds,
+    pace_per_mile, achieved_at, compare_mode, margin
+FROM pr_history
+WHERE category = ?
+ORDER BY achieved_at, id
+`
+
+func (q *Queries) GetPRHistory(ctx context.Context, category string) ([]PrHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getPRHistory, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PrHistory{}
+	for rows.Next() {
+		var i PrHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Category,
+			&i.ActivityID,
+			&i.DistanceMeters,
+			&i.DurationSeconds,
+			&i.PacePerMile,
+			&i.AchievedAt,
+			&i.CompareMode,
+			&i.Margin,
 		); err != nil {
 			return nil, err
 		}
@@ -60,9 +139,37 @@ func (q *Queries) GetAllPersonalRecords(ctx context.Context) ([]PersonalRecord,
 	return items, nil
 }
 
+const getPRHistoryCategoriesForActivity = `-- name: GetPRHistoryCategoriesForActivity :many
+SELECT DISTINCT category FROM pr_history WHERE activity_id = ?
+`
+
+func (q *Queries) GetPRHistoryCategoriesForActivity(ctx context.Context, activityID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPRHistoryCategoriesForActivity, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, err
+		}
+		items = append(items, category)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPersonalRecordByCategory = `-- name: GetPersonalRecordByCategory :one
 SELECT id, category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
 FROM personal_records
 WHERE category = ?
 `
@@ -81,13 +188,15 @@ func (q *Queries) GetPersonalRecordByCategory(ctx context.Context, category stri
 		&i.AchievedAt,
 		&i.StartOffset,
 		&i.EndOffset,
+		&i.Unverified,
 	)
 	return i, err
 }
 
 const getPersonalRecordsForActivity = `-- name: GetPersonalRecordsForActivity :many
 SELECT id, category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
 FROM personal_records
 WHERE activity_id = ?
 ORDER BY category
@@ -113,6 +222,7 @@ func (q *Queries) GetPersonalRecordsForActivity(ctx context.Context, activityID
 			&i.AchievedAt,
 			&i.StartOffset,
 			&i.EndOffset,
+			&i.Unverified,
 		); err != nil {
 			return nil, err
 		}
@@ -127,11 +237,44 @@ func (q *Queries) GetPersonalRecordsForActivity(ctx context.Context, activityID
 	return items, nil
 }
 
+const insertPRHistory = `-- name: InsertPRHistory :exec
+INSERT INTO pr_history (
+    category, activity_id, distance_meters, duration_seconds,
+    pace_per_mile, achieved_at, compare_mode, margin
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertPRHistoryParams struct {
+	Category        string          `db:"category"`
+	ActivityID      int64           `db:"activity_id"`
+	DistanceMeters  float64         `db:"distance_meters"`
+	DurationSeconds int64           `db:"duration_seconds"`
+	PacePerMile     sql.NullFloat64 `db:"pace_per_mile"`
+	AchievedAt      string          `db:"achieved_at"`
+	CompareMode     int64           `db:"compare_mode"`
+	Margin          sql.NullFloat64 `db:"margin"`
+}
+
+func (q *Queries) InsertPRHistory(ctx context.Context, arg InsertPRHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertPRHistory,
+		arg.Category,
+		arg.ActivityID,
+		arg.DistanceMeters,
+		arg.DurationSeconds,
+		arg.PacePerMile,
+		arg.AchievedAt,
+		arg.CompareMode,
+		arg.Margin,
+	)
+	return err
+}
+
 const insertPersonalRecord = `-- name: InsertPersonalRecord :exec
 INSERT INTO personal_records (
     category, activity_id, distance_meters, duration_seconds,
-    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    pace_per_mile, avg_heartrate, achieved_at, start_offset, end_offset,
+    unverified
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(category) DO UPDATE SET
     activity_id = excluded.activity_id,
     distance_meters = excluded.distance_meters,
@@ -140,7 +283,8 @@ ON CONFLICT(category) DO UPDATE SET
     avg_heartrate = excluded.avg_heartrate,
     achieved_at = excluded.achieved_at,
     start_offset = excluded.start_offset,
-    end_offset = excluded.end_offset
+    end_offset = excluded.end_offset,
+    unverified = excluded.unverified
 `
 
 type InsertPersonalRecordParams struct {
@@ -153,6 +297,7 @@ type InsertPersonalRecordParams struct {
 	AchievedAt      string          `db:"achieved_at"`
 	StartOffset     sql.NullInt64   `db:"start_offset"`
 	EndOffset       sql.NullInt64   `db:"end_offset"`
+	Unverified      int64           `db:"unverified"`
 }
 
 func (q *Queries) InsertPersonalRecord(ctx context.Context, arg InsertPersonalRecordParams) error {
@@ -166,6 +311,65 @@ func (q *Queries) InsertPersonalRecord(ctx context.Context, arg InsertPersonalRe
 		arg.AchievedAt,
 		arg.StartOffset,
 		arg.EndOffset,
+		arg.Unverified,
 	)
 	return err
 }
+
+const listStaleRecordCategories = `-- name: ListStaleRecordCategories :many
+SELECT category FROM personal_records
+WHERE activity_id NOT IN (SELECT id FROM activities WHERE excluded = 0)
+UNION
+SELECT category FROM pr_history
+WHERE activity_id NOT IN (SELECT id FROM activities WHERE excluded = 0)
+UNION
+SELECT category FROM pr_history
+WHERE category NOT IN (SELECT category FROM personal_records)
+ORDER BY category
+`
+
+func (q *Queries) ListStaleRecordCategories(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listStaleRecordCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, err
+		}
+		items = append(items, category)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePRHistoryMargin = `-- name: UpdatePRHistoryMargin :exec
+UPDATE pr_history SET margin = ? WHERE id = ?
+`
+
+type UpdatePRHistoryMarginParams struct {
+	Margin sql.NullFloat64 `db:"margin"`
+	ID     int64           `db:"id"`
+}
+
+func (q *Queries) UpdatePRHistoryMargin(ctx context.Context, arg UpdatePRHistoryMarginParams) error {
+	_, err := q.db.ExecContext(ctx, updatePRHistoryMargin, arg.Margin, arg.ID)
+	return err
+}
+
+const verifyPersonalRecordsForActivity = `-- name: VerifyPersonalRecordsForActivity :exec
+UPDATE personal_records SET unverified = 0 WHERE activity_id = ?
+`
+
+func (q *Queries) VerifyPersonalRecordsForActivity(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, verifyPersonalRecordsForActivity, activityID)
+	return err
+}
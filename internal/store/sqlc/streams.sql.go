@@ -10,6 +10,15 @@ import (
 	"database/sql"
 )
 
+const deleteStreamBlob = `-- name: DeleteStreamBlob :exec
+DELETE FROM stream_blobs WHERE activity_id = ?
+`
+
+func (q *Queries) DeleteStreamBlob(ctx context.Context, activityID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteStreamBlob, activityID)
+	return err
+}
+
 const deleteStreams = `-- name: DeleteStreams :exec
 DELETE FROM streams WHERE activity_id = ?
 `
@@ -28,6 +37,22 @@ func (q *Queries) DeleteStreamsForActivity(ctx context.Context, activityID int64
 	return err
 }
 
+const getStreamBlob = `-- name: GetStreamBlob :one
+SELECT point_count, data FROM stream_blobs WHERE activity_id = ?
+`
+
+type GetStreamBlobRow struct {
+	PointCount int64  `db:"point_count"`
+	Data       []byte `db:"data"`
+}
+
+func (q *Queries) GetStreamBlob(ctx context.Context, activityID int64) (GetStreamBlobRow, error) {
+	row := q.db.QueryRowContext(ctx, getStreamBlob, activityID)
+	var i GetStreamBlobRow
+	err := row.Scan(&i.PointCount, &i.Data)
+	return i, err
+}
+
 const getStreamCount = `-- name: GetStreamCount :one
 SELECT COUNT(*) FROM streams WHERE activity_id = ?
 `
@@ -39,6 +64,55 @@ func (q *Queries) GetStreamCount(ctx context.Context, activityID int64) (int64,
 	return count, err
 }
 
+const getStreamPage = `-- name: GetStreamPage :many
+SELECT activity_id, time_offset, latlng_lat, latlng_lng, altitude,
+    velocity_smooth, heartrate, cadence, grade_smooth, distance
+FROM streams
+WHERE activity_id = ?
+ORDER BY time_offset
+LIMIT ? OFFSET ?
+`
+
+type GetStreamPageParams struct {
+	ActivityID int64 `db:"activity_id"`
+	Limit      int64 `db:"limit"`
+	Offset     int64 `db:"offset"`
+}
+
+func (q *Queries) GetStreamPage(ctx context.Context, arg GetStreamPageParams) ([]Stream, error) {
+	rows, err := q.db.QueryContext(ctx, getStreamPage, arg.ActivityID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Stream{}
+	for rows.Next() {
+		var i Stream
+		if err := rows.Scan(
+			&i.ActivityID,
+			&i.TimeOffset,
+			&i.LatlngLat,
+			&i.LatlngLng,
+			&i.Altitude,
+			&i.VelocitySmooth,
+			&i.Heartrate,
+			&i.Cadence,
+			&i.GradeSmooth,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStreams = `-- name: GetStreams :many
 SELECT activity_id, time_offset, latlng_lat, latlng_lng, altitude,
     velocity_smooth, heartrate, cadence, grade_smooth, distance
@@ -127,3 +201,107 @@ func (q *Queries) InsertStreamPoint(ctx context.Context, arg InsertStreamPointPa
 	)
 	return err
 }
+
+const listBlobStreamActivityIDs = `-- name: ListBlobStreamActivityIDs :many
+SELECT activity_id FROM stream_blobs ORDER BY activity_id
+`
+
+func (q *Queries) ListBlobStreamActivityIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listBlobStreamActivityIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var activity_id int64
+		if err := rows.Scan(&activity_id); err != nil {
+			return nil, err
+		}
+		items = append(items, activity_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRowStreamActivityIDs = `-- name: ListRowStreamActivityIDs :many
+SELECT DISTINCT activity_id FROM streams ORDER BY activity_id
+`
+
+func (q *Queries) ListRowStreamActivityIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listRowStreamActivityIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var activity_id int64
+		if err := rows.Scan(&activity_id); err != nil {
+			return nil, err
+		}
+		items = append(items, activity_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveStreamBlob = `-- name: SaveStreamBlob :exec
+INSERT INTO stream_blobs (activity_id, point_count, data)
+VALUES (?, ?, ?)
+ON CONFLICT(activity_id) DO UPDATE SET
+    point_count = excluded.point_count,
+    data = excluded.data
+`
+
+type SaveStreamBlobParams struct {
+	ActivityID int64  `db:"activity_id"`
+	PointCount int64  `db:"point_count"`
+	Data       []byte `db:"data"`
+}
+
+func (q *Queries) SaveStreamBlob(ctx context.Context, arg SaveStreamBlobParams) error {
+	_, err := q.db.ExecContext(ctx, saveStreamBlob, arg.ActivityID, arg.PointCount, arg.Data)
+	return err
+}
+
+const listActivitiesWithStreamsBefore = `-- name: ListActivitiesWithStreamsBefore :many
+SELECT id FROM activities
+WHERE start_date_local < ?
+AND (EXISTS (SELECT 1 FROM streams s WHERE s.activity_id = activities.id)
+    OR EXISTS (SELECT 1 FROM stream_blobs b WHERE b.activity_id = activities.id))
+ORDER BY id
+`
+
+func (q *Queries) ListActivitiesWithStreamsBefore(ctx context.Context, startDateLocal string) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listActivitiesWithStreamsBefore, startDateLocal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"runner/internal/store/sqlc"
@@ -17,21 +18,96 @@ import (
 type Store struct {
 	db      *sql.DB
 	queries *sqlc.Queries
+	writer  *writer
+
+	// compressStreams makes SaveStreams write compressed column blobs
+	// instead of one row per sample
+	compressStreams bool
+
+	// utcDays buckets runs into days, weeks and months by their UTC start
+	// instead of the local time where they started
+	utcDays bool
+
+	// weekStart is the first day of the weeks weekly summaries cover
+	weekStart time.Weekday
+
+	// readOnly is set when the connection can't write
+	readOnly bool
 }
 
 // newStore creates a Store from a database connection.
 func newStore(db *sql.DB) *Store {
+	w := newWriter()
 	return &Store{
-		db:      db,
-		queries: sqlc.New(db),
+		db:        db,
+		queries:   sqlc.New(timedDB{db: db, writer: w}),
+		writer:    w,
+		weekStart: time.Monday,
 	}
 }
 
-// Close closes the underlying database connection.
+// Close finishes queued writes and closes the underlying database connection.
 func (s *Store) Close() error {
+	s.writer.close()
 	return s.db.Close()
 }
 
+// ReadOnly reports whether the database was opened read-only
+func (s *Store) ReadOnly() bool {
+	return s.readOnly
+}
+
+// SetCompressStreams selects how SaveStreams stores new stream data. Reads
+// handle both formats, so existing data stays readable either way.
+func (s *Store) SetCompressStreams(compress bool) {
+	s.compressStreams = compress
+}
+
+// SetUTCDays selects the clock runs are bucketed into days, weeks and months
+// by. By default a run belongs to the day on the local clock where it
+// started, so a late-evening run while traveling stays on its own day; with
+// utc set, start times are bucketed in UTC instead.
+func (s *Store) SetUTCDays(utc bool) {
+	s.utcDays = utc
+}
+
+// UTCDays reports whether runs are bucketed by their UTC start times.
+func (s *Store) UTCDays() bool {
+	return s.utcDays
+}
+
+// SetWeekStart selects the first day of the week runs are bucketed into
+// weeks by. Weeks start on Monday by default, as ISO weeks do.
+func (s *Store) SetWeekStart(first time.Weekday) {
+	s.weekStart = first
+}
+
+// WeekStart returns the first day of the week.
+func (s *Store) WeekStart() time.Weekday {
+	return s.weekStart
+}
+
+// BucketTime returns when an activity started on the day-bucketing clock:
+// its local wall-clock start, or its UTC start under SetUTCDays. Either way
+// the result is in UTC, so its calendar fields name the bucket.
+func (s *Store) BucketTime(a Activity) time.Time {
+	if s.utcDays || a.StartDateLocal.IsZero() {
+		return a.StartDate.UTC()
+	}
+	return a.StartDateLocal.UTC()
+}
+
+// BucketClock converts an instant such as time.Now() to the day-bucketing
+// clock, with the local wall clock standing in for where the athlete is now.
+func (s *Store) BucketClock(t time.Time) time.Time {
+	if s.utcDays {
+		return t.UTC()
+	}
+	local := t.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(),
+		local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+}
+
 // DB returns the underlying *sql.DB for advanced operations.
 func (s *Store) DB() *sql.DB {
 	return s.db
@@ -53,6 +129,7 @@ func (s *Store) GetAuth() (*Auth, error) {
 		AccessToken:  row.AccessToken,
 		RefreshToken: row.RefreshToken,
 		ExpiresAt:    time.Unix(row.ExpiresAt, 0),
+		Scopes:       row.Scopes,
 	}, nil
 }
 
@@ -63,6 +140,7 @@ func (s *Store) SaveAuth(auth *Auth) error {
 		AccessToken:  auth.AccessToken,
 		RefreshToken: auth.RefreshToken,
 		ExpiresAt:    auth.ExpiresAt.Unix(),
+		Scopes:       auth.Scopes,
 	})
 }
 
@@ -133,6 +211,41 @@ func (s *Store) UpsertActivity(a *Activity) error {
 	})
 }
 
+// CreateManualActivity inserts a locally entered activity and returns its ID.
+// Manual activities get negative IDs so they can never collide with Strava's.
+func (s *Store) CreateManualActivity(a *Activity) (int64, error) {
+	ctx := context.Background()
+	id, err := s.queries.NextManualActivityID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("allocating activity id: %w", err)
+	}
+
+	var avgSpeed sql.NullFloat64
+	if a.MovingTime > 0 {
+		avgSpeed = toNullFloat64(a.Distance / float64(a.MovingTime))
+	}
+
+	err = s.queries.InsertManualActivity(ctx, sqlc.InsertManualActivityParams{
+		ID:               id,
+		AthleteID:        a.AthleteID,
+		Name:             a.Name,
+		Type:             a.Type,
+		StartDate:        a.StartDate.Format(time.RFC3339),
+		StartDateLocal:   a.StartDateLocal.Format(time.RFC3339),
+		Timezone:         toNullString(a.Timezone),
+		Distance:         a.Distance,
+		MovingTime:       int64(a.MovingTime),
+		ElapsedTime:      int64(a.ElapsedTime),
+		AverageSpeed:     avgSpeed,
+		AverageHeartrate: ptrToNullFloat64(a.AverageHeartrate),
+		HasHeartrate:     boolToInt64(a.AverageHeartrate != nil),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
 // GetActivity retrieves an activity by ID.
 func (s *Store) GetActivity(id int64) (*Activity, error) {
 	row, err := s.queries.GetActivity(context.Background(), id)
@@ -199,6 +312,37 @@ func (s *Store) GetActivitiesNeedingMetrics() ([]Activity, error) {
 	return activities, nil
 }
 
+// GetActivitiesNeedingEfforts returns the activities with streams that
+// haven't been analyzed for personal records since they were downloaded,
+// changed or restored, newest first.
+func (s *Store) GetActivitiesNeedingEfforts() ([]Activity, error) {
+	rows, err := s.queries.GetActivitiesNeedingEfforts(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	activities := make([]Activity, 0, len(rows))
+	for _, row := range rows {
+		a, err := listActivityRowToActivity(sqlc.ListActivitiesRow(row))
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, *a)
+	}
+	return activities, nil
+}
+
+// MarkEffortsComputed records that an activity was analyzed for personal
+// records, so later syncs skip it until it changes.
+func (s *Store) MarkEffortsComputed(id int64) error {
+	return s.queries.MarkEffortsComputed(context.Background(), id)
+}
+
+// ResetEffortsComputed marks every activity as needing analysis for
+// personal records again.
+func (s *Store) ResetEffortsComputed() error {
+	return s.queries.ResetEffortsComputed(context.Background())
+}
+
 // MarkStreamsSynced marks an activity's streams as synced.
 func (s *Store) MarkStreamsSynced(id int64) error {
 	result, err := s.queries.MarkStreamsSynced(context.Background(), id)
@@ -215,16 +359,69 @@ func (s *Store) MarkStreamsSynced(id int64) error {
 	return nil
 }
 
+// SetActivityExcluded marks an activity as excluded from (or included in)
+// metrics, trends and personal records.
+func (s *Store) SetActivityExcluded(id int64, excluded bool) error {
+	result, err := s.queries.SetActivityExcluded(context.Background(), sqlc.SetActivityExcludedParams{
+		Excluded: boolToInt64(excluded),
+		ID:       id,
+	})
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrActivityNotFound
+	}
+	return nil
+}
+
 // CountActivities returns the total number of activities.
 func (s *Store) CountActivities() (int, error) {
 	count, err := s.queries.CountActivities(context.Background())
 	return int(count), err
 }
 
+// CountActivitiesNeedingStreams returns how many activities are still
+// waiting for their streams to be synced.
+func (s *Store) CountActivitiesNeedingStreams() (int, error) {
+	count, err := s.queries.CountActivitiesNeedingStreams(context.Background())
+	return int(count), err
+}
+
+// ListRunDays returns each calendar day on the day-bucketing clock with at
+// least one non-excluded activity, oldest first, as midnight UTC.
+func (s *Store) ListRunDays() ([]time.Time, error) {
+	rows, err := s.queries.ListRunDays(context.Background(), s.utcDays)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]time.Time, 0, len(rows))
+	for _, row := range rows {
+		day, err := time.Parse("2006-01-02", row)
+		if err != nil {
+			return nil, fmt.Errorf("parsing run day %q: %w", row, err)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
 // --- Stream Methods ---
 
 // GetStreams retrieves all stream points for an activity.
 func (s *Store) GetStreams(activityID int64) ([]StreamPoint, error) {
+	blob, err := s.queries.GetStreamBlob(context.Background(), activityID)
+	if err == nil {
+		return decodeStreams(activityID, blob.Data)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
 	rows, err := s.queries.GetStreams(context.Background(), activityID)
 	if err != nil {
 		return nil, err
@@ -238,24 +435,64 @@ func (s *Store) GetStreams(activityID int64) ([]StreamPoint, error) {
 
 // GetStreamCount returns the number of stream points for an activity.
 func (s *Store) GetStreamCount(activityID int64) (int, error) {
+	blob, err := s.queries.GetStreamBlob(context.Background(), activityID)
+	if err == nil {
+		return int(blob.PointCount), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
 	count, err := s.queries.GetStreamCount(context.Background(), activityID)
 	return int(count), err
 }
 
+// GetStreamPage retrieves up to limit stream points for an activity,
+// starting with the point at index offset.
+func (s *Store) GetStreamPage(activityID int64, offset, limit int) ([]StreamPoint, error) {
+	blob, err := s.queries.GetStreamBlob(context.Background(), activityID)
+	if err == nil {
+		// Compressed streams are stored whole, so decode and slice
+		points, err := decodeStreams(activityID, blob.Data)
+		if err != nil {
+			return nil, err
+		}
+		start := min(max(offset, 0), len(points))
+		return points[start:min(start+limit, len(points))], nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	rows, err := s.queries.GetStreamPage(context.Background(), sqlc.GetStreamPageParams{
+		ActivityID: activityID,
+		Limit:      int64(limit),
+		Offset:     int64(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	points := make([]StreamPoint, 0, len(rows))
+	for _, row := range rows {
+		points = append(points, streamToStreamPoint(row))
+	}
+	return points, nil
+}
+
 // HasStreams checks if an activity has stream data.
 func (s *Store) HasStreams(activityID int64) (bool, error) {
-	_, err := s.queries.HasStreams(context.Background(), activityID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return false, nil
-	}
+	count, err := s.GetStreamCount(activityID)
 	if err != nil {
 		return false, err
 	}
-	return true, nil
+	return count > 0, nil
 }
 
 // DeleteStreams removes all stream data for an activity.
 func (s *Store) DeleteStreams(activityID int64) error {
+	if err := s.queries.DeleteStreamBlob(context.Background(), activityID); err != nil {
+		return err
+	}
 	return s.queries.DeleteStreams(context.Background(), activityID)
 }
 
@@ -275,6 +512,16 @@ func (s *Store) SaveActivityMetrics(m *ActivityMetrics) error {
 		Hrss:              ptrToNullFloat64(m.HRSS),
 		DataQualityScore:  ptrToNullFloat64(m.DataQualityScore),
 		SteadyStatePct:    ptrToNullFloat64(m.SteadyStatePct),
+		AnomalyFlags:      flagsToNullString(m.AnomalyFlags),
+		PacingSplit:       ptrToNullFloat64(m.PacingSplit),
+		PaceVariability:   ptrToNullFloat64(m.PaceVariability),
+		AvgStrideLength:   ptrToNullFloat64(m.AvgStrideLength),
+		Hrr60:             ptrToNullFloat64(m.HRR60),
+		Z1Seconds:         ptrIntToNullInt64(m.Z1Seconds),
+		Z2Seconds:         ptrIntToNullInt64(m.Z2Seconds),
+		Z3Seconds:         ptrIntToNullInt64(m.Z3Seconds),
+		Z4Seconds:         ptrIntToNullInt64(m.Z4Seconds),
+		Z5Seconds:         ptrIntToNullInt64(m.Z5Seconds),
 	})
 }
 
@@ -299,9 +546,31 @@ func (s *Store) GetActivityMetrics(activityID int64) (*ActivityMetrics, error) {
 		HRSS:              nullFloat64ToPtr(row.Hrss),
 		DataQualityScore:  nullFloat64ToPtr(row.DataQualityScore),
 		SteadyStatePct:    nullFloat64ToPtr(row.SteadyStatePct),
+		AnomalyFlags:      nullStringToFlags(row.AnomalyFlags),
+		PacingSplit:       nullFloat64ToPtr(row.PacingSplit),
+		PaceVariability:   nullFloat64ToPtr(row.PaceVariability),
+		AvgStrideLength:   nullFloat64ToPtr(row.AvgStrideLength),
+		HRR60:             nullFloat64ToPtr(row.Hrr60),
+		Z1Seconds:         nullInt64ToIntPtr(row.Z1Seconds),
+		Z2Seconds:         nullInt64ToIntPtr(row.Z2Seconds),
+		Z3Seconds:         nullInt64ToIntPtr(row.Z3Seconds),
+		Z4Seconds:         nullInt64ToIntPtr(row.Z4Seconds),
+		Z5Seconds:         nullInt64ToIntPtr(row.Z5Seconds),
 	}, nil
 }
 
+// RescaleLegacyHRSS puts HRSS stored when it equaled TRIMP on the current
+// scale, perTRIMP HRSS for each point of TRIMP. It returns how many runs
+// changed; once done there are none left to rescale.
+func (s *Store) RescaleLegacyHRSS(perTRIMP float64) (int, error) {
+	result, err := s.queries.RescaleLegacyHRSS(context.Background(), perTRIMP)
+	if err != nil {
+		return 0, fmt.Errorf("rescaling HRSS: %w", err)
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
 // HasMetrics checks if an activity has computed metrics.
 func (s *Store) HasMetrics(activityID int64) (bool, error) {
 	_, err := s.queries.HasMetrics(context.Background(), activityID)
@@ -334,6 +603,16 @@ func (s *Store) GetAllMetrics() ([]ActivityMetrics, error) {
 			HRSS:              nullFloat64ToPtr(row.Hrss),
 			DataQualityScore:  nullFloat64ToPtr(row.DataQualityScore),
 			SteadyStatePct:    nullFloat64ToPtr(row.SteadyStatePct),
+			AnomalyFlags:      nullStringToFlags(row.AnomalyFlags),
+			PacingSplit:       nullFloat64ToPtr(row.PacingSplit),
+			PaceVariability:   nullFloat64ToPtr(row.PaceVariability),
+			AvgStrideLength:   nullFloat64ToPtr(row.AvgStrideLength),
+			HRR60:             nullFloat64ToPtr(row.Hrr60),
+			Z1Seconds:         nullInt64ToIntPtr(row.Z1Seconds),
+			Z2Seconds:         nullInt64ToIntPtr(row.Z2Seconds),
+			Z3Seconds:         nullInt64ToIntPtr(row.Z3Seconds),
+			Z4Seconds:         nullInt64ToIntPtr(row.Z4Seconds),
+			Z5Seconds:         nullInt64ToIntPtr(row.Z5Seconds),
 		})
 	}
 	return metrics, nil
@@ -345,7 +624,8 @@ func (s *Store) CountMetrics() (int, error) {
 	return int(count), err
 }
 
-// GetActivitiesWithMetrics retrieves activities that have computed metrics.
+// GetActivitiesWithMetrics retrieves activities that have computed metrics,
+// skipping activities excluded from analysis.
 func (s *Store) GetActivitiesWithMetrics(limit, offset int) ([]Activity, []ActivityMetrics, error) {
 	rows, err := s.queries.GetActivitiesWithMetricsRaw(context.Background(), sqlc.GetActivitiesWithMetricsRawParams{
 		Limit:  int64(limit),
@@ -359,55 +639,168 @@ func (s *Store) GetActivitiesWithMetrics(limit, offset int) ([]Activity, []Activ
 	metrics := make([]ActivityMetrics, 0, len(rows))
 
 	for _, row := range rows {
-		startDate, err := time.Parse(time.RFC3339, row.StartDate)
+		a, m, err := activityWithMetricsRowToModels(row)
 		if err != nil {
-			return nil, nil, fmt.Errorf("parsing start_date %q: %w", row.StartDate, err)
+			return nil, nil, err
 		}
-		startDateLocal, err := time.Parse(time.RFC3339, row.StartDateLocal)
+		activities = append(activities, a)
+		metrics = append(metrics, m)
+	}
+
+	return activities, metrics, nil
+}
+
+// GetActivitiesWithMetricsBetween retrieves the activities with computed
+// metrics that started in [start, end) on the day-bucketing clock, newest
+// first, skipping activities excluded from analysis.
+func (s *Store) GetActivitiesWithMetricsBetween(start, end time.Time) ([]Activity, []ActivityMetrics, error) {
+	rows, err := s.queries.GetActivitiesWithMetricsBetween(context.Background(), sqlc.GetActivitiesWithMetricsBetweenParams{
+		UtcDays:   s.utcDays,
+		StartDate: formatBound(start),
+		EndDate:   formatBound(end),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activities := make([]Activity, 0, len(rows))
+	metrics := make([]ActivityMetrics, 0, len(rows))
+
+	for _, row := range rows {
+		a, m, err := activityWithMetricsRowToModels(sqlc.GetActivitiesWithMetricsRawRow(row))
 		if err != nil {
-			return nil, nil, fmt.Errorf("parsing start_date_local %q: %w", row.StartDateLocal, err)
+			return nil, nil, err
 		}
+		activities = append(activities, a)
+		metrics = append(metrics, m)
+	}
 
-		activities = append(activities, Activity{
-			ID:                 row.ID,
-			AthleteID:          row.AthleteID,
-			Name:               row.Name,
-			Type:               row.Type,
-			StartDate:          startDate,
-			StartDateLocal:     startDateLocal,
-			Timezone:           row.Timezone.String,
-			Distance:           row.Distance,
-			MovingTime:         int(row.MovingTime),
-			ElapsedTime:        int(row.ElapsedTime),
-			TotalElevationGain: row.TotalElevationGain.Float64,
-			AverageSpeed:       row.AverageSpeed.Float64,
-			MaxSpeed:           row.MaxSpeed.Float64,
-			AverageHeartrate:   nullFloat64ToPtr(row.AverageHeartrate),
-			MaxHeartrate:       nullFloat64ToPtr(row.MaxHeartrate),
-			AverageCadence:     nullFloat64ToPtr(row.AverageCadence),
-			SufferScore:        nullInt64ToIntPtr(row.SufferScore),
-			HasHeartrate:       row.HasHeartrate == 1,
-			StreamsSynced:      row.StreamsSynced == 1,
-		})
+	return activities, metrics, nil
+}
 
-		metrics = append(metrics, ActivityMetrics{
-			ActivityID:        row.ID,
-			EfficiencyFactor:  nullFloat64ToPtr(row.EfficiencyFactor),
-			AerobicDecoupling: nullFloat64ToPtr(row.AerobicDecoupling),
-			CardiacDrift:      nullFloat64ToPtr(row.CardiacDrift),
-			PaceAtZ1:          nullFloat64ToPtr(row.PaceAtZ1),
-			PaceAtZ2:          nullFloat64ToPtr(row.PaceAtZ2),
-			PaceAtZ3:          nullFloat64ToPtr(row.PaceAtZ3),
-			TRIMP:             nullFloat64ToPtr(row.Trimp),
-			HRSS:              nullFloat64ToPtr(row.Hrss),
-			DataQualityScore:  nullFloat64ToPtr(row.DataQualityScore),
-			SteadyStatePct:    nullFloat64ToPtr(row.SteadyStatePct),
-		})
+// formatBound formats a range bound for comparison with stored start dates.
+// Those are whole seconds, so the bound is rounded up to a whole second to
+// compare as the times themselves would: an activity starting at 10:00:00 is
+// before an end of 10:00:00.5.
+func formatBound(t time.Time) string {
+	if rounded := t.Truncate(time.Second); !rounded.Equal(t) {
+		t = rounded.Add(time.Second)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// SearchActivitiesWithMetrics retrieves activities with computed metrics that
+// match the given filter, in the given order. Ties and missing values fall back
+// to newest first.
+func (s *Store) SearchActivitiesWithMetrics(filter ActivityFilter, order ActivitySort, limit, offset int) ([]Activity, []ActivityMetrics, error) {
+	f := filter.params()
+	rows, err := s.queries.SearchActivitiesWithMetrics(context.Background(), sqlc.SearchActivitiesWithMetricsParams{
+		Name:        f.Name,
+		Type:        f.Type,
+		StartAfter:  f.StartAfter,
+		StartBefore: f.StartBefore,
+		MinDistance: f.MinDistance,
+		MaxDistance: f.MaxDistance,
+		HasPr:       f.HasPr,
+		Tag:         f.Tag,
+		SortAsc:     boolToInt64(order.Ascending),
+		SortBy:      string(order.Field),
+		Limit:       int64(limit),
+		Offset:      int64(offset),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activities := make([]Activity, 0, len(rows))
+	metrics := make([]ActivityMetrics, 0, len(rows))
+
+	for _, row := range rows {
+		a, m, err := activityWithMetricsRowToModels(sqlc.GetActivitiesWithMetricsRawRow(row))
+		if err != nil {
+			return nil, nil, err
+		}
+		activities = append(activities, a)
+		metrics = append(metrics, m)
 	}
 
 	return activities, metrics, nil
 }
 
+// SearchActivitiesByDate retrieves up to limit activities with computed
+// metrics that match the filter, continuing from cursor by start date. older
+// walks back in time and returns newest first; otherwise the activities after
+// cursor come oldest first. A nil cursor starts from the newest or oldest
+// activity. Unlike an offset, the cost doesn't grow with how far in the page is.
+func (s *Store) SearchActivitiesByDate(filter ActivityFilter, cursor *ActivityCursor, older bool, limit int) ([]Activity, []ActivityMetrics, error) {
+	f := filter.params()
+	params := sqlc.SearchActivitiesWithMetricsKeysetParams{
+		Name:        f.Name,
+		Type:        f.Type,
+		StartAfter:  f.StartAfter,
+		StartBefore: f.StartBefore,
+		MinDistance: f.MinDistance,
+		MaxDistance: f.MaxDistance,
+		HasPr:       f.HasPr,
+		Tag:         f.Tag,
+		Older:       boolToInt64(older),
+		Limit:       int64(limit),
+	}
+	if cursor != nil {
+		params.CursorDate = toNullString(cursor.StartDate.Format(time.RFC3339))
+		params.CursorID = cursor.ID
+	}
+	rows, err := s.queries.SearchActivitiesWithMetricsKeyset(context.Background(), params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activities := make([]Activity, 0, len(rows))
+	metrics := make([]ActivityMetrics, 0, len(rows))
+
+	for _, row := range rows {
+		a, m, err := activityWithMetricsRowToModels(sqlc.GetActivitiesWithMetricsRawRow(row))
+		if err != nil {
+			return nil, nil, err
+		}
+		activities = append(activities, a)
+		metrics = append(metrics, m)
+	}
+
+	return activities, metrics, nil
+}
+
+// CountSearchActivitiesWithMetrics returns the number of activities with
+// computed metrics that match the given filter.
+func (s *Store) CountSearchActivitiesWithMetrics(filter ActivityFilter) (int, error) {
+	count, err := s.queries.CountSearchActivitiesWithMetrics(context.Background(), filter.params())
+	return int(count), err
+}
+
+// params converts the filter to sqlc parameters; zero-valued fields are left NULL
+// so the query ignores them.
+func (f ActivityFilter) params() sqlc.CountSearchActivitiesWithMetricsParams {
+	p := sqlc.CountSearchActivitiesWithMetricsParams{
+		Name:  toNullString(f.Name),
+		Type:  toNullString(f.Type),
+		HasPr: boolToInt64(f.HasPR),
+		Tag:   toNullString(f.Tag),
+	}
+	if !f.After.IsZero() {
+		p.StartAfter = toNullString(f.After.Format(time.RFC3339))
+	}
+	if !f.Before.IsZero() {
+		p.StartBefore = toNullString(f.Before.Format(time.RFC3339))
+	}
+	if f.MinDistance > 0 {
+		p.MinDistance = toNullFloat64(f.MinDistance)
+	}
+	if f.MaxDistance > 0 {
+		p.MaxDistance = toNullFloat64(f.MaxDistance)
+	}
+	return p
+}
+
 // --- Personal Records Methods ---
 
 // GetPersonalRecordByCategory retrieves a personal record by category.
@@ -456,8 +849,19 @@ func (s *Store) GetPersonalRecordsForActivity(activityID int64) ([]PersonalRecor
 	return records, nil
 }
 
-// DeletePersonalRecordsForActivity removes all PRs associated with an activity.
+// DeletePersonalRecordsForActivity removes all PRs associated with an
+// activity, along with its entries in the PR history.
 func (s *Store) DeletePersonalRecordsForActivity(activityID int64) error {
+	if err := s.queries.DeletePersonalRecordsForActivity(context.Background(), activityID); err != nil {
+		return err
+	}
+	return s.deletePRHistoryForActivity(activityID)
+}
+
+// VacatePersonalRecordsForActivity removes the PRs an activity holds but
+// keeps its PR history entries, so the next sync finds the categories stale
+// and recomputes them from the other activities.
+func (s *Store) VacatePersonalRecordsForActivity(activityID int64) error {
 	return s.queries.DeletePersonalRecordsForActivity(context.Background(), activityID)
 }
 
@@ -468,7 +872,12 @@ func (s *Store) UpsertPersonalRecord(pr *PersonalRecord) (updated bool, err erro
 }
 
 // UpsertPersonalRecordWithMode inserts or updates a personal record with the specified comparison mode.
+// Every call also offers pr to the category's PR history.
 func (s *Store) UpsertPersonalRecordWithMode(pr *PersonalRecord, mode CompareMode) (updated bool, err error) {
+	if err := s.recordPRHistory(pr, mode); err != nil {
+		return false, fmt.Errorf("recording PR history: %w", err)
+	}
+
 	existing, err := s.GetPersonalRecordByCategory(pr.Category)
 	if err != nil && !errors.Is(err, ErrPersonalRecordNotFound) {
 		return false, err
@@ -501,6 +910,7 @@ func (s *Store) UpsertPersonalRecordWithMode(pr *PersonalRecord, mode CompareMod
 		AchievedAt:      pr.AchievedAt.Format(time.RFC3339),
 		StartOffset:     ptrIntToNullInt64(pr.StartOffset),
 		EndOffset:       ptrIntToNullInt64(pr.EndOffset),
+		Unverified:      boolToInt64(pr.Unverified),
 	})
 	if err != nil {
 		return false, err
@@ -508,9 +918,31 @@ func (s *Store) UpsertPersonalRecordWithMode(pr *PersonalRecord, mode CompareMod
 	return true, nil
 }
 
-// GetPreviousRecord retrieves the previous record for a category before a given activity.
+// GetPreviousRecord retrieves the record a given activity improved on in a
+// category, from the PR history. It returns nil when the activity isn't in
+// the history or set the first record.
 func (s *Store) GetPreviousRecord(category string, currentActivityID int64) (*PersonalRecord, error) {
-	// Since we only keep the best record, we can't get the previous one.
+	entries, err := s.GetPRHistory(category)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range entries {
+		if e.ActivityID != currentActivityID {
+			continue
+		}
+		if i == 0 {
+			return nil, nil
+		}
+		prev := entries[i-1]
+		return &PersonalRecord{
+			Category:        prev.Category,
+			ActivityID:      prev.ActivityID,
+			DistanceMeters:  prev.DistanceMeters,
+			DurationSeconds: prev.DurationSeconds,
+			PacePerMile:     prev.PacePerMile,
+			AchievedAt:      prev.AchievedAt,
+		}, nil
+	}
 	return nil, nil
 }
 
@@ -594,6 +1026,421 @@ func (s *Store) DeleteAllRacePredictions() error {
 	return s.queries.DeleteAllRacePredictions(context.Background())
 }
 
+// --- Tags, Notes and Weather Methods ---
+
+// GetActivityTags returns the tags on an activity in alphabetical order.
+func (s *Store) GetActivityTags(activityID int64) ([]string, error) {
+	return s.queries.GetActivityTags(context.Background(), activityID)
+}
+
+// SetActivityTags replaces the tags on an activity.
+func (s *Store) SetActivityTags(activityID int64, tags []string) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteActivityTags(context.Background(), activityID); err != nil {
+			return fmt.Errorf("deleting existing tags: %w", err)
+		}
+		for _, tag := range tags {
+			if err := qtx.AddActivityTag(context.Background(), sqlc.AddActivityTagParams{
+				ActivityID: activityID,
+				Tag:        tag,
+			}); err != nil {
+				return fmt.Errorf("adding tag %q: %w", tag, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetAllActivityTags returns the tags on every tagged activity in
+// alphabetical order, keyed by activity ID.
+func (s *Store) GetAllActivityTags() (map[int64][]string, error) {
+	rows, err := s.queries.ListActivityTags(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[int64][]string)
+	for _, row := range rows {
+		tags[row.ActivityID] = append(tags[row.ActivityID], row.Tag)
+	}
+	return tags, nil
+}
+
+// GetActivityNotes returns every note, keyed by activity ID.
+func (s *Store) GetActivityNotes() (map[int64]string, error) {
+	rows, err := s.queries.ListActivityNotes(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	notes := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		notes[row.ActivityID] = row.Note
+	}
+	return notes, nil
+}
+
+// GetActivityNote returns the note on an activity, or "" if there is none.
+func (s *Store) GetActivityNote(activityID int64) (string, error) {
+	note, err := s.queries.GetActivityNote(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return note, err
+}
+
+// SetActivityNote saves the note on an activity. An empty note deletes it.
+func (s *Store) SetActivityNote(activityID int64, note string) error {
+	if note == "" {
+		return s.queries.DeleteActivityNote(context.Background(), activityID)
+	}
+	return s.queries.SetActivityNote(context.Background(), sqlc.SetActivityNoteParams{
+		ActivityID: activityID,
+		Note:       note,
+	})
+}
+
+// TemperatureSourceManual marks a temperature entered by hand
+const TemperatureSourceManual = "manual"
+
+// GetActivityTemperature returns the temperature during an activity in
+// degrees Celsius, or nil if none is recorded.
+func (s *Store) GetActivityTemperature(activityID int64) (*float64, error) {
+	temp, err := s.queries.GetActivityTemperature(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &temp, nil
+}
+
+// GetActivityTemperatures returns every recorded temperature, keyed by
+// activity ID.
+func (s *Store) GetActivityTemperatures() (map[int64]float64, error) {
+	rows, err := s.queries.ListActivityTemperatures(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	temps := make(map[int64]float64, len(rows))
+	for _, row := range rows {
+		temps[row.ActivityID] = row.TemperatureC
+	}
+	return temps, nil
+}
+
+// SetActivityTemperature records the temperature during an activity in
+// degrees Celsius. A nil temperature deletes it.
+func (s *Store) SetActivityTemperature(activityID int64, tempC *float64, source string) error {
+	if tempC == nil {
+		return s.queries.DeleteActivityTemperature(context.Background(), activityID)
+	}
+	return s.queries.SetActivityTemperature(context.Background(), sqlc.SetActivityTemperatureParams{
+		ActivityID:   activityID,
+		TemperatureC: *tempC,
+		Source:       source,
+	})
+}
+
+// MinRPE and MaxRPE bound a perceived effort on the 1-10 CR10 scale, and
+// MinFeel and MaxFeel how a run felt, from awful to great
+const (
+	MinRPE  = 1
+	MaxRPE  = 10
+	MinFeel = 1
+	MaxFeel = 5
+)
+
+// GetActivityRPE returns the perceived effort logged for an activity, or nil
+// if none is.
+func (s *Store) GetActivityRPE(activityID int64) (*ActivityRPE, error) {
+	row, err := s.queries.GetActivityRPE(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ActivityRPE{RPE: int(row.Rpe), Feel: nullInt64ToIntPtr(row.Feel)}, nil
+}
+
+// GetActivityRPEs returns every logged perceived effort, keyed by activity ID.
+func (s *Store) GetActivityRPEs() (map[int64]ActivityRPE, error) {
+	rows, err := s.queries.ListActivityRPEs(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	rpes := make(map[int64]ActivityRPE, len(rows))
+	for _, row := range rows {
+		rpes[row.ActivityID] = ActivityRPE{RPE: int(row.Rpe), Feel: nullInt64ToIntPtr(row.Feel)}
+	}
+	return rpes, nil
+}
+
+// SetActivityRPE logs the perceived effort of an activity, and optionally how
+// it felt. A nil entry deletes it.
+func (s *Store) SetActivityRPE(activityID int64, rpe *ActivityRPE) error {
+	if rpe == nil {
+		return s.queries.DeleteActivityRPE(context.Background(), activityID)
+	}
+	if rpe.RPE < MinRPE || rpe.RPE > MaxRPE {
+		return fmt.Errorf("RPE must be between %d and %d, got %d", MinRPE, MaxRPE, rpe.RPE)
+	}
+	if rpe.Feel != nil && (*rpe.Feel < MinFeel || *rpe.Feel > MaxFeel) {
+		return fmt.Errorf("feel must be between %d and %d, got %d", MinFeel, MaxFeel, *rpe.Feel)
+	}
+	return s.queries.SetActivityRPE(context.Background(), sqlc.SetActivityRPEParams{
+		ActivityID: activityID,
+		Rpe:        int64(rpe.RPE),
+		Feel:       ptrIntToNullInt64(rpe.Feel),
+	})
+}
+
+// --- Weekly Summary Methods ---
+
+// weekStartFormat is how weekly_summaries.week_start is stored
+const weekStartFormat = "2006-01-02"
+
+// SaveWeeklySummary inserts or replaces the summary for a week.
+func (s *Store) SaveWeeklySummary(w *WeeklySummary) error {
+	return s.queries.SaveWeeklySummary(context.Background(), sqlc.SaveWeeklySummaryParams{
+		WeekStart:      w.WeekStart.Format(weekStartFormat),
+		RunCount:       int64(w.RunCount),
+		Distance:       w.Distance,
+		MovingTime:     int64(w.MovingTime),
+		MovingDistance: w.MovingDistance,
+		HrSum:          w.HRSum,
+		HrCount:        int64(w.HRCount),
+		CadenceSum:     w.CadenceSum,
+		CadenceCount:   int64(w.CadenceCount),
+		Trimp:          w.TRIMP,
+		ElevationGain:  w.ElevationGain,
+	})
+}
+
+// GetWeeklySummaries returns the stored summaries for weeks starting between
+// from and to (inclusive), oldest first. Weeks without runs have no row.
+func (s *Store) GetWeeklySummaries(from, to time.Time) ([]WeeklySummary, error) {
+	rows, err := s.queries.GetWeeklySummaries(context.Background(), sqlc.GetWeeklySummariesParams{
+		FromWeek: from.Format(weekStartFormat),
+		ToWeek:   to.Format(weekStartFormat),
+	})
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]WeeklySummary, 0, len(rows))
+	for _, row := range rows {
+		weekStart, err := time.Parse(weekStartFormat, row.WeekStart)
+		if err != nil {
+			return nil, fmt.Errorf("parsing week_start %q: %w", row.WeekStart, err)
+		}
+		summaries = append(summaries, WeeklySummary{
+			WeekStart:      weekStart,
+			RunCount:       int(row.RunCount),
+			Distance:       row.Distance,
+			MovingTime:     int(row.MovingTime),
+			MovingDistance: row.MovingDistance,
+			HRSum:          row.HrSum,
+			HRCount:        int(row.HrCount),
+			CadenceSum:     row.CadenceSum,
+			CadenceCount:   int(row.CadenceCount),
+			TRIMP:          row.Trimp,
+			ElevationGain:  row.ElevationGain,
+		})
+	}
+	return summaries, nil
+}
+
+// DeleteWeeklySummary removes the summary for a week.
+func (s *Store) DeleteWeeklySummary(weekStart time.Time) error {
+	return s.queries.DeleteWeeklySummary(context.Background(), weekStart.Format(weekStartFormat))
+}
+
+// DeleteAllWeeklySummaries removes every stored weekly summary, so they are
+// refilled on next use.
+func (s *Store) DeleteAllWeeklySummaries() error {
+	return s.queries.DeleteAllWeeklySummaries(context.Background())
+}
+
+// CountWeeklySummaries returns the number of stored weekly summaries.
+func (s *Store) CountWeeklySummaries() (int, error) {
+	count, err := s.queries.CountWeeklySummaries(context.Background())
+	return int(count), err
+}
+
+// GetWeekActivities returns the analyzed, non-excluded activities that started
+// in [start, end) on the day-bucketing clock.
+func (s *Store) GetWeekActivities(start, end time.Time) ([]WeekActivity, error) {
+	rows, err := s.queries.GetWeekActivities(context.Background(), sqlc.GetWeekActivitiesParams{
+		UtcDays:   s.utcDays,
+		WeekStart: start.UTC().Format(time.RFC3339),
+		WeekEnd:   end.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	activities := make([]WeekActivity, 0, len(rows))
+	for _, row := range rows {
+		activities = append(activities, WeekActivity{
+			ID:            row.ID,
+			Distance:      row.Distance,
+			ElevationGain: row.TotalElevationGain.Float64,
+			TRIMP:         nullFloat64ToPtr(row.Trimp),
+			StreamStats:   weekActivityStreamStats(row),
+		})
+	}
+	return activities, nil
+}
+
+// weekActivityStreamStats returns the cached stream aggregates joined to a
+// week activity, or nil if they are missing or predate max HR
+func weekActivityStreamStats(row sqlc.GetWeekActivitiesRow) *ActivityStreamStats {
+	if !row.MaxHr.Valid {
+		return nil
+	}
+	return &ActivityStreamStats{
+		ActivityID:     row.ID,
+		MovingTime:     int(row.MovingTime.Int64),
+		MovingDistance: row.MovingDistance.Float64,
+		HRSum:          row.HrSum.Float64,
+		HRCount:        int(row.HrCount.Int64),
+		CadenceSum:     row.CadenceSum.Float64,
+		CadenceCount:   int(row.CadenceCount.Int64),
+		MaxHR:          int(row.MaxHr.Int64),
+	}
+}
+
+// ListAnalyzedStartDates returns the start time of every analyzed,
+// non-excluded activity on the day-bucketing clock, oldest first.
+func (s *Store) ListAnalyzedStartDates() ([]time.Time, error) {
+	rows, err := s.queries.ListAnalyzedStartDates(context.Background(), s.utcDays)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]time.Time, 0, len(rows))
+	for _, row := range rows {
+		startDate, err := time.Parse(time.RFC3339, row)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start_date %q: %w", row, err)
+		}
+		dates = append(dates, startDate)
+	}
+	return dates, nil
+}
+
+// --- Fitness Trend Methods ---
+
+// ListTrainingLoads returns every analyzed, non-excluded run's date,
+// distance, time and TRIMP, oldest first.
+func (s *Store) ListTrainingLoads() ([]TrainingLoad, error) {
+	rows, err := s.queries.ListTrainingLoads(context.Background(), s.utcDays)
+	if err != nil {
+		return nil, err
+	}
+	loads := make([]TrainingLoad, 0, len(rows))
+	for _, row := range rows {
+		startDate, err := time.Parse(time.RFC3339, row.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start_date %q: %w", row.StartDate, err)
+		}
+		bucketDate, err := time.Parse(time.RFC3339, row.BucketDate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start_date_local %q: %w", row.BucketDate, err)
+		}
+		loads = append(loads, TrainingLoad{
+			StartDate:  startDate,
+			BucketDate: bucketDate,
+			Distance:   row.Distance,
+			MovingTime: int(row.MovingTime),
+			TRIMP:      nullFloat64ToPtr(row.Trimp),
+			HRSS:       nullFloat64ToPtr(row.Hrss),
+			RPE:        nullInt64ToIntPtr(row.Rpe),
+		})
+	}
+	return loads, nil
+}
+
+// ReplaceFitnessTrends replaces every stored daily fitness trend.
+func (s *Store) ReplaceFitnessTrends(trends []FitnessTrend) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteFitnessTrends(context.Background()); err != nil {
+			return fmt.Errorf("deleting fitness trends: %w", err)
+		}
+		for _, t := range trends {
+			if err := qtx.InsertFitnessTrend(context.Background(), sqlc.InsertFitnessTrendParams{
+				Date:            t.Date,
+				Ctl:             ptrToNullFloat64(t.CTL),
+				Atl:             ptrToNullFloat64(t.ATL),
+				Tsb:             ptrToNullFloat64(t.TSB),
+				RunCount7d:      sql.NullInt64{Int64: int64(t.RunCount7d), Valid: true},
+				TotalDistance7d: toNullFloat64(t.TotalDistance7d),
+				TotalTime7d:     sql.NullInt64{Int64: int64(t.TotalTime7d), Valid: true},
+				Monotony7d:      ptrToNullFloat64(t.Monotony7d),
+				Strain7d:        ptrToNullFloat64(t.Strain7d),
+			}); err != nil {
+				return fmt.Errorf("saving fitness trend for %s: %w", t.Date, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetLatestFitnessTrend returns the most recent daily fitness trend, or nil
+// if none have been computed.
+func (s *Store) GetLatestFitnessTrend() (*FitnessTrend, error) {
+	row, err := s.queries.GetLatestFitnessTrend(context.Background())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &FitnessTrend{
+		Date:                row.Date,
+		CTL:                 nullFloat64ToPtr(row.Ctl),
+		ATL:                 nullFloat64ToPtr(row.Atl),
+		TSB:                 nullFloat64ToPtr(row.Tsb),
+		EfficiencyFactor7d:  nullFloat64ToPtr(row.EfficiencyFactor7d),
+		EfficiencyFactor28d: nullFloat64ToPtr(row.EfficiencyFactor28d),
+		EfficiencyFactor90d: nullFloat64ToPtr(row.EfficiencyFactor90d),
+		RunCount7d:          int(row.RunCount7d.Int64),
+		TotalDistance7d:     row.TotalDistance7d.Float64,
+		TotalTime7d:         int(row.TotalTime7d.Int64),
+		Monotony7d:          nullFloat64ToPtr(row.Monotony7d),
+		Strain7d:            nullFloat64ToPtr(row.Strain7d),
+	}, nil
+}
+
+// GetFitnessTrends returns the daily fitness trends from the day of since
+// on, oldest first.
+func (s *Store) GetFitnessTrends(since time.Time) ([]FitnessTrend, error) {
+	rows, err := s.queries.GetFitnessTrendsSince(context.Background(), since.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	trends := make([]FitnessTrend, 0, len(rows))
+	for _, row := range rows {
+		trends = append(trends, FitnessTrend{
+			Date:                row.Date,
+			CTL:                 nullFloat64ToPtr(row.Ctl),
+			ATL:                 nullFloat64ToPtr(row.Atl),
+			TSB:                 nullFloat64ToPtr(row.Tsb),
+			EfficiencyFactor7d:  nullFloat64ToPtr(row.EfficiencyFactor7d),
+			EfficiencyFactor28d: nullFloat64ToPtr(row.EfficiencyFactor28d),
+			EfficiencyFactor90d: nullFloat64ToPtr(row.EfficiencyFactor90d),
+			RunCount7d:          int(row.RunCount7d.Int64),
+			TotalDistance7d:     row.TotalDistance7d.Float64,
+			TotalTime7d:         int(row.TotalTime7d.Int64),
+			Monotony7d:          nullFloat64ToPtr(row.Monotony7d),
+			Strain7d:            nullFloat64ToPtr(row.Strain7d),
+		})
+	}
+	return trends, nil
+}
+
 // --- Conversion Helpers ---
 
 func boolToInt64(b bool) int64 {
@@ -628,6 +1475,18 @@ func ptrIntToNullInt64(i *int) sql.NullInt64 {
 	return sql.NullInt64{Int64: int64(*i), Valid: true}
 }
 
+// flagsToNullString stores anomaly flags as a comma separated list
+func flagsToNullString(flags []string) sql.NullString {
+	return toNullString(strings.Join(flags, ","))
+}
+
+func nullStringToFlags(n sql.NullString) []string {
+	if !n.Valid || n.String == "" {
+		return nil
+	}
+	return strings.Split(n.String, ",")
+}
+
 func nullFloat64ToPtr(n sql.NullFloat64) *float64 {
 	if !n.Valid {
 		return nil
@@ -643,6 +1502,69 @@ func nullInt64ToIntPtr(n sql.NullInt64) *int {
 	return &v
 }
 
+// activityWithMetricsRowToModels converts a joined activity/metrics row to its
+// Activity and ActivityMetrics parts.
+func activityWithMetricsRowToModels(row sqlc.GetActivitiesWithMetricsRawRow) (Activity, ActivityMetrics, error) {
+	startDate, err := time.Parse(time.RFC3339, row.StartDate)
+	if err != nil {
+		return Activity{}, ActivityMetrics{}, fmt.Errorf("parsing start_date %q: %w", row.StartDate, err)
+	}
+	startDateLocal, err := time.Parse(time.RFC3339, row.StartDateLocal)
+	if err != nil {
+		return Activity{}, ActivityMetrics{}, fmt.Errorf("parsing start_date_local %q: %w", row.StartDateLocal, err)
+	}
+
+	a := Activity{
+		ID:                 row.ID,
+		AthleteID:          row.AthleteID,
+		Name:               row.Name,
+		Type:               row.Type,
+		StartDate:          startDate,
+		StartDateLocal:     startDateLocal,
+		Timezone:           row.Timezone.String,
+		Distance:           row.Distance,
+		MovingTime:         int(row.MovingTime),
+		ElapsedTime:        int(row.ElapsedTime),
+		TotalElevationGain: row.TotalElevationGain.Float64,
+		AverageSpeed:       row.AverageSpeed.Float64,
+		MaxSpeed:           row.MaxSpeed.Float64,
+		AverageHeartrate:   nullFloat64ToPtr(row.AverageHeartrate),
+		MaxHeartrate:       nullFloat64ToPtr(row.MaxHeartrate),
+		AverageCadence:     nullFloat64ToPtr(row.AverageCadence),
+		SufferScore:        nullInt64ToIntPtr(row.SufferScore),
+		HasHeartrate:       row.HasHeartrate == 1,
+		StreamsSynced:      row.StreamsSynced == 1,
+		Manual:             row.Manual == 1,
+		Excluded:           row.Excluded == 1,
+	}
+
+	m := ActivityMetrics{
+		ActivityID:        row.ID,
+		EfficiencyFactor:  nullFloat64ToPtr(row.EfficiencyFactor),
+		AerobicDecoupling: nullFloat64ToPtr(row.AerobicDecoupling),
+		CardiacDrift:      nullFloat64ToPtr(row.CardiacDrift),
+		PaceAtZ1:          nullFloat64ToPtr(row.PaceAtZ1),
+		PaceAtZ2:          nullFloat64ToPtr(row.PaceAtZ2),
+		PaceAtZ3:          nullFloat64ToPtr(row.PaceAtZ3),
+		TRIMP:             nullFloat64ToPtr(row.Trimp),
+		HRSS:              nullFloat64ToPtr(row.Hrss),
+		DataQualityScore:  nullFloat64ToPtr(row.DataQualityScore),
+		SteadyStatePct:    nullFloat64ToPtr(row.SteadyStatePct),
+		AnomalyFlags:      nullStringToFlags(row.AnomalyFlags),
+		PacingSplit:       nullFloat64ToPtr(row.PacingSplit),
+		PaceVariability:   nullFloat64ToPtr(row.PaceVariability),
+		AvgStrideLength:   nullFloat64ToPtr(row.AvgStrideLength),
+		HRR60:             nullFloat64ToPtr(row.Hrr60),
+		Z1Seconds:         nullInt64ToIntPtr(row.Z1Seconds),
+		Z2Seconds:         nullInt64ToIntPtr(row.Z2Seconds),
+		Z3Seconds:         nullInt64ToIntPtr(row.Z3Seconds),
+		Z4Seconds:         nullInt64ToIntPtr(row.Z4Seconds),
+		Z5Seconds:         nullInt64ToIntPtr(row.Z5Seconds),
+	}
+
+	return a, m, nil
+}
+
 // activityRowToActivity converts a GetActivityRow to an Activity.
 func activityRowToActivity(row sqlc.GetActivityRow) (*Activity, error) {
 	startDate, err := time.Parse(time.RFC3339, row.StartDate)
@@ -673,6 +1595,8 @@ func activityRowToActivity(row sqlc.GetActivityRow) (*Activity, error) {
 		SufferScore:        nullInt64ToIntPtr(row.SufferScore),
 		HasHeartrate:       row.HasHeartrate == 1,
 		StreamsSynced:      row.StreamsSynced == 1,
+		Manual:             row.Manual == 1,
+		Excluded:           row.Excluded == 1,
 	}, nil
 }
 
@@ -705,6 +1629,8 @@ func listActivityRowToActivity(row sqlc.ListActivitiesRow) (*Activity, error) {
 		SufferScore:        nullInt64ToIntPtr(row.SufferScore),
 		HasHeartrate:       row.HasHeartrate == 1,
 		StreamsSynced:      row.StreamsSynced == 1,
+		Manual:             row.Manual == 1,
+		Excluded:           row.Excluded == 1,
 	}, nil
 }
 
@@ -803,5 +1729,6 @@ func personalRecordRowToPersonalRecord(row sqlc.PersonalRecord) (*PersonalRecord
 		AchievedAt:      achievedAt,
 		StartOffset:     nullInt64ToIntPtr(row.StartOffset),
 		EndOffset:       nullInt64ToIntPtr(row.EndOffset),
+		Unverified:      row.Unverified != 0,
 	}, nil
 }
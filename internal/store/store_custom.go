@@ -2,27 +2,57 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"runner/internal/store/sqlc"
 )
 
-// GetActivitiesByIDs retrieves multiple activities by their IDs.
-// Returns a map of activity ID to activity for easy lookup.
-// This method uses dynamic SQL for the IN clause, which sqlc cannot generate.
-func (s *Store) GetActivitiesByIDs(ids []int64) (map[int64]*Activity, error) {
-	if len(ids) == 0 {
-		return make(map[int64]*Activity), nil
+// maxQueryIDs is how many IDs go in one IN clause. SQLite builds before
+// 3.32 refuse statements with more than 999 variables, so larger ID sets are
+// queried in chunks.
+const maxQueryIDs = 500
+
+// chunkIDs splits ids into slices of at most maxQueryIDs
+func chunkIDs(ids []int64) [][]int64 {
+	var chunks [][]int64
+	for len(ids) > 0 {
+		n := min(maxQueryIDs, len(ids))
+		chunks = append(chunks, ids[:n])
+		ids = ids[n:]
 	}
+	return chunks
+}
 
-	// Build query with placeholders
+// inClause returns the placeholders and args for an IN clause over ids
+func inClause(ids []int64) (string, []interface{}) {
 	placeholders := make([]string, len(ids))
 	args := make([]interface{}, len(ids))
 	for i, id := range ids {
 		placeholders[i] = "?"
 		args[i] = id
 	}
+	return joinStrings(placeholders, ", "), args
+}
+
+// GetActivitiesByIDs retrieves multiple activities by their IDs.
+// Returns a map of activity ID to activity for easy lookup.
+// This method uses dynamic SQL for the IN clause, which sqlc cannot generate.
+func (s *Store) GetActivitiesByIDs(ids []int64) (map[int64]*Activity, error) {
+	result := make(map[int64]*Activity)
+	for _, chunk := range chunkIDs(ids) {
+		if err := s.getActivitiesByIDs(chunk, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// getActivitiesByIDs adds one chunk of activities to result
+func (s *Store) getActivitiesByIDs(ids []int64, result map[int64]*Activity) error {
+	placeholders, args := inClause(ids)
 
 	query := `
 		SELECT id, athlete_id, name, type, start_date, start_date_local, timezone,
@@ -30,15 +60,14 @@ func (s *Store) GetActivitiesByIDs(ids []int64) (map[int64]*Activity, error) {
 			average_speed, max_speed, average_heartrate, max_heartrate,
 			average_cadence, suffer_score, has_heartrate, streams_synced
 		FROM activities
-		WHERE id IN (` + joinStrings(placeholders, ",") + `)`
+		WHERE id IN (` + placeholders + `)`
 
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	result := make(map[int64]*Activity)
 	for rows.Next() {
 		var a Activity
 		var startDate, startDateLocal string
@@ -54,17 +83,17 @@ func (s *Store) GetActivitiesByIDs(ids []int64) (map[int64]*Activity, error) {
 			&avgCadence, &sufferScore, &hasHR, &streamsSynced,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		var parseErr error
 		a.StartDate, parseErr = time.Parse(time.RFC3339, startDate)
 		if parseErr != nil {
-			return nil, fmt.Errorf("parsing start_date %q: %w", startDate, parseErr)
+			return fmt.Errorf("parsing start_date %q: %w", startDate, parseErr)
 		}
 		a.StartDateLocal, parseErr = time.Parse(time.RFC3339, startDateLocal)
 		if parseErr != nil {
-			return nil, fmt.Errorf("parsing start_date_local %q: %w", startDateLocal, parseErr)
+			return fmt.Errorf("parsing start_date_local %q: %w", startDateLocal, parseErr)
 		}
 
 		if timezone != nil {
@@ -92,41 +121,63 @@ func (s *Store) GetActivitiesByIDs(ids []int64) (map[int64]*Activity, error) {
 		result[a.ID] = &a
 	}
 
-	return result, rows.Err()
+	return rows.Err()
 }
 
-// GetStreamsForActivities retrieves stream points for multiple activities in a single query.
+// GetStreamsForActivities retrieves stream points for multiple activities,
+// with one query per maxQueryIDs activities.
 // Returns a map from activity ID to stream points, sorted by time offset.
 // This method uses dynamic SQL for the IN clause, which sqlc cannot generate.
 func (s *Store) GetStreamsForActivities(activityIDs []int64) (map[int64][]StreamPoint, error) {
-	if len(activityIDs) == 0 {
-		return make(map[int64][]StreamPoint), nil
+	result := make(map[int64][]StreamPoint)
+	for _, chunk := range chunkIDs(activityIDs) {
+		if err := s.getStreamsForActivities(chunk, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// getStreamsForActivities adds one chunk of activities' streams to result
+func (s *Store) getStreamsForActivities(activityIDs []int64, result map[int64][]StreamPoint) error {
+	placeholders, args := inClause(activityIDs)
+
+	// Compressed streams first
+	blobRows, err := s.db.Query(`SELECT activity_id, data FROM stream_blobs WHERE activity_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return err
+	}
+	defer blobRows.Close()
+
+	for blobRows.Next() {
+		var id int64
+		var data []byte
+		if err := blobRows.Scan(&id, &data); err != nil {
+			return err
+		}
+		points, err := decodeStreams(id, data)
+		if err != nil {
+			return fmt.Errorf("decoding streams for %d: %w", id, err)
+		}
+		result[id] = points
+	}
+	if err := blobRows.Err(); err != nil {
+		return err
 	}
+	blobRows.Close()
 
-	// Build query with placeholders
 	query := `
 		SELECT activity_id, time_offset, latlng_lat, latlng_lng, altitude,
 			velocity_smooth, heartrate, cadence, grade_smooth, distance
 		FROM streams
-		WHERE activity_id IN (`
-
-	args := make([]interface{}, len(activityIDs))
-	for i, id := range activityIDs {
-		if i > 0 {
-			query += ", "
-		}
-		query += "?"
-		args[i] = id
-	}
-	query += `) ORDER BY activity_id, time_offset`
+		WHERE activity_id IN (` + placeholders + `) ORDER BY activity_id, time_offset`
 
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	result := make(map[int64][]StreamPoint)
 	for rows.Next() {
 		var p StreamPoint
 		err := rows.Scan(
@@ -134,58 +185,158 @@ func (s *Store) GetStreamsForActivities(activityIDs []int64) (map[int64][]Stream
 			&p.VelocitySmooth, &p.Heartrate, &p.Cadence, &p.GradeSmooth, &p.Distance,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		result[p.ActivityID] = append(result[p.ActivityID], p)
 	}
 
-	return result, rows.Err()
+	return rows.Err()
 }
 
 // SaveStreams saves stream data for an activity.
-// It replaces any existing stream data for the activity.
-// This method uses transactions and prepared statements for efficiency.
+// It replaces any existing stream data for the activity, in either format.
+// Points are stored as a compressed blob when stream compression is on,
+// otherwise one row per sample, inserted in multi-row batches. Either way
+// it's one transaction per activity.
 func (s *Store) SaveStreams(activityID int64, points []StreamPoint) error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return fmt.Errorf("beginning transaction: %w", err)
+	return s.writeTx(func(tx *sql.Tx) error {
+		// Use sqlc's WithTx for the deletes
+		qtx := s.queries.WithTx(tx)
+		if err := qtx.DeleteStreamsForActivity(context.Background(), activityID); err != nil {
+			return fmt.Errorf("deleting existing streams: %w", err)
+		}
+		if err := qtx.DeleteStreamBlob(context.Background(), activityID); err != nil {
+			return fmt.Errorf("deleting existing stream blob: %w", err)
+		}
+
+		if s.compressStreams {
+			data, err := encodeStreams(points)
+			if err != nil {
+				return fmt.Errorf("encoding streams: %w", err)
+			}
+			if err := qtx.SaveStreamBlob(context.Background(), sqlc.SaveStreamBlobParams{
+				ActivityID: activityID,
+				PointCount: int64(len(points)),
+				Data:       data,
+			}); err != nil {
+				return fmt.Errorf("saving stream blob: %w", err)
+			}
+			return nil
+		}
+
+		return insertStreamRows(tx, points, streamInsertBatch)
+	})
+}
+
+// streamInsertBatch is how many stream rows go into one INSERT. Batching
+// saves a statement execution per row, but the driver matches each bound
+// parameter against the whole argument list, so binding cost grows with the
+// square of the batch; BenchmarkSaveStreams found 20 rows fastest, clearly
+// ahead of a row at a time and of batches near SQLite's 999-variable limit.
+const streamInsertBatch = 20
+
+// streamColumns are the values bound for each stream row
+const streamColumns = 10
+
+// insertStreamRows inserts points as rows, batchSize to a statement. Full
+// batches reuse one prepared statement; the remainder gets its own.
+func insertStreamRows(tx *sql.Tx, points []StreamPoint, batchSize int) error {
+	full := len(points) / batchSize
+	if full > 0 {
+		stmt, err := tx.Prepare(streamInsertSQL(batchSize))
+		if err != nil {
+			return fmt.Errorf("preparing statement: %w", err)
+		}
+		defer stmt.Close()
+
+		args := make([]any, 0, batchSize*streamColumns)
+		for i := range full {
+			args = appendStreamArgs(args[:0], points[i*batchSize:(i+1)*batchSize])
+			if _, err := stmt.Exec(args...); err != nil {
+				return fmt.Errorf("inserting stream points: %w", err)
+			}
+		}
 	}
-	defer tx.Rollback()
 
-	// Use sqlc's WithTx for the delete
-	qtx := s.queries.WithTx(tx)
-	if err := qtx.DeleteStreamsForActivity(context.Background(), activityID); err != nil {
-		return fmt.Errorf("deleting existing streams: %w", err)
+	if rest := points[full*batchSize:]; len(rest) > 0 {
+		if _, err := tx.Exec(streamInsertSQL(len(rest)), appendStreamArgs(nil, rest)...); err != nil {
+			return fmt.Errorf("inserting stream points: %w", err)
+		}
 	}
+	return nil
+}
 
-	// Prepare insert statement for batch efficiency
-	stmt, err := tx.Prepare(`
-		INSERT INTO streams (
-			activity_id, time_offset, latlng_lat, latlng_lng, altitude,
-			velocity_smooth, heartrate, cadence, grade_smooth, distance
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`)
-	if err != nil {
-		return fmt.Errorf("preparing statement: %w", err)
+// streamInsertSQL returns an INSERT of rows stream rows
+func streamInsertSQL(rows int) string {
+	var b strings.Builder
+	b.WriteString(`INSERT INTO streams (
+		activity_id, time_offset, latlng_lat, latlng_lng, altitude,
+		velocity_smooth, heartrate, cadence, grade_smooth, distance
+	) VALUES `)
+	for i := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	}
-	defer stmt.Close()
+	return b.String()
+}
 
-	// Insert all points
+// appendStreamArgs appends each point's values in streamInsertSQL's order.
+// Optional values go in as plain numbers or nil: database/sql converts
+// pointers by reflection, which costs more than the insert for narrow rows.
+func appendStreamArgs(args []any, points []StreamPoint) []any {
 	for _, p := range points {
-		_, err := stmt.Exec(
-			p.ActivityID, p.TimeOffset, p.Lat, p.Lng, p.Altitude,
-			p.VelocitySmooth, p.Heartrate, p.Cadence, p.GradeSmooth, p.Distance,
+		args = append(args,
+			p.ActivityID, int64(p.TimeOffset), floatArg(p.Lat), floatArg(p.Lng), floatArg(p.Altitude),
+			floatArg(p.VelocitySmooth), intArg(p.Heartrate), intArg(p.Cadence), floatArg(p.GradeSmooth), floatArg(p.Distance),
 		)
-		if err != nil {
-			return fmt.Errorf("inserting stream point: %w", err)
-		}
 	}
+	return args
+}
+
+func floatArg(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("committing transaction: %w", err)
+func intArg(v *int) any {
+	if v == nil {
+		return nil
 	}
+	return int64(*v)
+}
 
-	return nil
+// MigrateStreams rewrites every activity's streams into the format selected
+// by SetCompressStreams. It returns the number of activities converted; the
+// database file only shrinks after a VACUUM.
+func (s *Store) MigrateStreams(progress func(done, total int)) (int, error) {
+	var ids []int64
+	var err error
+	if s.compressStreams {
+		ids, err = s.queries.ListRowStreamActivityIDs(context.Background())
+	} else {
+		ids, err = s.queries.ListBlobStreamActivityIDs(context.Background())
+	}
+	if err != nil {
+		return 0, fmt.Errorf("listing activities with streams: %w", err)
+	}
+
+	for i, id := range ids {
+		points, err := s.GetStreams(id)
+		if err != nil {
+			return i, fmt.Errorf("reading streams for %d: %w", id, err)
+		}
+		if err := s.SaveStreams(id, points); err != nil {
+			return i, fmt.Errorf("saving streams for %d: %w", id, err)
+		}
+		if progress != nil {
+			progress(i+1, len(ids))
+		}
+	}
+	return len(ids), nil
 }
 
 // InsertStreamPoint inserts a single stream point.
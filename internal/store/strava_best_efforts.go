@@ -0,0 +1,27 @@
+package store
+
+import (
+	"context"
+)
+
+// GetStravaBestEfforts returns an activity's imported Strava best efforts,
+// shortest first
+func (s *Store) GetStravaBestEfforts(activityID int64) ([]StravaBestEffort, error) {
+	rows, err := s.queries.ListStravaBestEfforts(context.Background(), activityID)
+	if err != nil {
+		return nil, err
+	}
+	efforts := make([]StravaBestEffort, 0, len(rows))
+	for _, row := range rows {
+		efforts = append(efforts, StravaBestEffort{
+			ActivityID:  row.ActivityID,
+			Name:        row.Name,
+			Distance:    row.Distance,
+			ElapsedTime: int(row.ElapsedTime),
+			MovingTime:  int(row.MovingTime),
+			StartIndex:  int(row.StartIndex),
+			EndIndex:    int(row.EndIndex),
+		})
+	}
+	return efforts, nil
+}
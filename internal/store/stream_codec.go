@@ -0,0 +1,274 @@
+package store
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// streamBlobVersion is the first byte of every encoded stream blob
+const streamBlobVersion = 1
+
+// errCorruptStreamBlob is returned when a stream blob can't be decoded
+var errCorruptStreamBlob = errors.New("corrupt stream blob")
+
+// encodeStreams packs stream points into a compressed columnar blob.
+//
+// Each column is stored separately: a presence bitmap for nullable columns,
+// then the present values. Integers are delta-encoded as zigzag varints and
+// floats are XORed with the previous value, so slowly changing samples turn
+// into long runs of zero bytes before DEFLATE. The encoding is lossless.
+func encodeStreams(points []StreamPoint) ([]byte, error) {
+	var raw bytes.Buffer
+	w := columnWriter{buf: &raw}
+
+	w.uvarint(uint64(len(points)))
+
+	offsets := make([]*int, len(points))
+	for i := range points {
+		offsets[i] = &points[i].TimeOffset
+	}
+	w.intColumn(offsets, false)
+
+	w.floatColumn(floatColumn(points, func(p *StreamPoint) *float64 { return p.Lat }))
+	w.floatColumn(floatColumn(points, func(p *StreamPoint) *float64 { return p.Lng }))
+	w.floatColumn(floatColumn(points, func(p *StreamPoint) *float64 { return p.Altitude }))
+	w.floatColumn(floatColumn(points, func(p *StreamPoint) *float64 { return p.VelocitySmooth }))
+	w.intColumn(intColumn(points, func(p *StreamPoint) *int { return p.Heartrate }), true)
+	w.intColumn(intColumn(points, func(p *StreamPoint) *int { return p.Cadence }), true)
+	w.floatColumn(floatColumn(points, func(p *StreamPoint) *float64 { return p.GradeSmooth }))
+	w.floatColumn(floatColumn(points, func(p *StreamPoint) *float64 { return p.Distance }))
+
+	var out bytes.Buffer
+	out.WriteByte(streamBlobVersion)
+	fw, err := flate.NewWriter(&out, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(raw.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := fw.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// decodeStreams unpacks a blob written by encodeStreams
+func decodeStreams(activityID int64, data []byte) ([]StreamPoint, error) {
+	return decodeStreamFields(activityID, data, allStreamFields)
+}
+
+// decodeStreamFields unpacks only the given columns of a blob. The others
+// are still read past, as values are variable length, but not allocated.
+func decodeStreamFields(activityID int64, data []byte, fields StreamField) ([]StreamPoint, error) {
+	if len(data) == 0 || data[0] != streamBlobVersion {
+		return nil, fmt.Errorf("%w: unknown version", errCorruptStreamBlob)
+	}
+
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(data[1:])))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorruptStreamBlob, err)
+	}
+	r := columnReader{buf: bytes.NewReader(raw)}
+
+	n := int(r.uvarint())
+	if r.err != nil || n > len(raw)*8 {
+		return nil, fmt.Errorf("%w: bad point count", errCorruptStreamBlob)
+	}
+
+	points := make([]StreamPoint, n)
+	offsets := r.intColumn(n, false, true)
+	lat := r.floatColumn(n, fields&StreamLatLng != 0)
+	lng := r.floatColumn(n, fields&StreamLatLng != 0)
+	altitude := r.floatColumn(n, fields&StreamAltitude != 0)
+	velocity := r.floatColumn(n, fields&StreamVelocity != 0)
+	heartrate := r.intColumn(n, true, fields&StreamHeartrate != 0)
+	cadence := r.intColumn(n, true, fields&StreamCadence != 0)
+	grade := r.floatColumn(n, fields&StreamGrade != 0)
+	distance := r.floatColumn(n, fields&StreamDistance != 0)
+	if r.err != nil {
+		return nil, fmt.Errorf("%w: %v", errCorruptStreamBlob, r.err)
+	}
+
+	for i := range points {
+		points[i] = StreamPoint{
+			ActivityID:     activityID,
+			TimeOffset:     *offsets[i],
+			Lat:            lat[i],
+			Lng:            lng[i],
+			Altitude:       altitude[i],
+			VelocitySmooth: velocity[i],
+			Heartrate:      heartrate[i],
+			Cadence:        cadence[i],
+			GradeSmooth:    grade[i],
+			Distance:       distance[i],
+		}
+	}
+	return points, nil
+}
+
+func floatColumn(points []StreamPoint, get func(*StreamPoint) *float64) []*float64 {
+	col := make([]*float64, len(points))
+	for i := range points {
+		col[i] = get(&points[i])
+	}
+	return col
+}
+
+func intColumn(points []StreamPoint, get func(*StreamPoint) *int) []*int {
+	col := make([]*int, len(points))
+	for i := range points {
+		col[i] = get(&points[i])
+	}
+	return col
+}
+
+// columnWriter appends encoded columns to a buffer
+type columnWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w columnWriter) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	w.buf.Write(tmp[:binary.PutUvarint(tmp[:], v)])
+}
+
+func (w columnWriter) varint(v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	w.buf.Write(tmp[:binary.PutVarint(tmp[:], v)])
+}
+
+// bitmap writes one bit per value marking which entries are non-nil
+func (w columnWriter) bitmap(present func(i int) bool, n int) {
+	bits := make([]byte, (n+7)/8)
+	for i := 0; i < n; i++ {
+		if present(i) {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	w.buf.Write(bits)
+}
+
+func (w columnWriter) intColumn(col []*int, nullable bool) {
+	if nullable {
+		w.bitmap(func(i int) bool { return col[i] != nil }, len(col))
+	}
+	var prev int64
+	for _, v := range col {
+		if v == nil {
+			continue
+		}
+		w.varint(int64(*v) - prev)
+		prev = int64(*v)
+	}
+}
+
+func (w columnWriter) floatColumn(col []*float64) {
+	w.bitmap(func(i int) bool { return col[i] != nil }, len(col))
+	var prev uint64
+	var tmp [8]byte
+	for _, v := range col {
+		if v == nil {
+			continue
+		}
+		bits := math.Float64bits(*v)
+		binary.LittleEndian.PutUint64(tmp[:], bits^prev)
+		w.buf.Write(tmp[:])
+		prev = bits
+	}
+}
+
+// columnReader reads columns written by columnWriter, keeping the first error
+type columnReader struct {
+	buf *bytes.Reader
+	err error
+}
+
+func (r *columnReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r.buf)
+	r.err = err
+	return v
+}
+
+func (r *columnReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(r.buf)
+	r.err = err
+	return v
+}
+
+func (r *columnReader) bitmap(n int) []byte {
+	bits := make([]byte, (n+7)/8)
+	if r.err == nil {
+		_, r.err = io.ReadFull(r.buf, bits)
+	}
+	return bits
+}
+
+// intColumn reads a column written by columnWriter.intColumn. Without keep
+// the values are read past and the column is all nil.
+func (r *columnReader) intColumn(n int, nullable, keep bool) []*int {
+	col := make([]*int, n)
+	var bits []byte
+	if nullable {
+		bits = r.bitmap(n)
+	}
+	var prev int64
+	for i := range col {
+		if nullable && bits[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		prev += r.varint()
+		if keep {
+			v := int(prev)
+			col[i] = &v
+		}
+	}
+	return col
+}
+
+// floatColumn reads a column written by columnWriter.floatColumn. Without
+// keep the values are skipped and the column is all nil.
+func (r *columnReader) floatColumn(n int, keep bool) []*float64 {
+	col := make([]*float64, n)
+	bits := r.bitmap(n)
+	if !keep {
+		var present int
+		for i := range col {
+			if bits[i/8]&(1<<(i%8)) != 0 {
+				present++
+			}
+		}
+		if r.err == nil && r.buf.Len() < 8*present {
+			r.err = io.ErrUnexpectedEOF
+		}
+		if r.err == nil {
+			_, r.err = r.buf.Seek(int64(8*present), io.SeekCurrent)
+		}
+		return col
+	}
+	var prev uint64
+	var tmp [8]byte
+	for i := range col {
+		if bits[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		if r.err == nil {
+			_, r.err = io.ReadFull(r.buf, tmp[:])
+		}
+		prev ^= binary.LittleEndian.Uint64(tmp[:])
+		v := math.Float64frombits(prev)
+		col[i] = &v
+	}
+	return col
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// StreamField selects optional stream columns for GetStreamSeries. Fields
+// are bit flags, so a set of them can be combined with |.
+type StreamField uint8
+
+const (
+	StreamLatLng StreamField = 1 << iota
+	StreamAltitude
+	StreamVelocity
+	StreamHeartrate
+	StreamCadence
+	StreamGrade
+	StreamDistance
+)
+
+// allStreamFields is every optional column, as GetStreams loads
+const allStreamFields = StreamLatLng | StreamAltitude | StreamVelocity | StreamHeartrate |
+	StreamCadence | StreamGrade | StreamDistance
+
+// streamFieldColumns maps each field to its columns in the streams table
+var streamFieldColumns = []struct {
+	field   StreamField
+	columns string
+}{
+	{StreamLatLng, "latlng_lat, latlng_lng"},
+	{StreamAltitude, "altitude"},
+	{StreamVelocity, "velocity_smooth"},
+	{StreamHeartrate, "heartrate"},
+	{StreamCadence, "cadence"},
+	{StreamGrade, "grade_smooth"},
+	{StreamDistance, "distance"},
+}
+
+// GetStreamSeries retrieves an activity's stream points with only the given
+// fields filled in, for analytics that don't need every column. Time
+// offsets are always loaded; other fields are left nil. Row-stored streams
+// read only the selected columns, and compressed ones skip allocating the
+// rest.
+func (s *Store) GetStreamSeries(activityID int64, fields ...StreamField) ([]StreamPoint, error) {
+	var set StreamField
+	for _, f := range fields {
+		set |= f
+	}
+
+	blob, err := s.queries.GetStreamBlob(context.Background(), activityID)
+	if err == nil {
+		return decodeStreamFields(activityID, blob.Data, set)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	columns := []string{"time_offset"}
+	for _, c := range streamFieldColumns {
+		if set&c.field != 0 {
+			columns = append(columns, c.columns)
+		}
+	}
+	rows, err := s.db.Query(`SELECT `+strings.Join(columns, ", ")+`
+		FROM streams WHERE activity_id = ? ORDER BY time_offset`, activityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []StreamPoint
+	for rows.Next() {
+		p := StreamPoint{ActivityID: activityID}
+		if err := rows.Scan(streamFieldDests(&p, set)...); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// streamFieldDests returns scan destinations in p for time_offset and the
+// columns of set, in streamFieldColumns order
+func streamFieldDests(p *StreamPoint, set StreamField) []interface{} {
+	dests := []interface{}{&p.TimeOffset}
+	if set&StreamLatLng != 0 {
+		dests = append(dests, &p.Lat, &p.Lng)
+	}
+	if set&StreamAltitude != 0 {
+		dests = append(dests, &p.Altitude)
+	}
+	if set&StreamVelocity != 0 {
+		dests = append(dests, &p.VelocitySmooth)
+	}
+	if set&StreamHeartrate != 0 {
+		dests = append(dests, &p.Heartrate)
+	}
+	if set&StreamCadence != 0 {
+		dests = append(dests, &p.Cadence)
+	}
+	if set&StreamGrade != 0 {
+		dests = append(dests, &p.GradeSmooth)
+	}
+	if set&StreamDistance != 0 {
+		dests = append(dests, &p.Distance)
+	}
+	return dests
+}
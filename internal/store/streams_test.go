@@ -0,0 +1,356 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testStreamPoints returns n samples with a few gaps in the optional columns
+func testStreamPoints(activityID int64, n int) []StreamPoint {
+	points := make([]StreamPoint, n)
+	for i := range points {
+		lat, lng := 40.0+float64(i)*1e-5, -105.0-float64(i)*1e-5
+		alt, vel, grade := 1600.5+float64(i%7), 3.1+float64(i%5)*0.1, -0.4
+		dist := float64(i) * 3.1
+		hr, cad := 140+i%11, 88
+		points[i] = StreamPoint{
+			ActivityID:     activityID,
+			TimeOffset:     i * 2,
+			Lat:            &lat,
+			Lng:            &lng,
+			Altitude:       &alt,
+			VelocitySmooth: &vel,
+			Heartrate:      &hr,
+			Cadence:        &cad,
+			GradeSmooth:    &grade,
+			Distance:       &dist,
+		}
+		if i%13 == 0 {
+			points[i].Heartrate = nil
+		}
+		if i%29 == 0 {
+			points[i].Lat, points[i].Lng = nil, nil
+		}
+	}
+	return points
+}
+
+func TestStreamCodec_RoundTrip(t *testing.T) {
+	for _, n := range []int{0, 1, 9, 500} {
+		points := testStreamPoints(7, n)
+
+		data, err := encodeStreams(points)
+		if err != nil {
+			t.Fatalf("encodeStreams(%d) error = %v", n, err)
+		}
+		got, err := decodeStreams(7, data)
+		if err != nil {
+			t.Fatalf("decodeStreams(%d) error = %v", n, err)
+		}
+		if !reflect.DeepEqual(got, points) {
+			t.Errorf("round trip of %d points changed the data", n)
+		}
+	}
+}
+
+func TestStreamCodec_Corrupt(t *testing.T) {
+	data, err := encodeStreams(testStreamPoints(1, 50))
+	if err != nil {
+		t.Fatalf("encodeStreams() error = %v", err)
+	}
+
+	for name, blob := range map[string][]byte{
+		"empty":       nil,
+		"bad version": append([]byte{99}, data[1:]...),
+		"truncated":   data[:len(data)/2],
+	} {
+		if _, err := decodeStreams(1, blob); !errors.Is(err, errCorruptStreamBlob) {
+			t.Errorf("%s: expected errCorruptStreamBlob, got %v", name, err)
+		}
+	}
+}
+
+func TestSaveStreams_Compressed(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	// Activity 1 stored as rows, activity 2 compressed
+	rows := testStreamPoints(1, 120)
+	if err := db.SaveStreams(1, rows); err != nil {
+		t.Fatalf("SaveStreams(1) error = %v", err)
+	}
+	db.SetCompressStreams(true)
+	blob := testStreamPoints(2, 300)
+	if err := db.SaveStreams(2, blob); err != nil {
+		t.Fatalf("SaveStreams(2) error = %v", err)
+	}
+
+	got, err := db.GetStreams(2)
+	if err != nil {
+		t.Fatalf("GetStreams(2) error = %v", err)
+	}
+	if !reflect.DeepEqual(got, blob) {
+		t.Error("GetStreams(2) did not return the saved points")
+	}
+	if count, _ := db.GetStreamCount(2); count != 300 {
+		t.Errorf("GetStreamCount(2) = %d, want 300", count)
+	}
+
+	both, err := db.GetStreamsForActivities([]int64{1, 2})
+	if err != nil {
+		t.Fatalf("GetStreamsForActivities() error = %v", err)
+	}
+	if !reflect.DeepEqual(both[1], rows) || !reflect.DeepEqual(both[2], blob) {
+		t.Error("GetStreamsForActivities() did not return both formats")
+	}
+
+	// Migrating converts the row-stored activity only
+	converted, err := db.MigrateStreams(nil)
+	if err != nil {
+		t.Fatalf("MigrateStreams() error = %v", err)
+	}
+	if converted != 1 {
+		t.Errorf("MigrateStreams() converted %d, want 1", converted)
+	}
+	var rowCount int
+	if err := db.DB().QueryRow("SELECT COUNT(*) FROM streams").Scan(&rowCount); err != nil {
+		t.Fatalf("counting stream rows: %v", err)
+	}
+	if rowCount != 0 {
+		t.Errorf("expected no stream rows after migration, got %d", rowCount)
+	}
+	if got, _ := db.GetStreams(1); !reflect.DeepEqual(got, rows) {
+		t.Error("GetStreams(1) changed after migration")
+	}
+
+	if err := db.DeleteStreams(2); err != nil {
+		t.Fatalf("DeleteStreams(2) error = %v", err)
+	}
+	if has, _ := db.HasStreams(2); has {
+		t.Error("expected no streams after delete")
+	}
+}
+
+func TestSaveStreams_Batches(t *testing.T) {
+	db := setupTestDB(t)
+
+	// Around the batch size, so full batches, a remainder, and both
+	for _, n := range []int{0, 1, streamInsertBatch, streamInsertBatch + 1, 3*streamInsertBatch + 7} {
+		points := testStreamPoints(1, n)
+		if err := db.SaveStreams(1, points); err != nil {
+			t.Fatalf("SaveStreams(%d points) error = %v", n, err)
+		}
+		got, err := db.GetStreams(1)
+		if err != nil {
+			t.Fatalf("GetStreams() error = %v", err)
+		}
+		if len(got) != n || (n > 0 && !reflect.DeepEqual(got, points)) {
+			t.Errorf("saved %d points, read back %d that don't match", n, len(got))
+		}
+	}
+}
+
+// BenchmarkSaveStreams compares inserting an hour of second-by-second
+// points a row at a time with the batched inserts SaveStreams uses, and with
+// larger batches, which bind more slowly
+func BenchmarkSaveStreams(b *testing.B) {
+	points := testStreamPoints(1, 3600)
+	for _, bc := range []struct {
+		name  string
+		batch int
+	}{
+		{"row_at_a_time", 1},
+		{"batched", streamInsertBatch},
+		{"batch_of_100", 100},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			db, err := OpenPath(filepath.Join(b.TempDir(), "bench.db"))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer db.Close()
+			for i := range b.N {
+				if err := db.UpsertActivity(&Activity{ID: int64(i + 1), Name: "Long Run", Type: "Run", StartDate: time.Now()}); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			// Each run into a table that grows, as a first sync's do
+			b.ResetTimer()
+			for i := range b.N {
+				id := int64(i + 1)
+				for j := range points {
+					points[j].ActivityID = id
+				}
+				err := db.writeTx(func(tx *sql.Tx) error {
+					return insertStreamRows(tx, points, bc.batch)
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetStreamPage(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	// Pages read the same from rows and from a compressed blob
+	rows := testStreamPoints(1, 120)
+	if err := db.SaveStreams(1, rows); err != nil {
+		t.Fatalf("SaveStreams(1) error = %v", err)
+	}
+	db.SetCompressStreams(true)
+	blob := testStreamPoints(2, 120)
+	if err := db.SaveStreams(2, blob); err != nil {
+		t.Fatalf("SaveStreams(2) error = %v", err)
+	}
+
+	for id, points := range map[int64][]StreamPoint{1: rows, 2: blob} {
+		page, err := db.GetStreamPage(id, 50, 20)
+		if err != nil {
+			t.Fatalf("GetStreamPage(%d) error = %v", id, err)
+		}
+		if !reflect.DeepEqual(page, points[50:70]) {
+			t.Errorf("GetStreamPage(%d, 50, 20) returned the wrong points", id)
+		}
+		if last, _ := db.GetStreamPage(id, 110, 20); len(last) != 10 {
+			t.Errorf("GetStreamPage(%d, 110, 20) returned %d points, want the last 10", id, len(last))
+		}
+		if past, _ := db.GetStreamPage(id, 500, 20); len(past) != 0 {
+			t.Errorf("GetStreamPage(%d, 500, 20) returned %d points, want none", id, len(past))
+		}
+	}
+}
+
+func TestGetStreamsForActivities_Chunked(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	if err := db.SaveStreams(1, testStreamPoints(1, 10)); err != nil {
+		t.Fatalf("SaveStreams(1) error = %v", err)
+	}
+	if err := db.SaveStreams(2, testStreamPoints(2, 20)); err != nil {
+		t.Fatalf("SaveStreams(2) error = %v", err)
+	}
+
+	// More IDs than any SQLite build allows variables in one statement,
+	// with the stored activities in different chunks
+	ids := make([]int64, 40000)
+	for i := range ids {
+		ids[i] = int64(i + 1000)
+	}
+	ids[3] = 1
+	ids[len(ids)-1] = 2
+
+	streams, err := db.GetStreamsForActivities(ids)
+	if err != nil {
+		t.Fatalf("GetStreamsForActivities() error = %v", err)
+	}
+	if len(streams) != 2 || len(streams[1]) != 10 || len(streams[2]) != 20 {
+		t.Errorf("GetStreamsForActivities() returned %d activities, want 10 points for 1 and 20 for 2", len(streams))
+	}
+
+	activities, err := db.GetActivitiesByIDs(ids)
+	if err != nil {
+		t.Fatalf("GetActivitiesByIDs() error = %v", err)
+	}
+	if len(activities) != 2 || activities[1] == nil || activities[2] == nil {
+		t.Errorf("GetActivitiesByIDs() returned %d activities, want 1 and 2", len(activities))
+	}
+}
+
+func TestComputeStreamStatsForActivities(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+	rules := StreamStatsRules{MinHeartrate: 50, MaxHeartrate: 220, MinMovingSpeed: 0.5, CadenceMultiplier: 2}
+
+	// A standing stop, a dropout and trailing points without distance
+	points := testStreamPoints(1, 200)
+	stopped, spike := 0.2, 250
+	for i := 50; i < 60; i++ {
+		points[i].VelocitySmooth = &stopped
+	}
+	points[70].Heartrate = &spike
+	points[80].Cadence = nil
+	points[198].Distance, points[199].Distance = nil, nil
+
+	// Activity 1 stored as rows, activity 2 compressed
+	if err := db.SaveStreams(1, points); err != nil {
+		t.Fatalf("SaveStreams(1) error = %v", err)
+	}
+	db.SetCompressStreams(true)
+	blob := append([]StreamPoint(nil), points...)
+	for i := range blob {
+		blob[i].ActivityID = 2
+	}
+	if err := db.SaveStreams(2, blob); err != nil {
+		t.Fatalf("SaveStreams(2) error = %v", err)
+	}
+
+	got, err := db.ComputeStreamStatsForActivities([]int64{1, 2, 3}, rules)
+	if err != nil {
+		t.Fatalf("ComputeStreamStatsForActivities() error = %v", err)
+	}
+
+	want := summarizeStreams(1, points, rules)
+	if want.MovingTime != 199*2-10*2 || want.MovingDistance != 197*3.1 || want.CadenceCount != 199 {
+		t.Fatalf("summarizeStreams() = %+v, test points changed", want)
+	}
+	if *got[1] != *want {
+		t.Errorf("row-stored stats = %+v, want %+v", *got[1], *want)
+	}
+	want.ActivityID = 2
+	if *got[2] != *want {
+		t.Errorf("compressed stats = %+v, want %+v", *got[2], *want)
+	}
+	if *got[3] != (ActivityStreamStats{ActivityID: 3}) {
+		t.Errorf("stats without streams = %+v, want zeros", *got[3])
+	}
+}
+
+func TestGetStreamSeries(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	// Activity 1 stored as rows, activity 2 compressed
+	saved := map[int64][]StreamPoint{1: testStreamPoints(1, 120), 2: testStreamPoints(2, 300)}
+	if err := db.SaveStreams(1, saved[1]); err != nil {
+		t.Fatalf("SaveStreams(1) error = %v", err)
+	}
+	db.SetCompressStreams(true)
+	if err := db.SaveStreams(2, saved[2]); err != nil {
+		t.Fatalf("SaveStreams(2) error = %v", err)
+	}
+
+	for id, points := range saved {
+		want := make([]StreamPoint, len(points))
+		for i, p := range points {
+			want[i] = StreamPoint{ActivityID: id, TimeOffset: p.TimeOffset, Lat: p.Lat, Lng: p.Lng, Heartrate: p.Heartrate}
+		}
+		got, err := db.GetStreamSeries(id, StreamHeartrate, StreamLatLng)
+		if err != nil {
+			t.Fatalf("GetStreamSeries(%d) error = %v", id, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetStreamSeries(%d) did not return just time, position and heart rate", id)
+		}
+
+		all, err := db.GetStreamSeries(id, allStreamFields)
+		if err != nil {
+			t.Fatalf("GetStreamSeries(%d, all) error = %v", id, err)
+		}
+		if !reflect.DeepEqual(all, points) {
+			t.Errorf("GetStreamSeries(%d, all) differs from the saved points", id)
+		}
+	}
+
+	none, err := db.GetStreamSeries(1)
+	if err != nil {
+		t.Fatalf("GetStreamSeries(1) error = %v", err)
+	}
+	if len(none) != 120 || none[5].TimeOffset != 10 || none[5].Heartrate != nil {
+		t.Errorf("GetStreamSeries(1) without fields = %d points, want 120 with only time offsets", len(none))
+	}
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// SyncLockTTL is how long a sync lock holds without a heartbeat. A process
+// that dies mid-sync leaves its row behind; once stale, the next sync takes
+// it over.
+const SyncLockTTL = 2 * time.Minute
+
+// SyncLock records which process is syncing
+type SyncLock struct {
+	Owner       string
+	StartedAt   time.Time
+	HeartbeatAt time.Time
+}
+
+// SyncLockedError is returned when another process holds the sync lock
+type SyncLockedError struct {
+	Lock SyncLock
+}
+
+func (e *SyncLockedError) Error() string {
+	return fmt.Sprintf("a sync is in progress elsewhere (%s, started %s)",
+		e.Lock.Owner, e.Lock.StartedAt.Local().Format("15:04"))
+}
+
+// staleBefore returns the heartbeat a lock needs to be live at now.
+// Heartbeats are stored to the second, so the cutoff is too, and both the
+// SQL and GetSyncLock call a lock stale when its heartbeat is before it.
+func staleBefore(now time.Time) time.Time {
+	return now.UTC().Truncate(time.Second).Add(-SyncLockTTL)
+}
+
+// AcquireSyncLock takes the sync lock for owner, replacing a stale one.
+// It returns a *SyncLockedError if another process holds a live lock.
+func (s *Store) AcquireSyncLock(owner string, now time.Time) error {
+	stamp := now.UTC().Format(time.RFC3339)
+	// A second attempt covers a lock released between the insert and the
+	// read; one that keeps changing hands is reported as taken
+	var lock *SyncLock
+	for range 2 {
+		res, err := s.queries.AcquireSyncLock(context.Background(), sqlc.AcquireSyncLockParams{
+			Owner:         owner,
+			StartedAt:     stamp,
+			HeartbeatAt:   stamp,
+			HeartbeatAt_2: staleBefore(now).Format(time.RFC3339),
+		})
+		if err != nil {
+			return fmt.Errorf("acquiring sync lock: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			return nil
+		}
+
+		if lock, err = s.GetSyncLock(now); err != nil {
+			return err
+		}
+		if lock != nil {
+			return &SyncLockedError{Lock: *lock}
+		}
+	}
+	return &SyncLockedError{Lock: SyncLock{Owner: "another process", StartedAt: now}}
+}
+
+// RefreshSyncLock moves owner's heartbeat to now so the lock stays live
+func (s *Store) RefreshSyncLock(owner string, now time.Time) error {
+	return s.queries.RefreshSyncLock(context.Background(), sqlc.RefreshSyncLockParams{
+		HeartbeatAt: now.UTC().Format(time.RFC3339),
+		Owner:       owner,
+	})
+}
+
+// ReleaseSyncLock drops the sync lock if owner holds it
+func (s *Store) ReleaseSyncLock(owner string) error {
+	return s.queries.ReleaseSyncLock(context.Background(), owner)
+}
+
+// GetSyncLock returns the live sync lock, or nil if no process is syncing
+func (s *Store) GetSyncLock(now time.Time) (*SyncLock, error) {
+	row, err := s.queries.GetSyncLock(context.Background())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lock := SyncLock{Owner: row.Owner}
+	lock.StartedAt, _ = time.Parse(time.RFC3339, row.StartedAt)
+	lock.HeartbeatAt, _ = time.Parse(time.RFC3339, row.HeartbeatAt)
+	if lock.HeartbeatAt.Before(staleBefore(now)) {
+		return nil, nil
+	}
+	return &lock, nil
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSyncLock(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	lock, err := db.GetSyncLock(now)
+	if err != nil || lock != nil {
+		t.Fatalf("GetSyncLock = %v, %v before any sync, want nil", lock, err)
+	}
+
+	if err := db.AcquireSyncLock("tui:1", now); err != nil {
+		t.Fatalf("AcquireSyncLock failed: %v", err)
+	}
+	// Taking it again as the same owner succeeds
+	if err := db.AcquireSyncLock("tui:1", now); err != nil {
+		t.Fatalf("re-acquiring as the owner failed: %v", err)
+	}
+
+	err = db.AcquireSyncLock("daemon:2", now.Add(time.Minute))
+	var locked *SyncLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("AcquireSyncLock by another owner = %v, want SyncLockedError", err)
+	}
+	if locked.Lock.Owner != "tui:1" || !locked.Lock.StartedAt.Equal(now) {
+		t.Errorf("locked by %+v, want tui:1 since %v", locked.Lock, now)
+	}
+
+	// A heartbeat keeps the lock live past the TTL from its start
+	later := now.Add(SyncLockTTL + time.Minute)
+	if err := db.RefreshSyncLock("tui:1", later); err != nil {
+		t.Fatalf("RefreshSyncLock failed: %v", err)
+	}
+	if lock, err := db.GetSyncLock(later.Add(time.Minute)); err != nil || lock == nil {
+		t.Fatalf("GetSyncLock = %v, %v after a heartbeat, want the lock", lock, err)
+	}
+
+	// A stale lock is ignored and taken over
+	stale := later.Add(SyncLockTTL + time.Second)
+	if lock, err := db.GetSyncLock(stale); err != nil || lock != nil {
+		t.Errorf("GetSyncLock = %v, %v for a stale lock, want nil", lock, err)
+	}
+	if err := db.AcquireSyncLock("daemon:2", stale); err != nil {
+		t.Fatalf("taking over a stale lock failed: %v", err)
+	}
+
+	// Releasing as someone else leaves the lock alone
+	if err := db.ReleaseSyncLock("tui:1"); err != nil {
+		t.Fatalf("ReleaseSyncLock failed: %v", err)
+	}
+	if lock, _ := db.GetSyncLock(stale); lock == nil || lock.Owner != "daemon:2" {
+		t.Fatalf("GetSyncLock = %v after another owner's release, want daemon:2", lock)
+	}
+	if err := db.ReleaseSyncLock("daemon:2"); err != nil {
+		t.Fatalf("ReleaseSyncLock failed: %v", err)
+	}
+	if lock, _ := db.GetSyncLock(stale); lock != nil {
+		t.Errorf("GetSyncLock = %v after release, want nil", lock)
+	}
+}
+
+func TestSyncLock_StaleBoundary(t *testing.T) {
+	db := setupTestDB(t)
+	hb := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := db.AcquireSyncLock("tui:1", hb); err != nil {
+		t.Fatalf("AcquireSyncLock failed: %v", err)
+	}
+
+	// Half a second past the TTL is the same second as the cutoff, so the
+	// lock is still live to both the SQL and GetSyncLock
+	edge := hb.Add(SyncLockTTL + 500*time.Millisecond)
+	if lock, err := db.GetSyncLock(edge); err != nil || lock == nil {
+		t.Fatalf("GetSyncLock = %v, %v at the TTL, want the lock", lock, err)
+	}
+	var locked *SyncLockedError
+	if err := db.AcquireSyncLock("daemon:2", edge); !errors.As(err, &locked) {
+		t.Fatalf("AcquireSyncLock at the TTL = %v, want SyncLockedError", err)
+	}
+
+	// A full second past it the lock is stale to both
+	past := hb.Add(SyncLockTTL + time.Second)
+	if lock, err := db.GetSyncLock(past); err != nil || lock != nil {
+		t.Fatalf("GetSyncLock = %v, %v past the TTL, want nil", lock, err)
+	}
+	if err := db.AcquireSyncLock("daemon:2", past); err != nil {
+		t.Fatalf("AcquireSyncLock past the TTL failed: %v", err)
+	}
+}
@@ -2,10 +2,28 @@ package store
 
 import (
 	"database/sql"
+	"fmt"
 )
 
-// NewTestStore creates a Store for testing with an in-memory database.
-// This is only intended for use in tests.
-func NewTestStore(sqlDB *sql.DB) *Store {
-	return newStore(sqlDB)
+// OpenMemory opens an in-memory database with foreign keys enabled and all
+// migrations applied. This is only intended for use in tests.
+func OpenMemory() (*Store, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	// Each connection to :memory: would be a database of its own
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("enabling foreign keys: %w", err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("running migrations: %w", err)
+	}
+
+	return newStore(db), nil
 }
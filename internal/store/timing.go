@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// slowQuery is how long a query can run before it is logged as a warning
+const slowQuery = 250 * time.Millisecond
+
+// timedDB wraps the connection used by the generated queries and logs how
+// long each statement takes. Timings are logged at debug level, and slow
+// statements as warnings. Statements that write run on the writer.
+type timedDB struct {
+	db     *sql.DB
+	writer *writer
+}
+
+func (t timedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var res sql.Result
+	err := t.writer.do(func() error {
+		start := time.Now()
+		var err error
+		res, err = t.db.ExecContext(ctx, query, args...)
+		logQuery(ctx, query, start, err)
+		return err
+	})
+	return res, err
+}
+
+func (t timedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.db.PrepareContext(ctx, query)
+}
+
+func (t timedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	logQuery(ctx, query, start, err)
+	return rows, err
+}
+
+func (t timedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := t.db.QueryRowContext(ctx, query, args...)
+	logQuery(ctx, query, start, row.Err())
+	return row
+}
+
+func logQuery(ctx context.Context, query string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	level := slog.LevelDebug
+	if elapsed >= slowQuery {
+		level = slog.LevelWarn
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []any{"query", queryName(query), "ms", elapsed.Milliseconds()}
+	if err != nil && err != sql.ErrNoRows {
+		attrs = append(attrs, "error", err.Error())
+	}
+	slog.Log(ctx, level, "sql", attrs...)
+}
+
+// queryName returns the sqlc name of a generated query ("-- name: GetAuth
+// :one"), or the start of the statement otherwise
+func queryName(query string) string {
+	if rest, ok := strings.CutPrefix(query, "-- name: "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok {
+			return name
+		}
+	}
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > 60 {
+		query = query[:60] + "..."
+	}
+	return query
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// planDateLayout is how training plan dates are stored.
+const planDateLayout = "2006-01-02"
+
+// GetTrainingPlan returns the current training plan, or nil without one.
+func (s *Store) GetTrainingPlan() (*TrainingPlan, error) {
+	row, err := s.queries.GetTrainingPlan(context.Background())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	raceDate, err := time.Parse(planDateLayout, row.RaceDate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing race_date %q: %w", row.RaceDate, err)
+	}
+	startWeek, err := time.Parse(planDateLayout, row.StartWeek)
+	if err != nil {
+		return nil, fmt.Errorf("parsing start_week %q: %w", row.StartWeek, err)
+	}
+	return &TrainingPlan{
+		RaceDate:     raceDate,
+		StartWeek:    startWeek,
+		BaseDistance: row.BaseDistance,
+		PeakDistance: row.PeakDistance,
+		TaperWeeks:   int(row.TaperWeeks),
+		CutbackEvery: int(row.CutbackEvery),
+	}, nil
+}
+
+// SaveTrainingPlan stores plan, replacing any plan already saved.
+func (s *Store) SaveTrainingPlan(plan TrainingPlan) error {
+	return s.queries.SaveTrainingPlan(context.Background(), sqlc.SaveTrainingPlanParams{
+		RaceDate:     plan.RaceDate.Format(planDateLayout),
+		StartWeek:    plan.StartWeek.Format(planDateLayout),
+		BaseDistance: plan.BaseDistance,
+		PeakDistance: plan.PeakDistance,
+		TaperWeeks:   int64(plan.TaperWeeks),
+		CutbackEvery: int64(plan.CutbackEvery),
+	})
+}
+
+// DeleteTrainingPlan removes the training plan. It returns
+// ErrTrainingPlanNotFound when there is none.
+func (s *Store) DeleteTrainingPlan() error {
+	result, err := s.queries.DeleteTrainingPlan(context.Background())
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTrainingPlanNotFound
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTrainingPlan(t *testing.T) {
+	db := setupTestDB(t)
+
+	if plan, err := db.GetTrainingPlan(); err != nil || plan != nil {
+		t.Fatalf("GetTrainingPlan() = %+v, %v, want nil without a plan", plan, err)
+	}
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	plan := TrainingPlan{
+		RaceDate:     day("2024-04-21"),
+		StartWeek:    day("2024-01-15"),
+		BaseDistance: 30000,
+		PeakDistance: 60000,
+		TaperWeeks:   3,
+		CutbackEvery: 4,
+	}
+	if err := db.SaveTrainingPlan(plan); err != nil {
+		t.Fatalf("SaveTrainingPlan failed: %v", err)
+	}
+	got, err := db.GetTrainingPlan()
+	if err != nil {
+		t.Fatalf("GetTrainingPlan failed: %v", err)
+	}
+	if got == nil || *got != plan {
+		t.Errorf("GetTrainingPlan() = %+v, want %+v", got, plan)
+	}
+
+	// Saving again replaces the plan
+	plan.PeakDistance, plan.CutbackEvery = 70000, 0
+	if err := db.SaveTrainingPlan(plan); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := db.GetTrainingPlan(); got == nil || *got != plan {
+		t.Errorf("GetTrainingPlan() after replace = %+v, want %+v", got, plan)
+	}
+
+	if err := db.DeleteTrainingPlan(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteTrainingPlan(); !errors.Is(err, ErrTrainingPlanNotFound) {
+		t.Errorf("expected ErrTrainingPlanNotFound, got %v", err)
+	}
+}
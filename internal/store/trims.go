@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// GetActivityTrim returns how an activity was trimmed, or nil if it wasn't.
+func (s *Store) GetActivityTrim(activityID int64) (*ActivityTrim, error) {
+	row, err := s.queries.GetActivityTrim(context.Background(), activityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	trimmedAt, err := time.Parse(time.RFC3339, row.TrimmedAt)
+	if err != nil {
+		return nil, fmt.Errorf("parsing trim time %q: %w", row.TrimmedAt, err)
+	}
+	return &ActivityTrim{
+		ActivityID:          row.ActivityID,
+		EndOffset:           int(row.EndOffset),
+		OriginalDistance:    row.OriginalDistance,
+		OriginalElapsedTime: int(row.OriginalElapsedTime),
+		TrimmedAt:           trimmedAt,
+	}, nil
+}
+
+// SetActivityTrim records a trim. Trimming an activity again only moves the
+// end offset; the original distance and time stay those of the first trim.
+func (s *Store) SetActivityTrim(trim ActivityTrim) error {
+	return s.queries.SetActivityTrim(context.Background(), sqlc.SetActivityTrimParams{
+		ActivityID:          trim.ActivityID,
+		EndOffset:           int64(trim.EndOffset),
+		OriginalDistance:    trim.OriginalDistance,
+		OriginalElapsedTime: int64(trim.OriginalElapsedTime),
+		TrimmedAt:           trim.TrimmedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// DeleteActivityTrim forgets a trim, for when the activity is restored from
+// Strava.
+func (s *Store) DeleteActivityTrim(activityID int64) error {
+	return s.queries.DeleteActivityTrim(context.Background(), activityID)
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityTrims(t *testing.T) {
+	db := setupTestDB(t) // Uses activity ID 1 from the setup
+
+	trim, err := db.GetActivityTrim(1)
+	if err != nil {
+		t.Fatalf("GetActivityTrim failed: %v", err)
+	}
+	if trim != nil {
+		t.Fatalf("GetActivityTrim() = %+v before any trim, want nil", trim)
+	}
+
+	first := time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)
+	if err := db.SetActivityTrim(ActivityTrim{ActivityID: 1, EndOffset: 1800, OriginalDistance: 5000, OriginalElapsedTime: 2400, TrimmedAt: first}); err != nil {
+		t.Fatalf("SetActivityTrim failed: %v", err)
+	}
+	// A second trim moves the end but keeps the first trim's originals
+	second := first.Add(time.Hour)
+	if err := db.SetActivityTrim(ActivityTrim{ActivityID: 1, EndOffset: 1500, OriginalDistance: 4200, OriginalElapsedTime: 1800, TrimmedAt: second}); err != nil {
+		t.Fatalf("SetActivityTrim failed: %v", err)
+	}
+
+	trim, err = db.GetActivityTrim(1)
+	if err != nil {
+		t.Fatalf("GetActivityTrim failed: %v", err)
+	}
+	want := ActivityTrim{ActivityID: 1, EndOffset: 1500, OriginalDistance: 5000, OriginalElapsedTime: 2400, TrimmedAt: second}
+	if trim == nil || *trim != want {
+		t.Fatalf("GetActivityTrim() = %+v, want %+v", trim, want)
+	}
+
+	if err := db.DeleteActivityTrim(1); err != nil {
+		t.Fatalf("DeleteActivityTrim failed: %v", err)
+	}
+	if trim, err := db.GetActivityTrim(1); err != nil || trim != nil {
+		t.Errorf("GetActivityTrim() = %+v, %v after delete, want nil", trim, err)
+	}
+}
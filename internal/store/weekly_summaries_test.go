@@ -0,0 +1,150 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWeeklySummaries(t *testing.T) {
+	db := setupTestDB(t) // Uses activity IDs 1 and 2 from the setup
+
+	week := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	want := WeeklySummary{
+		WeekStart:      week,
+		RunCount:       2,
+		Distance:       15000,
+		MovingTime:     4500,
+		MovingDistance: 14900,
+		HRSum:          675000,
+		HRCount:        4500,
+		CadenceSum:     765000,
+		CadenceCount:   4500,
+		TRIMP:          180,
+		ElevationGain:  240,
+	}
+	if err := db.SaveWeeklySummary(&want); err != nil {
+		t.Fatalf("SaveWeeklySummary() error = %v", err)
+	}
+
+	// Saving again replaces the row
+	want.TRIMP = 200
+	if err := db.SaveWeeklySummary(&want); err != nil {
+		t.Fatalf("SaveWeeklySummary() error = %v", err)
+	}
+
+	got, err := db.GetWeeklySummaries(week.AddDate(0, 0, -7), week)
+	if err != nil {
+		t.Fatalf("GetWeeklySummaries() error = %v", err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Fatalf("GetWeeklySummaries() = %+v, want [%+v]", got, want)
+	}
+
+	if got, _ := db.GetWeeklySummaries(week.AddDate(0, 0, 7), week.AddDate(0, 0, 14)); len(got) != 0 {
+		t.Errorf("expected no summaries after the week, got %d", len(got))
+	}
+
+	if err := db.DeleteWeeklySummary(week); err != nil {
+		t.Fatalf("DeleteWeeklySummary() error = %v", err)
+	}
+	if count, _ := db.CountWeeklySummaries(); count != 0 {
+		t.Errorf("expected 0 summaries after delete, got %d", count)
+	}
+}
+
+func TestGetWeekActivities(t *testing.T) {
+	db := setupTestDB(t)
+
+	trimp := 80.0
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1, TRIMP: &trimp}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 2}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+	if err := db.SetActivityExcluded(2, true); err != nil {
+		t.Fatalf("SetActivityExcluded() error = %v", err)
+	}
+	if _, err := db.db.Exec("UPDATE activities SET total_elevation_gain = 85 WHERE id = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	got, err := db.GetWeekActivities(start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetWeekActivities() error = %v", err)
+	}
+
+	// Activity 2 is excluded from analysis
+	want := []WeekActivity{{ID: 1, Distance: 5000, ElevationGain: 85, TRIMP: &trimp}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetWeekActivities() = %+v, want %+v", got, want)
+	}
+}
+
+func TestElevationGainMigrationClearsSummaries(t *testing.T) {
+	db := setupTestDB(t)
+
+	week := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if err := db.SaveWeeklySummary(&WeeklySummary{WeekStart: week, RunCount: 1, Distance: 5000}); err != nil {
+		t.Fatalf("SaveWeeklySummary() error = %v", err)
+	}
+	if _, err := db.db.Exec("ALTER TABLE weekly_summaries DROP COLUMN elevation_gain"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Summaries saved without elevation are dropped so they get rebuilt
+	if err := migrate(db.db); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	if count, _ := db.CountWeeklySummaries(); count != 0 {
+		t.Errorf("expected summaries to be cleared, got %d", count)
+	}
+}
+
+func TestGetWeekActivities_DayBuckets(t *testing.T) {
+	db := setupTestDB(t)
+
+	// A Sunday evening run out west is already Monday in UTC
+	if err := db.SaveActivityMetrics(&ActivityMetrics{ActivityID: 1}); err != nil {
+		t.Fatalf("SaveActivityMetrics() error = %v", err)
+	}
+	if _, err := db.db.Exec(`UPDATE activities SET start_date = '2024-01-15T03:00:00Z',
+		start_date_local = '2024-01-14T22:00:00Z' WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+
+	sunday := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		utcDays bool
+		week    time.Time
+		day     time.Time
+	}{
+		{"local", false, monday.AddDate(0, 0, -7), sunday},
+		{"utc", true, monday, monday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.SetUTCDays(tt.utcDays)
+
+			got, err := db.GetWeekActivities(tt.week, tt.week.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatalf("GetWeekActivities() error = %v", err)
+			}
+			if len(got) != 1 || got[0].ID != 1 {
+				t.Errorf("GetWeekActivities(%s) = %+v, want activity 1", tt.week.Format("Jan 02"), got)
+			}
+
+			days, err := db.ListRunDays()
+			if err != nil {
+				t.Fatalf("ListRunDays() error = %v", err)
+			}
+			if len(days) == 0 || !days[0].Equal(tt.day) {
+				t.Errorf("ListRunDays() = %v, want first day %v", days, tt.day)
+			}
+		})
+	}
+}
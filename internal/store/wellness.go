@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"runner/internal/store/sqlc"
+)
+
+// wellnessDateLayout is how wellness dates are stored
+const wellnessDateLayout = "2006-01-02"
+
+// GetWellness returns the wellness entry for a calendar day, or nil if none
+// was logged
+func (s *Store) GetWellness(day time.Time) (*Wellness, error) {
+	row, err := s.queries.GetWellness(context.Background(), day.Format(wellnessDateLayout))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wellnessFromRow(sqlc.ListWellnessSinceRow(row))
+}
+
+// ListWellnessSince returns the wellness entries from the given day on,
+// oldest first
+func (s *Store) ListWellnessSince(since time.Time) ([]Wellness, error) {
+	rows, err := s.queries.ListWellnessSince(context.Background(), since.Format(wellnessDateLayout))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Wellness, 0, len(rows))
+	for _, row := range rows {
+		w, err := wellnessFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *w)
+	}
+	return entries, nil
+}
+
+// SaveWellness saves the wellness entries, replacing any already logged for
+// the same days
+func (s *Store) SaveWellness(entries []Wellness) error {
+	return s.writeTx(func(tx *sql.Tx) error {
+		qtx := s.queries.WithTx(tx)
+		for _, w := range entries {
+			if err := qtx.UpsertWellness(context.Background(), sqlc.UpsertWellnessParams{
+				Date:       w.Date.Format(wellnessDateLayout),
+				RestingHr:  ptrToNullFloat64(w.RestingHR),
+				Hrv:        ptrToNullFloat64(w.HRV),
+				SleepHours: ptrToNullFloat64(w.SleepHours),
+				Soreness:   ptrIntToNullInt64(w.Soreness),
+			}); err != nil {
+				return fmt.Errorf("saving wellness for %s: %w", w.Date.Format(wellnessDateLayout), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func wellnessFromRow(row sqlc.ListWellnessSinceRow) (*Wellness, error) {
+	date, err := time.Parse(wellnessDateLayout, row.Date)
+	if err != nil {
+		return nil, fmt.Errorf("parsing wellness date %q: %w", row.Date, err)
+	}
+	return &Wellness{
+		Date:       date,
+		RestingHR:  nullFloat64ToPtr(row.RestingHr),
+		HRV:        nullFloat64ToPtr(row.Hrv),
+		SleepHours: nullFloat64ToPtr(row.SleepHours),
+		Soreness:   nullInt64ToIntPtr(row.Soreness),
+	}, nil
+}
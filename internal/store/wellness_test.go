@@ -0,0 +1,58 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWellness(t *testing.T) {
+	db := setupTestDB(t)
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	f := func(v float64) *float64 { return &v }
+	i := func(v int) *int { return &v }
+
+	if w, err := db.GetWellness(day("2024-03-01")); err != nil || w != nil {
+		t.Fatalf("GetWellness() = %+v, %v; want nil", w, err)
+	}
+
+	if err := db.SaveWellness([]Wellness{
+		{Date: day("2024-03-01"), RestingHR: f(48), HRV: f(62), SleepHours: f(7.5), Soreness: i(2)},
+		{Date: day("2024-03-02"), SleepHours: f(6)},
+	}); err != nil {
+		t.Fatalf("SaveWellness failed: %v", err)
+	}
+
+	w, err := db.GetWellness(day("2024-03-01"))
+	if err != nil || w == nil {
+		t.Fatalf("GetWellness() = %+v, %v", w, err)
+	}
+	if *w.RestingHR != 48 || *w.HRV != 62 || *w.SleepHours != 7.5 || *w.Soreness != 2 {
+		t.Errorf("unexpected entry %+v", w)
+	}
+
+	// Saving a day again replaces it, including clearing fields
+	if err := db.SaveWellness([]Wellness{{Date: day("2024-03-01"), RestingHR: f(50)}}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := db.ListWellnessSince(day("2024-03-01"))
+	if err != nil {
+		t.Fatalf("ListWellnessSince failed: %v", err)
+	}
+	if len(entries) != 2 || !entries[0].Date.Equal(day("2024-03-01")) {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if *entries[0].RestingHR != 50 || entries[0].HRV != nil || entries[0].Soreness != nil {
+		t.Errorf("expected the entry to be replaced, got %+v", entries[0])
+	}
+	if entries[1].RestingHR != nil || *entries[1].SleepHours != 6 {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+
+	if entries, _ := db.ListWellnessSince(day("2024-03-02")); len(entries) != 1 {
+		t.Errorf("expected 1 entry since Mar 2, got %d", len(entries))
+	}
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// busyTimeout is how long a statement waits for another process to release
+// the write lock before failing with "database is locked"
+const busyTimeout = 10 * time.Second
+
+// connString returns the connection string for the database at dbPath.
+// Pragmas given here apply to every connection in the pool, where a PRAGMA
+// statement would reach only the connection that ran it.
+func connString(dbPath string, readOnly bool) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	if readOnly {
+		params.Set("mode", "ro")
+	}
+	return "file:" + filepath.ToSlash(dbPath) + "?" + params.Encode()
+}
+
+// enableWAL switches the database to write-ahead logging, which is stored in
+// the file and so lasts. Readers then don't block the writer or each other,
+// so the TUI keeps working while a scheduled sync writes.
+func enableWAL(db *sql.DB) error {
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode = WAL").Scan(&mode); err != nil {
+		return fmt.Errorf("enabling WAL: %w", err)
+	}
+	return nil
+}
+
+// writer runs every write the store makes on one goroutine. SQLite allows one
+// writer at a time, so writes from the TUI's commands and a running sync
+// queue here instead of failing on each other's lock.
+type writer struct {
+	jobs chan func()
+	done chan struct{}
+
+	// stop is closed by close; jobs never is, so a late write can't panic
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// writes counts finished writes, so readers can tell the data changed
+	writes atomic.Uint64
+}
+
+func newWriter() *writer {
+	w := &writer{
+		jobs: make(chan func()),
+		done: make(chan struct{}),
+		stop: make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		for {
+			select {
+			case job := <-w.jobs:
+				job()
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// do runs fn on the writer goroutine and returns its error, or ErrClosed
+// once the writer is closed. fn must not write through the store itself, or
+// it waits on its own queue.
+func (w *writer) do(fn func() error) error {
+	errc := make(chan error, 1)
+	job := func() {
+		err := fn()
+		w.writes.Add(1)
+		errc <- err
+	}
+	select {
+	case w.jobs <- job:
+		return <-errc
+	case <-w.stop:
+		return ErrClosed
+	}
+}
+
+// close stops the writer once the write it is running finishes. Writes
+// made after it, or waiting for their turn, fail with ErrClosed. It can be
+// called more than once.
+func (w *writer) close() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// Generation counts the writes this Store has made. It changes whenever
+// this process changes the database, so a cache can check it instead of
+// tracking what each write touched. Writes by other processes don't count.
+func (s *Store) Generation() uint64 {
+	return s.writer.writes.Load()
+}
+
+// write runs fn as one of the store's serialized writes
+func (s *Store) write(fn func() error) error {
+	return s.writer.do(fn)
+}
+
+// writeTx runs fn in a transaction on the writer goroutine, committing if it
+// returns nil
+func (s *Store) writeTx(fn func(tx *sql.Tx) error) error {
+	return s.write(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing transaction: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,78 @@
+package strava
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// ResponseCache keeps responses with their ETag and Last-Modified, keyed by
+// URL, so a page fetched before is revalidated rather than downloaded again.
+// A cache that can't read or write should act as a miss; it only costs a
+// full response.
+type ResponseCache interface {
+	Get(url string) (CachedResponse, bool)
+	Put(url string, r CachedResponse)
+}
+
+// CachedResponse is a response body with the validators it came with
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+}
+
+// SetCache turns on revalidation of activity pages and activities through
+// cache. Streams are large and fetched once per run, so they aren't cached.
+func (c *Client) SetCache(cache ResponseCache) {
+	c.cache = cache
+}
+
+// CacheHits returns how many requests were answered with 304 Not Modified
+// and served from the cache
+func (c *Client) CacheHits() int {
+	return int(c.cacheHits.Load())
+}
+
+// conditional adds the cached response's validators to req, returning the
+// response to serve on a 304
+func (c *Client) conditional(req *http.Request) (CachedResponse, bool) {
+	cached, ok := c.cache.Get(req.URL.String())
+	if !ok || (cached.ETag == "" && cached.LastModified == "") {
+		return CachedResponse{}, false
+	}
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+	return cached, true
+}
+
+// cacheResponse caches a 200 response that came with a validator, leaving
+// resp with a body that can still be read
+func (c *Client) cacheResponse(req *http.Request, resp *http.Response) error {
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.cache.Put(req.URL.String(), CachedResponse{ETag: etag, LastModified: lastModified, Body: body})
+	return nil
+}
+
+// serveCached turns a 304 into the cached 200 it confirmed
+func serveCached(resp *http.Response, cached CachedResponse) *http.Response {
+	resp.Body.Close()
+	resp.StatusCode = http.StatusOK
+	resp.Status = "200 OK"
+	resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+	resp.ContentLength = int64(len(cached.Body))
+	return resp
+}
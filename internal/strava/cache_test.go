@@ -0,0 +1,66 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mapCache is a ResponseCache in memory
+type mapCache map[string]CachedResponse
+
+func (m mapCache) Get(url string) (CachedResponse, bool) {
+	r, ok := m[url]
+	return r, ok
+}
+
+func (m mapCache) Put(url string, r CachedResponse) { m[url] = r }
+
+func TestClient_Cache(t *testing.T) {
+	var full, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/activities/7/streams" {
+			w.Header().Set("ETag", `"s1"`)
+			w.Write([]byte(`{}`))
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"id": 7, "name": "Tempo"}]`))
+	}))
+	defer srv.Close()
+
+	cache := mapCache{}
+	c := testClient(srv, 1)
+	c.SetCache(cache)
+
+	for range 2 {
+		activities, err := c.GetActivities(context.Background(), time.Time{}, 1, 100)
+		if err != nil {
+			t.Fatalf("GetActivities() error = %v", err)
+		}
+		if len(activities) != 1 || activities[0].Name != "Tempo" {
+			t.Errorf("activities = %+v, want the Tempo run", activities)
+		}
+	}
+	if full.Load() != 1 || notModified.Load() != 1 || c.CacheHits() != 1 || c.RequestCount() != 2 {
+		t.Errorf("got %d full and %d 304 responses, %d hits in %d requests, want 1 of each in 2",
+			full.Load(), notModified.Load(), c.CacheHits(), c.RequestCount())
+	}
+
+	// Streams are left out of the cache
+	if _, err := c.GetActivityStreams(context.Background(), 7, ResolutionFull); err != nil {
+		t.Fatalf("GetActivityStreams() error = %v", err)
+	}
+	if len(cache) != 1 {
+		t.Errorf("cache has %d entries, want only the activity page", len(cache))
+	}
+}
@@ -3,11 +3,14 @@ package strava
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -19,23 +22,33 @@ const BaseURL = "https://www.strava.com/api/v3"
 type Client struct {
 	httpClient  *http.Client
 	rateLimiter *RateLimiter
+	retry       RetryPolicy
+	baseURL     string
+	cache       ResponseCache // nil to always download
+	requests    atomic.Int64  // requests sent, for progress displays
+	cacheHits   atomic.Int64
+	log         requestLog
 }
 
-// NewClient creates a new Strava API client
+// NewClient creates a new Strava API client that retries with the default
+// policy
 func NewClient(tokenSource oauth2.TokenSource) *Client {
 	return &Client{
 		httpClient:  oauth2.NewClient(context.Background(), tokenSource),
 		rateLimiter: NewRateLimiter(),
+		retry:       DefaultRetryPolicy(),
+		baseURL:     BaseURL,
 	}
 }
 
+// SetRetryPolicy sets how requests that fail transiently are retried
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+}
+
 // GetActivities fetches activities with pagination
 // Returns activities after 'after' timestamp, up to 'perPage' results
 func (c *Client) GetActivities(ctx context.Context, after time.Time, page, perPage int) ([]Activity, error) {
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-
 	params := url.Values{}
 	if !after.IsZero() {
 		params.Set("after", strconv.FormatInt(after.Unix(), 10))
@@ -43,7 +56,7 @@ func (c *Client) GetActivities(ctx context.Context, after time.Time, page, perPa
 	params.Set("page", strconv.Itoa(page))
 	params.Set("per_page", strconv.Itoa(perPage))
 
-	resp, err := c.get(ctx, "/athlete/activities", params)
+	resp, err := c.get(ctx, "/athlete/activities", params, true)
 	if err != nil {
 		return nil, err
 	}
@@ -90,19 +103,37 @@ func (c *Client) GetAllActivities(ctx context.Context, after time.Time, onProgre
 	return allActivities, nil
 }
 
-// GetActivityStreams fetches detailed stream data for an activity
-func (c *Client) GetActivityStreams(ctx context.Context, activityID int64) (*Streams, error) {
-	if err := c.rateLimiter.Wait(ctx); err != nil {
+// GetActivity fetches a single activity by ID
+func (c *Client) GetActivity(ctx context.Context, activityID int64) (*Activity, error) {
+	resp, err := c.get(ctx, fmt.Sprintf("/activities/%d", activityID), nil, true)
+	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	var activity Activity
+	if err := json.NewDecoder(resp.Body).Decode(&activity); err != nil {
+		return nil, fmt.Errorf("decoding activity: %w", err)
+	}
 
+	return &activity, nil
+}
+
+// GetActivityStreams fetches detailed stream data for an activity, resampled
+// by Strava unless resolution is ResolutionFull
+func (c *Client) GetActivityStreams(ctx context.Context, activityID int64, resolution Resolution) (*Streams, error) {
 	// Request all available stream types
 	params := url.Values{}
 	params.Set("keys", "time,latlng,altitude,velocity_smooth,heartrate,cadence,grade_smooth,distance")
 	params.Set("key_by_type", "true")
+	if resolution != ResolutionFull {
+		// Resampled by time, so time offsets stay evenly spread
+		params.Set("resolution", string(resolution))
+		params.Set("series_type", "time")
+	}
 
 	path := fmt.Sprintf("/activities/%d/streams", activityID)
-	resp, err := c.get(ctx, path, params)
+	resp, err := c.get(ctx, path, params, false)
 	if err != nil {
 		return nil, err
 	}
@@ -121,30 +152,127 @@ func (c *Client) RateLimitStatus() (shortRemaining, dailyRemaining int) {
 	return c.rateLimiter.Status()
 }
 
-func (c *Client) get(ctx context.Context, path string, params url.Values) (*http.Response, error) {
-	reqURL := BaseURL + path
+// RateLimits returns the request limits of the 15-minute and daily windows,
+// as last reported by Strava
+func (c *Client) RateLimits() (shortLimit, dailyLimit int) {
+	return c.rateLimiter.Limits()
+}
+
+// RateLimitWait returns when a request held back by the rate limit will be
+// sent, or the zero time if none is waiting
+func (c *Client) RateLimitWait() time.Time {
+	return c.rateLimiter.WaitingUntil()
+}
+
+// RequestCount returns how many requests the client has sent
+func (c *Client) RequestCount() int {
+	return int(c.requests.Load())
+}
+
+// get sends a GET request, retrying transient failures as the retry policy
+// allows. Every attempt waits its turn with the rate limiter. With cached
+// set and a cache configured, the request is revalidated against the cache.
+func (c *Client) get(ctx context.Context, path string, params url.Values, cached bool) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, retryAfter, err := c.try(ctx, path, params, attempt, cached && c.cache != nil)
+		if err == nil {
+			return resp, nil
+		}
+		var transient *transientError
+		if !errors.As(err, &transient) || attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = c.retry.backoff(attempt)
+		}
+		slog.Info("retrying strava request", "path", path, "attempt", attempt+1,
+			"wait", delay.Round(time.Millisecond).String(), "error", err.Error())
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// try sends one attempt of a request and adds it to the request log.
+// Failures worth retrying come back as a transientError, with how long a 429
+// asked to wait in retryAfter.
+func (c *Client) try(ctx context.Context, path string, params url.Values, attempt int, useCache bool) (resp *http.Response, retryAfter time.Duration, err error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	reqURL := c.baseURL + path
 	if len(params) > 0 {
 		reqURL += "?" + params.Encode()
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	var cached CachedResponse
+	var revalidating bool
+	if useCache {
+		cached, revalidating = c.conditional(req)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	start := time.Now()
+	c.requests.Add(1)
+	resp, err = c.httpClient.Do(req)
+	rec := RequestRecord{Time: start, Path: path, Attempt: attempt, Latency: time.Since(start)}
 	if err != nil {
-		return nil, err
+		rec.Err = err.Error()
+		c.record(rec)
+		slog.Warn("strava request failed", "path", path, "error", err.Error())
+		if ctx.Err() != nil {
+			return nil, 0, err
+		}
+		return nil, 0, &transientError{err}
 	}
 
 	// Update rate limiter from response headers
 	c.rateLimiter.UpdateFromHeaders(resp.Header)
+	shortUsage, dailyUsage := c.rateLimiter.Usage()
+	rec.Status = resp.StatusCode
+	rec.Cached = resp.StatusCode == http.StatusNotModified && revalidating
+	c.record(rec)
+
+	if rec.Cached {
+		c.cacheHits.Add(1)
+		slog.Info("strava request", "path", path, "status", resp.StatusCode, "cached", true,
+			"ms", time.Since(start).Milliseconds(), "short_usage", shortUsage, "daily_usage", dailyUsage)
+		return serveCached(resp, cached), 0, nil
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		slog.Warn("strava request failed", "path", path, "status", resp.StatusCode,
+			"ms", time.Since(start).Milliseconds(), "short_usage", shortUsage, "daily_usage", dailyUsage,
+			"body", string(body))
+		if resp.StatusCode == http.StatusForbidden {
+			if err := scopeError(body); err != nil {
+				return nil, 0, err
+			}
+		}
+		err := fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return nil, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), &transientError{err}
+		}
+		return nil, 0, err
 	}
 
-	return resp, nil
+	slog.Info("strava request", "path", path, "status", resp.StatusCode,
+		"ms", time.Since(start).Milliseconds(), "short_usage", shortUsage, "daily_usage", dailyUsage)
+
+	if useCache {
+		if err := c.cacheResponse(req, resp); err != nil {
+			return nil, 0, &transientError{fmt.Errorf("reading response: %w", err)}
+		}
+	}
+	return resp, 0, nil
 }
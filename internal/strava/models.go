@@ -1,6 +1,9 @@
 package strava
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // Activity represents a Strava activity from the API
 type Activity struct {
@@ -23,6 +26,84 @@ type Activity struct {
 	AverageCadence     float64   `json:"average_cadence"`     // rpm or spm
 	SufferScore        int       `json:"suffer_score"`
 	HasHeartrate       bool      `json:"has_heartrate"`
+	WorkoutType        *int      `json:"workout_type"`         // see WorkoutTypeRace
+	KudosCount         int       `json:"kudos_count"`
+	CommentCount       int       `json:"comment_count"`
+	TotalPhotoCount    int       `json:"total_photo_count"` // Strava and Instagram photos
+
+	// Only the detailed activity endpoint returns these
+	Description    string       `json:"description"`
+	DeviceName     string       `json:"device_name"`
+	Calories       float64      `json:"calories"` // kcal
+	GearID         string       `json:"gear_id"`
+	Gear           *Gear        `json:"gear"`
+	SplitsMetric   []Split      `json:"splits_metric"`   // per kilometer
+	SplitsStandard []Split      `json:"splits_standard"` // per mile
+	BestEfforts    []BestEffort `json:"best_efforts"`
+	Photos         *Photos      `json:"photos"`
+}
+
+// Photos summarizes the photos attached to an activity
+type Photos struct {
+	Count   int    `json:"count"`
+	Primary *Photo `json:"primary"`
+}
+
+// Photo is one activity photo, with URLs keyed by size in pixels
+type Photo struct {
+	URLs map[string]string `json:"urls"`
+}
+
+// PrimaryPhotoURL returns the URL of the largest size of the activity's
+// primary photo, or "" when it has none
+func (a Activity) PrimaryPhotoURL() string {
+	if a.Photos == nil || a.Photos.Primary == nil {
+		return ""
+	}
+	url, best := "", 0
+	for size, u := range a.Photos.Primary.URLs {
+		if n, err := strconv.Atoi(size); err == nil && n > best {
+			url, best = u, n
+		}
+	}
+	return url
+}
+
+// Gear is the shoes (or bike) an activity was recorded with
+type Gear struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Distance float64 `json:"distance"` // meters logged on Strava
+}
+
+// Split is Strava's summary of one kilometer or mile of an activity
+type Split struct {
+	Split               int     `json:"split"`                // starting at 1
+	Distance            float64 `json:"distance"`             // meters
+	ElapsedTime         int     `json:"elapsed_time"`         // seconds
+	MovingTime          int     `json:"moving_time"`          // seconds
+	ElevationDifference float64 `json:"elevation_difference"` // meters
+	AverageSpeed        float64 `json:"average_speed"`        // m/s
+	AverageHeartrate    float64 `json:"average_heartrate"`    // bpm, 0 without HR
+}
+
+// BestEffort is Strava's fastest time over a standard distance within an
+// activity. The indexes point into the activity's streams.
+type BestEffort struct {
+	Name        string  `json:"name"`
+	Distance    float64 `json:"distance"`     // meters
+	ElapsedTime int     `json:"elapsed_time"` // seconds
+	MovingTime  int     `json:"moving_time"`  // seconds
+	StartIndex  int     `json:"start_index"`
+	EndIndex    int     `json:"end_index"`
+}
+
+// WorkoutTypeRace is the workout_type Strava gives runs marked as a race
+const WorkoutTypeRace = 1
+
+// IsRace reports whether the athlete marked the run as a race on Strava
+func (a Activity) IsRace() bool {
+	return a.WorkoutType != nil && *a.WorkoutType == WorkoutTypeRace
 }
 
 // Athlete represents a Strava athlete (minimal info in activity response)
@@ -2,6 +2,7 @@ package strava
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,6 +14,9 @@ import (
 // - 100 requests per 15 minutes
 // - 1000 requests per day
 
+// ShortWindow is the length of the short rate limit window
+const ShortWindow = 15 * time.Minute
+
 // RateLimiter manages Strava API rate limits
 type RateLimiter struct {
 	mu sync.Mutex
@@ -30,6 +34,9 @@ type RateLimiter struct {
 	// Minimum interval between requests
 	minInterval time.Duration
 	lastRequest time.Time
+
+	// When a Wait held back by a full window will return, zero otherwise
+	waitingUntil time.Time
 }
 
 // NewRateLimiter creates a new rate limiter with Strava's limits
@@ -37,7 +44,7 @@ func NewRateLimiter() *RateLimiter {
 	now := time.Now()
 	return &RateLimiter{
 		shortLimit:    100,
-		shortResetsAt: now.Add(15 * time.Minute),
+		shortResetsAt: now.Add(ShortWindow),
 		dailyLimit:    1000,
 		dailyResetsAt: now.Truncate(24 * time.Hour).Add(24 * time.Hour),
 		minInterval:   150 * time.Millisecond, // ~6.6 req/s max
@@ -54,7 +61,7 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 	// Reset windows if expired
 	if now.After(r.shortResetsAt) {
 		r.shortUsage = 0
-		r.shortResetsAt = now.Add(15 * time.Minute)
+		r.shortResetsAt = now.Add(ShortWindow)
 	}
 	if now.After(r.dailyResetsAt) {
 		r.dailyUsage = 0
@@ -64,27 +71,35 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 	// Check 15-minute limit
 	if r.shortUsage >= r.shortLimit {
 		waitTime := time.Until(r.shortResetsAt)
+		slog.Warn("rate limit reached", "window", "15m", "usage", r.shortUsage, "limit", r.shortLimit, "wait", waitTime.Round(time.Second).String())
+		r.waitingUntil = r.shortResetsAt
 		r.mu.Unlock()
 		select {
 		case <-time.After(waitTime):
 		case <-ctx.Done():
+			r.clearWait()
 			return ctx.Err()
 		}
 		r.mu.Lock()
+		r.waitingUntil = time.Time{}
 		r.shortUsage = 0
-		r.shortResetsAt = time.Now().Add(15 * time.Minute)
+		r.shortResetsAt = time.Now().Add(ShortWindow)
 	}
 
 	// Check daily limit
 	if r.dailyUsage >= r.dailyLimit {
 		waitTime := time.Until(r.dailyResetsAt)
+		slog.Warn("rate limit reached", "window", "daily", "usage", r.dailyUsage, "limit", r.dailyLimit, "wait", waitTime.Round(time.Second).String())
+		r.waitingUntil = r.dailyResetsAt
 		r.mu.Unlock()
 		select {
 		case <-time.After(waitTime):
 		case <-ctx.Done():
+			r.clearWait()
 			return ctx.Err()
 		}
 		r.mu.Lock()
+		r.waitingUntil = time.Time{}
 		r.dailyUsage = 0
 		r.dailyResetsAt = time.Now().Truncate(24 * time.Hour).Add(24 * time.Hour)
 	}
@@ -109,6 +124,20 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 	return nil
 }
 
+func (r *RateLimiter) clearWait() {
+	r.mu.Lock()
+	r.waitingUntil = time.Time{}
+	r.mu.Unlock()
+}
+
+// WaitingUntil returns when a Wait held back by a full window will return,
+// or the zero time if none is waiting
+func (r *RateLimiter) WaitingUntil() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.waitingUntil
+}
+
 // UpdateFromHeaders updates rate limit state from Strava response headers
 func (r *RateLimiter) UpdateFromHeaders(h http.Header) {
 	r.mu.Lock()
@@ -147,6 +176,13 @@ func (r *RateLimiter) Status() (shortRemaining, dailyRemaining int) {
 	return r.shortLimit - r.shortUsage, r.dailyLimit - r.dailyUsage
 }
 
+// Limits returns the request limits of the 15-minute and daily windows
+func (r *RateLimiter) Limits() (shortLimit, dailyLimit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.shortLimit, r.dailyLimit
+}
+
 // Usage returns current usage counts
 func (r *RateLimiter) Usage() (shortUsage, dailyUsage int) {
 	r.mu.Lock()
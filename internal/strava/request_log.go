@@ -0,0 +1,67 @@
+package strava
+
+import (
+	"sync"
+	"time"
+)
+
+// RequestLogSize is how many of the latest requests the client keeps
+const RequestLogSize = 200
+
+// RequestRecord is one API request as sent, for seeing why a sync stalls:
+// rate limited, unauthorized, or Strava failing
+type RequestRecord struct {
+	Time    time.Time
+	Path    string
+	Attempt int // 1 for the first try, more for retries
+	Status  int // 0 when no response came back
+	Latency time.Duration
+	Cached  bool   // 304 served from the response cache
+	Err     string // the network error when there was no response
+
+	// Rate limit usage once the response was read
+	ShortUsage int
+	ShortLimit int
+	DailyUsage int
+	DailyLimit int
+}
+
+// requestLog is a ring buffer of the latest requests
+type requestLog struct {
+	mu      sync.Mutex
+	entries []RequestRecord
+	next    int
+}
+
+func (l *requestLog) add(r RequestRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) < RequestLogSize {
+		l.entries = append(l.entries, r)
+		return
+	}
+	l.entries[l.next] = r
+	l.next = (l.next + 1) % RequestLogSize
+}
+
+// recent returns the kept requests, oldest first
+func (l *requestLog) recent() []RequestRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]RequestRecord, 0, len(l.entries))
+	out = append(out, l.entries[l.next:]...)
+	return append(out, l.entries[:l.next]...)
+}
+
+// RecentRequests returns the last RequestLogSize requests the client sent,
+// oldest first
+func (c *Client) RecentRequests() []RequestRecord {
+	return c.log.recent()
+}
+
+// record adds a finished request to the log with the current usage
+func (c *Client) record(r RequestRecord) {
+	r.ShortUsage, r.DailyUsage = c.rateLimiter.Usage()
+	r.ShortLimit, r.DailyLimit = c.rateLimiter.Limits()
+	c.log.add(r)
+}
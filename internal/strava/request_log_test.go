@@ -0,0 +1,54 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_RecentRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": 42}`))
+	}))
+	defer srv.Close()
+
+	c := testClient(srv, 2)
+	if _, err := c.GetActivity(context.Background(), 42); err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+
+	got := c.RecentRequests()
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want 2", len(got))
+	}
+	if got[0].Status != http.StatusServiceUnavailable || got[0].Attempt != 1 {
+		t.Errorf("first request = %+v, want a 503 on attempt 1", got[0])
+	}
+	if got[1].Status != http.StatusOK || got[1].Attempt != 2 || got[1].Path != "/activities/42" {
+		t.Errorf("second request = %+v, want a 200 for /activities/42 on attempt 2", got[1])
+	}
+	if got[1].ShortLimit == 0 || got[1].DailyLimit == 0 {
+		t.Errorf("second request = %+v, want rate limits filled in", got[1])
+	}
+}
+
+func TestRequestLog_Wraps(t *testing.T) {
+	var l requestLog
+	for i := range RequestLogSize + 5 {
+		l.add(RequestRecord{Attempt: i})
+	}
+	got := l.recent()
+	if len(got) != RequestLogSize {
+		t.Fatalf("got %d requests, want %d", len(got), RequestLogSize)
+	}
+	if got[0].Attempt != 5 || got[len(got)-1].Attempt != RequestLogSize+4 {
+		t.Errorf("kept %d to %d, want 5 to %d", got[0].Attempt, got[len(got)-1].Attempt, RequestLogSize+4)
+	}
+}
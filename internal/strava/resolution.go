@@ -0,0 +1,27 @@
+package strava
+
+// Resolution is how finely Strava resamples an activity's streams
+type Resolution string
+
+// Stream resolutions. Strava resamples to about 10,000, 1,000 or 100 points;
+// ResolutionFull downloads every point recorded.
+const (
+	ResolutionFull   Resolution = ""
+	ResolutionHigh   Resolution = "high"
+	ResolutionMedium Resolution = "medium"
+	ResolutionLow    Resolution = "low"
+)
+
+// Points returns about how many points a stream has at r, or 0 for full
+// streams, whose length depends on the recording
+func (r Resolution) Points() int {
+	switch r {
+	case ResolutionHigh:
+		return 10000
+	case ResolutionMedium:
+		return 1000
+	case ResolutionLow:
+		return 100
+	}
+	return 0
+}
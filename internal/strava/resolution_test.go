@@ -0,0 +1,33 @@
+package strava
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClient_StreamResolution(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"time": {"data": [0, 1, 2]}}`))
+	}))
+	defer srv.Close()
+	c := testClient(srv, 1)
+
+	if _, err := c.GetActivityStreams(context.Background(), 7, ResolutionFull); err != nil {
+		t.Fatal(err)
+	}
+	if query.Has("resolution") || query.Has("series_type") {
+		t.Errorf("full streams sent %v, want no resolution", query)
+	}
+
+	if _, err := c.GetActivityStreams(context.Background(), 7, ResolutionHigh); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("resolution") != "high" || query.Get("series_type") != "time" {
+		t.Errorf("high streams sent %v, want resolution=high by time", query)
+	}
+}
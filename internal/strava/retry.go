@@ -0,0 +1,83 @@
+package strava
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy is how the client retries requests that fail transiently:
+// network errors and timeouts, 5xx responses, and 429s
+type RetryPolicy struct {
+	// MaxAttempts counts the first try, so 1 turns retries off
+	MaxAttempts int
+
+	// BaseDelay is the backoff before the first retry, doubled for each one
+	// after it up to MaxDelay. Each backoff is jittered to between half and
+	// all of that, so clients retrying together spread out.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Default retry policy
+const (
+	DefaultMaxAttempts = 4
+	DefaultRetryDelay  = time.Second
+	maxRetryDelay      = 30 * time.Second
+)
+
+// DefaultRetryPolicy tries a request up to four times, backing off for at
+// most seven seconds in all
+func DefaultRetryPolicy() RetryPolicy {
+	return NewRetryPolicy(0, 0)
+}
+
+// NewRetryPolicy returns a policy with the given attempts and first backoff,
+// using the defaults for zero values
+func NewRetryPolicy(maxAttempts int, baseDelay time.Duration) RetryPolicy {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryDelay
+	}
+	return RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: baseDelay, MaxDelay: maxRetryDelay}
+}
+
+// backoff returns the wait before retrying after the given failed attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// transientError is a failure that may succeed if the request is sent again
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// parseRetryAfter reads a Retry-After header, given in seconds or as an HTTP
+// date, as a wait from now. It returns zero when the header is missing or
+// can't be read.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClient returns a client for srv that retries quickly
+func testClient(srv *httptest.Server, attempts int) *Client {
+	limiter := NewRateLimiter()
+	limiter.minInterval = 0
+	return &Client{
+		httpClient:  srv.Client(),
+		rateLimiter: limiter,
+		retry:       RetryPolicy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+		baseURL:     srv.URL,
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"id": 42, "name": "Morning Run"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := testClient(srv, 3)
+	a, err := c.GetActivity(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetActivity() error = %v", err)
+	}
+	if a.ID != 42 || calls.Load() != 3 || c.RequestCount() != 3 {
+		t.Errorf("got activity %d after %d calls (%d counted), want 42 after 3", a.ID, calls.Load(), c.RequestCount())
+	}
+}
+
+func TestClient_RetryGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/activities/404" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := testClient(srv, 3)
+	if _, err := c.GetActivity(context.Background(), 1); err == nil || calls.Load() != 3 {
+		t.Errorf("got %v after %d calls, want an error after 3", err, calls.Load())
+	}
+
+	// Client errors aren't retried
+	calls.Store(0)
+	if _, err := c.GetActivity(context.Background(), 404); err == nil || calls.Load() != 1 {
+		t.Errorf("got %v after %d calls, want an error after 1", err, calls.Load())
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := NewRetryPolicy(0, 0)
+	if p.MaxAttempts != DefaultMaxAttempts || p.BaseDelay != DefaultRetryDelay {
+		t.Errorf("NewRetryPolicy(0, 0) = %+v, want the defaults", p)
+	}
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: maxRetryDelay} {
+		for range 20 {
+			if d := p.backoff(attempt); d < want/2 || d > want {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", attempt, d, want/2, want)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"Fri, 01 Mar 2024 12:00:30 GMT", 30 * time.Second},
+		{"Fri, 01 Mar 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestClient_MissingScope(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+		if r.URL.Path == "/activities/1" {
+			w.Write([]byte(`{"message":"Authorization Error","errors":[{"resource":"AccessToken","field":"activity:read_permission","code":"missing"}]}`))
+			return
+		}
+		w.Write([]byte(`{"message":"Forbidden","errors":[]}`))
+	}))
+	defer srv.Close()
+
+	c := testClient(srv, 3)
+	_, err := c.GetActivity(context.Background(), 1)
+	if !errors.Is(err, ErrMissingScope) || !strings.Contains(err.Error(), "activity:read") || calls.Load() != 1 {
+		t.Errorf("got %v after %d calls, want ErrMissingScope for activity:read after 1", err, calls.Load())
+	}
+
+	if _, err := c.GetActivity(context.Background(), 2); err == nil || errors.Is(err, ErrMissingScope) {
+		t.Errorf("got %v for a plain 403, want an API error", err)
+	}
+}
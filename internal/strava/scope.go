@@ -0,0 +1,40 @@
+package strava
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingScope is wrapped by errors for requests Strava refused because
+// the token wasn't granted a scope they need. Reauthorizing with every
+// permission ticked fixes it; retrying doesn't.
+var ErrMissingScope = errors.New("strava token is missing a permission")
+
+// apiFault is the error body Strava sends with a 4xx
+type apiFault struct {
+	Message string `json:"message"`
+	Errors  []struct {
+		Resource string `json:"resource"`
+		Field    string `json:"field"`
+		Code     string `json:"code"`
+	} `json:"errors"`
+}
+
+// scopeError reads a 403 body, returning an error wrapping ErrMissingScope
+// if Strava says a permission is missing, or nil for any other refusal.
+// Strava reports these as a missing field named after the permission, like
+// activity:read_permission.
+func scopeError(body []byte) error {
+	var fault apiFault
+	if json.Unmarshal(body, &fault) != nil {
+		return nil
+	}
+	for _, e := range fault.Errors {
+		if e.Code == "missing" && strings.HasSuffix(e.Field, "_permission") {
+			return fmt.Errorf("%w (%s)", ErrMissingScope, strings.TrimSuffix(e.Field, "_permission"))
+		}
+	}
+	return nil
+}
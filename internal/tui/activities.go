@@ -2,28 +2,75 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"runner/internal/service"
+	"runner/internal/store"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Activities are loaded activityChunk at a time as the list scrolls, keeping
+// at most maxLoadedActivities around the cursor so years of runs scroll
+// smoothly without all being held in memory
+const (
+	activityChunk       = 50
+	maxLoadedActivities = 200
+)
+
 // ActivitiesModel is the activities list screen model
 type ActivitiesModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
 	units        Units
-	activities   []service.ActivityWithMetrics
-	cursor       int
-	offset       int
+	activities   []service.ActivityWithMetrics // loaded rows around the cursor
+	first        int                           // list position of activities[0]
+	cursor       int                           // index into activities
+	top          int                           // index of the first row shown
 	total        int
 	pageSize     int
 	loading      bool
+	loadingMore  bool
+	generation   int // bumped on reload so chunks of an old list are dropped
 	err          error
+
+	// Search and filters
+	filter    store.ActivityFilter
+	query     string    // applied search text
+	input     textInput // search text being edited
+	searching bool
+	filterErr error
+
+	sort store.ActivitySort
+}
+
+// activitySortFields is the order "o" cycles through
+var activitySortFields = []store.ActivitySortField{
+	store.SortByDate,
+	store.SortByDistance,
+	store.SortByDuration,
+	store.SortByPace,
+	store.SortByEF,
+	store.SortByTRIMP,
+	store.SortByDecoupling,
+	store.SortByKudos,
+}
+
+// activitySortLabels are the display names for sort fields
+var activitySortLabels = map[store.ActivitySortField]string{
+	store.SortByDate:       "date",
+	store.SortByDistance:   "distance",
+	store.SortByDuration:   "duration",
+	store.SortByPace:       "pace",
+	store.SortByEF:         "EF",
+	store.SortByTRIMP:      "TRIMP",
+	store.SortByDecoupling: "decoupling",
+	store.SortByKudos:      "kudos",
 }
 
 // NewActivitiesModel creates a new activities model
-func NewActivitiesModel(qs *service.QueryService, units Units) ActivitiesModel {
+func NewActivitiesModel(qs QueryProvider, units Units) ActivitiesModel {
 	return ActivitiesModel{
 		queryService: qs,
 		units:        units,
@@ -34,7 +81,7 @@ func NewActivitiesModel(qs *service.QueryService, units Units) ActivitiesModel {
 
 // Init initializes the activities screen
 func (m ActivitiesModel) Init() tea.Cmd {
-	return m.loadPage
+	return m.loadFirst
 }
 
 type activitiesLoadedMsg struct {
@@ -43,13 +90,21 @@ type activitiesLoadedMsg struct {
 	err        error
 }
 
-func (m ActivitiesModel) loadPage() tea.Msg {
-	activities, err := m.queryService.GetActivitiesList(m.pageSize, m.offset)
+// activitiesChunkMsg carries rows loaded next to the ones already shown
+type activitiesChunkMsg struct {
+	generation int
+	before     bool // rows come before activities[0] rather than after the end
+	activities []service.ActivityWithMetrics
+	err        error
+}
+
+func (m ActivitiesModel) loadFirst() tea.Msg {
+	activities, err := m.queryService.SearchActivitiesAfter(m.filter, m.sort, nil, -1, activityChunk)
 	if err != nil {
 		return activitiesLoadedMsg{err: err}
 	}
 
-	total, err := m.queryService.GetTotalActivityCount()
+	total, err := m.queryService.CountSearchActivities(m.filter)
 	if err != nil {
 		return activitiesLoadedMsg{err: err}
 	}
@@ -57,6 +112,82 @@ func (m ActivitiesModel) loadPage() tea.Msg {
 	return activitiesLoadedMsg{activities: activities, total: total}
 }
 
+// reload starts the list again from the top, e.g. after the filter or sort
+// changes
+func (m ActivitiesModel) reload() (ActivitiesModel, tea.Cmd) {
+	m.generation++
+	m.first, m.cursor, m.top = 0, 0, 0
+	m.loading = true
+	m.loadingMore = false
+	return m, m.loadFirst
+}
+
+// loadMore fetches the next chunk when the cursor comes within a screen of
+// either end of the loaded rows and more exist past it
+func (m ActivitiesModel) loadMore() (ActivitiesModel, tea.Cmd) {
+	if m.loading || m.loadingMore || len(m.activities) == 0 {
+		return m, nil
+	}
+	qs, filter, order, generation := m.queryService, m.filter, m.sort, m.generation
+
+	end := m.first + len(m.activities)
+	if m.cursor >= len(m.activities)-m.pageSize && end < m.total {
+		last := m.activities[len(m.activities)-1].Activity
+		m.loadingMore = true
+		return m, func() tea.Msg {
+			activities, err := qs.SearchActivitiesAfter(filter, order, &last, end-1, activityChunk)
+			return activitiesChunkMsg{generation: generation, activities: activities, err: err}
+		}
+	}
+	if m.cursor < m.pageSize && m.first > 0 {
+		first, pos := m.activities[0].Activity, m.first
+		m.loadingMore = true
+		return m, func() tea.Msg {
+			activities, err := qs.SearchActivitiesBefore(filter, order, first, pos, activityChunk)
+			return activitiesChunkMsg{generation: generation, before: true, activities: activities, err: err}
+		}
+	}
+	return m, nil
+}
+
+// addChunk adds loaded rows to the list, dropping rows from the far end to
+// stay within maxLoadedActivities
+func (m ActivitiesModel) addChunk(msg activitiesChunkMsg) ActivitiesModel {
+	if msg.before {
+		n := len(msg.activities)
+		m.activities = append(msg.activities, m.activities...)
+		m.first -= n
+		m.cursor += n
+		m.top += n
+		if len(m.activities) > maxLoadedActivities {
+			m.activities = m.activities[:maxLoadedActivities]
+		}
+		return m
+	}
+
+	m.activities = append(m.activities, msg.activities...)
+	if extra := len(m.activities) - maxLoadedActivities; extra > 0 {
+		m.activities = slices.Clone(m.activities[extra:])
+		m.first += extra
+		m.cursor -= extra
+		m.top -= extra
+	}
+	return m
+}
+
+// moveCursor moves the cursor by delta rows within the loaded ones, scrolls
+// to keep it visible, and loads more rows as it nears an end
+func (m ActivitiesModel) moveCursor(delta int) (ActivitiesModel, tea.Cmd) {
+	m.cursor = max(0, min(len(m.activities)-1, m.cursor+delta))
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+m.pageSize {
+		m.top = m.cursor - m.pageSize + 1
+	}
+	return m.loadMore()
+}
+
 // Update handles messages
 func (m ActivitiesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -65,49 +196,56 @@ func (m ActivitiesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		m.activities = msg.activities
 		m.total = msg.total
+		return m.loadMore()
+
+	case activitiesChunkMsg:
+		if msg.generation != m.generation {
+			return m, nil
+		}
+		m.loadingMore = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m = m.addChunk(msg)
+		return m.loadMore()
 
 	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+
 		switch msg.String() {
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			} else if m.offset > 0 {
-				// Go to previous page
-				m.offset -= m.pageSize
-				m.cursor = m.pageSize - 1
-				m.loading = true
-				return m, m.loadPage
+		case "/":
+			m.searching = true
+			m.input = textInput{value: m.query}
+			m.filterErr = nil
+			return m, nil
+		case "x":
+			if m.query != "" {
+				m.query = ""
+				m.filter = store.ActivityFilter{}
+				return m.reload()
 			}
-		case "down", "j":
-			if m.cursor < len(m.activities)-1 {
-				m.cursor++
-			} else if m.offset+len(m.activities) < m.total {
-				// Go to next page
-				m.offset += m.pageSize
-				m.cursor = 0
-				m.loading = true
-				return m, m.loadPage
+		case "o":
+			m.sort.Field = nextSortField(m.sort.Field)
+			if m.sort.Field == store.SortByKudos && !m.showsSocial() {
+				m.sort.Field = nextSortField(m.sort.Field)
 			}
+			return m.reload()
+		case "O":
+			m.sort.Ascending = !m.sort.Ascending
+			return m.reload()
+		case "up", "k":
+			return m.moveCursor(-1)
+		case "down", "j":
+			return m.moveCursor(1)
 		case "pgup":
-			if m.offset > 0 {
-				m.offset -= m.pageSize
-				if m.offset < 0 {
-					m.offset = 0
-				}
-				m.cursor = 0
-				m.loading = true
-				return m, m.loadPage
-			}
+			return m.moveCursor(-m.pageSize)
 		case "pgdown":
-			if m.offset+m.pageSize < m.total {
-				m.offset += m.pageSize
-				m.cursor = 0
-				m.loading = true
-				return m, m.loadPage
-			}
+			return m.moveCursor(m.pageSize)
 		case "r":
-			m.loading = true
-			return m, m.loadPage
+			return m.reload()
 		case "enter":
 			if len(m.activities) > 0 && m.cursor < len(m.activities) {
 				activityID := m.activities[m.cursor].Activity.ID
@@ -120,6 +258,92 @@ func (m ActivitiesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// activityListName returns the name shown in the list, marking manual,
+// suspect, and excluded activities
+func activityListName(a store.Activity, met store.ActivityMetrics) string {
+	name := a.Name
+	if a.Manual {
+		name = "✎ " + name
+	}
+	if len(met.AnomalyFlags) > 0 {
+		name = "⚠ " + name
+	}
+	if a.Excluded {
+		name = "⊘ " + name
+	}
+	return name
+}
+
+// nextSortField returns the sort field after f in activitySortFields
+func nextSortField(f store.ActivitySortField) store.ActivitySortField {
+	for i, field := range activitySortFields {
+		if field == f {
+			return activitySortFields[(i+1)%len(activitySortFields)]
+		}
+	}
+	// The zero value sorts by date
+	return activitySortFields[1]
+}
+
+// showsSocial reports whether any loaded run has kudos counts, which are
+// only synced with privacy.sync_social on
+func (m ActivitiesModel) showsSocial() bool {
+	return slices.ContainsFunc(m.activities, func(am service.ActivityWithMetrics) bool {
+		return am.Social != nil
+	})
+}
+
+// sortLabel describes the current sort order, e.g. "pace ↑"
+func (m ActivitiesModel) sortLabel() string {
+	field := m.sort.Field
+	if field == "" {
+		field = store.SortByDate
+	}
+	arrow := "↓"
+	if m.sort.Ascending {
+		arrow = "↑"
+	}
+	return activitySortLabels[field] + " " + arrow
+}
+
+// updateSearch handles key presses while the search box is open
+func (m ActivitiesModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case submitted:
+		filter, err := parseActivityFilter(m.input.value, m.units)
+		if err != nil {
+			m.filterErr = err
+			return m, nil
+		}
+		m.searching = false
+		m.filterErr = nil
+		m.query = strings.TrimSpace(m.input.value)
+		m.filter = filter
+		return m.reload()
+	case cancelled:
+		m.searching = false
+		m.filterErr = nil
+	}
+	return m, nil
+}
+
+// renderSearch renders the search box or the applied filter, if any
+func (m ActivitiesModel) renderSearch() string {
+	if m.searching {
+		line := fmt.Sprintf("  Search: %s", m.input.view())
+		if m.filterErr != nil {
+			line += "  " + errorStyle.Render(m.filterErr.Error())
+		}
+		hint := statusStyle.Render("  name/note text, type:run, tag:race, after:YYYY-MM-DD, before:YYYY-MM-DD, dist:5-10, pr  (enter: apply  esc: cancel)")
+		return lipgloss.JoinVertical(lipgloss.Left, line, hint)
+	}
+	if m.query != "" {
+		return statusStyle.Render(fmt.Sprintf("  Filter: %s  (/: edit  x: clear)", m.query))
+	}
+	return ""
+}
+
 // View renders the activities list
 func (m ActivitiesModel) View() string {
 	if m.loading {
@@ -130,25 +354,40 @@ func (m ActivitiesModel) View() string {
 		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
 	}
 
+	search := m.renderSearch()
+
 	if len(m.activities) == 0 {
+		if m.query != "" || m.searching {
+			return lipgloss.JoinVertical(lipgloss.Left, "\n  No activities match the filter.", search)
+		}
 		return "\n  No activities found. Press 's' to sync with Strava."
 	}
 
 	var sections []string
 
-	// Title with pagination info
-	startNum := m.offset + 1
-	endNum := m.offset + len(m.activities)
-	title := cardTitleStyle.Render(fmt.Sprintf("Activities (%d-%d of %d)", startNum, endNum, m.total))
+	// Title with the visible range
+	end := min(m.top+m.pageSize, len(m.activities))
+	startNum := m.first + m.top + 1
+	endNum := m.first + end
+	title := cardTitleStyle.Render(fmt.Sprintf("Activities (%d-%d of %d)  sorted by %s", startNum, endNum, m.total, m.sortLabel()))
 	sections = append(sections, title)
 
+	if search != "" {
+		sections = append(sections, search)
+	}
+
 	// Header
-	header := tableHeaderStyle.Render(fmt.Sprintf("   %-10s  %-20s  %7s  %5s  %3s  %3s  %5s  %6s  %5s",
-		"Date", "Name", "Dist", "Pace", "HR", "SPM", "EF", "Decoup", "TRIMP"))
-	sections = append(sections, header)
+	social := m.showsSocial()
+	headerText := fmt.Sprintf("   %-10s  %-20s  %7s  %5s  %3s  %3s  %5s  %6s  %5s",
+		"Date", "Name", "Dist", "Pace", "HR", "SPM", "EF", "Decoup", "TRIMP")
+	if social {
+		headerText += fmt.Sprintf("  %5s", "Kudos")
+	}
+	sections = append(sections, tableHeaderStyle.Render(headerText))
 
 	// Rows
-	for i, am := range m.activities {
+	for i := m.top; i < end; i++ {
+		am := m.activities[i]
 		a := am.Activity
 		met := am.Metrics
 
@@ -189,7 +428,7 @@ func (m ActivitiesModel) View() string {
 		row := fmt.Sprintf("%s%-10s  %-20s  %8s  %5s  %3s  %3s  %5s  %6s  %5s",
 			cursor,
 			a.StartDateLocal.Format("Jan 02"),
-			truncateName(a.Name, 20),
+			truncateName(activityListName(a, met), 20),
 			m.units.FormatDistance(a.Distance),
 			pace,
 			hr,
@@ -198,6 +437,13 @@ func (m ActivitiesModel) View() string {
 			dec,
 			trimp,
 		)
+		if social {
+			kudos := "-"
+			if am.Social != nil {
+				kudos = fmt.Sprintf("%d", am.Social.KudosCount)
+			}
+			row += fmt.Sprintf("  %5s", kudos)
+		}
 
 		if i == m.cursor {
 			sections = append(sections, tableSelectedStyle.Render(row))
@@ -207,7 +453,7 @@ func (m ActivitiesModel) View() string {
 	}
 
 	// Help
-	help := statusStyle.Render("\n  enter: view details  j/k: navigate  pgup/pgdn: page  r: refresh")
+	help := statusStyle.Render("\n  enter: view details  j/k: navigate  pgup/pgdn: page  /: search  o/O: sort  r: refresh")
 	sections = append(sections, help)
 
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
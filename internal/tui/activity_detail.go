@@ -1,20 +1,32 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
+	"runner/internal/analysis"
 	"runner/internal/service"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/guptarohit/asciigraph"
+)
+
+// Stream chart sizes. The width is used before the terminal width is known.
+const (
+	detailChartWidth  = 50
+	detailChartHeight = 8
+	cursorJumpMinutes = 5 // how far shift+arrows move the chart cursor
 )
 
 // ActivityDetailModel is the activity detail screen model
 type ActivityDetailModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
+	syncService  SyncRunner
 	units        Units
 	activityID   int64
 	detail       *service.ActivityDetail
@@ -25,17 +37,67 @@ type ActivityDetailModel struct {
 	width        int
 	height       int
 	ready        bool
+
+	// Tag/note/temperature/effort editing; editing is "" when no prompt is open
+	editing string
+	input   textInput
+	editErr error
+
+	// benchmarkName holds the name typed while the benchmark kind is asked
+	benchmarkName string
+
+	// Resync from Strava; notice reports how the last one went
+	resyncing bool
+	notice    string
+
+	// bothSplits shows mile and km splits instead of only the configured unit
+	bothSplits bool
+
+	// cursor is the minute marked on the stream charts, -1 when hidden
+	cursor int
+}
+
+// effortPromptAge is how recent a run must be for the detail screen to ask
+// for its perceived effort when none is logged
+const effortPromptAge = 72 * time.Hour
+
+// Fields that can be edited from the activity detail screen
+const (
+	editTags          = "tags"
+	editNote          = "note"
+	editTemperature   = "temperature"
+	editEffort        = "effort"
+	editBenchmark     = "benchmark"
+	editBenchmarkKind = "benchmark kind"
+	editDistance      = "distance"
+)
+
+type activityAnnotationSavedMsg struct {
+	err    error
+	notice string // shown once saved, if set
+}
+
+// benchmarkLookedUpMsg reports that no benchmark has the typed name yet
+type benchmarkLookedUpMsg struct {
+	name string
+	err  error
+}
+
+type activityResyncedMsg struct {
+	err error
 }
 
 // NewActivityDetailModel creates a new activity detail model
-func NewActivityDetailModel(qs *service.QueryService, units Units, activityID int64, width, height int) ActivityDetailModel {
+func NewActivityDetailModel(qs QueryProvider, ss SyncRunner, units Units, activityID int64, width, height int) ActivityDetailModel {
 	m := ActivityDetailModel{
 		queryService: qs,
+		syncService:  ss,
 		units:        units,
 		activityID:   activityID,
 		loading:      true,
 		width:        width,
 		height:       height,
+		cursor:       -1,
 	}
 
 	if width > 0 && height > 0 {
@@ -80,6 +142,9 @@ func (m ActivityDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		m.detail = msg.detail
 		m.activityPRs = msg.prs
+		if m.cursor >= m.minutes() {
+			m.cursor = m.minutes() - 1
+		}
 		if m.ready {
 			m.viewport.SetContent(m.renderContent())
 		}
@@ -98,11 +163,176 @@ func (m ActivityDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.SetContent(m.renderContent())
 		}
 
+	case activityAnnotationSavedMsg:
+		if msg.err != nil {
+			m.editErr = msg.err
+			return m, nil
+		}
+		m.editErr = nil
+		if msg.notice != "" {
+			m.notice = successStyle.Render("  " + msg.notice)
+		}
+		return m, m.loadDetail
+
+	case benchmarkLookedUpMsg:
+		if msg.err != nil {
+			m.editErr = msg.err
+			return m, nil
+		}
+		if strings.TrimSpace(msg.name) == "" {
+			return m, nil
+		}
+		m.benchmarkName = msg.name
+		m.editing = editBenchmarkKind
+		m.input = textInput{}
+		m.editErr = nil
+		return m, nil
+
+	case activityResyncedMsg:
+		m.resyncing = false
+		if msg.err != nil {
+			m.notice = errorStyle.Render(fmt.Sprintf("  Resync failed: %v", msg.err))
+		} else {
+			m.notice = successStyle.Render("  Resynced from Strava")
+		}
+		return m, m.loadDetail
+
 	case tea.KeyMsg:
+		if m.editing != "" {
+			return m.updateEdit(msg)
+		}
+
 		switch msg.String() {
 		case "r":
+			// Picks up changes from another process, which the cache can't see
+			m.queryService.InvalidateActivityDetails(m.activityID)
 			m.loading = true
 			return m, m.loadDetail
+		case "t":
+			if m.detail != nil {
+				m.editing = editTags
+				m.input = textInput{value: strings.Join(m.detail.Tags, ", ")}
+				m.editErr = nil
+			}
+			return m, nil
+		case "n":
+			if m.detail != nil {
+				m.editing = editNote
+				m.input = textInput{value: m.detail.Note}
+				m.editErr = nil
+			}
+			return m, nil
+		case "T":
+			if m.detail != nil {
+				m.editing = editTemperature
+				m.input = textInput{}
+				if m.detail.TemperatureC != nil {
+					m.input.value = m.units.FormatTemperature(*m.detail.TemperatureC)
+				}
+				m.editErr = nil
+			}
+			return m, nil
+		case "e":
+			if m.detail != nil {
+				m.editing = editEffort
+				m.input = textInput{}
+				if e := m.detail.Effort; e != nil {
+					m.input.value = strconv.Itoa(e.RPE)
+					if e.Feel != nil {
+						m.input.value += "/" + service.FeelLabel(*e.Feel)
+					}
+				}
+				m.editErr = nil
+			}
+			return m, nil
+		case "b":
+			if m.detail != nil {
+				m.editing = editBenchmark
+				m.input = textInput{}
+				m.editErr = nil
+			}
+			return m, nil
+		case "C":
+			if m.detail != nil {
+				if m.syncService == nil {
+					m.notice = warningStyle.Render("  Distance correction is off in demo and read-only mode")
+					return m, nil
+				}
+				m.editing = editDistance
+				m.input = textInput{}
+				m.editErr = nil
+			}
+			return m, nil
+		case "x":
+			if m.detail != nil {
+				qs, id := m.queryService, m.activityID
+				excluded := !m.detail.Activity.Activity.Excluded
+				m.editErr = nil
+				return m, func() tea.Msg {
+					return activityAnnotationSavedMsg{err: qs.SetActivityExcluded(id, excluded)}
+				}
+			}
+			return m, nil
+		case "v":
+			if !m.hasUnverifiedPRs() {
+				return m, nil
+			}
+			qs, id := m.queryService, m.activityID
+			m.editErr = nil
+			return m, func() tea.Msg {
+				if err := qs.VerifyActivityEfforts(id); err != nil {
+					return activityAnnotationSavedMsg{err: err}
+				}
+				return activityAnnotationSavedMsg{notice: "Best efforts confirmed"}
+			}
+		case "left", "h":
+			m.moveCursor(-1)
+			return m, nil
+		case "right", "l":
+			m.moveCursor(1)
+			return m, nil
+		case "shift+left", "H":
+			m.moveCursor(-cursorJumpMinutes)
+			return m, nil
+		case "shift+right", "L":
+			m.moveCursor(cursorJumpMinutes)
+			return m, nil
+		case "g":
+			m.jumpToSplit()
+			return m, nil
+		case "d":
+			if m.detail != nil {
+				id, name := m.activityID, m.detail.Activity.Activity.Name
+				return m, func() tea.Msg {
+					return OpenRawDataMsg{ActivityID: id, Name: name}
+				}
+			}
+			return m, nil
+		case "u":
+			m.bothSplits = !m.bothSplits
+			if m.detail != nil && m.ready {
+				m.viewport.SetContent(m.renderContent())
+			}
+			return m, nil
+		case "S":
+			if m.detail != nil && !m.resyncing {
+				if m.detail.Activity.Activity.Manual {
+					m.notice = warningStyle.Render("  Manual activities aren't on Strava")
+					return m, nil
+				}
+				if m.syncService == nil {
+					m.notice = warningStyle.Render("  Resync is off in demo and read-only mode")
+					return m, nil
+				}
+				ss, id := m.syncService, m.activityID
+				m.resyncing = true
+				m.notice = statusStyle.Render("  Resyncing from Strava...")
+				return m, func() tea.Msg {
+					_, err := ss.ResyncActivity(context.Background(), id)
+					return activityResyncedMsg{err: err}
+				}
+			}
+			return m, nil
 		}
 	}
 
@@ -112,6 +342,99 @@ func (m ActivityDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateEdit handles key presses while the tag, note, temperature, effort
+// or benchmark prompt is open
+func (m ActivityDetailModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case submitted:
+		field, value := m.editing, m.input.value
+		qs, id := m.queryService, m.activityID
+		switch field {
+		case editBenchmark:
+			// An existing name adds this run to it; a new one asks how later
+			// runs should match
+			m.editing = ""
+			return m, func() tea.Msg {
+				b, err := qs.BenchmarkByName(value)
+				if err != nil || b == nil {
+					return benchmarkLookedUpMsg{name: value, err: err}
+				}
+				if err := qs.SetBenchmarkAttempt(b.ID, id, true); err != nil {
+					return activityAnnotationSavedMsg{err: err}
+				}
+				return activityAnnotationSavedMsg{notice: fmt.Sprintf("Added to benchmark %q", b.Name)}
+			}
+		case editBenchmarkKind:
+			kind, err := service.ParseBenchmarkKind(value)
+			if err != nil {
+				m.editErr = err
+				return m, nil
+			}
+			m.editing = ""
+			name := m.benchmarkName
+			return m, func() tea.Msg {
+				_, err := qs.AddBenchmark(name, kind, id)
+				if err == nil {
+					return activityAnnotationSavedMsg{notice: fmt.Sprintf("Saved as benchmark %q, press B to view", name)}
+				}
+				return activityAnnotationSavedMsg{err: err}
+			}
+		}
+		if field == editDistance {
+			fix, err := service.ParseDistanceFix(value)
+			if err != nil {
+				m.editErr = err
+				return m, nil
+			}
+			m.editing = ""
+			ss := m.syncService
+			return m, func() tea.Msg {
+				if _, err := ss.CorrectDistance(context.Background(), id, fix); err != nil {
+					return activityAnnotationSavedMsg{err: err}
+				}
+				return activityAnnotationSavedMsg{notice: "Distance corrected; metrics and records recomputed"}
+			}
+		}
+		if field == editTemperature {
+			// Keep the prompt open on a typo
+			temp, err := service.ParseTemperature(value, m.units.IsMiles())
+			if err != nil {
+				m.editErr = err
+				return m, nil
+			}
+			m.editing = ""
+			return m, func() tea.Msg {
+				return activityAnnotationSavedMsg{err: qs.SetActivityTemperature(id, temp)}
+			}
+		}
+		if field == editEffort {
+			effort, err := service.ParseActivityRPE(value)
+			if err != nil {
+				m.editErr = err
+				return m, nil
+			}
+			m.editing = ""
+			return m, func() tea.Msg {
+				return activityAnnotationSavedMsg{err: qs.SetActivityRPE(id, effort)}
+			}
+		}
+		m.editing = ""
+		return m, func() tea.Msg {
+			var err error
+			if field == editTags {
+				err = qs.SetActivityTags(id, service.ParseTags(value))
+			} else {
+				err = qs.SetActivityNote(id, value)
+			}
+			return activityAnnotationSavedMsg{err: err}
+		}
+	case cancelled:
+		m.editing = ""
+	}
+	return m, nil
+}
+
 // View renders the activity detail screen
 func (m ActivityDetailModel) View() string {
 	if m.loading {
@@ -126,8 +449,42 @@ func (m ActivityDetailModel) View() string {
 		return "\n  Initializing..."
 	}
 
-	// Footer with help
-	footer := statusStyle.Render("  esc: back to list  j/k or arrows: scroll  r: refresh")
+	// Footer with help, or the open edit prompt
+	var footer string
+	switch m.editing {
+	case editTags:
+		footer = fmt.Sprintf("  Tags (comma separated): %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	case editNote:
+		footer = fmt.Sprintf("  Note: %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	case editTemperature:
+		footer = fmt.Sprintf("  Temperature (e.g. 28C or 82F, blank to clear): %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	case editEffort:
+		footer = fmt.Sprintf("  RPE 1-10, then /feel 1-5 or awful..great (e.g. 7/good, blank to clear): %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	case editBenchmark:
+		footer = fmt.Sprintf("  Benchmark name (new, or existing to add this run): %s", m.input.view()) +
+			statusStyle.Render("  enter: next  esc: cancel")
+	case editBenchmarkKind:
+		footer = fmt.Sprintf("  Match later runs by route or duration (blank for route): %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	case editDistance:
+		footer = fmt.Sprintf("  Distance correction (factor like 1.05, cadence, or cadence 1.1 for a 1.1 m stride): %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	default:
+		footer = statusStyle.Render("  esc: back to list  j/k: scroll  h/l: chart cursor  g: go to split  t: tags  n: note  T: temperature  e: effort  b: benchmark  x: exclude/include  v: confirm efforts  C: correct distance  u: splits  d: raw data  S: resync  r: refresh")
+		if m.cursor >= 0 {
+			footer = lipgloss.JoinVertical(lipgloss.Left, m.renderCursorInfo(), footer)
+		}
+	}
+	if m.notice != "" && m.editing == "" {
+		footer = lipgloss.JoinVertical(lipgloss.Left, m.notice, footer)
+	}
+	if m.editErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error saving: %v", m.editErr)), footer)
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
 }
@@ -136,18 +493,63 @@ func (m ActivityDetailModel) renderContent() string {
 	if m.detail == nil {
 		return "No data"
 	}
+	sections, _ := m.contentSections()
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
 
-	var sections []string
+// contentSections renders the screen's sections in order, along with the
+// index of the splits table in the configured unit (-1 if there isn't one)
+// so the cursor can scroll to it
+func (m ActivityDetailModel) contentSections() (sections []string, splitsAt int) {
+	splitsAt = -1
 
 	// Activity header
 	sections = append(sections, m.renderHeader())
 
+	// The athlete's description from Strava
+	if d := m.detail.Details; d != nil && d.Description != "" {
+		sections = append(sections, m.renderDescription())
+	}
+
+	// Data-quality warnings
+	if len(m.detail.Warnings) > 0 {
+		sections = append(sections, m.renderWarnings())
+	}
+
+	// Local tags, note and effort
+	if len(m.detail.Tags) > 0 || m.detail.Note != "" || m.detail.Effort != nil || m.asksEffort() {
+		sections = append(sections, m.renderAnnotations())
+	}
+
 	// Summary metrics
 	sections = append(sections, m.renderSummary())
 
-	// Mile splits
-	if len(m.detail.Splits) > 0 {
-		sections = append(sections, m.renderSplits())
+	// Metrics from analysis.custom_metrics and registered plugins
+	if len(m.detail.CustomMetrics) > 0 {
+		sections = append(sections, m.renderCustomMetrics())
+	}
+
+	// Pacing analysis
+	if m.detail.PacingGrade != "" {
+		sections = append(sections, m.renderPacing())
+	}
+
+	// Splits in the configured unit, or both units when toggled
+	showMiles := m.units.IsMiles() || m.bothSplits
+	showKm := !m.units.IsMiles() || m.bothSplits
+	if showMiles && len(m.detail.Splits) > 0 {
+		current := 0
+		if m.units.IsMiles() {
+			splitsAt, current = len(sections), m.cursorSplit()
+		}
+		sections = append(sections, renderSplits("Mile Splits", "Mile", m.detail.Splits, current))
+	}
+	if showKm && len(m.detail.KmSplits) > 0 {
+		current := 0
+		if !m.units.IsMiles() {
+			splitsAt, current = len(sections), m.cursorSplit()
+		}
+		sections = append(sections, renderSplits("Kilometer Splits", "Km", m.detail.KmSplits, current))
 	}
 
 	// HR zones
@@ -155,22 +557,158 @@ func (m ActivityDetailModel) renderContent() string {
 		sections = append(sections, m.renderHRZones())
 	}
 
-	// Pace chart
-	if len(m.detail.PaceData) > 5 {
-		sections = append(sections, m.renderPaceChart())
+	// Cadence bands and stride length
+	if len(m.detail.CadenceBands) > 0 {
+		sections = append(sections, m.renderCadenceBands())
 	}
 
-	// HR chart
+	// Time and pace by terrain, for runs with any climbing
+	if h := m.detail.Hills; h != nil && h.AscentSeconds+h.DescentSeconds > 0 {
+		sections = append(sections, m.renderHills())
+	}
+
+	// Pace, HR and cadence over time, side by side when the terminal fits
+	var charts []string
+	if len(m.detail.PaceData) > 5 {
+		charts = append(charts, m.renderPaceChart())
+	}
 	if len(m.detail.HRData) > 5 {
-		sections = append(sections, m.renderHRChart())
+		charts = append(charts, m.renderHRChart())
 	}
+	if len(m.detail.CadenceData) > 5 && hasNonZero(m.detail.CadenceData) {
+		charts = append(charts, m.renderCadenceChart())
+	}
+	sections = append(sections, m.layout().rows(charts)...)
 
 	// PRs achieved during this activity
 	if len(m.activityPRs) > 0 {
 		sections = append(sections, m.renderActivityPRs())
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return sections, splitsAt
+}
+
+// layout sizes the detail charts to the terminal width
+func (m ActivityDetailModel) layout() gridLayout {
+	return newGridLayout(m.width)
+}
+
+// minutes is the length of the per-minute stream series
+func (m ActivityDetailModel) minutes() int {
+	if m.detail == nil {
+		return 0
+	}
+	return len(m.detail.PaceData)
+}
+
+// moveCursor moves the chart cursor by delta minutes. The first move shows
+// it at the start of the run, or the end when moving left.
+func (m *ActivityDetailModel) moveCursor(delta int) {
+	n := m.minutes()
+	if n == 0 {
+		return
+	}
+	switch {
+	case m.cursor < 0 && delta > 0:
+		m.cursor = 0
+	case m.cursor < 0:
+		m.cursor = n - 1
+	default:
+		m.cursor = max(0, min(m.cursor+delta, n-1))
+	}
+	if m.ready {
+		m.viewport.SetContent(m.renderContent())
+	}
+}
+
+// cursorSplit is the split in the configured unit the cursor falls in,
+// numbered from 1, or 0 when the cursor is hidden
+func (m ActivityDetailModel) cursorSplit() int {
+	if m.cursor < 0 || m.cursor >= len(m.detail.DistanceData) {
+		return 0
+	}
+	splits, splitMeters := m.detail.Splits, metersPerMile
+	if !m.units.IsMiles() {
+		splits, splitMeters = m.detail.KmSplits, metersPerKm
+	}
+	if len(splits) == 0 {
+		return 0
+	}
+	return min(int(m.detail.DistanceData[m.cursor]/splitMeters)+1, len(splits))
+}
+
+// jumpToSplit scrolls the split under the cursor to the middle of the screen
+func (m *ActivityDetailModel) jumpToSplit() {
+	split := m.cursorSplit()
+	if split == 0 || !m.ready {
+		return
+	}
+	sections, at := m.contentSections()
+	if at < 0 {
+		return
+	}
+	// Rows start after the table's title and header lines
+	row := lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, sections[:at]...)) + 2 + split - 1
+	m.viewport.SetYOffset(max(0, row-m.viewport.Height/2))
+}
+
+// renderCursorInfo describes the run at the chart cursor
+func (m ActivityDetailModel) renderCursorInfo() string {
+	d, i := m.detail, m.cursor
+	parts := []string{d.TimeLabels[i]}
+	if i < len(d.DistanceData) {
+		parts = append(parts, m.units.FormatDistance(d.DistanceData[i]))
+	}
+	if d.PaceData[i] > 0 {
+		parts = append(parts, m.units.FormatPacePerMile(d.PaceData[i]*60))
+	}
+	if i < len(d.HRData) && d.HRData[i] > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f bpm", d.HRData[i]))
+	}
+	if i < len(d.CadenceData) && d.CadenceData[i] > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f spm", d.CadenceData[i]))
+	}
+	if split := m.cursorSplit(); split > 0 {
+		unit := "km"
+		if m.units.IsMiles() {
+			unit = "mile"
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", unit, split))
+	}
+	marker := lipgloss.NewStyle().Foreground(accentColor).Render("  ▸ ")
+	return marker + strings.Join(parts, "  ")
+}
+
+// renderStreamChart plots a per-minute series at one point per braille dot
+// column, marking the cursor
+func (m ActivityDetailModel) renderStreamChart(data []float64, precision int) string {
+	w := m.layout().plotWidth(detailChartWidth)
+	labels := m.detail.TimeLabels
+	cursor := m.cursor
+
+	if n := w * brailleCellW; len(data) > n {
+		ratio := float64(len(data)) / float64(n)
+		data = downsample(data, n)
+		sampled := make([]string, n)
+		for i := range sampled {
+			sampled[i] = labels[min(int(float64(i)*ratio), len(labels)-1)]
+		}
+		labels = sampled
+		if cursor >= 0 {
+			cursor = int(float64(cursor) / ratio)
+		}
+	}
+	data = trimTrailingZeros(data)
+
+	chart := lineChart{
+		Width:      w,
+		Height:     detailChartHeight,
+		Precision:  precision,
+		XLabels:    labels[:min(len(data), len(labels))],
+		Cursor:     cursor,
+		ShowCursor: cursor >= 0,
+	}
+	return chart.render(data)
 }
 
 func (m ActivityDetailModel) renderHeader() string {
@@ -179,6 +717,23 @@ func (m ActivityDetailModel) renderHeader() string {
 
 	// Date and basic stats
 	date := a.StartDateLocal.Format("Monday, January 2, 2006 at 3:04 PM")
+	if a.Manual {
+		date += "  •  manual entry"
+	}
+	if a.Excluded {
+		date += "  •  excluded from analysis"
+	}
+	if t := m.detail.Trim; t != nil {
+		date += fmt.Sprintf("  •  trimmed at %s (was %s)", formatClock(t.EndOffset), m.units.FormatDistance(t.OriginalDistance))
+	}
+	if c := m.detail.Correction; c != nil {
+		if c.StrideLength != nil {
+			date += fmt.Sprintf("  •  distance from cadence, %.2f m stride", *c.StrideLength)
+		} else {
+			date += "  •  distance calibrated"
+		}
+		date += fmt.Sprintf(" (was %s)", m.units.FormatDistance(c.OriginalDistance))
+	}
 	duration := formatDuration(a.MovingTime)
 	pace := m.units.FormatPaceWithUnit(a.MovingTime, a.Distance)
 
@@ -187,7 +742,80 @@ func (m ActivityDetailModel) renderHeader() string {
 	stats := fmt.Sprintf("%s  •  %s  •  %s", m.units.FormatDistance(a.Distance), duration, pace)
 	statsLine := lipgloss.NewStyle().Foreground(textColor).Bold(true).Render(stats)
 
-	return lipgloss.JoinVertical(lipgloss.Left, "", title, subtitle, statsLine, "")
+	lines := []string{"", title, subtitle, statsLine}
+	if d := m.detail.Details; d != nil {
+		var gear []string
+		if d.DeviceName != "" {
+			gear = append(gear, d.DeviceName)
+		}
+		if d.GearName != "" {
+			gear = append(gear, "shoes: "+d.GearName)
+		}
+		if len(gear) > 0 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(mutedColor).Render(strings.Join(gear, "  •  ")))
+		}
+	}
+	if s := m.detail.Activity.Social; s != nil {
+		muted := lipgloss.NewStyle().Foreground(mutedColor)
+		counts := fmt.Sprintf("%d kudos  •  %d comments  •  %d photos", s.KudosCount, s.CommentCount, s.PhotoCount)
+		lines = append(lines, muted.Render(counts))
+		if s.PhotoURL != "" {
+			lines = append(lines, muted.Render("photo: "+s.PhotoURL))
+		}
+	}
+	lines = append(lines, "")
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderDescription shows the description written on Strava, wrapped to
+// the screen
+func (m ActivityDetailModel) renderDescription() string {
+	style := lipgloss.NewStyle().Foreground(textColor).PaddingLeft(2)
+	if m.width > 8 {
+		style = style.Width(m.width - 4)
+	}
+	return style.Render(m.detail.Details.Description) + "\n"
+}
+
+func (m ActivityDetailModel) renderWarnings() string {
+	style := lipgloss.NewStyle().Foreground(warningColor)
+
+	var lines []string
+	for _, w := range m.detail.Warnings {
+		lines = append(lines, style.Render("  ⚠ "+w))
+	}
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+// asksEffort reports whether to prompt for the effort of a recent run that
+// has none logged
+func (m ActivityDetailModel) asksEffort() bool {
+	return m.detail.Effort == nil && time.Since(m.detail.Activity.Activity.StartDate) < effortPromptAge
+}
+
+func (m ActivityDetailModel) renderAnnotations() string {
+	var lines []string
+
+	if len(m.detail.Tags) > 0 {
+		tags := make([]string, len(m.detail.Tags))
+		for i, tag := range m.detail.Tags {
+			tags[i] = "#" + tag
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render("  "+strings.Join(tags, "  ")))
+	}
+	if m.detail.Note != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(mutedColor).Italic(true).Render("  "+m.detail.Note))
+	}
+	switch {
+	case m.detail.Effort != nil:
+		lines = append(lines, "  "+service.FormatEffort(*m.detail.Effort))
+	case m.asksEffort():
+		lines = append(lines, warningStyle.Render("  How did it feel? Press e to log RPE and feel"))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
 }
 
 func (m ActivityDetailModel) renderSummary() string {
@@ -201,8 +829,19 @@ func (m ActivityDetailModel) renderSummary() string {
 	efStr := "-"
 	if met.EfficiencyFactor != nil {
 		efStr = fmt.Sprintf("%.2f", *met.EfficiencyFactor)
+		if m.detail.AdjustedEF > 0 {
+			efStr += lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf("  (%.2f heat-adjusted)", m.detail.AdjustedEF))
+		}
 	}
 	lines = append(lines, fmt.Sprintf("  Efficiency Factor:    %s", efStr))
+	if m.detail.TemperatureC != nil {
+		lines = append(lines, fmt.Sprintf("  Temperature:          %s", m.units.FormatTemperature(*m.detail.TemperatureC)))
+	}
+	if p := m.detail.Performance; p != nil {
+		lines = append(lines, fmt.Sprintf("  Performance Score:    %.1f", p.Score)+
+			lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf("  (%s for a flat, cool 10K)",
+				m.units.FormatPaceWithUnit(p.EquivalentSeconds, analysis.Distance10K))))
+	}
 
 	// Decoupling
 	decStr := "-"
@@ -228,35 +867,83 @@ func (m ActivityDetailModel) renderSummary() string {
 		lines = append(lines, fmt.Sprintf("  Max HR:               %d bpm", m.detail.MaxHR))
 	}
 
+	// HR recovery after the hardest effort
+	if met.HRR60 != nil {
+		lines = append(lines, fmt.Sprintf("  HR Recovery (60s):    %.0f bpm", *met.HRR60))
+	}
+
 	// Avg Cadence
 	if m.detail.AvgCadence > 0 {
 		lines = append(lines, fmt.Sprintf("  Average Cadence:      %.0f spm", m.detail.AvgCadence))
 	}
 
+	// Energy as Strava estimated it
+	if d := m.detail.Details; d != nil && d.Calories > 0 {
+		lines = append(lines, fmt.Sprintf("  Calories:             %.0f kcal", d.Calories))
+	}
+
+	// Time stopped or auto-paused, which EF and decoupling leave out
+	if n := len(m.detail.Stops); n > 0 {
+		stops := "stop"
+		if n > 1 {
+			stops = "stops"
+		}
+		lines = append(lines, fmt.Sprintf("  Stopped:              %s in %d %s", formatDuration(m.detail.StoppedTime), n, stops)+
+			lipgloss.NewStyle().Foreground(mutedColor).Render(fmt.Sprintf("  (elapsed %s)", formatDuration(m.detail.Activity.Activity.ElapsedTime))))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+func (m ActivityDetailModel) renderCustomMetrics() string {
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Custom Metrics"))
+
+	for _, c := range m.detail.CustomMetrics {
+		value := strconv.FormatFloat(math.Round(c.Value*100)/100, 'f', -1, 64)
+		lines = append(lines, fmt.Sprintf("  %-22s%s", c.Label+":", value))
+	}
+
 	lines = append(lines, "")
 	return strings.Join(lines, "\n")
 }
 
-func (m ActivityDetailModel) renderSplits() string {
+func (m ActivityDetailModel) renderPacing() string {
 	var lines []string
 
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Mile Splits"))
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Pacing"))
 
-	// Header
-	// Splits are calculated per mile
-	header := fmt.Sprintf("  %-6s  %8s  %6s  %6s", "Mile", "Pace", "HR", "Cadence")
+	met := m.detail.Activity.Metrics
+	lines = append(lines, fmt.Sprintf("  Half-to-half split:   %+.1f%% (%s)", *met.PacingSplit, m.detail.PacingGrade))
+	if met.PaceVariability != nil {
+		lines = append(lines, fmt.Sprintf("  Split variability:    %.1f%%", *met.PaceVariability))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+// renderSplits renders a table of splits, highlighting the fastest
+func renderSplits(title, unitLabel string, splits []service.MileSplit, current int) string {
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(title))
+
+	// Header; pace is per split distance
+	header := fmt.Sprintf("  %-6s  %8s  %6s  %6s", unitLabel, "Pace", "HR", "Cadence")
 	lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(header))
-	// Note: Pace shown here is always per-mile as calculated by service
 
 	// Find fastest split for highlighting
 	fastestPace := 9999
-	for _, s := range m.detail.Splits {
+	for _, s := range splits {
 		if s.Duration > 0 && s.Duration < fastestPace {
 			fastestPace = s.Duration
 		}
 	}
 
-	for _, s := range m.detail.Splits {
+	for _, s := range splits {
 		hrStr := "-"
 		if s.AvgHR > 0 {
 			hrStr = fmt.Sprintf("%.0f", s.AvgHR)
@@ -269,8 +956,10 @@ func (m ActivityDetailModel) renderSplits() string {
 
 		row := fmt.Sprintf("  %-6d  %8s  %6s  %6s", s.Mile, s.Pace, hrStr, cadStr)
 
-		// Highlight fastest split
-		if s.Duration == fastestPace {
+		// Highlight the split under the chart cursor, then the fastest
+		if s.Mile == current {
+			lines = append(lines, tableSelectedStyle.Render("▸"+row[1:]))
+		} else if s.Duration == fastestPace {
 			lines = append(lines, lipgloss.NewStyle().Foreground(secondaryColor).Bold(true).Render(row))
 		} else {
 			lines = append(lines, row)
@@ -292,13 +981,7 @@ func (m ActivityDetailModel) renderHRZones() string {
 	}
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(title))
 
-	zoneColors := []lipgloss.Color{
-		lipgloss.Color("#10B981"), // Zone 1 - Green (recovery)
-		lipgloss.Color("#3B82F6"), // Zone 2 - Blue (aerobic)
-		lipgloss.Color("#F59E0B"), // Zone 3 - Amber (tempo)
-		lipgloss.Color("#EF4444"), // Zone 4 - Red (threshold)
-		lipgloss.Color("#9333EA"), // Zone 5 - Purple (VO2max)
-	}
+	zoneColors := hrZoneColors()
 
 	maxBarWidth := 30
 	for i, z := range m.detail.HRZones {
@@ -327,55 +1010,106 @@ func (m ActivityDetailModel) renderPaceChart() string {
 
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(fmt.Sprintf("Pace Over Time (%s)", m.units.PaceLabel())))
 
-	// Filter out zeros and prepare data
 	// PaceData is in min/mi, convert if user prefers min/km
 	data := m.units.ConvertPaceData(m.detail.PaceData)
-	if len(data) > 60 {
-		// Downsample for very long runs
-		data = downsample(data, 60)
+	lines = append(lines, m.renderStreamChart(data, 1))
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+func (m ActivityDetailModel) renderHRChart() string {
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Heart Rate Over Time (bpm)"))
+	lines = append(lines, m.renderStreamChart(m.detail.HRData, 0))
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+func (m ActivityDetailModel) renderCadenceBands() string {
+	var lines []string
+
+	title := "Cadence Distribution"
+	if stride := m.detail.Activity.Metrics.AvgStrideLength; stride != nil {
+		if m.units.IsMiles() {
+			title += fmt.Sprintf(" (avg stride %.2f ft)", *stride*3.28084)
+		} else {
+			title += fmt.Sprintf(" (avg stride %.2f m)", *stride)
+		}
 	}
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(title))
 
-	// Trim trailing zeros
-	data = trimTrailingZeros(data)
+	maxBarWidth := 30
+	for _, b := range m.detail.CadenceBands {
+		barWidth := int(b.Percent / 100 * float64(maxBarWidth))
+		if barWidth < 1 && b.Seconds > 0 {
+			barWidth = 1
+		}
 
-	if len(data) > 2 {
-		chart := asciigraph.Plot(data,
-			asciigraph.Height(8),
-			asciigraph.Width(50),
-		)
-		lines = append(lines, chart)
+		bar := lipgloss.NewStyle().Foreground(primaryColor).Render(strings.Repeat("█", barWidth))
+		label := fmt.Sprintf("  %-13s", b.Name)
+		lines = append(lines, label+bar+fmt.Sprintf(" %5.1f%% (%s)", b.Percent, formatDuration(b.Seconds)))
 	}
 
 	lines = append(lines, "")
 	return strings.Join(lines, "\n")
 }
 
-func (m ActivityDetailModel) renderHRChart() string {
+func (m ActivityDetailModel) renderHills() string {
+	h := m.detail.Hills
 	var lines []string
 
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Heart Rate Over Time (bpm)"))
+	title := fmt.Sprintf("Hills (climbed %s, descended %s)", m.units.FormatElevation(h.Climbed), m.units.FormatElevation(h.Descended))
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(title))
 
-	// Filter and prepare data
-	data := m.detail.HRData
-	if len(data) > 60 {
-		data = downsample(data, 60)
+	total := h.AscentSeconds + h.FlatSeconds + h.DescentSeconds
+	terrain := []struct {
+		name     string
+		seconds  int
+		distance float64
+	}{
+		{"Uphill", h.AscentSeconds, h.AscentDistance},
+		{"Flat", h.FlatSeconds, h.FlatDistance},
+		{"Downhill", h.DescentSeconds, h.DescentDistance},
 	}
 
-	// Trim trailing zeros
-	data = trimTrailingZeros(data)
+	maxBarWidth := 30
+	for _, t := range terrain {
+		percent := float64(t.seconds) / float64(total) * 100
+		barWidth := int(percent / 100 * float64(maxBarWidth))
+		if barWidth < 1 && t.seconds > 0 {
+			barWidth = 1
+		}
 
-	if len(data) > 2 {
-		chart := asciigraph.Plot(data,
-			asciigraph.Height(8),
-			asciigraph.Width(50),
-		)
-		lines = append(lines, chart)
+		bar := lipgloss.NewStyle().Foreground(primaryColor).Render(strings.Repeat("█", barWidth))
+		label := fmt.Sprintf("  %-13s", t.name)
+		stats := fmt.Sprintf(" %5.1f%% (%s)", percent, formatDuration(t.seconds))
+		if t.seconds > 0 {
+			stats += "  " + m.units.FormatPaceWithUnit(t.seconds, t.distance)
+		}
+		lines = append(lines, label+bar+stats)
+	}
+
+	if h.AscentSeconds > 0 {
+		lines = append(lines, fmt.Sprintf("  Vertical speed uphill: %s/h", m.units.FormatElevation(h.VerticalSpeed())))
 	}
 
 	lines = append(lines, "")
 	return strings.Join(lines, "\n")
 }
 
+func (m ActivityDetailModel) renderCadenceChart() string {
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Cadence Over Time (spm)"))
+	lines = append(lines, m.renderStreamChart(m.detail.CadenceData, 0))
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
 func (m ActivityDetailModel) renderActivityPRs() string {
 	var lines []string
 
@@ -392,14 +1126,35 @@ func (m ActivityDetailModel) renderActivityPRs() string {
 			prType = "Distance PR"
 		}
 
-		line := fmt.Sprintf("  %s %s: %s (%s/mi)", prType, pr.CategoryLabel, pr.Time, pr.Pace)
+		line := fmt.Sprintf("  %s %s: %s (%s)", prType, pr.CategoryLabel, pr.Time, m.units.FormatPacePerMile(pr.PacePerMile))
+		if pr.HasPrevious {
+			line += fmt.Sprintf("  %s vs previous best", formatPRMargin(m.units, pr.Category, pr.Margin, pr.Mode))
+		}
+		if pr.Unverified {
+			lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(line)+warningStyle.Render("  ⚠ unverified"))
+			continue
+		}
 		lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(line))
 	}
+	if m.hasUnverifiedPRs() {
+		lines = append(lines, lipgloss.NewStyle().Foreground(mutedColor).Render("  Unverified: doubtful GPS, or far faster than your record. Press v if it was real."))
+	}
 
 	lines = append(lines, "")
 	return strings.Join(lines, "\n")
 }
 
+// hasUnverifiedPRs reports whether any record set this run awaits
+// confirmation
+func (m ActivityDetailModel) hasUnverifiedPRs() bool {
+	for _, pr := range m.activityPRs {
+		if pr.Unverified {
+			return true
+		}
+	}
+	return false
+}
+
 func downsample(data []float64, targetLen int) []float64 {
 	if len(data) <= targetLen {
 		return data
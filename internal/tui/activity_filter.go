@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"runner/internal/store"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// parseActivityFilter parses the activities search box into a store filter.
+//
+// Bare words are matched against the activity name and note. Recognised tokens:
+//
+//	type:<type>         activity type, e.g. type:run
+//	tag:<tag>           local tag, e.g. tag:race
+//	after:<YYYY-MM-DD>  on or after the date
+//	before:<YYYY-MM-DD> before the date
+//	dist:<min>-<max>    distance range in the display unit; either end may be
+//	                    omitted, and dist:<min> means at least <min>
+//	pr                  only activities holding a personal record
+func parseActivityFilter(input string, units Units) (store.ActivityFilter, error) {
+	var f store.ActivityFilter
+	var words []string
+
+	for _, tok := range strings.Fields(input) {
+		key, value, hasValue := strings.Cut(tok, ":")
+		if !hasValue {
+			if strings.EqualFold(tok, "pr") {
+				f.HasPR = true
+			} else {
+				words = append(words, tok)
+			}
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "type":
+			f.Type = value
+		case "tag":
+			f.Tag = value
+		case "after":
+			t, err := time.ParseInLocation(filterDateLayout, value, time.UTC)
+			if err != nil {
+				return f, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", value)
+			}
+			f.After = t
+		case "before":
+			t, err := time.ParseInLocation(filterDateLayout, value, time.UTC)
+			if err != nil {
+				return f, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", value)
+			}
+			f.Before = t
+		case "dist":
+			lo, hi, err := parseDistanceRange(value, units)
+			if err != nil {
+				return f, err
+			}
+			f.MinDistance, f.MaxDistance = lo, hi
+		default:
+			words = append(words, tok)
+		}
+	}
+
+	f.Name = strings.Join(words, " ")
+	return f, nil
+}
+
+// parseDistanceRange parses "min-max" in the display unit and returns meters.
+func parseDistanceRange(value string, units Units) (lo, hi float64, err error) {
+	minStr, maxStr, _ := strings.Cut(value, "-")
+
+	scale := metersPerKm
+	if units.IsMiles() {
+		scale = metersPerMile
+	}
+
+	if minStr != "" {
+		v, err := strconv.ParseFloat(minStr, 64)
+		if err != nil || v < 0 {
+			return 0, 0, fmt.Errorf("invalid distance %q", minStr)
+		}
+		lo = v * scale
+	}
+	if maxStr != "" {
+		v, err := strconv.ParseFloat(maxStr, 64)
+		if err != nil || v < 0 {
+			return 0, 0, fmt.Errorf("invalid distance %q", maxStr)
+		}
+		hi = v * scale
+	}
+	return lo, hi, nil
+}
@@ -1,8 +1,10 @@
 package tui
 
 import (
+	"fmt"
+	"reflect"
+
 	"runner/internal/config"
-	"runner/internal/service"
 	"runner/internal/store"
 	"runner/internal/strava"
 
@@ -17,11 +19,25 @@ const (
 	ScreenDashboard Screen = iota
 	ScreenActivities
 	ScreenActivityDetail
+	ScreenRawData
 	ScreenStats
 	ScreenComparisons
 	ScreenPRs
 	ScreenPredictions
+	ScreenDistribution
+	ScreenLogs
+	ScreenRequests
+	ScreenReview
+	ScreenCriticalPace
+	ScreenRaces
+	ScreenSegments
+	ScreenInjuries
+	ScreenPlan
+	ScreenBenchmarks
+	ScreenTrends
+	ScreenPaceAtHR
 	ScreenSync
+	ScreenSettings
 	ScreenHelp
 )
 
@@ -34,51 +50,124 @@ type App struct {
 	dashboard      DashboardModel
 	activities     ActivitiesModel
 	activityDetail ActivityDetailModel
+	rawData        RawDataModel
 	stats          StatsModel
 	comparisons    ComparisonsModel
 	prs            PRsModel
 	predictions    PredictionsModel
+	distribution   DistributionModel
+	logs           LogsModel
+	requests       RequestsModel
+	review         ReviewModel
+	criticalPace   CriticalPaceModel
+	races          RacesModel
+	segments       SegmentsModel
+	injuries       InjuriesModel
+	plan           PlanModel
+	benchmarks     BenchmarksModel
+	trends         TrendsModel
+	paceAtHR       PaceAtHRModel
 	syncScreen     SyncModel
+	settings       SettingsModel
 	help           HelpModel
 
 	// Services
 	db           *store.Store
-	queryService *service.QueryService
-	syncService  *service.SyncService
+	queryService QueryProvider
+	syncService  SyncRunner
 	stravaClient *strava.Client
 
+	// Config as last saved, and how to save it; nil in demo mode
+	cfg        config.Config
+	saveConfig func(*config.Config) error
+
 	// Display config
 	units Units
 
+	// Log file shown on the logs screen
+	logPath string
+
 	// Window dimensions
 	width  int
 	height int
 
 	// Status message
 	status string
+
+	// syncOnStart opens the sync screen with a sync running, after setup
+	syncOnStart bool
+
+	// Config reloaded from disk during a sync, applied once it finishes
+	pendingConfig *config.Config
+
+	// readOnly browses a database without changing it
+	readOnly bool
 }
 
-// NewApp creates a new App with all dependencies
-func NewApp(db *store.Store, stravaClient *strava.Client, syncService *service.SyncService, queryService *service.QueryService, displayCfg config.DisplayConfig) *App {
-	units := NewUnits(displayCfg)
+// NewApp creates a new App with all dependencies. syncService may be nil to
+// browse a database offline, as demo mode does; sync and resync are then
+// unavailable.
+func NewApp(db *store.Store, stravaClient *strava.Client, syncService SyncRunner, queryService QueryProvider, cfg config.Config, logPath string) *App {
+	units := NewUnits(cfg.Display)
 	return &App{
 		screen:       ScreenDashboard,
+		cfg:          cfg,
 		db:           db,
 		queryService: queryService,
 		syncService:  syncService,
 		stravaClient: stravaClient,
 		units:        units,
+		logPath:      logPath,
 		dashboard:    NewDashboardModel(queryService, units, 0, 0),
 		activities:   NewActivitiesModel(queryService, units),
 		stats:        NewStatsModel(queryService, units),
 		comparisons:  NewComparisonsModel(queryService, units, 0, 0),
-		syncScreen:   NewSyncModel(syncService),
+		syncScreen:   NewSyncModel(syncService, units),
 		help:         NewHelpModel(),
 	}
 }
 
+// SetConfigSaver sets how the settings screen saves the config. Without
+// one, settings changes only last for the session.
+func (a *App) SetConfigSaver(save func(*config.Config) error) {
+	a.saveConfig = save
+}
+
+// SetReadOnly browses the database without changing it: sync is off and
+// edits on every screen are refused. The store should be opened read-only
+// too, so nothing else can write.
+func (a *App) SetReadOnly() {
+	a.readOnly = true
+	a.queryService = readOnlyQueries{a.queryService}
+	a.syncService = nil
+	a.syncOnStart = false
+
+	a.dashboard = NewDashboardModel(a.queryService, a.units, a.width, a.height)
+	a.activities = NewActivitiesModel(a.queryService, a.units)
+	a.stats = NewStatsModel(a.queryService, a.units)
+	a.syncScreen = NewSyncModel(nil, a.units)
+}
+
+// SetStatus shows a message in the footer until something replaces it, for
+// warnings found before the TUI starts
+func (a *App) SetStatus(status string) {
+	a.status = status
+}
+
+// StartWithSync makes the app open on the sync screen with a sync
+// running, as the first-run setup asks for
+func (a *App) StartWithSync() {
+	a.syncOnStart = a.syncService != nil
+}
+
 // Init initializes the app
 func (a *App) Init() tea.Cmd {
+	if a.syncOnStart {
+		a.screen = ScreenSync
+		var cmd tea.Cmd
+		a.syncScreen, cmd = a.syncScreen.startSync()
+		return tea.Batch(a.dashboard.Init(), cmd)
+	}
 	return a.dashboard.Init()
 }
 
@@ -86,8 +175,8 @@ func (a *App) Init() tea.Cmd {
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Global keybindings (unless in sync mode)
-		if a.screen != ScreenSync || !a.syncScreen.syncing {
+		// Global keybindings (unless in sync mode or typing in a search box)
+		if !a.capturingInput() {
 			switch msg.String() {
 			case "q", "ctrl+c":
 				return a, tea.Quit
@@ -103,7 +192,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return a, a.stats.Init()
 			case "4", "c":
 				a.screen = ScreenComparisons
-				a.comparisons = NewComparisonsModel(a.queryService, a.units, a.width, a.height)
+				a.comparisons = a.newComparisons()
 				return a, a.comparisons.Init()
 			case "5":
 				a.screen = ScreenPRs
@@ -118,6 +207,58 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					a.screen = ScreenSync
 					return a, a.syncScreen.Init()
 				}
+			case "8":
+				a.screen = ScreenDistribution
+				a.distribution = NewDistributionModel(a.queryService, a.width, a.height)
+				return a, a.distribution.Init()
+			case "9":
+				a.screen = ScreenLogs
+				a.logs = NewLogsModel(a.logPath, a.width, a.height)
+				return a, a.logs.Init()
+			case "A":
+				a.screen = ScreenRequests
+				a.requests = NewRequestsModel(a.syncService, a.width, a.height)
+				return a, a.requests.Init()
+			case "0":
+				a.screen = ScreenReview
+				a.review = NewReviewModel(a.queryService, a.units, a.width, a.height)
+				return a, a.review.Init()
+			case "p":
+				a.screen = ScreenCriticalPace
+				a.criticalPace = NewCriticalPaceModel(a.queryService, a.units, a.width, a.height)
+				return a, a.criticalPace.Init()
+			case "R":
+				a.screen = ScreenRaces
+				a.races = NewRacesModel(a.queryService, a.units, a.width, a.height)
+				return a, a.races.Init()
+			case "F":
+				a.screen = ScreenSegments
+				a.segments = NewSegmentsModel(a.queryService, a.units, a.width, a.height)
+				return a, a.segments.Init()
+			case "I":
+				a.screen = ScreenInjuries
+				a.injuries = NewInjuriesModel(a.queryService, a.units, a.width, a.height)
+				return a, a.injuries.Init()
+			case "P":
+				a.screen = ScreenPlan
+				a.plan = NewPlanModel(a.queryService, a.units, a.width, a.height)
+				return a, a.plan.Init()
+			case "B":
+				a.screen = ScreenBenchmarks
+				a.benchmarks = NewBenchmarksModel(a.queryService, a.units, a.width, a.height)
+				return a, a.benchmarks.Init()
+			case "L":
+				a.screen = ScreenTrends
+				a.trends = NewTrendsModel(a.queryService, a.units, a.width, a.height)
+				return a, a.trends.Init()
+			case "H":
+				a.screen = ScreenPaceAtHR
+				a.paceAtHR = NewPaceAtHRModel(a.queryService, a.units, a.width, a.height)
+				return a, a.paceAtHR.Init()
+			case ",":
+				a.screen = ScreenSettings
+				a.settings = NewSettingsModel(a.cfg, a.saveConfig)
+				return a, a.settings.Init()
 			case "?":
 				a.prevScreen = a.screen
 				a.screen = ScreenHelp
@@ -127,6 +268,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					a.screen = a.prevScreen
 					return a, nil
 				}
+				if a.screen == ScreenRawData {
+					a.screen = ScreenActivityDetail
+					if a.rawData.trimmed {
+						return a, a.activityDetail.Init()
+					}
+					return a, nil
+				}
 				if a.screen == ScreenActivityDetail {
 					a.screen = ScreenActivities
 					return a, a.activities.Init()
@@ -138,7 +286,20 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 
+	case SettingsSavedMsg:
+		if msg.Err == nil {
+			a.applyConfig(msg.Config)
+		}
+
+	case ConfigReloadedMsg:
+		return a, a.reloadConfig(msg)
+
 	case SyncCompleteMsg:
+		if a.pendingConfig != nil {
+			a.applyReloadedConfig(*a.pendingConfig)
+			a.pendingConfig = nil
+		}
+
 		// Refresh dashboard after sync
 		a.screen = ScreenDashboard
 		a.dashboard = NewDashboardModel(a.queryService, a.units, a.width, a.height)
@@ -146,8 +307,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case OpenActivityDetailMsg:
 		a.screen = ScreenActivityDetail
-		a.activityDetail = NewActivityDetailModel(a.queryService, a.units, msg.ActivityID, a.width, a.height)
+		a.activityDetail = NewActivityDetailModel(a.queryService, a.syncService, a.units, msg.ActivityID, a.width, a.height)
 		return a, a.activityDetail.Init()
+
+	case OpenRawDataMsg:
+		a.screen = ScreenRawData
+		a.rawData = NewRawDataModel(a.queryService, a.syncService, a.units, msg.ActivityID, msg.Name, a.width, a.height)
+		a.rawData.zones = a.cfg.Privacy.Zones
+		return a, a.rawData.Init()
 	}
 
 	// Delegate to current screen
@@ -165,6 +332,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var m tea.Model
 		m, cmd = a.activityDetail.Update(msg)
 		a.activityDetail = m.(ActivityDetailModel)
+	case ScreenRawData:
+		var m tea.Model
+		m, cmd = a.rawData.Update(msg)
+		a.rawData = m.(RawDataModel)
 	case ScreenStats:
 		var m tea.Model
 		m, cmd = a.stats.Update(msg)
@@ -181,10 +352,63 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var m tea.Model
 		m, cmd = a.predictions.Update(msg)
 		a.predictions = m.(PredictionsModel)
+	case ScreenDistribution:
+		var m tea.Model
+		m, cmd = a.distribution.Update(msg)
+		a.distribution = m.(DistributionModel)
+	case ScreenLogs:
+		var m tea.Model
+		m, cmd = a.logs.Update(msg)
+		a.logs = m.(LogsModel)
+	case ScreenRequests:
+		var m tea.Model
+		m, cmd = a.requests.Update(msg)
+		a.requests = m.(RequestsModel)
+	case ScreenReview:
+		var m tea.Model
+		m, cmd = a.review.Update(msg)
+		a.review = m.(ReviewModel)
+	case ScreenCriticalPace:
+		var m tea.Model
+		m, cmd = a.criticalPace.Update(msg)
+		a.criticalPace = m.(CriticalPaceModel)
+	case ScreenRaces:
+		var m tea.Model
+		m, cmd = a.races.Update(msg)
+		a.races = m.(RacesModel)
+	case ScreenSegments:
+		var m tea.Model
+		m, cmd = a.segments.Update(msg)
+		a.segments = m.(SegmentsModel)
+	case ScreenInjuries:
+		var m tea.Model
+		m, cmd = a.injuries.Update(msg)
+		a.injuries = m.(InjuriesModel)
+	case ScreenPlan:
+		var m tea.Model
+		m, cmd = a.plan.Update(msg)
+		a.plan = m.(PlanModel)
+	case ScreenBenchmarks:
+		var m tea.Model
+		m, cmd = a.benchmarks.Update(msg)
+		a.benchmarks = m.(BenchmarksModel)
+	case ScreenTrends:
+		var m tea.Model
+		m, cmd = a.trends.Update(msg)
+		a.trends = m.(TrendsModel)
+	case ScreenPaceAtHR:
+		var m tea.Model
+		m, cmd = a.paceAtHR.Update(msg)
+		a.paceAtHR = m.(PaceAtHRModel)
 	case ScreenSync:
 		var m tea.Model
 		m, cmd = a.syncScreen.Update(msg)
 		a.syncScreen = m.(SyncModel)
+	case ScreenSettings:
+		a.settings.syncing = a.syncScreen.syncing
+		var m tea.Model
+		m, cmd = a.settings.Update(msg)
+		a.settings = m.(SettingsModel)
 	case ScreenHelp:
 		var m tea.Model
 		m, cmd = a.help.Update(msg)
@@ -207,6 +431,8 @@ func (a *App) View() string {
 		content = a.activities.View()
 	case ScreenActivityDetail:
 		content = a.activityDetail.View()
+	case ScreenRawData:
+		content = a.rawData.View()
 	case ScreenStats:
 		content = a.stats.View()
 	case ScreenComparisons:
@@ -215,8 +441,34 @@ func (a *App) View() string {
 		content = a.prs.View()
 	case ScreenPredictions:
 		content = a.predictions.View()
+	case ScreenDistribution:
+		content = a.distribution.View()
+	case ScreenLogs:
+		content = a.logs.View()
+	case ScreenRequests:
+		content = a.requests.View()
+	case ScreenReview:
+		content = a.review.View()
+	case ScreenCriticalPace:
+		content = a.criticalPace.View()
+	case ScreenRaces:
+		content = a.races.View()
+	case ScreenSegments:
+		content = a.segments.View()
+	case ScreenInjuries:
+		content = a.injuries.View()
+	case ScreenPlan:
+		content = a.plan.View()
+	case ScreenBenchmarks:
+		content = a.benchmarks.View()
+	case ScreenTrends:
+		content = a.trends.View()
+	case ScreenPaceAtHR:
+		content = a.paceAtHR.View()
 	case ScreenSync:
 		content = a.syncScreen.View()
+	case ScreenSettings:
+		content = a.settings.View()
 	case ScreenHelp:
 		content = a.help.View()
 	}
@@ -226,7 +478,187 @@ func (a *App) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, nav, content, footer)
 }
 
+// newComparisons rebuilds the comparisons screen, keeping the custom
+// comparisons built on it this session
+func (a *App) newComparisons() ComparisonsModel {
+	m := NewComparisonsModel(a.queryService, a.units, a.width, a.height)
+	m.custom = a.comparisons.custom
+	return m
+}
+
+// capturingInput reports whether the current screen needs every key press,
+// so global keybindings must not fire
+func (a *App) capturingInput() bool {
+	switch a.screen {
+	case ScreenDashboard:
+		return a.dashboard.editing
+	case ScreenSync:
+		return a.syncScreen.syncing || a.syncScreen.previewing
+	case ScreenActivities:
+		return a.activities.searching
+	case ScreenActivityDetail:
+		return a.activityDetail.editing != ""
+	case ScreenRawData:
+		return a.rawData.confirmTrim >= 0
+	case ScreenRaces:
+		return a.races.editing
+	case ScreenInjuries:
+		return a.injuries.editing
+	case ScreenPlan:
+		return a.plan.editing
+	case ScreenComparisons:
+		return a.comparisons.building
+	case ScreenSettings:
+		return a.settings.editing
+	}
+	return false
+}
+
+// applyConfig switches to saved settings without a restart: units and theme
+// redraw every screen, and the services take the new HR and analysis values
+func (a *App) applyConfig(cfg config.Config) {
+	hrChanged := hrValuesChanged(a.cfg.Athlete, cfg.Athlete)
+	a.cfg = cfg
+	a.units = NewUnits(cfg.Display)
+	if err := ApplyTheme(cfg.Display); err != nil {
+		a.status = fmt.Sprintf("Theme not applied: %v", err)
+	}
+
+	a.queryService.SetAthleteConfig(cfg.Athlete)
+	a.queryService.SetRiegelExponent(cfg.Analysis.RiegelExponent)
+	a.queryService.SetRestDayWarningDays(cfg.Analysis.RestDayWarningDays)
+	a.queryService.SetRampThreshold(cfg.Analysis.RampThresholdPct)
+	a.queryService.SetWeekNumbers(cfg.Display.WeekNumbers)
+	if err := a.queryService.SetBucketing(cfg.Analysis); err != nil {
+		a.status = fmt.Sprintf("Week and day buckets not applied: %v", err)
+	}
+	if err := a.queryService.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		a.status = fmt.Sprintf("Load model not applied: %v", err)
+	}
+	if a.syncService != nil {
+		a.syncService.SetAthleteConfig(cfg.Athlete)
+		a.syncService.SetAnalysisConfig(cfg.Analysis)
+		a.syncService.SetPrivacyConfig(cfg.Privacy)
+		a.syncService.SetStorageConfig(cfg.Storage)
+	}
+
+	// Screens kept between visits are rebuilt with the new units
+	a.dashboard = NewDashboardModel(a.queryService, a.units, a.width, a.height)
+	a.activities = NewActivitiesModel(a.queryService, a.units)
+	a.stats = NewStatsModel(a.queryService, a.units)
+	a.syncScreen = NewSyncModel(a.syncService, a.units)
+
+	// Past runs keep metrics from the old HR values until recomputed
+	a.syncScreen.recompute = hrChanged
+}
+
+// reloadConfig applies config.json after it was edited outside the app.
+// A change made during a sync waits for it to finish, since the sync
+// computes metrics with the HR values it started with.
+func (a *App) reloadConfig(msg ConfigReloadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		a.status = fmt.Sprintf("config.json not reloaded: %v", msg.Err)
+		return nil
+	}
+	cfg := *msg.Config
+	if err := cfg.Validate(); err != nil {
+		a.status = fmt.Sprintf("config.json not reloaded: %v", err)
+		return nil
+	}
+	if reflect.DeepEqual(cfg, a.cfg) {
+		return nil // saved from the settings screen, or nothing that matters changed
+	}
+	if a.syncScreen.syncing {
+		a.pendingConfig = &cfg
+		a.status = "config.json changed; it applies when the sync finishes."
+		return nil
+	}
+	a.applyReloadedConfig(cfg)
+	return a.refreshScreen()
+}
+
+// applyReloadedConfig switches to a config reloaded from disk and says what
+// changed
+func (a *App) applyReloadedConfig(cfg config.Config) {
+	hrChanged := hrValuesChanged(a.cfg.Athlete, cfg.Athlete)
+	credsChanged := cfg.Strava != a.cfg.Strava
+	a.applyConfig(cfg)
+	if a.screen == ScreenSettings && !a.settings.dirty() {
+		a.settings = NewSettingsModel(cfg, a.saveConfig)
+	}
+
+	a.status = "Reloaded config.json."
+	if hrChanged {
+		a.status += " HR values changed: sync with \"Recompute metrics\" (7) to update past runs."
+	}
+	if credsChanged {
+		a.status += " Strava settings apply after a restart."
+	}
+}
+
+// refreshScreen rebuilds the screen on display so it redraws with the
+// current config. Screens taking input are left alone.
+func (a *App) refreshScreen() tea.Cmd {
+	if a.capturingInput() {
+		return nil
+	}
+	switch a.screen {
+	case ScreenDashboard:
+		return a.dashboard.Init()
+	case ScreenActivities:
+		return a.activities.Init()
+	case ScreenStats:
+		return a.stats.Init()
+	case ScreenActivityDetail:
+		a.activityDetail = NewActivityDetailModel(a.queryService, a.syncService, a.units, a.activityDetail.activityID, a.width, a.height)
+		return a.activityDetail.Init()
+	case ScreenComparisons:
+		a.comparisons = a.newComparisons()
+		return a.comparisons.Init()
+	case ScreenPRs:
+		a.prs = NewPRsModel(a.queryService, a.units, a.width, a.height)
+		return a.prs.Init()
+	case ScreenPredictions:
+		a.predictions = NewPredictionsModel(a.queryService, a.units, a.width, a.height)
+		return a.predictions.Init()
+	case ScreenReview:
+		a.review = NewReviewModel(a.queryService, a.units, a.width, a.height)
+		return a.review.Init()
+	case ScreenCriticalPace:
+		a.criticalPace = NewCriticalPaceModel(a.queryService, a.units, a.width, a.height)
+		return a.criticalPace.Init()
+	case ScreenRaces:
+		a.races = NewRacesModel(a.queryService, a.units, a.width, a.height)
+		return a.races.Init()
+	case ScreenSegments:
+		a.segments = NewSegmentsModel(a.queryService, a.units, a.width, a.height)
+		return a.segments.Init()
+	case ScreenInjuries:
+		a.injuries = NewInjuriesModel(a.queryService, a.units, a.width, a.height)
+		return a.injuries.Init()
+	case ScreenPlan:
+		a.plan = NewPlanModel(a.queryService, a.units, a.width, a.height)
+		return a.plan.Init()
+	case ScreenBenchmarks:
+		a.benchmarks = NewBenchmarksModel(a.queryService, a.units, a.width, a.height)
+		return a.benchmarks.Init()
+	case ScreenTrends:
+		a.trends = NewTrendsModel(a.queryService, a.units, a.width, a.height)
+		return a.trends.Init()
+	case ScreenPaceAtHR:
+		a.paceAtHR = NewPaceAtHRModel(a.queryService, a.units, a.width, a.height)
+		return a.paceAtHR.Init()
+	}
+	return nil
+}
+
 func (a *App) renderHeader() string {
+	if a.readOnly {
+		return headerStyle.Render("Strava Aerobic Fitness Analyzer (read-only)")
+	}
+	if a.syncService == nil {
+		return headerStyle.Render("Strava Aerobic Fitness Analyzer (demo data)")
+	}
 	return headerStyle.Render("Strava Aerobic Fitness Analyzer")
 }
 
@@ -243,6 +675,19 @@ func (a *App) renderNav() string {
 		{"5", "PRs", ScreenPRs},
 		{"6", "Predict", ScreenPredictions},
 		{"7", "Sync", ScreenSync},
+		{"8", "Zones", ScreenDistribution},
+		{"9", "Logs", ScreenLogs},
+		{"A", "API", ScreenRequests},
+		{"0", "Year", ScreenReview},
+		{"p", "Pace", ScreenCriticalPace},
+		{"R", "Races", ScreenRaces},
+		{"F", "Segments", ScreenSegments},
+		{"I", "Injuries", ScreenInjuries},
+		{"P", "Plan", ScreenPlan},
+		{"B", "Bench", ScreenBenchmarks},
+		{"L", "Trends", ScreenTrends},
+		{"H", "HR Pace", ScreenPaceAtHR},
+		{",", "Settings", ScreenSettings},
 		{"?", "Help", ScreenHelp},
 	}
 
@@ -279,3 +724,9 @@ type SyncCompleteMsg struct{}
 type OpenActivityDetailMsg struct {
 	ActivityID int64
 }
+
+// OpenRawDataMsg is sent to page through an activity's stream points
+type OpenRawDataMsg struct {
+	ActivityID int64
+	Name       string
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/store"
+)
+
+func TestApp_ConfigReloaded(t *testing.T) {
+	base := config.DefaultConfig()
+	base.Strava = config.StravaConfig{ClientID: "1", ClientSecret: "x"}
+
+	withHR := base
+	withHR.Athlete.MaxHR = 192
+	inMiles := base
+	inMiles.Display.DistanceUnit, inMiles.Display.PaceUnit = "mi", "min/mi"
+	invalid := base
+	invalid.Display.PaceUnit = "min/furlong"
+
+	tests := []struct {
+		name          string
+		msg           ConfigReloadedMsg
+		syncing       bool
+		wantStatus    string
+		wantMaxHR     float64
+		wantRecompute bool
+		wantMiles     bool
+		wantPending   bool
+	}{
+		{
+			name:          "HR change preselects recompute",
+			msg:           ConfigReloadedMsg{Config: &withHR},
+			wantStatus:    "HR values changed",
+			wantMaxHR:     192,
+			wantRecompute: true,
+		},
+		{
+			name:       "units apply right away",
+			msg:        ConfigReloadedMsg{Config: &inMiles},
+			wantStatus: "Reloaded config.json.",
+			wantMaxHR:  185,
+			wantMiles:  true,
+		},
+		{
+			name:       "unchanged config is ignored",
+			msg:        ConfigReloadedMsg{Config: &base},
+			wantMaxHR:  185,
+			wantStatus: "",
+		},
+		{
+			name:       "invalid config is kept out",
+			msg:        ConfigReloadedMsg{Config: &invalid},
+			wantStatus: "config.json not reloaded: display.pace_unit",
+			wantMaxHR:  185,
+		},
+		{
+			name:       "unreadable config is kept out",
+			msg:        ConfigReloadedMsg{Err: errors.New("parsing config file: unexpected EOF")},
+			wantStatus: "config.json not reloaded: parsing config file",
+			wantMaxHR:  185,
+		},
+		{
+			name:        "change during a sync waits for it",
+			msg:         ConfigReloadedMsg{Config: &withHR},
+			syncing:     true,
+			wantStatus:  "applies when the sync finishes",
+			wantMaxHR:   185,
+			wantPending: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qs := &fakeQueries{athlete: base.Athlete}
+			app := NewApp(nil, nil, nil, qs, base, "")
+			app.screen = ScreenSettings
+			app.syncScreen.syncing = tt.syncing
+
+			app.Update(tt.msg)
+
+			if !strings.Contains(app.status, tt.wantStatus) || (tt.wantStatus == "" && app.status != "") {
+				t.Errorf("status = %q, want %q", app.status, tt.wantStatus)
+			}
+			if qs.athlete.MaxHR != tt.wantMaxHR || app.cfg.Athlete.MaxHR != tt.wantMaxHR {
+				t.Errorf("MaxHR = %v in services, %v in app; want %v", qs.athlete.MaxHR, app.cfg.Athlete.MaxHR, tt.wantMaxHR)
+			}
+			if app.syncScreen.recompute != tt.wantRecompute {
+				t.Errorf("recompute = %v, want %v", app.syncScreen.recompute, tt.wantRecompute)
+			}
+			if got := app.units.DistanceLabel() == "mi"; got != tt.wantMiles {
+				t.Errorf("miles = %v, want %v", got, tt.wantMiles)
+			}
+			if (app.pendingConfig != nil) != tt.wantPending {
+				t.Fatalf("pending = %v, want %v", app.pendingConfig != nil, tt.wantPending)
+			}
+
+			if tt.wantPending {
+				app.syncScreen.syncing = false
+				app.Update(SyncCompleteMsg{})
+				if app.pendingConfig != nil || qs.athlete.MaxHR != withHR.Athlete.MaxHR {
+					t.Errorf("after the sync MaxHR = %v, pending = %v; want the reloaded config applied", qs.athlete.MaxHR, app.pendingConfig != nil)
+				}
+			}
+		})
+	}
+}
+
+func TestApp_ReadOnly(t *testing.T) {
+	app := NewApp(nil, nil, &fakeSync{}, &fakeQueries{}, config.DefaultConfig(), "")
+	app.SetReadOnly()
+
+	if app.syncService != nil {
+		t.Error("sync service kept in read-only mode")
+	}
+	if err := app.queryService.SetActivityNote(1, "note"); !errors.Is(err, errReadOnly) {
+		t.Errorf("SetActivityNote() error = %v, want errReadOnly", err)
+	}
+	if _, err := app.queryService.AddInjury(store.Injury{}); !errors.Is(err, errReadOnly) {
+		t.Errorf("AddInjury() error = %v, want errReadOnly", err)
+	}
+	if _, err := app.queryService.CreateTrainingPlan(time.Now(), 50000, 3, 4); !errors.Is(err, errReadOnly) {
+		t.Errorf("CreateTrainingPlan() error = %v, want errReadOnly", err)
+	}
+	if header := app.renderHeader(); !strings.Contains(header, "(read-only)") {
+		t.Errorf("header = %q, want it to say read-only", header)
+	}
+	if view := app.syncScreen.View(); !strings.Contains(view, "Sync is off in demo and read-only mode.") {
+		t.Errorf("sync screen = %q, want sync off", view)
+	}
+}
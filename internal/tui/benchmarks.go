@@ -0,0 +1,363 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"runner/internal/service"
+	"runner/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// benchmarkChartHeight is the height of the attempt trend charts
+const benchmarkChartHeight = 6
+
+// BenchmarksModel is the benchmarks screen model
+type BenchmarksModel struct {
+	queryService QueryProvider
+	units        Units
+	benchmarks   []service.BenchmarkDisplay
+	selected     int // benchmark shown
+	cursor       int // attempt row
+	top          int // first visible row
+	loading      bool
+	err          error
+	saveErr      error
+	width        int
+	height       int
+}
+
+// NewBenchmarksModel creates a new benchmarks model
+func NewBenchmarksModel(qs QueryProvider, units Units, width, height int) BenchmarksModel {
+	return BenchmarksModel{
+		queryService: qs,
+		units:        units,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init initializes the benchmarks screen
+func (m BenchmarksModel) Init() tea.Cmd {
+	return m.loadBenchmarks
+}
+
+type benchmarksLoadedMsg struct {
+	benchmarks []service.BenchmarkDisplay
+	err        error
+}
+
+type benchmarkSavedMsg struct {
+	err error
+}
+
+func (m BenchmarksModel) loadBenchmarks() tea.Msg {
+	benchmarks, err := m.queryService.GetBenchmarks()
+	return benchmarksLoadedMsg{benchmarks: benchmarks, err: err}
+}
+
+// visibleRows is how many attempts fit on screen below the charts
+func (m BenchmarksModel) visibleRows() int {
+	if rows := m.height - 30; rows > 5 {
+		return rows
+	}
+	return 5
+}
+
+// Update handles messages
+func (m BenchmarksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case benchmarksLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.benchmarks = msg.benchmarks
+		if m.selected >= len(m.benchmarks) {
+			m.selected = max(len(m.benchmarks)-1, 0)
+		}
+		if m.cursor >= len(m.attempts()) {
+			m.cursor = max(len(m.attempts())-1, 0)
+		}
+		m.scrollToCursor()
+
+	case benchmarkSavedMsg:
+		m.saveErr = msg.err
+		return m, m.loadBenchmarks
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "left", "h", "[":
+			if m.selected > 0 {
+				m.selected--
+				m.cursor, m.top = 0, 0
+			}
+		case "right", "l", "]":
+			if m.selected < len(m.benchmarks)-1 {
+				m.selected++
+				m.cursor, m.top = 0, 0
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.scrollToCursor()
+			}
+		case "down", "j":
+			if m.cursor < len(m.attempts())-1 {
+				m.cursor++
+				m.scrollToCursor()
+			}
+		case "enter":
+			if attempt, ok := m.selectedAttempt(); ok {
+				return m, func() tea.Msg {
+					return OpenActivityDetailMsg{ActivityID: attempt.Activity.ID}
+				}
+			}
+		case "x":
+			attempt, ok := m.selectedAttempt()
+			if !ok {
+				return m, nil
+			}
+			if attempt.Reference {
+				m.saveErr = errors.New("the reference run can't be removed, delete the benchmark instead")
+				return m, nil
+			}
+			qs, id := m.queryService, m.benchmarks[m.selected].ID
+			return m, func() tea.Msg {
+				return benchmarkSavedMsg{err: qs.SetBenchmarkAttempt(id, attempt.Activity.ID, false)}
+			}
+		case "D":
+			if m.selected < len(m.benchmarks) {
+				qs, id := m.queryService, m.benchmarks[m.selected].ID
+				m.cursor, m.top = 0, 0
+				return m, func() tea.Msg {
+					return benchmarkSavedMsg{err: qs.DeleteBenchmark(id)}
+				}
+			}
+		case "r":
+			m.loading = true
+			return m, m.loadBenchmarks
+		}
+	}
+	return m, nil
+}
+
+// attempts returns the attempts of the benchmark shown
+func (m BenchmarksModel) attempts() []service.BenchmarkAttempt {
+	if m.selected >= len(m.benchmarks) {
+		return nil
+	}
+	return m.benchmarks[m.selected].Attempts
+}
+
+func (m BenchmarksModel) selectedAttempt() (service.BenchmarkAttempt, bool) {
+	attempts := m.attempts()
+	if m.cursor < 0 || m.cursor >= len(attempts) {
+		return service.BenchmarkAttempt{}, false
+	}
+	return attempts[m.cursor], true
+}
+
+// scrollToCursor keeps the cursor row on screen
+func (m *BenchmarksModel) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+}
+
+func (m BenchmarksModel) layout() gridLayout {
+	return newGridLayout(m.width)
+}
+
+// View renders the benchmarks screen
+func (m BenchmarksModel) View() string {
+	if m.loading {
+		return "\n  Loading benchmarks..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var sections []string
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("Benchmarks (%d)", len(m.benchmarks))))
+
+	if len(m.benchmarks) == 0 {
+		sections = append(sections, muted.Render(
+			"  No benchmarks yet. Open a run and press b to make it one; later runs that repeat it are matched automatically."))
+		if m.saveErr != nil {
+			sections = append(sections, "", errorStyle.Render(fmt.Sprintf("  Error saving: %v", m.saveErr)))
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	b := m.benchmarks[m.selected]
+	sections = append(sections, m.renderTabs(), m.renderSummary(b))
+	sections = append(sections, m.layout().rows([]string{m.renderEFChart(b), m.renderPaceChart(b)})...)
+
+	header := tableHeaderStyle.Render(fmt.Sprintf("   %-12s  %-24s  %8s  %8s  %9s  %6s  %5s  %11s  %s",
+		"Date", "Name", "Dist", "Time", "Pace", "Avg HR", "EF", "Pace at HR", ""))
+	sections = append(sections, header)
+
+	end := min(m.top+m.visibleRows(), len(b.Attempts))
+	for i := m.top; i < end; i++ {
+		sections = append(sections, m.renderRow(b, i))
+	}
+
+	footer := statusStyle.Render("  h/l: benchmark  j/k: navigate  enter: view details  x: remove run  D: delete benchmark  r: refresh")
+	if m.saveErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error saving: %v", m.saveErr)), footer)
+	}
+	sections = append(sections, "", footer)
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderTabs lists the benchmarks with the one shown highlighted
+func (m BenchmarksModel) renderTabs() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	tabs := " "
+	for i, b := range m.benchmarks {
+		if i == m.selected {
+			tabs += " " + tableSelectedStyle.Render(" "+b.Name+" ")
+		} else {
+			tabs += " " + muted.Render(" "+b.Name+" ")
+		}
+	}
+	return tabs
+}
+
+func (m BenchmarksModel) renderSummary(b service.BenchmarkDisplay) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	match := "same route and distance"
+	if b.Kind == store.BenchmarkDuration {
+		match = "same moving time"
+	}
+	line := fmt.Sprintf("  %d attempts, matched by %s, from %q", len(b.Attempts), match, b.ReferenceName)
+	if b.ReferenceHR > 0 {
+		line += fmt.Sprintf("  ·  pace at %.0f bpm", b.ReferenceHR)
+	}
+	summary := muted.Render(line)
+	if b.EFChange != nil {
+		style := successStyle
+		if *b.EFChange < 0 {
+			style = warningStyle
+		}
+		summary += muted.Render("  ·  EF ") + style.Render(fmt.Sprintf("%+.1f%%", *b.EFChange)) + muted.Render(" since the first")
+	}
+	return summary
+}
+
+// attemptLabels returns the date of each attempt for the chart x axis
+func attemptLabels(b service.BenchmarkDisplay) []string {
+	labels := make([]string, len(b.Attempts))
+	for i, a := range b.Attempts {
+		labels[i] = a.Activity.StartDateLocal.Format("Jan 02")
+	}
+	return labels
+}
+
+func (m BenchmarksModel) renderEFChart(b service.BenchmarkDisplay) string {
+	data := make([]float64, len(b.Attempts))
+	for i, a := range b.Attempts {
+		data[i] = math.NaN()
+		if a.EF != nil {
+			data[i] = *a.EF
+		}
+	}
+	return m.renderChart("Efficiency Factor", data, lineChart{
+		Precision: 2,
+		XLabels:   attemptLabels(b),
+		Caption:   "higher = fitter",
+	})
+}
+
+func (m BenchmarksModel) renderPaceChart(b service.BenchmarkDisplay) string {
+	title := "Pace at HR"
+	if b.ReferenceHR > 0 {
+		title = fmt.Sprintf("Pace at %.0f bpm", b.ReferenceHR)
+	}
+
+	// PaceAtHR is seconds per mile; attempts without EF are gaps
+	paces := make([]float64, len(b.Attempts))
+	for i, a := range b.Attempts {
+		paces[i] = a.PaceAtHR / 60
+	}
+	paces = m.units.ConvertPaceData(paces)
+	for i, p := range paces {
+		if p <= 0 {
+			paces[i] = math.NaN()
+		}
+	}
+	return m.renderChart(title, paces, lineChart{
+		Precision: 2,
+		XLabels:   attemptLabels(b),
+		Caption:   m.units.PaceLabel() + " (lower = fitter)",
+	})
+}
+
+func (m BenchmarksModel) renderChart(title string, data []float64, chart lineChart) string {
+	g := m.layout()
+	chart.Width = g.chartWidth()
+	chart.Height = benchmarkChartHeight
+	return cardStyle.Width(g.cardWidth(0)).Render(lipgloss.JoinVertical(lipgloss.Left,
+		cardTitleStyle.Render(title), chart.render(data)))
+}
+
+func (m BenchmarksModel) renderRow(b service.BenchmarkDisplay, i int) string {
+	attempt := b.Attempts[i]
+	a := attempt.Activity
+
+	cursor := "  "
+	if i == m.cursor {
+		cursor = "> "
+	}
+
+	hr := "-"
+	if a.AverageHeartrate != nil {
+		hr = formatHR(*a.AverageHeartrate)
+	}
+	ef := "-"
+	if attempt.EF != nil {
+		ef = formatEF(*attempt.EF)
+	}
+	source := ""
+	switch {
+	case attempt.Reference:
+		source = "reference"
+	case attempt.Added:
+		source = "added"
+	}
+
+	row := fmt.Sprintf("%s%-12s  %-24s  %8s  %8s  %9s  %6s  %5s  %11s  %s",
+		cursor,
+		a.StartDateLocal.Format("Jan 02, 2006"),
+		truncateName(a.Name, 24),
+		m.units.FormatDistance(a.Distance),
+		formatDuration(a.MovingTime),
+		m.units.FormatPaceWithUnit(a.MovingTime, a.Distance),
+		hr,
+		ef,
+		m.units.FormatPacePerMile(attempt.PaceAtHR),
+		source,
+	)
+
+	if i == m.cursor {
+		return tableSelectedStyle.Render(row)
+	}
+	return tableRowStyle.Render(row)
+}
@@ -0,0 +1,378 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Braille cells hold a 2x4 grid of dots, so a braille chart has twice the
+// horizontal and four times the vertical resolution of one drawn with
+// box-drawing characters
+const (
+	brailleBase    = 0x2800
+	brailleCellW   = 2
+	brailleCellH   = 4
+	xLabelMinGap   = 2 // spaces between x-axis labels
+	chartYLabelPad = 1
+)
+
+// brailleDots maps a dot's column and row within a cell to its bit
+var brailleDots = [brailleCellW][brailleCellH]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// chartTarget is a horizontal reference line such as a weekly goal
+type chartTarget struct {
+	Value float64
+	Label string
+}
+
+// lineChart draws one series on a braille canvas with a labeled y axis,
+// optional x-axis labels, target lines, and a min/max/now summary
+type lineChart struct {
+	Width     int // plot area in cells, not counting the y axis
+	Height    int
+	Precision int
+	XLabels   []string // one per point; as many as fit are shown
+	Targets   []chartTarget
+	Caption   string
+
+	// Cursor is the point marked with a vertical line when ShowCursor is set
+	Cursor     int
+	ShowCursor bool
+}
+
+// brailleCanvas is a grid of dots addressed from the top left
+type brailleCanvas struct {
+	w, h  int // in cells
+	cells [][]rune
+}
+
+func newBrailleCanvas(w, h int) *brailleCanvas {
+	cells := make([][]rune, h)
+	for i := range cells {
+		cells[i] = make([]rune, w)
+	}
+	return &brailleCanvas{w: w, h: h, cells: cells}
+}
+
+func (c *brailleCanvas) set(x, y int) {
+	if x < 0 || y < 0 || x >= c.w*brailleCellW || y >= c.h*brailleCellH {
+		return
+	}
+	c.cells[y/brailleCellH][x/brailleCellW] |= brailleDots[x%brailleCellW][y%brailleCellH]
+}
+
+// line sets the dots between two points (Bresenham)
+func (c *brailleCanvas) line(x0, y0, x1, y1 int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		c.set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// render plots data, skipping NaN points as gaps
+func (c lineChart) render(data []float64) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	lo, hi, last, ok := chartRange(data)
+	if !ok {
+		return muted.Render("No data")
+	}
+	summary := muted.Render(fmt.Sprintf("min %.*f  max %.*f  now %.*f", c.Precision, lo, c.Precision, hi, c.Precision, last))
+	targetStyle := lipgloss.NewStyle().Foreground(warningColor)
+	for _, t := range c.Targets {
+		lo, hi = math.Min(lo, t.Value), math.Max(hi, t.Value)
+		summary += muted.Render("  ") + targetStyle.Render(fmt.Sprintf("%s %.*f", t.Label, c.Precision, t.Value))
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+
+	w, h := max(c.Width, 2), max(c.Height, 2)
+	dotsW, dotsH := w*brailleCellW, h*brailleCellH
+	toX := func(i int) int {
+		if len(data) == 1 {
+			return 0
+		}
+		return int(math.Round(float64(i) * float64(dotsW-1) / float64(len(data)-1)))
+	}
+	toY := func(v float64) int {
+		return dotsH - 1 - int(math.Round((v-lo)/(hi-lo)*float64(dotsH-1)))
+	}
+
+	series := newBrailleCanvas(w, h)
+	prev := -1
+	for i, v := range data {
+		if math.IsNaN(v) {
+			prev = -1
+			continue
+		}
+		if prev < 0 {
+			series.set(toX(i), toY(v))
+		} else {
+			series.line(toX(prev), toY(data[prev]), toX(i), toY(v))
+		}
+		prev = i
+	}
+
+	// Targets are dotted lines in the warning color, named in the summary
+	targets := newBrailleCanvas(w, h)
+	for _, t := range c.Targets {
+		y := toY(t.Value)
+		for x := 0; x < dotsW; x += 2 {
+			targets.set(x, y)
+		}
+	}
+
+	cursor := newBrailleCanvas(w, h)
+	if c.ShowCursor && c.Cursor >= 0 && c.Cursor < len(data) {
+		x := toX(c.Cursor)
+		for y := 0; y < dotsH; y++ {
+			cursor.set(x, y)
+		}
+	}
+
+	yLabels, labelW := yAxisLabels(lo, hi, h, c.Precision)
+
+	lineStyle := lipgloss.NewStyle().Foreground(primaryColor)
+	cursorStyle := lipgloss.NewStyle().Foreground(accentColor)
+
+	var lines []string
+	for row := 0; row < h; row++ {
+		var b strings.Builder
+		b.WriteString(muted.Render(fmt.Sprintf("%*s ┤", labelW+chartYLabelPad, yLabels[row])))
+		for col := 0; col < w; col++ {
+			s, t, cur := series.cells[row][col], targets.cells[row][col], cursor.cells[row][col]
+			switch {
+			case cur != 0:
+				b.WriteString(cursorStyle.Render(string(brailleBase + (s | t | cur))))
+			case s != 0:
+				b.WriteString(lineStyle.Render(string(brailleBase + (s | t))))
+			case t != 0:
+				b.WriteString(targetStyle.Render(string(brailleBase + t)))
+			default:
+				b.WriteRune(brailleBase)
+			}
+		}
+		lines = append(lines, b.String())
+	}
+
+	indent := strings.Repeat(" ", labelW+chartYLabelPad+1)
+	lines = append(lines, muted.Render(indent+"└"+strings.Repeat("─", w)))
+	if axis := c.xAxis(toX, w); axis != "" {
+		lines = append(lines, muted.Render(indent+" "+axis))
+	}
+	lines = append(lines, muted.Render(indent+" ")+summary)
+	if c.Caption != "" {
+		lines = append(lines, muted.Render(indent+" "+c.Caption))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// xAxis places as many labels under their points as fit without
+// overlapping, always starting with the first
+func (c lineChart) xAxis(toX func(int) int, w int) string {
+	if len(c.XLabels) == 0 {
+		return ""
+	}
+	axis := []rune(strings.Repeat(" ", w))
+	next := 0
+	for i, label := range c.XLabels {
+		col := toX(i) / brailleCellW
+		// Center the label on its point, but keep it inside the axis
+		start := max(col-len(label)/2, 0)
+		if start < next || start+len(label) > w {
+			continue
+		}
+		copy(axis[start:], []rune(label))
+		next = start + len(label) + xLabelMinGap
+	}
+	return strings.TrimRight(string(axis), " ")
+}
+
+// chartRange returns the smallest, largest and last values that aren't NaN
+func chartRange(data []float64) (lo, hi, last float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range data {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo, hi, last, ok = math.Min(lo, v), math.Max(hi, v), v, true
+	}
+	return lo, hi, last, ok
+}
+
+// yAxisLabels labels the top, middle and bottom of h rows spanning lo to
+// hi, and returns the widest label's width
+func yAxisLabels(lo, hi float64, h, precision int) ([]string, int) {
+	labels := make([]string, h)
+	for _, row := range []int{0, h / 2, h - 1} {
+		v := hi - (hi-lo)*float64(row)/float64(h-1)
+		labels[row] = fmt.Sprintf("%.*f", precision, v)
+	}
+	width := 0
+	for _, l := range labels {
+		width = max(width, len(l))
+	}
+	return labels, width
+}
+
+// scatterChart draws one dot per point on a braille canvas, with an
+// optional fitted curve and target lines. The x axis is labeled at its ends
+// and middle.
+type scatterChart struct {
+	Width     int // plot area in cells, not counting the y axis
+	Height    int
+	Precision int
+	XFormat   func(x float64) string
+	Curve     func(x float64) float64 // nil for no curve
+	Targets   []chartTarget
+	Caption   string
+}
+
+// render plots ys against xs. The y range covers the points and targets;
+// the curve is clipped to it.
+func (c scatterChart) render(xs, ys []float64) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	if len(xs) == 0 || len(xs) != len(ys) {
+		return muted.Render("No data")
+	}
+
+	xLo, xHi, _, _ := chartRange(xs)
+	lo, hi, _, _ := chartRange(ys)
+	summary := muted.Render(fmt.Sprintf("%d runs", len(xs)))
+	targetStyle := lipgloss.NewStyle().Foreground(warningColor)
+	for _, t := range c.Targets {
+		lo, hi = math.Min(lo, t.Value), math.Max(hi, t.Value)
+		summary += muted.Render("  ") + targetStyle.Render(fmt.Sprintf("%s %.*f", t.Label, c.Precision, t.Value))
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	if xHi == xLo {
+		xLo, xHi = xLo-1, xHi+1
+	}
+
+	w, h := max(c.Width, 2), max(c.Height, 2)
+	dotsW, dotsH := w*brailleCellW, h*brailleCellH
+	toX := func(x float64) int {
+		return int(math.Round((x - xLo) / (xHi - xLo) * float64(dotsW-1)))
+	}
+	toY := func(v float64) int {
+		return dotsH - 1 - int(math.Round((v-lo)/(hi-lo)*float64(dotsH-1)))
+	}
+
+	points := newBrailleCanvas(w, h)
+	for i := range xs {
+		points.set(toX(xs[i]), toY(ys[i]))
+	}
+
+	// The curve is sampled at every dot column; points off the chart break it
+	curve := newBrailleCanvas(w, h)
+	if c.Curve != nil {
+		prevX, prevY := -1, 0
+		for x := 0; x < dotsW; x++ {
+			v := c.Curve(xLo + (xHi-xLo)*float64(x)/float64(dotsW-1))
+			if math.IsNaN(v) || v < lo || v > hi {
+				prevX = -1
+				continue
+			}
+			y := toY(v)
+			if prevX < 0 {
+				curve.set(x, y)
+			} else {
+				curve.line(prevX, prevY, x, y)
+			}
+			prevX, prevY = x, y
+		}
+	}
+
+	targets := newBrailleCanvas(w, h)
+	for _, t := range c.Targets {
+		y := toY(t.Value)
+		for x := 0; x < dotsW; x += 2 {
+			targets.set(x, y)
+		}
+	}
+
+	yLabels, labelW := yAxisLabels(lo, hi, h, c.Precision)
+	pointStyle := lipgloss.NewStyle().Foreground(primaryColor)
+	curveStyle := lipgloss.NewStyle().Foreground(accentColor)
+
+	var lines []string
+	for row := 0; row < h; row++ {
+		var b strings.Builder
+		b.WriteString(muted.Render(fmt.Sprintf("%*s ┤", labelW+chartYLabelPad, yLabels[row])))
+		for col := 0; col < w; col++ {
+			p, cv, t := points.cells[row][col], curve.cells[row][col], targets.cells[row][col]
+			switch {
+			case p != 0:
+				b.WriteString(pointStyle.Render(string(brailleBase + (p | cv | t))))
+			case cv != 0:
+				b.WriteString(curveStyle.Render(string(brailleBase + (cv | t))))
+			case t != 0:
+				b.WriteString(targetStyle.Render(string(brailleBase + t)))
+			default:
+				b.WriteRune(brailleBase)
+			}
+		}
+		lines = append(lines, b.String())
+	}
+
+	indent := strings.Repeat(" ", labelW+chartYLabelPad+1)
+	lines = append(lines, muted.Render(indent+"└"+strings.Repeat("─", w)))
+	if c.XFormat != nil {
+		lines = append(lines, muted.Render(indent+" "+scatterXAxis(c.XFormat(xLo), c.XFormat((xLo+xHi)/2), c.XFormat(xHi), w)))
+	}
+	lines = append(lines, muted.Render(indent+" ")+summary)
+	if c.Caption != "" {
+		lines = append(lines, muted.Render(indent+" "+c.Caption))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scatterXAxis places labels at the left, middle and right of a w-wide
+// axis, dropping the middle one when it doesn't fit
+func scatterXAxis(left, mid, right string, w int) string {
+	axis := []rune(strings.Repeat(" ", w))
+	copy(axis, []rune(left))
+	if start := w - len(right); start > len(left)+xLabelMinGap {
+		copy(axis[start:], []rune(right))
+		if ms := w/2 - len(mid)/2; ms > len(left)+xLabelMinGap && ms+len(mid)+xLabelMinGap < start {
+			copy(axis[ms:], []rune(mid))
+		}
+	}
+	return strings.TrimRight(string(axis), " ")
+}
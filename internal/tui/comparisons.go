@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"runner/internal/service"
 
@@ -12,7 +14,7 @@ import (
 
 // ComparisonsModel is the trend comparisons screen model
 type ComparisonsModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
 	units        Units
 	comparisons  []service.ComparisonStats
 	periodType   string // "weekly" or "monthly"
@@ -22,10 +24,31 @@ type ComparisonsModel struct {
 	ready        bool
 	width        int
 	height       int
+
+	// Custom comparisons built on this screen, recomputed on each load
+	custom []customComparison
+
+	// Comparison builder, asking for one range at a time
+	building bool
+	step     int
+	input    textInput
+	pending  service.ComparisonRange
+	buildErr error
+}
+
+// customComparison is a pair of ranges the user chose to compare
+type customComparison struct {
+	current, previous service.ComparisonRange
+}
+
+// comparisonBuilderPrompts are the steps of the comparison builder, in order
+var comparisonBuilderPrompts = []string{
+	"Compare (" + service.ComparisonRangeHelp + ")",
+	"Against (blank for the period before)",
 }
 
 // NewComparisonsModel creates a new comparisons model
-func NewComparisonsModel(qs *service.QueryService, units Units, width, height int) ComparisonsModel {
+func NewComparisonsModel(qs QueryProvider, units Units, width, height int) ComparisonsModel {
 	m := ComparisonsModel{
 		queryService: qs,
 		units:        units,
@@ -66,8 +89,19 @@ func (m ComparisonsModel) loadComparisons() tea.Msg {
 	} else {
 		comparisons, err = m.queryService.GetMonthlyComparisons()
 	}
+	if err != nil {
+		return comparisonsLoadedMsg{err: err}
+	}
+
+	for _, c := range m.custom {
+		comp, err := m.queryService.GetCustomComparison(c.current, c.previous)
+		if err != nil {
+			return comparisonsLoadedMsg{err: err}
+		}
+		comparisons = append(comparisons, comp)
+	}
 
-	return comparisonsLoadedMsg{comparisons: comparisons, err: err}
+	return comparisonsLoadedMsg{comparisons: comparisons}
 }
 
 // Update handles messages
@@ -103,7 +137,22 @@ func (m ComparisonsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if m.building {
+			return m.updateBuilder(msg)
+		}
 		switch msg.String() {
+		case "n":
+			m.building = true
+			m.step = 0
+			m.input = textInput{}
+			m.buildErr = nil
+			return m, nil
+		case "x":
+			if len(m.custom) > 0 {
+				m.custom = nil
+				m.loading = true
+				return m, m.loadComparisons
+			}
 		case "w":
 			if m.periodType != "weekly" {
 				m.periodType = "weekly"
@@ -130,6 +179,59 @@ func (m ComparisonsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateBuilder handles key presses while the comparison builder is open.
+// Each range is checked as it's entered so mistakes can be fixed in place.
+func (m ComparisonsModel) updateBuilder(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case cancelled:
+		m.building = false
+		m.buildErr = nil
+		return m, nil
+	case !submitted:
+		return m, nil
+	}
+
+	value := m.input.value
+	var r service.ComparisonRange
+	var err error
+	if m.step == 1 && strings.TrimSpace(value) == "" {
+		r = service.PreviousRange(m.pending)
+	} else {
+		r, err = service.ParseComparisonRange(value, time.Now())
+	}
+	if err != nil {
+		m.buildErr = err
+		return m, nil
+	}
+
+	m.buildErr = nil
+	m.input = textInput{}
+	if m.step == 0 {
+		m.pending = r
+		m.step++
+		return m, nil
+	}
+
+	m.building = false
+	m.custom = append(m.custom, customComparison{current: m.pending, previous: r})
+	m.loading = true
+	return m, m.loadComparisons
+}
+
+// footer shows the builder prompt while it's open, or else the key help
+func (m ComparisonsModel) footer(help string) string {
+	if !m.building {
+		return statusStyle.Render(help)
+	}
+	footer := fmt.Sprintf("  %s: %s", comparisonBuilderPrompts[m.step], m.input.view()) +
+		statusStyle.Render(fmt.Sprintf("  (%d/%d)  enter: next  esc: cancel", m.step+1, len(comparisonBuilderPrompts)))
+	if m.buildErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error: %v", m.buildErr)), footer)
+	}
+	return footer
+}
+
 // View renders the comparisons screen
 func (m ComparisonsModel) View() string {
 	if m.loading {
@@ -141,11 +243,11 @@ func (m ComparisonsModel) View() string {
 	}
 
 	if !m.ready {
-		return m.renderContent() + "\n" + statusStyle.Render("  w/m: weekly/monthly  r: refresh")
+		return m.renderContent() + "\n" + m.footer("  w/m: weekly/monthly  n: new comparison  x: clear custom  r: refresh")
 	}
 
 	scrollPct := m.viewport.ScrollPercent() * 100
-	scrollInfo := statusStyle.Render(fmt.Sprintf("  scroll: %.0f%% (j/k to scroll, w/m: weekly/monthly, r: refresh)", scrollPct))
+	scrollInfo := m.footer(fmt.Sprintf("  scroll: %.0f%% (j/k to scroll, w/m: weekly/monthly, n: new comparison, x: clear custom, r: refresh)", scrollPct))
 
 	return m.viewport.View() + "\n" + scrollInfo
 }
@@ -190,10 +292,7 @@ func (m ComparisonsModel) renderComparison(comp service.ComparisonStats) string
 	distLabel := "Distance"
 	currentDist := m.formatDistance(comp.Current.TotalMiles)
 	prevDist := m.formatDistance(comp.Previous.TotalMiles)
-	deltaDist := comp.DeltaMiles
-	if !m.units.IsMiles() {
-		deltaDist = comp.DeltaMiles * 1.60934
-	}
+	deltaDist := m.units.FromMiles(comp.DeltaMiles)
 
 	rows := []string{
 		m.renderRow("Runs", fmt.Sprintf("%d", comp.Current.RunCount), fmt.Sprintf("%d", comp.Previous.RunCount), comp.DeltaRuns, false),
@@ -269,10 +368,7 @@ func (m ComparisonsModel) formatDistance(miles float64) string {
 	if miles == 0 {
 		return "-"
 	}
-	if m.units.IsMiles() {
-		return fmt.Sprintf("%.1f", miles)
-	}
-	return fmt.Sprintf("%.1f", miles*1.60934)
+	return fmt.Sprintf("%.1f", m.units.FromMiles(miles))
 }
 
 func formatHR(hr float64) string {
@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"runner/internal/service"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// CriticalPaceModel is the pace-duration curve screen model
+type CriticalPaceModel struct {
+	queryService QueryProvider
+	units        Units
+	data         *service.CriticalPaceData
+	viewport     viewport.Model
+	loading      bool
+	err          error
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewCriticalPaceModel creates a new critical pace model
+func NewCriticalPaceModel(qs QueryProvider, units Units, width, height int) CriticalPaceModel {
+	m := CriticalPaceModel{
+		queryService: qs,
+		units:        units,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the critical pace screen
+func (m CriticalPaceModel) Init() tea.Cmd {
+	return m.loadCriticalPace
+}
+
+type criticalPaceLoadedMsg struct {
+	data *service.CriticalPaceData
+	err  error
+}
+
+func (m CriticalPaceModel) loadCriticalPace() tea.Msg {
+	data, err := m.queryService.GetCriticalPace()
+	return criticalPaceLoadedMsg{data: data, err: err}
+}
+
+// Update handles messages
+func (m CriticalPaceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case criticalPaceLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.data = msg.data
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			return m, m.loadCriticalPace
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the critical pace screen
+func (m CriticalPaceModel) View() string {
+	if m.loading {
+		return "\n  Loading pace curve..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	footer := statusStyle.Render("  j/k or arrows: scroll  r: refresh")
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+func (m CriticalPaceModel) renderContent() string {
+	var sections []string
+
+	sections = append(sections, "")
+	sections = append(sections, cardTitleStyle.Render("Critical Pace"))
+	sections = append(sections, "")
+
+	if m.data == nil || len(m.data.AllTime.Points) == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render("  No efforts yet. Sync activities with streams to build your pace curve."))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	sections = append(sections, m.renderChart())
+	sections = append(sections, m.renderFit())
+	sections = append(sections, m.renderCurve("All-Time Bests", m.data.AllTime))
+	sections = append(sections, m.renderCurve(fmt.Sprintf("Last %d Days", service.CriticalPaceRecentDays), m.data.Recent))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderChart plots pace against duration, all-time and recent. Both curves
+// cover the durations from the shortest up, so they line up point for point.
+func (m CriticalPaceModel) renderChart() string {
+	allTime := m.chartPaces(m.data.AllTime)
+	series := [][]float64{allTime}
+	legends := []string{"all-time"}
+	colors := []asciigraph.AnsiColor{asciigraph.Default}
+	if recent := m.chartPaces(m.data.Recent); len(recent) > 0 {
+		series = append(series, recent)
+		legends = append(legends, fmt.Sprintf("last %d days", service.CriticalPaceRecentDays))
+		colors = append(colors, activeTheme.ChartHighlight)
+	}
+
+	var durations []string
+	for _, pt := range m.data.AllTime.Points {
+		durations = append(durations, formatCurveDuration(pt.DurationSeconds))
+	}
+
+	graph := asciigraph.PlotMany(series,
+		asciigraph.Height(10),
+		asciigraph.Width(50),
+		asciigraph.Precision(2),
+		asciigraph.SeriesColors(colors...),
+		asciigraph.SeriesLegends(legends...),
+		asciigraph.Caption(fmt.Sprintf("pace (%s) at %s", m.units.PaceLabel(), strings.Join(durations, ", "))),
+	)
+	return cardStyle.Render(graph)
+}
+
+func (m CriticalPaceModel) chartPaces(curve service.CriticalPaceCurve) []float64 {
+	paces := make([]float64, len(curve.Points))
+	for i, pt := range curve.Points {
+		paces[i] = pt.PacePerMile / 60
+	}
+	return m.units.ConvertPaceData(paces)
+}
+
+func (m CriticalPaceModel) renderFit() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Critical Speed"))
+	for _, c := range []struct {
+		label string
+		curve service.CriticalPaceCurve
+	}{
+		{"All-time", m.data.AllTime},
+		{fmt.Sprintf("Last %d days", service.CriticalPaceRecentDays), m.data.Recent},
+	} {
+		if !c.curve.HasFit {
+			lines = append(lines, fmt.Sprintf("  %-13s %s", c.label+":", muted.Render("not enough 2-20 minute efforts")))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %-13s CS %s  D' %.0f m",
+			c.label+":", m.units.FormatPacePerMile(c.curve.CriticalPace), c.curve.DPrime))
+	}
+	lines = append(lines, muted.Render("  CS is roughly the pace you can hold for 30-60 minutes; D' is the"))
+	lines = append(lines, muted.Render("  distance you can run above it before tiring."))
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+func (m CriticalPaceModel) renderCurve(title string, curve service.CriticalPaceCurve) string {
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(title))
+	if len(curve.Points) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(mutedColor).Render("  No efforts in this period"))
+		lines = append(lines, "")
+		return strings.Join(lines, "\n")
+	}
+
+	header := fmt.Sprintf("  %-8s  %-10s  %-10s  %-12s  %s", "Duration", "Pace", "Distance", "Date", "Activity")
+	lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(header))
+	for _, pt := range curve.Points {
+		name := pt.ActivityName
+		if len(name) > 30 {
+			name = name[:27] + "..."
+		}
+		lines = append(lines, fmt.Sprintf("  %-8s  %-10s  %-10s  %-12s  %s",
+			formatCurveDuration(pt.DurationSeconds),
+			m.units.FormatPacePerMile(pt.PacePerMile),
+			m.units.FormatDistance(pt.DistanceMeters),
+			pt.Date,
+			name))
+	}
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+// formatCurveDuration labels a curve duration as "5m" or "1h30m"
+func formatCurveDuration(seconds int) string {
+	h := seconds / 3600
+	min := (seconds % 3600) / 60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", min)
+	case min == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, min)
+	}
+}
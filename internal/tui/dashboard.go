@@ -2,8 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"time"
 
+	"runner/internal/analysis"
 	"runner/internal/service"
+	"runner/internal/store"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,7 +18,7 @@ import (
 
 // DashboardModel is the dashboard screen model
 type DashboardModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
 	units        Units
 	data         *service.DashboardData
 	loading      bool
@@ -22,10 +27,25 @@ type DashboardModel struct {
 	ready        bool
 	width        int
 	height       int
+
+	// Wellness form for today, filled in one field at a time
+	editing bool
+	step    int
+	input   textInput
+	pending store.Wellness
+	editErr error
+}
+
+// wellnessFormFields are the steps of the wellness form, in order
+var wellnessFormFields = []service.WellnessField{
+	service.WellnessRestingHR,
+	service.WellnessHRV,
+	service.WellnessSleep,
+	service.WellnessSoreness,
 }
 
 // NewDashboardModel creates a new dashboard model
-func NewDashboardModel(qs *service.QueryService, units Units, width, height int) DashboardModel {
+func NewDashboardModel(qs QueryProvider, units Units, width, height int) DashboardModel {
 	m := DashboardModel{
 		queryService: qs,
 		units:        units,
@@ -65,6 +85,10 @@ type dashboardDataMsg struct {
 	err  error
 }
 
+type wellnessSavedMsg struct {
+	err error
+}
+
 // Update handles messages
 func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -98,11 +122,24 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.SetContent(m.renderContent())
 		}
 
+	case wellnessSavedMsg:
+		m.editErr = msg.err
+		return m, m.loadData
+
 	case tea.KeyMsg:
+		if m.editing {
+			return m.updateForm(msg)
+		}
+
 		switch msg.String() {
 		case "r":
 			m.loading = true
 			return m, m.loadData
+		case "w":
+			if m.data != nil {
+				m.startWellnessForm()
+				return m, nil
+			}
 		}
 	}
 
@@ -114,6 +151,89 @@ func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// startWellnessForm opens the wellness form for today, starting from
+// whatever was already logged
+func (m *DashboardModel) startWellnessForm() {
+	m.editing = true
+	m.step = 0
+	m.editErr = nil
+	m.pending = store.Wellness{Date: time.Now()}
+	if today := m.data.Readiness.Today; today != nil {
+		m.pending = *today
+	}
+	m.input = textInput{value: m.pendingValue(wellnessFormFields[0])}
+}
+
+// pendingValue is the form's current value for a field, blank if unset
+func (m DashboardModel) pendingValue(f service.WellnessField) string {
+	var v *float64
+	switch f {
+	case service.WellnessRestingHR:
+		v = m.pending.RestingHR
+	case service.WellnessHRV:
+		v = m.pending.HRV
+	case service.WellnessSleep:
+		v = m.pending.SleepHours
+	case service.WellnessSoreness:
+		if m.pending.Soreness != nil {
+			return fmt.Sprintf("%d", *m.pending.Soreness)
+		}
+	}
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// updateForm handles key presses while the wellness form is open. Blank
+// fields aren't recorded.
+func (m DashboardModel) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case cancelled:
+		m.editing = false
+		m.editErr = nil
+		m.viewport.SetContent(m.renderContent())
+		return m, nil
+	case !submitted:
+		return m, nil
+	}
+
+	field := wellnessFormFields[m.step]
+	v, err := field.Parse(m.input.value)
+	if err != nil {
+		m.editErr = err
+		return m, nil
+	}
+	switch field {
+	case service.WellnessRestingHR:
+		m.pending.RestingHR = v
+	case service.WellnessHRV:
+		m.pending.HRV = v
+	case service.WellnessSleep:
+		m.pending.SleepHours = v
+	case service.WellnessSoreness:
+		m.pending.Soreness = nil
+		if v != nil {
+			n := int(*v)
+			m.pending.Soreness = &n
+		}
+	}
+
+	m.editErr = nil
+	m.step++
+	if m.step < len(wellnessFormFields) {
+		m.input = textInput{value: m.pendingValue(wellnessFormFields[m.step])}
+		return m, nil
+	}
+
+	m.editing = false
+	qs, entry := m.queryService, m.pending
+	return m, func() tea.Msg {
+		return wellnessSavedMsg{err: qs.SaveWellness(entry)}
+	}
+}
+
 // View renders the dashboard
 func (m DashboardModel) View() string {
 	if m.loading {
@@ -133,11 +253,19 @@ func (m DashboardModel) View() string {
 		return m.renderContent() + "\n" + statusStyle.Render("  r to refresh, s to sync")
 	}
 
-	// Show scroll indicator
+	// Show scroll indicator, or the wellness form while it's open
 	scrollPct := m.viewport.ScrollPercent() * 100
-	scrollInfo := statusStyle.Render(fmt.Sprintf("  scroll: %.0f%% (j/k or arrows to scroll, r to refresh)", scrollPct))
+	footer := statusStyle.Render(fmt.Sprintf("  scroll: %.0f%% (j/k or arrows to scroll, w to log wellness, r to refresh)", scrollPct))
+	if m.editing {
+		field := wellnessFormFields[m.step]
+		footer = fmt.Sprintf("  Today's %s: %s", field.Label, m.input.view()) +
+			statusStyle.Render(fmt.Sprintf("  (%d/%d)  enter: next, blank to skip  esc: cancel", m.step+1, len(wellnessFormFields)))
+	}
+	if m.editErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error: %v", m.editErr)), footer)
+	}
 
-	return m.viewport.View() + "\n" + scrollInfo
+	return m.viewport.View() + "\n" + footer
 }
 
 func (m DashboardModel) renderContent() string {
@@ -145,38 +273,60 @@ func (m DashboardModel) renderContent() string {
 		return ""
 	}
 
-	// Build the dashboard layout
+	// Cards flow into as many columns as the terminal fits, so each list
+	// is in reading order rather than fixed rows
+	g := m.layout()
 	var sections []string
 
-	// Top row: Current Fitness and This Week side by side
-	fitnessCard := m.renderFitnessCard()
-	weekCard := m.renderWeekCard()
-	topRow := lipgloss.JoinHorizontal(lipgloss.Top, fitnessCard, "  ", weekCard)
-	sections = append(sections, topRow)
+	// Top: Current Fitness, This Week and Readiness
+	sections = append(sections, g.rows([]string{
+		m.renderFitnessCard(),
+		m.renderWeekCard(),
+		m.renderReadinessCard(),
+	})...)
 
-	// Charts row 1: EF and Weekly Mileage side by side
-	var chartsRow1 []string
+	// Charts: EF, weekly mileage and vertical gain, cadence and HR trends, pacing
+	// discipline and stride length, then HR recovery
+	var charts []string
 	if len(m.data.EFHistory) > 2 {
-		chartsRow1 = append(chartsRow1, m.renderEFChart())
+		charts = append(charts, m.renderEFChart())
 	}
 	if len(m.data.WeeklyMileage) > 0 {
-		chartsRow1 = append(chartsRow1, m.renderMileageChart())
+		charts = append(charts, m.renderMileageChart())
 	}
-	if len(chartsRow1) > 0 {
-		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, chartsRow1...))
+	if hasNonZero(m.data.WeeklyVertical) {
+		charts = append(charts, m.renderVerticalChart())
 	}
-
-	// Charts row 2: Cadence and HR trends
-	var chartsRow2 []string
 	if len(m.data.WeeklyAvgCadence) > 0 && hasNonZero(m.data.WeeklyAvgCadence) {
-		chartsRow2 = append(chartsRow2, m.renderCadenceChart())
+		charts = append(charts, m.renderCadenceChart())
 	}
 	if len(m.data.WeeklyAvgHR) > 0 && hasNonZero(m.data.WeeklyAvgHR) {
-		chartsRow2 = append(chartsRow2, m.renderHRChart())
+		charts = append(charts, m.renderHRChart())
 	}
-	if len(chartsRow2) > 0 {
-		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, chartsRow2...))
+	if len(m.data.PacingHistory) > 2 {
+		charts = append(charts, m.renderPacingChart())
 	}
+	if len(m.data.StrideHistory) > 2 {
+		charts = append(charts, m.renderStrideChart())
+	}
+	if len(m.data.HRRHistory) > 2 {
+		charts = append(charts, m.renderHRRChart())
+	}
+
+	// EF and VDOT trajectories, then VO2max from HR and pace against VDOT
+	if m.data.EFProjection.OK {
+		charts = append(charts, m.renderProjectionChart("EF Trajectory", m.data.EFProjection, 2, "%+.3f"))
+	}
+	if m.data.VDOTProjection.OK {
+		charts = append(charts, m.renderProjectionChart("VDOT Trajectory", m.data.VDOTProjection, 1, "%+.2f"))
+	}
+	if m.data.VO2max.WeightSet {
+		charts = append(charts, m.renderVO2maxCard())
+		if m.data.VO2max.Trend.OK {
+			charts = append(charts, m.renderProjectionChart("VO2max Trajectory", m.data.VO2max.Trend, 1, "%+.2f"))
+		}
+	}
+	sections = append(sections, g.rows(charts)...)
 
 	// Recent activities
 	activities := m.renderRecentActivities()
@@ -185,30 +335,94 @@ func (m DashboardModel) renderContent() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
+// layout sizes the dashboard's cards to the terminal width
+func (m DashboardModel) layout() gridLayout {
+	return newGridLayout(m.width)
+}
+
 func (m DashboardModel) renderFitnessCard() string {
 	title := cardTitleStyle.Render("Current Fitness")
 
-	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
 
 	lines := []string{
 		RenderMetric("Efficiency Factor", fmt.Sprintf("%.2f", m.data.CurrentEF), m.data.EFTrend),
 		RenderMetric("Fitness (CTL)", fmt.Sprintf("%.0f", m.data.CurrentFitness), ""),
 		RenderMetric("Fatigue (ATL)", fmt.Sprintf("%.0f", m.data.CurrentFatigue), ""),
 		RenderMetric("Form (TSB)", fmt.Sprintf("%.0f", m.data.CurrentForm), ""),
-		"",
-		mutedStyle.Render(m.data.FormDescription),
+	}
+	if m.data.Monotony > 0 {
+		lines = append(lines,
+			RenderMetric("Monotony (7d)", fmt.Sprintf("%.1f", m.data.Monotony), ""),
+			RenderMetric("Strain (7d)", fmt.Sprintf("%.0f", m.data.Strain), ""),
+		)
+	}
+	for _, p := range m.data.PaceAtHR {
+		if p.Runs > 0 {
+			lines = append(lines, RenderMetric("Pace @ "+p.Label, m.units.FormatPacePerMile(p.Pace), ""))
+		}
+	}
+	lines = append(lines, "", mutedStyle.Render(m.data.FormDescription))
+	if m.data.HighMonotony {
+		lines = append(lines, warningStyle.Render("⚠ Monotony > 2.0: vary your days"))
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
-	return cardStyle.Width(38).Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
+	return cardStyle.Width(m.layout().cardWidth(38)).Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
+}
+
+// vo2maxGap is how far VO2max and VDOT can differ before the card points
+// it out, in ml/kg/min
+const vo2maxGap = 3.0
+
+// renderVO2maxCard compares VO2max estimated from easy runs with the VDOT
+// the athlete's efforts imply. VDOT also reflects running economy and
+// speed endurance, so a large gap says which one is lagging.
+func (m DashboardModel) renderVO2maxCard() string {
+	title := cardTitleStyle.Render("Aerobic Capacity")
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	est := m.data.VO2max
+
+	if !est.OK {
+		lines := []string{
+			muted.Render(fmt.Sprintf("Needs %d steady runs of 20+ min", service.VO2maxMinRuns)),
+			muted.Render(fmt.Sprintf("in the last %d days (%d so far).", service.VO2maxWindowDays, est.Runs)),
+		}
+		return cardStyle.Width(m.layout().cardWidth(38)).Render(lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	}
+
+	lines := []string{
+		RenderMetric("VO2max (HR/pace)", fmt.Sprintf("%.1f", est.Current), fmt.Sprintf("%.1f L/min", est.Absolute)),
+	}
+	note := "No recent efforts to compare with."
+	if est.VDOT > 0 {
+		gap := est.Current - est.VDOT
+		lines = append(lines,
+			RenderMetric("VDOT (efforts)", fmt.Sprintf("%.1f", est.VDOT), ""),
+			RenderMetric("Gap", fmt.Sprintf("%+.1f", gap), ""),
+		)
+		switch {
+		case gap > vo2maxGap:
+			note = "Engine ahead of race fitness: threshold and race-pace work should close the gap."
+		case gap < -vo2maxGap:
+			note = "Efforts beat what HR suggests: check resting and max HR settings."
+		default:
+			note = "HR-based VO2max and VDOT agree."
+		}
+	}
+	lines = append(lines, "",
+		muted.Render(note),
+		muted.Render(fmt.Sprintf("ml/kg/min, median of %d runs in %d days", est.Runs, service.VO2maxWindowDays)))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return cardStyle.Width(m.layout().cardWidth(38)).Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
 }
 
 func (m DashboardModel) renderWeekCard() string {
 	title := cardTitleStyle.Render("This Week")
 
-	// WeekDistance is stored in miles internally, need to convert meters
-	// Note: WeekDistance from service is calculated via metersToMiles, so it's in miles
-	distMeters := m.data.WeekDistance * 1609.34 // Convert back to meters for formatting
+	// WeekDistance from the service is in miles
+	distMeters := m.data.WeekDistance * metersPerMile
 
 	lines := []string{
 		RenderMetric("Runs", fmt.Sprintf("%d", m.data.WeekRunCount), ""),
@@ -216,75 +430,248 @@ func (m DashboardModel) renderWeekCard() string {
 		RenderMetric("Time", formatDuration(m.data.WeekTime), ""),
 		RenderMetric("Avg EF", fmt.Sprintf("%.2f", m.data.WeekAvgEF), ""),
 	}
+	if streaks := m.data.Streaks; streaks.Longest > 0 {
+		lines = append(lines,
+			"",
+			RenderMetric("Run Streak", formatDays(streaks.Current), ""),
+			RenderMetric("Longest Streak", formatDays(streaks.Longest), ""),
+			RenderMetric("Since Rest", formatDays(streaks.DaysSinceRest), ""),
+		)
+		if m.data.NeedsRest {
+			lines = append(lines, warningStyle.Render(fmt.Sprintf("⚠ No rest day in %d+ days", m.data.RestDayWarningDays)))
+		}
+	}
+	if goal := m.data.GoalConsistency; goal.Weeks > 0 {
+		lines = append(lines,
+			"",
+			RenderMetric("Goal Streak", formatWeeks(goal.Streak), ""),
+			RenderMetric("Consistency", fmt.Sprintf("%.0f%% of %d wks", goal.Percent, goal.Weeks), ""),
+		)
+	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
-	return cardStyle.Width(30).Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
+	return cardStyle.Width(m.layout().cardWidth(30)).Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
 }
 
-func (m DashboardModel) renderEFChart() string {
-	title := cardTitleStyle.Render("Efficiency Factor Trend")
+// renderReadinessCard shows today's wellness and the readiness score it
+// gives combined with form
+func (m DashboardModel) renderReadinessCard() string {
+	title := cardTitleStyle.Render("Readiness")
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	r := m.data.Readiness
+
+	if !r.OK {
+		lines := []string{
+			muted.Render("No wellness logged today."),
+			muted.Render("Press w to log resting HR,"),
+			muted.Render("HRV, sleep and soreness."),
+		}
+		return cardStyle.Width(m.layout().cardWidth(36)).Render(lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	}
 
-	graph := asciigraph.Plot(m.data.EFHistory,
-		asciigraph.Height(6),
-		asciigraph.Width(35),
-		asciigraph.Precision(2),
-	)
+	scoreStyle := metricValueStyle
+	switch {
+	case r.Score < analysis.ReadinessLow:
+		scoreStyle = warningStyle.Bold(true)
+	case r.Score >= analysis.ReadinessHigh:
+		scoreStyle = successStyle.Bold(true)
+	}
+
+	today := r.Today
+	lines := []string{
+		scoreStyle.Render(fmt.Sprintf("%.0f / 100", r.Score)) + " " + muted.Render(analysis.ReadinessAdvice(r.Score)),
+		"",
+	}
+	if today.RestingHR != nil {
+		lines = append(lines, RenderMetric("Resting HR", fmt.Sprintf("%.0f bpm", *today.RestingHR), baselineDelta(*today.RestingHR, r.BaselineRestingHR)))
+	}
+	if today.HRV != nil {
+		lines = append(lines, RenderMetric("HRV", fmt.Sprintf("%.0f ms", *today.HRV), baselineDelta(*today.HRV, r.BaselineHRV)))
+	}
+	if today.SleepHours != nil {
+		lines = append(lines, RenderMetric("Sleep", fmt.Sprintf("%.1f h", *today.SleepHours), ""))
+	}
+	if today.Soreness != nil {
+		lines = append(lines, RenderMetric("Soreness", fmt.Sprintf("%d / 5", *today.Soreness), ""))
+	}
+	if r.Form != nil {
+		lines = append(lines, RenderMetric("Form (TSB)", fmt.Sprintf("%.0f", m.data.CurrentForm), ""))
+	}
+	if r.BaselineRestingHR == nil && r.BaselineHRV == nil && (today.RestingHR != nil || today.HRV != nil) {
+		lines = append(lines, "", muted.Render(fmt.Sprintf("HR and HRV count after %d days", service.ReadinessMinBaseline)))
+	}
 
-	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, graph))
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return cardStyle.Width(m.layout().cardWidth(36)).Render(lipgloss.JoinVertical(lipgloss.Left, title, content))
 }
 
-func (m DashboardModel) renderMileageChart() string {
-	title := cardTitleStyle.Render(fmt.Sprintf("Weekly Distance (12 weeks)"))
+// baselineDelta formats how far a value is from its usual level, blank
+// until there's a baseline. It's bracketed so it isn't colored as a trend:
+// higher is worse for resting HR but better for HRV.
+func baselineDelta(v float64, baseline *float64) string {
+	if baseline == nil {
+		return ""
+	}
+	return fmt.Sprintf("(%+.0f)", v-*baseline)
+}
+
+// formatDays formats a count of days as "1 day" or "12 days"
+func formatDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// formatWeeks formats a count of weeks as "1 week" or "6 weeks"
+func formatWeeks(n int) string {
+	if n == 1 {
+		return "1 week"
+	}
+	return fmt.Sprintf("%d weeks", n)
+}
 
-	// WeeklyMileage is in miles from service, convert if needed
-	data := m.data.WeeklyMileage
-	caption := m.units.DistanceLabelLong() + "/week"
-	if !m.units.IsMiles() {
-		// Convert miles to km
+func (m DashboardModel) renderEFChart() string {
+	labels := make([]string, len(m.data.EFDates))
+	for i, d := range m.data.EFDates {
+		labels[i] = d.Format("Jan 02")
+	}
+	chart := lineChart{Precision: 2, XLabels: labels}
+	if n := m.data.EFHeatAdjusted; n > 0 {
+		chart.Caption = fmt.Sprintf("heat-adjusted: %d of %d runs", n, len(m.data.EFHistory))
+	}
+	return m.renderLineChart("Efficiency Factor Trend", m.data.EFHistory, chart)
+}
+
+func (m DashboardModel) renderPacingChart() string {
+	return m.renderLineChart("Pacing Split Trend", m.data.PacingHistory, lineChart{
+		Precision: 1,
+		Caption:   "% 2nd half slower (- = negative split)",
+	})
+}
+
+func (m DashboardModel) renderStrideChart() string {
+	data := m.data.StrideHistory
+	caption := "m/step"
+	if m.units.IsMiles() {
 		converted := make([]float64, len(data))
-		for i, mi := range data {
-			converted[i] = mi * 1.60934
+		for i, meters := range data {
+			converted[i] = meters * 3.28084
 		}
 		data = converted
+		caption = "ft/step"
 	}
 
+	return m.renderLineChart("Stride Length Trend", data, lineChart{Precision: 2, Caption: caption})
+}
+
+func (m DashboardModel) renderHRRChart() string {
+	return m.renderLineChart("HR Recovery Trend (60s)", m.data.HRRHistory, lineChart{
+		Caption: "bpm drop (higher = fitter)",
+	})
+}
+
+func (m DashboardModel) renderMileageChart() string {
+	// WeeklyMileage from the service is in miles
+	data := make([]float64, len(m.data.WeeklyMileage))
+	for i, mi := range m.data.WeeklyMileage {
+		data[i] = m.units.FromMiles(mi)
+	}
 	data = trimTrailingZeros(data)
-	graph := asciigraph.Plot(data,
-		asciigraph.Height(6),
-		asciigraph.Width(35),
-		asciigraph.Precision(0),
-		asciigraph.Caption(caption),
-	)
 
-	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, graph))
+	chart := lineChart{
+		XLabels: m.weekLabels(len(data)),
+		Caption: m.units.DistanceLabelLong() + "/week",
+	}
+	if m.data.WeeklyGoal > 0 {
+		chart.Targets = []chartTarget{{Value: m.units.FromMiles(m.data.WeeklyGoal), Label: "goal"}}
+	}
+	return m.renderLineChart("Weekly Distance (12 weeks)", data, chart)
 }
 
-func (m DashboardModel) renderCadenceChart() string {
-	title := cardTitleStyle.Render("Weekly Avg Cadence (12 weeks)")
+func (m DashboardModel) renderVerticalChart() string {
+	// WeeklyVertical from the service is in meters
+	scale, caption := 1.0, "m climbed/week"
+	if m.units.IsMiles() {
+		scale, caption = feetPerMeter, "ft climbed/week"
+	}
+	data := make([]float64, len(m.data.WeeklyVertical))
+	for i, meters := range m.data.WeeklyVertical {
+		data[i] = meters * scale
+	}
+	data = trimTrailingZeros(data)
 
-	data := trimTrailingZeros(m.data.WeeklyAvgCadence)
-	graph := asciigraph.Plot(data,
-		asciigraph.Height(6),
-		asciigraph.Width(35),
-		asciigraph.Precision(0),
-		asciigraph.Caption("spm"),
-	)
+	return m.renderLineChart("Weekly Vertical Gain (12 weeks)", data, lineChart{
+		XLabels: m.weekLabels(len(data)),
+		Caption: caption,
+	})
+}
 
-	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, graph))
+func (m DashboardModel) renderCadenceChart() string {
+	data := trimTrailingZeros(m.data.WeeklyAvgCadence)
+	return m.renderLineChart("Weekly Avg Cadence (12 weeks)", data, lineChart{
+		XLabels: m.weekLabels(len(data)),
+		Caption: "spm",
+	})
 }
 
 func (m DashboardModel) renderHRChart() string {
-	title := cardTitleStyle.Render("Weekly Avg HR (12 weeks)")
-
 	data := trimTrailingZeros(m.data.WeeklyAvgHR)
-	graph := asciigraph.Plot(data,
+	return m.renderLineChart("Weekly Avg HR (12 weeks)", data, lineChart{
+		XLabels: m.weekLabels(len(data)),
+		Caption: "bpm",
+	})
+}
+
+// dashboardChartHeight is the height of the dashboard's trend charts in rows
+const dashboardChartHeight = 6
+
+// renderLineChart draws a braille chart in a card sized to the layout
+func (m DashboardModel) renderLineChart(title string, data []float64, chart lineChart) string {
+	g := m.layout()
+	chart.Width = g.chartWidth()
+	chart.Height = dashboardChartHeight
+	return cardStyle.Width(g.cardWidth(0)).Render(lipgloss.JoinVertical(lipgloss.Left,
+		cardTitleStyle.Render(title), chart.render(data)))
+}
+
+// weekLabels returns the labels of the first n weekly chart points
+func (m DashboardModel) weekLabels(n int) []string {
+	return m.data.WeeklyLabels[:min(n, len(m.data.WeeklyLabels))]
+}
+
+// renderProjectionChart draws the smoothed weekly history followed by the
+// linear projection, with the edges of its prediction band in gray
+func (m DashboardModel) renderProjectionChart(title string, p service.TrendProjection, precision uint, slopeFormat string) string {
+	weeks := len(p.History) + len(p.Projected) - 1
+
+	// The projection starts at the current week, the last history column
+	offset := len(p.History) - 1
+	pad := func(values []float64) []float64 {
+		series := make([]float64, weeks)
+		for i := range series {
+			series[i] = math.NaN()
+		}
+		copy(series[offset:], values)
+		return series
+	}
+
+	graph := asciigraph.PlotMany([][]float64{p.History, pad(p.Low), pad(p.High), pad(p.Projected)},
 		asciigraph.Height(6),
-		asciigraph.Width(35),
-		asciigraph.Precision(0),
-		asciigraph.Caption("bpm"),
+		asciigraph.Width(m.layout().chartWidth()),
+		asciigraph.Precision(precision),
+		asciigraph.SeriesColors(asciigraph.Default, activeTheme.ChartBand, activeTheme.ChartBand, activeTheme.ChartProjection),
+		asciigraph.Caption(fmt.Sprintf("%d wks history, %d projected", len(p.History), len(p.Projected)-1)),
 	)
 
-	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, graph))
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	digits := int(precision)
+	summary := fmt.Sprintf("Now %.*f → %.*f  (%s/wk)", digits, p.Current, digits, p.ProjectedEnd,
+		fmt.Sprintf(slopeFormat, p.SlopePerWeek))
+	band := fmt.Sprintf("95%% band %.*f–%.*f", digits, p.Low[len(p.Low)-1], digits, p.High[len(p.High)-1])
+
+	return cardStyle.Width(m.layout().cardWidth(0)).Render(lipgloss.JoinVertical(lipgloss.Left,
+		cardTitleStyle.Render(title), graph, summary, muted.Render(band)))
 }
 
 func hasNonZero(data []float64) bool {
@@ -370,8 +757,9 @@ func formatDuration(seconds int) string {
 }
 
 func truncateName(s string, max int) string {
-	if len(s) <= max {
+	runes := []rune(s)
+	if len(runes) <= max {
 		return s
 	}
-	return s[:max-3] + "..."
+	return string(runes[:max-3]) + "..."
 }
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"runner/internal/service"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DistributionModel is the training distribution (80/20) screen model
+type DistributionModel struct {
+	queryService QueryProvider
+	data         *service.TrainingDistribution
+	viewport     viewport.Model
+	loading      bool
+	err          error
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewDistributionModel creates a new training distribution model
+func NewDistributionModel(qs QueryProvider, width, height int) DistributionModel {
+	m := DistributionModel{
+		queryService: qs,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the training distribution screen
+func (m DistributionModel) Init() tea.Cmd {
+	return m.loadDistribution
+}
+
+type distributionLoadedMsg struct {
+	data *service.TrainingDistribution
+	err  error
+}
+
+func (m DistributionModel) loadDistribution() tea.Msg {
+	data, err := m.queryService.GetTrainingDistribution(service.ChartWeeks)
+	return distributionLoadedMsg{data: data, err: err}
+}
+
+// Update handles messages
+func (m DistributionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case distributionLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.data = msg.data
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if m.data != nil {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			return m, m.loadDistribution
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the training distribution screen
+func (m DistributionModel) View() string {
+	if m.loading {
+		return "\n  Loading training distribution..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	footer := statusStyle.Render("  j/k or arrows: scroll  r: refresh")
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+// Bar geometry for the weekly easy/hard split
+const distributionBarWidth = 40
+
+func (m DistributionModel) renderContent() string {
+	if m.data == nil {
+		return ""
+	}
+
+	var sections []string
+
+	sections = append(sections, "")
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("Training Distribution (last %d weeks)", len(m.data.Weeks))))
+	sections = append(sections, "")
+
+	if m.data.Overall.TotalSeconds == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render("  No heart rate data in this period."))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	sections = append(sections, m.renderSummary())
+	sections = append(sections, m.renderWeeks())
+	sections = append(sections, m.renderLegend())
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m DistributionModel) renderSummary() string {
+	o := m.data.Overall
+	target := m.data.TargetLow
+
+	var verdict string
+	switch {
+	case o.LowPct >= target-5 && o.LowPct <= target+10:
+		verdict = successStyle.Render("On target")
+	case o.LowPct < target-5:
+		verdict = warningStyle.Render("Too much intensity - run more of your training easy")
+	default:
+		verdict = lipgloss.NewStyle().Foreground(mutedColor).Render("Mostly easy - room for more quality work")
+	}
+
+	lines := []string{
+		fmt.Sprintf("  Overall: %s easy (Z1-Z2) / %s hard (Z3+)   target %.0f/%.0f",
+			easyStyle.Render(fmt.Sprintf("%.0f%%", o.LowPct)),
+			hardStyle.Render(fmt.Sprintf("%.0f%%", o.HighPct)),
+			target, 100-target),
+		"  " + verdict,
+		"",
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m DistributionModel) renderWeeks() string {
+	var lines []string
+
+	header := fmt.Sprintf("  %-8s  %-*s  %5s  %5s  %8s", "Week", distributionBarWidth, "Easy │ Hard", "Easy", "Hard", "HR Time")
+	lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(header))
+
+	targetCol := int(m.data.TargetLow / 100 * distributionBarWidth)
+
+	for _, w := range m.data.Weeks {
+		if w.TotalSeconds == 0 {
+			lines = append(lines, fmt.Sprintf("  %-8s  %-*s  %5s  %5s  %8s", w.Label, distributionBarWidth, strings.Repeat("·", distributionBarWidth), "-", "-", "-"))
+			continue
+		}
+
+		easy := int(w.LowPct/100*distributionBarWidth + 0.5)
+		var bar strings.Builder
+		for i := 0; i < distributionBarWidth; i++ {
+			ch := "█"
+			if i == targetCol {
+				ch = "│" // 80/20 target marker
+			}
+			if i < easy {
+				bar.WriteString(easyStyle.Render(ch))
+			} else {
+				bar.WriteString(hardStyle.Render(ch))
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("  %-8s  %s  %4.0f%%  %4.0f%%  %8s",
+			w.Label, bar.String(), w.LowPct, w.HighPct, formatDuration(w.TotalSeconds)))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+func (m DistributionModel) renderLegend() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	lines := []string{
+		muted.Render("  The │ marks the 80/20 target: about 80% of training time easy (Z1-Z2)"),
+		muted.Render("  and 20% moderate or hard (Z3+). Zones follow the HR zones on the activity"),
+		muted.Render("  detail screen."),
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// fakeQueries serves canned data to the screens. Methods a test doesn't set
+// up fall through to the nil embedded interface and panic.
+type fakeQueries struct {
+	QueryProvider
+
+	periodStats map[string][]service.PeriodStats
+	periodErr   error
+	calls       []string
+	athlete     config.AthleteConfig
+}
+
+func (f *fakeQueries) GetPeriodStats(periodType string, numPeriods int) ([]service.PeriodStats, error) {
+	f.calls = append(f.calls, periodType)
+	return f.periodStats[periodType], f.periodErr
+}
+
+func (f *fakeQueries) SetAthleteConfig(athleteCfg config.AthleteConfig) { f.athlete = athleteCfg }
+
+func (f *fakeQueries) SetRiegelExponent(exponent float64) {}
+
+func (f *fakeQueries) SetRestDayWarningDays(days int) {}
+
+func (f *fakeQueries) SetRampThreshold(pct float64) {}
+
+func (f *fakeQueries) SetWeekNumbers(on bool) {}
+
+func (f *fakeQueries) SetBucketing(analysisCfg config.AnalysisConfig) error { return nil }
+
+func (f *fakeQueries) SetLoadModel(model string) error { return nil }
+
+// fakeSync stands in for a sync service with a fixed dry run
+type fakeSync struct {
+	SyncRunner
+
+	preview   *service.SyncPreview
+	elsewhere *store.SyncLock
+	requests  []strava.RequestRecord
+}
+
+func (f *fakeSync) Preview(ctx context.Context) (*service.SyncPreview, error) {
+	return f.preview, nil
+}
+
+func (f *fakeSync) RateLimitStatus() (int, int) { return 90, 950 }
+
+func (f *fakeSync) RateLimitWait() time.Time { return time.Time{} }
+
+func (f *fakeSync) RecentRequests() []strava.RequestRecord { return f.requests }
+
+func (f *fakeSync) Resume() {}
+
+func (f *fakeSync) SyncElsewhere() (*store.SyncLock, error) { return f.elsewhere, nil }
+
+// testUnits displays miles
+func testUnits() Units {
+	return NewUnits(config.DisplayConfig{DistanceUnit: "mi"})
+}
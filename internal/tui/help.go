@@ -41,6 +41,19 @@ func (m HelpModel) View() string {
 		{"5", "Personal Records"},
 		{"6", "Race Predictions"},
 		{"7", "Sync screen"},
+		{"8", "Training distribution (80/20)"},
+		{"9", "Debug logs"},
+		{"A", "Strava API requests (why a sync stalls)"},
+		{"0", "Year in review"},
+		{"p", "Critical pace (pace-duration curve)"},
+		{"R", "Races"},
+		{"F", "Fastest segments (top 10 efforts per distance)"},
+		{"I", "Injury log"},
+		{"P", "Training plan (weekly mileage toward a race)"},
+		{"B", "Benchmark workouts"},
+		{"L", "Long-horizon trends (6, 12 or 24 months)"},
+		{"H", "Pace at heart rate (aerobic speed)"},
+		{",", "Settings"},
 		{"?", "Help (this screen)"},
 		{"q", "Quit"},
 		{"esc", "Back / close help"},
@@ -49,6 +62,7 @@ func (m HelpModel) View() string {
 
 	// Dashboard keys
 	dashSection := m.renderSection("Dashboard", []keyHelp{
+		{"w", "Log today's wellness (resting HR, HRV, sleep, soreness)"},
 		{"r", "Refresh data"},
 	})
 	sections = append(sections, dashSection)
@@ -60,6 +74,10 @@ func (m HelpModel) View() string {
 		{"k / up", "Move cursor up"},
 		{"pgdn", "Next page"},
 		{"pgup", "Previous page"},
+		{"/", "Search by name/note, tag:, type:, after:, before:, dist:, pr"},
+		{"x", "Clear search and filters"},
+		{"o", "Cycle sort (date, distance, duration, pace, EF, TRIMP, decoupling)"},
+		{"O", "Reverse sort direction"},
 		{"r", "Refresh list"},
 	})
 	sections = append(sections, actSection)
@@ -69,10 +87,36 @@ func (m HelpModel) View() string {
 		{"j / down", "Scroll down"},
 		{"k / up", "Scroll up"},
 		{"esc", "Back to activities list"},
+		{"t", "Edit tags (comma separated)"},
+		{"n", "Edit note"},
+		{"T", "Set the temperature (heat-adjusts EF)"},
+		{"e", "Log RPE (1-10) and feel (1-5), e.g. 7/good"},
+		{"b", "Make a benchmark, or add the run to one by name"},
+		{"x", "Exclude from (or restore to) metrics and PRs"},
+		{"v", "Confirm unverified best efforts are real"},
+		{"d", "Raw data: every stream point, with CSV export and trimming"},
+		{"u", "Show mile and km splits (or only the configured unit)"},
+		{"h / l", "Move the chart cursor a minute (arrows too)"},
+		{"H / L", "Move the chart cursor five minutes"},
+		{"g", "Scroll to the split under the chart cursor"},
+		{"C", "Correct a treadmill run's distance (factor or cadence)"},
+		{"S", "Resync from Strava (summary, streams, metrics, PRs); undoes local edits"},
 		{"r", "Refresh"},
 	})
 	sections = append(sections, detailSection)
 
+	// Raw data keys
+	rawSection := m.renderSection("Raw Data", []keyHelp{
+		{"j / k", "Move one point"},
+		{"pgdn / pgup", "Move one page"},
+		{"g / G", "First / last point"},
+		{"space", "Mark one end of a range (again to clear)"},
+		{"e", "Export the range, or every point, as CSV"},
+		{"t", "Trim the activity after this point (locally)"},
+		{"esc", "Back to activity detail"},
+	})
+	sections = append(sections, rawSection)
+
 	// Stats keys
 	statsSection := m.renderSection("Period Stats", []keyHelp{
 		{"w", "Weekly view"},
@@ -89,6 +133,8 @@ func (m HelpModel) View() string {
 	compareSection := m.renderSection("Trend Comparisons", []keyHelp{
 		{"w", "Weekly comparisons (this week vs last week)"},
 		{"m", "Monthly comparisons (month vs month, year over year)"},
+		{"n", "New comparison of any two ranges (last 6w, 2024-06, ...)"},
+		{"x", "Clear custom comparisons"},
 		{"r", "Refresh"},
 	})
 	sections = append(sections, compareSection)
@@ -97,6 +143,8 @@ func (m HelpModel) View() string {
 	prsSection := m.renderSection("Personal Records", []keyHelp{
 		{"j / down", "Scroll down"},
 		{"k / up", "Scroll up"},
+		{"h", "Record history (progression per category)"},
+		{"[ / ]", "Previous / next category in the history"},
 		{"r", "Refresh"},
 	})
 	sections = append(sections, prsSection)
@@ -109,12 +157,125 @@ func (m HelpModel) View() string {
 	})
 	sections = append(sections, predictSection)
 
+	// Races keys
+	racesSection := m.renderSection("Races", []keyHelp{
+		{"enter", "View activity details"},
+		{"j / down", "Move cursor down"},
+		{"k / up", "Move cursor up"},
+		{"n", "Edit placing notes"},
+		{"x", "Not a race (remove from the list)"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, racesSection)
+
+	// Segments keys
+	segmentsSection := m.renderSection("Fastest Segments", []keyHelp{
+		{"h / l or [ / ]", "Previous / next distance"},
+		{"enter", "View activity details"},
+		{"j / down", "Move cursor down"},
+		{"k / up", "Move cursor up"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, segmentsSection)
+
+	// Injuries keys
+	injuriesSection := m.renderSection("Injury Log", []keyHelp{
+		{"j / down", "Move cursor down"},
+		{"k / up", "Move cursor up"},
+		{"a", "Log an injury (date, body part, severity, notes)"},
+		{"e", "Mark resolved today, or reopen"},
+		{"x", "Delete injury"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, injuriesSection)
+
+	// Training plan keys
+	planSection := m.renderSection("Training Plan", []keyHelp{
+		{"j / down", "Move cursor down"},
+		{"k / up", "Move cursor up"},
+		{"a", "New plan (race date, peak week, taper, cutbacks)"},
+		{"D", "Delete the plan"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, planSection)
+
+	// Benchmarks keys
+	benchmarksSection := m.renderSection("Benchmarks", []keyHelp{
+		{"h / l", "Previous / next benchmark ([ and ] too)"},
+		{"enter", "View activity details"},
+		{"j / down", "Move cursor down"},
+		{"k / up", "Move cursor up"},
+		{"x", "Remove a run matched by mistake"},
+		{"D", "Delete the benchmark (runs are kept)"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, benchmarksSection)
+
+	// Critical pace keys
+	paceSection := m.renderSection("Critical Pace", []keyHelp{
+		{"j / down", "Scroll down"},
+		{"k / up", "Scroll up"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, paceSection)
+
+	// Trends keys
+	trendsSection := m.renderSection("Trends", []keyHelp{
+		{"[ / ]", "Shorter / longer horizon (6, 12 or 24 months)"},
+		{"d", "Durability by run duration or distance"},
+		{"j / down", "Scroll down"},
+		{"k / up", "Scroll up"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, trendsSection)
+
+	// Pace at HR keys
+	paceAtHRSection := m.renderSection("Pace at HR", []keyHelp{
+		{"[ / ]", "Shorter / longer horizon (6, 12 or 24 months)"},
+		{"j / down", "Scroll down"},
+		{"k / up", "Scroll up"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, paceAtHRSection)
+
 	// Sync keys
 	syncSection := m.renderSection("Sync Screen", []keyHelp{
 		{"s / enter", "Start sync"},
+		{"j/k / space", "Choose which phases run"},
+		{"a", "Select all phases or none"},
+		{"p / space", "Pause or resume a running sync"},
+		{"esc", "Cancel a running sync (progress is kept)"},
+		{"d", "Dry run: preview what a sync would fetch and cost"},
+		{"f", "Retry failed items after a sync with errors"},
 	})
 	sections = append(sections, syncSection)
 
+	// Settings keys
+	settingsSection := m.renderSection("Settings", []keyHelp{
+		{"j / k", "Move between settings"},
+		{"enter", "Edit a number, or switch a choice or toggle"},
+		{"s", "Validate and save to config.json, applying it right away"},
+		{"u", "Discard unsaved changes"},
+	})
+	sections = append(sections, settingsSection)
+
+	// Logs keys
+	logsSection := m.renderSection("Logs", []keyHelp{
+		{"j / down", "Scroll down"},
+		{"k / up", "Scroll up"},
+		{"w", "Show only warnings and errors"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, logsSection)
+
+	// Requests keys
+	requestsSection := m.renderSection("API Requests", []keyHelp{
+		{"j / down", "Scroll down"},
+		{"k / up", "Scroll up"},
+		{"r", "Refresh"},
+	})
+	sections = append(sections, requestsSection)
+
 	// Metrics explanation
 	metricsSection := m.renderMetricsHelp()
 	sections = append(sections, metricsSection)
@@ -131,7 +292,7 @@ func (m HelpModel) renderSection(title string, keys []keyHelp) string {
 	var lines []string
 
 	lines = append(lines, "")
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#10B981")).Render(title))
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(title))
 
 	for _, k := range keys {
 		lines = append(lines, "  "+RenderKeyHelp(k.key, k.desc))
@@ -144,10 +305,10 @@ func (m HelpModel) renderMetricsHelp() string {
 	var lines []string
 
 	lines = append(lines, "")
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#10B981")).Render("Metrics Explained"))
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render("Metrics Explained"))
 
-	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B"))
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	valueStyle := lipgloss.NewStyle().Foreground(warningColor)
 
 	// EF
 	lines = append(lines, "")
@@ -181,6 +342,18 @@ func (m HelpModel) renderMetricsHelp() string {
 	lines = append(lines, "  "+mutedStyle.Render("Positive = fresh/recovered, negative = fatigued."))
 	lines = append(lines, "  "+mutedStyle.Render("Race ready: +5 to +15. Heavy training: -10 to -30."))
 
+	// Monotony and strain
+	lines = append(lines, "")
+	lines = append(lines, "  "+helpKeyStyle.Render("Monotony / Strain")+" "+valueStyle.Render("Warning: monotony >2.0"))
+	lines = append(lines, "  "+mutedStyle.Render("Mean daily TRIMP over its spread for the last 7 days (Foster)."))
+	lines = append(lines, "  "+mutedStyle.Render("High = every day alike. Strain = weekly load x monotony."))
+
+	// Readiness
+	lines = append(lines, "")
+	lines = append(lines, "  "+helpKeyStyle.Render("Readiness")+" "+valueStyle.Render("Range: 0-100, typical ~75"))
+	lines = append(lines, "  "+mutedStyle.Render("Today's resting HR and HRV vs your 28-day baseline, sleep,"))
+	lines = append(lines, "  "+mutedStyle.Render("soreness and form, averaged. 75+ = go hard, <50 = keep it easy."))
+
 	lines = append(lines, "")
 
 	return strings.Join(lines, "\n")
@@ -0,0 +1,402 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"runner/internal/service"
+	"runner/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// injuryFormPrompts are the steps of the new injury form, in order
+var injuryFormPrompts = []string{
+	"Started (YYYY-MM-DD, blank for today)",
+	"Body part",
+	fmt.Sprintf("Severity (1 niggle - %d can't run)", service.MaxInjurySeverity),
+	"Notes (optional)",
+}
+
+// InjuriesModel is the injury log screen model
+type InjuriesModel struct {
+	queryService QueryProvider
+	units        Units
+	log          *service.InjuryLog
+	cursor       int
+	top          int // first visible row
+	loading      bool
+	err          error
+	width        int
+	height       int
+
+	// New injury form, filled in one field at a time
+	editing bool
+	step    int
+	input   textInput
+	pending store.Injury
+	editErr error
+}
+
+// NewInjuriesModel creates a new injuries model
+func NewInjuriesModel(qs QueryProvider, units Units, width, height int) InjuriesModel {
+	return InjuriesModel{
+		queryService: qs,
+		units:        units,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init initializes the injuries screen
+func (m InjuriesModel) Init() tea.Cmd {
+	return m.loadInjuries
+}
+
+type injuriesLoadedMsg struct {
+	log *service.InjuryLog
+	err error
+}
+
+type injurySavedMsg struct {
+	err error
+}
+
+func (m InjuriesModel) loadInjuries() tea.Msg {
+	injuryLog, err := m.queryService.GetInjuryLog()
+	return injuriesLoadedMsg{log: injuryLog, err: err}
+}
+
+// visibleRows is how many injuries fit on screen above the analysis and
+// charts
+func (m InjuriesModel) visibleRows() int {
+	if rows := m.height - 30; rows > 3 {
+		return rows
+	}
+	return 3
+}
+
+func (m InjuriesModel) injuries() []service.InjuryDisplay {
+	if m.log == nil {
+		return nil
+	}
+	return m.log.Injuries
+}
+
+// Update handles messages
+func (m InjuriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case injuriesLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.log = msg.log
+		if m.cursor >= len(m.injuries()) {
+			m.cursor = max(len(m.injuries())-1, 0)
+		}
+		m.scrollToCursor()
+
+	case injurySavedMsg:
+		m.editErr = msg.err
+		return m, m.loadInjuries
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateForm(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.scrollToCursor()
+			}
+		case "down", "j":
+			if m.cursor < len(m.injuries())-1 {
+				m.cursor++
+				m.scrollToCursor()
+			}
+		case "a":
+			m.editing = true
+			m.step = 0
+			m.input = textInput{}
+			m.pending = store.Injury{}
+			m.editErr = nil
+		case "e":
+			// Mark resolved today, or reopen a resolved injury
+			if injury, ok := m.selected(); ok {
+				qs := m.queryService
+				var resolvedOn *time.Time
+				if injury.Active() {
+					today, _ := service.ParseInjuryDate("", time.Now())
+					resolvedOn = &today
+				}
+				return m, func() tea.Msg {
+					return injurySavedMsg{err: qs.ResolveInjury(injury.ID, resolvedOn)}
+				}
+			}
+		case "x":
+			if injury, ok := m.selected(); ok {
+				qs := m.queryService
+				return m, func() tea.Msg {
+					return injurySavedMsg{err: qs.DeleteInjury(injury.ID)}
+				}
+			}
+		case "r":
+			m.loading = true
+			return m, m.loadInjuries
+		}
+	}
+	return m, nil
+}
+
+// updateForm handles key presses while the new injury form is open. Each
+// field is checked as it's entered so mistakes can be fixed in place.
+func (m InjuriesModel) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case cancelled:
+		m.editing = false
+		m.editErr = nil
+		return m, nil
+	case !submitted:
+		return m, nil
+	}
+
+	value := m.input.value
+	var err error
+	switch m.step {
+	case 0:
+		m.pending.StartedOn, err = service.ParseInjuryDate(value, time.Now())
+	case 1:
+		m.pending.BodyPart = strings.TrimSpace(value)
+		if m.pending.BodyPart == "" {
+			err = errors.New("body part is required")
+		}
+	case 2:
+		m.pending.Severity, err = service.ParseInjurySeverity(value)
+	case 3:
+		m.pending.Notes = value
+	}
+	if err != nil {
+		m.editErr = err
+		return m, nil
+	}
+
+	m.editErr = nil
+	m.input = textInput{}
+	m.step++
+	if m.step < len(injuryFormPrompts) {
+		return m, nil
+	}
+
+	m.editing = false
+	qs, injury := m.queryService, m.pending
+	return m, func() tea.Msg {
+		_, err := qs.AddInjury(injury)
+		return injurySavedMsg{err: err}
+	}
+}
+
+func (m InjuriesModel) selected() (service.InjuryDisplay, bool) {
+	injuries := m.injuries()
+	if m.cursor < 0 || m.cursor >= len(injuries) {
+		return service.InjuryDisplay{}, false
+	}
+	return injuries[m.cursor], true
+}
+
+// scrollToCursor keeps the cursor row on screen
+func (m *InjuriesModel) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+}
+
+// View renders the injuries screen
+func (m InjuriesModel) View() string {
+	if m.loading {
+		return "\n  Loading injuries..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	injuries := m.injuries()
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var sections []string
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("Injury Log (%d)", len(injuries))))
+
+	if len(injuries) == 0 {
+		sections = append(sections, muted.Render("  No injuries logged. Press a to log one."))
+	} else {
+		header := tableHeaderStyle.Render(fmt.Sprintf("   %-12s  %-18s  %-4s  %-14s  %7s  %5s  %s",
+			"Started", "Body part", "Sev", "Status", "Ramp", "ACWR", "Notes"))
+		sections = append(sections, header)
+
+		end := min(m.top+m.visibleRows(), len(injuries))
+		for i := m.top; i < end; i++ {
+			sections = append(sections, m.renderRow(i))
+		}
+	}
+
+	sections = append(sections, "", m.renderAnalysis())
+	if m.log != nil && len(m.log.WeeklyMiles) > 0 {
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top,
+			m.renderCTLChart(), "  ", m.renderDistanceChart()))
+	}
+
+	var footer string
+	if m.editing {
+		footer = fmt.Sprintf("  %s: %s", injuryFormPrompts[m.step], m.input.view()) +
+			statusStyle.Render(fmt.Sprintf("  (%d/%d)  enter: next  esc: cancel", m.step+1, len(injuryFormPrompts)))
+	} else {
+		footer = statusStyle.Render("  j/k: navigate  a: log injury  e: resolve/reopen  x: delete  r: refresh")
+	}
+	if m.editErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error: %v", m.editErr)), footer)
+	}
+	sections = append(sections, "", footer)
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m InjuriesModel) renderRow(i int) string {
+	injury := m.injuries()[i]
+
+	cursor := "  "
+	if i == m.cursor {
+		cursor = "> "
+	}
+
+	status := fmt.Sprintf("active, day %d", injury.Days+1)
+	if !injury.Active() {
+		status = fmt.Sprintf("healed in %dd", injury.Days)
+	}
+	ramp, acwr := "-", "-"
+	if injury.RampPct != nil {
+		ramp = fmt.Sprintf("%+.0f%%", *injury.RampPct)
+	}
+	if injury.ACWR != nil {
+		acwr = fmt.Sprintf("%.2f", *injury.ACWR)
+	}
+	notes := injury.Notes
+	if notes == "" {
+		notes = "-"
+	}
+
+	row := fmt.Sprintf("%s%-12s  %-18s  %-4d  %-14s  %7s  %5s  %s",
+		cursor,
+		injury.StartedOn.Format("Jan 02, 2006"),
+		truncateName(injury.BodyPart, 18),
+		injury.Severity,
+		status,
+		ramp,
+		acwr,
+		truncateName(notes, 30),
+	)
+
+	if i == m.cursor {
+		return tableSelectedStyle.Render(row)
+	}
+	if injury.Active() {
+		return warningStyle.Render(row)
+	}
+	return tableRowStyle.Render(row)
+}
+
+// renderAnalysis compares the load going into injuries with typical training
+func (m InjuriesModel) renderAnalysis() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	title := cardTitleStyle.Render("Load Before Injury")
+	if m.log == nil {
+		return title
+	}
+
+	a := m.log.Analysis
+	var lines []string
+	if a.RampInjuries > 0 {
+		lines = append(lines, fmt.Sprintf("Weekly ramp before injury: %+.0f%% avg over %d  (typical %+.0f%%)",
+			a.AvgRampBefore, a.RampInjuries, a.TypicalRamp))
+	}
+	if a.ACWRInjuries > 0 {
+		lines = append(lines, fmt.Sprintf("Acute:chronic load before injury: %.2f avg over %d  (typical %.2f)",
+			a.AvgACWRBefore, a.ACWRInjuries, a.TypicalACWR))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, muted.Render("Not enough training history before any injury to compare."))
+	} else {
+		lines = append(lines, muted.Render("Ramp: last 7 days vs the 4 weeks before. Load ratio: ATL/CTL the day before."))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, append([]string{title}, lines...)...)
+}
+
+func (m InjuriesModel) renderCTLChart() string {
+	title := cardTitleStyle.Render(fmt.Sprintf("Fitness (CTL, %d weeks)", len(m.log.WeeklyCTL)))
+	return m.renderOverlayChart(title, m.log.WeeklyCTL, "CTL")
+}
+
+func (m InjuriesModel) renderDistanceChart() string {
+	title := cardTitleStyle.Render(fmt.Sprintf("Weekly Distance (%d weeks)", len(m.log.WeeklyMiles)))
+
+	// WeeklyMiles from the service is in miles
+	data := make([]float64, len(m.log.WeeklyMiles))
+	for i, mi := range m.log.WeeklyMiles {
+		data[i] = m.units.FromMiles(mi)
+	}
+	return m.renderOverlayChart(title, data, m.units.DistanceLabelLong()+"/week")
+}
+
+// renderOverlayChart plots weekly values with injured weeks marked by a line
+// along the top of the chart in the theme's alert color
+func (m InjuriesModel) renderOverlayChart(title string, data []float64, label string) string {
+	top := 1.0
+	for _, v := range data {
+		top = math.Max(top, v)
+	}
+
+	injured := make([]float64, len(data))
+	anyInjured := false
+	for i := range injured {
+		injured[i] = math.NaN()
+		if i < len(m.log.InjuredWeeks) && m.log.InjuredWeeks[i] {
+			injured[i] = top
+			anyInjured = true
+		}
+	}
+
+	series := [][]float64{data}
+	colors := []asciigraph.AnsiColor{asciigraph.Default}
+	legends := []string{label}
+	if anyInjured {
+		series = append(series, injured)
+		colors = append(colors, activeTheme.ChartAlert)
+		legends = append(legends, "injured")
+	}
+
+	graph := asciigraph.PlotMany(series,
+		asciigraph.Height(6),
+		asciigraph.Width(40),
+		asciigraph.Precision(0),
+		asciigraph.SeriesColors(colors...),
+		asciigraph.SeriesLegends(legends...),
+	)
+	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, graph))
+}
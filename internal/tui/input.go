@@ -0,0 +1,35 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// textInput is a minimal single-line text field used by search and edit prompts
+type textInput struct {
+	value string
+}
+
+// update applies a key press, reporting whether enter submitted the value or
+// esc cancelled the prompt
+func (t *textInput) update(msg tea.KeyMsg) (submitted, cancelled bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		return true, false
+	case tea.KeyEsc:
+		return false, true
+	case tea.KeyBackspace:
+		if runes := []rune(t.value); len(runes) > 0 {
+			t.value = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		t.value = ""
+	case tea.KeySpace:
+		t.value += " "
+	case tea.KeyRunes:
+		t.value += string(msg.Runes)
+	}
+	return false, false
+}
+
+// view renders the value followed by a cursor
+func (t textInput) view() string {
+	return t.value + "_"
+}
@@ -0,0 +1,87 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// Grid geometry. Cards flow into 1, 2 or 3 columns depending on how many of
+// the narrowest useful column fit in the terminal.
+const (
+	cardGap        = 2  // columns between cards side by side
+	cardBorder     = 2  // left and right border of cardStyle
+	cardPadding    = 4  // left and right padding of cardStyle
+	chartAxisWidth = 10 // y-axis labels asciigraph draws left of the plot
+	minColumnWidth = 48 // narrowest column a chart card is readable in
+	maxColumns     = 3
+	minChartWidth  = 20
+	maxChartWidth  = 120
+
+	// Sizes used before the first tea.WindowSizeMsg arrives
+	defaultColumns    = 2
+	defaultChartWidth = 35
+)
+
+// gridLayout sizes cards and charts to the terminal width
+type gridLayout struct {
+	width int // terminal width, 0 until known
+	cols  int
+}
+
+// newGridLayout picks the column count for a terminal width
+func newGridLayout(width int) gridLayout {
+	if width <= 0 {
+		return gridLayout{cols: defaultColumns}
+	}
+	cols := (width + cardGap) / (minColumnWidth + cardGap)
+	cols = max(1, min(cols, maxColumns))
+	return gridLayout{width: width, cols: cols}
+}
+
+// columnWidth is the outer width of one column
+func (g gridLayout) columnWidth() int {
+	return (g.width - (g.cols-1)*cardGap) / g.cols
+}
+
+// cardWidth is the lipgloss Width for a card filling one column, which
+// counts padding but not the border. Before the terminal size is known it
+// is the card's natural width, where 0 sizes the card to its content.
+func (g gridLayout) cardWidth(natural int) int {
+	if g.width <= 0 {
+		return natural
+	}
+	return max(g.columnWidth()-cardBorder, minChartWidth)
+}
+
+// chartWidth is the plot width for a chart inside a card filling one column
+func (g gridLayout) chartWidth() int {
+	if g.width <= 0 {
+		return defaultChartWidth
+	}
+	w := g.columnWidth() - cardBorder - cardPadding - chartAxisWidth
+	return max(minChartWidth, min(w, maxChartWidth))
+}
+
+// plotWidth is the width for a chart drawn without a card, filling one
+// column. Before the terminal size is known it is natural.
+func (g gridLayout) plotWidth(natural int) int {
+	if g.width <= 0 {
+		return natural
+	}
+	w := g.columnWidth() - cardGap - chartAxisWidth
+	return max(minChartWidth, min(w, maxChartWidth))
+}
+
+// rows lays blocks out left to right, g.cols to a row
+func (g gridLayout) rows(blocks []string) []string {
+	var rows []string
+	for start := 0; start < len(blocks); start += g.cols {
+		end := min(start+g.cols, len(blocks))
+		var row []string
+		for i, b := range blocks[start:end] {
+			if i > 0 {
+				row = append(row, "  ")
+			}
+			row = append(row, b)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return rows
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"runner/internal/logging"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logsShown is how many of the newest log entries the screen loads
+const logsShown = 500
+
+// LogsModel is the debug log viewer screen model
+type LogsModel struct {
+	logPath      string
+	entries      []logging.Entry
+	problemsOnly bool
+	viewport     viewport.Model
+	loading      bool
+	err          error
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewLogsModel creates a new log viewer for the log file at logPath
+func NewLogsModel(logPath string, width, height int) LogsModel {
+	m := LogsModel{
+		logPath: logPath,
+		loading: true,
+		width:   width,
+		height:  height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the log viewer
+func (m LogsModel) Init() tea.Cmd {
+	return m.loadLogs
+}
+
+type logsLoadedMsg struct {
+	entries []logging.Entry
+	err     error
+}
+
+func (m LogsModel) loadLogs() tea.Msg {
+	entries, err := logging.ReadRecent(m.logPath, logsShown)
+	return logsLoadedMsg{entries: entries, err: err}
+}
+
+// Update handles messages
+func (m LogsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logsLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.entries = msg.entries
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+			m.viewport.GotoBottom()
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if m.entries != nil {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			return m, m.loadLogs
+		case "w":
+			m.problemsOnly = !m.problemsOnly
+			m.viewport.SetContent(m.renderContent())
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the log viewer
+func (m LogsModel) View() string {
+	if m.loading {
+		return "\n  Loading logs..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	filter := "w: warnings and errors only"
+	if m.problemsOnly {
+		filter = "w: show all"
+	}
+	footer := statusStyle.Render(fmt.Sprintf("  %s  j/k or arrows: scroll  %s  r: refresh", m.logPath, filter))
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+func (m LogsModel) renderContent() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var lines []string
+	for _, e := range m.entries {
+		if m.problemsOnly && e.Level != "WARN" && e.Level != "ERROR" {
+			continue
+		}
+
+		level := fmt.Sprintf("%-5s", e.Level)
+		switch e.Level {
+		case "ERROR":
+			level = errorStyle.Render(level)
+		case "WARN":
+			level = warningStyle.Render(level)
+		case "DEBUG":
+			level = muted.Render(level)
+		}
+
+		line := fmt.Sprintf("  %s %s %s", muted.Render(e.Time.Local().Format("Jan 02 15:04:05")), level, e.Msg)
+		if len(e.Attrs) > 0 {
+			line += " " + muted.Render(strings.Join(e.Attrs, " "))
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return muted.Render("\n  No log entries yet.")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+
+	"runner/internal/service"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PaceAtHRModel is the aerobic speed screen model: pace at fixed heart rates
+// by month, one chart per anchor. Each horizon is loaded when shown.
+type PaceAtHRModel struct {
+	queryService QueryProvider
+	units        Units
+	data         *service.PaceAtHRData
+	horizon      int // index into service.TrendHorizons
+	viewport     viewport.Model
+	loading      bool
+	err          error
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewPaceAtHRModel creates a new pace-at-HR model
+func NewPaceAtHRModel(qs QueryProvider, units Units, width, height int) PaceAtHRModel {
+	m := PaceAtHRModel{
+		queryService: qs,
+		units:        units,
+		horizon:      trendsDefaultHorizon,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the pace-at-HR screen
+func (m PaceAtHRModel) Init() tea.Cmd {
+	return m.loadPaceAtHR
+}
+
+type paceAtHRLoadedMsg struct {
+	data *service.PaceAtHRData
+	err  error
+}
+
+func (m PaceAtHRModel) loadPaceAtHR() tea.Msg {
+	data, err := m.queryService.GetPaceAtHR(service.TrendHorizons[m.horizon])
+	return paceAtHRLoadedMsg{data: data, err: err}
+}
+
+// Update handles messages
+func (m PaceAtHRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case paceAtHRLoadedMsg:
+		// A reply for a horizon no longer shown is dropped
+		if msg.data != nil && len(msg.data.Months) != service.TrendHorizons[m.horizon] {
+			return m, nil
+		}
+		m.loading = false
+		m.data, m.err = msg.data, msg.err
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "[":
+			if m.horizon > 0 {
+				m.horizon--
+				m.loading = true
+				return m, m.loadPaceAtHR
+			}
+			return m, nil
+		case "]":
+			if m.horizon < len(service.TrendHorizons)-1 {
+				m.horizon++
+				m.loading = true
+				return m, m.loadPaceAtHR
+			}
+			return m, nil
+		case "r":
+			m.loading = true
+			return m, m.loadPaceAtHR
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the pace-at-HR screen
+func (m PaceAtHRModel) View() string {
+	if m.loading {
+		return "\n  Loading pace at HR..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	footer := statusStyle.Render("  [/]: shorter/longer  j/k or arrows: scroll  r: refresh")
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+func (m PaceAtHRModel) renderContent() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var sections []string
+	sections = append(sections, "")
+	sections = append(sections, cardTitleStyle.Render("Pace at Heart Rate"))
+	sections = append(sections, m.renderTabs())
+	sections = append(sections, "")
+
+	runs := 0
+	for _, a := range m.data.Anchors {
+		runs += a.Runs
+	}
+	if runs == 0 {
+		sections = append(sections, muted.Render(fmt.Sprintf(
+			"  No runs held an anchor heart rate in the last %d months. Sync activities with HR to see your aerobic speed.",
+			len(m.data.Months))))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	sections = append(sections, m.renderTable(), "")
+
+	labels := make([]string, len(m.data.Months))
+	for i, mo := range m.data.Months {
+		labels[i] = mo.Format("Jan 06")
+	}
+	var blocks []string
+	for _, a := range m.data.Anchors {
+		blocks = append(blocks, m.renderAnchorChart(a, labels))
+	}
+	sections = append(sections, newGridLayout(m.width).rows(blocks)...)
+	sections = append(sections, "", muted.Render(
+		"  Faster pace at the same heart rate means a stronger aerobic engine. Set the anchors in settings (,), then recompute metrics."))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderTabs lists the horizons with the one shown highlighted
+func (m PaceAtHRModel) renderTabs() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	tabs := " "
+	for i, n := range service.TrendHorizons {
+		label := fmt.Sprintf(" %d months ", n)
+		if i == m.horizon {
+			tabs += " " + tableSelectedStyle.Render(label)
+		} else {
+			tabs += " " + muted.Render(label)
+		}
+	}
+	return tabs
+}
+
+// renderTable lists each anchor's first and latest monthly pace and the
+// speed gained between them
+func (m PaceAtHRModel) renderTable() string {
+	header := fmt.Sprintf("  %-10s %8s %10s %10s %8s %6s", "Anchor", "HR", "First", "Latest", "Change", "Runs")
+	rows := []string{tableHeaderStyle.Render(header)}
+
+	for _, a := range m.data.Anchors {
+		var first, latest float64
+		for _, pace := range a.Monthly {
+			if pace > 0 {
+				if first == 0 {
+					first = pace
+				}
+				latest = pace
+			}
+		}
+
+		change := "-"
+		style := tableRowStyle
+		if a.HasImprovement {
+			change = fmt.Sprintf("%+.1f%%", a.Improvement)
+			if a.Improvement > 0 {
+				style = successStyle
+			} else {
+				style = warningStyle
+			}
+		}
+		rows = append(rows, style.Render(fmt.Sprintf("  %-10s %8s %10s %10s %8s %6d",
+			a.Label, fmt.Sprintf("%.0f", a.HR), m.units.FormatPacePerMile(first), m.units.FormatPacePerMile(latest), change, a.Runs)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderAnchorChart draws one anchor's monthly pace, with months without
+// runs as gaps
+func (m PaceAtHRModel) renderAnchorChart(a service.PaceAnchorTrend, labels []string) string {
+	minPerMile := make([]float64, len(a.Monthly))
+	for i, pace := range a.Monthly {
+		minPerMile[i] = pace / 60
+	}
+	data := m.units.ConvertPaceData(minPerMile)
+	for i := range data {
+		data[i] = orNaN(data[i])
+	}
+
+	g := newGridLayout(m.width)
+	chart := lineChart{
+		Width:     g.chartWidth(),
+		Height:    trendsChartHeight,
+		Precision: 2,
+		XLabels:   labels,
+		Caption:   m.units.PaceLabel() + " (lower = faster)",
+	}
+	title := "Pace @ " + a.Label
+	if bpm := fmt.Sprintf("%.0f bpm", a.HR); a.Label != bpm {
+		title += " (" + bpm + ")"
+	}
+	return cardStyle.Width(g.cardWidth(0)).Render(lipgloss.JoinVertical(lipgloss.Left,
+		cardTitleStyle.Render(title), chart.render(data)))
+}
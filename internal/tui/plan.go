@@ -0,0 +1,356 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"runner/internal/analysis"
+	"runner/internal/service"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// planForm is the new plan form's answers, filled in one step at a time
+type planForm struct {
+	raceDate     time.Time
+	peak         float64 // meters
+	taperWeeks   int
+	cutbackEvery int
+}
+
+// PlanModel is the training plan screen model
+type PlanModel struct {
+	queryService QueryProvider
+	units        Units
+	view         *service.TrainingPlanView
+	cursor       int
+	top          int // first visible row
+	loading      bool
+	err          error
+	width        int
+	height       int
+
+	// New plan form
+	editing bool
+	step    int
+	input   textInput
+	pending planForm
+	editErr error
+}
+
+// NewPlanModel creates a new training plan model
+func NewPlanModel(qs QueryProvider, units Units, width, height int) PlanModel {
+	return PlanModel{
+		queryService: qs,
+		units:        units,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init initializes the training plan screen
+func (m PlanModel) Init() tea.Cmd {
+	return m.loadPlan
+}
+
+type planLoadedMsg struct {
+	view *service.TrainingPlanView
+	err  error
+}
+
+type planSavedMsg struct {
+	err error
+}
+
+func (m PlanModel) loadPlan() tea.Msg {
+	view, err := m.queryService.GetTrainingPlan()
+	return planLoadedMsg{view: view, err: err}
+}
+
+// prompts are the steps of the new plan form, in order
+func (m PlanModel) prompts() []string {
+	return []string{
+		"Race date (YYYY-MM-DD)",
+		fmt.Sprintf("Peak weekly distance (%s)", m.units.DistanceLabel()),
+		fmt.Sprintf("Taper weeks, race week included (blank for %d)", service.DefaultTaperWeeks),
+		fmt.Sprintf("Cutback every N weeks (blank for %d, 0 for none)", service.DefaultCutbackEvery),
+	}
+}
+
+// visibleRows is how many weeks fit on screen above the chart
+func (m PlanModel) visibleRows() int {
+	if rows := m.height - 26; rows > 4 {
+		return rows
+	}
+	return 4
+}
+
+func (m PlanModel) weeks() []service.PlanWeekDisplay {
+	if m.view == nil {
+		return nil
+	}
+	return m.view.Weeks
+}
+
+// Update handles messages
+func (m PlanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case planLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.view = msg.view
+		// Start on this week
+		if m.view != nil {
+			m.cursor = min(m.view.Current, max(len(m.view.Weeks)-1, 0))
+		}
+		m.scrollToCursor()
+
+	case planSavedMsg:
+		m.editErr = msg.err
+		return m, m.loadPlan
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateForm(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.scrollToCursor()
+			}
+		case "down", "j":
+			if m.cursor < len(m.weeks())-1 {
+				m.cursor++
+				m.scrollToCursor()
+			}
+		case "a":
+			m.editing = true
+			m.step = 0
+			m.input = textInput{}
+			m.pending = planForm{}
+			m.editErr = nil
+		case "D":
+			if m.view != nil {
+				qs := m.queryService
+				return m, func() tea.Msg {
+					return planSavedMsg{err: qs.DeleteTrainingPlan()}
+				}
+			}
+		case "r":
+			m.loading = true
+			return m, m.loadPlan
+		}
+	}
+	return m, nil
+}
+
+// updateForm handles key presses while the new plan form is open. Each
+// answer is checked as it's entered so mistakes can be fixed in place.
+func (m PlanModel) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case cancelled:
+		m.editing = false
+		m.editErr = nil
+		return m, nil
+	case !submitted:
+		return m, nil
+	}
+
+	value := m.input.value
+	var err error
+	switch m.step {
+	case 0:
+		m.pending.raceDate, err = service.ParsePlanRaceDate(value, time.Now())
+	case 1:
+		m.pending.peak, err = service.ParsePlanDistance(value, m.units.IsMiles())
+	case 2:
+		m.pending.taperWeeks, err = service.ParsePlanTaperWeeks(value)
+	case 3:
+		m.pending.cutbackEvery, err = service.ParsePlanCutbackEvery(value)
+	}
+	if err != nil {
+		m.editErr = err
+		return m, nil
+	}
+
+	m.editErr = nil
+	m.input = textInput{}
+	m.step++
+	if m.step < len(m.prompts()) {
+		return m, nil
+	}
+
+	m.editing = false
+	qs, form := m.queryService, m.pending
+	return m, func() tea.Msg {
+		_, err := qs.CreateTrainingPlan(form.raceDate, form.peak, form.taperWeeks, form.cutbackEvery)
+		return planSavedMsg{err: err}
+	}
+}
+
+// scrollToCursor keeps the cursor row on screen
+func (m *PlanModel) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+}
+
+// View renders the training plan screen
+func (m PlanModel) View() string {
+	if m.loading {
+		return "\n  Loading training plan..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var sections []string
+	if m.view == nil {
+		sections = append(sections,
+			cardTitleStyle.Render("Training Plan"),
+			muted.Render("  No training plan. Press a to plan a build toward a race."))
+	} else {
+		sections = append(sections, m.renderSummary()...)
+		sections = append(sections, "", tableHeaderStyle.Render(fmt.Sprintf("   %-8s  %-8s  %10s  %10s  %10s  %s",
+			"Week", "Kind", "Target", "Planned", "Run", "Status")))
+		weeks := m.weeks()
+		end := min(m.top+m.visibleRows(), len(weeks))
+		for i := m.top; i < end; i++ {
+			sections = append(sections, m.renderRow(i))
+		}
+		sections = append(sections, "", m.renderChart())
+	}
+
+	var footer string
+	if m.editing {
+		prompts := m.prompts()
+		footer = fmt.Sprintf("  %s: %s", prompts[m.step], m.input.view()) +
+			statusStyle.Render(fmt.Sprintf("  (%d/%d)  enter: next  esc: cancel", m.step+1, len(prompts)))
+	} else {
+		footer = statusStyle.Render("  j/k: navigate  a: new plan  D: delete plan  r: refresh")
+	}
+	if m.editErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error: %v", m.editErr)), footer)
+	}
+	sections = append(sections, "", footer)
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderSummary describes the plan and how this week is going
+func (m PlanModel) renderSummary() []string {
+	v := m.view
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	cutbacks := "no cutback weeks"
+	if v.Plan.CutbackEvery > 0 {
+		cutbacks = fmt.Sprintf("cutback every %d weeks", v.Plan.CutbackEvery)
+	}
+
+	lines := []string{
+		cardTitleStyle.Render(fmt.Sprintf("Training Plan: race on %s", v.Plan.RaceDate.Format("Mon Jan 02, 2006"))),
+		muted.Render(fmt.Sprintf("  %d weeks from %s a week to a %s peak, %d week taper, %s",
+			len(v.Weeks), m.units.FormatDistance(v.Plan.BaseDistance), m.units.FormatDistance(v.Plan.PeakDistance),
+			v.Plan.TaperWeeks, cutbacks)),
+	}
+
+	if v.Done() {
+		lines = append(lines, muted.Render("  Race week is over. Press a to plan the next build."))
+	} else {
+		w := v.Weeks[v.Current]
+		left := math.Max(w.Target-w.Actual, 0)
+		lines = append(lines, fmt.Sprintf("  Week %d of %d: %s of %s run, %s to go",
+			v.Current+1, len(v.Weeks), m.units.FormatDistance(w.Actual), m.units.FormatDistance(w.Target),
+			m.units.FormatDistance(left)))
+	}
+	if v.Adjusted {
+		missed := "1 missed week"
+		if v.Missed > 1 {
+			missed = fmt.Sprintf("%d missed weeks", v.Missed)
+		}
+		lines = append(lines, warningStyle.Render(fmt.Sprintf("  %s: the weeks after each were rebuilt from the distance you ran.", missed)))
+	}
+	return lines
+}
+
+func (m PlanModel) renderRow(i int) string {
+	w := m.weeks()[i]
+
+	cursor := "  "
+	if i == m.cursor {
+		cursor = "> "
+	}
+
+	run, status := "-", ""
+	switch w.Status {
+	case service.PlanCurrent:
+		run, status = m.units.FormatDistance(w.Actual), "this week"
+	case service.PlanHit:
+		run, status = m.units.FormatDistance(w.Actual), "✓"
+	case service.PlanMissed:
+		run, status = m.units.FormatDistance(w.Actual), "missed"
+	case service.PlanOver:
+		run, status = m.units.FormatDistance(w.Actual), "over"
+	}
+	planned := ""
+	if math.Abs(w.Planned-w.Target) >= 1 {
+		planned = m.units.FormatDistance(w.Planned)
+	}
+
+	row := fmt.Sprintf("%s%-8s  %-8s  %10s  %10s  %10s  %s",
+		cursor, w.Label, w.Kind, m.units.FormatDistance(w.Target), planned, run, status)
+
+	switch {
+	case i == m.cursor:
+		return tableSelectedStyle.Render(row)
+	case w.Status == service.PlanMissed || w.Status == service.PlanOver:
+		return tableRowStyle.Foreground(warningColor).Render(row)
+	case w.Status == service.PlanHit:
+		return tableRowStyle.Foreground(secondaryColor).Render(row)
+	case w.Kind == analysis.PlanRace:
+		return tableRowStyle.Foreground(accentColor).Render(row)
+	}
+	return tableRowStyle.Render(row)
+}
+
+// renderChart plots each week's target with the distance run so far
+func (m PlanModel) renderChart() string {
+	weeks := m.weeks()
+	targets := make([]float64, len(weeks))
+	actual := make([]float64, len(weeks))
+	for i, w := range weeks {
+		targets[i] = m.units.FromMiles(w.Target / metersPerMile)
+		actual[i] = math.NaN()
+		if w.Status != service.PlanUpcoming {
+			actual[i] = m.units.FromMiles(w.Actual / metersPerMile)
+		}
+	}
+
+	title := cardTitleStyle.Render(fmt.Sprintf("Weekly Distance (%s)", m.units.DistanceLabelLong()))
+	graph := asciigraph.PlotMany([][]float64{targets, actual},
+		asciigraph.Height(8),
+		asciigraph.Width(max(len(weeks)*3, 30)),
+		asciigraph.Precision(0),
+		asciigraph.SeriesColors(asciigraph.Default, activeTheme.ChartHighlight),
+		asciigraph.SeriesLegends("target", "run"),
+	)
+	return cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, graph))
+}
@@ -13,7 +13,7 @@ import (
 
 // PredictionsModel is the race predictions screen model
 type PredictionsModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
 	units        Units
 	data         *service.PredictionsData
 	viewport     viewport.Model
@@ -25,7 +25,7 @@ type PredictionsModel struct {
 }
 
 // NewPredictionsModel creates a new predictions model
-func NewPredictionsModel(qs *service.QueryService, units Units, width, height int) PredictionsModel {
+func NewPredictionsModel(qs QueryProvider, units Units, width, height int) PredictionsModel {
 	m := PredictionsModel{
 		queryService: qs,
 		units:        units,
@@ -133,6 +133,9 @@ func (m PredictionsModel) renderContent() string {
 	// Predictions table
 	sections = append(sections, m.renderPredictionsTable())
 
+	// VDOT vs Riegel vs recent race
+	sections = append(sections, m.renderEnsembleTable())
+
 	// About section
 	sections = append(sections, m.renderAboutSection())
 
@@ -209,11 +212,11 @@ func (m PredictionsModel) formatPredictionRow(pred service.PredictionDisplay) st
 	var confStyle lipgloss.Style
 	switch pred.Confidence {
 	case "High":
-		confStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981")) // green
+		confStyle = lipgloss.NewStyle().Foreground(secondaryColor)
 	case "Medium":
-		confStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")) // amber
+		confStyle = lipgloss.NewStyle().Foreground(warningColor)
 	case "Low":
-		confStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")) // red
+		confStyle = lipgloss.NewStyle().Foreground(errorColor)
 	default:
 		confStyle = lipgloss.NewStyle().Foreground(mutedColor)
 	}
@@ -221,11 +224,53 @@ func (m PredictionsModel) formatPredictionRow(pred service.PredictionDisplay) st
 	return fmt.Sprintf("  %-15s  %12s  %10s  %s",
 		pred.TargetLabel,
 		pred.PredictedTime,
-		pred.PredictedPace+"/mi",
+		m.units.FormatPaceWithUnit(pred.PredictedSeconds, pred.TargetMeters),
 		confStyle.Render(pred.Confidence),
 	)
 }
 
+func (m PredictionsModel) renderEnsembleTable() string {
+	var lines []string
+
+	divider := strings.Repeat("─", 55)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(secondaryColor)
+	lines = append(lines, headerStyle.Render(fmt.Sprintf("── Model Comparison %s", divider[:55-20])))
+
+	tableHeaderStyle := lipgloss.NewStyle().Foreground(primaryColor)
+	header := fmt.Sprintf("  %-15s  %9s  %9s  %9s  %9s  %s", "Distance", "VDOT", "Riegel", "Recent", "Ensemble", "Range")
+	lines = append(lines, tableHeaderStyle.Render(header))
+
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	for _, pred := range m.data.Predictions {
+		band := pred.Range
+		if band == "" {
+			band = mutedStyle.Render("-")
+		}
+		lines = append(lines, fmt.Sprintf("  %-15s  %9s  %9s  %9s  %9s  %s",
+			pred.TargetLabel,
+			pred.PredictedTime,
+			pred.RiegelTime,
+			pred.RecentTime,
+			pred.EnsembleTime,
+			band,
+		))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Riegel: T2 = T1 x (D2/D1)^%.2f from the same PR.", m.data.RiegelExponent)))
+	if m.data.HasRecentRace {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Recent: Riegel from your best race-distance run in the last %d days,",
+			service.RecentRaceDays)))
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  %s (%s).", m.data.RecentRace, m.data.RecentRaceDate)))
+	} else {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  Recent: no race-distance run in the last %d days.", service.RecentRaceDays)))
+	}
+	lines = append(lines, mutedStyle.Render("  Ensemble averages the models; a wide range means they disagree."))
+	lines = append(lines, "")
+
+	return strings.Join(lines, "\n")
+}
+
 func (m PredictionsModel) renderAboutSection() string {
 	var lines []string
 
@@ -241,12 +286,12 @@ func (m PredictionsModel) renderAboutSection() string {
 	lines = append(lines, "")
 
 	// Confidence legend
-	legendStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF"))
+	legendStyle := lipgloss.NewStyle().Foreground(subtleColor)
 	lines = append(lines, legendStyle.Render("  Confidence Levels:"))
 
-	highStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981"))
-	medStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B"))
-	lowStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	highStyle := lipgloss.NewStyle().Foreground(secondaryColor)
+	medStyle := lipgloss.NewStyle().Foreground(warningColor)
+	lowStyle := lipgloss.NewStyle().Foreground(errorColor)
 
 	lines = append(lines, fmt.Sprintf("    %s - Recent PR, minimal extrapolation", highStyle.Render("High")))
 	lines = append(lines, fmt.Sprintf("    %s - Moderate extrapolation or older PR", medStyle.Render("Medium")))
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// QueryProvider is the read and annotate side of the services the screens
+// use. *service.QueryService implements it; tests substitute fakes.
+type QueryProvider interface {
+	// Dashboard and summaries
+	GetDashboardData() (*service.DashboardData, error)
+	GetPeriodStats(periodType string, numPeriods int) ([]service.PeriodStats, error)
+	GetWeeklyComparisons() ([]service.ComparisonStats, error)
+	GetMonthlyComparisons() ([]service.ComparisonStats, error)
+	GetCustomComparison(current, previous service.ComparisonRange) (service.ComparisonStats, error)
+	GetYearInReview() ([]service.YearReview, error)
+	GetTrainingDistribution(numWeeks int) (*service.TrainingDistribution, error)
+	GetCriticalPace() (*service.CriticalPaceData, error)
+	GetTrends(months int) ([]service.MonthTrend, error)
+	GetMileageRamp(months int) (*service.RampData, error)
+	GetDurability(months int) (*service.DurabilityData, error)
+	GetPaceAtHR(months int) (*service.PaceAtHRData, error)
+	GetFeelVsForm(months int) (*service.FeelVsFormData, error)
+	SaveWellness(entries ...store.Wellness) error
+
+	// Activities
+	CountSearchActivities(filter store.ActivityFilter) (int, error)
+	SearchActivitiesAfter(filter store.ActivityFilter, order store.ActivitySort, last *store.Activity, pos, limit int) ([]service.ActivityWithMetrics, error)
+	SearchActivitiesBefore(filter store.ActivityFilter, order store.ActivitySort, first store.Activity, pos, limit int) ([]service.ActivityWithMetrics, error)
+	GetActivityDetailByID(id int64) (*service.ActivityDetail, error)
+	InvalidateActivityDetails(ids ...int64)
+	GetActivityPRs(activityID int64) ([]service.PersonalRecordDisplay, error)
+	GetStreamPage(activityID int64, offset, limit int) (*service.StreamPage, error)
+	GetStreamRange(activityID int64, from, to int) ([]store.StreamPoint, error)
+	SetActivityNote(activityID int64, note string) error
+	SetActivityTags(activityID int64, tags []string) error
+	SetActivityTemperature(activityID int64, tempC *float64) error
+	SetActivityRPE(activityID int64, rpe *store.ActivityRPE) error
+	SetActivityExcluded(activityID int64, excluded bool) error
+	VerifyActivityEfforts(activityID int64) error
+
+	// Records and predictions
+	GetPersonalRecords() (*service.PRsData, error)
+	GetPRProgressions() ([]service.PRProgression, error)
+	GetRacePredictions() (*service.PredictionsData, error)
+	GetFastestSegments() ([]service.SegmentBoard, error)
+
+	// Races
+	GetRaces() ([]service.RaceDisplay, error)
+	SetRacePlacing(activityID int64, placing string) error
+	DismissRace(activityID int64) error
+
+	// Injuries
+	GetInjuryLog() (*service.InjuryLog, error)
+	AddInjury(injury store.Injury) (int64, error)
+	ResolveInjury(id int64, resolvedOn *time.Time) error
+	DeleteInjury(id int64) error
+
+	// Training plan
+	GetTrainingPlan() (*service.TrainingPlanView, error)
+	CreateTrainingPlan(raceDate time.Time, peak float64, taperWeeks, cutbackEvery int) (*store.TrainingPlan, error)
+	DeleteTrainingPlan() error
+
+	// Benchmarks
+	GetBenchmarks() ([]service.BenchmarkDisplay, error)
+	BenchmarkByName(name string) (*store.Benchmark, error)
+	AddBenchmark(name string, kind store.BenchmarkKind, referenceID int64) (int64, error)
+	DeleteBenchmark(id int64) error
+	SetBenchmarkAttempt(benchmarkID, activityID int64, included bool) error
+
+	// Settings
+	SetAthleteConfig(athleteCfg config.AthleteConfig)
+	SetRiegelExponent(exponent float64)
+	SetRestDayWarningDays(days int)
+	SetRampThreshold(pct float64)
+	SetWeekNumbers(on bool)
+	SetBucketing(analysisCfg config.AnalysisConfig) error
+	SetLoadModel(model string) error
+}
+
+// SyncRunner is the sync side of the services the screens use.
+// *service.SyncService implements it; tests substitute fakes.
+type SyncRunner interface {
+	Preview(ctx context.Context) (*service.SyncPreview, error)
+	Sync(ctx context.Context, opts service.SyncOptions, progress chan<- service.SyncProgress) (*service.SyncResult, error)
+	RetryFailed(ctx context.Context, failures []service.SyncFailure) (*service.SyncResult, error)
+	ResyncActivity(ctx context.Context, activityID int64) (*service.SyncResult, error)
+	TrimActivity(ctx context.Context, activityID int64, endOffset int) (*service.SyncResult, error)
+	CorrectDistance(ctx context.Context, activityID int64, fix service.DistanceFix) (*service.SyncResult, error)
+
+	// Pausing and rate limits
+	Pause()
+	Resume()
+	Paused() bool
+	RequestCount() int
+	RecentRequests() []strava.RequestRecord
+	RateLimits() (shortLimit, dailyLimit int)
+	RateLimitStatus() (shortRemaining, dailyRemaining int)
+	RateLimitWait() time.Time
+
+	// A sync another process is running, or nil
+	SyncElsewhere() (*store.SyncLock, error)
+
+	// Settings
+	SetAthleteConfig(athleteCfg config.AthleteConfig)
+	SetAnalysisConfig(analysisCfg config.AnalysisConfig)
+	SetPrivacyConfig(privacyCfg config.PrivacyConfig)
+	SetStorageConfig(storageCfg config.StorageConfig)
+}
+
+var (
+	_ QueryProvider = (*service.QueryService)(nil)
+	_ SyncRunner    = (*service.SyncService)(nil)
+)
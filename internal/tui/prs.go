@@ -5,27 +5,34 @@ import (
 	"strings"
 
 	"runner/internal/service"
+	"runner/internal/store"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
 )
 
 // PRsModel is the personal records screen model
 type PRsModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
 	units        Units
 	data         *service.PRsData
+	progressions []service.PRProgression
 	viewport     viewport.Model
 	loading      bool
 	err          error
 	width        int
 	height       int
 	ready        bool
+
+	// Progression view; selected indexes progressions
+	showHistory bool
+	selected    int
 }
 
 // NewPRsModel creates a new PRs model
-func NewPRsModel(qs *service.QueryService, units Units, width, height int) PRsModel {
+func NewPRsModel(qs QueryProvider, units Units, width, height int) PRsModel {
 	m := PRsModel{
 		queryService: qs,
 		units:        units,
@@ -48,13 +55,18 @@ func (m PRsModel) Init() tea.Cmd {
 }
 
 type prsLoadedMsg struct {
-	data *service.PRsData
-	err  error
+	data         *service.PRsData
+	progressions []service.PRProgression
+	err          error
 }
 
 func (m PRsModel) loadPRs() tea.Msg {
 	data, err := m.queryService.GetPersonalRecords()
-	return prsLoadedMsg{data: data, err: err}
+	if err != nil {
+		return prsLoadedMsg{err: err}
+	}
+	progressions, err := m.queryService.GetPRProgressions()
+	return prsLoadedMsg{data: data, progressions: progressions, err: err}
 }
 
 // Update handles messages
@@ -64,6 +76,10 @@ func (m PRsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		m.err = msg.err
 		m.data = msg.data
+		m.progressions = msg.progressions
+		if m.selected >= len(m.progressions) {
+			m.selected = 0
+		}
 		if m.ready {
 			m.viewport.SetContent(m.renderContent())
 		}
@@ -87,6 +103,22 @@ func (m PRsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			m.loading = true
 			return m, m.loadPRs
+		case "h":
+			m.showHistory = !m.showHistory
+			m.refreshContent()
+			return m, nil
+		case "]":
+			if m.showHistory && len(m.progressions) > 0 {
+				m.selected = (m.selected + 1) % len(m.progressions)
+				m.refreshContent()
+			}
+			return m, nil
+		case "[":
+			if m.showHistory && len(m.progressions) > 0 {
+				m.selected = (m.selected + len(m.progressions) - 1) % len(m.progressions)
+				m.refreshContent()
+			}
+			return m, nil
 		}
 	}
 
@@ -110,16 +142,31 @@ func (m PRsModel) View() string {
 		return "\n  Initializing..."
 	}
 
-	footer := statusStyle.Render("  j/k or arrows: scroll  r: refresh")
+	footer := statusStyle.Render("  j/k or arrows: scroll  h: history  r: refresh")
+	if m.showHistory {
+		footer = statusStyle.Render("  j/k or arrows: scroll  [/]: category  h: current records  r: refresh")
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
 }
 
+// refreshContent re-renders the viewport after a view change
+func (m *PRsModel) refreshContent() {
+	if m.ready && m.data != nil {
+		m.viewport.SetContent(m.renderContent())
+		m.viewport.GotoTop()
+	}
+}
+
 func (m PRsModel) renderContent() string {
 	if m.data == nil {
 		return "No personal records yet. Run a sync to analyze your activities."
 	}
 
+	if m.showHistory {
+		return m.renderHistory()
+	}
+
 	var sections []string
 
 	// Title
@@ -137,6 +184,11 @@ func (m PRsModel) renderContent() string {
 		sections = append(sections, m.renderBestEfforts())
 	}
 
+	// Fastest splits section
+	if len(m.fastestSplits()) > 0 {
+		sections = append(sections, m.renderFastestSplits())
+	}
+
 	// Other Achievements section
 	if len(m.data.OtherPRs) > 0 {
 		sections = append(sections, m.renderOtherAchievements())
@@ -169,8 +221,49 @@ func (m PRsModel) renderBestEfforts() string {
 	lines = append(lines, m.sectionHeader("Best Efforts"))
 	lines = append(lines, m.effortTableHeader())
 
+	unverified := false
 	for _, pr := range m.data.BestEffortPRs {
-		lines = append(lines, m.formatEffortRow(pr))
+		row := m.formatEffortRow(pr)
+		if pr.Unverified {
+			row += warningStyle.Render("  ⚠ unverified")
+			unverified = true
+		}
+		lines = append(lines, row)
+	}
+	if unverified {
+		lines = append(lines, lipgloss.NewStyle().Foreground(mutedColor).Render("  ⚠ Doubtful GPS, or far faster than the record before it. Confirm with v on the run's detail screen."))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+// fastestSplits returns the fastest splits in the display unit
+func (m PRsModel) fastestSplits() []service.SplitRecordDisplay {
+	if m.units.IsMiles() {
+		return m.data.FastestMileSplits
+	}
+	return m.data.FastestKmSplits
+}
+
+func (m PRsModel) renderFastestSplits() string {
+	var lines []string
+
+	label, title := "Km", "Fastest Kilometer Splits"
+	if m.units.IsMiles() {
+		label, title = "Mile", "Fastest Mile Splits"
+	}
+	lines = append(lines, m.sectionHeader(title))
+	header := fmt.Sprintf("  %-14s  %10s  %8s  %-12s  %s", "Split", "Time", "Avg HR", "Date", "Activity")
+	lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(header))
+
+	for _, sp := range m.fastestSplits() {
+		activityName := sp.ActivityName
+		if len(activityName) > 30 {
+			activityName = activityName[:27] + "..."
+		}
+		lines = append(lines, fmt.Sprintf("  %-14s  %10s  %8s  %-12s  %s",
+			fmt.Sprintf("%s %d", label, sp.Split), sp.Time, sp.AvgHR, sp.Date, activityName))
 	}
 
 	lines = append(lines, "")
@@ -201,21 +294,22 @@ func (m PRsModel) sectionHeader(title string) string {
 }
 
 func (m PRsModel) tableHeader() string {
-	header := fmt.Sprintf("  %-14s  %10s  %10s  %8s  %s", "Distance", "Time", "Pace", "Avg HR", "Date")
+	header := fmt.Sprintf("  %-14s  %10s  %10s  %8s  %9s  %s", "Distance", "Time", "Pace", "Avg HR", "vs prev", "Date")
 	return lipgloss.NewStyle().Foreground(primaryColor).Render(header)
 }
 
 func (m PRsModel) effortTableHeader() string {
-	header := fmt.Sprintf("  %-14s  %10s  %10s  %s", "Distance", "Time", "Pace", "Source Activity")
+	header := fmt.Sprintf("  %-14s  %10s  %10s  %9s  %s", "Distance", "Time", "Pace", "vs prev", "Source Activity")
 	return lipgloss.NewStyle().Foreground(primaryColor).Render(header)
 }
 
 func (m PRsModel) formatPRRow(pr service.PersonalRecordDisplay) string {
-	return fmt.Sprintf("  %-14s  %10s  %10s  %8s  %s",
+	return fmt.Sprintf("  %-14s  %10s  %10s  %8s  %9s  %s",
 		pr.CategoryLabel,
 		pr.Time,
-		pr.Pace+"/mi",
+		m.units.FormatPacePerMile(pr.PacePerMile),
 		pr.AvgHR,
+		m.previousDelta(pr),
 		pr.Date,
 	)
 }
@@ -225,10 +319,11 @@ func (m PRsModel) formatEffortRow(pr service.PersonalRecordDisplay) string {
 	if len(activityName) > 30 {
 		activityName = activityName[:27] + "..."
 	}
-	return fmt.Sprintf("  %-14s  %10s  %10s  %s",
+	return fmt.Sprintf("  %-14s  %10s  %10s  %9s  %s",
 		pr.CategoryLabel,
 		pr.Time,
-		pr.Pace+"/mi",
+		m.units.FormatPacePerMile(pr.PacePerMile),
+		m.previousDelta(pr),
 		activityName,
 	)
 }
@@ -242,10 +337,129 @@ func (m PRsModel) formatOtherRow(pr service.PersonalRecordDisplay) string {
 	case "highest_elevation":
 		value = fmt.Sprintf("%.0f m", pr.DistanceMeters)
 	case "fastest_pace":
-		value = pr.Pace + "/mi"
+		value = m.units.FormatPacePerMile(pr.PacePerMile)
 	default:
 		value = pr.Time
 	}
 
+	if pr.HasPrevious {
+		return fmt.Sprintf("  %-18s  %s  (%s, %s vs previous)", pr.CategoryLabel, value, pr.Date, m.previousDelta(pr))
+	}
 	return fmt.Sprintf("  %-18s  %s  (%s)", pr.CategoryLabel, value, pr.Date)
 }
+
+// previousDelta formats a record's improvement over the previous one
+func (m PRsModel) previousDelta(pr service.PersonalRecordDisplay) string {
+	if !pr.HasPrevious {
+		return "-"
+	}
+	return formatPRMargin(m.units, pr.Category, pr.Margin, pr.Mode)
+}
+
+// formatPRMargin formats an improvement from the PR history: time saved,
+// distance or elevation added, or pace gained
+func formatPRMargin(u Units, category string, margin float64, mode store.CompareMode) string {
+	switch mode {
+	case store.CompareDistance:
+		if category == "highest_elevation" {
+			return fmt.Sprintf("+%.0f m", margin)
+		}
+		return "+" + u.FormatDistance(margin)
+	case store.ComparePace:
+		// FormatPacePerMile gives "M:SS/unit" for the gain per mile
+		return "-" + u.FormatPacePerMile(margin)
+	default:
+		secs := int(margin + 0.5)
+		return fmt.Sprintf("-%d:%02d", secs/60, secs%60)
+	}
+}
+
+func (m PRsModel) renderHistory() string {
+	var sections []string
+
+	sections = append(sections, "")
+	if len(m.progressions) == 0 {
+		sections = append(sections, cardTitleStyle.Render("Record History"))
+		sections = append(sections, "")
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render("  No record history yet. It fills in on the next sync."))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	p := m.progressions[m.selected]
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("Record History: %s (%d of %d)", p.CategoryLabel, m.selected+1, len(m.progressions))))
+	sections = append(sections, "")
+
+	if len(p.Points) > 1 {
+		sections = append(sections, m.renderProgressionChart(p))
+	}
+
+	var lines []string
+	header := fmt.Sprintf("  %-12s  %10s  %10s  %10s  %s", "Date", "Result", "Pace", "Gain", "Activity")
+	lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(header))
+	for i, pt := range p.Points {
+		gain := "-"
+		if i > 0 {
+			gain = formatPRMargin(m.units, p.Category, pt.Margin, p.Mode)
+		}
+		row := fmt.Sprintf("  %-12s  %10s  %10s  %10s  %s",
+			pt.Date,
+			m.progressionValue(p, pt),
+			m.units.FormatPacePerMile(pt.PacePerMile),
+			gain,
+			truncateName(pt.ActivityName, 30),
+		)
+		// The current record is the last improvement
+		if i == len(p.Points)-1 {
+			row = lipgloss.NewStyle().Foreground(secondaryColor).Bold(true).Render(row)
+		}
+		lines = append(lines, row)
+	}
+	lines = append(lines, "")
+	sections = append(sections, strings.Join(lines, "\n"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// progressionValue formats the result that set a record
+func (m PRsModel) progressionValue(p service.PRProgression, pt service.PRProgressionPoint) string {
+	switch {
+	case p.Category == "highest_elevation":
+		return fmt.Sprintf("%.0f m", pt.DistanceMeters)
+	case p.Mode == store.CompareDistance:
+		return m.units.FormatDistance(pt.DistanceMeters)
+	case p.Mode == store.ComparePace:
+		return m.units.FormatPacePerMile(pt.PacePerMile)
+	default:
+		return pt.Time
+	}
+}
+
+// renderProgressionChart plots each record in the category, oldest first
+func (m PRsModel) renderProgressionChart(p service.PRProgression) string {
+	data := make([]float64, len(p.Points))
+	var caption string
+	for i, pt := range p.Points {
+		switch {
+		case p.Category == "highest_elevation":
+			data[i] = pt.DistanceMeters
+			caption = "meters"
+		case p.Mode == store.CompareDistance:
+			data[i] = m.units.FromMiles(pt.DistanceMeters / metersPerMile)
+			caption = m.units.DistanceLabelLong()
+		case p.Mode == store.ComparePace:
+			data[i] = m.units.ConvertPaceData([]float64{pt.PacePerMile / 60})[0]
+			caption = m.units.PaceLabel()
+		default:
+			data[i] = float64(pt.DurationSeconds) / 60
+			caption = "minutes"
+		}
+	}
+
+	graph := asciigraph.Plot(data,
+		asciigraph.Height(8),
+		asciigraph.Width(50),
+		asciigraph.Precision(1),
+		asciigraph.Caption(caption+", one point per record"),
+	)
+	return cardStyle.Render(graph)
+}
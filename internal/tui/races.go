@@ -0,0 +1,255 @@
+package tui
+
+import (
+	"fmt"
+
+	"runner/internal/service"
+	"runner/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RacesModel is the races screen model
+type RacesModel struct {
+	queryService QueryProvider
+	units        Units
+	races        []service.RaceDisplay
+	cursor       int
+	top          int // first visible row
+	loading      bool
+	err          error
+	width        int
+	height       int
+
+	// Placing notes prompt
+	editing bool
+	input   textInput
+	editErr error
+}
+
+// NewRacesModel creates a new races model
+func NewRacesModel(qs QueryProvider, units Units, width, height int) RacesModel {
+	return RacesModel{
+		queryService: qs,
+		units:        units,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init initializes the races screen
+func (m RacesModel) Init() tea.Cmd {
+	return m.loadRaces
+}
+
+type racesLoadedMsg struct {
+	races []service.RaceDisplay
+	err   error
+}
+
+type raceSavedMsg struct {
+	err error
+}
+
+func (m RacesModel) loadRaces() tea.Msg {
+	races, err := m.queryService.GetRaces()
+	return racesLoadedMsg{races: races, err: err}
+}
+
+// visibleRows is how many races fit on screen below the title and header
+func (m RacesModel) visibleRows() int {
+	if rows := m.height - 12; rows > 5 {
+		return rows
+	}
+	return 5
+}
+
+// Update handles messages
+func (m RacesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case racesLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.races = msg.races
+		if m.cursor >= len(m.races) {
+			m.cursor = max(len(m.races)-1, 0)
+		}
+		m.scrollToCursor()
+
+	case raceSavedMsg:
+		m.editErr = msg.err
+		return m, m.loadRaces
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m.updateEdit(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				m.scrollToCursor()
+			}
+		case "down", "j":
+			if m.cursor < len(m.races)-1 {
+				m.cursor++
+				m.scrollToCursor()
+			}
+		case "enter":
+			if race, ok := m.selected(); ok {
+				return m, func() tea.Msg {
+					return OpenActivityDetailMsg{ActivityID: race.ActivityID}
+				}
+			}
+		case "n":
+			if race, ok := m.selected(); ok {
+				m.editing = true
+				m.input = textInput{value: race.Placing}
+				m.editErr = nil
+			}
+		case "x":
+			if race, ok := m.selected(); ok {
+				qs := m.queryService
+				return m, func() tea.Msg {
+					return raceSavedMsg{err: qs.DismissRace(race.ActivityID)}
+				}
+			}
+		case "r":
+			m.loading = true
+			return m, m.loadRaces
+		}
+	}
+	return m, nil
+}
+
+// updateEdit handles key presses while the placing prompt is open
+func (m RacesModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case submitted:
+		race, ok := m.selected()
+		m.editing = false
+		if !ok {
+			return m, nil
+		}
+		qs, placing := m.queryService, m.input.value
+		return m, func() tea.Msg {
+			return raceSavedMsg{err: qs.SetRacePlacing(race.ActivityID, placing)}
+		}
+	case cancelled:
+		m.editing = false
+	}
+	return m, nil
+}
+
+func (m RacesModel) selected() (service.RaceDisplay, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.races) {
+		return service.RaceDisplay{}, false
+	}
+	return m.races[m.cursor], true
+}
+
+// scrollToCursor keeps the cursor row on screen
+func (m *RacesModel) scrollToCursor() {
+	rows := m.visibleRows()
+	if m.cursor < m.top {
+		m.top = m.cursor
+	}
+	if m.cursor >= m.top+rows {
+		m.top = m.cursor - rows + 1
+	}
+}
+
+// View renders the races screen
+func (m RacesModel) View() string {
+	if m.loading {
+		return "\n  Loading races..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	var sections []string
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("Races (%d)", len(m.races))))
+
+	if len(m.races) == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render(
+			"  No races yet. Mark runs as a race on Strava, or sync to detect them."))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	header := tableHeaderStyle.Render(fmt.Sprintf("   %-12s  %-8s  %-24s  %8s  %9s  %8s  %-8s  %s",
+		"Date", "Race", "Name", "Time", "Pace", "Dist", "Source", "Placing"))
+	sections = append(sections, header)
+
+	end := min(m.top+m.visibleRows(), len(m.races))
+	for i := m.top; i < end; i++ {
+		sections = append(sections, m.renderRow(i))
+	}
+
+	var footer string
+	if m.editing {
+		footer = fmt.Sprintf("  Placing: %s", m.input.view()) +
+			statusStyle.Render("  enter: save  esc: cancel")
+	} else {
+		footer = statusStyle.Render("  enter: view details  j/k: navigate  n: placing notes  x: not a race  r: refresh")
+	}
+	if m.editErr != nil {
+		footer = lipgloss.JoinVertical(lipgloss.Left, errorStyle.Render(fmt.Sprintf("  Error saving: %v", m.editErr)), footer)
+	}
+	sections = append(sections, "", footer)
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m RacesModel) renderRow(i int) string {
+	race := m.races[i]
+
+	cursor := "  "
+	if i == m.cursor {
+		cursor = "> "
+	}
+
+	label := race.RaceLabel
+	if label == "" {
+		label = "-"
+	}
+	name := race.Name
+	if race.IsPR {
+		name = "★ " + name
+	}
+	source := "Strava"
+	if race.Source == store.RaceSourceDetected {
+		source = "detected"
+	}
+	placing := race.Placing
+	if placing == "" {
+		placing = "-"
+	}
+
+	row := fmt.Sprintf("%s%-12s  %-8s  %-24s  %8s  %9s  %8s  %-8s  %s",
+		cursor,
+		race.Date,
+		truncateName(label, 8),
+		truncateName(name, 24),
+		race.Time,
+		m.units.FormatPacePerMile(race.PacePerMile),
+		m.units.FormatDistance(race.DistanceMeters),
+		source,
+		placing,
+	)
+
+	if i == m.cursor {
+		return tableSelectedStyle.Render(row)
+	}
+	return tableRowStyle.Render(row)
+}
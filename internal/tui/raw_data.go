@@ -0,0 +1,417 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"runner/internal/config"
+	"runner/internal/export"
+	"runner/internal/paths"
+	"runner/internal/service"
+	"runner/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RawDataModel pages through an activity's stream points, one row per
+// sample, exports a range of them as CSV and trims the activity
+type RawDataModel struct {
+	queryService QueryProvider
+	syncService  SyncRunner
+	units        Units
+	activityID   int64
+	name         string
+	page         *service.StreamPage
+	cursor       int // index of the selected point in the whole stream
+	top          int // index of the first visible point
+	mark         int // other end of the export range, -1 when unset
+	markTime     int // time offset of the marked point
+	loading      bool
+	err          error
+	notice       string
+	width        int
+	height       int
+
+	// Trimming after the cursor: confirmTrim holds the time offset while
+	// the prompt is open, -1 otherwise. trimmed is set once the activity
+	// changed, so the detail screen reloads.
+	confirmTrim int
+	trimming    bool
+	trimmed     bool
+
+	// zones are cropped from exports (privacy.zones)
+	zones []config.PrivacyZone
+}
+
+// NewRawDataModel creates a raw data model for one activity. ss may be nil,
+// which turns trimming off.
+func NewRawDataModel(qs QueryProvider, ss SyncRunner, units Units, activityID int64, name string, width, height int) RawDataModel {
+	return RawDataModel{
+		queryService: qs,
+		syncService:  ss,
+		units:        units,
+		activityID:   activityID,
+		name:         name,
+		mark:         -1,
+		confirmTrim:  -1,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init initializes the raw data screen
+func (m RawDataModel) Init() tea.Cmd {
+	return m.loadPage
+}
+
+type rawDataLoadedMsg struct {
+	page *service.StreamPage
+	err  error
+}
+
+type rawDataExportedMsg struct {
+	path    string
+	points  int
+	cropped int // points whose position was removed for a privacy zone
+	err     error
+}
+
+type rawDataTrimmedMsg struct {
+	endOffset int
+	err       error
+}
+
+func (m RawDataModel) loadPage() tea.Msg {
+	page, err := m.queryService.GetStreamPage(m.activityID, m.top, m.visibleRows())
+	return rawDataLoadedMsg{page: page, err: err}
+}
+
+// visibleRows is how many points fit on screen below the title and header
+func (m RawDataModel) visibleRows() int {
+	if rows := m.height - 12; rows > 5 {
+		return rows
+	}
+	return 5
+}
+
+// total is the number of points in the stream, 0 until the first page loads
+func (m RawDataModel) total() int {
+	if m.page == nil {
+		return 0
+	}
+	return m.page.Total
+}
+
+// Update handles messages
+func (m RawDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case rawDataLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.page != nil {
+			m.page = msg.page
+			m.top = msg.page.Offset
+			m.cursor = min(m.cursor, max(msg.page.Total-1, 0))
+		}
+
+	case rawDataExportedMsg:
+		if msg.err != nil {
+			m.notice = errorStyle.Render(fmt.Sprintf("  Export failed: %v", msg.err))
+		} else {
+			notice := fmt.Sprintf("  Exported %d points to %s", msg.points, msg.path)
+			if msg.cropped > 0 {
+				notice += fmt.Sprintf(" (%d cropped in privacy zones)", msg.cropped)
+			}
+			m.notice = successStyle.Render(notice)
+		}
+
+	case rawDataTrimmedMsg:
+		m.trimming = false
+		if msg.err != nil {
+			m.notice = errorStyle.Render(fmt.Sprintf("  Trim failed: %v", msg.err))
+			return m, nil
+		}
+		m.trimmed = true
+		m.mark = -1
+		m.notice = successStyle.Render(fmt.Sprintf("  Trimmed after %s and recomputed metrics and records; resync (S) restores it", formatClock(msg.endOffset)))
+		m.loading = true
+		return m, m.loadPage
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, m.scrollToCursor(true)
+
+	case tea.KeyMsg:
+		if m.confirmTrim >= 0 {
+			return m.updateConfirmTrim(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			return m, m.moveCursor(-1)
+		case "down", "j":
+			return m, m.moveCursor(1)
+		case "pgup":
+			return m, m.moveCursor(-m.visibleRows())
+		case "pgdown":
+			return m, m.moveCursor(m.visibleRows())
+		case "home", "g":
+			return m, m.moveCursor(-m.total())
+		case "end", "G":
+			return m, m.moveCursor(m.total())
+		case " ":
+			if m.mark >= 0 {
+				m.mark = -1
+			} else if p, ok := m.point(m.cursor); ok {
+				m.mark, m.markTime = m.cursor, p.TimeOffset
+			}
+		case "e":
+			if p, ok := m.point(m.cursor); ok {
+				return m, m.exportRange(p.TimeOffset)
+			}
+		case "t":
+			if m.trimming {
+				return m, nil
+			}
+			if m.syncService == nil {
+				m.notice = warningStyle.Render("  Trimming is off in demo and read-only mode")
+				return m, nil
+			}
+			if p, ok := m.point(m.cursor); ok {
+				m.confirmTrim = p.TimeOffset
+			}
+		case "r":
+			m.loading = true
+			return m, m.loadPage
+		}
+	}
+	return m, nil
+}
+
+// updateConfirmTrim trims the activity on y and cancels on any other key
+func (m RawDataModel) updateConfirmTrim(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	endOffset := m.confirmTrim
+	m.confirmTrim = -1
+	if msg.String() != "y" {
+		return m, nil
+	}
+
+	m.trimming = true
+	m.notice = statusStyle.Render("  Trimming...")
+	ss, id := m.syncService, m.activityID
+	return m, func() tea.Msg {
+		_, err := ss.TrimActivity(context.Background(), id, endOffset)
+		return rawDataTrimmedMsg{endOffset: endOffset, err: err}
+	}
+}
+
+// moveCursor moves the cursor by delta points, loading another page when it
+// leaves the one shown
+func (m *RawDataModel) moveCursor(delta int) tea.Cmd {
+	if m.total() == 0 {
+		return nil
+	}
+	m.cursor = min(max(m.cursor+delta, 0), m.total()-1)
+	return m.scrollToCursor(false)
+}
+
+// scrollToCursor keeps the cursor row on screen, reloading the page when
+// the visible window moves or force is set
+func (m *RawDataModel) scrollToCursor(force bool) tea.Cmd {
+	rows := m.visibleRows()
+	top := m.top
+	if m.cursor < top {
+		top = m.cursor
+	}
+	if m.cursor >= top+rows {
+		top = m.cursor - rows + 1
+	}
+	if top == m.top && !force {
+		return nil
+	}
+	m.top = top
+	return m.loadPage
+}
+
+// point returns the stream point at index i if it is on the loaded page
+func (m RawDataModel) point(i int) (store.StreamPoint, bool) {
+	if m.page == nil || i < m.page.Offset || i >= m.page.Offset+len(m.page.Points) {
+		return store.StreamPoint{}, false
+	}
+	return m.page.Points[i-m.page.Offset], true
+}
+
+// exportRange writes the points between the mark and the cursor to a CSV
+// file in the data directory's exports folder, or the whole stream without
+// a mark. Positions inside privacy zones are left out.
+func (m RawDataModel) exportRange(cursorTime int) tea.Cmd {
+	from, to := 0, math.MaxInt
+	name := fmt.Sprintf("activity-%d-streams.csv", m.activityID)
+	if m.mark >= 0 {
+		from, to = min(m.markTime, cursorTime), max(m.markTime, cursorTime)
+		name = fmt.Sprintf("activity-%d-streams-%d-%d.csv", m.activityID, from, to)
+	}
+
+	qs, id, zones := m.queryService, m.activityID, m.zones
+	return func() tea.Msg {
+		points, err := qs.GetStreamRange(id, from, to)
+		if err != nil {
+			return rawDataExportedMsg{err: err}
+		}
+		points, cropped := export.CropPrivacyZones(points, zones)
+		dir, err := paths.DataDir()
+		if err != nil {
+			return rawDataExportedMsg{err: err}
+		}
+		dir = filepath.Join(dir, "exports")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return rawDataExportedMsg{err: err}
+		}
+
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			return rawDataExportedMsg{err: err}
+		}
+		defer f.Close()
+		if err := export.WriteStreamsCSV(f, points); err != nil {
+			return rawDataExportedMsg{err: err}
+		}
+		return rawDataExportedMsg{path: path, points: len(points), cropped: cropped}
+	}
+}
+
+// View renders the raw data screen
+func (m RawDataModel) View() string {
+	if m.loading && m.page == nil {
+		return "\n  Loading stream data..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	title := "Raw Data"
+	if m.name != "" {
+		title += " - " + m.name
+	}
+	var sections []string
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("%s (%d points)", title, m.total())))
+
+	if m.total() == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render(
+			"  No stream data for this activity. Sync or resync it to fetch streams."))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	header := tableHeaderStyle.Render(fmt.Sprintf("   %8s  %9s  %6s  %9s  %4s  %4s  %8s  %6s  %10s  %11s",
+		"Time", "Dist", "m/s", "Pace", "HR", "Cad", "Alt", "Grade", "Lat", "Lng"))
+	sections = append(sections, header)
+
+	for i := m.page.Offset; i < m.page.Offset+len(m.page.Points); i++ {
+		sections = append(sections, m.renderRow(i))
+	}
+
+	status := fmt.Sprintf("  Point %d of %d", m.cursor+1, m.total())
+	if m.mark >= 0 {
+		lo, hi := min(m.mark, m.cursor), max(m.mark, m.cursor)
+		status += fmt.Sprintf("  ·  %d points selected", hi-lo+1)
+	}
+	help := statusStyle.Render("  esc: back  j/k: move  pgup/pgdn: page  g/G: start/end  space: mark range  e: export CSV  t: trim after  r: refresh")
+	if m.confirmTrim >= 0 {
+		help = warningStyle.Render(fmt.Sprintf("  Cut everything recorded after %s from this activity? Only the local copy changes.", formatClock(m.confirmTrim))) +
+			statusStyle.Render("  y: trim  any other key: cancel")
+	}
+	footer := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Foreground(mutedColor).Render(status), help)
+	if m.notice != "" {
+		footer = lipgloss.JoinVertical(lipgloss.Left, m.notice, footer)
+	}
+	sections = append(sections, "", footer)
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m RawDataModel) renderRow(i int) string {
+	p, _ := m.point(i)
+
+	cursor := "  "
+	if i == m.cursor {
+		cursor = "> "
+	} else if m.mark >= 0 && i >= min(m.mark, m.cursor) && i <= max(m.mark, m.cursor) {
+		cursor = "│ "
+	}
+
+	dist, speed, pace := "-", "-", "-"
+	if p.Distance != nil {
+		if m.units.IsMiles() {
+			dist = fmt.Sprintf("%.2f mi", *p.Distance/metersPerMile)
+		} else {
+			dist = fmt.Sprintf("%.2f km", *p.Distance/metersPerKm)
+		}
+	}
+	if p.VelocitySmooth != nil {
+		speed = fmt.Sprintf("%.2f", *p.VelocitySmooth)
+		if *p.VelocitySmooth > 0 {
+			pace = m.units.FormatPacePerMile(metersPerMile / *p.VelocitySmooth)
+		}
+	}
+
+	row := fmt.Sprintf("%s%8s  %9s  %6s  %9s  %4s  %4s  %8s  %6s  %10s  %11s",
+		cursor,
+		formatClock(p.TimeOffset),
+		dist,
+		speed,
+		pace,
+		optionalInt(p.Heartrate, 1),
+		optionalInt(p.Cadence, int(service.StravaCadenceMultiplier)),
+		optionalElevation(m.units, p.Altitude),
+		optionalFloat(p.GradeSmooth, "%.1f%%"),
+		optionalFloat(p.Lat, "%.5f"),
+		optionalFloat(p.Lng, "%.5f"),
+	)
+
+	if i == m.cursor {
+		return tableSelectedStyle.Render(row)
+	}
+	return tableRowStyle.Render(row)
+}
+
+// formatClock formats seconds as H:MM:SS, or M:SS under an hour
+func formatClock(seconds int) string {
+	h, m, s := seconds/3600, seconds%3600/60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+func optionalInt(v *int, scale int) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *v*scale)
+}
+
+func optionalFloat(v *float64, format string) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf(format, *v)
+}
+
+func optionalElevation(u Units, meters *float64) string {
+	if meters == nil {
+		return "-"
+	}
+	if u.IsMiles() {
+		return fmt.Sprintf("%.1f ft", *meters*feetPerMeter)
+	}
+	return fmt.Sprintf("%.1f m", *meters)
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"errors"
+	"time"
+
+	"runner/internal/store"
+)
+
+// errReadOnly is returned for changes made while browsing read-only
+var errReadOnly = errors.New("the database is open read-only; run without --read-only to make changes")
+
+// readOnlyQueries serves a database opened read-only. It refuses the
+// annotations, logs and flags that would write to it, so the screens report
+// that instead of a SQLite error.
+type readOnlyQueries struct {
+	QueryProvider
+}
+
+func (readOnlyQueries) SaveWellness(entries ...store.Wellness) error { return errReadOnly }
+
+func (readOnlyQueries) SetActivityNote(activityID int64, note string) error { return errReadOnly }
+
+func (readOnlyQueries) SetActivityTags(activityID int64, tags []string) error { return errReadOnly }
+
+func (readOnlyQueries) SetActivityTemperature(activityID int64, tempC *float64) error {
+	return errReadOnly
+}
+
+func (readOnlyQueries) SetActivityRPE(activityID int64, rpe *store.ActivityRPE) error {
+	return errReadOnly
+}
+
+func (readOnlyQueries) SetActivityExcluded(activityID int64, excluded bool) error {
+	return errReadOnly
+}
+
+func (readOnlyQueries) VerifyActivityEfforts(activityID int64) error { return errReadOnly }
+
+func (readOnlyQueries) SetRacePlacing(activityID int64, placing string) error { return errReadOnly }
+
+func (readOnlyQueries) DismissRace(activityID int64) error { return errReadOnly }
+
+func (readOnlyQueries) AddInjury(injury store.Injury) (int64, error) { return 0, errReadOnly }
+
+func (readOnlyQueries) ResolveInjury(id int64, resolvedOn *time.Time) error { return errReadOnly }
+
+func (readOnlyQueries) DeleteInjury(id int64) error { return errReadOnly }
+
+func (readOnlyQueries) CreateTrainingPlan(raceDate time.Time, peak float64, taperWeeks, cutbackEvery int) (*store.TrainingPlan, error) {
+	return nil, errReadOnly
+}
+
+func (readOnlyQueries) DeleteTrainingPlan() error { return errReadOnly }
+
+func (readOnlyQueries) AddBenchmark(name string, kind store.BenchmarkKind, referenceID int64) (int64, error) {
+	return 0, errReadOnly
+}
+
+func (readOnlyQueries) DeleteBenchmark(id int64) error { return errReadOnly }
+
+func (readOnlyQueries) SetBenchmarkAttempt(benchmarkID, activityID int64, included bool) error {
+	return errReadOnly
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"runner/internal/strava"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RequestsModel is the Strava request log screen model, for seeing why a
+// sync stalls
+type RequestsModel struct {
+	syncService SyncRunner
+	records     []strava.RequestRecord
+	viewport    viewport.Model
+	loading     bool
+	width       int
+	height      int
+	ready       bool
+}
+
+// NewRequestsModel creates a new request log screen. syncService may be nil,
+// as in demo and read-only mode, when no requests are sent.
+func NewRequestsModel(syncService SyncRunner, width, height int) RequestsModel {
+	m := RequestsModel{
+		syncService: syncService,
+		loading:     true,
+		width:       width,
+		height:      height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the request log
+func (m RequestsModel) Init() tea.Cmd {
+	return m.loadRequests
+}
+
+type requestsLoadedMsg struct {
+	records []strava.RequestRecord
+}
+
+func (m RequestsModel) loadRequests() tea.Msg {
+	if m.syncService == nil {
+		return requestsLoadedMsg{}
+	}
+	return requestsLoadedMsg{records: m.syncService.RecentRequests()}
+}
+
+// Update handles messages
+func (m RequestsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case requestsLoadedMsg:
+		m.loading = false
+		m.records = msg.records
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+			m.viewport.GotoTop()
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		if msg.String() == "r" {
+			m.loading = true
+			return m, m.loadRequests
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the request log
+func (m RequestsModel) View() string {
+	if m.loading {
+		return "\n  Loading requests..."
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	footer := statusStyle.Render(fmt.Sprintf("  Last %d Strava requests, newest first  j/k or arrows: scroll  r: refresh", strava.RequestLogSize))
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+func (m RequestsModel) renderContent() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	if m.syncService == nil {
+		return muted.Render("\n  No Strava connection, so no requests are sent.")
+	}
+	if len(m.records) == 0 {
+		return muted.Render("\n  No Strava requests yet. Start a sync (7) and come back.")
+	}
+
+	lines := []string{"", "  " + diagnoseRequests(m.records), ""}
+	lines = append(lines, tableHeaderStyle.Render(fmt.Sprintf("  %-8s  %-7s  %8s  %-16s  %s", "Time", "Status", "Latency", "Used 15min/day", "Endpoint")))
+
+	// Newest first, as that's what explains a stall
+	for i := len(m.records) - 1; i >= 0; i-- {
+		r := m.records[i]
+		status := fmt.Sprintf("%-7s", requestStatus(r))
+		switch {
+		case r.Status == http.StatusTooManyRequests:
+			status = warningStyle.Render(status)
+		case r.Err != "" || r.Status >= 400:
+			status = errorStyle.Render(status)
+		case r.Cached:
+			status = muted.Render(status)
+		}
+
+		endpoint := r.Path
+		if r.Attempt > 1 {
+			endpoint += muted.Render(fmt.Sprintf(" (attempt %d)", r.Attempt))
+		}
+		if r.Err != "" {
+			endpoint += " " + errorStyle.Render(r.Err)
+		}
+
+		quota := fmt.Sprintf("%d/%d %d/%d", r.ShortUsage, r.ShortLimit, r.DailyUsage, r.DailyLimit)
+		lines = append(lines, fmt.Sprintf("  %s  %s  %8s  %-16s  %s",
+			muted.Render(r.Time.Local().Format("15:04:05")), status, formatLatency(r), quota, endpoint))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// requestStatus is a request's status code, or what stood in for one
+func requestStatus(r strava.RequestRecord) string {
+	switch {
+	case r.Err != "":
+		return "failed"
+	case r.Cached:
+		return "304 hit"
+	}
+	return fmt.Sprintf("%d", r.Status)
+}
+
+func formatLatency(r strava.RequestRecord) string {
+	ms := r.Latency.Milliseconds()
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", r.Latency.Seconds())
+}
+
+// diagnoseRequests reads the latest requests for why a sync would stall:
+// an expired or revoked token, the rate limit, or Strava failing. The
+// newest request decides, as an older failure may have since cleared.
+func diagnoseRequests(records []strava.RequestRecord) string {
+	if len(records) == 0 {
+		return ""
+	}
+	last := records[len(records)-1]
+
+	switch {
+	case last.Status == http.StatusUnauthorized:
+		return errorStyle.Render("Unauthorized (401): Strava rejected the token, restart to reauthorize")
+	case last.Status == http.StatusForbidden:
+		return errorStyle.Render("Forbidden (403): the token may lack a permission, run runner auth to grant it")
+	case last.Status == http.StatusTooManyRequests:
+		return warningStyle.Render("Rate limited: Strava refused the last request, sync carries on once the window resets")
+	case last.ShortLimit > 0 && last.ShortUsage >= last.ShortLimit:
+		return warningStyle.Render(fmt.Sprintf("Rate limited: %d/%d requests used this 15 minutes", last.ShortUsage, last.ShortLimit))
+	case last.DailyLimit > 0 && last.DailyUsage >= last.DailyLimit:
+		return warningStyle.Render(fmt.Sprintf("Rate limited: %d/%d requests used today", last.DailyUsage, last.DailyLimit))
+	case last.Err != "" || last.Status >= 500:
+		var failing int
+		for i := len(records) - 1; i >= 0 && (records[i].Err != "" || records[i].Status >= 500); i-- {
+			failing++
+		}
+		if failing == 1 {
+			return errorStyle.Render("Strava is failing: the last request got no answer or a server error")
+		}
+		return errorStyle.Render(fmt.Sprintf("Strava is failing: the last %d requests got no answer or a server error", failing))
+	}
+	return successStyle.Render("OK: the last request succeeded")
+}
+
+// lastRequest is a one-line account of the newest request, for the live
+// sync view
+func lastRequest(records []strava.RequestRecord) string {
+	if len(records) == 0 {
+		return ""
+	}
+	r := records[len(records)-1]
+	line := fmt.Sprintf("Last request: %s %s in %s", requestStatus(r), r.Path, formatLatency(r))
+	if r.Attempt > 1 {
+		line += fmt.Sprintf(" (attempt %d)", r.Attempt)
+	}
+	return line
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"runner/internal/strava"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRequestsModel(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ok := strava.RequestRecord{Time: at, Path: "/athlete/activities", Attempt: 1, Status: 200, Latency: 180 * time.Millisecond, ShortLimit: 100, DailyLimit: 1000}
+
+	tests := []struct {
+		name    string
+		sync    SyncRunner
+		records []strava.RequestRecord
+		want    []string
+	}{
+		{
+			name: "no connection",
+			want: []string{"No Strava connection"},
+		},
+		{
+			name: "no requests yet",
+			sync: &fakeSync{},
+			want: []string{"No Strava requests yet"},
+		},
+		{
+			name:    "healthy",
+			records: []strava.RequestRecord{ok},
+			want:    []string{"OK: the last request succeeded", "/athlete/activities", "180ms"},
+		},
+		{
+			name: "unauthorized",
+			records: []strava.RequestRecord{ok,
+				{Time: at, Path: "/activities/7", Attempt: 1, Status: 401}},
+			want: []string{"Unauthorized (401)"},
+		},
+		{
+			name: "rate limited",
+			records: []strava.RequestRecord{
+				{Time: at, Path: "/activities/7", Attempt: 1, Status: 200, ShortUsage: 100, ShortLimit: 100, DailyLimit: 1000}},
+			want: []string{"Rate limited: 100/100 requests used this 15 minutes"},
+		},
+		{
+			name: "strava down",
+			records: []strava.RequestRecord{ok,
+				{Time: at, Path: "/activities/7", Attempt: 1, Status: 502},
+				{Time: at, Path: "/activities/7", Attempt: 2, Err: "connection reset", Latency: 2 * time.Second}},
+			want: []string{"the last 2 requests got no answer", "(attempt 2)", "connection reset", "2.0s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := tt.sync
+			if tt.records != nil {
+				ss = &fakeSync{requests: tt.records}
+			}
+			var m tea.Model = NewRequestsModel(ss, 120, 40)
+			m = runCmd(m, m.Init())
+
+			view := m.View()
+			for _, s := range tt.want {
+				if !strings.Contains(view, s) {
+					t.Errorf("View() missing %q:\n%s", s, view)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"runner/internal/service"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ReviewModel is the year in review screen model
+type ReviewModel struct {
+	queryService QueryProvider
+	units        Units
+	years        []service.YearReview
+	viewport     viewport.Model
+	loading      bool
+	err          error
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewReviewModel creates a new year in review model
+func NewReviewModel(qs QueryProvider, units Units, width, height int) ReviewModel {
+	m := ReviewModel{
+		queryService: qs,
+		units:        units,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the year in review screen
+func (m ReviewModel) Init() tea.Cmd {
+	return m.loadReview
+}
+
+type reviewLoadedMsg struct {
+	years []service.YearReview
+	err   error
+}
+
+func (m ReviewModel) loadReview() tea.Msg {
+	years, err := m.queryService.GetYearInReview()
+	return reviewLoadedMsg{years: years, err: err}
+}
+
+// Update handles messages
+func (m ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case reviewLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.years = msg.years
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			return m, m.loadReview
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the year in review screen
+func (m ReviewModel) View() string {
+	if m.loading {
+		return "\n  Loading year in review..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	footer := statusStyle.Render("  j/k or arrows: scroll  r: refresh")
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+// Bar width for the year-over-year distance comparison
+const reviewBarWidth = 40
+
+func (m ReviewModel) renderContent() string {
+	var sections []string
+
+	sections = append(sections, "")
+	sections = append(sections, cardTitleStyle.Render("Year in Review"))
+	sections = append(sections, "")
+
+	if len(m.years) == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render("  No runs yet. Sync with Strava to build your review."))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	sections = append(sections, m.renderComparison())
+	for _, y := range m.years {
+		sections = append(sections, m.renderYear(y))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderComparison draws one distance bar per year, scaled to the biggest year
+func (m ReviewModel) renderComparison() string {
+	var lines []string
+
+	header := fmt.Sprintf("  %-4s  %-*s  %9s  %7s", "Year", reviewBarWidth, "Distance", m.units.DistanceLabel(), "vs prev")
+	lines = append(lines, lipgloss.NewStyle().Foreground(primaryColor).Render(header))
+
+	var longest float64
+	for _, y := range m.years {
+		if y.Distance > longest {
+			longest = y.Distance
+		}
+	}
+
+	for _, y := range m.years {
+		delta := "-"
+		if y.HasPrevious {
+			delta = formatPercentDelta(y.DistanceDelta)
+		}
+		var share float64
+		if longest > 0 {
+			share = y.Distance / longest
+		}
+		lines = append(lines, fmt.Sprintf("  %-4d  %s  %9s  %7s",
+			y.Year, RenderProgressBar(share, reviewBarWidth), m.units.FormatDistanceValue(y.Distance), delta))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+func (m ReviewModel) renderYear(y service.YearReview) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	var lines []string
+
+	lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(secondaryColor).Render(fmt.Sprintf("%d", y.Year)))
+
+	runs := fmt.Sprintf("%d", y.RunCount)
+	distance := m.units.FormatDistance(y.Distance)
+	duration := formatDuration(y.MovingTime)
+	if y.HasPrevious {
+		runs += muted.Render(" (" + formatPercentDelta(y.RunsDelta) + ")")
+		distance += muted.Render(" (" + formatPercentDelta(y.DistanceDelta) + ")")
+		duration += muted.Render(" (" + formatPercentDelta(y.TimeDelta) + ")")
+	}
+
+	lines = append(lines, fmt.Sprintf("  Runs:          %s", runs))
+	lines = append(lines, fmt.Sprintf("  Distance:      %s", distance))
+	lines = append(lines, fmt.Sprintf("  Time:          %s", duration))
+	lines = append(lines, fmt.Sprintf("  Elevation:     %s", m.units.FormatElevation(y.Elevation)))
+	lines = append(lines, fmt.Sprintf("  PRs set:       %d", y.PRsSet))
+
+	bestEF := muted.Render("-")
+	if y.BestEF > 0 {
+		bestEF = fmt.Sprintf("%s (EF %.2f)", y.BestEFMonth, y.BestEF)
+	}
+	lines = append(lines, fmt.Sprintf("  Best EF month: %s", bestEF))
+
+	if y.BiggestWeekDistance > 0 {
+		lines = append(lines, fmt.Sprintf("  Biggest week:  %s (week of %s)",
+			m.units.FormatDistance(y.BiggestWeekDistance), y.BiggestWeekStart.Format("Jan 2")))
+	}
+
+	lines = append(lines, "")
+	return strings.Join(lines, "\n")
+}
+
+// formatPercentDelta formats a year-over-year change as "+12%"
+func formatPercentDelta(pct float64) string {
+	return fmt.Sprintf("%+.0f%%", pct)
+}
@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+
+	"runner/internal/service"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SegmentsModel is the fastest segments screen model, ranking the best
+// efforts over one distance from every run
+type SegmentsModel struct {
+	queryService QueryProvider
+	units        Units
+	boards       []service.SegmentBoard
+	selected     int // board shown
+	cursor       int
+	loading      bool
+	err          error
+	width        int
+	height       int
+}
+
+// NewSegmentsModel creates a new segments model
+func NewSegmentsModel(qs QueryProvider, units Units, width, height int) SegmentsModel {
+	return SegmentsModel{
+		queryService: qs,
+		units:        units,
+		selected:     -1,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+}
+
+// Init initializes the segments screen
+func (m SegmentsModel) Init() tea.Cmd {
+	return m.loadSegments
+}
+
+type segmentsLoadedMsg struct {
+	boards []service.SegmentBoard
+	err    error
+}
+
+func (m SegmentsModel) loadSegments() tea.Msg {
+	boards, err := m.queryService.GetFastestSegments()
+	return segmentsLoadedMsg{boards: boards, err: err}
+}
+
+// defaultBoard is the mile in miles units and the kilometer otherwise
+func (m SegmentsModel) defaultBoard() int {
+	want := "effort_1k"
+	if m.units.IsMiles() {
+		want = "effort_1mi"
+	}
+	for i, b := range m.boards {
+		if b.Category == want {
+			return i
+		}
+	}
+	return 0
+}
+
+// Update handles messages
+func (m SegmentsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case segmentsLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.boards = msg.boards
+		if m.selected < 0 || m.selected >= len(m.boards) {
+			m.selected = m.defaultBoard()
+		}
+		if m.cursor >= len(m.segments()) {
+			m.cursor = max(len(m.segments())-1, 0)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "left", "h", "[":
+			if m.selected > 0 {
+				m.selected--
+				m.cursor = 0
+			}
+		case "right", "l", "]":
+			if m.selected < len(m.boards)-1 {
+				m.selected++
+				m.cursor = 0
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.segments())-1 {
+				m.cursor++
+			}
+		case "enter":
+			if segment, ok := m.selectedSegment(); ok {
+				return m, func() tea.Msg {
+					return OpenActivityDetailMsg{ActivityID: segment.ActivityID}
+				}
+			}
+		case "r":
+			m.loading = true
+			return m, m.loadSegments
+		}
+	}
+	return m, nil
+}
+
+// segments returns the efforts on the board shown
+func (m SegmentsModel) segments() []service.SegmentDisplay {
+	if m.selected < 0 || m.selected >= len(m.boards) {
+		return nil
+	}
+	return m.boards[m.selected].Segments
+}
+
+func (m SegmentsModel) selectedSegment() (service.SegmentDisplay, bool) {
+	segments := m.segments()
+	if m.cursor < 0 || m.cursor >= len(segments) {
+		return service.SegmentDisplay{}, false
+	}
+	return segments[m.cursor], true
+}
+
+// View renders the segments screen
+func (m SegmentsModel) View() string {
+	if m.loading {
+		return "\n  Loading segments..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var sections []string
+	sections = append(sections, cardTitleStyle.Render("Fastest Segments"))
+	if len(m.boards) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	board := m.boards[m.selected]
+	sections = append(sections, m.renderTabs(), muted.Render(fmt.Sprintf(
+		"  The %d fastest %s stretches from any run, each run's best once", service.SegmentsShown, board.CategoryLabel)))
+
+	if len(board.Segments) == 0 {
+		sections = append(sections, "", muted.Render(
+			"  No efforts yet. Run a sync with the personal records phase to rank them."))
+	} else {
+		header := tableHeaderStyle.Render(fmt.Sprintf("   %4s  %8s  %9s  %6s  %-12s  %s",
+			"#", "Time", "Pace", "Avg HR", "Date", "Activity"))
+		sections = append(sections, header)
+		for i := range board.Segments {
+			sections = append(sections, m.renderRow(board.Segments, i))
+		}
+	}
+
+	sections = append(sections, "", statusStyle.Render("  h/l: distance  j/k: navigate  enter: view details  r: refresh"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderTabs lists the distances with the one shown highlighted
+func (m SegmentsModel) renderTabs() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	tabs := " "
+	for i, b := range m.boards {
+		if i == m.selected {
+			tabs += " " + tableSelectedStyle.Render(" "+b.CategoryLabel+" ")
+		} else {
+			tabs += " " + muted.Render(" "+b.CategoryLabel+" ")
+		}
+	}
+	return tabs
+}
+
+func (m SegmentsModel) renderRow(segments []service.SegmentDisplay, i int) string {
+	s := segments[i]
+
+	cursor := "  "
+	if i == m.cursor {
+		cursor = "> "
+	}
+
+	row := fmt.Sprintf("%s%4d  %8s  %9s  %6s  %-12s  %s",
+		cursor,
+		s.Rank,
+		s.Time,
+		m.units.FormatPacePerMile(s.PacePerMile),
+		s.AvgHR,
+		s.Date,
+		truncateName(s.ActivityName, 30),
+	)
+
+	if i == m.cursor {
+		row = tableSelectedStyle.Render(row)
+	} else {
+		row = tableRowStyle.Render(row)
+	}
+	if s.Unverified {
+		row += warningStyle.Render("  ⚠ unverified")
+	}
+	return row
+}
@@ -0,0 +1,392 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"runner/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// settingKind is how a setting is edited
+type settingKind int
+
+const (
+	settingNumber settingKind = iota // typed in
+	settingChoice                    // cycled through a fixed list
+	settingToggle                    // switched on and off
+)
+
+// setting is one editable row of the settings screen
+type setting struct {
+	section string
+	label   string
+	kind    settingKind
+	choices []string
+	get     func(c *config.Config) string
+	set     func(c *config.Config, value string) error
+}
+
+// settings are the rows of the settings screen, in display order
+var settings = []setting{
+	floatSetting("Athlete", "Resting HR (bpm)", func(c *config.Config) *float64 { return &c.Athlete.RestingHR }),
+	floatSetting("Athlete", "Max HR (bpm)", func(c *config.Config) *float64 { return &c.Athlete.MaxHR }),
+	floatSetting("Athlete", "Threshold HR (bpm)", func(c *config.Config) *float64 { return &c.Athlete.ThresholdHR }),
+	choiceSetting("Athlete", "Sex (for TRIMP)", []string{config.SexMale, config.SexFemale}, func(c *config.Config) *string { return &c.Athlete.Sex }),
+	paceAnchorsSetting("Athlete", "Pace at HR anchors (bpm, blank for 60/70/80% HRR)"),
+	floatSetting("Athlete", "Weight (kg, 0 for none)", func(c *config.Config) *float64 { return &c.Athlete.WeightKg }),
+	floatSetting("Athlete", "Weekly goal (km, 0 for none)", func(c *config.Config) *float64 { return &c.Athlete.WeeklyGoalKm }),
+
+	choiceSetting("Display", "Distance unit", []string{"km", "mi"}, func(c *config.Config) *string { return &c.Display.DistanceUnit }),
+	choiceSetting("Display", "Pace unit", []string{"min/km", "min/mi"}, func(c *config.Config) *string { return &c.Display.PaceUnit }),
+	choiceSetting("Display", "Theme", config.Themes, func(c *config.Config) *string { return &c.Display.Theme }),
+	toggleSetting("Display", "Label weeks by ISO week number", func(c *config.Config) *bool { return &c.Display.WeekNumbers }),
+
+	toggleSetting("Analysis", "Leave flagged runs out of PRs and EF", func(c *config.Config) *bool { return &c.Analysis.ExcludeFlagged }),
+	toggleSetting("Analysis", "Disable GPS smoothing", func(c *config.Config) *bool { return &c.Analysis.DisableSmoothing }),
+	choiceSetting("Analysis", "Best efforts from", []string{config.BestEffortsFromStreams, config.BestEffortsFromStrava}, func(c *config.Config) *string { return &c.Analysis.BestEffortSource }),
+	choiceSetting("Analysis", "Days and weeks in", []string{config.DayBucketsLocal, config.DayBucketsUTC}, func(c *config.Config) *string { return &c.Analysis.DayBuckets }),
+	choiceSetting("Analysis", "Weeks start on", []string{config.WeekStartMonday, config.WeekStartSunday}, func(c *config.Config) *string { return &c.Analysis.WeekStart }),
+	choiceSetting("Analysis", "Training load from", config.LoadModels, func(c *config.Config) *string { return &c.Analysis.LoadModel }),
+	floatSetting("Analysis", "Riegel exponent", func(c *config.Config) *float64 { return &c.Analysis.RiegelExponent }),
+	intSetting("Analysis", "Rest day warning (days, -1 for off)", func(c *config.Config) *int { return &c.Analysis.RestDayWarningDays }),
+	floatSetting("Analysis", "Weekly ramp warning (%)", func(c *config.Config) *float64 { return &c.Analysis.RampThresholdPct }),
+
+	toggleSetting("Privacy", "Sync kudos, comment and photo counts", func(c *config.Config) *bool { return &c.Privacy.SyncSocial }),
+	toggleSetting("Privacy", "Sync primary photo URLs", func(c *config.Config) *bool { return &c.Privacy.SyncPhotos }),
+
+	toggleSetting("Notifications", "Sync results", func(c *config.Config) *bool { return &c.Notifications.Sync }),
+	toggleSetting("Notifications", "New personal records", func(c *config.Config) *bool { return &c.Notifications.Records }),
+	toggleSetting("Notifications", "Training warnings", func(c *config.Config) *bool { return &c.Notifications.Warnings }),
+}
+
+func floatSetting(section, label string, field func(c *config.Config) *float64) setting {
+	return setting{
+		section: section,
+		label:   label,
+		kind:    settingNumber,
+		get: func(c *config.Config) string {
+			return strconv.FormatFloat(*field(c), 'f', -1, 64)
+		},
+		set: func(c *config.Config, value string) error {
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a number", label, value)
+			}
+			*field(c) = v
+			return nil
+		},
+	}
+}
+
+func intSetting(section, label string, field func(c *config.Config) *int) setting {
+	return setting{
+		section: section,
+		label:   label,
+		kind:    settingNumber,
+		get: func(c *config.Config) string {
+			return strconv.Itoa(*field(c))
+		},
+		set: func(c *config.Config, value string) error {
+			v, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a whole number", label, value)
+			}
+			*field(c) = v
+			return nil
+		},
+	}
+}
+
+// paceAnchorsSetting edits the pace-at-HR anchors as comma-separated heart
+// rates; config.Validate checks how many and their range
+func paceAnchorsSetting(section, label string) setting {
+	return setting{
+		section: section,
+		label:   label,
+		kind:    settingNumber,
+		get: func(c *config.Config) string {
+			values := make([]string, len(c.Athlete.PaceAnchors))
+			for i, hr := range c.Athlete.PaceAnchors {
+				values[i] = strconv.FormatFloat(hr, 'f', -1, 64)
+			}
+			return strings.Join(values, ",")
+		},
+		set: func(c *config.Config, value string) error {
+			var anchors []float64
+			for _, field := range strings.Split(value, ",") {
+				if field = strings.TrimSpace(field); field == "" {
+					continue
+				}
+				hr, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					return fmt.Errorf("%s: %q is not a number", label, field)
+				}
+				anchors = append(anchors, hr)
+			}
+			c.Athlete.PaceAnchors = anchors
+			return nil
+		},
+	}
+}
+
+func choiceSetting(section, label string, choices []string, field func(c *config.Config) *string) setting {
+	return setting{
+		section: section,
+		label:   label,
+		kind:    settingChoice,
+		choices: choices,
+		get: func(c *config.Config) string {
+			if *field(c) == "" {
+				return choices[0]
+			}
+			return *field(c)
+		},
+		set: func(c *config.Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+func toggleSetting(section, label string, field func(c *config.Config) *bool) setting {
+	return setting{
+		section: section,
+		label:   label,
+		kind:    settingToggle,
+		get: func(c *config.Config) string {
+			if *field(c) {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *config.Config, value string) error {
+			*field(c) = value == "on"
+			return nil
+		},
+	}
+}
+
+// next returns the value after the current one: the next choice, or the
+// opposite of a toggle
+func (s setting) next(c *config.Config) string {
+	current := s.get(c)
+	if s.kind == settingToggle {
+		if current == "on" {
+			return "off"
+		}
+		return "on"
+	}
+	for i, choice := range s.choices {
+		if choice == current {
+			return s.choices[(i+1)%len(s.choices)]
+		}
+	}
+	return s.choices[0]
+}
+
+// SettingsSavedMsg is sent when edited settings were validated and saved.
+// Persisted is false when there is nowhere to save them, as in demo mode,
+// and they only last for the session.
+type SettingsSavedMsg struct {
+	Config    config.Config
+	Persisted bool
+	Err       error
+}
+
+// ConfigReloadedMsg carries config.json reloaded after it changed on disk,
+// or the error reading it
+type ConfigReloadedMsg struct {
+	Config *config.Config
+	Err    error
+}
+
+// hrValuesChanged reports whether the HR values that metrics are computed
+// from differ, the TRIMP coefficient and pace anchors included
+func hrValuesChanged(a, b config.AthleteConfig) bool {
+	return a.RestingHR != b.RestingHR || a.MaxHR != b.MaxHR || a.ThresholdHR != b.ThresholdHR ||
+		a.Sex != b.Sex || !slices.Equal(a.PaceAnchors, b.PaceAnchors)
+}
+
+// SettingsModel is the settings screen model. It edits a copy of the config
+// and hands it to the app once saved.
+type SettingsModel struct {
+	cfg   config.Config // being edited
+	saved config.Config // as last saved
+	save  func(*config.Config) error
+
+	cursor  int
+	editing bool
+	input   textInput
+
+	// syncing blocks saving while a sync uses the current HR values
+	syncing bool
+
+	err    error
+	status string
+}
+
+// NewSettingsModel creates a settings model editing cfg. save writes the
+// config file; nil applies changes for this session only.
+func NewSettingsModel(cfg config.Config, save func(*config.Config) error) SettingsModel {
+	return SettingsModel{
+		cfg:   cfg,
+		saved: cfg,
+		save:  save,
+	}
+}
+
+// Init initializes the settings screen
+func (m SettingsModel) Init() tea.Cmd {
+	return nil
+}
+
+// dirty reports whether there are unsaved changes
+func (m SettingsModel) dirty() bool {
+	for _, s := range settings {
+		if s.get(&m.cfg) != s.get(&m.saved) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update handles messages
+func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case SettingsSavedMsg:
+		m.err = msg.Err
+		if msg.Err != nil {
+			return m, nil
+		}
+		hrChanged := hrValuesChanged(m.saved.Athlete, msg.Config.Athlete)
+		m.saved = msg.Config
+		m.status = "Saved."
+		if !msg.Persisted {
+			m.status = "Applied for this session; demo mode doesn't save settings."
+		}
+		if hrChanged {
+			m.status += " HR values changed: sync with \"Recompute metrics\" (7) to update past runs."
+		}
+
+	case tea.KeyMsg:
+		if m.editing {
+			submitted, cancelled := m.input.update(msg)
+			switch {
+			case cancelled:
+				m.editing = false
+			case submitted:
+				if err := settings[m.cursor].set(&m.cfg, m.input.value); err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.editing = false
+				m.err = nil
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(settings)-1 {
+				m.cursor++
+			}
+		case "enter", " ":
+			s := settings[m.cursor]
+			m.status = ""
+			if s.kind == settingNumber {
+				m.editing = true
+				m.input = textInput{value: s.get(&m.cfg)}
+				return m, nil
+			}
+			_ = s.set(&m.cfg, s.next(&m.cfg)) // choices and toggles can't fail
+		case "u":
+			m.cfg = m.saved
+			m.err = nil
+			m.status = "Changes discarded."
+		case "s":
+			return m.saveSettings()
+		}
+	}
+	return m, nil
+}
+
+// saveSettings validates the edited config and saves it
+func (m SettingsModel) saveSettings() (tea.Model, tea.Cmd) {
+	m.status = ""
+	if m.syncing {
+		m.err = fmt.Errorf("wait for the sync to finish before saving")
+		return m, nil
+	}
+	if err := m.cfg.ValidateSettings(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+
+	cfg, save := m.cfg, m.save
+	return m, func() tea.Msg {
+		if save == nil {
+			return SettingsSavedMsg{Config: cfg}
+		}
+		return SettingsSavedMsg{Config: cfg, Persisted: true, Err: save(&cfg)}
+	}
+}
+
+// View renders the settings screen
+func (m SettingsModel) View() string {
+	var lines []string
+	lines = append(lines, cardTitleStyle.Render("Settings"))
+
+	section := ""
+	for i, s := range settings {
+		if s.section != section {
+			section = s.section
+			lines = append(lines, "", tableHeaderStyle.Render(section))
+		}
+
+		value := s.get(&m.cfg)
+		if m.editing && i == m.cursor {
+			value = m.input.view()
+		}
+		if value != s.get(&m.saved) && !(m.editing && i == m.cursor) {
+			value += " *"
+		}
+
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		row := fmt.Sprintf("%s%-38s %s", cursor, s.label, value)
+		if i == m.cursor {
+			lines = append(lines, tableSelectedStyle.Render(row))
+		} else {
+			lines = append(lines, tableRowStyle.Render(row))
+		}
+	}
+	lines = append(lines, "")
+
+	if m.err != nil {
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  %v", m.err)), "")
+	} else if m.status != "" {
+		lines = append(lines, statusStyle.Render("  "+m.status), "")
+	}
+
+	help := "  enter: edit or switch  s: save  u: discard changes"
+	if m.editing {
+		help = "  enter: set  esc: cancel"
+	} else if m.dirty() {
+		help += "  (* unsaved)"
+	}
+	lines = append(lines, statusStyle.Render(help))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
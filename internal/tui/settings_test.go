@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"runner/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSettingsModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []string
+		saveErr   error
+		wantSaved bool
+		check     func(t *testing.T, cfg config.Config)
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "edit max HR and save",
+			keys:      []string{"j", "enter", "ctrl+u", "190", "enter", "s"},
+			wantSaved: true,
+			check: func(t *testing.T, cfg config.Config) {
+				if cfg.Athlete.MaxHR != 190 {
+					t.Errorf("saved MaxHR = %v, want 190", cfg.Athlete.MaxHR)
+				}
+			},
+			want: []string{"Saved.", "HR values changed"},
+		},
+		{
+			name:      "switch to miles",
+			keys:      []string{"j", "j", "j", "j", "j", "j", "j", "enter", "s"},
+			wantSaved: true,
+			check: func(t *testing.T, cfg config.Config) {
+				if cfg.Display.DistanceUnit != "mi" {
+					t.Errorf("saved DistanceUnit = %q, want mi", cfg.Display.DistanceUnit)
+				}
+			},
+			want: []string{"Saved."},
+		},
+		{
+			name: "invalid values are not saved",
+			keys: []string{"j", "j", "enter", "ctrl+u", "200", "enter", "s"},
+			want: []string{"must be less than athlete.max_hr"},
+		},
+		{
+			name:      "pace anchors are a comma-separated list",
+			keys:      []string{"j", "j", "j", "j", "enter", "140, 155,170", "enter", "s"},
+			wantSaved: true,
+			check: func(t *testing.T, cfg config.Config) {
+				if want := []float64{140, 155, 170}; !slices.Equal(cfg.Athlete.PaceAnchors, want) {
+					t.Errorf("saved PaceAnchors = %v, want %v", cfg.Athlete.PaceAnchors, want)
+				}
+			},
+			want: []string{"Saved.", "HR values changed"},
+		},
+		{
+			name: "numbers are checked as they are typed in",
+			keys: []string{"enter", "ctrl+u", "fifty", "enter"},
+			want: []string{`"fifty" is not a number`},
+		},
+		{
+			name:    "discard reverts edits",
+			keys:    []string{"j", "j", "j", "j", "j", "j", "j", "enter", "u"},
+			want:    []string{"Changes discarded."},
+			notWant: []string{"mi *", "unsaved"},
+		},
+		{
+			name:      "save errors are shown",
+			keys:      []string{"s"},
+			saveErr:   errors.New("disk full"),
+			wantSaved: true,
+			want:      []string{"disk full"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *config.Config
+			save := func(cfg *config.Config) error {
+				saved = cfg
+				return tt.saveErr
+			}
+
+			var m tea.Model = NewSettingsModel(config.DefaultConfig(), save)
+			for _, key := range tt.keys {
+				var cmd tea.Cmd
+				m, cmd = m.Update(keyMsg(key))
+				m = runCmd(m, cmd)
+			}
+
+			if (saved != nil) != tt.wantSaved {
+				t.Fatalf("saved = %v, want saved %v", saved != nil, tt.wantSaved)
+			}
+			if tt.check != nil {
+				tt.check(t, *saved)
+			}
+			view := m.View()
+			for _, s := range tt.want {
+				if !strings.Contains(view, s) {
+					t.Errorf("View() missing %q:\n%s", s, view)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(view, s) {
+					t.Errorf("View() contains %q:\n%s", s, view)
+				}
+			}
+		})
+	}
+}
+
+func TestSettingsModel_BlockedWhileSyncing(t *testing.T) {
+	m := NewSettingsModel(config.DefaultConfig(), func(*config.Config) error {
+		t.Error("saved during a sync")
+		return nil
+	})
+	m.syncing = true
+
+	model, cmd := m.Update(keyMsg("s"))
+	if cmd != nil {
+		t.Fatal("save command returned during a sync")
+	}
+	if view := model.View(); !strings.Contains(view, "wait for the sync to finish") {
+		t.Errorf("View() missing the sync warning:\n%s", view)
+	}
+}
+
+// keyMsg builds the key press for a key name, or for typed text
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "ctrl+u":
+		return tea.KeyMsg{Type: tea.KeyCtrlU}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(key)}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
@@ -0,0 +1,517 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"runner/internal/analysis"
+	"runner/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConnectFunc runs the Strava OAuth flow for the app credentials and stores
+// the tokens, calling showURL with the page the user must open
+type ConnectFunc func(ctx context.Context, creds config.StravaConfig, showURL func(authURL string)) error
+
+// setupStep is a page of the first-run setup
+type setupStep int
+
+const (
+	setupCredentials setupStep = iota
+	setupConnect
+	setupHeartRate
+	setupUnits
+	setupFinish
+)
+
+// setupHRPrompts are the heart rate questions, in order
+var setupHRPrompts = []string{
+	"Age (for estimates, blank to skip)",
+	"Max HR (bpm)",
+	"Resting HR (bpm)",
+	"Threshold HR (bpm)",
+}
+
+// setupUnitChoices are the unit systems offered, with their labels
+var setupUnitChoices = []struct {
+	units string
+	label string
+}{
+	{"metric", "Metric (km, min/km)"},
+	{"imperial", "Imperial (mi, min/mi)"},
+}
+
+// setupAuthURLMsg carries the page to open to authorize the app
+type setupAuthURLMsg struct {
+	url string
+}
+
+// setupConnectedMsg is sent when the OAuth flow ends
+type setupConnectedMsg struct {
+	err error
+}
+
+// setupSavedMsg is sent when the config was written
+type setupSavedMsg struct {
+	err error
+}
+
+// SetupModel walks a new user through first-run setup: Strava app
+// credentials, authorization, heart rate values and units. It saves the
+// config and quits, and the caller then starts the app.
+type SetupModel struct {
+	cfg     config.Config
+	connect ConnectFunc
+	save    func(*config.Config) error
+
+	step   setupStep
+	prompt int // question within the step
+	input  textInput
+	choice int // unit system under the cursor
+	err    error
+
+	// OAuth flow in progress
+	connecting bool
+	authURL    string
+	cancel     context.CancelFunc
+
+	// startSync is set when the user asked for a first sync, and finished
+	// once the config was saved
+	startSync bool
+	finished  bool
+}
+
+// NewSetupModel creates the setup wizard starting from cfg, normally the
+// defaults. connect authorizes the app and save writes the config file.
+func NewSetupModel(cfg config.Config, connect ConnectFunc, save func(*config.Config) error) SetupModel {
+	if !cfg.HasCredentials() {
+		cfg.Strava = config.StravaConfig{} // don't prefill placeholders
+	}
+	m := SetupModel{
+		cfg:     cfg,
+		connect: connect,
+		save:    save,
+	}
+	if cfg.Display.DistanceUnit == "mi" {
+		m.choice = 1
+	}
+	m.input = textInput{value: m.promptDefault()}
+	return m
+}
+
+// Result returns the saved config and whether a first sync was asked for.
+// ok is false if setup was quit before saving.
+func (m SetupModel) Result() (cfg config.Config, startSync, ok bool) {
+	return m.cfg, m.startSync, m.finished
+}
+
+// Init initializes the setup wizard
+func (m SetupModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages
+func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case setupAuthURLMsg:
+		m.authURL = msg.url
+		return m, nil
+
+	case setupConnectedMsg:
+		m.connecting = false
+		m.cancel = nil
+		m.err = msg.err
+		if msg.err == nil {
+			m.goTo(setupHeartRate)
+		}
+		return m, nil
+
+	case setupSavedMsg:
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		m.finished = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case setupCredentials, setupHeartRate:
+			return m.updatePrompt(msg)
+		case setupConnect:
+			return m.updateConnect(msg)
+		case setupUnits:
+			return m.updateUnits(msg)
+		case setupFinish:
+			return m.updateFinish(msg)
+		}
+	}
+	return m, nil
+}
+
+// goTo moves to the first question of a step
+func (m *SetupModel) goTo(step setupStep) {
+	m.step = step
+	m.prompt = 0
+	m.err = nil
+	m.input = textInput{value: m.promptDefault()}
+}
+
+// promptDefault is the answer prefilled for the current question: the value
+// already set, or an estimate
+func (m SetupModel) promptDefault() string {
+	formatHR := func(hr float64) string { return strconv.FormatFloat(hr, 'f', -1, 64) }
+	switch m.step {
+	case setupCredentials:
+		if m.prompt == 0 {
+			return m.cfg.Strava.ClientID
+		}
+		return m.cfg.Strava.ClientSecret
+	case setupHeartRate:
+		switch m.prompt {
+		case 1:
+			return formatHR(m.cfg.Athlete.MaxHR)
+		case 2:
+			return formatHR(m.cfg.Athlete.RestingHR)
+		case 3:
+			return formatHR(m.cfg.Athlete.ThresholdHR)
+		}
+	}
+	return ""
+}
+
+// updatePrompt handles typing an answer to the current question
+func (m SetupModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	if cancelled {
+		// Back one question, or to the previous step
+		switch {
+		case m.prompt > 0:
+			m.prompt--
+		case m.step == setupHeartRate:
+			m.step = setupCredentials
+			m.prompt = 1
+		}
+		m.err = nil
+		m.input = textInput{value: m.promptDefault()}
+		return m, nil
+	}
+	if !submitted {
+		return m, nil
+	}
+
+	value := strings.TrimSpace(m.input.value)
+	if err := m.answer(value); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+
+	last := len(setupHRPrompts) - 1
+	if m.step == setupCredentials {
+		last = 1
+	}
+	if m.prompt < last {
+		m.prompt++
+		m.input = textInput{value: m.promptDefault()}
+		return m, nil
+	}
+
+	if m.step == setupCredentials {
+		return m.startConnect()
+	}
+	if err := m.cfg.ValidateSettings(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.goTo(setupUnits)
+	return m, nil
+}
+
+// answer stores the answer to the current question
+func (m *SetupModel) answer(value string) error {
+	if m.step == setupCredentials {
+		if value == "" {
+			return fmt.Errorf("copy it from https://www.strava.com/settings/api")
+		}
+		if m.prompt == 0 {
+			m.cfg.Strava.ClientID = value
+		} else {
+			m.cfg.Strava.ClientSecret = value
+		}
+		return nil
+	}
+
+	if m.prompt == 0 && value == "" {
+		return nil // no age, keep the defaults
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("%q is not a positive number", value)
+	}
+	switch m.prompt {
+	case 0:
+		m.cfg.Athlete.MaxHR = analysis.EstimateMaxHR(n)
+		m.cfg.Athlete.ThresholdHR = analysis.EstimateThresholdHR(m.cfg.Athlete.MaxHR)
+	case 1:
+		if n != m.cfg.Athlete.MaxHR {
+			m.cfg.Athlete.MaxHR = n
+			m.cfg.Athlete.ThresholdHR = analysis.EstimateThresholdHR(n)
+		}
+	case 2:
+		m.cfg.Athlete.RestingHR = n
+	case 3:
+		m.cfg.Athlete.ThresholdHR = n
+	}
+	return nil
+}
+
+// startConnect runs the OAuth flow, showing its URL once it is listening
+func (m SetupModel) startConnect() (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	urls := make(chan string, 1)
+
+	m.step = setupConnect
+	m.connecting = true
+	m.authURL = ""
+	m.err = nil
+	m.cancel = cancel
+
+	connect, creds := m.connect, m.cfg.Strava
+	run := func() tea.Msg {
+		err := connect(ctx, creds, func(authURL string) {
+			select {
+			case urls <- authURL:
+			default:
+			}
+		})
+		close(urls)
+		return setupConnectedMsg{err: err}
+	}
+	waitURL := func() tea.Msg {
+		if url, ok := <-urls; ok {
+			return setupAuthURLMsg{url: url}
+		}
+		return nil
+	}
+	return m, tea.Batch(run, waitURL)
+}
+
+// updateConnect handles keys while authorizing, or after it failed
+func (m SetupModel) updateConnect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.connecting {
+		if msg.String() == "esc" && m.cancel != nil {
+			m.cancel()
+		}
+		return m, nil
+	}
+	switch msg.String() {
+	case "enter", "r":
+		return m.startConnect()
+	case "esc", "b":
+		m.goTo(setupCredentials)
+	}
+	return m, nil
+}
+
+// updateUnits handles choosing the unit system
+func (m SetupModel) updateUnits(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.choice > 0 {
+			m.choice--
+		}
+	case "down", "j":
+		if m.choice < len(setupUnitChoices)-1 {
+			m.choice++
+		}
+	case "esc":
+		m.goTo(setupHeartRate)
+	case "enter":
+		units := setupUnitChoices[m.choice].units
+		m.cfg.Display.Units = units
+		m.cfg.Display.DistanceUnit, m.cfg.Display.PaceUnit = "km", "min/km"
+		if units == "imperial" {
+			m.cfg.Display.DistanceUnit, m.cfg.Display.PaceUnit = "mi", "min/mi"
+		}
+		m.goTo(setupFinish)
+	}
+	return m, nil
+}
+
+// updateFinish saves the config, with or without a first sync
+func (m SetupModel) updateFinish(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.goTo(setupUnits)
+		return m, nil
+	case "enter", "s":
+		m.startSync = true
+	case "n":
+		m.startSync = false
+	default:
+		return m, nil
+	}
+
+	if err := m.cfg.Validate(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	cfg, save := m.cfg, m.save
+	return m, func() tea.Msg {
+		return setupSavedMsg{err: save(&cfg)}
+	}
+}
+
+// View renders the setup wizard
+func (m SetupModel) View() string {
+	var lines []string
+	lines = append(lines, headerStyle.Render("Welcome to Runner"), "")
+	lines = append(lines, m.renderSteps(), "")
+
+	switch m.step {
+	case setupCredentials:
+		lines = append(lines,
+			"  Runner reads your activities through your own Strava API application.",
+			"  Create one at https://www.strava.com/settings/api and set its",
+			"  Authorization Callback Domain to localhost, then copy its credentials.",
+			"")
+		lines = append(lines, m.renderPrompts([]string{"Client ID", "Client Secret"})...)
+	case setupConnect:
+		lines = append(lines, m.renderConnect()...)
+	case setupHeartRate:
+		lines = append(lines,
+			"  Heart rate values set your training zones, TRIMP and efficiency.",
+			"  Enter your age to estimate max HR (208 - 0.7 x age) and threshold",
+			"  (90% of max), or type values you have measured: max HR from an",
+			"  all-out effort, resting HR on waking, threshold HR as the average",
+			"  of the last 20 minutes of a 30-minute time trial.",
+			"")
+		lines = append(lines, m.renderPrompts(setupHRPrompts)...)
+	case setupUnits:
+		lines = append(lines, "  Show distances and paces in:", "")
+		for i, c := range setupUnitChoices {
+			cursor := "  "
+			if i == m.choice {
+				cursor = "> "
+			}
+			lines = append(lines, fmt.Sprintf("  %s%s", cursor, c.label))
+		}
+	case setupFinish:
+		lines = append(lines, m.renderSummary()...)
+	}
+
+	if m.err != nil {
+		lines = append(lines, "", errorStyle.Render(fmt.Sprintf("  %v", m.err)))
+	}
+	lines = append(lines, "", statusStyle.Render("  "+m.help()))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderSteps shows where the wizard is
+func (m SetupModel) renderSteps() string {
+	names := []string{"Strava app", "Connect", "Heart rate", "Units", "Finish"}
+	var parts []string
+	for i, name := range names {
+		if setupStep(i) == m.step {
+			parts = append(parts, navActiveStyle.Render(fmt.Sprintf("%d. %s", i+1, name)))
+		} else {
+			parts = append(parts, navInactiveStyle.Render(fmt.Sprintf("%d. %s", i+1, name)))
+		}
+	}
+	return "  " + strings.Join(parts, "  ")
+}
+
+// renderPrompts lists the questions answered so far and the current one
+func (m SetupModel) renderPrompts(prompts []string) []string {
+	var lines []string
+	for i := 0; i <= m.prompt && i < len(prompts); i++ {
+		value := m.input.view()
+		if i < m.prompt {
+			value = m.answered(i)
+		}
+		lines = append(lines, fmt.Sprintf("  %-36s %s", prompts[i]+":", value))
+	}
+	return lines
+}
+
+// answered returns the stored answer to an earlier question of the step
+func (m SetupModel) answered(prompt int) string {
+	if m.step == setupCredentials {
+		if prompt == 0 {
+			return m.cfg.Strava.ClientID
+		}
+		return strings.Repeat("*", len(m.cfg.Strava.ClientSecret))
+	}
+	switch prompt {
+	case 1:
+		return fmt.Sprintf("%.0f", m.cfg.Athlete.MaxHR)
+	case 2:
+		return fmt.Sprintf("%.0f", m.cfg.Athlete.RestingHR)
+	case 3:
+		return fmt.Sprintf("%.0f", m.cfg.Athlete.ThresholdHR)
+	}
+	return "-"
+}
+
+// renderConnect shows the OAuth flow's progress
+func (m SetupModel) renderConnect() []string {
+	switch {
+	case m.connecting && m.authURL == "":
+		return []string{"  Starting the authorization server..."}
+	case m.connecting:
+		return []string{
+			"  Open this URL in your browser and authorize the app:",
+			"",
+			"  " + m.authURL,
+			"",
+			"  Waiting for Strava...",
+		}
+	case m.err != nil:
+		return []string{"  Authorization failed. Check the credentials and the callback domain."}
+	}
+	return nil
+}
+
+// renderSummary lists the settings about to be saved
+func (m SetupModel) renderSummary() []string {
+	a := m.cfg.Athlete
+	return []string{
+		"  Connected to Strava. Your settings:",
+		"",
+		fmt.Sprintf("  Max HR %.0f, resting HR %.0f, threshold HR %.0f", a.MaxHR, a.RestingHR, a.ThresholdHR),
+		fmt.Sprintf("  Distances in %s, paces in %s", m.cfg.Display.DistanceUnit, m.cfg.Display.PaceUnit),
+		"",
+		"  They are saved to config.json and can be changed later",
+		"  from the settings screen (,). The first sync downloads your history",
+		"  and can take a while; it pauses at Strava's rate limits by itself.",
+	}
+}
+
+// help lists the keys for the current step
+func (m SetupModel) help() string {
+	switch m.step {
+	case setupConnect:
+		if m.connecting {
+			return "esc: cancel  ctrl+c: quit"
+		}
+		return "enter: try again  esc: edit credentials  ctrl+c: quit"
+	case setupUnits:
+		return "j/k: choose  enter: next  esc: back  ctrl+c: quit"
+	case setupFinish:
+		return "enter: save and start the first sync  n: save without syncing  esc: back"
+	}
+	return "enter: next  esc: back  ctrl+c: quit"
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"runner/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetupModel(t *testing.T) {
+	creds := []string{"12345", "enter", "s3cret", "enter"}
+	tests := []struct {
+		name       string
+		keys       []string
+		connectErr error
+		wantSaved  bool
+		wantSync   bool
+		check      func(t *testing.T, cfg config.Config)
+		want       []string
+	}{
+		{
+			name: "estimates from age, imperial, first sync",
+			keys: append(append([]string{}, creds...),
+				"40", "enter", "enter", "ctrl+u", "55", "enter", "enter", // age, max, resting, threshold
+				"j", "enter", // imperial
+				"enter"),
+			wantSaved: true,
+			wantSync:  true,
+			check: func(t *testing.T, cfg config.Config) {
+				if cfg.Strava.ClientID != "12345" || cfg.Strava.ClientSecret != "s3cret" {
+					t.Errorf("credentials = %+v", cfg.Strava)
+				}
+				if cfg.Athlete.MaxHR != 180 || cfg.Athlete.ThresholdHR != 162 || cfg.Athlete.RestingHR != 55 {
+					t.Errorf("athlete = %+v, want max 180, threshold 162, resting 55", cfg.Athlete)
+				}
+				if cfg.Display.Units != "imperial" || cfg.Display.DistanceUnit != "mi" || cfg.Display.PaceUnit != "min/mi" {
+					t.Errorf("display = %+v, want imperial", cfg.Display)
+				}
+			},
+		},
+		{
+			name: "measured max HR re-estimates threshold, no sync",
+			keys: append(append([]string{}, creds...),
+				"enter", "ctrl+u", "200", "enter", "enter", "enter",
+				"enter",
+				"n"),
+			wantSaved: true,
+			check: func(t *testing.T, cfg config.Config) {
+				if cfg.Athlete.MaxHR != 200 || cfg.Athlete.ThresholdHR != 180 {
+					t.Errorf("athlete = %+v, want max 200, threshold 180", cfg.Athlete)
+				}
+				if cfg.Display.DistanceUnit != "km" {
+					t.Errorf("DistanceUnit = %q, want km", cfg.Display.DistanceUnit)
+				}
+			},
+		},
+		{
+			name: "resting above threshold is rejected",
+			keys: append(append([]string{}, creds...),
+				"enter", "enter", "ctrl+u", "170", "enter", "enter"),
+			want: []string{"resting_hr"},
+		},
+		{
+			name:       "authorization failure can be retried",
+			keys:       creds,
+			connectErr: errors.New("access denied"),
+			want:       []string{"Authorization failed", "access denied", "enter: try again"},
+		},
+		{
+			name: "credentials are required",
+			keys: []string{"enter"},
+			want: []string{"strava.com/settings/api", "Client ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *config.Config
+			var connectedWith config.StravaConfig
+			connect := func(ctx context.Context, creds config.StravaConfig, showURL func(string)) error {
+				connectedWith = creds
+				showURL("https://www.strava.com/oauth/authorize?client_id=" + creds.ClientID)
+				return tt.connectErr
+			}
+			save := func(cfg *config.Config) error {
+				saved = cfg
+				return nil
+			}
+
+			var m tea.Model = NewSetupModel(config.DefaultConfig(), connect, save)
+			for _, key := range tt.keys {
+				var cmd tea.Cmd
+				m, cmd = m.Update(keyMsg(key))
+				m = runSetupCmd(m, cmd)
+			}
+
+			cfg, startSync, ok := m.(SetupModel).Result()
+			if ok != tt.wantSaved || (saved != nil) != tt.wantSaved {
+				t.Fatalf("finished = %v, saved = %v, want %v", ok, saved != nil, tt.wantSaved)
+			}
+			if startSync != tt.wantSync {
+				t.Errorf("startSync = %v, want %v", startSync, tt.wantSync)
+			}
+			if len(tt.keys) >= len(creds) && connectedWith.ClientID != "12345" {
+				t.Errorf("connected with %+v, want the typed credentials", connectedWith)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+
+			view := m.View()
+			for _, want := range tt.want {
+				if !strings.Contains(view, want) {
+					t.Errorf("view missing %q:\n%s", want, view)
+				}
+			}
+		})
+	}
+}
+
+// runSetupCmd runs a command and any it batches, in order, feeding their
+// messages back to the model
+func runSetupCmd(m tea.Model, cmd tea.Cmd) tea.Model {
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = runSetupCmd(m, c)
+		}
+	case tea.QuitMsg, nil:
+	default:
+		m, cmd = m.Update(msg)
+		return runSetupCmd(m, cmd)
+	}
+	return m
+}
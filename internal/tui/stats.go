@@ -11,7 +11,7 @@ import (
 
 // StatsModel is the period stats screen model
 type StatsModel struct {
-	queryService *service.QueryService
+	queryService QueryProvider
 	units        Units
 	stats        []service.PeriodStats
 	periodType   string // "weekly" or "monthly"
@@ -24,7 +24,7 @@ type StatsModel struct {
 }
 
 // NewStatsModel creates a new stats model
-func NewStatsModel(qs *service.QueryService, units Units) StatsModel {
+func NewStatsModel(qs QueryProvider, units Units) StatsModel {
 	return StatsModel{
 		queryService: qs,
 		units:        units,
@@ -207,12 +207,7 @@ func (m StatsModel) View() string {
 
 		distStr := "-"
 		if s.TotalMiles > 0 {
-			// TotalMiles is in miles, convert if needed
-			if m.units.IsMiles() {
-				distStr = fmt.Sprintf("%.1f", s.TotalMiles)
-			} else {
-				distStr = fmt.Sprintf("%.1f", s.TotalMiles*1.60934)
-			}
+			distStr = fmt.Sprintf("%.1f", m.units.FromMiles(s.TotalMiles))
 		}
 
 		paceStr := m.units.FormatPace(s.TotalMovingTime, s.TotalDistance)
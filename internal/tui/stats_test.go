@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"runner/internal/service"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStatsModel(t *testing.T) {
+	weeks := []service.PeriodStats{
+		{PeriodLabel: "Jan 08", RunCount: 0},
+		{PeriodLabel: "Jan 15", RunCount: 3, TotalMiles: 15, AvgHR: 148, TotalMovingTime: 9000, TotalDistance: 24140},
+	}
+	months := []service.PeriodStats{
+		{PeriodLabel: "Jan 2024", RunCount: 12, TotalMiles: 60, AvgHR: 150},
+	}
+
+	tests := []struct {
+		name     string
+		keys     []string
+		err      error
+		wantCall []string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "weekly hides empty weeks",
+			wantCall: []string{"weekly"},
+			want:     []string{"Period Stats (Weekly) - 1-1 of 1", "Jan 15", "148"},
+			notWant:  []string{"Jan 08"},
+		},
+		{
+			name:     "m switches to monthly",
+			keys:     []string{"m"},
+			wantCall: []string{"weekly", "monthly"},
+			want:     []string{"Period Stats (Monthly)", "Jan 2024"},
+		},
+		{
+			name:     "w when already weekly doesn't reload",
+			keys:     []string{"w"},
+			wantCall: []string{"weekly"},
+			want:     []string{"Period Stats (Weekly)"},
+		},
+		{
+			name:     "load errors are shown",
+			err:      errors.New("database locked"),
+			wantCall: []string{"weekly"},
+			want:     []string{"Error: database locked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qs := &fakeQueries{
+				periodStats: map[string][]service.PeriodStats{"weekly": weeks, "monthly": months},
+				periodErr:   tt.err,
+			}
+			var m tea.Model = NewStatsModel(qs, testUnits())
+			if got := m.View(); !strings.Contains(got, "Loading stats") {
+				t.Errorf("View() before load = %q, want loading", got)
+			}
+
+			m = runCmd(m, m.Init())
+			for _, key := range tt.keys {
+				var cmd tea.Cmd
+				m, cmd = m.Update(keyMsg(key))
+				m = runCmd(m, cmd)
+			}
+
+			if strings.Join(qs.calls, ",") != strings.Join(tt.wantCall, ",") {
+				t.Errorf("GetPeriodStats calls = %v, want %v", qs.calls, tt.wantCall)
+			}
+			view := m.View()
+			for _, s := range tt.want {
+				if !strings.Contains(view, s) {
+					t.Errorf("View() missing %q:\n%s", s, view)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(view, s) {
+					t.Errorf("View() contains %q:\n%s", s, view)
+				}
+			}
+		})
+	}
+}
+
+// runCmd runs a command synchronously and feeds its message back into the
+// model, the way the program loop would for a single load
+func runCmd(m tea.Model, cmd tea.Cmd) tea.Model {
+	if cmd == nil {
+		return m
+	}
+	m, _ = m.Update(cmd())
+	return m
+}
@@ -2,120 +2,169 @@ package tui
 
 import "github.com/charmbracelet/lipgloss"
 
-// Colors
+// Colors of the active theme; see ApplyTheme
 var (
-	primaryColor   = lipgloss.Color("#7C3AED") // Purple
-	secondaryColor = lipgloss.Color("#10B981") // Green
-	warningColor   = lipgloss.Color("#F59E0B") // Amber
-	errorColor     = lipgloss.Color("#EF4444") // Red
-	mutedColor     = lipgloss.Color("#6B7280") // Gray
-	bgColor        = lipgloss.Color("#1F2937") // Dark gray
-	textColor      = lipgloss.Color("#F9FAFB") // Light gray
+	primaryColor   lipgloss.Color
+	secondaryColor lipgloss.Color
+	warningColor   lipgloss.Color
+	errorColor     lipgloss.Color
+	infoColor      lipgloss.Color
+	accentColor    lipgloss.Color
+	mutedColor     lipgloss.Color
+	subtleColor    lipgloss.Color
+	bgColor        lipgloss.Color
+	textColor      lipgloss.Color
 )
 
-// Styles
+// Styles, rebuilt from the active theme by buildStyles
 var (
 	// App chrome
+	titleStyle  lipgloss.Style
+	headerStyle lipgloss.Style
+
+	// Navigation
+	navStyle         lipgloss.Style
+	navActiveStyle   lipgloss.Style
+	navInactiveStyle lipgloss.Style
+
+	// Cards and boxes
+	cardStyle      lipgloss.Style
+	cardTitleStyle lipgloss.Style
+
+	// Metrics
+	metricLabelStyle lipgloss.Style
+	metricValueStyle lipgloss.Style
+
+	// Trends
+	trendUpStyle   lipgloss.Style
+	trendDownStyle lipgloss.Style
+	trendFlatStyle lipgloss.Style
+
+	// Table
+	tableHeaderStyle   lipgloss.Style
+	tableRowStyle      lipgloss.Style
+	tableSelectedStyle lipgloss.Style
+
+	// Status
+	statusStyle  lipgloss.Style
+	errorStyle   lipgloss.Style
+	successStyle lipgloss.Style
+	warningStyle lipgloss.Style
+
+	// Help
+	helpKeyStyle  lipgloss.Style
+	helpDescStyle lipgloss.Style
+
+	// Progress bar
+	progressFullStyle  lipgloss.Style
+	progressEmptyStyle lipgloss.Style
+
+	// Training distribution bars
+	easyStyle lipgloss.Style // Z1-Z2
+	hardStyle lipgloss.Style // Z3+
+)
+
+func init() {
+	setTheme(themes[DefaultTheme])
+}
+
+// buildStyles rebuilds every shared style from the theme colors
+func buildStyles() {
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(1)
 
 	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(textColor).
-			Background(primaryColor).
-			Padding(0, 1).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(textColor).
+		Background(primaryColor).
+		Padding(0, 1).
+		MarginBottom(1)
 
-	// Navigation
 	navStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginBottom(1)
+		Foreground(mutedColor).
+		MarginBottom(1)
 
 	navActiveStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor)
+		Bold(true).
+		Foreground(primaryColor)
 
 	navInactiveStyle = lipgloss.NewStyle().
-				Foreground(mutedColor)
+		Foreground(mutedColor)
 
-	// Cards and boxes
 	cardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(mutedColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(mutedColor).
+		Padding(1, 2)
 
 	cardTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(1)
 
-	// Metrics
 	metricLabelStyle = lipgloss.NewStyle().
-				Foreground(mutedColor).
-				Width(20)
+		Foreground(mutedColor).
+		Width(20)
 
 	metricValueStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(textColor)
+		Bold(true).
+		Foreground(textColor)
 
-	// Trends
 	trendUpStyle = lipgloss.NewStyle().
-			Foreground(secondaryColor)
+		Foreground(secondaryColor)
 
 	trendDownStyle = lipgloss.NewStyle().
-			Foreground(errorColor)
+		Foreground(errorColor)
 
 	trendFlatStyle = lipgloss.NewStyle().
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
-	// Table
 	tableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(primaryColor).
-				BorderBottom(true).
-				BorderForeground(mutedColor).
-				Padding(0, 1)
+		Bold(true).
+		Foreground(primaryColor).
+		BorderBottom(true).
+		BorderForeground(mutedColor).
+		Padding(0, 1)
 
 	tableRowStyle = lipgloss.NewStyle().
-			Padding(0, 1)
+		Padding(0, 1)
 
 	tableSelectedStyle = lipgloss.NewStyle().
-				Bold(true).
-				Background(primaryColor).
-				Foreground(textColor).
-				Padding(0, 1)
+		Bold(true).
+		Background(primaryColor).
+		Foreground(textColor).
+		Padding(0, 1)
 
-	// Status
 	statusStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginTop(1)
+		Foreground(mutedColor).
+		MarginTop(1)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(errorColor)
+		Foreground(errorColor)
 
 	successStyle = lipgloss.NewStyle().
-			Foreground(secondaryColor)
+		Foreground(secondaryColor)
 
 	warningStyle = lipgloss.NewStyle().
-			Foreground(warningColor)
+		Foreground(warningColor)
 
-	// Help
 	helpKeyStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true)
+		Foreground(primaryColor).
+		Bold(true)
 
 	helpDescStyle = lipgloss.NewStyle().
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
-	// Progress bar
 	progressFullStyle = lipgloss.NewStyle().
-				Foreground(secondaryColor)
+		Foreground(secondaryColor)
 
 	progressEmptyStyle = lipgloss.NewStyle().
-				Foreground(mutedColor)
-)
+		Foreground(mutedColor)
+
+	easyStyle = lipgloss.NewStyle().Foreground(infoColor)
+	hardStyle = lipgloss.NewStyle().Foreground(errorColor)
+}
 
 // Helper functions
 
@@ -2,10 +2,14 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"runner/internal/service"
+	"runner/internal/store"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -13,23 +17,90 @@ import (
 
 // SyncModel is the sync screen model
 type SyncModel struct {
-	syncService *service.SyncService
+	syncService SyncRunner
+	units       Units
 	syncing     bool
 	result      *service.SyncResult
 	err         error
 	done        bool
+
+	// Phases chosen on the start prompt, indexed like syncSteps, and the
+	// row under the cursor. The row after the phases toggles recompute.
+	selected  [len(syncSteps)]bool
+	recompute bool
+	cursor    int
+
+	// Dry run shown on the start prompt
+	previewing bool
+	preview    *service.SyncPreview
+	previewErr error
+
+	// Sync another process is running against the same database, if any
+	elsewhere *store.SyncLock
+
+	// Live progress of the running sync, and whether it was cancelled
+	live      *syncLive
+	cancel    context.CancelFunc
+	cancelled bool
 }
 
-// NewSyncModel creates a new sync model
-func NewSyncModel(ss *service.SyncService) SyncModel {
-	return SyncModel{
+// syncSteps are the phases offered on the start prompt, in run order
+var syncSteps = [...]struct {
+	phase service.SyncPhase
+	label string
+}{
+	{service.PhaseActivities, "Fetch new activities from Strava"},
+	{service.PhaseStreams, "Download detailed stream data"},
+	{service.PhaseMetrics, "Compute fitness metrics"},
+	{service.PhasePersonalRecords, "Analyze personal records"},
+	{service.PhaseRaces, "Detect races"},
+	{service.PhasePredictions, "Update race predictions"},
+}
+
+// NewSyncModel creates a new sync model with every phase selected
+func NewSyncModel(ss SyncRunner, units Units) SyncModel {
+	m := SyncModel{
 		syncService: ss,
+		units:       units,
+	}
+	for i := range m.selected {
+		m.selected[i] = true
+	}
+	return m
+}
+
+// options returns the sync options chosen on the start prompt
+func (m SyncModel) options() service.SyncOptions {
+	opts := service.SyncOptions{RecomputeMetrics: m.recompute}
+	for i, step := range syncSteps {
+		if m.selected[i] {
+			opts.Phases = append(opts.Phases, step.phase)
+		}
 	}
+	return opts
 }
 
 // Init initializes the sync screen
 func (m SyncModel) Init() tea.Cmd {
-	return nil
+	if m.syncService == nil || m.syncing {
+		return nil
+	}
+	return m.checkElsewhere
+}
+
+// syncElsewhereMsg reports a sync running in another process
+type syncElsewhereMsg struct {
+	lock *store.SyncLock
+}
+
+// checkElsewhere looks for a sync running in another process, such as
+// `runner sync -every`, so the start prompt can warn before one is started
+func (m SyncModel) checkElsewhere() tea.Msg {
+	lock, err := m.syncService.SyncElsewhere()
+	if err != nil {
+		return syncElsewhereMsg{}
+	}
+	return syncElsewhereMsg{lock: lock}
 }
 
 // SyncDoneMsg is sent when sync finishes
@@ -38,39 +109,188 @@ type SyncDoneMsg struct {
 	Err    error
 }
 
+// SyncPreviewMsg is sent when a dry run finishes
+type SyncPreviewMsg struct {
+	Preview *service.SyncPreview
+	Err     error
+}
+
 // Update handles messages
 func (m SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case syncElsewhereMsg:
+		m.elsewhere = msg.lock
+		return m, nil
+
+	case SyncPreviewMsg:
+		m.previewing = false
+		m.preview = msg.Preview
+		m.previewErr = msg.Err
+		return m, nil
+
 	case SyncDoneMsg:
 		m.syncing = false
 		m.done = true
 		m.result = msg.Result
 		m.err = msg.Err
+		m.cancel = nil
+		m.syncService.Resume() // a paused sync that was cancelled
+		if m.live != nil {
+			m.live.finish(time.Now())
+		}
+		// Another process took the lock first: back to the start prompt,
+		// which says who holds it
+		var locked *store.SyncLockedError
+		if errors.As(m.err, &locked) {
+			m.done = false
+			m.err = nil
+			m.elsewhere = &locked.Lock
+			return m, nil
+		}
+		// Cancelled syncs keep what they stored; say so rather than erroring
+		m.cancelled = errors.Is(m.err, context.Canceled)
+		if m.cancelled {
+			m.err = nil
+			return m, nil
+		}
+		// Stay on the error report when something failed
+		if m.err == nil && m.result != nil && len(m.result.Failures) > 0 {
+			return m, nil
+		}
 		return m, func() tea.Msg { return SyncCompleteMsg{} }
 
+	case syncProgressMsg:
+		if m.live == nil || m.live.updates != msg.updates {
+			return m, nil // from an earlier sync
+		}
+		m.live.update(msg.progress, time.Now())
+		return m, waitForSyncProgress(msg.updates)
+
+	case syncTickMsg:
+		if !m.syncing || m.live == nil {
+			return m, nil
+		}
+		m.live.now = time.Time(msg)
+		return m, syncTick()
+
 	case tea.KeyMsg:
-		if !m.syncing {
+		if m.syncing {
+			switch msg.String() {
+			case "p", " ":
+				if m.syncService.Paused() {
+					m.syncService.Resume()
+				} else if m.cancel != nil {
+					m.syncService.Pause()
+				}
+			case "esc", "x", "ctrl+c":
+				if m.cancel != nil {
+					m.cancel()
+					m.cancel = nil
+				}
+			}
+			return m, nil
+		}
+		if !m.syncing && !m.previewing && m.syncService != nil {
 			switch msg.String() {
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case "down", "j":
+				if m.cursor < len(syncSteps) {
+					m.cursor++
+				}
+			case " ", "x":
+				if m.cursor < len(syncSteps) {
+					m.selected[m.cursor] = !m.selected[m.cursor]
+				} else {
+					m.recompute = !m.recompute
+				}
+			case "a":
+				// Select every phase, or none if all are selected
+				all := !slices.Contains(m.selected[:], false)
+				for i := range m.selected {
+					m.selected[i] = !all
+				}
 			case "enter", "s":
-				m.syncing = true
-				m.done = false
-				m.err = nil
-				m.result = nil
-				return m, m.runSync
+				if len(m.options().Phases) == 0 {
+					return m, nil
+				}
+				m.elsewhere = nil
+				return m.startSync()
+			case "d":
+				if !m.done && m.err == nil {
+					m.previewing = true
+					m.previewErr = nil
+					return m, m.runPreview
+				}
+			case "f":
+				if m.retryableCount() > 0 {
+					failures := m.result.Failures
+					ctx, cancel := context.WithCancel(context.Background())
+					m.syncing = true
+					m.done = false
+					m.cancelled = false
+					m.result = nil
+					m.live = nil
+					m.cancel = cancel
+					return m, m.runRetry(ctx, failures)
+				}
 			}
 		}
 	}
 	return m, nil
 }
 
-func (m SyncModel) runSync() tea.Msg {
-	ctx := context.Background()
+// startSync runs a sync with the chosen phases, streaming its progress to
+// the screen until it finishes or is cancelled
+func (m SyncModel) startSync() (SyncModel, tea.Cmd) {
+	opts := m.options()
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan service.SyncProgress, syncProgressBuffer)
+
+	m.syncing = true
+	m.done = false
+	m.cancelled = false
+	m.err = nil
+	m.result = nil
+	m.preview = nil
+	m.cancel = cancel
+	m.live = newSyncLive(opts, updates, m.syncService.RequestCount(), time.Now())
+
+	run := func() tea.Msg {
+		result, err := m.syncService.Sync(ctx, opts, updates)
+		return SyncDoneMsg{Result: result, Err: err}
+	}
+	return m, tea.Batch(run, waitForSyncProgress(updates), syncTick())
+}
+
+// runPreview runs a dry run of the sync
+func (m SyncModel) runPreview() tea.Msg {
+	preview, err := m.syncService.Preview(context.Background())
+	return SyncPreviewMsg{Preview: preview, Err: err}
+}
 
-	// Pass nil for progress channel - we're not showing real-time updates
-	// (the channel would block if buffer fills up)
-	result, syncErr := m.syncService.SyncAll(ctx, nil)
+// runRetry re-runs just the failed items of the last sync
+func (m SyncModel) runRetry(ctx context.Context, failures []service.SyncFailure) tea.Cmd {
+	return func() tea.Msg {
+		result, err := m.syncService.RetryFailed(ctx, failures)
+		return SyncDoneMsg{Result: result, Err: err}
+	}
+}
 
-	return SyncDoneMsg{Result: result, Err: syncErr}
+// retryableCount returns how many failures from the last run can be retried
+func (m SyncModel) retryableCount() int {
+	if m.result == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range m.result.Failures {
+		if f.Retryable() {
+			n++
+		}
+	}
+	return n
 }
 
 // View renders the sync screen
@@ -87,9 +307,24 @@ func (m SyncModel) View() string {
 	}
 
 	if m.done && !m.syncing {
-		sections = append(sections, successStyle.Render("\n  Sync complete!"))
+		if m.cancelled {
+			sections = append(sections, warningStyle.Render("\n  Sync cancelled."))
+			sections = append(sections, statusStyle.Render("  Everything fetched so far is saved; the next sync carries on from there."))
+		} else {
+			sections = append(sections, successStyle.Render("\n  Sync complete!"))
+		}
 		sections = append(sections, m.renderSummary())
-		sections = append(sections, "\n"+statusStyle.Render("  Press '1' to go to dashboard"))
+		if m.result != nil && len(m.result.Failures) > 0 {
+			sections = append(sections, m.renderFailures())
+		}
+		help := "  Press '1' to go to dashboard"
+		if m.cancelled {
+			help = "  s: resume sync  1: dashboard"
+		}
+		if m.retryableCount() > 0 {
+			help = "  f: retry failed  s: sync again  1: dashboard"
+		}
+		sections = append(sections, "\n"+statusStyle.Render(help))
 		return lipgloss.JoinVertical(lipgloss.Left, sections...)
 	}
 
@@ -105,38 +340,126 @@ func (m SyncModel) View() string {
 func (m SyncModel) renderStartPrompt() string {
 	var lines []string
 
+	if m.syncService == nil {
+		lines = append(lines, "")
+		lines = append(lines, "  Sync is off in demo and read-only mode.")
+		lines = append(lines, "")
+		lines = append(lines, statusStyle.Render("  Run runner without --demo or --read-only to sync with Strava."))
+		return strings.Join(lines, "\n")
+	}
+
 	lines = append(lines, "")
 	lines = append(lines, "  This will sync your Strava activities:")
 	lines = append(lines, "")
-	lines = append(lines, "  1. Fetch new activities from Strava")
-	lines = append(lines, "  2. Download detailed stream data")
-	lines = append(lines, "  3. Compute fitness metrics")
-	lines = append(lines, "  4. Analyze personal records")
+	for i, step := range syncSteps {
+		lines = append(lines, m.renderOption(i, m.selected[i], step.label))
+	}
+	lines = append(lines, m.renderOption(len(syncSteps), m.recompute, "Recompute metrics for all runs (after changing HR settings)"))
 	lines = append(lines, "")
 
 	// Show rate limit status
 	short, daily := m.syncService.RateLimitStatus()
 	lines = append(lines, statusStyle.Render(fmt.Sprintf("  API limits: %d/100 (15min), %d/1000 (daily)", short, daily)))
 	lines = append(lines, "")
-	lines = append(lines, statusStyle.Render("  Press 's' or Enter to start sync"))
+
+	switch {
+	case m.previewing:
+		lines = append(lines, "  Checking what a sync would fetch...", "")
+	case m.previewErr != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  Preview failed: %v", m.previewErr)), "")
+	case m.preview != nil:
+		lines = append(lines, m.renderPreview()...)
+	}
+
+	if m.elsewhere != nil {
+		lines = append(lines,
+			warningStyle.Render(fmt.Sprintf("  Sync in progress elsewhere (%s, started %s).",
+				m.elsewhere.Owner, m.elsewhere.StartedAt.Local().Format("15:04"))),
+			statusStyle.Render("  Wait for it to finish; starting a sync here checks again."), "")
+	}
+	if len(m.options().Phases) == 0 {
+		lines = append(lines, warningStyle.Render("  Select at least one phase to sync."), "")
+	}
+	lines = append(lines, statusStyle.Render("  s/Enter: start sync  space: toggle  a: all/none  d: dry run"))
 
 	return strings.Join(lines, "\n")
 }
 
-func (m SyncModel) renderProgress() string {
-	var lines []string
+// renderOption renders a checkbox row of the start prompt
+func (m SyncModel) renderOption(row int, checked bool, label string) string {
+	cursor := "  "
+	if row == m.cursor {
+		cursor = "> "
+	}
+	box := "[ ]"
+	if checked {
+		box = "[x]"
+	}
+	return fmt.Sprintf("  %s%s %s", cursor, box, label)
+}
 
+// renderPreview describes a dry run: what the sync would fetch, the
+// requests it needs against the remaining budget, and how long it would take
+func (m SyncModel) renderPreview() []string {
+	p := m.preview
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	since := "all history (first sync)"
+	if !p.Since.IsZero() {
+		since = "since " + p.Since.Local().Format("Jan 2, 2006 15:04")
+	}
+	more := ""
+	if p.MorePages {
+		more = "+"
+	}
+
+	lines := []string{cardTitleStyle.Render("  Dry Run"), ""}
+	lines = append(lines, fmt.Sprintf("  Activities:  %d%s %s, %d%s new runs with HR", p.Activities, more, since, p.NewRuns, more))
+
+	streams := fmt.Sprintf("  Streams:     %d this sync", p.StreamsThisSync)
+	if p.SyncsToFinish > 1 {
+		streams += fmt.Sprintf(" (%d%s to fetch, about %d%s syncs)", p.StreamsPending+p.NewRuns, more, p.SyncsToFinish, more)
+	}
+	lines = append(lines, streams)
+	if p.DetailsThisSync > 0 {
+		lines = append(lines, fmt.Sprintf("  Details:     %d this sync", p.DetailsThisSync))
+	}
+
+	lines = append(lines, fmt.Sprintf("  Requests:    ~%d%s of %d/%d (15min), %d/%d (daily) left",
+		p.Requests, more, p.ShortRemaining, p.ShortLimit, p.DailyRemaining, p.DailyLimit))
+
+	duration := "under a minute"
+	if p.Duration >= time.Minute {
+		duration = "about " + formatDuration(int(p.Duration.Seconds()))
+	}
+	lines = append(lines, fmt.Sprintf("  Duration:    %s", duration))
+
+	if p.ExceedsDaily() {
+		lines = append(lines, warningStyle.Render("  This sync needs more requests than remain today; it will pause until the daily limit resets."))
+	} else if p.Requests > p.ShortRemaining {
+		lines = append(lines, muted.Render("  Includes waits for the 15-minute rate limit to reset."))
+	}
+	if p.MorePages {
+		lines = append(lines, muted.Render("  More pages follow, so counts are lower bounds."))
+	}
 	lines = append(lines, "")
-	lines = append(lines, "  Syncing with Strava...")
-	lines = append(lines, "")
-	lines = append(lines, "  1. Fetching new activities")
-	lines = append(lines, "  2. Downloading stream data")
-	lines = append(lines, "  3. Computing fitness metrics")
-	lines = append(lines, "  4. Analyzing personal records")
-	lines = append(lines, "")
-	lines = append(lines, statusStyle.Render("  This may take a moment..."))
 
-	return strings.Join(lines, "\n")
+	return lines
+}
+
+func (m SyncModel) renderProgress() string {
+	if m.live == nil {
+		lines := []string{"", "  Retrying failed items...", ""}
+		lines = append(lines, statusStyle.Render("  esc: cancel"))
+		return strings.Join(lines, "\n")
+	}
+
+	paused := m.syncService.Paused()
+	var wait time.Time
+	if m.live.opts.NeedsStrava() {
+		wait = m.syncService.RateLimitWait()
+	}
+	return m.live.render(m.syncService, paused, wait, m.cancel == nil)
 }
 
 func (m SyncModel) renderSummary() string {
@@ -159,18 +482,221 @@ func (m SyncModel) renderSummary() string {
 		lines = append(lines, successStyle.Render(fmt.Sprintf("  %d streams downloaded", r.StreamsFetched)))
 	}
 
+	if r.DetailsFetched > 0 {
+		lines = append(lines, successStyle.Render(fmt.Sprintf("  %d activity details fetched", r.DetailsFetched)))
+	}
+
 	if r.MetricsComputed > 0 {
 		lines = append(lines, successStyle.Render(fmt.Sprintf("  %d metrics computed", r.MetricsComputed)))
 	}
 
-	if r.PRsComputed > 0 {
-		lines = append(lines, successStyle.Render(fmt.Sprintf("  %d personal records found", r.PRsComputed)))
+	if r.RacesFound > 0 {
+		lines = append(lines, successStyle.Render(fmt.Sprintf("  %d races detected", r.RacesFound)))
+	}
+
+	if len(r.NewRecords) > 0 {
+		lines = append(lines, "", m.renderNewRecords(r.NewRecords))
+	}
+
+	if len(r.Phases) > 0 {
+		lines = append(lines, "", renderPhaseTimings(r))
 	}
 
 	if len(r.Errors) > 0 {
 		lines = append(lines, "")
 		lines = append(lines, warningStyle.Render(fmt.Sprintf("  %d errors occurred", len(r.Errors))))
 	}
+	if r.MissingScope() {
+		lines = append(lines, errorStyle.Render("  Strava refused requests the token has no permission for."),
+			errorStyle.Render("  Quit and run runner auth, leaving every permission ticked on Strava's page."))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderPhaseTimings breaks the sync down by phase: time taken, API
+// requests, data stored and the rate limit used when it finished
+func renderPhaseTimings(r *service.SyncResult) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	api := false
+	for _, p := range r.Phases {
+		api = api || p.RateLimit != nil
+	}
+
+	header := fmt.Sprintf("  %-18s %8s %10s", "Phase", "Time", "Stored")
+	if api {
+		header += fmt.Sprintf(" %9s %11s %11s", "Requests", "15min", "Daily")
+	}
+	lines := []string{tableHeaderStyle.Render(header)}
+
+	row := func(label string, d time.Duration, bytes int64, requests int, limit *service.RateLimitSnapshot) string {
+		line := fmt.Sprintf("  %-18s %8s %10s", label, formatPhaseDuration(d), formatStoredBytes(bytes))
+		if api {
+			short, daily := "-", "-"
+			if limit != nil {
+				short = fmt.Sprintf("%d/%d", limit.ShortUsage, limit.ShortLimit)
+				daily = fmt.Sprintf("%d/%d", limit.DailyUsage, limit.DailyLimit)
+			}
+			line += fmt.Sprintf(" %9d %11s %11s", requests, short, daily)
+		}
+		return line
+	}
+	for _, p := range r.Phases {
+		lines = append(lines, muted.Render(row(syncPhaseLabels[string(p.Phase)], p.Duration, p.BytesStored, p.Requests, p.RateLimit)))
+	}
+	lines = append(lines, row("Total", r.Duration, r.BytesStored, r.Requests, nil))
+	return strings.Join(lines, "\n")
+}
+
+// formatPhaseDuration formats a phase's time, with tenths of a second for
+// the quick ones
+func formatPhaseDuration(d time.Duration) string {
+	if d < 10*time.Second {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return formatSyncDuration(d)
+}
+
+// formatStoredBytes formats a change in stored data as "+12.3 KB", or "-"
+// when nothing changed
+func formatStoredBytes(n int64) string {
+	if n == 0 {
+		return "-"
+	}
+	sign := "+"
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	switch {
+	case n < 1<<10:
+		return fmt.Sprintf("%s%d B", sign, n)
+	case n < 1<<20:
+		return fmt.Sprintf("%s%.1f KB", sign, float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%s%.1f MB", sign, float64(n)/(1<<20))
+}
+
+// renderNewRecords lists the records the sync improved, with how much each
+// beat the record that stood before
+func (m SyncModel) renderNewRecords(records []service.NewRecord) string {
+	title := "🏆 New personal record"
+	if len(records) > 1 {
+		title = fmt.Sprintf("🏆 %d new personal records", len(records))
+	}
+	lines := []string{cardTitleStyle.Render(title)}
+	for _, r := range records {
+		kind := "PR"
+		if strings.HasPrefix(r.Record.Category, "effort_") {
+			kind = "best effort"
+		}
+		line := fmt.Sprintf("New %s %s: %s, %s", r.Label(), kind, m.recordValue(r), m.recordImprovement(r))
+		if r.Record.Unverified {
+			line += warningStyle.Render(" ⚠ unverified")
+		}
+		lines = append(lines, successStyle.Render(line))
+	}
+	return lipgloss.NewStyle().MarginLeft(2).Render(
+		cardStyle.BorderForeground(secondaryColor).Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
+
+// recordValue formats the result that set a record
+func (m SyncModel) recordValue(r service.NewRecord) string {
+	switch {
+	case r.Record.Category == "highest_elevation":
+		return fmt.Sprintf("%.0f m", r.Record.DistanceMeters)
+	case r.Mode == store.CompareDistance:
+		return m.units.FormatDistance(r.Record.DistanceMeters)
+	case r.Mode == store.ComparePace && r.Record.PacePerMile != nil:
+		return m.units.FormatPacePerMile(*r.Record.PacePerMile)
+	default:
+		return formatClock(r.Record.DurationSeconds)
+	}
+}
+
+// recordImprovement says how much a record beat the previous one and when
+// that was set, e.g. "27s faster than Mar 2024"
+func (m SyncModel) recordImprovement(r service.NewRecord) string {
+	if r.Previous == nil {
+		return "first record"
+	}
+	margin := r.Margin()
+	var gain string
+	switch {
+	case r.Record.Category == "highest_elevation":
+		gain = fmt.Sprintf("%.0f m more climbing", margin)
+	case r.Mode == store.CompareDistance:
+		gain = m.units.FormatDistance(margin) + " further"
+	case r.Mode == store.ComparePace:
+		gain = m.units.FormatPacePerMile(margin) + " faster"
+	case margin < 60:
+		gain = fmt.Sprintf("%.0fs faster", margin)
+	default:
+		gain = formatClock(int(margin+0.5)) + " faster"
+	}
+	return fmt.Sprintf("%s than %s", gain, r.Previous.AchievedAt.Format("Jan 2006"))
+}
+
+// syncPhaseLabels names the sync phases in the error report
+var syncPhaseLabels = map[string]string{
+	"activities":       "Activities",
+	"streams":          "Streams",
+	"details":          "Activity details",
+	"metrics":          "Metrics",
+	"personal_records": "Personal records",
+	"races":            "Races",
+	"predictions":      "Predictions",
+}
+
+// maxFailuresShown caps the error report so it fits on screen
+const maxFailuresShown = 15
+
+// renderFailures lists what failed in the last sync, grouped by phase
+func (m SyncModel) renderFailures() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	var phases []string
+	byPhase := make(map[string][]service.SyncFailure)
+	for _, f := range m.result.Failures {
+		if _, ok := byPhase[f.Phase]; !ok {
+			phases = append(phases, f.Phase)
+		}
+		byPhase[f.Phase] = append(byPhase[f.Phase], f)
+	}
+
+	lines := []string{""}
+	shown := 0
+	for _, phase := range phases {
+		failures := byPhase[phase]
+		label := syncPhaseLabels[phase]
+		if label == "" {
+			label = phase
+		}
+		lines = append(lines, warningStyle.Render(fmt.Sprintf("  %s (%d failed)", label, len(failures))))
+
+		for _, f := range failures {
+			if shown == maxFailuresShown {
+				break
+			}
+			shown++
+
+			item := "general"
+			if f.ActivityID != 0 {
+				item = fmt.Sprintf("%s (#%d)", truncateName(f.ActivityName, 30), f.ActivityID)
+			}
+			marker := "  "
+			if !f.Retryable() {
+				marker = "- " // can't be retried
+			}
+			lines = append(lines, fmt.Sprintf("    %s%s  %s", marker, item, muted.Render(truncateName(f.Err.Error(), 80))))
+		}
+	}
+
+	if hidden := len(m.result.Failures) - shown; hidden > 0 {
+		lines = append(lines, muted.Render(fmt.Sprintf("    ...and %d more (see the logs screen)", hidden)))
+	}
+	if m.retryableCount() < len(m.result.Failures) {
+		lines = append(lines, muted.Render("    Items marked - can't be retried on their own; run a full sync."))
+	}
 
 	return strings.Join(lines, "\n")
 }
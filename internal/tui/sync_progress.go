@@ -0,0 +1,243 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"runner/internal/service"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// syncProgressBuffer lets the sync run ahead of the screen without blocking
+// on every update
+const syncProgressBuffer = 64
+
+// syncBarWidth is the width of a phase's progress bar
+const syncBarWidth = 30
+
+// syncProgressMsg carries one update from a running sync
+type syncProgressMsg struct {
+	progress service.SyncProgress
+	updates  <-chan service.SyncProgress
+}
+
+// syncTickMsg redraws the elapsed time and ETA while a sync runs
+type syncTickMsg time.Time
+
+// waitForSyncProgress reads the next update from a running sync. It returns
+// no message once the sync closes the channel.
+func waitForSyncProgress(updates <-chan service.SyncProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return syncProgressMsg{progress: p, updates: updates}
+	}
+}
+
+func syncTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return syncTickMsg(t)
+	})
+}
+
+// phaseProgress is how far one phase has got
+type phaseProgress struct {
+	total     int
+	completed int
+	current   string         // activity being worked on
+	workers   map[int]string // activity each worker is on, in phases that analyze runs in parallel
+	started   time.Time
+	finished  time.Time
+}
+
+// syncLive tracks a running sync's progress for display
+type syncLive struct {
+	opts          service.SyncOptions
+	updates       <-chan service.SyncProgress
+	phases        map[string]*phaseProgress
+	active        string
+	errors        int
+	requestsStart int
+	started       time.Time
+	now           time.Time
+}
+
+func newSyncLive(opts service.SyncOptions, updates <-chan service.SyncProgress, requests int, now time.Time) *syncLive {
+	return &syncLive{
+		opts:          opts,
+		updates:       updates,
+		phases:        make(map[string]*phaseProgress),
+		requestsStart: requests,
+		started:       now,
+		now:           now,
+	}
+}
+
+// update applies a progress update. A phase is finished once a later one
+// starts, since not every phase reports its own end.
+func (l *syncLive) update(p service.SyncProgress, now time.Time) {
+	l.now = now
+	if p.Error != nil {
+		l.errors++
+		return
+	}
+
+	phase, ok := l.phases[p.Phase]
+	if !ok {
+		if prev := l.phases[l.active]; prev != nil && prev.finished.IsZero() {
+			prev.finished = now
+		}
+		phase = &phaseProgress{started: now}
+		l.phases[p.Phase] = phase
+		l.active = p.Phase
+	}
+	// Workers only say what they picked up; the count comes from saved runs
+	if p.Worker > 0 {
+		if phase.workers == nil {
+			phase.workers = make(map[int]string)
+		}
+		phase.workers[p.Worker] = p.CurrentActivity
+		return
+	}
+	phase.total = p.Total
+	phase.completed = p.Completed
+	phase.current = p.CurrentActivity
+}
+
+// finish marks every started phase finished when the sync ends
+func (l *syncLive) finish(now time.Time) {
+	l.now = now
+	for _, phase := range l.phases {
+		if phase.finished.IsZero() {
+			phase.finished = now
+		}
+	}
+}
+
+// render draws each selected phase with a progress bar and timing, then the
+// API budget and the keys that control the sync
+func (l *syncLive) render(ss SyncRunner, paused bool, rateLimitWait time.Time, cancelling bool) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+
+	heading := "  Syncing with Strava..."
+	if !l.opts.NeedsStrava() {
+		heading = "  Updating local analysis..."
+	}
+	lines := []string{"", heading + muted.Render("  elapsed "+formatSyncDuration(l.now.Sub(l.started))), ""}
+
+	for _, step := range syncSteps {
+		if !l.opts.Runs(step.phase) {
+			continue
+		}
+		lines = append(lines, l.renderPhase(string(step.phase))...)
+	}
+	lines = append(lines, "")
+
+	if l.opts.NeedsStrava() {
+		short, daily := ss.RateLimitStatus()
+		shortLimit, dailyLimit := ss.RateLimits()
+		requests := ss.RequestCount() - l.requestsStart
+		lines = append(lines, statusStyle.Render(fmt.Sprintf("  Requests: %d this sync   API used: %d/%d (15min), %d/%d (daily)",
+			requests, shortLimit-short, shortLimit, dailyLimit-daily, dailyLimit)))
+		if last := lastRequest(ss.RecentRequests()); last != "" {
+			lines = append(lines, statusStyle.Render("  "+last))
+		}
+		if !rateLimitWait.IsZero() && rateLimitWait.After(l.now) {
+			lines = append(lines, warningStyle.Render(fmt.Sprintf("  Rate limit reached, carrying on in %s", formatSyncDuration(rateLimitWait.Sub(l.now)))))
+		}
+	}
+	if l.errors > 0 {
+		lines = append(lines, warningStyle.Render(fmt.Sprintf("  %d errors so far", l.errors)))
+	}
+
+	switch {
+	case cancelling:
+		lines = append(lines, "", warningStyle.Render("  Cancelling..."))
+	case paused:
+		lines = append(lines, "", warningStyle.Render("  Paused"), statusStyle.Render("  p: resume  esc: cancel"))
+	default:
+		lines = append(lines, "", statusStyle.Render("  p: pause  esc: cancel"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderPhase draws one phase's row, with the activity being worked on
+// beneath it while the phase runs
+func (l *syncLive) renderPhase(name string) []string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	label := fmt.Sprintf("  %-18s", syncPhaseLabels[name])
+
+	phase, ok := l.phases[name]
+	if !ok {
+		return []string{label + muted.Render("waiting")}
+	}
+	done := !phase.finished.IsZero()
+
+	// Activity pages come in until one is short, so there's no total to
+	// draw a bar against
+	if name == string(service.PhaseActivities) {
+		status := fmt.Sprintf("%d fetched, %d runs stored", phase.total, phase.completed)
+		if done {
+			status += muted.Render("  " + formatSyncDuration(phase.finished.Sub(phase.started)))
+		}
+		return []string{label + status}
+	}
+
+	pct := 1.0
+	if phase.total > 0 && !done {
+		pct = float64(phase.completed) / float64(phase.total)
+	}
+	row := label + RenderProgressBar(pct, syncBarWidth)
+	if phase.total > 1 {
+		completed := phase.completed
+		if done {
+			completed = phase.total
+		}
+		row += fmt.Sprintf(" %d/%d", completed, phase.total)
+	}
+
+	if done {
+		return []string{row + muted.Render("  done in "+formatSyncDuration(phase.finished.Sub(phase.started)))}
+	}
+	if eta, ok := phase.eta(l.now); ok {
+		row += muted.Render("  ETA " + formatSyncDuration(eta))
+	}
+	lines := []string{row}
+	if len(phase.workers) > 0 {
+		for _, w := range slices.Sorted(maps.Keys(phase.workers)) {
+			lines = append(lines, muted.Render(fmt.Sprintf("  %-18s%d: %s", "", w, truncateName(phase.workers[w], 40))))
+		}
+	} else if phase.current != "" {
+		lines = append(lines, muted.Render(fmt.Sprintf("  %-18s%s", "", truncateName(phase.current, 40))))
+	}
+	return lines
+}
+
+// eta extrapolates the phase's remaining time from its pace so far
+func (p *phaseProgress) eta(now time.Time) (time.Duration, bool) {
+	if p.completed == 0 || p.total <= p.completed {
+		return 0, false
+	}
+	perItem := now.Sub(p.started) / time.Duration(p.completed)
+	return perItem * time.Duration(p.total-p.completed), true
+}
+
+// formatSyncDuration formats a duration as "45s", "3m12s" or "1h04m"
+func formatSyncDuration(d time.Duration) string {
+	secs := int(d.Round(time.Second).Seconds())
+	switch {
+	case secs < 60:
+		return fmt.Sprintf("%ds", secs)
+	case secs < 3600:
+		return fmt.Sprintf("%dm%02ds", secs/60, secs%60)
+	}
+	return fmt.Sprintf("%dh%02dm", secs/3600, (secs%3600)/60)
+}
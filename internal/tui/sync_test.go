@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"runner/internal/service"
+	"runner/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+func TestSyncModel_DryRun(t *testing.T) {
+	ss := &fakeSync{preview: &service.SyncPreview{
+		Activities:      7,
+		NewRuns:         5,
+		StreamsThisSync: 5,
+		Requests:        6,
+		ShortRemaining:  90,
+		ShortLimit:      100,
+		DailyRemaining:  950,
+		DailyLimit:      1000,
+	}}
+
+	tm := teatest.NewTestModel(t, NewSyncModel(ss, testUnits()), teatest.WithInitialTermSize(120, 40))
+	t.Cleanup(func() { _ = tm.Quit() })
+
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("d: dry run"))
+	}, teatest.WithDuration(3*time.Second))
+
+	tm.Send(keyMsg("d"))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("7 all history (first sync), 5 new runs with HR"))
+	}, teatest.WithDuration(3*time.Second))
+}
+
+func TestSyncModel_StartPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		ss   SyncRunner
+		keys []string
+		want []string
+	}{
+		{
+			name: "demo mode has no sync",
+			want: []string{"Sync is off in demo and read-only mode."},
+		},
+		{
+			name: "every phase starts selected",
+			ss:   &fakeSync{},
+			want: []string{"> [x] Fetch new activities from Strava", "[x] Update race predictions", "API limits: 90/100 (15min), 950/1000 (daily)"},
+		},
+		{
+			name: "a clears every phase",
+			ss:   &fakeSync{},
+			keys: []string{"a"},
+			want: []string{"> [ ] Fetch new activities from Strava", "Select at least one phase to sync."},
+		},
+		{
+			name: "space toggles the phase under the cursor",
+			ss:   &fakeSync{},
+			keys: []string{"j", " "},
+			want: []string{"  [x] Fetch new activities from Strava", "> [ ] Download detailed stream data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m tea.Model = NewSyncModel(tt.ss, testUnits())
+			for _, key := range tt.keys {
+				m, _ = m.Update(keyMsg(key))
+			}
+			view := m.View()
+			for _, s := range tt.want {
+				if !strings.Contains(view, s) {
+					t.Errorf("View() missing %q:\n%s", s, view)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncModel_SyncElsewhere(t *testing.T) {
+	lock := store.SyncLock{Owner: "laptop:4182", StartedAt: time.Now()}
+	ss := &fakeSync{elsewhere: &lock}
+
+	m := NewSyncModel(ss, testUnits())
+	updated, _ := m.Update(m.Init()())
+	if view := updated.View(); !strings.Contains(view, "Sync in progress elsewhere (laptop:4182") {
+		t.Errorf("View() missing the sync elsewhere warning:\n%s", view)
+	}
+
+	// A sync refused by the lock returns to the prompt rather than erroring
+	m = NewSyncModel(&fakeSync{}, testUnits())
+	updated, _ = m.Update(SyncDoneMsg{Err: &store.SyncLockedError{Lock: lock}})
+	view := updated.View()
+	if strings.Contains(view, "Error:") || !strings.Contains(view, "Sync in progress elsewhere (laptop:4182") {
+		t.Errorf("View() after a locked sync:\n%s", view)
+	}
+}
+
+func TestSyncModel_PhaseTimings(t *testing.T) {
+	result := &service.SyncResult{
+		ActivitiesStored: 2,
+		Phases: []service.PhaseTiming{
+			{Phase: service.PhaseActivities, Duration: 1200 * time.Millisecond, Requests: 3, BytesStored: 8 << 10,
+				RateLimit: &service.RateLimitSnapshot{ShortUsage: 3, ShortLimit: 100, DailyUsage: 41, DailyLimit: 1000}},
+			{Phase: service.PhaseStreams, Duration: 95 * time.Second, Requests: 2, BytesStored: 3 << 20,
+				RateLimit: &service.RateLimitSnapshot{ShortUsage: 5, ShortLimit: 100, DailyUsage: 43, DailyLimit: 1000}},
+			{Phase: service.PhaseMetrics, Duration: 300 * time.Millisecond},
+		},
+		Duration:    97 * time.Second,
+		Requests:    5,
+		BytesStored: 3<<20 + 8<<10,
+	}
+
+	m := NewSyncModel(&fakeSync{}, testUnits())
+	updated, _ := m.Update(SyncDoneMsg{Result: result})
+	view := updated.View()
+	for _, want := range []string{"Activities", "1.2s", "+8.0 KB", "3/100", "41/1000", "1m35s", "+3.0 MB", "43/1000", "Total", "1m37s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q:\n%s", want, view)
+		}
+	}
+
+	if got := formatStoredBytes(-512); got != "-512 B" {
+		t.Errorf("formatStoredBytes(-512) = %q", got)
+	}
+	if got := formatStoredBytes(0); got != "-" {
+		t.Errorf("formatStoredBytes(0) = %q", got)
+	}
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+
+	"runner/internal/config"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// DefaultTheme is used when display.theme isn't set
+const DefaultTheme = "dark"
+
+// Theme is a color scheme. Every shared style and chart series takes its
+// color from the active theme, so switching themes changes the whole TUI.
+type Theme struct {
+	Primary    lipgloss.Color // titles, navigation, selection
+	Success    lipgloss.Color // improving trends, good values
+	Warning    lipgloss.Color
+	Error      lipgloss.Color // worsening trends, errors
+	Info       lipgloss.Color // easy effort
+	Accent     lipgloss.Color // hardest effort
+	Muted      lipgloss.Color // labels, borders, help text
+	Subtle     lipgloss.Color // legends
+	Text       lipgloss.Color
+	Background lipgloss.Color
+
+	// Chart series are drawn in ANSI colors
+	ChartHighlight  asciigraph.AnsiColor // a second series to compare
+	ChartProjection asciigraph.AnsiColor
+	ChartBand       asciigraph.AnsiColor // uncertainty bands
+	ChartAlert      asciigraph.AnsiColor // overlays such as injuries
+}
+
+// themes holds the built-in themes by config name
+var themes = map[string]Theme{
+	"dark": {
+		Primary:         "#7C3AED", // Purple
+		Success:         "#10B981", // Green
+		Warning:         "#F59E0B", // Amber
+		Error:           "#EF4444", // Red
+		Info:            "#3B82F6", // Blue
+		Accent:          "#9333EA", // Deep purple
+		Muted:           "#6B7280", // Gray
+		Subtle:          "#9CA3AF", // Light gray
+		Text:            "#F9FAFB", // Near white
+		Background:      "#1F2937", // Dark gray
+		ChartHighlight:  asciigraph.Green,
+		ChartProjection: asciigraph.Yellow,
+		ChartBand:       asciigraph.DarkGray,
+		ChartAlert:      asciigraph.Red,
+	},
+	// Darker shades that keep contrast on a light terminal background
+	"light": {
+		Primary:         "#6D28D9",
+		Success:         "#047857",
+		Warning:         "#B45309",
+		Error:           "#B91C1C",
+		Info:            "#1D4ED8",
+		Accent:          "#7E22CE",
+		Muted:           "#4B5563",
+		Subtle:          "#6B7280",
+		Text:            "#111827",
+		Background:      "#E5E7EB",
+		ChartHighlight:  asciigraph.DarkGreen,
+		ChartProjection: asciigraph.DarkOrange,
+		ChartBand:       asciigraph.Gray,
+		ChartAlert:      asciigraph.DarkRed,
+	},
+	// Fully saturated colors on black
+	"high-contrast": {
+		Primary:         "#00FFFF",
+		Success:         "#00FF00",
+		Warning:         "#FFFF00",
+		Error:           "#FF0000",
+		Info:            "#00BFFF",
+		Accent:          "#FF00FF",
+		Muted:           "#C0C0C0",
+		Subtle:          "#FFFFFF",
+		Text:            "#FFFFFF",
+		Background:      "#000000",
+		ChartHighlight:  asciigraph.Lime,
+		ChartProjection: asciigraph.Yellow,
+		ChartBand:       asciigraph.Silver,
+		ChartAlert:      asciigraph.Red,
+	},
+	// The Okabe-Ito palette, told apart with any color vision. Good and bad
+	// are blue and vermilion rather than green and red.
+	"colorblind": {
+		Primary:         "#CC79A7",
+		Success:         "#56B4E9",
+		Warning:         "#E69F00",
+		Error:           "#D55E00",
+		Info:            "#0072B2",
+		Accent:          "#F0E442",
+		Muted:           "#999999",
+		Subtle:          "#BBBBBB",
+		Text:            "#F9FAFB",
+		Background:      "#1F2937",
+		ChartHighlight:  asciigraph.SkyBlue,
+		ChartProjection: asciigraph.Orange,
+		ChartBand:       asciigraph.DarkGray,
+		ChartAlert:      asciigraph.DarkOrange,
+	},
+}
+
+// activeTheme is the theme the styles were last built from
+var activeTheme Theme
+
+// ApplyTheme switches to the configured theme with any color overrides. An
+// unknown theme or color name is an error and leaves the theme unchanged;
+// config validation normally catches these first.
+func ApplyTheme(display config.DisplayConfig) error {
+	name := display.Theme
+	if name == "" {
+		name = DefaultTheme
+	}
+	theme, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+
+	for role, hex := range display.Colors {
+		if !config.IsHexColor(hex) {
+			return fmt.Errorf("invalid color %q for %s", hex, role)
+		}
+		c := lipgloss.Color(hex)
+		switch role {
+		case "primary":
+			theme.Primary = c
+		case "success":
+			theme.Success = c
+		case "warning":
+			theme.Warning = c
+		case "error":
+			theme.Error = c
+		case "info":
+			theme.Info = c
+		case "accent":
+			theme.Accent = c
+		case "muted":
+			theme.Muted = c
+		case "subtle":
+			theme.Subtle = c
+		case "text":
+			theme.Text = c
+		case "background":
+			theme.Background = c
+		default:
+			return fmt.Errorf("unknown theme color %q", role)
+		}
+	}
+
+	setTheme(theme)
+	return nil
+}
+
+// setTheme makes a theme active and rebuilds the styles from it
+func setTheme(t Theme) {
+	activeTheme = t
+	primaryColor = t.Primary
+	secondaryColor = t.Success
+	warningColor = t.Warning
+	errorColor = t.Error
+	infoColor = t.Info
+	accentColor = t.Accent
+	mutedColor = t.Muted
+	subtleColor = t.Subtle
+	textColor = t.Text
+	bgColor = t.Background
+	buildStyles()
+}
+
+// hrZoneColors returns the colors of heart rate zones 1-5, from recovery to
+// VO2max
+func hrZoneColors() []lipgloss.Color {
+	return []lipgloss.Color{secondaryColor, infoColor, warningColor, errorColor, accentColor}
+}
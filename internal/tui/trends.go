@@ -0,0 +1,516 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"runner/internal/analysis"
+	"runner/internal/service"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// trendsChartHeight is the height of the long-horizon charts in rows
+const trendsChartHeight = 6
+
+// trendsDefaultHorizon indexes service.TrendHorizons: a year
+const trendsDefaultHorizon = 1
+
+// TrendsModel is the long-horizon trends screen model. The longest horizon
+// is loaded once, so switching horizons only redraws; the durability and
+// feel scatters are refitted to each.
+type TrendsModel struct {
+	queryService QueryProvider
+	units        Units
+	months       []service.MonthTrend
+	horizon      int // index into service.TrendHorizons
+	durability   *service.DurabilityData
+	durErr       error
+	byDistance   bool // durability against distance rather than duration
+	feel         *service.FeelVsFormData
+	feelErr      error
+	ramp         *service.RampData
+	rampErr      error
+	viewport     viewport.Model
+	loading      bool
+	err          error
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewTrendsModel creates a new trends model
+func NewTrendsModel(qs QueryProvider, units Units, width, height int) TrendsModel {
+	m := TrendsModel{
+		queryService: qs,
+		units:        units,
+		horizon:      trendsDefaultHorizon,
+		loading:      true,
+		width:        width,
+		height:       height,
+	}
+
+	if width > 0 && height > 0 {
+		m.viewport = viewport.New(width, height-6)
+		m.ready = true
+	}
+
+	return m
+}
+
+// Init initializes the trends screen
+func (m TrendsModel) Init() tea.Cmd {
+	return tea.Batch(m.loadTrends, m.loadDurability, m.loadFeel, m.loadRamp)
+}
+
+type trendsLoadedMsg struct {
+	months []service.MonthTrend
+	err    error
+}
+
+func (m TrendsModel) loadTrends() tea.Msg {
+	longest := service.TrendHorizons[len(service.TrendHorizons)-1]
+	months, err := m.queryService.GetTrends(longest)
+	return trendsLoadedMsg{months: months, err: err}
+}
+
+type durabilityLoadedMsg struct {
+	data *service.DurabilityData
+	err  error
+}
+
+func (m TrendsModel) loadDurability() tea.Msg {
+	data, err := m.queryService.GetDurability(service.TrendHorizons[m.horizon])
+	return durabilityLoadedMsg{data: data, err: err}
+}
+
+type feelLoadedMsg struct {
+	data *service.FeelVsFormData
+	err  error
+}
+
+func (m TrendsModel) loadFeel() tea.Msg {
+	data, err := m.queryService.GetFeelVsForm(service.TrendHorizons[m.horizon])
+	return feelLoadedMsg{data: data, err: err}
+}
+
+type rampLoadedMsg struct {
+	data *service.RampData
+	err  error
+}
+
+func (m TrendsModel) loadRamp() tea.Msg {
+	data, err := m.queryService.GetMileageRamp(service.TrendHorizons[m.horizon])
+	return rampLoadedMsg{data: data, err: err}
+}
+
+// Update handles messages
+func (m TrendsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case trendsLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.months = msg.months
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case durabilityLoadedMsg:
+		// A reply for a horizon no longer shown is dropped
+		if msg.data != nil && msg.data.Months != service.TrendHorizons[m.horizon] {
+			return m, nil
+		}
+		m.durability, m.durErr = msg.data, msg.err
+		if m.ready && !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case feelLoadedMsg:
+		if msg.data != nil && msg.data.Months != service.TrendHorizons[m.horizon] {
+			return m, nil
+		}
+		m.feel, m.feelErr = msg.data, msg.err
+		if m.ready && !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case rampLoadedMsg:
+		if msg.data != nil && msg.data.Months != service.TrendHorizons[m.horizon] {
+			return m, nil
+		}
+		m.ramp, m.rampErr = msg.data, msg.err
+		if m.ready && !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-6)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 6
+		}
+		if !m.loading {
+			m.viewport.SetContent(m.renderContent())
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "[":
+			if m.horizon > 0 {
+				m.horizon--
+				m.viewport.SetContent(m.renderContent())
+				return m, tea.Batch(m.loadDurability, m.loadFeel, m.loadRamp)
+			}
+			return m, nil
+		case "]":
+			if m.horizon < len(service.TrendHorizons)-1 {
+				m.horizon++
+				m.viewport.SetContent(m.renderContent())
+				return m, tea.Batch(m.loadDurability, m.loadFeel, m.loadRamp)
+			}
+			return m, nil
+		case "d":
+			m.byDistance = !m.byDistance
+			m.viewport.SetContent(m.renderContent())
+			return m, nil
+		case "r":
+			m.loading = true
+			return m, tea.Batch(m.loadTrends, m.loadDurability, m.loadFeel, m.loadRamp)
+		}
+	}
+
+	// Handle viewport scrolling
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the trends screen
+func (m TrendsModel) View() string {
+	if m.loading {
+		return "\n  Loading trends..."
+	}
+
+	if m.err != nil {
+		return errorStyle.Render(fmt.Sprintf("\n  Error: %v", m.err))
+	}
+
+	if !m.ready {
+		return "\n  Initializing..."
+	}
+
+	footer := statusStyle.Render("  [/]: shorter/longer  d: duration/distance  j/k or arrows: scroll  r: refresh")
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.viewport.View(), footer)
+}
+
+// shown returns the months in the selected horizon, oldest first
+func (m TrendsModel) shown() []service.MonthTrend {
+	n := service.TrendHorizons[m.horizon]
+	if n > len(m.months) {
+		return m.months
+	}
+	return m.months[len(m.months)-n:]
+}
+
+func (m TrendsModel) renderContent() string {
+	var sections []string
+
+	sections = append(sections, "")
+	sections = append(sections, cardTitleStyle.Render("Trends"))
+	sections = append(sections, m.renderTabs())
+	sections = append(sections, "")
+
+	months := m.shown()
+	runs := 0
+	for _, mo := range months {
+		runs += mo.RunCount
+	}
+	if runs == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(mutedColor).Render(
+			fmt.Sprintf("  No runs in the last %d months. Sync activities to see your trends.", len(months))))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	labels := make([]string, len(months))
+	for i, mo := range months {
+		labels[i] = mo.Month.Format("Jan 06")
+	}
+	chart := func(title string, value func(service.MonthTrend) float64, c lineChart) string {
+		data := make([]float64, len(months))
+		for i, mo := range months {
+			data[i] = value(mo)
+		}
+		c.XLabels = labels
+		return m.renderChart(title, data, c)
+	}
+
+	blocks := []string{
+		chart("Monthly Distance", func(mo service.MonthTrend) float64 {
+			return m.units.FromMiles(mo.Miles)
+		}, lineChart{Caption: m.units.DistanceLabelLong() + "/month"}),
+		chart("Efficiency Factor", func(mo service.MonthTrend) float64 {
+			return orNaN(mo.EF)
+		}, lineChart{Precision: 2, Caption: "monthly average (higher = fitter)"}),
+		chart("Fitness (CTL)", func(mo service.MonthTrend) float64 {
+			return orNaN(mo.CTL)
+		}, lineChart{Caption: "at the end of each month"}),
+		chart("VDOT", func(mo service.MonthTrend) float64 {
+			return orNaN(mo.VDOT)
+		}, lineChart{Precision: 1, Caption: "best effort of 10+ minutes each month"}),
+		chart("Performance Score", func(mo service.MonthTrend) float64 {
+			return orNaN(mo.Score)
+		}, lineChart{Precision: 1, Caption: "monthly average, adjusted for hills, heat and distance"}),
+		chart("Cadence", func(mo service.MonthTrend) float64 {
+			return orNaN(mo.Cadence)
+		}, lineChart{Caption: "spm"}),
+	}
+	sections = append(sections, newGridLayout(m.width).rows(blocks)...)
+	sections = append(sections, m.renderRamp()...)
+	sections = append(sections, m.renderDurability()...)
+	sections = append(sections, m.renderFeel()...)
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderTabs lists the horizons with the one shown highlighted
+func (m TrendsModel) renderTabs() string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	tabs := " "
+	for i, n := range service.TrendHorizons {
+		label := fmt.Sprintf(" %d months ", n)
+		if i == m.horizon {
+			tabs += " " + tableSelectedStyle.Render(label)
+		} else {
+			tabs += " " + muted.Render(label)
+		}
+	}
+	return tabs
+}
+
+// renderChart draws a braille chart in a card sized to the layout
+func (m TrendsModel) renderChart(title string, data []float64, chart lineChart) string {
+	g := newGridLayout(m.width)
+	chart.Width = g.chartWidth()
+	chart.Height = trendsChartHeight
+	return cardStyle.Width(g.cardWidth(0)).Render(lipgloss.JoinVertical(lipgloss.Left,
+		cardTitleStyle.Render(title), chart.render(data)))
+}
+
+// orNaN charts a month without data as a gap
+func orNaN(v float64) float64 {
+	if v == 0 {
+		return math.NaN()
+	}
+	return v
+}
+
+// rampFlaggedShown is how many of the latest flagged weeks are listed
+const rampFlaggedShown = 6
+
+// renderRamp charts each week's distance change on the week before against
+// the ramp threshold, with how this week and the weekly goal measure up
+func (m TrendsModel) renderRamp() []string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	sections := []string{"", cardTitleStyle.Render("Weekly Ramp")}
+
+	if m.rampErr != nil {
+		return append(sections, errorStyle.Render(fmt.Sprintf("  Error: %v", m.rampErr)))
+	}
+	d := m.ramp
+	if d == nil {
+		return append(sections, muted.Render("  Loading..."))
+	}
+	limit := fmt.Sprintf("%.0f%%", d.ThresholdPct)
+
+	switch {
+	case d.Cap == 0:
+		sections = append(sections, muted.Render("  No running last week to measure this week's ramp against."))
+	case d.ThisWeek.Over:
+		sections = append(sections, warningStyle.Render(fmt.Sprintf("  This week: %s, up %.0f%% on last week and past the %s guard of %s.",
+			m.units.FormatDistance(d.ThisWeek.Distance), d.ThisWeek.RampPct, limit, m.units.FormatDistance(d.Cap))))
+	default:
+		sections = append(sections, successStyle.Render(fmt.Sprintf("  This week: %s of %s before ramping more than %s over last week.",
+			m.units.FormatDistance(d.ThisWeek.Distance), m.units.FormatDistance(d.Cap), limit)))
+	}
+	if d.GoalOver {
+		sections = append(sections, warningStyle.Render(fmt.Sprintf("  Your weekly goal of %s would be a %.0f%% ramp over last week.",
+			m.units.FormatDistance(d.Goal), d.GoalRampPct)))
+	}
+
+	labels := make([]string, len(d.Weeks))
+	data := make([]float64, len(d.Weeks))
+	var flagged []string
+	for i, w := range d.Weeks {
+		labels[i] = w.Label
+		data[i] = math.NaN()
+		if w.HasRamp {
+			data[i] = w.RampPct
+		}
+		if w.Over {
+			flagged = append(flagged, fmt.Sprintf("%s %+.0f%%", w.Label, w.RampPct))
+		}
+	}
+
+	summary := fmt.Sprintf("  %d of %d weeks ramped more than %s over the week before", d.OverWeeks, len(d.Weeks), limit)
+	if len(flagged) > rampFlaggedShown {
+		summary += fmt.Sprintf(", latest: %s", strings.Join(flagged[len(flagged)-rampFlaggedShown:], ", "))
+	} else if len(flagged) > 0 {
+		summary += ": " + strings.Join(flagged, ", ")
+	}
+	sections = append(sections, muted.Render(summary+"."))
+
+	chart := lineChart{
+		XLabels: labels,
+		Targets: []chartTarget{{Value: d.ThresholdPct, Label: "limit"}},
+		Caption: "% change in distance on the week before",
+	}
+	return append(sections, newGridLayout(m.width).rows([]string{m.renderChart("Week-over-Week Ramp", data, chart)})...)
+}
+
+// renderDurability plots decoupling and EF against run length, with the
+// length at which the fitted decoupling passes analysis.DecouplingLimit
+func (m TrendsModel) renderDurability() []string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	sections := []string{"", cardTitleStyle.Render("Durability")}
+
+	if m.durErr != nil {
+		return append(sections, errorStyle.Render(fmt.Sprintf("  Error: %v", m.durErr)))
+	}
+	d := m.durability
+	if d == nil {
+		return append(sections, muted.Render("  Loading..."))
+	}
+	if len(d.Runs) == 0 {
+		return append(sections, muted.Render(fmt.Sprintf(
+			"  No runs of %d minutes or more in the last %d months.", service.DurabilityMinRunSecs/60, d.Months)))
+	}
+
+	fit, longest := d.ByDuration, d.LongestMinutes
+	length := func(r service.DurabilityRun) float64 { return r.Minutes }
+	format := func(minutes float64) string { return formatCurveDuration(int(minutes * 60)) }
+	axis := "run duration"
+	if m.byDistance {
+		fit, longest = d.ByDistance, d.LongestDistance
+		length = func(r service.DurabilityRun) float64 { return r.Distance }
+		format = m.units.FormatDistance
+		axis = "run distance"
+	}
+
+	sections = append(sections, "  "+m.durabilitySummary(fit, longest, format))
+
+	var decX, decY, efX, efY []float64
+	for _, r := range d.Runs {
+		if r.Decoupling != nil {
+			decX, decY = append(decX, length(r)), append(decY, *r.Decoupling)
+		}
+		if r.EF != nil {
+			efX, efY = append(efX, length(r)), append(efY, *r.EF)
+		}
+	}
+
+	decChart := scatterChart{
+		Precision: 1,
+		XFormat:   format,
+		Targets:   []chartTarget{{Value: analysis.DecouplingLimit, Label: "limit"}},
+		Caption:   "% decoupling by " + axis,
+	}
+	if fit.Decoupling != nil {
+		decChart.Curve = fit.Decoupling.At
+	}
+	efChart := scatterChart{Precision: 2, XFormat: format, Caption: "EF by " + axis}
+	if fit.EF != nil {
+		efChart.Curve = fit.EF.At
+	}
+
+	blocks := []string{
+		m.renderScatter("Decoupling vs Length", decX, decY, decChart),
+		m.renderScatter("EF vs Length", efX, efY, efChart),
+	}
+	return append(sections, newGridLayout(m.width).rows(blocks)...)
+}
+
+// durabilitySummary says where the fitted decoupling passes the limit
+func (m TrendsModel) durabilitySummary(fit service.DurabilityFit, longest float64, format func(float64) string) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	limit := fmt.Sprintf("%.0f%%", analysis.DecouplingLimit)
+	switch {
+	case fit.Decoupling == nil:
+		return muted.Render(fmt.Sprintf("Not enough runs with decoupling to fit a curve (need %d).", analysis.MinTrendPoints))
+	case fit.Breakdown == 0:
+		return successStyle.Render("Decoupling doesn't rise with length: your aerobic base holds through your longest runs.")
+	case fit.Breakdown > longest:
+		return successStyle.Render(fmt.Sprintf("Decoupling stays under %s through your longest run (%s); the curve reaches it around %s.",
+			limit, format(longest), format(fit.Breakdown)))
+	default:
+		return warningStyle.Render(fmt.Sprintf("Decoupling passes %s at about %s, so aerobic endurance currently breaks down there.",
+			limit, format(fit.Breakdown)))
+	}
+}
+
+// renderFeel plots how runs felt against the form they started on, with how
+// closely the two go together
+func (m TrendsModel) renderFeel() []string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	sections := []string{"", cardTitleStyle.Render("Feel vs Form")}
+
+	if m.feelErr != nil {
+		return append(sections, errorStyle.Render(fmt.Sprintf("  Error: %v", m.feelErr)))
+	}
+	d := m.feel
+	if d == nil {
+		return append(sections, muted.Render("  Loading..."))
+	}
+	if len(d.Points) == 0 {
+		return append(sections, muted.Render(fmt.Sprintf(
+			"  No runs with a feel logged in the last %d months. Press e on a run's detail to log RPE and feel.", d.Months)))
+	}
+
+	sections = append(sections, "  "+feelSummary(d))
+
+	xs, ys := make([]float64, len(d.Points)), make([]float64, len(d.Points))
+	for i, p := range d.Points {
+		xs[i], ys[i] = p.TSB, float64(p.Feel)
+	}
+	chart := scatterChart{
+		XFormat: func(tsb float64) string { return fmt.Sprintf("%+.0f", tsb) },
+		Caption: "feel (1 awful to 5 great) by TSB the day before",
+	}
+	if d.Fit != nil {
+		chart.Curve = d.Fit.At
+	}
+	return append(sections, newGridLayout(m.width).rows([]string{m.renderScatter("Feel vs TSB", xs, ys, chart)})...)
+}
+
+// feelSummary says how closely feel follows form
+func feelSummary(d *service.FeelVsFormData) string {
+	muted := lipgloss.NewStyle().Foreground(mutedColor)
+	if !d.HasCorrelation {
+		return muted.Render(fmt.Sprintf("Log a feel on at least %d runs with varied form to see how they relate.", analysis.MinTrendPoints))
+	}
+	r := fmt.Sprintf("r = %.2f", d.Correlation)
+	switch {
+	case d.Correlation >= 0.3:
+		return successStyle.Render("Runs feel better when you're fresher (" + r + "): form is a fair guide to how you'll feel.")
+	case d.Correlation <= -0.3:
+		return warningStyle.Render("Runs feel worse when you're fresher (" + r + "): you may run best with some fatigue in your legs.")
+	default:
+		return muted.Render("Feel barely follows form (" + r + "): sleep, stress and weather may matter more.")
+	}
+}
+
+// renderScatter draws a scatter chart in a card sized to the layout
+func (m TrendsModel) renderScatter(title string, xs, ys []float64, chart scatterChart) string {
+	g := newGridLayout(m.width)
+	chart.Width = g.chartWidth()
+	chart.Height = trendsChartHeight
+	return cardStyle.Width(g.cardWidth(0)).Render(lipgloss.JoinVertical(lipgloss.Left,
+		cardTitleStyle.Render(title), chart.render(xs, ys)))
+}
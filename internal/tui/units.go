@@ -9,6 +9,7 @@ import (
 const (
 	metersPerMile = 1609.34
 	metersPerKm   = 1000.0
+	feetPerMeter  = 3.28084
 )
 
 // Units provides unit conversion and formatting based on user preferences
@@ -29,6 +30,24 @@ func (u Units) FormatDistance(meters float64) string {
 	return fmt.Sprintf("%.1f km", meters/metersPerKm)
 }
 
+// FormatElevation formats an elevation gain in meters, in feet when the
+// distance unit is miles
+func (u Units) FormatElevation(meters float64) string {
+	if u.IsMiles() {
+		return fmt.Sprintf("%.0f ft", meters*feetPerMeter)
+	}
+	return fmt.Sprintf("%.0f m", meters)
+}
+
+// FormatTemperature formats a temperature in Celsius, in Fahrenheit when the
+// distance unit is miles
+func (u Units) FormatTemperature(celsius float64) string {
+	if u.IsMiles() {
+		return fmt.Sprintf("%.0f°F", celsius*9/5+32)
+	}
+	return fmt.Sprintf("%.0f°C", celsius)
+}
+
 // FormatDistanceValue returns just the numeric distance value (no unit label)
 func (u Units) FormatDistanceValue(meters float64) string {
 	if u.cfg.DistanceUnit == "mi" {
@@ -61,7 +80,33 @@ func (u Units) FormatPaceWithUnit(seconds int, meters float64) string {
 	if pace == "-" {
 		return pace
 	}
-	return pace + "/" + u.DistanceLabel()
+	return pace + "/" + u.paceDistanceLabel()
+}
+
+// FormatPacePerMile formats a pace given in seconds per mile, as stored for
+// personal records, in the preferred pace unit with its label
+func (u Units) FormatPacePerMile(secondsPerMile float64) string {
+	if secondsPerMile <= 0 {
+		return "-"
+	}
+	return u.FormatPaceWithUnit(int(secondsPerMile+0.5), metersPerMile)
+}
+
+// FromMiles converts a distance in miles, as the weekly and period
+// aggregates report it, to the preferred distance unit
+func (u Units) FromMiles(miles float64) float64 {
+	if u.IsMiles() {
+		return miles
+	}
+	return miles * metersPerMile / metersPerKm
+}
+
+// paceDistanceLabel returns the distance a pace is measured over ("mi" or "km")
+func (u Units) paceDistanceLabel() string {
+	if u.cfg.PaceUnit == "min/mi" {
+		return "mi"
+	}
+	return "km"
 }
 
 // DistanceLabel returns the short unit label ("mi" or "km")
@@ -3,8 +3,13 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 
@@ -12,6 +17,8 @@ import (
 
 	"runner/internal/auth"
 	"runner/internal/config"
+	"runner/internal/logging"
+	"runner/internal/paths"
 	"runner/internal/service"
 	"runner/internal/store"
 	"runner/internal/strava"
@@ -24,64 +31,251 @@ func main() {
 	}
 }
 
+const usage = `Usage: runner [--config FILE] [--data-dir DIR] [--read-only] [--all-time] [--demo] [command]
+
+Without a command, opens the TUI. Commands: add, delete, streams, db,
+backup, restore, report, export, sync, wellness, auth. Run a command with -h for its
+flags.
+
+Flags:`
+
+// readOnly is set by --read-only, and by --all-time
+var readOnly bool
+
+// allTime is set by --all-time
+var allTime bool
+
+// readOnlyCommands are the subcommands that work on a read-only database
+var readOnlyCommands = map[string]bool{
+	"report": true,
+	"export": true,
+	"db":     true, // stats and verify; vacuum and repair refuse
+}
+
+// openStore opens the database, read-only under --read-only and with the
+// season archives attached under --all-time
+func openStore() (*store.Store, error) {
+	if allTime {
+		return store.OpenAllTime()
+	}
+	if readOnly {
+		return store.OpenReadOnly()
+	}
+	return store.Open()
+}
+
 func run() error {
+	// Locations apply to every command
+	fs := flag.NewFlagSet("runner", flag.ContinueOnError)
+	configFlag := fs.String("config", "", "config file (default $"+paths.ConfigEnv+", or $XDG_CONFIG_HOME/runner/config.json)")
+	dataDirFlag := fs.String("data-dir", "", "directory for the database, backups and logs (default $"+paths.DataEnv+", or $XDG_DATA_HOME/runner)")
+	demoMode := fs.Bool("demo", false, "browse generated demo data instead of your own")
+	fs.BoolVar(&readOnly, "read-only", false, "open the database read-only, to browse one on a share or written by another machine")
+	fs.BoolVar(&allTime, "all-time", false, "attach the season archives, read-only, to browse every run")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	paths.SetConfigFile(*configFlag)
+	paths.SetDataDir(*dataDirFlag)
+	readOnly = readOnly || allTime
+	if *demoMode {
+		return runDemo()
+	}
+
+	// Subcommands that work offline, without Strava credentials
+	if args := fs.Args(); len(args) > 0 {
+		if readOnly && !readOnlyCommands[args[0]] {
+			return fmt.Errorf("runner %s changes the database; run it without --read-only", args[0])
+		}
+		switch args[0] {
+		case "add":
+			return runAdd(args[1:])
+		case "delete":
+			return runDelete(args[1:])
+		case "streams":
+			return runStreams(args[1:])
+		case "db":
+			return runDB(args[1:])
+		case "backup":
+			return runBackup(args[1:])
+		case "restore":
+			return runRestore(args[1:])
+		case "report":
+			return runReport(args[1:])
+		case "export":
+			return runExport(args[1:])
+		case "sync":
+			return runSync(args[1:])
+		case "wellness":
+			return runWellness(args[1:])
+		case "auth":
+			return runAuth(args[1:])
+		default:
+			return fmt.Errorf("unknown command %q; run runner -h for a list", args[0])
+		}
+	}
+
 	ctx := context.Background()
 
-	// Load configuration
+	// Load configuration, starting from the defaults on first run
 	cfg, err := config.Load()
 	if errors.Is(err, config.ErrNoConfig) {
-		fmt.Println("No config file found. Creating example config...")
-		if err := config.CreateExample(); err != nil {
-			return fmt.Errorf("creating example config: %w", err)
-		}
-		configDir, _ := config.GetConfigDir()
-		fmt.Printf("\nPlease edit the config file at:\n  %s/config.json\n\n", configDir)
-		fmt.Println("You need to add your Strava API credentials.")
-		fmt.Println("Get them from: https://www.strava.com/settings/api")
-		return nil
+		defaults := config.DefaultConfig()
+		cfg, err = &defaults, nil
 	}
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	// Validate config
-	if err := cfg.Validate(); err != nil {
-		configDir, _ := config.GetConfigDir()
-		fmt.Printf("Config validation failed: %v\n\n", err)
-		fmt.Printf("Please edit the config file at:\n  %s/config.json\n", configDir)
-		return nil
+	// Without credentials the setup wizard fills them in; otherwise the
+	// config must be valid as it is. Browsing read-only needs no Strava.
+	firstRun := !cfg.HasCredentials() && !readOnly
+	validate := cfg.Validate
+	if readOnly {
+		validate = cfg.ValidateSettings
+	}
+	if !firstRun {
+		if err := validate(); err != nil {
+			configPath, _ := config.Path()
+			fmt.Printf("Config validation failed: %v\n\n", err)
+			fmt.Printf("Please edit the config file at:\n  %s\n", configPath)
+			return nil
+		}
 	}
 
+	// Log to a file, since the TUI owns the terminal
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return err
+	}
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return err
+	}
+	logFile, err := logging.Setup(dataDir, level)
+	if err != nil {
+		return fmt.Errorf("setting up logging: %w", err)
+	}
+	defer logFile.Close()
+
 	// Open database
-	db, err := store.Open()
+	db, err := openStore()
 	if err != nil {
 		return fmt.Errorf("opening database: %w", err)
 	}
 	defer db.Close()
+	db.SetCompressStreams(cfg.Storage.CompressStreams)
+
+	// Snapshot the database before this session's sync can change it. A
+	// read-only session changes nothing, and the writer keeps the backups.
+	if cfg.Storage.BackupIntervalHours > 0 && !readOnly {
+		interval := time.Duration(cfg.Storage.BackupIntervalHours) * time.Hour
+		if _, err := db.BackupIfDue(interval, cfg.Storage.BackupKeep); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: backup failed: %v\n", err)
+		}
+	}
+
+	syncOnStart := false
+	if firstRun {
+		cfg, syncOnStart, err = runSetup(ctx, db, cfg)
+		if err != nil || cfg == nil {
+			return err
+		}
+	}
 
+	// Create services
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	querySvc.SetRiegelExponent(cfg.Analysis.RiegelExponent)
+	querySvc.SetRestDayWarningDays(cfg.Analysis.RestDayWarningDays)
+	querySvc.SetRampThreshold(cfg.Analysis.RampThresholdPct)
+	querySvc.SetWeekNumbers(cfg.Display.WeekNumbers)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	if err := querySvc.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		return fmt.Errorf("applying load model: %w", err)
+	}
+
+	var stravaClient *strava.Client
+	var syncSvc tui.SyncRunner
+	if !readOnly {
+		stravaClient, err = connectStrava(ctx, db, cfg)
+		if err != nil {
+			return err
+		}
+		svc := service.NewSyncService(stravaClient, db, cfg.Athlete, cfg.Analysis)
+		svc.SetPrivacyConfig(cfg.Privacy)
+		svc.SetStorageConfig(cfg.Storage)
+		svc.SetOnSyncDone(syncHooks(db, querySvc, cfg.Hooks))
+		syncSvc = svc
+	}
+
+	if err := tui.ApplyTheme(cfg.Display); err != nil {
+		return fmt.Errorf("applying theme: %w", err)
+	}
+
+	// Launch TUI
+	app := tui.NewApp(db, stravaClient, syncSvc, querySvc, *cfg, logging.Path(dataDir))
+	app.SetConfigSaver(config.Save)
+	if syncOnStart {
+		app.StartWithSync()
+	}
+	if readOnly {
+		app.SetReadOnly()
+	} else if warning := scopeWarning(db); warning != "" {
+		slog.Warn("strava token is missing scopes", "warning", warning)
+		app.SetStatus(warning)
+	}
+	p := tea.NewProgram(app, tea.WithAltScreen())
+
+	// Apply edits to config.json while the TUI runs
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	go func() {
+		err := config.Watch(watchCtx, func(cfg *config.Config, err error) {
+			p.Send(tui.ConfigReloadedMsg{Config: cfg, Err: err})
+		})
+		if err != nil {
+			slog.Warn("config changes won't reload", "error", err)
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("running TUI: %w", err)
+	}
+
+	return nil
+}
+
+// connectStrava returns a Strava client using the stored tokens, running
+// the OAuth flow first if there are none or they no longer work
+func connectStrava(ctx context.Context, db *store.Store, cfg *config.Config) (*strava.Client, error) {
 	// Check for existing auth
 	storedAuth, err := db.GetAuth()
 	if errors.Is(err, store.ErrNoAuth) {
 		// No auth stored, need to authenticate
 		fmt.Println("No authentication found. Starting OAuth flow...")
 		if err := authenticate(ctx, db, cfg); err != nil {
-			return fmt.Errorf("authentication: %w", err)
+			return nil, fmt.Errorf("authentication: %w", err)
 		}
 		// Re-fetch auth after successful authentication
 		storedAuth, err = db.GetAuth()
 		if err != nil {
-			return fmt.Errorf("fetching auth after login: %w", err)
+			return nil, fmt.Errorf("fetching auth after login: %w", err)
 		}
 	} else if err != nil {
-		return fmt.Errorf("checking auth: %w", err)
+		return nil, fmt.Errorf("checking auth: %w", err)
 	}
 
 	// Create token source for API calls (with auto-refresh)
-	oauthCfg := auth.NewOAuthConfig(auth.Config{
-		ClientID:     cfg.Strava.ClientID,
-		ClientSecret: cfg.Strava.ClientSecret,
-		RedirectURL:  fmt.Sprintf("http://localhost:%d/callback", auth.CallbackPort),
-	})
+	oauthCfg := oauthConfig(cfg.Strava)
 
 	token := &oauth2.Token{
 		AccessToken:  storedAuth.AccessToken,
@@ -97,51 +291,85 @@ func run() error {
 	if _, err := tokenSource.Token(); err != nil {
 		fmt.Println("Stored token is invalid or expired. Re-authenticating...")
 		if err := authenticate(ctx, db, cfg); err != nil {
-			return fmt.Errorf("re-authentication: %w", err)
+			return nil, fmt.Errorf("re-authentication: %w", err)
 		}
 	}
 
-	// Create services
-	stravaClient := strava.NewClient(tokenSource)
-	syncSvc := service.NewSyncService(stravaClient, db, cfg.Athlete)
-	querySvc := service.NewQueryService(db, cfg.Athlete)
+	client := strava.NewClient(tokenSource)
+	client.SetRetryPolicy(strava.NewRetryPolicy(cfg.Strava.MaxAttempts, cfg.Strava.RetryDelay()))
+	client.SetCache(responseCache{db})
+	return client, nil
+}
 
-	// Launch TUI
-	app := tui.NewApp(db, stravaClient, syncSvc, querySvc, cfg.Display)
-	p := tea.NewProgram(app, tea.WithAltScreen())
+// responseCache keeps the Strava client's cached responses in the database.
+// A read or write that fails only costs a full download, so it is logged.
+type responseCache struct {
+	db *store.Store
+}
 
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("running TUI: %w", err)
+func (c responseCache) Get(url string) (strava.CachedResponse, bool) {
+	r, err := c.db.GetCachedResponse(url)
+	if err != nil {
+		slog.Warn("reading cached response", "url", url, "error", err)
 	}
-
-	return nil
+	if r == nil {
+		return strava.CachedResponse{}, false
+	}
+	return strava.CachedResponse{ETag: r.ETag, LastModified: r.LastModified, Body: r.Body}, true
 }
 
-func authenticate(ctx context.Context, db *store.Store, cfg *config.Config) error {
-	oauthCfg := auth.NewOAuthConfig(auth.Config{
-		ClientID:     cfg.Strava.ClientID,
-		ClientSecret: cfg.Strava.ClientSecret,
-		RedirectURL:  fmt.Sprintf("http://localhost:%d/callback", auth.CallbackPort),
+func (c responseCache) Put(url string, r strava.CachedResponse) {
+	err := c.db.SaveCachedResponse(store.CachedResponse{
+		URL:          url,
+		ETag:         r.ETag,
+		LastModified: r.LastModified,
+		Body:         r.Body,
+		StoredAt:     time.Now(),
 	})
+	if err != nil {
+		slog.Warn("caching response", "url", url, "error", err)
+	}
+}
 
-	result, err := auth.Authenticate(ctx, oauthCfg)
+func authenticate(ctx context.Context, db *store.Store, cfg *config.Config) error {
+	result, err := auth.Authenticate(ctx, oauthConfig(cfg.Strava))
 	if err != nil {
 		return err
 	}
+	if err := saveAuth(db, result); err != nil {
+		return err
+	}
 
-	// Store the tokens
+	fmt.Println()
+	fmt.Printf("Successfully authenticated as athlete %d!\n", result.AthleteID)
+	fmt.Printf("Permissions granted: %s\n", formatScopes(result.Scopes))
+	if warning := auth.ScopeWarning(auth.MissingScopes(result.Scopes)); warning != "" {
+		fmt.Println()
+		fmt.Println("Warning: " + warning)
+	}
+	return nil
+}
+
+// oauthConfig returns the OAuth config for the Strava app credentials
+func oauthConfig(creds config.StravaConfig) *oauth2.Config {
+	return auth.NewOAuthConfig(auth.Config{
+		ClientID:     creds.ClientID,
+		ClientSecret: creds.ClientSecret,
+		RedirectURL:  fmt.Sprintf("http://localhost:%d/callback", auth.CallbackPort),
+	})
+}
+
+// saveAuth stores the tokens from a completed OAuth flow
+func saveAuth(db *store.Store, result *auth.AuthResult) error {
 	storedAuth := &store.Auth{
 		AthleteID:    result.AthleteID,
 		AccessToken:  result.Token.AccessToken,
 		RefreshToken: result.Token.RefreshToken,
 		ExpiresAt:    result.Token.Expiry,
+		Scopes:       strings.Join(result.Scopes, ","),
 	}
-
 	if err := db.SaveAuth(storedAuth); err != nil {
 		return fmt.Errorf("saving auth: %w", err)
 	}
-
-	fmt.Println()
-	fmt.Printf("Successfully authenticated as athlete %d!\n", result.AthleteID)
 	return nil
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/notify"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+// notifiedWarningsKey is the sync_state key holding the warning kinds already
+// notified, so a warning is sent once when it appears rather than every sync
+const notifiedWarningsKey = "notified_warnings"
+
+// notifySync sends the desktop notifications enabled in cfg for one scheduled
+// sync. A notification that can't be sent is logged and otherwise ignored.
+func notifySync(db *store.Store, querySvc *service.QueryService, cfg config.NotificationsConfig, result *service.SyncResult, syncErr error) {
+	if cfg.Sync {
+		if msg := syncMessage(result, syncErr); msg != "" {
+			send("Sync complete", msg)
+		}
+	}
+
+	if cfg.Records && result != nil {
+		for _, r := range result.NewRecords {
+			send("New "+r.Label()+" record", r.ActivityName)
+		}
+	}
+
+	if cfg.Warnings {
+		notifyWarnings(db, querySvc)
+	}
+}
+
+// syncMessage summarizes a sync for a notification, or returns "" when
+// nothing happened worth interrupting for
+func syncMessage(result *service.SyncResult, syncErr error) string {
+	if syncErr != nil {
+		return fmt.Sprintf("Sync failed: %v", syncErr)
+	}
+	var parts []string
+	if result.ActivitiesStored > 0 {
+		parts = append(parts, fmt.Sprintf("%d new activities", result.ActivitiesStored))
+	}
+	if len(result.Failures) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", len(result.Failures)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// notifyWarnings sends the training warnings that weren't active after the
+// last sync
+func notifyWarnings(db *store.Store, querySvc *service.QueryService) {
+	warnings, err := querySvc.GetTrainingWarnings(time.Now())
+	if err != nil {
+		slog.Warn("checking training warnings", "error", err)
+		return
+	}
+	saved, err := db.GetSyncState(notifiedWarningsKey)
+	if err != nil {
+		slog.Warn("reading notified warnings", "error", err)
+		return
+	}
+	notified := strings.Split(saved, ",")
+
+	var kinds []string
+	for _, w := range warnings {
+		kinds = append(kinds, w.Kind)
+		if !slices.Contains(notified, w.Kind) {
+			send("Training warning", w.Message)
+		}
+	}
+	if err := db.SetSyncState(notifiedWarningsKey, strings.Join(kinds, ",")); err != nil {
+		slog.Warn("saving notified warnings", "error", err)
+	}
+}
+
+// send shows one notification, logging rather than failing if it can't
+func send(title, message string) {
+	if err := notify.Send(title, message); err != nil {
+		slog.Warn("sending notification", "title", title, "error", err)
+	}
+}
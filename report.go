@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/report"
+	"runner/internal/service"
+)
+
+// runReport implements `runner report`, which renders a monthly training
+// summary as Markdown or HTML
+func runReport(args []string) error {
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	lastMonth := time.Now().AddDate(0, 0, -time.Now().Day())
+
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	month := fs.String("month", lastMonth.Format("2006-01"), "month to report on, YYYY-MM")
+	formatName := fs.String("format", "md", "output format, md or html")
+	out := fs.String("o", "", "file to write (default stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: runner report [-month YYYY-MM] [-format md|html] [-o FILE]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	start, err := time.ParseInLocation("2006-01", *month, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid month %q (want YYYY-MM)", *month)
+	}
+	format, err := report.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	db, err := openStore()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	if err := querySvc.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		return fmt.Errorf("applying load model: %w", err)
+	}
+	data, err := querySvc.GetMonthlyReport(start.Year(), start.Month())
+	if err != nil {
+		return fmt.Errorf("building report: %w", err)
+	}
+	doc, err := report.Render(data, cfg.Display, format)
+	if err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+
+	if *out == "" {
+		fmt.Print(doc)
+		return nil
+	}
+	if err := os.WriteFile(*out, []byte(doc), 0o644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	fmt.Printf("Wrote the %s report to %s\n", start.Format("January 2006"), *out)
+	return nil
+}
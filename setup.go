@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"runner/internal/auth"
+	"runner/internal/config"
+	"runner/internal/store"
+	"runner/internal/tui"
+)
+
+// runSetup runs the first-run wizard, which connects to Strava and saves the
+// config. It returns the saved config and whether to start with a sync, or a
+// nil config if the user quit.
+func runSetup(ctx context.Context, db *store.Store, cfg *config.Config) (*config.Config, bool, error) {
+	connect := func(ctx context.Context, creds config.StravaConfig, showURL func(string)) error {
+		result, err := auth.AuthenticateWith(ctx, oauthConfig(creds), showURL)
+		if err != nil {
+			return err
+		}
+		return saveAuth(db, result)
+	}
+
+	setup := tui.NewSetupModel(*cfg, connect, config.Save)
+	final, err := tea.NewProgram(setup, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if err != nil {
+		return nil, false, fmt.Errorf("running setup: %w", err)
+	}
+
+	saved, startSync, ok := final.(tui.SetupModel).Result()
+	if !ok {
+		return nil, false, nil
+	}
+	return &saved, startSync, nil
+}
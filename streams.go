@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const streamsUsage = `Usage: runner streams <command>
+
+Commands:
+  migrate                  convert streams to the format set by storage.compress_streams
+  purge -older-than YEARS  delete the streams of older runs, keeping their summaries and metrics`
+
+// runStreams implements the `runner streams` commands
+func runStreams(args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "migrate":
+		return runStreamsMigrate()
+	case len(args) > 0 && args[0] == "purge":
+		return runStreamsPurge(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, streamsUsage)
+		return nil
+	}
+}
+
+// runStreamsMigrate converts stored streams to the format selected by
+// storage.compress_streams
+func runStreamsMigrate() error {
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+	db.SetCompressStreams(cfg.Storage.CompressStreams)
+
+	format := "one row per sample"
+	if cfg.Storage.CompressStreams {
+		format = "compressed blobs"
+	}
+
+	converted, err := db.MigrateStreams(func(done, total int) {
+		fmt.Printf("\rConverting streams to %s: %d/%d", format, done, total)
+	})
+	if converted > 0 {
+		fmt.Println()
+	}
+	if err != nil {
+		return fmt.Errorf("migrating streams: %w", err)
+	}
+	if converted == 0 {
+		fmt.Printf("All streams are already stored as %s.\n", format)
+		return nil
+	}
+
+	fmt.Println("Reclaiming space...")
+	if err := db.Vacuum(); err != nil {
+		return fmt.Errorf("vacuuming database: %w", err)
+	}
+	fmt.Printf("Converted %d activities.\n", converted)
+	return nil
+}
+
+// runStreamsPurge deletes the streams of runs older than a number of years
+func runStreamsPurge(args []string) error {
+	fs := flag.NewFlagSet("streams purge", flag.ContinueOnError)
+	years := fs.Int("older-than", 0, "purge the streams of runs that started more than this many years ago (required)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *years < 1 {
+		return errors.New("-older-than must be at least 1 year, e.g. runner streams purge -older-than 5")
+	}
+
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	before, err := db.Stats()
+	if err != nil {
+		return err
+	}
+	purged, err := querySvc.PurgeStreams(*years)
+	if err != nil {
+		return fmt.Errorf("purging streams: %w", err)
+	}
+	if purged == 0 {
+		fmt.Printf("No runs older than %d years have streams.\n", *years)
+		return nil
+	}
+
+	fmt.Println("Reclaiming space...")
+	if err := db.Vacuum(); err != nil {
+		return fmt.Errorf("vacuuming database: %w", err)
+	}
+	after, err := db.Stats()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Purged the streams of %d runs. Database size: %s -> %s\n",
+		purged, formatBytes(before.FileBytes), formatBytes(after.FileBytes))
+	return nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"runner/internal/config"
+	"runner/internal/logging"
+	"runner/internal/paths"
+	"runner/internal/service"
+	"runner/internal/store"
+	"runner/internal/strava"
+)
+
+// minSyncInterval keeps scheduled syncs within Strava's rate limits
+const minSyncInterval = 5 * time.Minute
+
+// runSync implements `runner sync`, which runs a sync, or just some of its
+// phases, without the TUI
+func runSync(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	phaseList := fs.String("phases", "all", "comma-separated phases: activities, streams, metrics, prs, races, predictions, or local/remote/all")
+	recompute := fs.Bool("recompute", false, "recompute metrics for every run, e.g. after changing HR settings")
+	recomputeRecords := fs.Bool("recompute-records", false, "analyze every run for personal records, not only new or changed ones")
+	every := fs.Duration("every", 0, "keep running and sync again after each interval, e.g. 1h, sending desktop notifications as configured")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: runner sync [-phases LIST] [-recompute] [-recompute-records] [-every DURATION]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	phases, err := service.ParseSyncPhases(*phaseList)
+	if err != nil {
+		return err
+	}
+	opts := service.SyncOptions{Phases: phases, RecomputeMetrics: *recompute, RecomputeRecords: *recomputeRecords}
+	if *every != 0 && *every < minSyncInterval {
+		return fmt.Errorf("-every must be at least %s", minSyncInterval)
+	}
+
+	// Local phases work without Strava credentials
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) && !opts.NeedsStrava() {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	} else if opts.NeedsStrava() {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	dataDir, err := paths.DataDir()
+	if err != nil {
+		return err
+	}
+	level, err := logging.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return err
+	}
+	logFile, err := logging.Setup(dataDir, level)
+	if err != nil {
+		return fmt.Errorf("setting up logging: %w", err)
+	}
+	defer logFile.Close()
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+	db.SetCompressStreams(cfg.Storage.CompressStreams)
+
+	ctx := context.Background()
+	var client *strava.Client
+	if opts.NeedsStrava() {
+		if client, err = connectStrava(ctx, db, cfg); err != nil {
+			return err
+		}
+		if warning := scopeWarning(db); warning != "" {
+			slog.Warn("strava token is missing scopes", "warning", warning)
+			fmt.Fprintln(os.Stderr, "Warning: "+warning)
+		}
+	}
+
+	// Summaries are bucketed by the configured clock before syncing adds to them
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	querySvc.SetRestDayWarningDays(cfg.Analysis.RestDayWarningDays)
+	querySvc.SetRampThreshold(cfg.Analysis.RampThresholdPct)
+	if err := querySvc.SetBucketing(cfg.Analysis); err != nil {
+		return fmt.Errorf("applying week and day buckets: %w", err)
+	}
+	if err := querySvc.SetLoadModel(cfg.Analysis.Load()); err != nil {
+		return fmt.Errorf("applying load model: %w", err)
+	}
+
+	syncSvc := service.NewSyncService(client, db, cfg.Athlete, cfg.Analysis)
+	syncSvc.SetPrivacyConfig(cfg.Privacy)
+	syncSvc.SetStorageConfig(cfg.Storage)
+	syncSvc.SetOnSyncDone(syncHooks(db, querySvc, cfg.Hooks))
+	if *every == 0 {
+		_, err := syncOnce(ctx, syncSvc, opts)
+		return err
+	}
+
+	// Daemon mode: sync on a schedule until interrupted
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("Syncing every %s, press Ctrl-C to stop\n", *every)
+	for {
+		result, err := syncOnce(ctx, syncSvc, opts)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var locked *store.SyncLockedError
+		if errors.As(err, &locked) {
+			// The TUI is syncing; try again next interval
+			slog.Info("scheduled sync skipped", "reason", err.Error())
+			fmt.Printf("Skipped: %v\n", err)
+		} else if err != nil {
+			slog.Error("scheduled sync failed", "error", err)
+			fmt.Printf("Sync failed: %v\n", err)
+		}
+		if locked == nil {
+			notifySync(db, querySvc, cfg.Notifications, result, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*every):
+		}
+	}
+}
+
+// syncOnce runs one sync, printing each phase as it starts and a summary at
+// the end
+func syncOnce(ctx context.Context, syncSvc *service.SyncService, opts service.SyncOptions) (*service.SyncResult, error) {
+	progress := make(chan service.SyncProgress)
+	printed := make(chan struct{})
+	go func() {
+		defer close(printed)
+		phase := ""
+		for p := range progress {
+			if p.Error == nil && p.Phase != phase {
+				phase = p.Phase
+				fmt.Printf("%s...\n", phaseLabel(phase))
+			}
+		}
+	}()
+
+	result, err := syncSvc.Sync(ctx, opts, progress)
+	<-printed
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("\nActivities stored:  %d\n", result.ActivitiesStored)
+	fmt.Printf("Streams downloaded: %d\n", result.StreamsFetched)
+	fmt.Printf("Details fetched:    %d\n", result.DetailsFetched)
+	fmt.Printf("Metrics computed:   %d\n", result.MetricsComputed)
+	fmt.Printf("Records updated:    %d\n", result.PRsComputed)
+	if result.RecordsRepaired > 0 {
+		fmt.Printf("Records repaired:   %d (their run was deleted or excluded)\n", result.RecordsRepaired)
+	}
+	fmt.Printf("Races detected:     %d\n", result.RacesFound)
+	fmt.Printf("Predictions:        %d\n", result.PredictionsComputed)
+	printPhaseTimings(result)
+
+	if len(result.Failures) > 0 {
+		fmt.Printf("\n%d failed:\n", len(result.Failures))
+		for _, f := range result.Failures {
+			fmt.Printf("  %s: %v\n", phaseLabel(f.Phase), f.Err)
+		}
+		if result.MissingScope() {
+			fmt.Println("\nStrava refused requests the token has no permission for. Run runner auth")
+			fmt.Println("and leave every permission ticked on Strava's page.")
+		}
+	}
+	return result, nil
+}
+
+// printPhaseTimings breaks the sync down by phase, so a slow sync shows
+// where the time went
+func printPhaseTimings(result *service.SyncResult) {
+	if len(result.Phases) == 0 {
+		return
+	}
+	fmt.Printf("\n%-18s %8s %10s %9s\n", "Phase", "Time", "Stored", "Requests")
+	var limit *service.RateLimitSnapshot
+	for _, p := range result.Phases {
+		fmt.Printf("%-18s %8s %10s %9d\n", phaseLabel(string(p.Phase)), p.Duration.Round(100*time.Millisecond),
+			formatByteChange(p.BytesStored), p.Requests)
+		if p.RateLimit != nil {
+			limit = p.RateLimit
+		}
+	}
+	fmt.Printf("%-18s %8s %10s %9d\n", "Total", result.Duration.Round(100*time.Millisecond),
+		formatByteChange(result.BytesStored), result.Requests)
+	if limit != nil {
+		fmt.Printf("API used:          %d/%d (15min), %d/%d (daily)\n",
+			limit.ShortUsage, limit.ShortLimit, limit.DailyUsage, limit.DailyLimit)
+	}
+}
+
+// formatByteChange formats a change in stored data with its sign
+func formatByteChange(n int64) string {
+	if n < 0 {
+		return "-" + formatBytes(-n)
+	}
+	return "+" + formatBytes(n)
+}
+
+// phaseLabel names a sync phase for output
+func phaseLabel(phase string) string {
+	switch service.SyncPhase(phase) {
+	case service.PhaseActivities:
+		return "Activities"
+	case service.PhaseStreams:
+		return "Streams"
+	case service.PhaseMetrics:
+		return "Metrics"
+	case service.PhasePersonalRecords:
+		return "Personal records"
+	case service.PhaseRaces:
+		return "Races"
+	case service.PhasePredictions:
+		return "Predictions"
+	}
+	return phase
+}
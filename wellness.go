@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"runner/internal/config"
+	"runner/internal/service"
+	"runner/internal/store"
+)
+
+const wellnessUsage = `Usage: runner wellness <command>
+
+Commands:
+  import FILE   import daily wellness from CSV ("-" for stdin). Needs a date
+                column (YYYY-MM-DD) and any of resting_hr, hrv, sleep_hours
+                and soreness (1-5). Days already logged are replaced.`
+
+// runWellness implements `runner wellness`, which imports daily wellness
+// logged elsewhere, e.g. exported from a watch or sleep tracker
+func runWellness(args []string) error {
+	if len(args) != 2 || args[0] != "import" {
+		fmt.Fprintln(os.Stderr, wellnessUsage)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if errors.Is(err, config.ErrNoConfig) {
+		defaults := config.DefaultConfig()
+		cfg = &defaults
+	} else if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	var r io.Reader = os.Stdin
+	if args[1] != "-" {
+		f, err := os.Open(args[1])
+		if err != nil {
+			return fmt.Errorf("opening wellness file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	entries, err := service.ParseWellnessCSV(r)
+	if err != nil {
+		return fmt.Errorf("reading wellness CSV: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No wellness entries found.")
+		return nil
+	}
+
+	db, err := store.Open()
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	querySvc := service.NewQueryService(db, cfg.Athlete)
+	if err := querySvc.SaveWellness(entries...); err != nil {
+		return fmt.Errorf("saving wellness: %w", err)
+	}
+
+	first, last := entries[0].Date, entries[0].Date
+	for _, e := range entries {
+		if e.Date.Before(first) {
+			first = e.Date
+		}
+		if e.Date.After(last) {
+			last = e.Date
+		}
+	}
+	fmt.Printf("Imported %d days of wellness, %s to %s\n", len(entries), first.Format("Jan 2, 2006"), last.Format("Jan 2, 2006"))
+	return nil
+}